@@ -3,6 +3,7 @@ package adapters
 import (
 	"context"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/auth/ports"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/util"
@@ -34,15 +35,26 @@ func (ts *TokenStorage) Set(ctx context.Context, key string, payload domain.Payl
 
 func (ts *TokenStorage) Del(ctx context.Context, key string) error {
 	ok, err := ts.redisClient.Del(ctx, key).Result()
-	if err != nil || ok != 1 {
+	if err != nil {
 		return util.WrapError(ports.ErrInternalTokenStorage, err)
 	}
 
+	if ok != 1 {
+		return ports.ErrNotExistingKey
+	}
+
 	return nil
 }
 
 func (ts *TokenStorage) Get(ctx context.Context, key string) (*domain.Payload, error) {
-	val, err := ts.redisClient.Get(ctx, key).Result()
+	return ts.unmarshalPayload(ts.redisClient.Get(ctx, key).Result())
+}
+
+func (ts *TokenStorage) GetDel(ctx context.Context, key string) (*domain.Payload, error) {
+	return ts.unmarshalPayload(ts.redisClient.GetDel(ctx, key).Result())
+}
+
+func (ts *TokenStorage) unmarshalPayload(val string, err error) (*domain.Payload, error) {
 	if err != nil {
 		if err == redis.Nil {
 			return nil, ports.ErrNotExistingKey
@@ -58,3 +70,218 @@ func (ts *TokenStorage) Get(ctx context.Context, key string) (*domain.Payload, e
 
 	return &payload, nil
 }
+
+const (
+	sessionKeyPrefix      = "session:"
+	userSessionsKeyPrefix = "user_sessions:"
+)
+
+func sessionKey(sessionID uuid.UUID) string {
+	return sessionKeyPrefix + sessionID.String()
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return userSessionsKeyPrefix + userID.String()
+}
+
+// extendSessionScript replaces the session only if it still exists, so a
+// session revoked in the meantime is not written back.
+var extendSessionScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+redis.call('SADD', KEYS[2], ARGV[3])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return 1
+`)
+
+// touchSessionScript rewrites only the last used time of the stored session,
+// so it can't put back fields a concurrent refresh has just changed.
+var touchSessionScript = redis.NewScript(`
+local val = redis.call('GET', KEYS[1])
+if not val then
+	return 0
+end
+local session = cjson.decode(val)
+session['LastUsedAt'] = ARGV[1]
+redis.call('SET', KEYS[1], cjson.encode(session), 'KEEPTTL')
+return 1
+`)
+
+// SetSession stores the session and indexes it by user. The index expires
+// together with the most recently stored session of the user.
+func (ts *TokenStorage) SetSession(ctx context.Context, session domain.Session, expiration time.Duration) error {
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	_, err = ts.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey(session.ID), sessionJSON, expiration)
+		pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID.String())
+		pipe.Expire(ctx, userSessionsKey(session.UserID), expiration)
+		return nil
+	})
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	return nil
+}
+
+func (ts *TokenStorage) ExtendSession(ctx context.Context, session domain.Session, expiration time.Duration) error {
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	stored, err := extendSessionScript.Run(ctx, ts.redisClient,
+		[]string{sessionKey(session.ID), userSessionsKey(session.UserID)},
+		sessionJSON, expiration.Milliseconds(), session.ID.String()).Int()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	if stored == 0 {
+		return ports.ErrNotExistingKey
+	}
+
+	return nil
+}
+
+func (ts *TokenStorage) TouchSession(ctx context.Context, sessionID uuid.UUID, lastUsedAt time.Time) error {
+	stored, err := touchSessionScript.Run(ctx, ts.redisClient, []string{sessionKey(sessionID)},
+		lastUsedAt.Format(time.RFC3339Nano)).Int()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	if stored == 0 {
+		return ports.ErrNotExistingKey
+	}
+
+	return nil
+}
+
+func (ts *TokenStorage) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	val, err := ts.redisClient.Get(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ports.ErrNotExistingKey
+		}
+
+		return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	var session domain.Session
+	if err = json.Unmarshal([]byte(val), &session); err != nil {
+		return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	return &session, nil
+}
+
+func (ts *TokenStorage) GetUserSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	ids, err := ts.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	if len(ids) == 0 {
+		return []domain.Session{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = sessionKeyPrefix + id
+	}
+
+	values, err := ts.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	sessions := make([]domain.Session, 0, len(values))
+	var expired []interface{}
+	for i, value := range values {
+		val, ok := value.(string)
+		if !ok {
+			expired = append(expired, ids[i])
+			continue
+		}
+
+		var session domain.Session
+		if err = json.Unmarshal([]byte(val), &session); err != nil {
+			return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(expired) > 0 {
+		err = ts.redisClient.SRem(ctx, userSessionsKey(userID), expired...).Err()
+		if err != nil {
+			return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+		}
+	}
+
+	return sessions, nil
+}
+
+func (ts *TokenStorage) DelSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	session, err := ts.GetSession(ctx, sessionID)
+	if err != nil && err != ports.ErrNotExistingKey {
+		return err
+	}
+
+	var del *redis.IntCmd
+	_, err = ts.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		del = pipe.Del(ctx, sessionKey(sessionID))
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID.String())
+		if session != nil && session.RefreshTokenID != "" {
+			pipe.Del(ctx, ports.RefreshTokenKey(session.RefreshTokenID))
+		}
+		return nil
+	})
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	if del.Val() != 1 {
+		return ports.ErrNotExistingKey
+	}
+
+	return nil
+}
+
+func (ts *TokenStorage) SetOneTimeToken(ctx context.Context, key string, token domain.OneTimeToken, expiration time.Duration) error {
+	tokenJSON, err := json.Marshal(token)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	err = ts.redisClient.Set(ctx, key, tokenJSON, expiration).Err()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	return nil
+}
+
+func (ts *TokenStorage) GetDelOneTimeToken(ctx context.Context, key string) (*domain.OneTimeToken, error) {
+	val, err := ts.redisClient.GetDel(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ports.ErrNotExistingKey
+		}
+
+		return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	var token domain.OneTimeToken
+	if err = json.Unmarshal([]byte(val), &token); err != nil {
+		return nil, util.WrapError(ports.ErrInternalTokenStorage, err)
+	}
+
+	return &token, nil
+}
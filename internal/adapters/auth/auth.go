@@ -2,17 +2,35 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/auth/ports"
 	"github.com/hanoys/sigma-music/internal/domain"
 	serviceports "github.com/hanoys/sigma-music/internal/ports"
 	"github.com/hanoys/sigma-music/internal/util"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+
+	revokedKeyPrefix = "revoked:"
+
+	// sessionTouchInterval limits how often verifying an access token
+	// rewrites the last used time of its session.
+	sessionTouchInterval = time.Minute
+)
+
+// JWTClaims carries the session (token family) ID in the payload. Access and
+// refresh tokens issued together share the same jti, so rotating a refresh
+// token also tells which access token has to be revoked.
 type JWTClaims struct {
 	domain.Payload
+	TokenType string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
@@ -20,28 +38,42 @@ type ProviderConfig struct {
 	AccessTokenExpTime  int64
 	RefreshTokenExpTime int64
 	SecretKey           string
+	// Keys overrides SecretKey with asymmetric signing keys.
+	Keys *KeySet
 }
 
+// Provider keeps three kinds of records in the token storage:
+//   - the session itself, which lives while the token family is valid;
+//   - refresh:<jti>, the only refresh token of the family that may be used,
+//     consumed on rotation;
+//   - revoked:<jti>, access tokens revoked before their expiration.
 type Provider struct {
 	tokenStorage ports.ITokenStorage
 	cfg          *ProviderConfig
 }
 
 func NewProvider(tokenStorage ports.ITokenStorage, cfg *ProviderConfig) *Provider {
+	if cfg.Keys == nil {
+		cfg.Keys = NewHMACKeySet(cfg.SecretKey)
+	}
+
 	return &Provider{tokenStorage: tokenStorage,
 		cfg: cfg}
 }
 
-func (p *Provider) newTokenWithExpiration(ctx context.Context, payload domain.Payload, exp time.Time) (string, error) {
-	claims := &JWTClaims{
-		Payload: payload,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(exp),
-		},
-	}
+func (p *Provider) accessTokenTTL() time.Duration {
+	return time.Minute * time.Duration(p.cfg.AccessTokenExpTime)
+}
+
+func (p *Provider) refreshTokenTTL() time.Duration {
+	return time.Minute * time.Duration(p.cfg.RefreshTokenExpTime)
+}
+
+func (p *Provider) newTokenWithExpiration(ctx context.Context, claims *JWTClaims, exp time.Time) (string, error) {
+	claims.ExpiresAt = jwt.NewNumericDate(exp)
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(p.cfg.SecretKey))
+	tokenString, err := p.cfg.Keys.sign(claims)
 	if err != nil {
 		return "", serviceports.ErrTokenProviderSignToken
 	}
@@ -49,32 +81,55 @@ func (p *Provider) newTokenWithExpiration(ctx context.Context, payload domain.Pa
 	return tokenString, nil
 }
 
-func (p *Provider) NewSession(ctx context.Context, payload domain.Payload) (domain.TokenPair, error) {
-	accessExpTime := time.Now().Add(time.Minute * time.Duration(p.cfg.AccessTokenExpTime))
-	refreshExpTime := time.Now().Add(time.Minute * time.Duration(p.cfg.RefreshTokenExpTime))
-
-	accessTokenString, err := p.newTokenWithExpiration(ctx, payload, accessExpTime)
+func (p *Provider) issueTokens(ctx context.Context, payload domain.Payload, tokenID string) (domain.TokenPair, error) {
+	accessTokenString, err := p.newTokenWithExpiration(ctx, &JWTClaims{
+		Payload:          payload,
+		TokenType:        accessTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{ID: tokenID},
+	}, time.Now().Add(p.accessTokenTTL()))
 	if err != nil {
 		return domain.TokenPair{}, err
 	}
 
-	refreshTokenString, err := p.newTokenWithExpiration(ctx, payload, refreshExpTime)
+	refreshTokenString, err := p.newTokenWithExpiration(ctx, &JWTClaims{
+		Payload:          payload,
+		TokenType:        refreshTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{ID: tokenID},
+	}, time.Now().Add(p.refreshTokenTTL()))
 	if err != nil {
 		return domain.TokenPair{}, err
 	}
 
-	tokenPair := domain.TokenPair{
+	err = p.tokenStorage.Set(ctx, ports.RefreshTokenKey(tokenID), payload, p.refreshTokenTTL())
+	if err != nil {
+		return domain.TokenPair{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	return domain.TokenPair{
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
-	}
+	}, nil
+}
+
+func (p *Provider) NewSession(ctx context.Context, payload domain.Payload, metadata domain.SessionMetadata) (domain.TokenPair, error) {
+	now := time.Now()
+	payload.SessionID = uuid.New()
+	tokenID := uuid.New().String()
 
-	err = p.tokenStorage.Set(ctx, tokenPair.AccessToken,
-		payload, refreshExpTime.Sub(time.Now()))
+	err := p.tokenStorage.SetSession(ctx, domain.Session{
+		ID:             payload.SessionID,
+		UserID:         payload.UserID,
+		Role:           payload.Role,
+		Metadata:       metadata,
+		CreatedAt:      now,
+		LastUsedAt:     now,
+		RefreshTokenID: tokenID,
+	}, p.refreshTokenTTL())
 	if err != nil {
 		return domain.TokenPair{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
 	}
 
-	return tokenPair, nil
+	return p.issueTokens(ctx, payload, tokenID)
 }
 
 func (p *Provider) RefreshSession(ctx context.Context, refreshTokenString string) (domain.TokenPair, error) {
@@ -83,31 +138,56 @@ func (p *Provider) RefreshSession(ctx context.Context, refreshTokenString string
 		return domain.TokenPair{}, err
 	}
 
-	err = p.tokenStorage.Del(ctx, refreshTokenString)
+	if refreshClaims.TokenType != refreshTokenType {
+		return domain.TokenPair{}, serviceports.ErrTokenProviderInvalidToken
+	}
+
+	session, err := p.getSession(ctx, refreshClaims.SessionID)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	_, err = p.tokenStorage.GetDel(ctx, ports.RefreshTokenKey(refreshClaims.ID))
+	if errors.Is(err, ports.ErrNotExistingKey) {
+		// The refresh token was already rotated, so either the client or an
+		// attacker holds a stolen copy: the whole family is revoked.
+		err = p.tokenStorage.DelSession(ctx, session.UserID, session.ID)
+		if err != nil && !errors.Is(err, ports.ErrNotExistingKey) {
+			return domain.TokenPair{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
+		}
+
+		return domain.TokenPair{}, serviceports.ErrTokenProviderReusedToken
+	} else if err != nil {
+		return domain.TokenPair{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	err = p.tokenStorage.Set(ctx, revokedKeyPrefix+refreshClaims.ID, refreshClaims.Payload, p.accessTokenTTL())
 	if err != nil {
 		return domain.TokenPair{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
 	}
 
-	payload := domain.Payload{
-		UserID: refreshClaims.UserID,
-		Role:   refreshClaims.Role,
+	// The session may have been revoked since it was read, extending it then
+	// fails instead of bringing it back.
+	tokenID := uuid.New().String()
+	session.LastUsedAt = time.Now()
+	session.RefreshTokenID = tokenID
+	err = p.tokenStorage.ExtendSession(ctx, *session, p.refreshTokenTTL())
+	if errors.Is(err, ports.ErrNotExistingKey) {
+		return domain.TokenPair{}, serviceports.ErrTokenProviderRevokedToken
+	} else if err != nil {
+		return domain.TokenPair{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
 	}
 
-	return p.NewSession(ctx, payload)
+	return p.issueTokens(ctx, refreshClaims.Payload, tokenID)
 }
 
-func (p *Provider) CloseSession(ctx context.Context, refreshTokenString string) error {
-	_, err := p.parseToken(refreshTokenString)
+func (p *Provider) CloseSession(ctx context.Context, tokenString string) error {
+	claims, err := p.parseToken(tokenString)
 	if err != nil {
 		return err
 	}
 
-	err = p.tokenStorage.Del(ctx, refreshTokenString)
-	if err != nil {
-		return util.WrapError(serviceports.ErrInternalTokenProvider, err)
-	}
-
-	return nil
+	return p.deleteSession(ctx, claims.UserID, claims.SessionID)
 }
 
 func (p *Provider) VerifyToken(ctx context.Context, tokenString string) (domain.Payload, error) {
@@ -120,15 +200,110 @@ func (p *Provider) VerifyToken(ctx context.Context, tokenString string) (domain.
 		return domain.Payload{}, serviceports.ErrTokenProviderExpiredToken
 	}
 
+	if claims.TokenType != accessTokenType {
+		return domain.Payload{}, serviceports.ErrTokenProviderInvalidToken
+	}
+
+	session, err := p.getSession(ctx, claims.SessionID)
+	if err != nil {
+		return domain.Payload{}, err
+	}
+
+	_, err = p.tokenStorage.Get(ctx, revokedKeyPrefix+claims.ID)
+	if err == nil {
+		return domain.Payload{}, serviceports.ErrTokenProviderRevokedToken
+	} else if !errors.Is(err, ports.ErrNotExistingKey) {
+		return domain.Payload{}, util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	if time.Since(session.LastUsedAt) > sessionTouchInterval {
+		// Last used time is informational, failing to store it must not
+		// reject an otherwise valid token.
+		_ = p.tokenStorage.TouchSession(ctx, session.ID, time.Now())
+	}
+
 	return claims.Payload, nil
 }
 
+func (p *Provider) GetSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	sessions, err := p.tokenStorage.GetUserSessions(ctx, userID)
+	if err != nil {
+		return nil, util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
+}
+
+func (p *Provider) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	session, err := p.getSession(ctx, sessionID)
+	if errors.Is(err, serviceports.ErrTokenProviderRevokedToken) {
+		return serviceports.ErrSessionNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if session.UserID != userID {
+		return serviceports.ErrSessionNotFound
+	}
+
+	err = p.deleteSession(ctx, userID, sessionID)
+	if errors.Is(err, serviceports.ErrTokenProviderRevokedToken) {
+		return serviceports.ErrSessionNotFound
+	}
+
+	return err
+}
+
+func (p *Provider) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	sessions, err := p.tokenStorage.GetUserSessions(ctx, userID)
+	if err != nil {
+		return util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	for _, session := range sessions {
+		err = p.deleteSession(ctx, userID, session.ID)
+		if err != nil && !errors.Is(err, serviceports.ErrTokenProviderRevokedToken) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) PublicKeys() []domain.JSONWebKey {
+	return p.cfg.Keys.PublicKeys()
+}
+
+func (p *Provider) getSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	session, err := p.tokenStorage.GetSession(ctx, sessionID)
+	if errors.Is(err, ports.ErrNotExistingKey) {
+		return nil, serviceports.ErrTokenProviderRevokedToken
+	} else if err != nil {
+		return nil, util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	return session, nil
+}
+
+func (p *Provider) deleteSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	err := p.tokenStorage.DelSession(ctx, userID, sessionID)
+	if errors.Is(err, ports.ErrNotExistingKey) {
+		return serviceports.ErrTokenProviderRevokedToken
+	} else if err != nil {
+		return util.WrapError(serviceports.ErrInternalTokenProvider, err)
+	}
+
+	return nil
+}
+
 func (p *Provider) parseToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString,
 		&JWTClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(p.cfg.SecretKey), nil
-		})
+		p.cfg.Keys.verificationKey)
 
 	if err != nil {
 		return nil, serviceports.ErrTokenProviderParsingToken
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnknownKeyID     = errors.New("unknown key id")
+	ErrUnexpectedMethod = errors.New("unexpected signing method")
+)
+
+// KeyConfig describes a key pair stored in PEM files. A key without a private
+// key path can only verify tokens, which is how retired keys are kept around
+// until every token they signed has expired.
+type KeyConfig struct {
+	ID             string
+	Algorithm      string
+	PrivateKeyPath string
+	PublicKeyPath  string
+}
+
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	privateKey interface{}
+	publicKey  interface{}
+}
+
+// KeySet holds the key new tokens are signed with and every key tokens are
+// still accepted from, indexed by the kid header.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// NewHMACKeySet keeps the shared secret setup: tokens are signed with HS256
+// and carry no kid header.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &signingKey{
+		method:     jwt.SigningMethodHS256,
+		privateKey: []byte(secret),
+		publicKey:  []byte(secret),
+	}
+
+	return &KeySet{
+		active: key,
+		keys:   map[string]*signingKey{"": key},
+	}
+}
+
+// LoadKeySet reads the configured keys and selects the signing key by its id.
+func LoadKeySet(signingKeyID string, configs []KeyConfig) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*signingKey, len(configs))}
+
+	for _, cfg := range configs {
+		if cfg.ID == "" {
+			return nil, errors.New("jwt key id is empty")
+		}
+
+		if _, ok := set.keys[cfg.ID]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id: %s", cfg.ID)
+		}
+
+		key, err := loadKey(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", cfg.ID, err)
+		}
+
+		set.keys[cfg.ID] = key
+	}
+
+	active, ok := set.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("jwt signing key %q is not configured", signingKeyID)
+	}
+
+	if active.privateKey == nil {
+		return nil, fmt.Errorf("jwt signing key %s has no private key", signingKeyID)
+	}
+
+	set.active = active
+
+	return set, nil
+}
+
+func loadKey(cfg KeyConfig) (*signingKey, error) {
+	if cfg.PrivateKeyPath == "" && cfg.PublicKeyPath == "" {
+		return nil, errors.New("neither private nor public key path is set")
+	}
+
+	key := &signingKey{id: cfg.ID}
+
+	switch cfg.Algorithm {
+	case AlgorithmRS256:
+		key.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyPath != "" {
+			privateKey, err := readPEM(cfg.PrivateKeyPath, jwt.ParseRSAPrivateKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+			key.privateKey = privateKey
+			key.publicKey = &privateKey.PublicKey
+		}
+		if cfg.PublicKeyPath != "" {
+			publicKey, err := readPEM(cfg.PublicKeyPath, jwt.ParseRSAPublicKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+			key.publicKey = publicKey
+		}
+	case AlgorithmEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if cfg.PrivateKeyPath != "" {
+			privateKey, err := readPEM(cfg.PrivateKeyPath, jwt.ParseEdPrivateKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+			key.privateKey = privateKey
+			key.publicKey = privateKey.(ed25519.PrivateKey).Public()
+		}
+		if cfg.PublicKeyPath != "" {
+			publicKey, err := readPEM(cfg.PublicKeyPath, jwt.ParseEdPublicKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+			key.publicKey = publicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", cfg.Algorithm)
+	}
+
+	return key, nil
+}
+
+func readPEM[T any](path string, parse func([]byte) (T, error)) (T, error) {
+	var key T
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return key, err
+	}
+
+	key, err = parse(buf)
+	if err != nil {
+		return key, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.method, claims)
+	if s.active.id != "" {
+		token.Header["kid"] = s.active.id
+	}
+
+	return token.SignedString(s.active.privateKey)
+}
+
+// verificationKey looks the key up by kid and refuses any other algorithm
+// than the one the key was configured with, so a public key can never be
+// used as an HMAC secret.
+func (s *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnexpectedMethod
+	}
+
+	return key.publicKey, nil
+}
+
+// PublicKeys returns the asymmetric verification keys as JSON Web Keys. The
+// HMAC secret is never published.
+func (s *KeySet) PublicKeys() []domain.JSONWebKey {
+	jwks := make([]domain.JSONWebKey, 0, len(s.keys))
+
+	for _, key := range s.keys {
+		jwk := domain.JSONWebKey{
+			KeyID:     key.id,
+			Use:       "sig",
+			Algorithm: key.method.Alg(),
+		}
+
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		jwks = append(jwks, jwk)
+	}
+
+	sort.Slice(jwks, func(i, j int) bool {
+		return jwks[i].KeyID < jwks[j].KeyID
+	})
+
+	return jwks
+}
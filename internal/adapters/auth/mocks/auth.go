@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/hanoys/sigma-music/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // TokenProvider is an autogenerated mock type for the ITokenProvider type
@@ -14,9 +16,9 @@ type TokenProvider struct {
 	mock.Mock
 }
 
-// CloseSession provides a mock function with given fields: ctx, refreshTokenString
-func (_m *TokenProvider) CloseSession(ctx context.Context, refreshTokenString string) error {
-	ret := _m.Called(ctx, refreshTokenString)
+// CloseSession provides a mock function with given fields: ctx, tokenString
+func (_m *TokenProvider) CloseSession(ctx context.Context, tokenString string) error {
+	ret := _m.Called(ctx, tokenString)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CloseSession")
@@ -24,7 +26,7 @@ func (_m *TokenProvider) CloseSession(ctx context.Context, refreshTokenString st
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, refreshTokenString)
+		r0 = rf(ctx, tokenString)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,9 +34,39 @@ func (_m *TokenProvider) CloseSession(ctx context.Context, refreshTokenString st
 	return r0
 }
 
-// NewSession provides a mock function with given fields: ctx, payload
-func (_m *TokenProvider) NewSession(ctx context.Context, payload domain.Payload) (domain.TokenPair, error) {
-	ret := _m.Called(ctx, payload)
+// GetSessions provides a mock function with given fields: ctx, userID
+func (_m *TokenProvider) GetSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessions")
+	}
+
+	var r0 []domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSession provides a mock function with given fields: ctx, payload, metadata
+func (_m *TokenProvider) NewSession(ctx context.Context, payload domain.Payload, metadata domain.SessionMetadata) (domain.TokenPair, error) {
+	ret := _m.Called(ctx, payload, metadata)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewSession")
@@ -42,17 +74,17 @@ func (_m *TokenProvider) NewSession(ctx context.Context, payload domain.Payload)
 
 	var r0 domain.TokenPair
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Payload) (domain.TokenPair, error)); ok {
-		return rf(ctx, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Payload, domain.SessionMetadata) (domain.TokenPair, error)); ok {
+		return rf(ctx, payload, metadata)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Payload) domain.TokenPair); ok {
-		r0 = rf(ctx, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Payload, domain.SessionMetadata) domain.TokenPair); ok {
+		r0 = rf(ctx, payload, metadata)
 	} else {
 		r0 = ret.Get(0).(domain.TokenPair)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Payload) error); ok {
-		r1 = rf(ctx, payload)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Payload, domain.SessionMetadata) error); ok {
+		r1 = rf(ctx, payload, metadata)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,6 +92,26 @@ func (_m *TokenProvider) NewSession(ctx context.Context, payload domain.Payload)
 	return r0, r1
 }
 
+// PublicKeys provides a mock function with no fields
+func (_m *TokenProvider) PublicKeys() []domain.JSONWebKey {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 []domain.JSONWebKey
+	if rf, ok := ret.Get(0).(func() []domain.JSONWebKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JSONWebKey)
+		}
+	}
+
+	return r0
+}
+
 // RefreshSession provides a mock function with given fields: ctx, refreshTokenString
 func (_m *TokenProvider) RefreshSession(ctx context.Context, refreshTokenString string) (domain.TokenPair, error) {
 	ret := _m.Called(ctx, refreshTokenString)
@@ -88,6 +140,42 @@ func (_m *TokenProvider) RefreshSession(ctx context.Context, refreshTokenString
 	return r0, r1
 }
 
+// RevokeAllSessions provides a mock function with given fields: ctx, userID
+func (_m *TokenProvider) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *TokenProvider) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // VerifyToken provides a mock function with given fields: ctx, accessTokenString
 func (_m *TokenProvider) VerifyToken(ctx context.Context, accessTokenString string) (domain.Payload, error) {
 	ret := _m.Called(ctx, accessTokenString)
@@ -0,0 +1,87 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OneTimeTokenProvider is an autogenerated mock type for the IOneTimeTokenProvider type
+type OneTimeTokenProvider struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: ctx, purpose, tokenString
+func (_m *OneTimeTokenProvider) Consume(ctx context.Context, purpose domain.OneTimeTokenPurpose, tokenString string) (domain.OneTimeToken, error) {
+	ret := _m.Called(ctx, purpose, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 domain.OneTimeToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OneTimeTokenPurpose, string) (domain.OneTimeToken, error)); ok {
+		return rf(ctx, purpose, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OneTimeTokenPurpose, string) domain.OneTimeToken); ok {
+		r0 = rf(ctx, purpose, tokenString)
+	} else {
+		r0 = ret.Get(0).(domain.OneTimeToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OneTimeTokenPurpose, string) error); ok {
+		r1 = rf(ctx, purpose, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Issue provides a mock function with given fields: ctx, token, ttl
+func (_m *OneTimeTokenProvider) Issue(ctx context.Context, token domain.OneTimeToken, ttl time.Duration) (string, error) {
+	ret := _m.Called(ctx, token, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Issue")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OneTimeToken, time.Duration) (string, error)); ok {
+		return rf(ctx, token, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OneTimeToken, time.Duration) string); ok {
+		r0 = rf(ctx, token, ttl)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OneTimeToken, time.Duration) error); ok {
+		r1 = rf(ctx, token, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOneTimeTokenProvider creates a new instance of OneTimeTokenProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOneTimeTokenProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OneTimeTokenProvider {
+	mock := &OneTimeTokenProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
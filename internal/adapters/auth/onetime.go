@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/adapters/auth/ports"
+	"github.com/hanoys/sigma-music/internal/domain"
+	serviceports "github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+)
+
+const (
+	oneTimeKeyPrefix = "onetime:"
+	oneTimeIDLength  = 32
+)
+
+// OneTimeTokenProvider issues tokens of the form <id>.<signature>. The
+// signature covers the purpose, so a forged or mistyped token is rejected
+// before the storage is queried and a token of one purpose can't be used for
+// another. The stored record is removed on the first use.
+type OneTimeTokenProvider struct {
+	tokenStorage ports.IOneTimeTokenStorage
+	secret       []byte
+}
+
+func NewOneTimeTokenProvider(tokenStorage ports.IOneTimeTokenStorage, secret string) *OneTimeTokenProvider {
+	return &OneTimeTokenProvider{
+		tokenStorage: tokenStorage,
+		secret:       []byte(secret),
+	}
+}
+
+func (p *OneTimeTokenProvider) signature(purpose domain.OneTimeTokenPurpose, id string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(string(purpose) + "." + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func oneTimeKey(purpose domain.OneTimeTokenPurpose, id string) string {
+	return oneTimeKeyPrefix + string(purpose) + ":" + id
+}
+
+func (p *OneTimeTokenProvider) Issue(ctx context.Context, token domain.OneTimeToken, ttl time.Duration) (string, error) {
+	buf := make([]byte, oneTimeIDLength)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", util.WrapError(serviceports.ErrInternalOneTimeTokenProvider, err)
+	}
+	id := base64.RawURLEncoding.EncodeToString(buf)
+
+	err = p.tokenStorage.SetOneTimeToken(ctx, oneTimeKey(token.Purpose, id), token, ttl)
+	if err != nil {
+		return "", util.WrapError(serviceports.ErrInternalOneTimeTokenProvider, err)
+	}
+
+	return id + "." + p.signature(token.Purpose, id), nil
+}
+
+func (p *OneTimeTokenProvider) Consume(ctx context.Context, purpose domain.OneTimeTokenPurpose,
+	tokenString string) (domain.OneTimeToken, error) {
+	id, signature, found := strings.Cut(tokenString, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(p.signature(purpose, id))) {
+		return domain.OneTimeToken{}, serviceports.ErrOneTimeTokenInvalid
+	}
+
+	token, err := p.tokenStorage.GetDelOneTimeToken(ctx, oneTimeKey(purpose, id))
+	if errors.Is(err, ports.ErrNotExistingKey) {
+		return domain.OneTimeToken{}, serviceports.ErrOneTimeTokenInvalid
+	} else if err != nil {
+		return domain.OneTimeToken{}, util.WrapError(serviceports.ErrInternalOneTimeTokenProvider, err)
+	}
+
+	if token.Purpose != purpose {
+		return domain.OneTimeToken{}, serviceports.ErrOneTimeTokenInvalid
+	}
+
+	return *token, nil
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"errors"
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"time"
 )
@@ -12,7 +13,33 @@ var (
 	ErrNotExistingKey       = errors.New("key doesn't exist")
 )
 
+func RefreshTokenKey(tokenID string) string {
+	return "refresh:" + tokenID
+}
+
 type ITokenStorage interface {
 	Set(ctx context.Context, key string, payload domain.Payload, expiration time.Duration) error
+	Get(ctx context.Context, key string) (*domain.Payload, error)
+	// GetDel atomically reads and removes the key, so only one caller can consume it.
+	GetDel(ctx context.Context, key string) (*domain.Payload, error)
 	Del(ctx context.Context, key string) error
+
+	SetSession(ctx context.Context, session domain.Session, expiration time.Duration) error
+	// TouchSession updates only the last used time of an existing session and
+	// keeps its expiration. It returns ErrNotExistingKey if the session is gone.
+	TouchSession(ctx context.Context, sessionID uuid.UUID, lastUsedAt time.Time) error
+	// ExtendSession replaces an existing session and restarts its expiration.
+	// It returns ErrNotExistingKey if the session is gone.
+	ExtendSession(ctx context.Context, session domain.Session, expiration time.Duration) error
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error)
+	GetUserSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	// DelSession removes the session together with its refresh token.
+	DelSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+}
+
+type IOneTimeTokenStorage interface {
+	SetOneTimeToken(ctx context.Context, key string, token domain.OneTimeToken, expiration time.Duration) error
+	// GetDelOneTimeToken atomically reads and removes the token, so it can be
+	// consumed only once.
+	GetDelOneTimeToken(ctx context.Context, key string) (*domain.OneTimeToken, error)
 }
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/auth"
+	"github.com/hanoys/sigma-music/internal/adapters/auth/ports"
+	"github.com/hanoys/sigma-music/internal/domain"
+	serviceports "github.com/hanoys/sigma-music/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTokenStorage mirrors the redis storage semantics without expiration.
+type memoryTokenStorage struct {
+	mu       sync.Mutex
+	data     map[string]domain.Payload
+	sessions map[uuid.UUID]domain.Session
+}
+
+func newMemoryTokenStorage() *memoryTokenStorage {
+	return &memoryTokenStorage{
+		data:     make(map[string]domain.Payload),
+		sessions: make(map[uuid.UUID]domain.Session),
+	}
+}
+
+func (s *memoryTokenStorage) Set(ctx context.Context, key string, payload domain.Payload, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = payload
+	return nil
+}
+
+func (s *memoryTokenStorage) Get(ctx context.Context, key string) (*domain.Payload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payload, ok := s.data[key]
+	if !ok {
+		return nil, ports.ErrNotExistingKey
+	}
+	return &payload, nil
+}
+
+func (s *memoryTokenStorage) GetDel(ctx context.Context, key string) (*domain.Payload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payload, ok := s.data[key]
+	if !ok {
+		return nil, ports.ErrNotExistingKey
+	}
+	delete(s.data, key)
+	return &payload, nil
+}
+
+func (s *memoryTokenStorage) Del(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; !ok {
+		return ports.ErrNotExistingKey
+	}
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memoryTokenStorage) SetSession(ctx context.Context, session domain.Session, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+	return nil
+}
+
+func (s *memoryTokenStorage) TouchSession(ctx context.Context, sessionID uuid.UUID, lastUsedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return ports.ErrNotExistingKey
+	}
+	session.LastUsedAt = lastUsedAt
+	s.sessions[sessionID] = session
+	return nil
+}
+
+func (s *memoryTokenStorage) ExtendSession(ctx context.Context, session domain.Session, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[session.ID]; !ok {
+		return ports.ErrNotExistingKey
+	}
+	s.sessions[session.ID] = session
+	return nil
+}
+
+func (s *memoryTokenStorage) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, ports.ErrNotExistingKey
+	}
+	return &session, nil
+}
+
+func (s *memoryTokenStorage) GetUserSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]domain.Session, 0)
+	for _, session := range s.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (s *memoryTokenStorage) DelSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return ports.ErrNotExistingKey
+	}
+	delete(s.sessions, sessionID)
+	delete(s.data, ports.RefreshTokenKey(session.RefreshTokenID))
+	return nil
+}
+
+func newProvider() *auth.Provider {
+	return auth.NewProvider(newMemoryTokenStorage(), &auth.ProviderConfig{
+		AccessTokenExpTime:  10,
+		RefreshTokenExpTime: 60,
+		SecretKey:           "secret",
+	})
+}
+
+func TestProviderSession(t *testing.T) {
+	ctx := context.Background()
+	payload := domain.Payload{UserID: uuid.New(), Role: domain.UserRole}
+	metadata := domain.SessionMetadata{DeviceLabel: "laptop", UserAgent: "test", IP: "127.0.0.1"}
+
+	t.Run("test verify access token", func(t *testing.T) {
+		provider := newProvider()
+		tokens, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		verified, err := provider.VerifyToken(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, payload.UserID, verified.UserID)
+		require.Equal(t, payload.Role, verified.Role)
+		require.NotEqual(t, uuid.Nil, verified.SessionID)
+
+		_, err = provider.VerifyToken(ctx, tokens.RefreshToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderInvalidToken)
+	})
+
+	t.Run("test refresh rotates tokens", func(t *testing.T) {
+		provider := newProvider()
+		tokens, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		rotated, err := provider.RefreshSession(ctx, tokens.RefreshToken)
+		require.NoError(t, err)
+		require.NotEqual(t, tokens, rotated)
+
+		_, err = provider.VerifyToken(ctx, tokens.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+
+		_, err = provider.VerifyToken(ctx, rotated.AccessToken)
+		require.NoError(t, err)
+	})
+
+	t.Run("test refresh token reuse revokes family", func(t *testing.T) {
+		provider := newProvider()
+		tokens, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		rotated, err := provider.RefreshSession(ctx, tokens.RefreshToken)
+		require.NoError(t, err)
+
+		_, err = provider.RefreshSession(ctx, tokens.RefreshToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderReusedToken)
+
+		_, err = provider.VerifyToken(ctx, rotated.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+
+		_, err = provider.RefreshSession(ctx, rotated.RefreshToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+	})
+
+	t.Run("test close session", func(t *testing.T) {
+		provider := newProvider()
+		tokens, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		err = provider.CloseSession(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+
+		_, err = provider.VerifyToken(ctx, tokens.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+
+		_, err = provider.RefreshSession(ctx, tokens.RefreshToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+	})
+
+	t.Run("test sessions are independent", func(t *testing.T) {
+		provider := newProvider()
+		first, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+		second, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		err = provider.CloseSession(ctx, first.AccessToken)
+		require.NoError(t, err)
+
+		_, err = provider.VerifyToken(ctx, second.AccessToken)
+		require.NoError(t, err)
+	})
+
+	t.Run("test list and revoke sessions", func(t *testing.T) {
+		provider := newProvider()
+		first, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+		second, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		sessions, err := provider.GetSessions(ctx, payload.UserID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		require.Equal(t, metadata, sessions[0].Metadata)
+
+		firstPayload, err := provider.VerifyToken(ctx, first.AccessToken)
+		require.NoError(t, err)
+
+		err = provider.RevokeSession(ctx, uuid.New(), firstPayload.SessionID)
+		require.ErrorIs(t, err, serviceports.ErrSessionNotFound)
+
+		err = provider.RevokeSession(ctx, payload.UserID, firstPayload.SessionID)
+		require.NoError(t, err)
+
+		_, err = provider.VerifyToken(ctx, first.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+
+		_, err = provider.VerifyToken(ctx, second.AccessToken)
+		require.NoError(t, err)
+	})
+
+	t.Run("test revoke all sessions", func(t *testing.T) {
+		provider := newProvider()
+		first, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+		second, err := provider.NewSession(ctx, payload, metadata)
+		require.NoError(t, err)
+
+		err = provider.RevokeAllSessions(ctx, payload.UserID)
+		require.NoError(t, err)
+
+		_, err = provider.VerifyToken(ctx, first.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+		_, err = provider.VerifyToken(ctx, second.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderRevokedToken)
+
+		sessions, err := provider.GetSessions(ctx, payload.UserID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+}
@@ -0,0 +1,198 @@
+package test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/auth"
+	"github.com/hanoys/sigma-music/internal/domain"
+	serviceports "github.com/hanoys/sigma-music/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir string, name string, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	require.NoError(t, err)
+	return path
+}
+
+func newRSAKey(t *testing.T, dir string, id string) auth.KeyConfig {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	return auth.KeyConfig{
+		ID:             id,
+		Algorithm:      auth.AlgorithmRS256,
+		PrivateKeyPath: writePEM(t, dir, id+".pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		PublicKeyPath:  writePEM(t, dir, id+".pub.pem", "PUBLIC KEY", publicDER),
+	}
+}
+
+func newEd25519Key(t *testing.T, dir string, id string) auth.KeyConfig {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return auth.KeyConfig{
+		ID:             id,
+		Algorithm:      auth.AlgorithmEdDSA,
+		PrivateKeyPath: writePEM(t, dir, id+".pem", "PRIVATE KEY", privateDER),
+	}
+}
+
+func newKeysProvider(storage *memoryTokenStorage, keys *auth.KeySet) *auth.Provider {
+	return auth.NewProvider(storage, &auth.ProviderConfig{
+		AccessTokenExpTime:  10,
+		RefreshTokenExpTime: 60,
+		Keys:                keys,
+	})
+}
+
+func TestProviderKeys(t *testing.T) {
+	ctx := context.Background()
+	payload := domain.Payload{UserID: uuid.New(), Role: domain.UserRole}
+
+	t.Run("test sign with rsa key", func(t *testing.T) {
+		keys, err := auth.LoadKeySet("rsa", []auth.KeyConfig{newRSAKey(t, t.TempDir(), "rsa")})
+		require.NoError(t, err)
+
+		provider := newKeysProvider(newMemoryTokenStorage(), keys)
+		tokens, err := provider.NewSession(ctx, payload, domain.SessionMetadata{})
+		require.NoError(t, err)
+
+		token, _, err := jwt.NewParser().ParseUnverified(tokens.AccessToken, &auth.JWTClaims{})
+		require.NoError(t, err)
+		require.Equal(t, "rsa", token.Header["kid"])
+		require.Equal(t, "RS256", token.Header["alg"])
+
+		verified, err := provider.VerifyToken(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, payload.UserID, verified.UserID)
+	})
+
+	t.Run("test rotated key still verifies", func(t *testing.T) {
+		dir := t.TempDir()
+		oldKey := newEd25519Key(t, dir, "old")
+		newKey := newRSAKey(t, dir, "new")
+		storage := newMemoryTokenStorage()
+
+		oldKeys, err := auth.LoadKeySet("old", []auth.KeyConfig{oldKey})
+		require.NoError(t, err)
+		tokens, err := newKeysProvider(storage, oldKeys).NewSession(ctx, payload, domain.SessionMetadata{})
+		require.NoError(t, err)
+
+		retiredKey := oldKey
+		retiredKey.PrivateKeyPath = ""
+		retiredKey.PublicKeyPath = writePEM(t, dir, "old.pub.pem", "PUBLIC KEY",
+			marshalEd25519Public(t, oldKey.PrivateKeyPath))
+
+		rotatedKeys, err := auth.LoadKeySet("new", []auth.KeyConfig{newKey, retiredKey})
+		require.NoError(t, err)
+		provider := newKeysProvider(storage, rotatedKeys)
+
+		_, err = provider.VerifyToken(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+
+		rotated, err := provider.RefreshSession(ctx, tokens.RefreshToken)
+		require.NoError(t, err)
+
+		token, _, err := jwt.NewParser().ParseUnverified(rotated.AccessToken, &auth.JWTClaims{})
+		require.NoError(t, err)
+		require.Equal(t, "new", token.Header["kid"])
+	})
+
+	t.Run("test verification only key can't sign", func(t *testing.T) {
+		key := newRSAKey(t, t.TempDir(), "rsa")
+		key.PrivateKeyPath = ""
+
+		_, err := auth.LoadKeySet("rsa", []auth.KeyConfig{key})
+		require.Error(t, err)
+	})
+
+	t.Run("test unknown key id", func(t *testing.T) {
+		_, err := auth.LoadKeySet("missing", []auth.KeyConfig{newRSAKey(t, t.TempDir(), "rsa")})
+		require.Error(t, err)
+	})
+
+	t.Run("test hmac token rejected by asymmetric keys", func(t *testing.T) {
+		keys, err := auth.LoadKeySet("rsa", []auth.KeyConfig{newRSAKey(t, t.TempDir(), "rsa")})
+		require.NoError(t, err)
+		storage := newMemoryTokenStorage()
+
+		tokens, err := newProvider().NewSession(ctx, payload, domain.SessionMetadata{})
+		require.NoError(t, err)
+
+		_, err = newKeysProvider(storage, keys).VerifyToken(ctx, tokens.AccessToken)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderParsingToken)
+	})
+
+	t.Run("test public key used as hmac secret rejected", func(t *testing.T) {
+		key := newRSAKey(t, t.TempDir(), "rsa")
+		keys, err := auth.LoadKeySet("rsa", []auth.KeyConfig{key})
+		require.NoError(t, err)
+
+		publicPEM, err := os.ReadFile(key.PublicKeyPath)
+		require.NoError(t, err)
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.JWTClaims{Payload: payload, TokenType: "access"})
+		token.Header["kid"] = "rsa"
+		forged, err := token.SignedString(publicPEM)
+		require.NoError(t, err)
+
+		_, err = newKeysProvider(newMemoryTokenStorage(), keys).VerifyToken(ctx, forged)
+		require.ErrorIs(t, err, serviceports.ErrTokenProviderParsingToken)
+	})
+
+	t.Run("test public keys", func(t *testing.T) {
+		dir := t.TempDir()
+		keys, err := auth.LoadKeySet("ed", []auth.KeyConfig{newRSAKey(t, dir, "rsa"), newEd25519Key(t, dir, "ed")})
+		require.NoError(t, err)
+
+		jwks := newKeysProvider(newMemoryTokenStorage(), keys).PublicKeys()
+		require.Len(t, jwks, 2)
+
+		require.Equal(t, "ed", jwks[0].KeyID)
+		require.Equal(t, "OKP", jwks[0].KeyType)
+		require.Equal(t, "Ed25519", jwks[0].Curve)
+		require.Equal(t, "EdDSA", jwks[0].Algorithm)
+		require.NotEmpty(t, jwks[0].X)
+
+		require.Equal(t, "rsa", jwks[1].KeyID)
+		require.Equal(t, "RSA", jwks[1].KeyType)
+		require.Equal(t, "RS256", jwks[1].Algorithm)
+		require.Equal(t, "AQAB", jwks[1].Exponent)
+		require.NotEmpty(t, jwks[1].Modulus)
+	})
+
+	t.Run("test secret is not published", func(t *testing.T) {
+		require.Empty(t, newProvider().PublicKeys())
+	})
+}
+
+func marshalEd25519Public(t *testing.T, privateKeyPath string) []byte {
+	buf, err := os.ReadFile(privateKeyPath)
+	require.NoError(t, err)
+
+	key, err := jwt.ParseEdPrivateKeyFromPEM(buf)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(key.(ed25519.PrivateKey).Public())
+	require.NoError(t, err)
+
+	return der
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/auth"
+	"github.com/hanoys/sigma-music/internal/adapters/auth/ports"
+	"github.com/hanoys/sigma-music/internal/domain"
+	serviceports "github.com/hanoys/sigma-music/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryOneTimeTokenStorage struct {
+	mu     sync.Mutex
+	tokens map[string]domain.OneTimeToken
+}
+
+func newMemoryOneTimeTokenStorage() *memoryOneTimeTokenStorage {
+	return &memoryOneTimeTokenStorage{tokens: make(map[string]domain.OneTimeToken)}
+}
+
+func (s *memoryOneTimeTokenStorage) SetOneTimeToken(ctx context.Context, key string, token domain.OneTimeToken,
+	expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[key] = token
+	return nil
+}
+
+func (s *memoryOneTimeTokenStorage) GetDelOneTimeToken(ctx context.Context, key string) (*domain.OneTimeToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, ports.ErrNotExistingKey
+	}
+	delete(s.tokens, key)
+	return &token, nil
+}
+
+func TestOneTimeTokenProvider(t *testing.T) {
+	ctx := context.Background()
+	token := domain.OneTimeToken{
+		Purpose:   domain.OneTimeTokenVerifyEmail,
+		AccountID: uuid.New(),
+		Email:     "test@mail.com",
+	}
+
+	t.Run("test consume once", func(t *testing.T) {
+		provider := auth.NewOneTimeTokenProvider(newMemoryOneTimeTokenStorage(), "secret")
+
+		tokenString, err := provider.Issue(ctx, token, time.Minute)
+		require.NoError(t, err)
+
+		consumed, err := provider.Consume(ctx, domain.OneTimeTokenVerifyEmail, tokenString)
+		require.NoError(t, err)
+		require.Equal(t, token, consumed)
+
+		_, err = provider.Consume(ctx, domain.OneTimeTokenVerifyEmail, tokenString)
+		require.ErrorIs(t, err, serviceports.ErrOneTimeTokenInvalid)
+	})
+
+	t.Run("test other purpose rejected", func(t *testing.T) {
+		storage := newMemoryOneTimeTokenStorage()
+		provider := auth.NewOneTimeTokenProvider(storage, "secret")
+
+		tokenString, err := provider.Issue(ctx, token, time.Minute)
+		require.NoError(t, err)
+
+		_, err = provider.Consume(ctx, domain.OneTimeTokenResetPassword, tokenString)
+		require.ErrorIs(t, err, serviceports.ErrOneTimeTokenInvalid)
+		require.Len(t, storage.tokens, 1)
+	})
+
+	t.Run("test forged signature rejected", func(t *testing.T) {
+		storage := newMemoryOneTimeTokenStorage()
+		tokenString, err := auth.NewOneTimeTokenProvider(storage, "secret").Issue(ctx, token, time.Minute)
+		require.NoError(t, err)
+
+		_, err = auth.NewOneTimeTokenProvider(storage, "other").
+			Consume(ctx, domain.OneTimeTokenVerifyEmail, tokenString)
+		require.ErrorIs(t, err, serviceports.ErrOneTimeTokenInvalid)
+
+		id, _, _ := strings.Cut(tokenString, ".")
+		_, err = auth.NewOneTimeTokenProvider(storage, "secret").
+			Consume(ctx, domain.OneTimeTokenVerifyEmail, id)
+		require.ErrorIs(t, err, serviceports.ErrOneTimeTokenInvalid)
+		require.Len(t, storage.tokens, 1)
+	})
+}
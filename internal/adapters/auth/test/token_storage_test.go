@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/auth/adapters"
+	"github.com/hanoys/sigma-music/internal/adapters/auth/ports"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisTokenStorage(t *testing.T) (*adapters.TokenStorage, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return adapters.NewTokenStorage(client), server
+}
+
+func newStoredSession(t *testing.T, storage *adapters.TokenStorage) domain.Session {
+	ctx := context.Background()
+	session := domain.Session{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Role:           domain.UserRole,
+		CreatedAt:      time.Now(),
+		LastUsedAt:     time.Now(),
+		RefreshTokenID: uuid.New().String(),
+	}
+
+	err := storage.SetSession(ctx, session, time.Hour)
+	require.NoError(t, err)
+	err = storage.Set(ctx, ports.RefreshTokenKey(session.RefreshTokenID), domain.Payload{UserID: session.UserID}, time.Hour)
+	require.NoError(t, err)
+	return session
+}
+
+func TestTokenStorageSession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("test touch keeps expiration", func(t *testing.T) {
+		storage, server := newRedisTokenStorage(t)
+		session := newStoredSession(t, storage)
+
+		lastUsedAt := time.Now().Add(time.Minute)
+		err := storage.TouchSession(ctx, session.ID, lastUsedAt)
+		require.NoError(t, err)
+
+		require.Equal(t, time.Hour, server.TTL("session:"+session.ID.String()))
+		stored, err := storage.GetSession(ctx, session.ID)
+		require.NoError(t, err)
+		require.True(t, lastUsedAt.Equal(stored.LastUsedAt))
+	})
+
+	t.Run("test touch keeps rotated refresh token", func(t *testing.T) {
+		storage, _ := newRedisTokenStorage(t)
+		session := newStoredSession(t, storage)
+		session.Metadata = domain.SessionMetadata{DeviceLabel: "Firefox on Linux", UserAgent: "Mozilla/5.0"}
+		err := storage.SetSession(ctx, session, time.Hour)
+		require.NoError(t, err)
+
+		rotated := session
+		rotated.RefreshTokenID = uuid.New().String()
+		err = storage.ExtendSession(ctx, rotated, time.Hour)
+		require.NoError(t, err)
+
+		err = storage.TouchSession(ctx, session.ID, time.Now())
+		require.NoError(t, err)
+
+		stored, err := storage.GetSession(ctx, session.ID)
+		require.NoError(t, err)
+		require.Equal(t, rotated.RefreshTokenID, stored.RefreshTokenID)
+		require.Equal(t, session.Metadata, stored.Metadata)
+		require.Equal(t, session.Role, stored.Role)
+	})
+
+	t.Run("test touch does not recreate deleted session", func(t *testing.T) {
+		storage, server := newRedisTokenStorage(t)
+		session := newStoredSession(t, storage)
+
+		err := storage.DelSession(ctx, session.UserID, session.ID)
+		require.NoError(t, err)
+
+		err = storage.TouchSession(ctx, session.ID, time.Now())
+		require.ErrorIs(t, err, ports.ErrNotExistingKey)
+
+		require.False(t, server.Exists("session:"+session.ID.String()))
+		_, err = storage.GetSession(ctx, session.ID)
+		require.ErrorIs(t, err, ports.ErrNotExistingKey)
+	})
+
+	t.Run("test extend does not recreate deleted session", func(t *testing.T) {
+		storage, server := newRedisTokenStorage(t)
+		session := newStoredSession(t, storage)
+
+		err := storage.DelSession(ctx, session.UserID, session.ID)
+		require.NoError(t, err)
+
+		err = storage.ExtendSession(ctx, session, time.Hour)
+		require.ErrorIs(t, err, ports.ErrNotExistingKey)
+
+		require.False(t, server.Exists("session:"+session.ID.String()))
+		sessions, err := storage.GetUserSessions(ctx, session.UserID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+
+	t.Run("test extend restarts expiration", func(t *testing.T) {
+		storage, server := newRedisTokenStorage(t)
+		session := newStoredSession(t, storage)
+		server.FastForward(30 * time.Minute)
+
+		session.RefreshTokenID = uuid.New().String()
+		err := storage.ExtendSession(ctx, session, time.Hour)
+		require.NoError(t, err)
+
+		require.Equal(t, time.Hour, server.TTL("session:"+session.ID.String()))
+		stored, err := storage.GetSession(ctx, session.ID)
+		require.NoError(t, err)
+		require.Equal(t, session.RefreshTokenID, stored.RefreshTokenID)
+	})
+
+	t.Run("test delete drops refresh token", func(t *testing.T) {
+		storage, _ := newRedisTokenStorage(t)
+		session := newStoredSession(t, storage)
+
+		err := storage.DelSession(ctx, session.UserID, session.ID)
+		require.NoError(t, err)
+
+		_, err = storage.Get(ctx, ports.RefreshTokenKey(session.RefreshTokenID))
+		require.ErrorIs(t, err, ports.ErrNotExistingKey)
+
+		err = storage.DelSession(ctx, session.UserID, session.ID)
+		require.ErrorIs(t, err, ports.ErrNotExistingKey)
+	})
+}
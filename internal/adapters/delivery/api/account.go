@@ -0,0 +1,302 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+type AccountHandler struct {
+	router *gin.RouterGroup
+	logger *zap.Logger
+	s      *Services
+}
+
+func NewAccountHandler(router *gin.RouterGroup, logger *zap.Logger, services *Services, authHandler *AuthHandler) *AccountHandler {
+	accountHandler := &AccountHandler{
+		router: router,
+		logger: logger,
+		s:      services,
+	}
+
+	accountGroup := router.Group("/auth")
+	{
+		accountGroup.POST("/email/verify",
+			authHandler.rateLimit("auth", authHandler.rateLimits.Auth, clientIPKey),
+			accountHandler.verifyEmail)
+		accountGroup.POST("/email/verification",
+			authHandler.verifyToken,
+			accountHandler.sendEmailVerification)
+		accountGroup.PUT("/password",
+			authHandler.verifyToken,
+			accountHandler.changePassword)
+		accountGroup.POST("/password/forgot",
+			authHandler.rateLimit("auth", authHandler.rateLimits.Auth, clientIPKey),
+			accountHandler.forgotPassword)
+		accountGroup.POST("/password/reset",
+			authHandler.rateLimit("auth", authHandler.rateLimits.Auth, clientIPKey),
+			accountHandler.resetPassword)
+		accountGroup.POST("/2fa",
+			authHandler.verifyToken,
+			accountHandler.beginTwoFactor)
+		accountGroup.POST("/2fa/confirm",
+			authHandler.verifyToken,
+			accountHandler.enableTwoFactor)
+		accountGroup.POST("/2fa/disable",
+			authHandler.verifyToken,
+			accountHandler.disableTwoFactor)
+	}
+
+	return accountHandler
+}
+
+// @Summary VerifyEmail
+// @Tags auth
+// @Description confirm the account email with the token from the mailed link
+// @Accept  json
+// @Produce json
+// @Param input body dto.VerifyEmailDTO true "token from the link"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/email/verify [post]
+func (h *AccountHandler) verifyEmail(context *gin.Context) {
+	var verifyDTO dto.VerifyEmailDTO
+	err := context.ShouldBindJSON(&verifyDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AccountService.VerifyEmail(context.Request.Context(), verifyDTO.Token)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary SendEmailVerification
+// @Tags auth
+// @Description send the email verification link again
+// @Security ApiKeyAuth
+// @Produce json
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/email/verification [post]
+func (h *AccountHandler) sendEmailVerification(context *gin.Context) {
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AccountService.SendEmailVerification(context.Request.Context(), accountID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary ChangePassword
+// @Tags auth
+// @Description change the password, other sessions of the account are closed
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce json
+// @Param input body dto.ChangePasswordDTO true "old and new password"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/password [put]
+func (h *AccountHandler) changePassword(context *gin.Context) {
+	var changeDTO dto.ChangePasswordDTO
+	err := context.ShouldBindJSON(&changeDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	sessionID, _ := uuid.Parse(context.GetString("SessionID"))
+
+	err = h.s.AccountService.ChangePassword(context.Request.Context(), ports.ChangePasswordRequest{
+		AccountID:   accountID,
+		SessionID:   sessionID,
+		OldPassword: changeDTO.OldPassword,
+		NewPassword: changeDTO.NewPassword,
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary ForgotPassword
+// @Tags auth
+// @Description mail a password reset link; succeeds whether or not the email is known
+// @Accept  json
+// @Produce json
+// @Param input body dto.ForgotPasswordDTO true "account email"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/password/forgot [post]
+func (h *AccountHandler) forgotPassword(context *gin.Context) {
+	var forgotDTO dto.ForgotPasswordDTO
+	err := context.ShouldBindJSON(&forgotDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AccountService.ForgotPassword(context.Request.Context(), forgotDTO.Email)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary ResetPassword
+// @Tags auth
+// @Description set a new password with the token from the mailed link, every session is closed
+// @Accept  json
+// @Produce json
+// @Param input body dto.ResetPasswordDTO true "token from the link and new password"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/password/reset [post]
+func (h *AccountHandler) resetPassword(context *gin.Context) {
+	var resetDTO dto.ResetPasswordDTO
+	err := context.ShouldBindJSON(&resetDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AccountService.ResetPassword(context.Request.Context(), resetDTO.Token, resetDTO.NewPassword)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary BeginTwoFactor
+// @Tags auth
+// @Description start two-factor enrollment; the uri is meant to be shown as a QR code, the recovery codes are shown only once
+// @Security ApiKeyAuth
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.TwoFactorEnrollmentDTO
+// @Router /auth/2fa [post]
+func (h *AccountHandler) beginTwoFactor(context *gin.Context) {
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	enrollment, err := h.s.AccountService.BeginTwoFactor(context.Request.Context(), accountID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.TwoFactorEnrollmentFromDomain(enrollment))
+}
+
+// @Summary EnableTwoFactor
+// @Tags auth
+// @Description confirm two-factor enrollment with a code from the authenticator app
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce json
+// @Param input body dto.TwoFactorCodeDTO true "TOTP code"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/2fa/confirm [post]
+func (h *AccountHandler) enableTwoFactor(context *gin.Context) {
+	var codeDTO dto.TwoFactorCodeDTO
+	err := context.ShouldBindJSON(&codeDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AccountService.EnableTwoFactor(context.Request.Context(), accountID, codeDTO.Code)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary DisableTwoFactor
+// @Tags auth
+// @Description turn two-factor authentication off
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce json
+// @Param input body dto.DisableTwoFactorDTO true "account password"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/2fa/disable [post]
+func (h *AccountHandler) disableTwoFactor(context *gin.Context) {
+	var disableDTO dto.DisableTwoFactorDTO
+	err := context.ShouldBindJSON(&disableDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AccountService.DisableTwoFactor(context.Request.Context(), accountID, disableDTO.Password)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"go.uber.org/zap"
@@ -26,9 +27,34 @@ func NewAlbumHandler(router *gin.RouterGroup,
 	}
 
 	router.PATCH("/albums/:album_id",
+		authHandler.verifyToken,
+		authHandler.verifyAlbumOwner,
+		albumHandler.update)
+	router.DELETE("/albums/:album_id",
+		authHandler.verifyToken,
+		authHandler.verifyAlbumOwner,
+		albumHandler.delete)
+	router.POST("/albums/:album_id/publish",
 		authHandler.verifyToken,
 		authHandler.verifyAlbumOwner,
 		albumHandler.publish)
+	router.POST("/albums/:album_id/unpublish",
+		authHandler.verifyToken,
+		authHandler.verifyAlbumOwner,
+		albumHandler.unpublish)
+	router.PUT("/albums/:album_id/tracks/order",
+		authHandler.verifyToken,
+		authHandler.verifyAlbumOwner,
+		albumHandler.reorderTracks)
+
+	router.PUT("/albums/:album_id/schedule",
+		authHandler.verifyToken,
+		authHandler.verifyAlbumOwner,
+		albumHandler.schedule)
+	router.DELETE("/albums/:album_id/schedule",
+		authHandler.verifyToken,
+		authHandler.verifyAlbumOwner,
+		albumHandler.cancelSchedule)
 
 	router.GET("/albums/", albumHandler.getAll)
 	router.GET("/albums/:album_id", albumHandler.getByID)
@@ -48,6 +74,7 @@ func NewAlbumHandler(router *gin.RouterGroup,
 	router.PUT("/albums/:album_id/image",
 		authHandler.verifyToken,
 		authHandler.verifyMusicianRole,
+		authHandler.rateLimit("upload", authHandler.rateLimits.Upload, accountKey),
 		albumHandler.uploadImage)
 
 	return albumHandler
@@ -239,7 +266,7 @@ func (h *AlbumHandler) getByID(context *gin.Context) {
 // @Failure 404 {object} RestErrorNotFound
 // @Failure 500 {object} RestErrorInternalError
 // @Success 200
-// @Router /albums/{id} [patch]
+// @Router /albums/{id}/publish [post]
 func (h *AlbumHandler) publish(context *gin.Context) {
 	id, err := getIdFromPath(context, "album_id")
 	if err != nil {
@@ -256,6 +283,232 @@ func (h *AlbumHandler) publish(context *gin.Context) {
 	successResponse(context, struct{}{})
 }
 
+// @Summary UnpublishAlbum
+// @Tags album
+// @Security ApiKeyAuth
+// @Description unpublish album with a reason
+// @Accept  json
+// @Produce json
+// @Param id   path    string  true  "album id"
+// @Param input body dto.UnpublishAlbumDTO true "unpublish reason"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200
+// @Router /albums/{id}/unpublish [post]
+func (h *AlbumHandler) unpublish(context *gin.Context) {
+	var unpublishAlbumDTO dto.UnpublishAlbumDTO
+	err := context.ShouldBindJSON(&unpublishAlbumDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	id, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AlbumService.Unpublish(context.Request.Context(), id, unpublishAlbumDTO.Reason)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary UpdateAlbum
+// @Tags album
+// @Security ApiKeyAuth
+// @Description edit album info
+// @Accept  json
+// @Produce json
+// @Param id   path    string  true  "album id"
+// @Param input body dto.UpdateAlbumDTO true "album info"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.AlbumDTO
+// @Router /albums/{id} [patch]
+func (h *AlbumHandler) update(context *gin.Context) {
+	var updateAlbumDTO dto.UpdateAlbumDTO
+	err := context.ShouldBindJSON(&updateAlbumDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	id, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	album, err := h.s.AlbumService.Update(context.Request.Context(), ports.UpdateAlbumServiceReq{
+		AlbumID:     id,
+		MusicianID:  musicianID,
+		Name:        null.StringFromPtr(updateAlbumDTO.Name),
+		Description: null.StringFromPtr(updateAlbumDTO.Description),
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.AlbumFromDomain(album))
+}
+
+// @Summary DeleteAlbum
+// @Tags album
+// @Security ApiKeyAuth
+// @Description delete album with its tracks
+// @Accept  json
+// @Produce json
+// @Param id   path    string  true  "album id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.AlbumDTO
+// @Router /albums/{id} [delete]
+func (h *AlbumHandler) delete(context *gin.Context) {
+	id, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	album, err := h.s.AlbumService.Delete(context.Request.Context(), id)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.AlbumFromDomain(album))
+}
+
+// @Summary ReorderAlbumTracks
+// @Tags album
+// @Security ApiKeyAuth
+// @Description set disc and track numbers of album tracks
+// @Accept  json
+// @Produce json
+// @Param id   path    string  true  "album id"
+// @Param input body dto.ReorderTracksDTO true "track positions"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200
+// @Router /albums/{id}/tracks/order [put]
+func (h *AlbumHandler) reorderTracks(context *gin.Context) {
+	var reorderTracksDTO dto.ReorderTracksDTO
+	err := context.ShouldBindJSON(&reorderTracksDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	id, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.TrackService.Reorder(context.Request.Context(), id, reorderTracksDTO.ToDomain())
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary ScheduleAlbum
+// @Tags album
+// @Security ApiKeyAuth
+// @Description schedule album release
+// @Accept  json
+// @Produce json
+// @Param id   path    string  true  "album id"
+// @Param input body dto.ScheduleAlbumDTO true "release time"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200
+// @Router /albums/{id}/schedule [put]
+func (h *AlbumHandler) schedule(context *gin.Context) {
+	var scheduleAlbumDTO dto.ScheduleAlbumDTO
+	err := context.ShouldBindJSON(&scheduleAlbumDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	id, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AlbumService.Schedule(context.Request.Context(), id, scheduleAlbumDTO.ReleaseAt)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary CancelAlbumSchedule
+// @Tags album
+// @Security ApiKeyAuth
+// @Description cancel scheduled album release
+// @Accept  json
+// @Produce json
+// @Param id   path    string  true  "album id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200
+// @Router /albums/{id}/schedule [delete]
+func (h *AlbumHandler) cancelSchedule(context *gin.Context) {
+	id, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AlbumService.CancelSchedule(context.Request.Context(), id)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
 // @Summary GetAlbumByMusicianID
 // @Tags album
 // @Description get album by musician id
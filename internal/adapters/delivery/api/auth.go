@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
@@ -11,25 +12,45 @@ import (
 )
 
 type AuthHandler struct {
-	router *gin.RouterGroup
-	logger *zap.Logger
-	s      *Services
+	router     *gin.RouterGroup
+	logger     *zap.Logger
+	s          *Services
+	rateLimits RateLimits
 }
 
-func NewAuthHandler(router *gin.RouterGroup, logger *zap.Logger, services *Services) *AuthHandler {
+func NewAuthHandler(router *gin.RouterGroup, logger *zap.Logger, services *Services, rateLimits RateLimits) *AuthHandler {
 	authHandler := &AuthHandler{
-		router: router,
-		logger: logger,
-		s:      services,
+		router:     router,
+		logger:     logger,
+		s:          services,
+		rateLimits: rateLimits,
 	}
 
 	authGroup := router.Group("/auth")
 	{
-		authGroup.POST("/login", authHandler.login)
+		authGroup.POST("/login",
+			authHandler.rateLimit("auth", rateLimits.Auth, clientIPKey),
+			authHandler.rateLimit("login", rateLimits.Login, loginNameKey),
+			authHandler.loginLockout,
+			authHandler.login)
+		authGroup.POST("/login/2fa",
+			authHandler.rateLimit("auth", rateLimits.Auth, clientIPKey),
+			authHandler.loginTwoFactor)
 		authGroup.POST("/logout",
 			authHandler.verifyToken,
 			authHandler.logout)
-		authGroup.POST("/refresh", authHandler.refresh)
+		authGroup.POST("/refresh",
+			authHandler.rateLimit("auth", rateLimits.Auth, clientIPKey),
+			authHandler.refresh)
+		authGroup.GET("/sessions",
+			authHandler.verifyToken,
+			authHandler.getSessions)
+		authGroup.DELETE("/sessions/:session_id",
+			authHandler.verifyToken,
+			authHandler.revokeSession)
+		authGroup.DELETE("/sessions",
+			authHandler.verifyToken,
+			authHandler.logoutEverywhere)
 	}
 
 	return authHandler
@@ -65,6 +86,107 @@ func (h *AuthHandler) refresh(context *gin.Context) {
 	successResponse(context, response)
 }
 
+// @Summary GetSessions
+// @Tags auth
+// @Description list active sessions of the current account
+// @Security ApiKeyAuth
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.SessionDTO
+// @Router /auth/sessions [get]
+func (h *AuthHandler) getSessions(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	sessions, err := h.s.AuthService.GetSessions(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	currentSessionID := context.GetString("SessionID")
+	sessionDTOs := make([]dto.SessionDTO, len(sessions))
+	for i, session := range sessions {
+		sessionDTOs[i] = dto.SessionFromDomain(session, session.ID.String() == currentSessionID)
+	}
+
+	successResponse(context, sessionDTOs)
+}
+
+// @Summary RevokeSession
+// @Tags auth
+// @Description log out the session on another device
+// @Security ApiKeyAuth
+// @Produce json
+// @Param   session_id   path    string  true  "session id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/sessions/{session_id} [delete]
+func (h *AuthHandler) revokeSession(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	sessionID, err := getIdFromPath(context, "session_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AuthService.RevokeSession(context.Request.Context(), userID, sessionID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary LogOutEverywhere
+// @Tags auth
+// @Description close all sessions of the current account, including this one
+// @Security ApiKeyAuth
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/sessions [delete]
+func (h *AuthHandler) logoutEverywhere(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AuthService.LogOutEverywhere(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary JWKS
+// @Tags auth
+// @Description public keys that verify access tokens, served at /.well-known/jwks.json
+// @Produce json
+// @Success 200 {object} dto.JSONWebKeySetDTO
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) jwks(context *gin.Context) {
+	keys := h.s.AuthService.GetPublicKeys(context.Request.Context())
+	successResponse(context, dto.JSONWebKeySetFromDomain(keys))
+}
+
 // @Summary LogIn
 // @Tags auth
 // @Description login
@@ -73,22 +195,68 @@ func (h *AuthHandler) refresh(context *gin.Context) {
 // @Param input body dto.LoginDTO true "credentials"
 // @Failure 400 {object} RestErrorBadRequest
 // @Failure 401 {object} RestErrorUnauthorized
+// @Failure 429 {object} RestErrorTooManyRequests
 // @Failure 500 {object} RestErrorInternalError
-// @Success 200 {object} []dto.LoginResponseDTO
+// @Success 200 {object} dto.LoginResultDTO
 // @Router /auth/login [post]
 func (h *AuthHandler) login(context *gin.Context) {
 	var loginDTO dto.LoginDTO
-	err := context.ShouldBindJSON(&loginDTO)
+	err := context.ShouldBindBodyWith(&loginDTO, binding.JSON)
 	if err != nil {
 		errorResponse(context, err)
 		return
 	}
 
-	tokenPair, err := h.s.AuthService.LogIn(
+	result, err := h.s.AuthService.LogIn(
 		context.Request.Context(),
 		ports.LogInCredentials{
 			Name:     loginDTO.Name,
 			Password: loginDTO.Password,
+			Metadata: domain.SessionMetadata{
+				DeviceLabel: loginDTO.Device,
+				UserAgent:   context.Request.UserAgent(),
+				IP:          context.ClientIP(),
+			},
+		},
+	)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	response := dto.LoginResultFromDomain(result)
+	successResponse(context, response)
+}
+
+// @Summary LogInTwoFactor
+// @Tags auth
+// @Description complete the login of an account with two-factor authentication
+// @Accept  json
+// @Produce json
+// @Param input body dto.TwoFactorLoginDTO true "challenge token from login and a TOTP or recovery code"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.LoginResponseDTO
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) loginTwoFactor(context *gin.Context) {
+	var loginDTO dto.TwoFactorLoginDTO
+	err := context.ShouldBindJSON(&loginDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	tokenPair, err := h.s.AuthService.LogInTwoFactor(
+		context.Request.Context(),
+		ports.TwoFactorCredentials{
+			ChallengeToken: loginDTO.ChallengeToken,
+			Code:           loginDTO.Code,
+			Metadata: domain.SessionMetadata{
+				DeviceLabel: loginDTO.Device,
+				UserAgent:   context.Request.UserAgent(),
+				IP:          context.ClientIP(),
+			},
 		},
 	)
 	if err != nil {
@@ -147,12 +315,44 @@ func (h *AuthHandler) verifyUserRole(context *gin.Context) {
 		return
 	}
 
-	if role != domain.UserRole {
+	if !domain.IsUserAccountRole(role) {
 		errorResponse(context, ForbiddenError)
 		return
 	}
 }
 
+// requirePermission is the single place where the role policy is enforced,
+// so routes declare what they need instead of which roles may call them.
+func (h *AuthHandler) requirePermission(permission domain.Permission) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		role, err := getRoleFromRequestContext(context)
+		if err != nil {
+			errorResponse(context, err)
+			return
+		}
+
+		if !domain.HasPermission(role, permission) {
+			errorResponse(context, ForbiddenError)
+			return
+		}
+	}
+}
+
+// requireSubscription gates premium features, it has to run after verifyToken.
+func (h *AuthHandler) requireSubscription(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.SubscriptionService.CheckActive(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+}
+
 func (h *AuthHandler) verifyMusicianID(context *gin.Context) {
 	id, err := getIdFromRequestContext(context)
 	if err != nil {
@@ -239,7 +439,7 @@ func (h *AuthHandler) verifyTrackOwner(context *gin.Context) {
 		errorResponse(context, UnauthorizedError)
 	}
 
-	tracks, err := h.s.TrackService.GetOwn(context.Request.Context(), userID)
+	tracks, err := h.s.TrackService.GetOwn(context.Request.Context(), userID, domain.TrackSortDefault)
 	if err != nil {
 		errorResponse(context, err)
 		return
@@ -289,6 +489,7 @@ func (h *AuthHandler) verifyToken(context *gin.Context) {
 
 	context.Set("UserID", payload.UserID.String())
 	context.Set("UserRole", payload.Role)
+	context.Set("SessionID", payload.SessionID.String())
 }
 
 func (h *AuthHandler) extractAuthToken(context *gin.Context) (string, error) {
@@ -2,13 +2,19 @@ package api
 
 import (
 	"log"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"go.uber.org/zap"
 )
 
+// totalCountHeader carries the number of items of a paginated list.
+const totalCountHeader = "X-Total-Count"
+
 type CommentHandler struct {
 	router      *gin.RouterGroup
 	logger      *zap.Logger
@@ -31,9 +37,33 @@ func NewCommentHandler(router *gin.RouterGroup,
 	router.POST("/tracks/:track_id/comments",
 		authHandler.verifyToken,
 		authHandler.verifyUserRole,
+		authHandler.rateLimit("comment", authHandler.rateLimits.Comment, accountKey),
 		commentHandler.post)
 	router.GET("/tracks/:track_id/comments",
 		commentHandler.getOnTrack)
+	router.POST("/comments/:comment_id/replies",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		authHandler.rateLimit("comment", authHandler.rateLimits.Comment, accountKey),
+		commentHandler.reply)
+	router.PATCH("/comments/:comment_id",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		authHandler.rateLimit("comment", authHandler.rateLimits.Comment, accountKey),
+		commentHandler.edit)
+	router.DELETE("/comments/:comment_id",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		commentHandler.deleteByID)
+	router.PUT("/comments/:comment_id/vote",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		authHandler.rateLimit("comment", authHandler.rateLimits.Comment, accountKey),
+		commentHandler.vote)
+	router.DELETE("/comments/:comment_id/vote",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		commentHandler.deleteVote)
 	router.GET("/users/me/comments",
 		authHandler.verifyToken,
 		commentHandler.getUsers)
@@ -41,6 +71,43 @@ func NewCommentHandler(router *gin.RouterGroup,
 		authHandler.verifyToken,
 		authHandler.verifyUserRole,
 		commentHandler.delete)
+	router.DELETE("/tracks/:track_id/comments/:user_id",
+		authHandler.verifyToken,
+		authHandler.requirePermission(domain.PermissionCommentModerate),
+		commentHandler.moderate)
+	router.POST("/comments/:comment_id/reports",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		authHandler.rateLimit("comment", authHandler.rateLimits.Comment, accountKey),
+		commentHandler.report)
+	router.GET("/moderation/comments",
+		authHandler.verifyToken,
+		authHandler.requirePermission(domain.PermissionCommentModerate),
+		commentHandler.getModerationQueue)
+	router.POST("/moderation/comments/:comment_id/hide",
+		authHandler.verifyToken,
+		authHandler.requirePermission(domain.PermissionCommentModerate),
+		commentHandler.hide)
+	router.POST("/moderation/comments/:comment_id/restore",
+		authHandler.verifyToken,
+		authHandler.requirePermission(domain.PermissionCommentModerate),
+		commentHandler.restore)
+	router.POST("/musicians/me/comments/:comment_id/hide",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		commentHandler.hideOnOwnTrack)
+	router.POST("/musicians/me/comments/:comment_id/restore",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		commentHandler.restoreOnOwnTrack)
+	router.PUT("/musicians/me/comments/:comment_id/pin",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		commentHandler.pin)
+	router.DELETE("/musicians/me/comments/:comment_id/pin",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		commentHandler.unpin)
 
 	return commentHandler
 }
@@ -83,10 +150,47 @@ func (h *CommentHandler) delete(context *gin.Context) {
 	successResponse(context, commentDTO)
 }
 
+// @Summary ModerateComment
+// @Tags comment
+// @Description remove another user's comment on track
+// @Security ApiKeyAuth
+// @Produce json
+// @Param   track_id   path    string  true  "track id"
+// @Param   user_id   path    string  true  "comment author id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /tracks/{track_id}/comments/{user_id} [delete]
+func (h *CommentHandler) moderate(context *gin.Context) {
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	userID, err := getIdFromPath(context, "user_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.Delete(context.Request.Context(), userID, trackID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentDTO := dto.CommentFromDomain(comment)
+	successResponse(context, commentDTO)
+}
+
 // @Summary PostComment
 // @Tags comment
 // @Security ApiKeyAuth
-// @Description post comment
+// @Description post the review of the track, replacing the one already posted
 // @Accept  json
 // @Produce json
 // @Param id   path    string  true  "track id"
@@ -136,16 +240,156 @@ func (h *CommentHandler) post(context *gin.Context) {
 	successResponse(context, commentDTO)
 }
 
+// @Summary ReplyComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description reply to a comment, replies to a reply join the thread of its review
+// @Accept  json
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Param input body dto.ReplyCommentDTO true "reply"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {object} dto.CommentDTO
+// @Router /comments/{comment_id}/replies [post]
+func (h *CommentHandler) reply(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var replyDTO dto.ReplyCommentDTO
+	err = context.ShouldBindJSON(&replyDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	reply, err := h.s.CommentService.Reply(context.Request.Context(), ports.ReplyCommentServiceReq{
+		UserID:   userID,
+		ParentID: commentID,
+		Text:     replyDTO.Text,
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, dto.CommentFromDomain(reply))
+}
+
+// @Summary EditComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description edit text or stars of an own comment, replies have no stars
+// @Accept  json
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Param input body dto.EditCommentDTO true "changed fields"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /comments/{comment_id} [patch]
+func (h *CommentHandler) edit(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var editDTO dto.EditCommentDTO
+	err = context.ShouldBindJSON(&editDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var stars null.Int
+	if editDTO.Stars != nil {
+		stars = null.IntFrom(int64(*editDTO.Stars))
+	}
+
+	comment, err := h.s.CommentService.Edit(context.Request.Context(), ports.EditCommentServiceReq{
+		UserID:    userID,
+		CommentID: commentID,
+		Stars:     stars,
+		Text:      null.StringFromPtr(editDTO.Text),
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary DeleteCommentByID
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description delete an own review or reply, a review is deleted with its replies
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /comments/{comment_id} [delete]
+func (h *CommentHandler) deleteByID(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.DeleteOwn(context.Request.Context(), userID, commentID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
 // @Summary GetByTrackID
 // @Tags comment
-// @Description get comments by track id
+// @Description get a page of the reviews on the track with their replies
 // @Accept  json
 // @Produce json
 // @Param   id   path    string  true  "track id"
+// @Param   sort   query   string  false  "newest (default), stars or helpful"
+// @Param   limit  query   int     false  "page size, 20 by default, at most 100"
+// @Param   offset query   int     false  "number of reviews to skip"
 // @Failure 400 {object} RestErrorBadRequest
 // @Failure 404 {object} RestErrorNotFound
 // @Failure 500 {object} RestErrorInternalError
-// @Success 200 {object} []dto.TrackDTO
+// @Success 200 {object} []dto.CommentDTO
+// @Header  200 {integer} X-Total-Count "number of reviews on the track"
 // @Router /tracks/{id}/comments [get]
 func (h *CommentHandler) getOnTrack(context *gin.Context) {
 	trackID, err := getIdFromPath(context, "track_id")
@@ -154,7 +398,15 @@ func (h *CommentHandler) getOnTrack(context *gin.Context) {
 		return
 	}
 
-	comments, err := h.s.CommentService.GetCommentsOnTrack(context.Request.Context(), trackID)
+	var pageDTO dto.CommentPageQueryDTO
+	err = context.ShouldBindQuery(&pageDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comments, total, err := h.s.CommentService.GetCommentsOnTrack(context.Request.Context(), trackID,
+		pageDTO.ToDomain())
 	if err != nil {
 		errorResponse(context, err)
 		return
@@ -165,6 +417,7 @@ func (h *CommentHandler) getOnTrack(context *gin.Context) {
 		commentDTOs[i] = dto.CommentFromDomain(comments[i])
 	}
 
+	context.Header(totalCountHeader, strconv.Itoa(total))
 	successResponse(context, commentDTOs)
 }
 
@@ -199,3 +452,349 @@ func (h *CommentHandler) getUsers(context *gin.Context) {
 
 	successResponse(context, commentDTOs)
 }
+
+// @Summary ReportComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description report an abusive comment to the moderators
+// @Accept  json
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Param input body dto.ReportCommentDTO true "report reason"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {object} dto.CommentReportDTO
+// @Router /comments/{comment_id}/reports [post]
+func (h *CommentHandler) report(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var reportDTO dto.ReportCommentDTO
+	err = context.ShouldBindJSON(&reportDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	report, err := h.s.CommentService.Report(context.Request.Context(), ports.ReportCommentServiceReq{
+		UserID:    userID,
+		CommentID: commentID,
+		Reason:    reportDTO.Reason,
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, dto.CommentReportFromDomain(report))
+}
+
+// @Summary GetModerationQueue
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description get a page of the reported comments, most reported first
+// @Produce json
+// @Param   limit  query   int     false  "page size, 20 by default, at most 100"
+// @Param   offset query   int     false  "number of comments to skip"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.ReportedCommentDTO
+// @Header  200 {integer} X-Total-Count "number of reported comments"
+// @Router /moderation/comments [get]
+func (h *CommentHandler) getModerationQueue(context *gin.Context) {
+	var pageDTO dto.ModerationQueueQueryDTO
+	err := context.ShouldBindQuery(&pageDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	queue, total, err := h.s.CommentService.GetModerationQueue(context.Request.Context(), pageDTO.ToDomain())
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	queueDTOs := make([]dto.ReportedCommentDTO, len(queue))
+	for i := range queue {
+		queueDTOs[i] = dto.ReportedCommentFromDomain(queue[i])
+	}
+
+	context.Header(totalCountHeader, strconv.Itoa(total))
+	successResponse(context, queueDTOs)
+}
+
+// @Summary HideComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description hide a comment from the track comments and resolve its reports
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /moderation/comments/{comment_id}/hide [post]
+func (h *CommentHandler) hide(context *gin.Context) {
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.Hide(context.Request.Context(), commentID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary RestoreComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description show a hidden comment again and dismiss its reports
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /moderation/comments/{comment_id}/restore [post]
+func (h *CommentHandler) restore(context *gin.Context) {
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.Restore(context.Request.Context(), commentID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary HideCommentOnOwnTrack
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description hide a comment on a track of the musician
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /musicians/me/comments/{comment_id}/hide [post]
+func (h *CommentHandler) hideOnOwnTrack(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.HideOnOwnTrack(context.Request.Context(), musicianID, commentID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary RestoreCommentOnOwnTrack
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description show a comment the musician hid on their track again
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /musicians/me/comments/{comment_id}/restore [post]
+func (h *CommentHandler) restoreOnOwnTrack(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.RestoreOnOwnTrack(context.Request.Context(), musicianID, commentID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary PinComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description pin a review on a track of the musician above the other comments
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /musicians/me/comments/{comment_id}/pin [put]
+func (h *CommentHandler) pin(context *gin.Context) {
+	h.setPinned(context, true)
+}
+
+// @Summary UnpinComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description unpin a review on a track of the musician
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /musicians/me/comments/{comment_id}/pin [delete]
+func (h *CommentHandler) unpin(context *gin.Context) {
+	h.setPinned(context, false)
+}
+
+func (h *CommentHandler) setPinned(context *gin.Context, pinned bool) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.Pin(context.Request.Context(), musicianID, commentID, pinned)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary VoteComment
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description mark a comment of another user helpful or unhelpful, replacing the previous vote
+// @Accept  json
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Param input body dto.VoteCommentDTO true "vote"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /comments/{comment_id}/vote [put]
+func (h *CommentHandler) vote(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var voteDTO dto.VoteCommentDTO
+	err = context.ShouldBindJSON(&voteDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.Vote(context.Request.Context(), userID, commentID, *voteDTO.Helpful)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
+
+// @Summary DeleteCommentVote
+// @Tags comment
+// @Security ApiKeyAuth
+// @Description take back the vote on a comment
+// @Produce json
+// @Param   comment_id   path    string  true  "comment id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.CommentDTO
+// @Router /comments/{comment_id}/vote [delete]
+func (h *CommentHandler) deleteVote(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	commentID, err := getIdFromPath(context, "comment_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	comment, err := h.s.CommentService.DeleteVote(context.Request.Context(), userID, commentID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.CommentFromDomain(comment))
+}
@@ -0,0 +1,394 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"go.uber.org/zap"
+)
+
+type CreditHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewCreditHandler(router *gin.RouterGroup,
+	logger *zap.Logger,
+	services *Services,
+	authHandler *AuthHandler,
+) *CreditHandler {
+	creditHandler := &CreditHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	router.GET("/albums/:album_id/contributors", creditHandler.getAlbumContributors)
+	router.POST("/albums/:album_id/contributors",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		authHandler.verifyAlbumOwner,
+		creditHandler.invite)
+	router.DELETE("/albums/:album_id/contributors/:musician_id",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		authHandler.verifyAlbumOwner,
+		creditHandler.removeContributor)
+
+	router.GET("/musicians/me/invites",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		creditHandler.getInvites)
+	router.POST("/musicians/me/invites/:album_id",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		creditHandler.acceptInvite)
+	router.DELETE("/musicians/me/invites/:album_id",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		creditHandler.declineInvite)
+
+	router.GET("/tracks/:track_id/credits", creditHandler.getTrackCredits)
+	router.POST("/tracks/:track_id/credits",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		authHandler.verifyTrackOwner,
+		creditHandler.addTrackCredit)
+	router.DELETE("/tracks/:track_id/credits/:musician_id/:role",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		authHandler.verifyTrackOwner,
+		creditHandler.removeTrackCredit)
+
+	return creditHandler
+}
+
+// @Summary GetAlbumContributors
+// @Tags credit
+// @Description get all musicians who contributed to the album
+// @Accept  json
+// @Produce json
+// @Param   album_id   path    string  true  "album id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.AlbumContributorDTO
+// @Router /albums/{album_id}/contributors [get]
+func (h *CreditHandler) getAlbumContributors(context *gin.Context) {
+	albumID, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	contributors, err := h.s.CreditService.GetAlbumContributors(context.Request.Context(), albumID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	contributorDTOs := make([]dto.AlbumContributorDTO, len(contributors))
+	for i := range contributors {
+		contributorDTOs[i] = dto.AlbumContributorFromDomain(contributors[i])
+	}
+
+	successResponse(context, contributorDTOs)
+}
+
+// @Summary InviteContributor
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description invite musician to the album as co-artist
+// @Accept  json
+// @Produce json
+// @Param   album_id   path    string  true  "album id"
+// @Param input body dto.InviteContributorDTO true "invited musician"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {string} string ""
+// @Router /albums/{album_id}/contributors [post]
+func (h *CreditHandler) invite(context *gin.Context) {
+	var inviteDTO dto.InviteContributorDTO
+	err := context.ShouldBindJSON(&inviteDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	albumID, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	ownerID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.CreditService.InviteToAlbum(context.Request.Context(), albumID, ownerID, inviteDTO.MusicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, struct{}{})
+}
+
+// @Summary RemoveContributor
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description remove co-artist or pending invite from the album
+// @Accept  json
+// @Produce json
+// @Param   album_id   path    string  true  "album id"
+// @Param   musician_id   path    string  true  "musician id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /albums/{album_id}/contributors/{musician_id} [delete]
+func (h *CreditHandler) removeContributor(context *gin.Context) {
+	albumID, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromPath(context, "musician_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.CreditService.RemoveContributor(context.Request.Context(), albumID, musicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary GetInvites
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description get albums musician is invited to
+// @Accept  json
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.AlbumDTO
+// @Router /musicians/me/invites [get]
+func (h *CreditHandler) getInvites(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	albums, err := h.s.CreditService.GetInvites(context.Request.Context(), musicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	albumDTOs := make([]dto.AlbumDTO, len(albums))
+	for i := range albums {
+		albumDTOs[i] = dto.AlbumFromDomain(albums[i])
+	}
+
+	successResponse(context, albumDTOs)
+}
+
+// @Summary AcceptInvite
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description accept invite to the album
+// @Accept  json
+// @Produce json
+// @Param   album_id   path    string  true  "album id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /musicians/me/invites/{album_id} [post]
+func (h *CreditHandler) acceptInvite(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	albumID, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.CreditService.AcceptInvite(context.Request.Context(), albumID, musicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary DeclineInvite
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description decline invite to the album
+// @Accept  json
+// @Produce json
+// @Param   album_id   path    string  true  "album id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /musicians/me/invites/{album_id} [delete]
+func (h *CreditHandler) declineInvite(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	albumID, err := getIdFromPath(context, "album_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.CreditService.DeclineInvite(context.Request.Context(), albumID, musicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary GetTrackCredits
+// @Tags credit
+// @Description get all musicians credited on the track
+// @Accept  json
+// @Produce json
+// @Param   track_id   path    string  true  "track id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.TrackCreditDTO
+// @Router /tracks/{track_id}/credits [get]
+func (h *CreditHandler) getTrackCredits(context *gin.Context) {
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	credits, err := h.s.CreditService.GetTrackCredits(context.Request.Context(), trackID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	creditDTOs := make([]dto.TrackCreditDTO, len(credits))
+	for i := range credits {
+		creditDTOs[i] = dto.TrackCreditFromDomain(credits[i])
+	}
+
+	successResponse(context, creditDTOs)
+}
+
+// @Summary AddTrackCredit
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description credit musician on the track
+// @Accept  json
+// @Produce json
+// @Param   track_id   path    string  true  "track id"
+// @Param input body dto.AddTrackCreditDTO true "credit"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {string} string ""
+// @Router /tracks/{track_id}/credits [post]
+func (h *CreditHandler) addTrackCredit(context *gin.Context) {
+	var creditDTO dto.AddTrackCreditDTO
+	err := context.ShouldBindJSON(&creditDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.CreditService.AddTrackCredit(context.Request.Context(), trackID, creditDTO.MusicianID,
+		domain.CreditRole(creditDTO.Role))
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, struct{}{})
+}
+
+// @Summary RemoveTrackCredit
+// @Tags credit
+// @Security ApiKeyAuth
+// @Description remove musician credit from the track
+// @Accept  json
+// @Produce json
+// @Param   track_id   path    string  true  "track id"
+// @Param   musician_id   path    string  true  "musician id"
+// @Param   role   path    string  true  "credit role"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /tracks/{track_id}/credits/{musician_id}/{role} [delete]
+func (h *CreditHandler) removeTrackCredit(context *gin.Context) {
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromPath(context, "musician_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.CreditService.RemoveTrackCredit(context.Request.Context(), trackID, musicianID,
+		domain.CreditRole(context.Param("role")))
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
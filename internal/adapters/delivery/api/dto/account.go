@@ -0,0 +1,43 @@
+package dto
+
+import "github.com/hanoys/sigma-music/internal/domain"
+
+type VerifyEmailDTO struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ChangePasswordDTO struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type ForgotPasswordDTO struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordDTO struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type TwoFactorEnrollmentDTO struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func TwoFactorEnrollmentFromDomain(enrollment domain.TwoFactorEnrollment) TwoFactorEnrollmentDTO {
+	return TwoFactorEnrollmentDTO{
+		Secret:        enrollment.Secret,
+		URI:           enrollment.URI,
+		RecoveryCodes: enrollment.RecoveryCodes,
+	}
+}
+
+type TwoFactorCodeDTO struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorDTO struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -1,26 +1,33 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
 type AlbumDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Published   bool      `json:"published"`
-	ReleaseDate string    `json:"release_date"`
-	ImageURL    string    `json:"image_url"`
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Published        bool      `json:"published"`
+	ReleaseDate      string    `json:"release_date"`
+	ImageURL         string    `json:"image_url"`
+	ScheduledRelease string    `json:"scheduled_release,omitempty"`
+	UnpublishReason  string    `json:"unpublish_reason,omitempty"`
+	Rating           RatingDTO `json:"rating"`
 }
 
 func AlbumFromDomain(album domain.Album) AlbumDTO {
 	albumDTO := AlbumDTO{
-		ID:          album.ID,
-		Name:        album.Name,
-		Description: album.Description,
-		Published:   album.Published,
-		ImageURL:    album.ImageURL.ValueOrZero(),
+		ID:              album.ID,
+		Name:            album.Name,
+		Description:     album.Description,
+		Published:       album.Published,
+		ImageURL:        album.ImageURL.ValueOrZero(),
+		UnpublishReason: album.UnpublishReason.ValueOrZero(),
+		Rating:          RatingFromDomain(album.Rating),
 	}
 
 	if album.ReleaseDate.IsZero() {
@@ -29,6 +36,10 @@ func AlbumFromDomain(album domain.Album) AlbumDTO {
 		albumDTO.ReleaseDate = album.ReleaseDate.Time.String()
 	}
 
+	if album.ScheduledRelease.Valid {
+		albumDTO.ScheduledRelease = album.ScheduledRelease.Time.String()
+	}
+
 	return albumDTO
 }
 
@@ -36,3 +47,16 @@ type CreateAlbumDTO struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description" binding:"required"`
 }
+
+type ScheduleAlbumDTO struct {
+	ReleaseAt time.Time `json:"release_at" binding:"required"`
+}
+
+type UpdateAlbumDTO struct {
+	Name        *string `json:"name" binding:"omitempty,min=1"`
+	Description *string `json:"description"`
+}
+
+type UnpublishAlbumDTO struct {
+	Reason string `json:"reason" binding:"required"`
+}
@@ -1,13 +1,18 @@
 package dto
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 )
 
 type LoginDTO struct {
+	// Name is either the account name or its email.
 	Name     string `json:"name" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Device   string `json:"device" binding:"omitempty,max=64"`
 }
 
 func (l *LoginDTO) ToServiceRequest() ports.LogInCredentials {
@@ -29,6 +34,31 @@ func LoginResponseFromTokenPair(pair domain.TokenPair) LoginResponseDTO {
 	}
 }
 
+// LoginResultDTO carries the tokens, or only the challenge token when the
+// account has two-factor authentication enabled.
+type LoginResultDTO struct {
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+func LoginResultFromDomain(result domain.LoginResult) LoginResultDTO {
+	return LoginResultDTO{
+		AccessToken:       result.Tokens.AccessToken,
+		RefreshToken:      result.Tokens.RefreshToken,
+		TwoFactorRequired: result.ChallengeRequired(),
+		ChallengeToken:    result.ChallengeToken,
+	}
+}
+
+type TwoFactorLoginDTO struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Code is either a TOTP code or a recovery code.
+	Code   string `json:"code" binding:"required"`
+	Device string `json:"device" binding:"omitempty,max=64"`
+}
+
 type LogoutDTO struct {
 	AccessToken string `json:"access_token" binding:"required"`
 }
@@ -36,3 +66,58 @@ type LogoutDTO struct {
 type RefreshDTO struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+type SessionDTO struct {
+	ID          uuid.UUID `json:"id"`
+	DeviceLabel string    `json:"device,omitempty"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	IP          string    `json:"ip,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	Current     bool      `json:"current"`
+}
+
+func SessionFromDomain(session domain.Session, current bool) SessionDTO {
+	return SessionDTO{
+		ID:          session.ID,
+		DeviceLabel: session.Metadata.DeviceLabel,
+		UserAgent:   session.Metadata.UserAgent,
+		IP:          session.Metadata.IP,
+		CreatedAt:   session.CreatedAt,
+		LastUsedAt:  session.LastUsedAt,
+		Current:     current,
+	}
+}
+
+type JSONWebKeyDTO struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JSONWebKeySetDTO struct {
+	Keys []JSONWebKeyDTO `json:"keys"`
+}
+
+func JSONWebKeySetFromDomain(keys []domain.JSONWebKey) JSONWebKeySetDTO {
+	keyDTOs := make([]JSONWebKeyDTO, len(keys))
+	for i, key := range keys {
+		keyDTOs[i] = JSONWebKeyDTO{
+			KeyType:   key.KeyType,
+			KeyID:     key.KeyID,
+			Use:       key.Use,
+			Algorithm: key.Algorithm,
+			Modulus:   key.Modulus,
+			Exponent:  key.Exponent,
+			Curve:     key.Curve,
+			X:         key.X,
+		}
+	}
+
+	return JSONWebKeySetDTO{Keys: keyDTOs}
+}
@@ -1,29 +1,133 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
 type CommentDTO struct {
-	ID      uuid.UUID `json:"id"`
-	UserID  uuid.UUID `json:"user_id"`
-	TrackID uuid.UUID `json:"track_id"`
-	Stars   int       `json:"stars"`
-	Text    string    `json:"text"`
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	TrackID   uuid.UUID    `json:"track_id"`
+	ParentID  *uuid.UUID   `json:"parent_id,omitempty"`
+	Stars     int          `json:"stars,omitempty"`
+	Text      string       `json:"text"`
+	CreatedAt time.Time    `json:"created_at"`
+	EditedAt  *time.Time   `json:"edited_at,omitempty"`
+	Pinned    bool         `json:"pinned"`
+	Hidden    bool         `json:"hidden"`
+	HiddenBy  string       `json:"hidden_by,omitempty"`
+	Helpful   int          `json:"helpful_votes"`
+	Unhelpful int          `json:"unhelpful_votes"`
+	Replies   []CommentDTO `json:"replies,omitempty"`
 }
 
 func CommentFromDomain(comment domain.Comment) CommentDTO {
-	return CommentDTO{
-		ID:      comment.ID,
-		UserID:  comment.UserID,
-		TrackID: comment.TrackID,
-		Stars:   comment.Stars,
-		Text:    comment.Text,
+	commentDTO := CommentDTO{
+		ID:        comment.ID,
+		UserID:    comment.UserID,
+		TrackID:   comment.TrackID,
+		Stars:     comment.Stars,
+		Text:      comment.Text,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt.Ptr(),
+		Pinned:    comment.PinnedAt.Valid,
+		Hidden:    comment.IsHidden(),
+		HiddenBy:  string(comment.HiddenBy),
+		Helpful:   comment.HelpfulVotes,
+		Unhelpful: comment.UnhelpfulVotes,
+	}
+
+	if comment.IsReply() {
+		parentID := comment.ParentID
+		commentDTO.ParentID = &parentID
+	}
+
+	for _, reply := range comment.Replies {
+		commentDTO.Replies = append(commentDTO.Replies, CommentFromDomain(reply))
 	}
+
+	return commentDTO
 }
 
 type PostCommentDTO struct {
-	Stars int    `json:"stars"`
-	Text  string `json:"text"`
+	Stars int    `json:"stars" binding:"required,min=1,max=5"`
+	Text  string `json:"text" binding:"max=1024"`
+}
+
+type ReplyCommentDTO struct {
+	Text string `json:"text" binding:"required,max=1024"`
+}
+
+type EditCommentDTO struct {
+	Stars *int    `json:"stars" binding:"omitempty,min=1,max=5"`
+	Text  *string `json:"text" binding:"omitempty,max=1024"`
+}
+
+type CommentPageQueryDTO struct {
+	Sort   string `form:"sort" binding:"omitempty,oneof=newest stars helpful"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+func (q *CommentPageQueryDTO) ToDomain() domain.CommentPage {
+	return domain.CommentPage{
+		Sort:   domain.CommentSort(q.Sort),
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+}
+
+type VoteCommentDTO struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+type ReportCommentDTO struct {
+	Reason string `json:"reason" binding:"required,max=512"`
+}
+
+type CommentReportDTO struct {
+	ID        uuid.UUID `json:"id"`
+	CommentID uuid.UUID `json:"comment_id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func CommentReportFromDomain(report domain.CommentReport) CommentReportDTO {
+	return CommentReportDTO{
+		ID:        report.ID,
+		CommentID: report.CommentID,
+		Reason:    report.Reason,
+		CreatedAt: report.CreatedAt,
+	}
+}
+
+type ReportedCommentDTO struct {
+	Comment         CommentDTO `json:"comment"`
+	Reports         int        `json:"reports"`
+	LastReason      string     `json:"last_reason"`
+	FirstReportedAt time.Time  `json:"first_reported_at"`
+}
+
+func ReportedCommentFromDomain(reported domain.ReportedComment) ReportedCommentDTO {
+	return ReportedCommentDTO{
+		Comment:         CommentFromDomain(reported.Comment),
+		Reports:         reported.Reports,
+		LastReason:      reported.LastReason,
+		FirstReportedAt: reported.FirstReportedAt,
+	}
+}
+
+type ModerationQueueQueryDTO struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+func (q *ModerationQueueQueryDTO) ToDomain() domain.CommentPage {
+	return domain.CommentPage{
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
 }
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type AlbumContributorDTO struct {
+	Musician MusicianDTO `json:"musician"`
+	Owner    bool        `json:"owner"`
+}
+
+func AlbumContributorFromDomain(contributor domain.AlbumContributor) AlbumContributorDTO {
+	return AlbumContributorDTO{
+		Musician: MusicianFromDomain(contributor.Musician),
+		Owner:    contributor.Owner,
+	}
+}
+
+type TrackCreditDTO struct {
+	Musician MusicianDTO `json:"musician"`
+	Role     string      `json:"role"`
+}
+
+func TrackCreditFromDomain(credit domain.TrackCredit) TrackCreditDTO {
+	return TrackCreditDTO{
+		Musician: MusicianFromDomain(credit.Musician),
+		Role:     string(credit.Role),
+	}
+}
+
+type InviteContributorDTO struct {
+	MusicianID uuid.UUID `json:"musician_id" binding:"required"`
+}
+
+type AddTrackCreditDTO struct {
+	MusicianID uuid.UUID `json:"musician_id" binding:"required"`
+	Role       string    `json:"role" binding:"required,oneof=primary featured producer composer"`
+}
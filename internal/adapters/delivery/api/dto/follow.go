@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type FeedItemDTO struct {
+	MusicianID  uuid.UUID `json:"musician_id"`
+	Album       AlbumDTO  `json:"album"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+func FeedItemFromDomain(item domain.FeedItem) FeedItemDTO {
+	return FeedItemDTO{
+		MusicianID:  item.MusicianID,
+		Album:       AlbumFromDomain(item.Album),
+		PublishedAt: item.PublishedAt,
+	}
+}
@@ -2,16 +2,21 @@ package dto
 
 import (
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
 )
 
 type MusicianDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	Country     string    `json:"country"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"image_url"`
+	ID          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	Email       string            `json:"email"`
+	Country     string            `json:"country"`
+	Description string            `json:"description"`
+	ImageURL    string            `json:"image_url"`
+	Verified    bool              `json:"verified"`
+	Followers   int64             `json:"followers"`
+	Links       []MusicianLinkDTO `json:"links,omitempty"`
 }
 
 func MusicianFromDomain(musician domain.Musician) MusicianDTO {
@@ -22,6 +27,26 @@ func MusicianFromDomain(musician domain.Musician) MusicianDTO {
 		Country:     musician.Country,
 		Description: musician.Description,
 		ImageURL:    musician.ImageURL.ValueOrZero(),
+		Verified:    musician.IsVerified(),
+	}
+}
+
+type MusicianLinkDTO struct {
+	Kind string `json:"kind" binding:"required"`
+	URL  string `json:"url" binding:"required"`
+}
+
+func MusicianLinkFromDomain(link domain.MusicianLink) MusicianLinkDTO {
+	return MusicianLinkDTO{
+		Kind: string(link.Kind),
+		URL:  link.URL,
+	}
+}
+
+func (l *MusicianLinkDTO) ToDomain() domain.MusicianLink {
+	return domain.MusicianLink{
+		Kind: domain.MusicianLinkKind(l.Kind),
+		URL:  l.URL,
 	}
 }
 
@@ -32,3 +57,29 @@ type RegisterMusicianDTO struct {
 	Country     string `json:"country" binding:"required"`
 	Description string `json:"description" binding:"required"`
 }
+
+// UpdateMusicianDTO changes only the fields present in the request. Links
+// replace all links when present, an empty list removes them.
+type UpdateMusicianDTO struct {
+	Name        *string            `json:"name" binding:"omitempty,min=1"`
+	Country     *string            `json:"country" binding:"omitempty,min=1"`
+	Description *string            `json:"description"`
+	Links       *[]MusicianLinkDTO `json:"links" binding:"omitempty,dive"`
+}
+
+func (u *UpdateMusicianDTO) ToServiceRequest() ports.MusicianServiceUpdateRequest {
+	req := ports.MusicianServiceUpdateRequest{
+		Name:        null.StringFromPtr(u.Name),
+		Country:     null.StringFromPtr(u.Country),
+		Description: null.StringFromPtr(u.Description),
+	}
+
+	if u.Links != nil {
+		req.Links = make([]domain.MusicianLink, len(*u.Links))
+		for i, link := range *u.Links {
+			req.Links[i] = link.ToDomain()
+		}
+	}
+
+	return req
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type OIDCAuthorizationDTO struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OIDCCallbackDTO carries the query of the redirect back from the provider.
+// Error is set instead of Code when the user declined.
+type OIDCCallbackDTO struct {
+	Code   string `form:"code"`
+	State  string `form:"state" binding:"required"`
+	Error  string `form:"error"`
+	Device string `form:"device" binding:"omitempty,max=64"`
+}
+
+type ExternalIdentityDTO struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ExternalIdentityFromDomain(identity domain.ExternalIdentity) ExternalIdentityDTO {
+	return ExternalIdentityDTO{
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
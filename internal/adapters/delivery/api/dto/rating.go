@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type RatingDTO struct {
+	Count     int            `json:"count"`
+	Mean      float64        `json:"mean"`
+	Histogram map[string]int `json:"histogram"`
+}
+
+func RatingFromDomain(rating domain.Rating) RatingDTO {
+	histogram := make(map[string]int, len(rating.Histogram))
+	for i, count := range rating.Histogram {
+		histogram[strconv.Itoa(i+1)] = count
+	}
+
+	return RatingDTO{
+		Count:     rating.Count,
+		Mean:      math.Round(rating.Mean()*100) / 100,
+		Histogram: histogram,
+	}
+}
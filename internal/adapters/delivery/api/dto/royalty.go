@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+// StatementPeriodLayout formats statement months, e.g. 2024-05.
+const StatementPeriodLayout = "2006-01"
+
+type RoyaltyStatementDTO struct {
+	Period      string    `json:"period"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Plays       int64     `json:"plays"`
+	Amount      int64     `json:"amount"`
+	TotalPlays  int64     `json:"total_plays"`
+	Pool        int64     `json:"pool"`
+}
+
+func RoyaltyStatementFromDomain(statement domain.RoyaltyStatement) RoyaltyStatementDTO {
+	return RoyaltyStatementDTO{
+		Period:      statement.Period.Start.Format(StatementPeriodLayout),
+		PeriodStart: statement.Period.Start,
+		PeriodEnd:   statement.Period.End,
+		Plays:       statement.Plays,
+		Amount:      statement.Amount,
+		TotalPlays:  statement.Period.Plays,
+		Pool:        statement.Period.Pool,
+	}
+}
+
+// RoyaltyStatementCSVHeader matches the columns of CSVRecord.
+var RoyaltyStatementCSVHeader = []string{"period", "period_start", "period_end", "plays", "amount", "total_plays", "pool"}
+
+func (s RoyaltyStatementDTO) CSVRecord() []string {
+	return []string{
+		s.Period,
+		s.PeriodStart.Format(time.RFC3339),
+		s.PeriodEnd.Format(time.RFC3339),
+		strconv.FormatInt(s.Plays, 10),
+		strconv.FormatInt(s.Amount, 10),
+		strconv.FormatInt(s.TotalPlays, 10),
+		strconv.FormatInt(s.Pool, 10),
+	}
+}
+
+// RoyaltyStatementQueryDTO selects statements from the from month to the to
+// month inclusive, both as StatementPeriodLayout.
+type RoyaltyStatementQueryDTO struct {
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type SubscriptionPlanDTO struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Price        int64     `json:"price"`
+	DurationDays int       `json:"duration_days"`
+}
+
+func SubscriptionPlanFromDomain(plan domain.SubscriptionPlan) SubscriptionPlanDTO {
+	return SubscriptionPlanDTO{
+		ID:           plan.ID,
+		Name:         plan.Name,
+		Price:        plan.Price,
+		DurationDays: plan.DurationDays,
+	}
+}
+
+type SubscriptionDTO struct {
+	ID             uuid.UUID `json:"id"`
+	PlanID         uuid.UUID `json:"plan_id"`
+	StartDate      time.Time `json:"start_date"`
+	ExpirationDate time.Time `json:"expiration_date"`
+	Status         string    `json:"status"`
+}
+
+func SubscriptionFromDomain(subscription domain.Subscription) SubscriptionDTO {
+	return SubscriptionDTO{
+		ID:             subscription.ID,
+		PlanID:         subscription.PlanID,
+		StartDate:      subscription.StartDate,
+		ExpirationDate: subscription.ExpirationDate,
+		Status:         string(subscription.Status),
+	}
+}
+
+type StartSubscriptionDTO struct {
+	PlanID uuid.UUID `json:"plan_id" binding:"required"`
+}
+
+type OrderDTO struct {
+	ID          uuid.UUID `json:"id"`
+	PlanID      uuid.UUID `json:"plan_id"`
+	Amount      int64     `json:"amount"`
+	Status      string    `json:"status"`
+	CheckoutURL string    `json:"checkout_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func OrderFromDomain(order domain.Order) OrderDTO {
+	return OrderDTO{
+		ID:          order.ID,
+		PlanID:      order.PlanID,
+		Amount:      order.Amount,
+		Status:      string(order.Status),
+		CheckoutURL: order.CheckoutURL,
+		CreatedAt:   order.CreatedAt,
+	}
+}
@@ -6,22 +6,73 @@ import (
 )
 
 type TrackDTO struct {
-	ID      uuid.UUID `json:"id"`
-	AlbumID uuid.UUID `json:"album_id"`
-	Name    string    `json:"name"`
-	URL     string    `json:"url"`
+	ID          uuid.UUID `json:"id"`
+	AlbumID     uuid.UUID `json:"album_id"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	DiscNumber  int       `json:"disc_number"`
+	TrackNumber int       `json:"track_number"`
+	Duration    int       `json:"duration_seconds"`
+	Rating      RatingDTO `json:"rating"`
 }
 
 func TrackFromDomain(track domain.Track) TrackDTO {
 	return TrackDTO{
-		ID:      track.ID,
-		AlbumID: track.AlbumID,
-		Name:    track.Name,
-		URL:     track.URL,
+		ID:          track.ID,
+		AlbumID:     track.AlbumID,
+		Name:        track.Name,
+		URL:         track.URL,
+		DiscNumber:  track.DiscNumber,
+		TrackNumber: track.TrackNumber,
+		Duration:    track.DurationSeconds,
+		Rating:      RatingFromDomain(track.Rating),
 	}
 }
 
 type CreateTrackDTO struct {
 	Name     string   `json:"name" binding:"required"`
+	Duration int      `json:"duration_seconds" binding:"required,min=1"`
 	GenreIDs []string `json:"genres" binding:"omitempty"`
 }
+
+type TrackPositionDTO struct {
+	TrackID     uuid.UUID `json:"track_id" binding:"required"`
+	DiscNumber  int       `json:"disc_number" binding:"required,min=1"`
+	TrackNumber int       `json:"track_number" binding:"required,min=1"`
+}
+
+type ReorderTracksDTO struct {
+	Tracks []TrackPositionDTO `json:"tracks" binding:"required,min=1,dive"`
+}
+
+func (r *ReorderTracksDTO) ToDomain() []domain.TrackPosition {
+	positions := make([]domain.TrackPosition, len(r.Tracks))
+	for i, track := range r.Tracks {
+		positions[i] = domain.TrackPosition{
+			TrackID:     track.TrackID,
+			DiscNumber:  track.DiscNumber,
+			TrackNumber: track.TrackNumber,
+		}
+	}
+
+	return positions
+}
+
+type UpdateTrackDTO struct {
+	Name     *string    `json:"name" binding:"omitempty,min=1"`
+	AlbumID  *uuid.UUID `json:"album_id"`
+	Duration *int       `json:"duration_seconds" binding:"omitempty,min=1"`
+	GenreIDs *[]string  `json:"genres"`
+}
+
+type TrackDownloadDTO struct {
+	URL string `json:"url"`
+}
+
+type TrackListQueryDTO struct {
+	Sort string `form:"sort" binding:"omitempty,oneof=rating"`
+}
+
+type TrackPlayDTO struct {
+	ListenedSeconds int `json:"listened_seconds" binding:"min=0"`
+}
@@ -1,26 +1,54 @@
 package dto
 
 import (
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 )
 
+// UserDTO is the private profile, shown to the user themselves and to staff.
 type UserDTO struct {
-	ID      uuid.UUID `json:"id"`
-	Name    string    `json:"name"`
-	Email   string    `json:"email"`
-	Phone   string    `json:"phone"`
-	Country string    `json:"country"`
+	ID                  uuid.UUID  `json:"id"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email"`
+	Phone               string     `json:"phone"`
+	Country             string     `json:"country"`
+	Role                string     `json:"role"`
+	AvatarURL           string     `json:"avatar_url"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
 }
 
 func UserFromDomain(user domain.User) UserDTO {
 	return UserDTO{
-		ID:      user.ID,
-		Name:    user.Name,
-		Email:   user.Email,
-		Phone:   user.Phone,
-		Country: user.Country,
+		ID:                  user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		Phone:               user.Phone,
+		Country:             user.Country,
+		Role:                strings.ToLower(domain.RoleName(user.Role)),
+		AvatarURL:           user.AvatarURL.ValueOrZero(),
+		DeletionScheduledAt: user.DeletionScheduledAt.Ptr(),
+	}
+}
+
+// PublicUserDTO is the profile anyone can see, without contact details.
+type PublicUserDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Country   string    `json:"country"`
+	AvatarURL string    `json:"avatar_url"`
+}
+
+func PublicUserFromDomain(user domain.User) PublicUserDTO {
+	return PublicUserDTO{
+		ID:        user.ID,
+		Name:      user.Name,
+		Country:   user.Country,
+		AvatarURL: user.AvatarURL.ValueOrZero(),
 	}
 }
 
@@ -41,3 +69,34 @@ func (r *RegisterUserDTO) ToServiceRequest() ports.UserServiceCreateRequest {
 		Country:  r.Country,
 	}
 }
+
+type UpdateUserDTO struct {
+	Name    *string `json:"name" binding:"omitempty,min=1"`
+	Email   *string `json:"email" binding:"omitempty,email"`
+	Phone   *string `json:"phone" binding:"omitempty,min=1"`
+	Country *string `json:"country" binding:"omitempty,min=1"`
+}
+
+func (u *UpdateUserDTO) ToServiceRequest() ports.UserServiceUpdateRequest {
+	return ports.UserServiceUpdateRequest{
+		Name:    null.StringFromPtr(u.Name),
+		Email:   null.StringFromPtr(u.Email),
+		Phone:   null.StringFromPtr(u.Phone),
+		Country: null.StringFromPtr(u.Country),
+	}
+}
+
+type SetUserRoleDTO struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+func (s *SetUserRoleDTO) ToDomain() int {
+	switch s.Role {
+	case "moderator":
+		return domain.ModeratorRole
+	case "admin":
+		return domain.AdminRole
+	default:
+		return domain.UserRole
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"go.uber.org/zap"
+)
+
+type DataExportHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewDataExportHandler(router *gin.RouterGroup,
+	logger *zap.Logger,
+	services *Services,
+	authHandler *AuthHandler,
+) *DataExportHandler {
+	exportHandler := &DataExportHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	router.POST("/users/me/export",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		exportHandler.request)
+	router.GET("/users/me/export/:export_id",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		exportHandler.get)
+
+	return exportHandler
+}
+
+// @Summary RequestDataExport
+// @Tags user
+// @Security ApiKeyAuth
+// @Description start building an archive of all personal data, poll the export until it's ready
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {object} dto.DataExportDTO
+// @Router /users/me/export [post]
+func (h *DataExportHandler) request(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	export, err := h.s.DataExportService.Request(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, dto.DataExportFromDomain(export))
+}
+
+// @Summary GetDataExport
+// @Tags user
+// @Security ApiKeyAuth
+// @Description get export status, a ready export comes with a short-lived download link
+// @Produce json
+// @Param   export_id   path    string  true  "export id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 410 {object} RestErrorGone
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.DataExportDTO
+// @Router /users/me/export/{export_id} [get]
+func (h *DataExportHandler) get(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	exportID, err := getIdFromPath(context, "export_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	export, err := h.s.DataExportService.Get(context.Request.Context(), userID, exportID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	exportDTO := dto.DataExportFromDomain(export)
+	if export.Status == domain.DataExportReady {
+		link, err := h.s.DataExportService.DownloadURL(context.Request.Context(), userID, exportID)
+		if err != nil {
+			errorResponse(context, err)
+			return
+		}
+		exportDTO.DownloadURL = link.String()
+	}
+
+	successResponse(context, exportDTO)
+}
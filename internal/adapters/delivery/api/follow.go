@@ -0,0 +1,184 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"go.uber.org/zap"
+)
+
+type FollowHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewFollowHandler(router *gin.RouterGroup,
+	logger *zap.Logger,
+	services *Services,
+	authHandler *AuthHandler,
+) *FollowHandler {
+	followHandler := &FollowHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	router.GET("/users/me/following",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		followHandler.getFollowing)
+	router.POST("/users/me/following/:musician_id",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		followHandler.follow)
+	router.DELETE("/users/me/following/:musician_id",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		followHandler.unfollow)
+	router.GET("/users/me/feed",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		followHandler.getFeed)
+
+	return followHandler
+}
+
+// @Summary Follow
+// @Tags follow
+// @Security ApiKeyAuth
+// @Description follow musician
+// @Accept  json
+// @Produce json
+// @Param   musician_id   path    string  true  "musician id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /users/me/following/{musician_id} [post]
+func (h *FollowHandler) follow(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromPath(context, "musician_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.FollowService.Follow(context.Request.Context(), userID, musicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary Unfollow
+// @Tags follow
+// @Security ApiKeyAuth
+// @Description unfollow musician
+// @Accept  json
+// @Produce json
+// @Param   musician_id   path    string  true  "musician id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /users/me/following/{musician_id} [delete]
+func (h *FollowHandler) unfollow(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromPath(context, "musician_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.FollowService.Unfollow(context.Request.Context(), userID, musicianID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary GetFollowing
+// @Tags follow
+// @Security ApiKeyAuth
+// @Description get musicians followed by user
+// @Accept  json
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.MusicianDTO
+// @Router /users/me/following [get]
+func (h *FollowHandler) getFollowing(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicians, err := h.s.FollowService.GetFollowing(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianDTOs, err := musiciansWithFollowers(context, h.s, musicians)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, musicianDTOs)
+}
+
+// @Summary GetFeed
+// @Tags follow
+// @Security ApiKeyAuth
+// @Description get albums released by followed musicians, newest first
+// @Accept  json
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.FeedItemDTO
+// @Router /users/me/feed [get]
+func (h *FollowHandler) getFeed(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	feed, err := h.s.FollowService.GetFeed(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	feedDTOs := make([]dto.FeedItemDTO, len(feed))
+	for i := range feed {
+		feedDTOs[i] = dto.FeedItemFromDomain(feed[i])
+	}
+
+	successResponse(context, feedDTOs)
+}
@@ -16,12 +16,24 @@ import (
 
 type Services struct {
 	AuthService     ports.IAuthorizationService
+	AccountService  ports.IAccountService
+	OIDCService     ports.IOIDCService
 	AlbumService    ports.IAlbumService
 	MusicianService ports.IMusicianService
 	UserService     ports.IUserService
 	TrackService    ports.ITrackService
 	CommentService  ports.ICommentService
 	GenreService    ports.IGenreService
+	FollowService   ports.IFollowService
+	CreditService   ports.ICreditService
+	StatService     ports.IStatService
+
+	SubscriptionService ports.ISubscriptionService
+	PaymentService      ports.IPaymentService
+	RoyaltyService      ports.IRoyaltyService
+	DataExportService   ports.IDataExportService
+
+	RateLimitService ports.IRateLimitService
 }
 
 type Handler struct {
@@ -31,10 +43,20 @@ type Handler struct {
 	albumHandler    *AlbumHandler
 	userHandler     *UserHandler
 	authHandler     *AuthHandler
+	accountHandler  *AccountHandler
+	oidcHandler     *OIDCHandler
 	musicianHandler *MusicianHandler
 	genreHandler    *GenreHandler
 	commentHandler  *CommentHandler
 	trackHandler    *TrackHandler
+	followHandler   *FollowHandler
+	creditHandler   *CreditHandler
+
+	subscriptionHandler *SubscriptionHandler
+	paymentHandler      *PaymentHandler
+	royaltyHandler      *RoyaltyHandler
+	exportHandler       *DataExportHandler
+	rateLimits          RateLimits
 }
 
 func NewHandler(logger *zap.Logger) *Handler {
@@ -43,26 +65,39 @@ func NewHandler(logger *zap.Logger) *Handler {
 	router.GET("/ping", func(c *gin.Context) {
 		c.String(http.StatusOK, "pong")
 	})
-	return &Handler{router: router}
+	return &Handler{router: router, logger: logger}
 }
 
 func (h *Handler) SetServices(services *Services) {
 	h.services = services
 }
 
+func (h *Handler) SetRateLimits(rateLimits RateLimits) {
+	h.rateLimits = rateLimits
+}
+
 func (h *Handler) ConfigureHandlers() error {
 	if h.services == nil {
 		return errors.New("services are not set")
 	}
 
 	v1Router := h.router.Group("/api/v1")
-	h.authHandler = NewAuthHandler(v1Router, h.logger, h.services)
+	h.authHandler = NewAuthHandler(v1Router, h.logger, h.services, h.rateLimits)
+	h.router.GET("/.well-known/jwks.json", h.authHandler.jwks)
+	h.accountHandler = NewAccountHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.oidcHandler = NewOIDCHandler(v1Router, h.logger, h.services, h.authHandler)
 	h.albumHandler = NewAlbumHandler(v1Router, h.logger, h.services, h.authHandler)
 	h.userHandler = NewUserHandler(v1Router, h.logger, h.services, h.authHandler)
 	h.musicianHandler = NewMusicianHandler(v1Router, h.logger, h.services, h.authHandler)
 	h.genreHandler = NewGenreHandler(v1Router, h.logger, h.services, h.authHandler)
 	h.commentHandler = NewCommentHandler(v1Router, h.logger, h.services, h.authHandler)
 	h.trackHandler = NewTrackHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.followHandler = NewFollowHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.creditHandler = NewCreditHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.subscriptionHandler = NewSubscriptionHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.paymentHandler = NewPaymentHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.royaltyHandler = NewRoyaltyHandler(v1Router, h.logger, h.services, h.authHandler)
+	h.exportHandler = NewDataExportHandler(v1Router, h.logger, h.services, h.authHandler)
 
 	return nil
 }
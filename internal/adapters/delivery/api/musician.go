@@ -4,7 +4,9 @@ import (
 	"log"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"go.uber.org/zap"
 )
@@ -35,6 +37,18 @@ func NewMusicianHandler(router *gin.RouterGroup,
 			musicianHandler.getAll)
 		musicianGroup.GET("/:musician_id",
 			musicianHandler.getByID)
+		musicianGroup.PATCH("/me",
+			authHandler.verifyToken,
+			authHandler.verifyMusicianRole,
+			musicianHandler.updateMe)
+		musicianGroup.PUT("/:musician_id/verification",
+			authHandler.verifyToken,
+			authHandler.requirePermission(domain.PermissionMusicianVerify),
+			musicianHandler.verify)
+		musicianGroup.DELETE("/:musician_id/verification",
+			authHandler.verifyToken,
+			authHandler.requirePermission(domain.PermissionMusicianVerify),
+			musicianHandler.unverify)
 	}
 
 	router.GET("/albums/:album_id/musicians", musicianHandler.getByAlbumID)
@@ -43,6 +57,7 @@ func NewMusicianHandler(router *gin.RouterGroup,
 	router.PUT("/musicians/:musician_id/image",
 		authHandler.verifyToken,
 		authHandler.verifyMusicianRole,
+		authHandler.rateLimit("upload", authHandler.rateLimits.Upload, accountKey),
 		musicianHandler.uploadImage)
 
 	return musicianHandler
@@ -85,7 +100,12 @@ func (h *MusicianHandler) uploadImage(context *gin.Context) {
 		return
 	}
 
-	musicianDTO := dto.MusicianFromDomain(musician)
+	musicianDTO, err := musicianWithFollowers(context, h.s, musician)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
 	successResponse(context, musicianDTO)
 }
 
@@ -123,6 +143,10 @@ func (h *MusicianHandler) register(context *gin.Context) {
 		return
 	}
 
+	// The account is created even if the mail can't be sent, the link can be
+	// requested again from /auth/email/verification.
+	_ = h.s.AccountService.SendEmailVerification(context.Request.Context(), musician.ID)
+
 	musicianDTO := dto.MusicianFromDomain(musician)
 	createdResponse(context, musicianDTO)
 }
@@ -142,9 +166,10 @@ func (h *MusicianHandler) getAll(context *gin.Context) {
 		return
 	}
 
-	musicianDTOs := make([]dto.MusicianDTO, len(musicians))
-	for i := range musicians {
-		musicianDTOs[i] = dto.MusicianFromDomain(musicians[i])
+	musicianDTOs, err := musiciansWithFollowers(context, h.s, musicians)
+	if err != nil {
+		errorResponse(context, err)
+		return
 	}
 
 	successResponse(context, musicianDTOs)
@@ -174,7 +199,126 @@ func (h *MusicianHandler) getByID(context *gin.Context) {
 		return
 	}
 
-	musicianDTO := dto.MusicianFromDomain(musician)
+	musicianDTO, err := musicianWithFollowers(context, h.s, musician)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = withLinks(context, h.s, &musicianDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, musicianDTO)
+}
+
+// @Summary UpdateOwnProfile
+// @Tags musician
+// @Security ApiKeyAuth
+// @Description change name, country, description or external links, a new name drops the verified badge
+// @Accept  json
+// @Produce json
+// @Param input body dto.UpdateMusicianDTO true "changed fields"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.MusicianDTO
+// @Router /musicians/me [patch]
+func (h *MusicianHandler) updateMe(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var updateDTO dto.UpdateMusicianDTO
+	err = context.ShouldBindJSON(&updateDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musician, err := h.s.MusicianService.UpdateProfile(context.Request.Context(), musicianID,
+		updateDTO.ToServiceRequest())
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianDTO, err := musicianWithFollowers(context, h.s, musician)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = withLinks(context, h.s, &musicianDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, musicianDTO)
+}
+
+// @Summary VerifyMusician
+// @Tags musician
+// @Security ApiKeyAuth
+// @Description grant the verified badge
+// @Accept  json
+// @Produce json
+// @Param   musician_id   path    string  true  "musician id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.MusicianDTO
+// @Router /musicians/{musician_id}/verification [put]
+func (h *MusicianHandler) verify(context *gin.Context) {
+	h.setVerified(context, true)
+}
+
+// @Summary UnverifyMusician
+// @Tags musician
+// @Security ApiKeyAuth
+// @Description revoke the verified badge
+// @Accept  json
+// @Produce json
+// @Param   musician_id   path    string  true  "musician id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.MusicianDTO
+// @Router /musicians/{musician_id}/verification [delete]
+func (h *MusicianHandler) unverify(context *gin.Context) {
+	h.setVerified(context, false)
+}
+
+func (h *MusicianHandler) setVerified(context *gin.Context, verified bool) {
+	id, err := getIdFromPath(context, "musician_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musician, err := h.s.MusicianService.SetVerified(context.Request.Context(), id, verified)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianDTO, err := musicianWithFollowers(context, h.s, musician)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
 	successResponse(context, musicianDTO)
 }
 
@@ -202,7 +346,12 @@ func (h *MusicianHandler) getByAlbumID(context *gin.Context) {
 		return
 	}
 
-	musicianDTO := dto.MusicianFromDomain(musician)
+	musicianDTO, err := musicianWithFollowers(context, h.s, musician)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
 	successResponse(context, musicianDTO)
 }
 
@@ -230,6 +379,58 @@ func (h *MusicianHandler) getByTrackID(context *gin.Context) {
 		return
 	}
 
-	musicianDTO := dto.MusicianFromDomain(musician)
+	musicianDTO, err := musicianWithFollowers(context, h.s, musician)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
 	successResponse(context, musicianDTO)
 }
+
+func musicianWithFollowers(context *gin.Context, s *Services, musician domain.Musician) (dto.MusicianDTO, error) {
+	followers, err := s.FollowService.CountFollowers(context.Request.Context(), musician.ID)
+	if err != nil {
+		return dto.MusicianDTO{}, err
+	}
+
+	musicianDTO := dto.MusicianFromDomain(musician)
+	musicianDTO.Followers = followers
+
+	return musicianDTO, nil
+}
+
+// musiciansWithFollowers counts followers of the whole list in one query.
+func musiciansWithFollowers(context *gin.Context, s *Services, musicians []domain.Musician) ([]dto.MusicianDTO, error) {
+	musicianIDs := make([]uuid.UUID, len(musicians))
+	for i, musician := range musicians {
+		musicianIDs[i] = musician.ID
+	}
+
+	followers, err := s.FollowService.CountFollowersByIDs(context.Request.Context(), musicianIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	musicianDTOs := make([]dto.MusicianDTO, len(musicians))
+	for i, musician := range musicians {
+		musicianDTOs[i] = dto.MusicianFromDomain(musician)
+		musicianDTOs[i].Followers = followers[musician.ID]
+	}
+
+	return musicianDTOs, nil
+}
+
+func withLinks(context *gin.Context, s *Services, musicianDTO *dto.MusicianDTO) error {
+	links, err := s.MusicianService.GetLinks(context.Request.Context(), musicianDTO.ID)
+	if err != nil {
+		return err
+	}
+
+	musicianDTO.Links = make([]dto.MusicianLinkDTO, len(links))
+	for i, link := range links {
+		musicianDTO.Links[i] = dto.MusicianLinkFromDomain(link)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+type OIDCHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewOIDCHandler(router *gin.RouterGroup, logger *zap.Logger, services *Services, authHandler *AuthHandler) *OIDCHandler {
+	oidcHandler := &OIDCHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	oidcGroup := router.Group("/auth/oidc")
+	{
+		oidcGroup.GET("/identities",
+			authHandler.verifyToken,
+			oidcHandler.getIdentities)
+		oidcGroup.GET("/:provider/login",
+			authHandler.rateLimit("auth", authHandler.rateLimits.Auth, clientIPKey),
+			oidcHandler.login)
+		oidcGroup.POST("/:provider/link",
+			authHandler.verifyToken,
+			oidcHandler.link)
+		oidcGroup.GET("/:provider/callback",
+			authHandler.rateLimit("auth", authHandler.rateLimits.Auth, clientIPKey),
+			oidcHandler.callback)
+		oidcGroup.DELETE("/:provider",
+			authHandler.verifyToken,
+			oidcHandler.unlink)
+	}
+
+	return oidcHandler
+}
+
+// @Summary OIDCLogin
+// @Tags auth
+// @Description redirect to the OpenID Connect provider to log in
+// @Param   provider   path    string  true  "provider name"
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 302
+// @Router /auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) login(context *gin.Context) {
+	authURL, err := h.s.OIDCService.Begin(context.Request.Context(), ports.BeginOIDCRequest{
+		Provider: context.Param("provider"),
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	context.Redirect(http.StatusFound, authURL)
+}
+
+// @Summary OIDCLink
+// @Tags auth
+// @Description start linking an OpenID Connect identity to the current account
+// @Security ApiKeyAuth
+// @Produce json
+// @Param   provider   path    string  true  "provider name"
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.OIDCAuthorizationDTO
+// @Router /auth/oidc/{provider}/link [post]
+func (h *OIDCHandler) link(context *gin.Context) {
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	authURL, err := h.s.OIDCService.Begin(context.Request.Context(), ports.BeginOIDCRequest{
+		Provider:      context.Param("provider"),
+		LinkAccountID: accountID,
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.OIDCAuthorizationDTO{AuthorizationURL: authURL})
+}
+
+// @Summary OIDCCallback
+// @Tags auth
+// @Description redirect target of the OpenID Connect provider, logs in the linked account
+// @Produce json
+// @Param   provider   path    string  true  "provider name"
+// @Param   code       query   string  false "authorization code"
+// @Param   state      query   string  true  "state from the login redirect"
+// @Param   error      query   string  false "error reported by the provider"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.LoginResponseDTO
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) callback(context *gin.Context) {
+	var callbackDTO dto.OIDCCallbackDTO
+	err := context.ShouldBindQuery(&callbackDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	if callbackDTO.Error != "" || callbackDTO.Code == "" {
+		errorResponse(context, BadRequestError)
+		return
+	}
+
+	tokenPair, err := h.s.OIDCService.Complete(context.Request.Context(), ports.CompleteOIDCRequest{
+		Provider: context.Param("provider"),
+		State:    callbackDTO.State,
+		Code:     callbackDTO.Code,
+		Metadata: domain.SessionMetadata{
+			DeviceLabel: callbackDTO.Device,
+			UserAgent:   context.Request.UserAgent(),
+			IP:          context.ClientIP(),
+		},
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.LoginResponseFromTokenPair(tokenPair))
+}
+
+// @Summary GetExternalIdentities
+// @Tags auth
+// @Description OpenID Connect identities linked to the current account
+// @Security ApiKeyAuth
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.ExternalIdentityDTO
+// @Router /auth/oidc/identities [get]
+func (h *OIDCHandler) getIdentities(context *gin.Context) {
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	identities, err := h.s.OIDCService.GetIdentities(context.Request.Context(), accountID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	identityDTOs := make([]dto.ExternalIdentityDTO, len(identities))
+	for i, identity := range identities {
+		identityDTOs[i] = dto.ExternalIdentityFromDomain(identity)
+	}
+
+	successResponse(context, identityDTOs)
+}
+
+// @Summary UnlinkExternalIdentity
+// @Tags auth
+// @Description unlink the identity of a provider from the current account
+// @Security ApiKeyAuth
+// @Produce json
+// @Param   provider   path    string  true  "provider name"
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /auth/oidc/{provider} [delete]
+func (h *OIDCHandler) unlink(context *gin.Context) {
+	accountID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.OIDCService.Unlink(context.Request.Context(), accountID, context.Param("provider"))
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"go.uber.org/zap"
+)
+
+const paymentSignatureHeader = "X-Payment-Signature"
+
+// maxWebhookBodySize bounds the body read before its signature is verified.
+const maxWebhookBodySize = 1 << 16
+
+type PaymentHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewPaymentHandler(router *gin.RouterGroup,
+	logger *zap.Logger,
+	services *Services,
+	authHandler *AuthHandler,
+) *PaymentHandler {
+	paymentHandler := &PaymentHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	router.POST("/payments/webhook", paymentHandler.webhook)
+	router.GET("/users/me/orders/:order_id",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		paymentHandler.getOrder)
+	router.POST("/orders/:order_id/refund",
+		authHandler.verifyToken,
+		authHandler.requirePermission(domain.PermissionPaymentRefund),
+		paymentHandler.refund)
+
+	return paymentHandler
+}
+
+// @Summary PaymentWebhook
+// @Tags payment
+// @Description payment gateway callback, signed with the shared webhook secret
+// @Accept  json
+// @Produce json
+// @Param X-Payment-Signature header string true "t=<unix time>,v1=<hex HMAC-SHA256>"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) webhook(context *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(context.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		errorResponse(context, BadRequestError)
+		return
+	}
+
+	err = h.s.PaymentService.HandleWebhook(context.Request.Context(), payload,
+		context.GetHeader(paymentSignatureHeader))
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
+
+// @Summary GetOrder
+// @Tags payment
+// @Security ApiKeyAuth
+// @Description get order of the user, e.g. to wait for the payment to complete
+// @Produce json
+// @Param   order_id   path    string  true  "order id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.OrderDTO
+// @Router /users/me/orders/{order_id} [get]
+func (h *PaymentHandler) getOrder(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	orderID, err := getIdFromPath(context, "order_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	order, err := h.s.PaymentService.GetOrder(context.Request.Context(), userID, orderID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.OrderFromDomain(order))
+}
+
+// @Summary RefundOrder
+// @Tags payment
+// @Security ApiKeyAuth
+// @Description refund a paid order and take its subscription period back
+// @Produce json
+// @Param   order_id   path    string  true  "order id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.OrderDTO
+// @Router /orders/{order_id}/refund [post]
+func (h *PaymentHandler) refund(context *gin.Context) {
+	orderID, err := getIdFromPath(context, "order_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	order, err := h.s.PaymentService.Refund(context.Request.Context(), orderID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.OrderFromDomain(order))
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+)
+
+// RateLimits are applied per client IP unless the name says otherwise.
+type RateLimits struct {
+	Auth    domain.RateLimit
+	Login   domain.RateLimit // per account name
+	Upload  domain.RateLimit // per account
+	Comment domain.RateLimit // per account
+	Play    domain.RateLimit // per account and track
+	Lockout domain.LockoutPolicy
+}
+
+type rateLimitKey func(context *gin.Context) string
+
+func clientIPKey(context *gin.Context) string {
+	return "ip:" + context.ClientIP()
+}
+
+// accountKey has to run after verifyToken.
+func accountKey(context *gin.Context) string {
+	id, err := getIdFromRequestContext(context)
+	if err != nil {
+		return clientIPKey(context)
+	}
+
+	return "account:" + id.String()
+}
+
+// accountTrackKey limits how often an account reports plays of one track.
+func accountTrackKey(context *gin.Context) string {
+	return accountKey(context) + ":track:" + context.Param("track_id")
+}
+
+func loginNameKey(context *gin.Context) string {
+	name := loginName(context)
+	if name == "" {
+		return ""
+	}
+
+	return "name:" + name
+}
+
+// loginName reads the login body without consuming it, the login handler
+// binds it again from the cached copy.
+func loginName(context *gin.Context) string {
+	var loginDTO dto.LoginDTO
+	_ = context.ShouldBindBodyWith(&loginDTO, binding.JSON)
+	return strings.ToLower(strings.TrimSpace(loginDTO.Name))
+}
+
+// rateLimit rejects the request with 429 once the key has used up the limit
+// of the scope. Requests without a key are not limited.
+func (h *AuthHandler) rateLimit(scope string, limit domain.RateLimit, key rateLimitKey) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if !limit.Enabled() {
+			return
+		}
+
+		keyValue := key(context)
+		if keyValue == "" {
+			return
+		}
+
+		err := h.s.RateLimitService.Allow(context.Request.Context(), scope+":"+keyValue, limit)
+		if err != nil {
+			errorResponse(context, err)
+			return
+		}
+	}
+}
+
+// loginLockout locks an account name out for a growing time after repeated
+// failed logins. Unknown names count as well, otherwise the lockout would
+// tell which accounts exist.
+func (h *AuthHandler) loginLockout(context *gin.Context) {
+	name := loginName(context)
+	if name == "" || !h.rateLimits.Lockout.Enabled() {
+		return
+	}
+
+	key := "login:" + name
+	err := h.s.RateLimitService.CheckLockout(context.Request.Context(), key)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	context.Next()
+
+	for _, ginErr := range context.Errors {
+		if errors.Is(ginErr.Err, ports.ErrIncorrectPassword) || errors.Is(ginErr.Err, ports.ErrIncorrectName) {
+			_ = h.s.RateLimitService.RegisterFailure(context.Request.Context(), key, h.rateLimits.Lockout)
+			return
+		}
+	}
+
+	if context.Writer.Status() == http.StatusOK {
+		_ = h.s.RateLimitService.Reset(context.Request.Context(), key)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -41,11 +42,26 @@ var errorStatusMap = map[error]int{
 	ports.ErrAlbumIDNotFound:   http.StatusNotFound,
 	ports.ErrAlbumPublish:      http.StatusInternalServerError,
 	ports.ErrInternalAlbumRepo: http.StatusInternalServerError,
+	ports.ErrAlbumPublished:    http.StatusConflict,
+	ports.ErrAlbumSchedule:     http.StatusBadRequest,
+	ports.ErrAlbumNotPublished: http.StatusConflict,
+	ports.ErrAlbumDelete:       http.StatusInternalServerError,
+	ports.ErrAlbumUpdate:       http.StatusInternalServerError,
 
 	ports.ErrCommentDuplicate:         http.StatusBadRequest,
 	ports.ErrCommentIDNotFound:        http.StatusNotFound,
 	ports.ErrCommentByTrackIDNotFound: http.StatusNotFound,
 	ports.ErrCommentByUserIDNotFound:  http.StatusNotFound,
+	ports.ErrCommentStars:             http.StatusBadRequest,
+	ports.ErrCommentNotOwner:          http.StatusForbidden,
+	ports.ErrCommentProfanity:         http.StatusBadRequest,
+	ports.ErrCommentReportOwn:         http.StatusBadRequest,
+	ports.ErrCommentReported:          http.StatusConflict,
+	ports.ErrCommentNotOnOwnTrack:     http.StatusForbidden,
+	ports.ErrCommentHiddenByModerator: http.StatusForbidden,
+	ports.ErrCommentPinReply:          http.StatusBadRequest,
+	ports.ErrCommentVoteOwn:           http.StatusBadRequest,
+	ports.ErrCommentVoteNotFound:      http.StatusNotFound,
 	ports.ErrInternalCommentRepo:      http.StatusInternalServerError,
 
 	ports.ErrGenreIDNotFound:   http.StatusNotFound,
@@ -55,6 +71,8 @@ var errorStatusMap = map[error]int{
 	ports.ErrTrackDuplicate:    http.StatusBadRequest,
 	ports.ErrTrackIDNotFound:   http.StatusNotFound,
 	ports.ErrTrackDelete:       http.StatusBadRequest,
+	ports.ErrTrackPosition:     http.StatusBadRequest,
+	ports.ErrTrackUpdate:       http.StatusInternalServerError,
 	ports.ErrInternalTrackRepo: http.StatusInternalServerError,
 
 	ports.ErrUserDuplicate:      http.StatusBadRequest,
@@ -63,27 +81,109 @@ var errorStatusMap = map[error]int{
 	ports.ErrUserEmailNotFound:  http.StatusNotFound,
 	ports.ErrUserPhoneNotFound:  http.StatusNotFound,
 	ports.ErrUserUnknownCountry: http.StatusBadRequest,
+	ports.ErrUserRole:           http.StatusBadRequest,
+	ports.ErrUserUpdate:         http.StatusInternalServerError,
 	ports.ErrInternalUserRepo:   http.StatusInternalServerError,
 
 	ports.ErrUserWithSuchNameAlreadyExists:  http.StatusConflict,
 	ports.ErrUserWithSuchEmailAlreadyExists: http.StatusConflict,
 	ports.ErrUserWithSuchPhoneAlreadyExists: http.StatusConflict,
+	ports.ErrUserDeletionRequested:          http.StatusConflict,
+	ports.ErrUserDeletionNotRequested:       http.StatusConflict,
+
+	ports.ErrAccountInvalidName:   http.StatusBadRequest,
+	ports.ErrAccountNotFound:      http.StatusNotFound,
+	ports.ErrAccountUpdate:        http.StatusInternalServerError,
+	ports.ErrAccountNoEmail:       http.StatusBadRequest,
+	ports.ErrEmailAlreadyVerified: http.StatusConflict,
+	ports.ErrInternalAccountRepo:  http.StatusInternalServerError,
+
+	ports.ErrTwoFactorNotFound:       http.StatusNotFound,
+	ports.ErrTwoFactorAlreadyEnabled: http.StatusConflict,
+	ports.ErrTwoFactorInvalidCode:    http.StatusUnauthorized,
+	ports.ErrInternalTwoFactorRepo:   http.StatusInternalServerError,
+
+	ports.ErrOneTimeTokenInvalid:          http.StatusBadRequest,
+	ports.ErrInternalOneTimeTokenProvider: http.StatusInternalServerError,
+	ports.ErrInternalMailer:               http.StatusInternalServerError,
+
+	ports.ErrOIDCProviderNotFound:      http.StatusNotFound,
+	ports.ErrOIDCInvalidState:          http.StatusBadRequest,
+	ports.ErrOIDCExchange:              http.StatusUnauthorized,
+	ports.ErrOIDCInvalidIDToken:        http.StatusUnauthorized,
+	ports.ErrOIDCIdentityNotLinked:     http.StatusForbidden,
+	ports.ErrOIDCIdentityAlreadyLinked: http.StatusConflict,
+	ports.ErrOIDCIdentityNotFound:      http.StatusNotFound,
+	ports.ErrInternalOIDCProvider:      http.StatusInternalServerError,
+	ports.ErrInternalOIDCStateStorage:  http.StatusInternalServerError,
+	ports.ErrInternalIdentityRepo:      http.StatusInternalServerError,
 
 	ports.ErrMusicianDuplicate:      http.StatusBadRequest,
 	ports.ErrMusicianIDNotFound:     http.StatusNotFound,
 	ports.ErrMusicianNameNotFound:   http.StatusNotFound,
 	ports.ErrMusicianEmailNotFound:  http.StatusNotFound,
 	ports.ErrMusicianUnknownCountry: http.StatusBadRequest,
+	ports.ErrMusicianInvalidLink:    http.StatusBadRequest,
+	ports.ErrMusicianUpdate:         http.StatusInternalServerError,
 	ports.ErrInternalMusicianRepo:   http.StatusInternalServerError,
 
 	ports.ErrMusicianWithSuchNameAlreadyExists:  http.StatusConflict,
 	ports.ErrMusicianWithSuchEmailAlreadyExists: http.StatusConflict,
 
+	ports.ErrAlreadyFollowing:   http.StatusConflict,
+	ports.ErrNotFollowing:       http.StatusNotFound,
+	ports.ErrInternalFollowRepo: http.StatusInternalServerError,
+
+	ports.ErrAlreadyContributor:  http.StatusConflict,
+	ports.ErrInviteNotFound:      http.StatusNotFound,
+	ports.ErrContributorNotFound: http.StatusNotFound,
+	ports.ErrUnknownCreditRole:   http.StatusBadRequest,
+	ports.ErrCreditDuplicate:     http.StatusConflict,
+	ports.ErrCreditNotFound:      http.StatusNotFound,
+	ports.ErrSelfInvite:          http.StatusBadRequest,
+	ports.ErrInternalCreditRepo:  http.StatusInternalServerError,
+
 	ports.ErrIncorrectName:     http.StatusUnauthorized,
 	ports.ErrIncorrectPassword: http.StatusUnauthorized,
 	ports.ErrUnexpectedRole:    http.StatusUnauthorized,
 	ports.ErrInternalAuthRepo:  http.StatusUnauthorized,
 	ports.ErrInvalidToken:      http.StatusUnauthorized,
+	ports.ErrSessionNotFound:   http.StatusNotFound,
+
+	ports.ErrSubscriptionPlanNotFound:  http.StatusNotFound,
+	ports.ErrSubscriptionNotFound:      http.StatusNotFound,
+	ports.ErrSubscriptionAlreadyActive: http.StatusConflict,
+	ports.ErrSubscriptionNotRenewable:  http.StatusConflict,
+	ports.ErrSubscriptionRequired:      http.StatusPaymentRequired,
+	ports.ErrInternalSubscriptionRepo:  http.StatusInternalServerError,
+
+	ports.ErrPaymentInvalidSignature:    http.StatusBadRequest,
+	ports.ErrPaymentIntentNotFound:      http.StatusNotFound,
+	ports.ErrPaymentIdempotencyConflict: http.StatusConflict,
+	ports.ErrPaymentNotRefundable:       http.StatusConflict,
+	ports.ErrInternalPaymentGateway:     http.StatusInternalServerError,
+	ports.ErrOrderNotFound:              http.StatusNotFound,
+	ports.ErrOrderDuplicate:             http.StatusConflict,
+	ports.ErrOrderStatusChanged:         http.StatusConflict,
+	ports.ErrOrderNotRefundable:         http.StatusConflict,
+	ports.ErrIdempotencyKeyEmpty:        http.StatusBadRequest,
+	ports.ErrInternalOrderRepo:          http.StatusInternalServerError,
+
+	ports.ErrInternalStatRepo: http.StatusInternalServerError,
+
+	ports.ErrRoyaltyPeriodNotFound:  http.StatusNotFound,
+	ports.ErrRoyaltyPeriodAllocated: http.StatusConflict,
+	ports.ErrRoyaltyStatementRange:  http.StatusBadRequest,
+	ports.ErrInternalRoyaltyRepo:    http.StatusInternalServerError,
+
+	ports.ErrDataExportIDNotFound:   http.StatusNotFound,
+	ports.ErrDataExportInProgress:   http.StatusConflict,
+	ports.ErrDataExportNotReady:     http.StatusConflict,
+	ports.ErrDataExportExpired:      http.StatusGone,
+	ports.ErrInternalDataExportRepo: http.StatusInternalServerError,
+
+	ports.ErrRateLimited:              http.StatusTooManyRequests,
+	ports.ErrInternalRateLimitStorage: http.StatusInternalServerError,
 
 	PathIDNotFoundError: http.StatusBadRequest,
 	InvalidPathIDError:  http.StatusBadRequest,
@@ -112,6 +212,12 @@ type RestErrorUnauthorized struct {
 	Timestamp  time.Time `json:"timestamp,omitempty" example:"2020-11-10T23:00:00+00:00"`
 }
 
+type RestErrorPaymentRequired struct {
+	ErrStatus  int       `json:"status,omitempty" example:"402"`
+	ErrMessage string    `json:"error,omitempty" example:"active subscription required"`
+	Timestamp  time.Time `json:"timestamp,omitempty" example:"2020-11-10T23:00:00+00:00"`
+}
+
 type RestErrorForbidden struct {
 	ErrStatus  int       `json:"status,omitempty" example:"403"`
 	ErrMessage string    `json:"error,omitempty" example:"forbidden"`
@@ -130,6 +236,18 @@ type RestErrorConflict struct {
 	Timestamp  time.Time `json:"timestamp,omitempty" example:"2020-11-10T23:00:00+00:00"`
 }
 
+type RestErrorGone struct {
+	ErrStatus  int       `json:"status,omitempty" example:"410"`
+	ErrMessage string    `json:"error,omitempty" example:"gone"`
+	Timestamp  time.Time `json:"timestamp,omitempty" example:"2020-11-10T23:00:00+00:00"`
+}
+
+type RestErrorTooManyRequests struct {
+	ErrStatus  int       `json:"status,omitempty" example:"429"`
+	ErrMessage string    `json:"error,omitempty" example:"too many requests"`
+	Timestamp  time.Time `json:"timestamp,omitempty" example:"2020-11-10T23:00:00+00:00"`
+}
+
 type RestErrorInternalError struct {
 	ErrStatus  int       `json:"status,omitempty" example:"500"`
 	ErrMessage string    `json:"error,omitempty" example:"internal server error"`
@@ -200,6 +318,14 @@ func ParseError(err error) RestErr {
 func errorResponse(context *gin.Context, err error) {
 	debug.PrintStack()
 	restErr := ParseError(err)
+
+	var rateLimitErr *ports.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		context.Header("Retry-After", strconv.Itoa(rateLimitErr.RetryAfterSeconds()))
+	}
+
+	// Recorded so that middleware running after the handler can react to it.
+	_ = context.Error(err)
 	context.AbortWithStatusJSON(restErr.Status(), restErr)
 }
 
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"go.uber.org/zap"
+)
+
+type RoyaltyHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewRoyaltyHandler(router *gin.RouterGroup,
+	logger *zap.Logger,
+	services *Services,
+	authHandler *AuthHandler,
+) *RoyaltyHandler {
+	royaltyHandler := &RoyaltyHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	router.GET("/musicians/me/statements",
+		authHandler.verifyToken,
+		authHandler.verifyMusicianRole,
+		royaltyHandler.getStatements)
+
+	return royaltyHandler
+}
+
+// @Summary GetStatements
+// @Tags royalty
+// @Security ApiKeyAuth
+// @Description get monthly royalty statements of the musician, newest first, as JSON or CSV
+// @Produce json
+// @Produce text/csv
+// @Param   from   query   string  false  "first month, e.g. 2024-01"
+// @Param   to     query   string  false  "last month, e.g. 2024-12"
+// @Param   format query   string  false  "json (default) or csv"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.RoyaltyStatementDTO
+// @Router /musicians/me/statements [get]
+func (h *RoyaltyHandler) getStatements(context *gin.Context) {
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var queryDTO dto.RoyaltyStatementQueryDTO
+	err = context.ShouldBindQuery(&queryDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var from, to time.Time
+	if queryDTO.From != "" {
+		from, err = time.Parse(dto.StatementPeriodLayout, queryDTO.From)
+		if err != nil {
+			errorResponse(context, BadRequestError)
+			return
+		}
+	}
+
+	if queryDTO.To != "" {
+		to, err = time.Parse(dto.StatementPeriodLayout, queryDTO.To)
+		if err != nil {
+			errorResponse(context, BadRequestError)
+			return
+		}
+		to = to.AddDate(0, 1, 0)
+	}
+
+	statements, err := h.s.RoyaltyService.GetStatements(context.Request.Context(), musicianID, from, to)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	statementDTOs := make([]dto.RoyaltyStatementDTO, len(statements))
+	for i, statement := range statements {
+		statementDTOs[i] = dto.RoyaltyStatementFromDomain(statement)
+	}
+
+	if queryDTO.Format != "csv" {
+		successResponse(context, statementDTOs)
+		return
+	}
+
+	context.Header("Content-Disposition", `attachment; filename="statements.csv"`)
+	context.Header("Content-Type", "text/csv; charset=utf-8")
+	context.Status(http.StatusOK)
+
+	writer := csv.NewWriter(context.Writer)
+	_ = writer.Write(dto.RoyaltyStatementCSVHeader)
+	for _, statementDTO := range statementDTOs {
+		_ = writer.Write(statementDTO.CSVRecord())
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		h.logger.Error("Failed to write royalty statements", zap.Error(err))
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type SubscriptionHandler struct {
+	router      *gin.RouterGroup
+	logger      *zap.Logger
+	authHandler *AuthHandler
+	s           *Services
+}
+
+func NewSubscriptionHandler(router *gin.RouterGroup,
+	logger *zap.Logger,
+	services *Services,
+	authHandler *AuthHandler,
+) *SubscriptionHandler {
+	subscriptionHandler := &SubscriptionHandler{
+		router:      router,
+		logger:      logger,
+		authHandler: authHandler,
+		s:           services,
+	}
+
+	router.GET("/subscriptions/plans", subscriptionHandler.getPlans)
+	router.GET("/users/me/subscription",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		subscriptionHandler.getCurrent)
+	router.POST("/users/me/subscription",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		subscriptionHandler.start)
+	router.POST("/users/me/subscription/renew",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		subscriptionHandler.renew)
+	router.DELETE("/users/me/subscription",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		subscriptionHandler.cancel)
+
+	return subscriptionHandler
+}
+
+// @Summary GetSubscriptionPlans
+// @Tags subscription
+// @Description get available subscription plans
+// @Produce json
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} []dto.SubscriptionPlanDTO
+// @Router /subscriptions/plans [get]
+func (h *SubscriptionHandler) getPlans(context *gin.Context) {
+	plans, err := h.s.SubscriptionService.GetPlans(context.Request.Context())
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	planDTOs := make([]dto.SubscriptionPlanDTO, len(plans))
+	for i, plan := range plans {
+		planDTOs[i] = dto.SubscriptionPlanFromDomain(plan)
+	}
+
+	successResponse(context, planDTOs)
+}
+
+// @Summary GetSubscription
+// @Tags subscription
+// @Security ApiKeyAuth
+// @Description get current subscription of the user
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.SubscriptionDTO
+// @Router /users/me/subscription [get]
+func (h *SubscriptionHandler) getCurrent(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	subscription, err := h.s.SubscriptionService.GetCurrent(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.SubscriptionFromDomain(subscription))
+}
+
+// @Summary StartSubscription
+// @Tags subscription
+// @Security ApiKeyAuth
+// @Description order a period of a plan, the subscription starts or is extended once the payment succeeds
+// @Accept  json
+// @Produce json
+// @Param Idempotency-Key header string true "unique key of the purchase, retries with the same key return the same order"
+// @Param input body dto.StartSubscriptionDTO true "subscription plan"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {object} dto.OrderDTO
+// @Router /users/me/subscription [post]
+func (h *SubscriptionHandler) start(context *gin.Context) {
+	var startDTO dto.StartSubscriptionDTO
+	err := context.ShouldBindJSON(&startDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	order, err := h.s.PaymentService.Checkout(context.Request.Context(), ports.CheckoutRequest{
+		UserID:         userID,
+		PlanID:         startDTO.PlanID,
+		IdempotencyKey: context.GetHeader(idempotencyKeyHeader),
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, dto.OrderFromDomain(order))
+}
+
+// @Summary RenewSubscription
+// @Tags subscription
+// @Security ApiKeyAuth
+// @Description order another period of the plan of the current subscription
+// @Produce json
+// @Param Idempotency-Key header string true "unique key of the purchase, retries with the same key return the same order"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {object} dto.OrderDTO
+// @Router /users/me/subscription/renew [post]
+func (h *SubscriptionHandler) renew(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	subscription, err := h.s.SubscriptionService.GetCurrent(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	if subscription.PlanID == uuid.Nil {
+		errorResponse(context, ports.ErrSubscriptionNotRenewable)
+		return
+	}
+
+	order, err := h.s.PaymentService.Checkout(context.Request.Context(), ports.CheckoutRequest{
+		UserID:         userID,
+		PlanID:         subscription.PlanID,
+		IdempotencyKey: context.GetHeader(idempotencyKeyHeader),
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, dto.OrderFromDomain(order))
+}
+
+// @Summary CancelSubscription
+// @Tags subscription
+// @Security ApiKeyAuth
+// @Description cancel current subscription, it stays in effect until its expiration date
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.SubscriptionDTO
+// @Router /users/me/subscription [delete]
+func (h *SubscriptionHandler) cancel(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	subscription, err := h.s.SubscriptionService.Cancel(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.SubscriptionFromDomain(subscription))
+}
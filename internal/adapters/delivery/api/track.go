@@ -5,7 +5,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"go.uber.org/zap"
 )
@@ -31,14 +33,37 @@ func NewTrackHandler(router *gin.RouterGroup,
 
 	router.GET("/tracks/", trackHandler.getAll)
 	router.GET("/tracks/:track_id", trackHandler.getByID)
+	router.GET("/tracks/:track_id/download",
+		authHandler.verifyToken,
+		authHandler.requireSubscription,
+		trackHandler.download)
+	router.POST("/tracks/:track_id/plays",
+		authHandler.verifyToken,
+		authHandler.verifyUserRole,
+		authHandler.rateLimit("play", authHandler.rateLimits.Play, accountTrackKey),
+		trackHandler.addPlay)
 	router.DELETE("/musicians/:musician_id/tracks/:track_id",
 		authHandler.verifyToken,
 		authHandler.verifyTrackOwner,
 		trackHandler.delete)
+	router.DELETE("/tracks/:track_id",
+		authHandler.verifyToken,
+		authHandler.requirePermission(domain.PermissionTrackDeleteAny),
+		trackHandler.delete)
+	router.PATCH("/musicians/:musician_id/tracks/:track_id",
+		authHandler.verifyToken,
+		authHandler.verifyTrackOwner,
+		trackHandler.update)
+	router.PUT("/musicians/:musician_id/tracks/:track_id/audio",
+		authHandler.verifyToken,
+		authHandler.verifyTrackOwner,
+		authHandler.rateLimit("upload", authHandler.rateLimits.Upload, accountKey),
+		trackHandler.replaceAudio)
 
 	router.POST("/musicians/:musician_id/albums/:album_id/tracks",
 		authHandler.verifyToken,
 		authHandler.verifyMusicianAlbumOwner,
+		authHandler.rateLimit("upload", authHandler.rateLimits.Upload, accountKey),
 		trackHandler.create,
 	)
 
@@ -148,10 +173,11 @@ func (h *TrackHandler) create(context *gin.Context) {
 	track, err := h.s.TrackService.Create(
 		context.Request.Context(),
 		ports.CreateTrackReq{
-			AlbumID:   albumID,
-			Name:      createTrackDTO.Name,
-			TrackBLOB: strings.NewReader("fdf"),
-			GenresID:  genreIDs,
+			AlbumID:         albumID,
+			Name:            createTrackDTO.Name,
+			DurationSeconds: createTrackDTO.Duration,
+			TrackBLOB:       strings.NewReader("fdf"),
+			GenresID:        genreIDs,
 		},
 	)
 	if err != nil {
@@ -168,22 +194,24 @@ func (h *TrackHandler) create(context *gin.Context) {
 // @Description get all tracks
 // @Accept  json
 // @Produce json
+// @Param   sort query   string  false  "rating sorts by mean stars, best first"
 // @Failure 500 {object} RestErrorInternalError
 // @Success 200 {object} []dto.TrackDTO
 // @Router /tracks [get]
 func (h *TrackHandler) getAll(context *gin.Context) {
-	tracks, err := h.s.TrackService.GetAll(context.Request.Context())
+	sort, err := getTrackSort(context)
 	if err != nil {
 		errorResponse(context, err)
 		return
 	}
 
-	trackDTOs := make([]dto.TrackDTO, len(tracks))
-	for i := range tracks {
-		trackDTOs[i] = dto.TrackFromDomain(tracks[i])
+	tracks, err := h.s.TrackService.GetAll(context.Request.Context(), sort)
+	if err != nil {
+		errorResponse(context, err)
+		return
 	}
 
-	successResponse(context, trackDTOs)
+	trackListResponse(context, tracks)
 }
 
 // @Summary getOwn
@@ -192,6 +220,7 @@ func (h *TrackHandler) getAll(context *gin.Context) {
 // @Description get own tracks
 // @Accept  json
 // @Produce json
+// @Param   sort query   string  false  "rating sorts by mean stars, best first"
 // @Failure 500 {object} RestErrorInternalError
 // @Success 200 {object} []dto.TrackDTO
 // @Router /musicians/me/tracks [get]
@@ -202,18 +231,19 @@ func (h *TrackHandler) getOwn(context *gin.Context) {
 		return
 	}
 
-	tracks, err := h.s.TrackService.GetOwn(context.Request.Context(), musicianID)
+	sort, err := getTrackSort(context)
 	if err != nil {
 		errorResponse(context, err)
 		return
 	}
 
-	trackDTOs := make([]dto.TrackDTO, len(tracks))
-	for i := range tracks {
-		trackDTOs[i] = dto.TrackFromDomain(tracks[i])
+	tracks, err := h.s.TrackService.GetOwn(context.Request.Context(), musicianID, sort)
+	if err != nil {
+		errorResponse(context, err)
+		return
 	}
 
-	successResponse(context, trackDTOs)
+	trackListResponse(context, tracks)
 }
 
 // @Summary GetTrackByID
@@ -244,6 +274,35 @@ func (h *TrackHandler) getByID(context *gin.Context) {
 	successResponse(context, trackDTO)
 }
 
+// @Summary DownloadTrack
+// @Tags track
+// @Security ApiKeyAuth
+// @Description get track file for offline listening, requires an active subscription
+// @Produce json
+// @Param   id   path    string  true  "track id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 402 {object} RestErrorPaymentRequired
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.TrackDownloadDTO
+// @Router /tracks/{id}/download [get]
+func (h *TrackHandler) download(context *gin.Context) {
+	id, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	track, err := h.s.TrackService.GetByID(context.Request.Context(), id)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.TrackDownloadDTO{URL: track.URL})
+}
+
 // @Summary DeleteTrack
 // @Tags track
 // @Description get track by id
@@ -259,6 +318,7 @@ func (h *TrackHandler) getByID(context *gin.Context) {
 // @Failure 500 {object} RestErrorInternalError
 // @Success 200 {object} dto.TrackDTO
 // @Router /musicians/{musician_id}/tracks/{track_id} [delete]
+// @Router /tracks/{track_id} [delete]
 func (h *TrackHandler) delete(context *gin.Context) {
 	id, err := getIdFromPath(context, "track_id")
 	if err != nil {
@@ -276,12 +336,155 @@ func (h *TrackHandler) delete(context *gin.Context) {
 	successResponse(context, trackDTO)
 }
 
+// @Summary UpdateTrack
+// @Tags track
+// @Security ApiKeyAuth
+// @Description rename track, move it to another own album or change its genres
+// @Accept  json
+// @Produce json
+// @Param   musician_id   path    string  true  "musician id"
+// @Param   track_id   path    string  true  "track id"
+// @Param input body dto.UpdateTrackDTO true "track info"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.TrackDTO
+// @Router /musicians/{musician_id}/tracks/{track_id} [patch]
+func (h *TrackHandler) update(context *gin.Context) {
+	var updateTrackDTO dto.UpdateTrackDTO
+	err := context.ShouldBindJSON(&updateTrackDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var albumID uuid.NullUUID
+	if updateTrackDTO.AlbumID != nil {
+		musician, err := h.s.MusicianService.GetByAlbumID(context.Request.Context(), *updateTrackDTO.AlbumID)
+		if err != nil {
+			errorResponse(context, err)
+			return
+		}
+
+		if musician.ID != musicianID {
+			errorResponse(context, ForbiddenError)
+			return
+		}
+
+		albumID = uuid.NullUUID{UUID: *updateTrackDTO.AlbumID, Valid: true}
+	}
+
+	var genreIDs []uuid.UUID
+	if updateTrackDTO.GenreIDs != nil {
+		genreIDs = make([]uuid.UUID, len(*updateTrackDTO.GenreIDs))
+		for i, genre := range *updateTrackDTO.GenreIDs {
+			id, err := uuid.Parse(genre)
+			if err != nil {
+				errorResponse(context, ParseGenreIDError)
+				return
+			}
+
+			genreIDs[i] = id
+		}
+	}
+
+	var duration null.Int
+	if updateTrackDTO.Duration != nil {
+		duration = null.IntFrom(int64(*updateTrackDTO.Duration))
+	}
+
+	track, err := h.s.TrackService.Update(context.Request.Context(), ports.UpdateTrackReq{
+		TrackID:         trackID,
+		MusicianID:      musicianID,
+		Name:            null.StringFromPtr(updateTrackDTO.Name),
+		AlbumID:         albumID,
+		DurationSeconds: duration,
+		GenresID:        genreIDs,
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.TrackFromDomain(track))
+}
+
+// @Summary ReplaceTrackAudio
+// @Tags track
+// @Security ApiKeyAuth
+// @Description replace track audio file keeping the track id
+// @Accept  mpfd
+// @Produce json
+// @Param   musician_id   path    string  true  "musician id"
+// @Param   track_id   path    string  true  "track id"
+// @Param audio formData file true "audio file"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.TrackDTO
+// @Router /musicians/{musician_id}/tracks/{track_id}/audio [put]
+func (h *TrackHandler) replaceAudio(context *gin.Context) {
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	musicianID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	fileheader, err := context.FormFile("audio")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	file, err := fileheader.Open()
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+	defer file.Close()
+
+	track, err := h.s.TrackService.ReplaceAudio(context.Request.Context(), ports.ReplaceTrackAudioReq{
+		TrackID:    trackID,
+		MusicianID: musicianID,
+		TrackBLOB:  file,
+	})
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.TrackFromDomain(track))
+}
+
 // @Summary GetTracksByAlbumID
 // @Tags track
 // @Description get tracks by album id
 // @Accept  json
 // @Produce json
 // @Param   id   path    string  true  "album id"
+// @Param   sort query   string  false  "rating sorts by mean stars, best first"
 // @Failure 400 {object} RestErrorBadRequest
 // @Failure 404 {object} RestErrorNotFound
 // @Failure 500 {object} RestErrorInternalError
@@ -294,18 +497,19 @@ func (h *TrackHandler) getByAlbumID(context *gin.Context) {
 		return
 	}
 
-	tracks, err := h.s.TrackService.GetByAlbumID(context.Request.Context(), id)
+	sort, err := getTrackSort(context)
 	if err != nil {
 		errorResponse(context, err)
 		return
 	}
 
-	trackDTOs := make([]dto.TrackDTO, len(tracks))
-	for i := range tracks {
-		trackDTOs[i] = dto.TrackFromDomain(tracks[i])
+	tracks, err := h.s.TrackService.GetByAlbumID(context.Request.Context(), id, sort)
+	if err != nil {
+		errorResponse(context, err)
+		return
 	}
 
-	successResponse(context, trackDTOs)
+	trackListResponse(context, tracks)
 }
 
 // @Summary GetFavorites
@@ -314,6 +518,7 @@ func (h *TrackHandler) getByAlbumID(context *gin.Context) {
 // @Description get user favorites tracks
 // @Accept  json
 // @Produce json
+// @Param   sort query   string  false  "rating sorts by mean stars, best first"
 // @Failure 400 {object} RestErrorBadRequest
 // @Failure 401 {object} RestErrorUnauthorized
 // @Failure 403 {object} RestErrorForbidden
@@ -328,18 +533,19 @@ func (h *TrackHandler) getFavorites(context *gin.Context) {
 		return
 	}
 
-	tracks, err := h.s.TrackService.GetUserFavorites(context.Request.Context(), id)
+	sort, err := getTrackSort(context)
 	if err != nil {
 		errorResponse(context, err)
 		return
 	}
 
-	trackDTOs := make([]dto.TrackDTO, len(tracks))
-	for i := range tracks {
-		trackDTOs[i] = dto.TrackFromDomain(tracks[i])
+	tracks, err := h.s.TrackService.GetUserFavorites(context.Request.Context(), id, sort)
+	if err != nil {
+		errorResponse(context, err)
+		return
 	}
 
-	successResponse(context, trackDTOs)
+	trackListResponse(context, tracks)
 }
 
 // @Summary AddToFavorites
@@ -378,12 +584,59 @@ func (h *TrackHandler) addToFavorites(context *gin.Context) {
 	successResponse(context, struct{}{})
 }
 
+// @Summary AddPlay
+// @Tags track
+// @Security ApiKeyAuth
+// @Description record that the user listened to the track, counted for royalties when long enough and
+// @Description the user was subscribed. Listened seconds are cut down to the track duration.
+// @Accept  json
+// @Produce json
+// @Param   track_id   path    string  true  "track id"
+// @Param input body dto.TrackPlayDTO true "listened seconds"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 429 {object} RestErrorTooManyRequests
+// @Failure 500 {object} RestErrorInternalError
+// @Success 201 {string} string ""
+// @Router /tracks/{track_id}/plays [post]
+func (h *TrackHandler) addPlay(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	trackID, err := getIdFromPath(context, "track_id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var playDTO dto.TrackPlayDTO
+	err = context.ShouldBindJSON(&playDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.StatService.Add(context.Request.Context(), userID, trackID, playDTO.ListenedSeconds)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	createdResponse(context, struct{}{})
+}
+
 // @Summary GetTracksByMusicianID
 // @Tags track
 // @Description get tracks by musician id
 // @Accept  json
 // @Produce json
 // @Param   id   path    string  true  "musician id"
+// @Param   sort query   string  false  "rating sorts by mean stars, best first"
 // @Failure 400 {object} RestErrorBadRequest
 // @Failure 404 {object} RestErrorNotFound
 // @Failure 500 {object} RestErrorInternalError
@@ -396,12 +649,33 @@ func (h *TrackHandler) getByMusicianID(context *gin.Context) {
 		return
 	}
 
-	tracks, err := h.s.TrackService.GetByMusicianID(context.Request.Context(), id)
+	sort, err := getTrackSort(context)
 	if err != nil {
 		errorResponse(context, err)
 		return
 	}
 
+	tracks, err := h.s.TrackService.GetByMusicianID(context.Request.Context(), id, sort)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	trackListResponse(context, tracks)
+}
+
+// getTrackSort reads the order of a track list from the sort query parameter.
+func getTrackSort(context *gin.Context) (domain.TrackSort, error) {
+	var query dto.TrackListQueryDTO
+	err := context.ShouldBindQuery(&query)
+	if err != nil {
+		return domain.TrackSortDefault, err
+	}
+
+	return domain.TrackSort(query.Sort), nil
+}
+
+func trackListResponse(context *gin.Context, tracks []domain.Track) {
 	trackDTOs := make([]dto.TrackDTO, len(tracks))
 	for i := range tracks {
 		trackDTOs[i] = dto.TrackFromDomain(tracks[i])
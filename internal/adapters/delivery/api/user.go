@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"go.uber.org/zap"
 )
@@ -32,6 +33,35 @@ func NewUserHandler(router *gin.RouterGroup,
 			userHandler.getAll)
 		userGroup.GET("/:id",
 			userHandler.getByID)
+		userGroup.GET("/me",
+			authHandler.verifyToken,
+			authHandler.verifyUserRole,
+			userHandler.getMe)
+		userGroup.PATCH("/me",
+			authHandler.verifyToken,
+			authHandler.verifyUserRole,
+			userHandler.updateMe)
+		userGroup.PUT("/me/avatar",
+			authHandler.verifyToken,
+			authHandler.verifyUserRole,
+			authHandler.rateLimit("upload", authHandler.rateLimits.Upload, accountKey),
+			userHandler.uploadAvatar)
+		userGroup.DELETE("/me",
+			authHandler.verifyToken,
+			authHandler.verifyUserRole,
+			userHandler.requestDeletion)
+		userGroup.POST("/me/restore",
+			authHandler.verifyToken,
+			authHandler.verifyUserRole,
+			userHandler.cancelDeletion)
+		userGroup.PUT("/:id/role",
+			authHandler.verifyToken,
+			authHandler.requirePermission(domain.PermissionUserRoleManage),
+			userHandler.setRole)
+		userGroup.DELETE("/:id/sessions",
+			authHandler.verifyToken,
+			authHandler.requirePermission(domain.PermissionSessionRevokeAny),
+			userHandler.revokeSessions)
 	}
 
 	return userHandler
@@ -71,6 +101,10 @@ func (h *UserHandler) register(context *gin.Context) {
 		return
 	}
 
+	// The account is created even if the mail can't be sent, the link can be
+	// requested again from /auth/email/verification.
+	_ = h.s.AccountService.SendEmailVerification(context.Request.Context(), user.ID)
+
 	userDTO := dto.UserFromDomain(user)
 	createdResponse(context, userDTO)
 }
@@ -81,7 +115,7 @@ func (h *UserHandler) register(context *gin.Context) {
 // @Accept  json
 // @Produce json
 // @Failure 500 {object} RestErrorInternalError
-// @Success 200 {object} []dto.UserDTO
+// @Success 200 {object} []dto.PublicUserDTO
 // @Router /users [get]
 func (h *UserHandler) getAll(context *gin.Context) {
 	users, err := h.s.UserService.GetAll(context.Request.Context())
@@ -90,9 +124,9 @@ func (h *UserHandler) getAll(context *gin.Context) {
 		return
 	}
 
-	userDTOs := make([]dto.UserDTO, len(users))
+	userDTOs := make([]dto.PublicUserDTO, len(users))
 	for i := range users {
-		userDTOs[i] = dto.UserFromDomain(users[i])
+		userDTOs[i] = dto.PublicUserFromDomain(users[i])
 	}
 
 	successResponse(context, userDTOs)
@@ -107,7 +141,7 @@ func (h *UserHandler) getAll(context *gin.Context) {
 // @Failure 400 {object} RestErrorBadRequest
 // @Failure 404 {object} RestErrorNotFound
 // @Failure 500 {object} RestErrorInternalError
-// @Success 200 {object} dto.UserDTO
+// @Success 200 {object} dto.PublicUserDTO
 // @Router /users/{id} [get]
 func (h *UserHandler) getByID(context *gin.Context) {
 	id, err := getIdFromPath(context, "id")
@@ -122,6 +156,252 @@ func (h *UserHandler) getByID(context *gin.Context) {
 		return
 	}
 
+	userDTO := dto.PublicUserFromDomain(user)
+	successResponse(context, userDTO)
+}
+
+// @Summary GetOwnProfile
+// @Tags user
+// @Security ApiKeyAuth
+// @Description get own profile with contact details
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.UserDTO
+// @Router /users/me [get]
+func (h *UserHandler) getMe(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	user, err := h.s.UserService.GetById(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.UserFromDomain(user))
+}
+
+// @Summary UpdateOwnProfile
+// @Tags user
+// @Security ApiKeyAuth
+// @Description change name, email, phone or country, a new email has to be verified again
+// @Accept  json
+// @Produce json
+// @Param input body dto.UpdateUserDTO true "changed fields"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.UserDTO
+// @Router /users/me [patch]
+func (h *UserHandler) updateMe(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var updateDTO dto.UpdateUserDTO
+	err = context.ShouldBindJSON(&updateDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	oldUser, err := h.s.UserService.GetById(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	user, err := h.s.UserService.UpdateProfile(context.Request.Context(), userID, updateDTO.ToServiceRequest())
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	// The verification is bound to the old address, so the new one gets a
+	// link right away. It can be requested again if the mail is lost.
+	if user.Email != oldUser.Email {
+		_ = h.s.AccountService.SendEmailVerification(context.Request.Context(), user.ID)
+	}
+
+	successResponse(context, dto.UserFromDomain(user))
+}
+
+// @Summary UploadAvatar
+// @Tags user
+// @Security ApiKeyAuth
+// @Description upload own avatar
+// @Accept  mpfd
+// @Produce json
+// @Param image formData file true "upload file"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.UserDTO
+// @Router /users/me/avatar [put]
+func (h *UserHandler) uploadAvatar(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	fileheader, err := context.FormFile("image")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	file, err := fileheader.Open()
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+	defer file.Close()
+
+	user, err := h.s.UserService.UploadAvatar(context.Request.Context(), file, userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.UserFromDomain(user))
+}
+
+// @Summary DeleteOwnAccount
+// @Tags user
+// @Security ApiKeyAuth
+// @Description schedule the account deletion, history, comments and favorites are removed after the grace period
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.UserDTO
+// @Router /users/me [delete]
+func (h *UserHandler) requestDeletion(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	user, err := h.s.UserService.RequestDeletion(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.UserFromDomain(user))
+}
+
+// @Summary RestoreOwnAccount
+// @Tags user
+// @Security ApiKeyAuth
+// @Description cancel a scheduled account deletion
+// @Produce json
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 409 {object} RestErrorConflict
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.UserDTO
+// @Router /users/me/restore [post]
+func (h *UserHandler) cancelDeletion(context *gin.Context) {
+	userID, err := getIdFromRequestContext(context)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	user, err := h.s.UserService.CancelDeletion(context.Request.Context(), userID)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, dto.UserFromDomain(user))
+}
+
+// @Summary SetUserRole
+// @Tags user
+// @Security ApiKeyAuth
+// @Description grant or revoke a staff role, the user has to log in again
+// @Accept  json
+// @Produce json
+// @Param   id   path    string  true  "user id"
+// @Param input body dto.SetUserRoleDTO true "role"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 404 {object} RestErrorNotFound
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {object} dto.UserDTO
+// @Router /users/{id}/role [put]
+func (h *UserHandler) setRole(context *gin.Context) {
+	id, err := getIdFromPath(context, "id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	var roleDTO dto.SetUserRoleDTO
+	err = context.ShouldBindJSON(&roleDTO)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	user, err := h.s.UserService.SetRole(context.Request.Context(), id, roleDTO.ToDomain())
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	// Tokens carry the role, so the old sessions would keep the previous
+	// permissions until they expire.
+	err = h.s.AuthService.LogOutEverywhere(context.Request.Context(), id)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
 	userDTO := dto.UserFromDomain(user)
 	successResponse(context, userDTO)
 }
+
+// @Summary RevokeUserSessions
+// @Tags user
+// @Security ApiKeyAuth
+// @Description log the user out on all devices
+// @Produce json
+// @Param   id   path    string  true  "user id"
+// @Failure 400 {object} RestErrorBadRequest
+// @Failure 401 {object} RestErrorUnauthorized
+// @Failure 403 {object} RestErrorForbidden
+// @Failure 500 {object} RestErrorInternalError
+// @Success 200 {string} string ""
+// @Router /users/{id}/sessions [delete]
+func (h *UserHandler) revokeSessions(context *gin.Context) {
+	id, err := getIdFromPath(context, "id")
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	err = h.s.AuthService.LogOutEverywhere(context.Request.Context(), id)
+	if err != nil {
+		errorResponse(context, err)
+		return
+	}
+
+	successResponse(context, struct{}{})
+}
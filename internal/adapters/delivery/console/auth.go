@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/console/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 )
 
@@ -11,9 +12,15 @@ func (h *Handler) LogIn(c *Console) {
 	var logInDTO dto.LogInDTO
 	dto.InputLogInDTO(&logInDTO)
 
-	tokenPair, err := h.authService.LogIn(context.Background(), ports.LogInCredentials{
+	metadata := domain.SessionMetadata{
+		DeviceLabel: "console",
+		UserAgent:   "sigma-music console",
+	}
+
+	result, err := h.authService.LogIn(context.Background(), ports.LogInCredentials{
 		Name:     logInDTO.Name,
 		Password: logInDTO.Password,
+		Metadata: metadata,
 	})
 
 	if err != nil {
@@ -21,16 +28,95 @@ func (h *Handler) LogIn(c *Console) {
 		return
 	}
 
+	tokenPair := result.Tokens
+	if result.ChallengeRequired() {
+		tokenPair, err = h.authService.LogInTwoFactor(context.Background(), ports.TwoFactorCredentials{
+			ChallengeToken: result.ChallengeToken,
+			Code:           dto.InputTwoFactorCode(),
+			Metadata:       metadata,
+		})
+
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
 	payload, _ := h.authService.VerifyToken(context.Background(), tokenPair.AccessToken)
 
 	c.UserID = payload.UserID
 	c.UserRole = payload.Role
+	c.AccessToken = tokenPair.AccessToken
 
 	fmt.Println("Your ID:", c.UserID)
 }
 
 func (h *Handler) LogOut(c *Console) {
+	if c.AccessToken != "" {
+		err := h.authService.LogOut(context.Background(), c.AccessToken)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	c.UserRole = -1
+	c.AccessToken = ""
+}
+
+func (h *Handler) GetSessions(c *Console) {
+	err := h.verifyAuth(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	sessions, err := h.authService.GetSessions(context.Background(), c.UserID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	payload, _ := h.authService.VerifyToken(context.Background(), c.AccessToken)
+	for _, session := range sessions {
+		dto.NewSessionDTO(session, session.ID == payload.SessionID).Print()
+		fmt.Println("-----------------------")
+	}
+}
+
+func (h *Handler) RevokeSession(c *Console) {
+	err := h.verifyAuth(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	id, err := readID()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = h.authService.RevokeSession(context.Background(), c.UserID, id)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func (h *Handler) LogOutEverywhere(c *Console) {
+	err := h.verifyAuth(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = h.authService.LogOutEverywhere(context.Background(), c.UserID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	c.UserRole = -1
+	c.AccessToken = ""
 }
 
 func (h *Handler) SignUpUser(c *Console) {
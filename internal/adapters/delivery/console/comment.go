@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/console/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 )
 
@@ -46,7 +47,8 @@ func (h *Handler) GetCommentsOnTrack(c *Console) {
 		return
 	}
 
-	comments, err := h.commentService.GetCommentsOnTrack(context.Background(), id)
+	comments, _, err := h.commentService.GetCommentsOnTrack(context.Background(), id,
+		domain.CommentPage{Sort: domain.CommentSortNewest})
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -10,10 +10,11 @@ import (
 type Option int
 
 type Console struct {
-	Handler  *Handler
-	Routes   map[Option]func(*Console)
-	UserID   uuid.UUID
-	UserRole int
+	Handler     *Handler
+	Routes      map[Option]func(*Console)
+	UserID      uuid.UUID
+	UserRole    int
+	AccessToken string
 }
 
 func NewConsole(h *Handler) *Console {
@@ -64,6 +65,10 @@ const (
 
 	getStatistic
 	listen
+
+	getSessions
+	revokeSession
+	logOutEverywhere
 )
 
 func (c *Console) InitRoutes() {
@@ -102,6 +107,9 @@ func (c *Console) InitRoutes() {
 		getStatistic:            c.Handler.GetStat,
 		listen:                  c.Handler.Listen,
 		getOwnTracks:            c.Handler.GetOwnTrack,
+		getSessions:             c.Handler.GetSessions,
+		revokeSession:           c.Handler.RevokeSession,
+		logOutEverywhere:        c.Handler.LogOutEverywhere,
 	}
 }
 
@@ -171,4 +179,8 @@ func (c *Console) PrintMenu() {
 	fmt.Println("31. Get statistics (U)")
 	fmt.Println("32. Listen Track (U)")
 	fmt.Println("-----------------------")
+	fmt.Println("33. Show active sessions")
+	fmt.Println("34. Revoke session")
+	fmt.Println("35. Log out everywhere")
+	fmt.Println("-----------------------")
 }
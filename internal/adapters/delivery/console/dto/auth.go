@@ -3,6 +3,7 @@ package dto
 import (
 	"errors"
 	"fmt"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"net/mail"
 	"regexp"
 	"strings"
@@ -91,3 +92,44 @@ func InputLogInDTO(dto *LogInDTO) {
 	fmt.Print("Password: ")
 	fmt.Scan(&dto.Password)
 }
+
+func InputTwoFactorCode() string {
+	var code string
+	fmt.Print("Authenticator or recovery code: ")
+	fmt.Scan(&code)
+	return code
+}
+
+type SessionDTO struct {
+	ID         string
+	Device     string
+	UserAgent  string
+	IP         string
+	CreatedAt  string
+	LastUsedAt string
+	Current    bool
+}
+
+func NewSessionDTO(session domain.Session, current bool) SessionDTO {
+	return SessionDTO{
+		ID:         session.ID.String(),
+		Device:     session.Metadata.DeviceLabel,
+		UserAgent:  session.Metadata.UserAgent,
+		IP:         session.Metadata.IP,
+		CreatedAt:  session.CreatedAt.Format("2006-01-02 15:04:05"),
+		LastUsedAt: session.LastUsedAt.Format("2006-01-02 15:04:05"),
+		Current:    current,
+	}
+}
+
+func (s SessionDTO) Print() {
+	fmt.Println("ID:", s.ID)
+	fmt.Println("Device:", s.Device)
+	fmt.Println("User agent:", s.UserAgent)
+	fmt.Println("IP:", s.IP)
+	fmt.Println("Created at:", s.CreatedAt)
+	fmt.Println("Last used at:", s.LastUsedAt)
+	if s.Current {
+		fmt.Println("(current session)")
+	}
+}
@@ -52,7 +52,7 @@ func (h *Handler) verifyAuth(c *Console) error {
 }
 
 func (h *Handler) verifyUserAuth(c *Console) error {
-	if c.UserRole != domain.UserRole {
+	if !domain.IsUserAccountRole(c.UserRole) {
 		return errors.New("unauthorized")
 	}
 
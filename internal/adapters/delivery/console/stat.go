@@ -19,7 +19,11 @@ func (h *Handler) Listen(c *Console) {
 		return
 	}
 
-	err = h.statService.Add(context.Background(), c.UserID, id)
+	var seconds int
+	fmt.Print("Listened seconds: ")
+	fmt.Scan(&seconds)
+
+	err = h.statService.Add(context.Background(), c.UserID, id, seconds)
 	if err != nil {
 		fmt.Println("listen error")
 	}
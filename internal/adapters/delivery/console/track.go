@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/console/dto"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 )
 
@@ -51,7 +52,7 @@ func (h *Handler) GetAllTrack(c *Console) {
 		return
 	}
 
-	tracks, err := h.trackService.GetAll(context.Background())
+	tracks, err := h.trackService.GetAll(context.Background(), domain.TrackSortDefault)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -123,7 +124,7 @@ func (h *Handler) GetUserFavoritesTrack(c *Console) {
 		return
 	}
 
-	tracks, err := h.trackService.GetUserFavorites(context.Background(), c.UserID)
+	tracks, err := h.trackService.GetUserFavorites(context.Background(), c.UserID, domain.TrackSortDefault)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -168,7 +169,7 @@ func (h *Handler) GetByAlbumIDTrack(c *Console) {
 		return
 	}
 
-	tracks, err := h.trackService.GetByAlbumID(context.Background(), id)
+	tracks, err := h.trackService.GetByAlbumID(context.Background(), id, domain.TrackSortDefault)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -193,7 +194,7 @@ func (h *Handler) GetByMusicianIDTrack(c *Console) {
 		return
 	}
 
-	tracks, err := h.trackService.GetByMusicianID(context.Background(), id)
+	tracks, err := h.trackService.GetByMusicianID(context.Background(), id, domain.TrackSortDefault)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -212,7 +213,7 @@ func (h *Handler) GetOwnTrack(c *Console) {
 		return
 	}
 
-	tracks, err := h.trackService.GetOwn(context.Background(), c.UserID)
+	tracks, err := h.trackService.GetOwn(context.Background(), c.UserID, domain.TrackSortDefault)
 	if err != nil {
 		fmt.Println(err)
 		return
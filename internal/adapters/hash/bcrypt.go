@@ -0,0 +1,64 @@
+package hash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const bcryptMaxPasswordLength = 72
+
+// BcryptPasswordProvider keeps the cost and salt inside the standard
+// $2a$<cost>$<salt+hash> string produced by bcrypt.
+type BcryptPasswordProvider struct {
+	cost int
+}
+
+func NewBcryptPasswordProvider(cost int) *BcryptPasswordProvider {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+
+	return &BcryptPasswordProvider{cost: cost}
+}
+
+func (h *BcryptPasswordProvider) EncodePassword(password string) domain.SaltedPassword {
+	// bcrypt only uses the first 72 bytes and refuses longer input, so the
+	// password is cut the same way the comparison will see it.
+	passwordBytes := []byte(password)
+	if len(passwordBytes) > bcryptMaxPasswordLength {
+		passwordBytes = passwordBytes[:bcryptMaxPasswordLength]
+	}
+
+	hash, err := bcrypt.GenerateFromPassword(passwordBytes, h.cost)
+	if err != nil {
+		panic(fmt.Sprintf("failed to hash password: %v", err))
+	}
+
+	return domain.SaltedPassword{
+		HashPassword: string(hash),
+	}
+}
+
+func (h *BcryptPasswordProvider) ComparePasswordWithHash(password string, saltedPassword domain.SaltedPassword) bool {
+	return comparePassword(password, saltedPassword)
+}
+
+func (h *BcryptPasswordProvider) NeedsRehash(saltedPassword domain.SaltedPassword) bool {
+	if !isBcryptHash(saltedPassword.HashPassword) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(saltedPassword.HashPassword))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
@@ -3,10 +3,15 @@ package hash
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	"github.com/hanoys/sigma-music/internal/domain"
+	"strings"
 	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// HashPasswordProvider is the legacy single round SHA-256 provider. New
+// deployments should use Argon2idPasswordProvider or BcryptPasswordProvider.
 type HashPasswordProvider struct {
 }
 
@@ -27,7 +32,29 @@ func (h *HashPasswordProvider) EncodePassword(password string) domain.SaltedPass
 		Salt:         salt,
 	}
 }
+
 func (h *HashPasswordProvider) ComparePasswordWithHash(password string, saltedPassword domain.SaltedPassword) bool {
+	return compareLegacy(password, saltedPassword)
+}
+
+func (h *HashPasswordProvider) NeedsRehash(saltedPassword domain.SaltedPassword) bool {
+	return false
+}
+
+func compareLegacy(password string, saltedPassword domain.SaltedPassword) bool {
 	passwordHash := sha256.Sum256([]byte(password + saltedPassword.Salt))
 	return hex.EncodeToString(passwordHash[:]) == saltedPassword.HashPassword
 }
+
+// comparePassword checks the password against a hash in any supported format,
+// so switching the configured provider never locks existing accounts out.
+func comparePassword(password string, saltedPassword domain.SaltedPassword) bool {
+	switch {
+	case strings.HasPrefix(saltedPassword.HashPassword, argon2idPrefix):
+		return compareArgon2id(password, saltedPassword.HashPassword)
+	case isBcryptHash(saltedPassword.HashPassword):
+		return bcrypt.CompareHashAndPassword([]byte(saltedPassword.HashPassword), []byte(password)) == nil
+	default:
+		return compareLegacy(password, saltedPassword)
+	}
+}
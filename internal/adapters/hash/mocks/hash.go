@@ -48,6 +48,24 @@ func (_m *HashPasswordProvider) EncodePassword(password string) domain.SaltedPas
 	return r0
 }
 
+// NeedsRehash provides a mock function with given fields: saltedPassword
+func (_m *HashPasswordProvider) NeedsRehash(saltedPassword domain.SaltedPassword) bool {
+	ret := _m.Called(saltedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NeedsRehash")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(domain.SaltedPassword) bool); ok {
+		r0 = rf(saltedPassword)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewHashPasswordProvider creates a new instance of HashPasswordProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewHashPasswordProvider(t interface {
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/hanoys/sigma-music/internal/adapters/hash"
+	"github.com/stretchr/testify/require"
+)
+
+func testArgon2idParams() hash.Argon2idParams {
+	params := hash.DefaultArgon2idParams()
+	params.Memory = 1024
+	params.Iterations = 1
+	return params
+}
+
+func TestArgon2idPasswordProvider(t *testing.T) {
+	provider := hash.NewArgon2idPasswordProvider(testArgon2idParams())
+
+	t.Run("test encode and compare", func(t *testing.T) {
+		salted := provider.EncodePassword("password")
+
+		require.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=2\$`, salted.HashPassword)
+		require.True(t, provider.ComparePasswordWithHash("password", salted))
+		require.False(t, provider.ComparePasswordWithHash("wrong", salted))
+		require.False(t, provider.NeedsRehash(salted))
+	})
+
+	t.Run("test unique salt", func(t *testing.T) {
+		require.NotEqual(t, provider.EncodePassword("password"), provider.EncodePassword("password"))
+	})
+
+	t.Run("test changed params need rehash", func(t *testing.T) {
+		params := testArgon2idParams()
+		params.Iterations = 2
+		stronger := hash.NewArgon2idPasswordProvider(params)
+		salted := provider.EncodePassword("password")
+
+		require.True(t, stronger.ComparePasswordWithHash("password", salted))
+		require.True(t, stronger.NeedsRehash(salted))
+	})
+
+	t.Run("test legacy hash", func(t *testing.T) {
+		salted := hash.NewHashPasswordProvider().EncodePassword("password")
+
+		require.True(t, provider.ComparePasswordWithHash("password", salted))
+		require.False(t, provider.ComparePasswordWithHash("wrong", salted))
+		require.True(t, provider.NeedsRehash(salted))
+	})
+}
+
+func TestBcryptPasswordProvider(t *testing.T) {
+	provider := hash.NewBcryptPasswordProvider(4)
+
+	t.Run("test encode and compare", func(t *testing.T) {
+		salted := provider.EncodePassword("password")
+
+		require.True(t, provider.ComparePasswordWithHash("password", salted))
+		require.False(t, provider.ComparePasswordWithHash("wrong", salted))
+		require.False(t, provider.NeedsRehash(salted))
+	})
+
+	t.Run("test changed cost needs rehash", func(t *testing.T) {
+		salted := provider.EncodePassword("password")
+
+		require.True(t, hash.NewBcryptPasswordProvider(5).NeedsRehash(salted))
+	})
+
+	t.Run("test argon2id hash", func(t *testing.T) {
+		salted := hash.NewArgon2idPasswordProvider(testArgon2idParams()).EncodePassword("password")
+
+		require.True(t, provider.ComparePasswordWithHash("password", salted))
+		require.True(t, provider.NeedsRehash(salted))
+	})
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+)
+
+// FileMailer writes every mail into its own .eml file instead of sending it.
+// It is meant for local development, where links from the mails are opened
+// straight from the directory.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir string, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, mail domain.Mail) error {
+	err := os.MkdirAll(m.dir, 0755)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalMailer, err)
+	}
+
+	now := time.Now()
+	recipient := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, mail.To)
+	path := filepath.Join(m.dir, fmt.Sprintf("%d-%s.eml", now.UnixNano(), recipient))
+
+	err = os.WriteFile(path, buildMessage(m.from, mail, now), 0644)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalMailer, err)
+	}
+
+	return nil
+}
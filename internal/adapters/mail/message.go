@@ -0,0 +1,26 @@
+package mail
+
+import (
+	"bytes"
+	"mime"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+// buildMessage renders a plain text RFC 5322 message. The subject is
+// Q-encoded, which also keeps line breaks out of the headers.
+func buildMessage(from string, mail domain.Mail, date time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + mail.To + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", mail.Subject) + "\r\n")
+	buf.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(mail.Body)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"context"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer delivers mail through an SMTP relay. The connection is upgraded
+// with STARTTLS whenever the server offers it, and credentials are only sent
+// over TLS or to localhost.
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail domain.Mail) error {
+	sender, err := netmail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalMailer, err)
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	err = smtp.SendMail(net.JoinHostPort(m.cfg.Host, m.cfg.Port), auth, sender.Address,
+		[]string{mail.To}, buildMessage(m.cfg.From, mail, time.Now()))
+	if err != nil {
+		return util.WrapError(ports.ErrInternalMailer, err)
+	}
+
+	return nil
+}
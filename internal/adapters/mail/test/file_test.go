@@ -0,0 +1,59 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hanoys/sigma-music/internal/adapters/mail"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("test mail written", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "mail")
+		mailer := mail.NewFileMailer(dir, "no-reply@sigma-music.local")
+
+		err := mailer.Send(ctx, domain.Mail{
+			To:      "test@mail.com",
+			Subject: "Confirm your email",
+			Body:    "http://localhost/verify-email?token=abc",
+		})
+		require.NoError(t, err)
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.True(t, strings.HasSuffix(files[0].Name(), "-test@mail.com.eml"))
+
+		buf, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+		require.NoError(t, err)
+		message := string(buf)
+		require.Contains(t, message, "From: no-reply@sigma-music.local\r\n")
+		require.Contains(t, message, "To: test@mail.com\r\n")
+		require.Contains(t, message, "Subject: Confirm your email\r\n")
+		require.True(t, strings.HasSuffix(message, "\r\n\r\nhttp://localhost/verify-email?token=abc"))
+	})
+
+	t.Run("test subject can't inject headers", func(t *testing.T) {
+		dir := t.TempDir()
+		mailer := mail.NewFileMailer(dir, "no-reply@sigma-music.local")
+
+		err := mailer.Send(ctx, domain.Mail{
+			To:      "test@mail.com",
+			Subject: "Hello\r\nBcc: victim@mail.com",
+		})
+		require.NoError(t, err)
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		buf, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+		require.NoError(t, err)
+		require.NotContains(t, string(buf), "\r\nBcc:")
+	})
+}
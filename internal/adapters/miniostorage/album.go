@@ -34,3 +34,8 @@ func (a *AlbumImageStorage) UploadImage(ctx context.Context, image io.Reader, id
 
 	return fileURL, nil
 }
+
+func (a *AlbumImageStorage) DeleteImage(ctx context.Context, id string) error {
+	object_name := id + "_image.jpg"
+	return a.client.RemoveObject(ctx, a.bucketName, object_name, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,47 @@
+package miniostorage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// DataExportStorage keeps export archives in a private bucket. Links are
+// signed by a client set up for the public endpoint, since the signature
+// covers the host the browser connects to.
+type DataExportStorage struct {
+	client     *minio.Client
+	signer     *minio.Client
+	bucketName string
+}
+
+func NewDataExportStorage(client *minio.Client, signer *minio.Client, bucketName string) *DataExportStorage {
+	return &DataExportStorage{client, signer, bucketName}
+}
+
+func (e *DataExportStorage) PutArchive(ctx context.Context, archive io.Reader, size int64, id string) error {
+	object_name := id + ".zip"
+	_, err := e.client.PutObject(ctx, e.bucketName, object_name, archive, size,
+		minio.PutObjectOptions{ContentType: "application/zip"})
+	return err
+}
+
+func (e *DataExportStorage) SignedURL(ctx context.Context, id string, ttl time.Duration) (url.URL, error) {
+	object_name := id + ".zip"
+	params := url.Values{}
+	params.Set("response-content-disposition", `attachment; filename="sigma-music-export.zip"`)
+	fileURL, err := e.signer.PresignedGetObject(ctx, e.bucketName, object_name, ttl, params)
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	return *fileURL, nil
+}
+
+func (e *DataExportStorage) DeleteArchive(ctx context.Context, id string) error {
+	object_name := id + ".zip"
+	return e.client.RemoveObject(ctx, e.bucketName, object_name, minio.RemoveObjectOptions{})
+}
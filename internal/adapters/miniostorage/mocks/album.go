@@ -0,0 +1,77 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	url "net/url"
+)
+
+// AlbumImageStorage is an autogenerated mock type for the IAlbumImageStorage type
+type AlbumImageStorage struct {
+	mock.Mock
+}
+
+// DeleteImage provides a mock function with given fields: ctx, id
+func (_m *AlbumImageStorage) DeleteImage(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UploadImage provides a mock function with given fields: ctx, image, id
+func (_m *AlbumImageStorage) UploadImage(ctx context.Context, image io.Reader, id string) (url.URL, error) {
+	ret := _m.Called(ctx, image, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadImage")
+	}
+
+	var r0 url.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string) (url.URL, error)); ok {
+		return rf(ctx, image, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string) url.URL); ok {
+		r0 = rf(ctx, image, id)
+	} else {
+		r0 = ret.Get(0).(url.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, string) error); ok {
+		r1 = rf(ctx, image, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAlbumImageStorage creates a new instance of AlbumImageStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAlbumImageStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AlbumImageStorage {
+	mock := &AlbumImageStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
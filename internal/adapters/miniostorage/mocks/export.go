@@ -0,0 +1,97 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	url "net/url"
+)
+
+// DataExportStorage is an autogenerated mock type for the IDataExportStorage type
+type DataExportStorage struct {
+	mock.Mock
+}
+
+// DeleteArchive provides a mock function with given fields: ctx, id
+func (_m *DataExportStorage) DeleteArchive(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteArchive")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutArchive provides a mock function with given fields: ctx, archive, size, id
+func (_m *DataExportStorage) PutArchive(ctx context.Context, archive io.Reader, size int64, id string) error {
+	ret := _m.Called(ctx, archive, size, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutArchive")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, int64, string) error); ok {
+		r0 = rf(ctx, archive, size, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignedURL provides a mock function with given fields: ctx, id, ttl
+func (_m *DataExportStorage) SignedURL(ctx context.Context, id string, ttl time.Duration) (url.URL, error) {
+	ret := _m.Called(ctx, id, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignedURL")
+	}
+
+	var r0 url.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (url.URL, error)); ok {
+		return rf(ctx, id, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) url.URL); ok {
+		r0 = rf(ctx, id, ttl)
+	} else {
+		r0 = ret.Get(0).(url.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, id, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataExportStorage creates a new instance of DataExportStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataExportStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataExportStorage {
+	mock := &DataExportStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
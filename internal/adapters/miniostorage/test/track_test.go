@@ -5,6 +5,7 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/miniostorage"
+	"github.com/hanoys/sigma-music/internal/ports"
 	"github.com/stretchr/testify/require"
 	"io"
 	"net/http"
@@ -52,7 +53,10 @@ func TestTrackStorage(t *testing.T) {
 			t.Errorf("failed to read file %s: %s", testFilename, err)
 		}
 
-		fileURL, err := store.PutTrack(ctx, uuid.New().String(), bytes.NewReader(data))
+		fileURL, err := store.PutTrack(ctx, ports.PutTrackReq{
+			TrackID:   uuid.New().String(),
+			TrackBLOB: bytes.NewReader(data),
+		})
 		if err != nil {
 			t.Errorf("failed to save file to minio: %v", err)
 		}
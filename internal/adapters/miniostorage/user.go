@@ -0,0 +1,41 @@
+package miniostorage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+type UserAvatarStorage struct {
+	client     *minio.Client
+	bucketName string
+}
+
+func NewUserAvatarStorage(client *minio.Client, bucketName string) *UserAvatarStorage {
+	return &UserAvatarStorage{client, bucketName}
+}
+
+func (u *UserAvatarStorage) UploadImage(ctx context.Context, image io.Reader, id string) (url.URL, error) {
+	object_name := id + "_avatar.jpg"
+	_, err := u.client.PutObject(ctx, u.bucketName, object_name, image, -1, minio.PutObjectOptions{})
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	fileURL := url.URL{
+		Scheme: "http",
+		Host:   strings.Replace(u.client.EndpointURL().Host, "minio", "localhost", 1),
+		Path:   filepath.Join(u.bucketName, object_name),
+	}
+
+	return fileURL, nil
+}
+
+func (u *UserAvatarStorage) DeleteImage(ctx context.Context, id string) error {
+	object_name := id + "_avatar.jpg"
+	return u.client.RemoveObject(ctx, u.bucketName, object_name, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OIDCStateStorage is an autogenerated mock type for the IOIDCStateStorage type
+type OIDCStateStorage struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, state, loginState, ttl
+func (_m *OIDCStateStorage) Save(ctx context.Context, state string, loginState domain.OIDCLoginState, ttl time.Duration) error {
+	ret := _m.Called(ctx, state, loginState, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OIDCLoginState, time.Duration) error); ok {
+		r0 = rf(ctx, state, loginState, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Take provides a mock function with given fields: ctx, state
+func (_m *OIDCStateStorage) Take(ctx context.Context, state string) (domain.OIDCLoginState, error) {
+	ret := _m.Called(ctx, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Take")
+	}
+
+	var r0 domain.OIDCLoginState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.OIDCLoginState, error)); ok {
+		return rf(ctx, state)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.OIDCLoginState); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Get(0).(domain.OIDCLoginState)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, state)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCStateStorage creates a new instance of OIDCStateStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCStateStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCStateStorage {
+	mock := &OIDCStateStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
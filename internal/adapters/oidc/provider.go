@@ -0,0 +1,216 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+type ProviderConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are requested in addition to openid.
+	Scopes []string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Provider talks to a single OpenID Connect provider. The discovery document
+// is fetched on first use, the signing keys whenever an unknown kid shows up.
+type Provider struct {
+	cfg        ProviderConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]interface{}
+}
+
+func NewProvider(cfg ProviderConfig) *Provider {
+	return &Provider{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, p.cfg.Scopes...), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return d.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (domain.OIDCClaims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return domain.OIDCClaims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrInternalOIDCProvider, err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var token tokenResponse
+	err = p.doJSON(request, &token)
+	if err != nil {
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCExchange, err)
+	}
+
+	if token.IDToken == "" {
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCExchange, fmt.Errorf("no id_token in response"))
+	}
+
+	return p.verifyIDToken(ctx, d, token.IDToken, nonce)
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, d *discovery, rawToken string, nonce string) (domain.OIDCClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		return p.verificationKey(ctx, d, token)
+	})
+	if err != nil {
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCInvalidIDToken, err)
+	}
+
+	// ParseWithClaims already checked exp, iat and nbf.
+	switch {
+	case claims.Issuer != d.Issuer:
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCInvalidIDToken, fmt.Errorf("issuer %q", claims.Issuer))
+	case !claims.VerifyAudience(p.cfg.ClientID, true):
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCInvalidIDToken, fmt.Errorf("audience %v", claims.Audience))
+	case claims.ExpiresAt == nil:
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCInvalidIDToken, fmt.Errorf("no expiration"))
+	case claims.Nonce != nonce:
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCInvalidIDToken, fmt.Errorf("nonce mismatch"))
+	case claims.Subject == "":
+		return domain.OIDCClaims{}, util.WrapError(ports.ErrOIDCInvalidIDToken, fmt.Errorf("no subject"))
+	}
+
+	return domain.OIDCClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalOIDCProvider, err)
+	}
+
+	var d discovery
+	err = p.doJSON(request, &d)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalOIDCProvider, err)
+	}
+
+	// OpenID Connect Discovery 1.0, section 4.3.
+	if d.Issuer != p.cfg.Issuer {
+		return nil, util.WrapError(ports.ErrInternalOIDCProvider,
+			fmt.Errorf("discovery issuer %q does not match %q", d.Issuer, p.cfg.Issuer))
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+func (p *Provider) doJSON(request *http.Request, v interface{}) error {
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s: %s", request.Method, request.URL.Path, response.Status, body)
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// codeChallenge is the S256 PKCE challenge of RFC 7636.
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
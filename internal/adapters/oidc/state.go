@@ -0,0 +1,54 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+const stateKeyPrefix = "oidc_state:"
+
+type RedisStateStorage struct {
+	redisClient *redis.Client
+}
+
+func NewRedisStateStorage(redisClient *redis.Client) *RedisStateStorage {
+	return &RedisStateStorage{redisClient: redisClient}
+}
+
+func (s *RedisStateStorage) Save(ctx context.Context, state string, loginState domain.OIDCLoginState, ttl time.Duration) error {
+	value, err := json.Marshal(loginState)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalOIDCStateStorage, err)
+	}
+
+	err = s.redisClient.Set(ctx, stateKeyPrefix+state, value, ttl).Err()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalOIDCStateStorage, err)
+	}
+
+	return nil
+}
+
+func (s *RedisStateStorage) Take(ctx context.Context, state string) (domain.OIDCLoginState, error) {
+	value, err := s.redisClient.GetDel(ctx, stateKeyPrefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.OIDCLoginState{}, ports.ErrOIDCInvalidState
+	} else if err != nil {
+		return domain.OIDCLoginState{}, util.WrapError(ports.ErrInternalOIDCStateStorage, err)
+	}
+
+	var loginState domain.OIDCLoginState
+	err = json.Unmarshal(value, &loginState)
+	if err != nil {
+		return domain.OIDCLoginState{}, util.WrapError(ports.ErrInternalOIDCStateStorage, err)
+	}
+
+	return loginState, nil
+}
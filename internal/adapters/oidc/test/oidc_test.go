@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hanoys/sigma-music/internal/adapters/oidc"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func newProvider(idp *stubIdP) *oidc.Provider {
+	return oidc.NewProvider(oidc.ProviderConfig{
+		Issuer:       idp.URL,
+		ClientID:     stubClientID,
+		ClientSecret: stubClientSecret,
+		RedirectURL:  stubRedirectURL,
+		Scopes:       []string{"email", "profile"},
+	})
+}
+
+func TestExchange(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := newProvider(idp)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL)
+	require.Equal(t, "state", state)
+
+	claims, err := provider.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier", "nonce")
+	require.NoError(t, err)
+	require.Equal(t, idp.subject, claims.Subject)
+	require.Equal(t, idp.email, claims.Email)
+	require.True(t, claims.EmailVerified)
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := newProvider(idp)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+	require.NoError(t, err)
+	code, _ := idp.authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), code, "another-verifier-another-verifier-another", "nonce")
+	require.ErrorIs(t, err, ports.ErrOIDCExchange)
+}
+
+func TestExchangeNonceMismatch(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := newProvider(idp)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+	require.NoError(t, err)
+	code, _ := idp.authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier", "other")
+	require.ErrorIs(t, err, ports.ErrOIDCInvalidIDToken)
+}
+
+func TestExchangeWrongAudience(t *testing.T) {
+	idp := newStubIdP(t)
+	idp.audience = "another-client"
+	provider := newProvider(idp)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+	require.NoError(t, err)
+	code, _ := idp.authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier", "nonce")
+	require.ErrorIs(t, err, ports.ErrOIDCInvalidIDToken)
+}
+
+func TestExchangeWrongIssuer(t *testing.T) {
+	idp := newStubIdP(t)
+	idp.issuer = "https://evil.example.com"
+	provider := newProvider(idp)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+	require.NoError(t, err)
+	code, _ := idp.authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier", "nonce")
+	require.ErrorIs(t, err, ports.ErrOIDCInvalidIDToken)
+}
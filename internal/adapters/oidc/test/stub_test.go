@@ -0,0 +1,154 @@
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	stubClientID     = "sigma-music"
+	stubClientSecret = "secret"
+	stubRedirectURL  = "http://localhost/callback"
+	stubKeyID        = "stub-key"
+)
+
+type stubAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+// stubIdP is a minimal OpenID Connect provider: discovery, JWKS and a token
+// endpoint that checks the PKCE verifier against the authorization request.
+type stubIdP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu             sync.Mutex
+	authorizations map[string]stubAuthorization
+
+	subject  string
+	email    string
+	audience string
+	issuer   string
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{
+		key:            key,
+		authorizations: make(map[string]stubAuthorization),
+		subject:        "external-subject",
+		email:          "staff@label.com",
+		audience:       stubClientID,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	idp.issuer = idp.URL
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+// authorize plays the user agent: it accepts the authorization URL and
+// returns the code the provider would redirect back with.
+func (idp *stubIdP) authorize(t *testing.T, authURL string) (code string, state string) {
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, stubClientID, query.Get("client_id"))
+	require.Equal(t, stubRedirectURL, query.Get("redirect_uri"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	code = uuid.NewString()
+	idp.mu.Lock()
+	idp.authorizations[code] = stubAuthorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+	}
+	idp.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (idp *stubIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 idp.URL,
+		"authorization_endpoint": idp.URL + "/authorize",
+		"token_endpoint":         idp.URL + "/token",
+		"jwks_uri":               idp.URL + "/jwks",
+	})
+}
+
+func (idp *stubIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": stubKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (idp *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != stubClientID || clientSecret != stubClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	code := r.PostFormValue("code")
+	idp.mu.Lock()
+	authorization, ok := idp.authorizations[code]
+	delete(idp.authorizations, code)
+	idp.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            idp.issuer,
+		"sub":            idp.subject,
+		"aud":            idp.audience,
+		"exp":            now.Add(time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          authorization.nonce,
+		"email":          idp.email,
+		"email_verified": true,
+	})
+	token.Header["kid"] = stubKeyID
+	idToken, _ := token.SignedString(idp.key)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
@@ -0,0 +1,186 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+)
+
+var errIntentSettled = errors.New("payment intent is already settled")
+
+type webhookEvent struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intent_id"`
+}
+
+// FakeGateway is an in-memory payment provider for tests and local runs.
+// Nobody pays on its checkout page; Pay and Decline settle an intent and
+// return the webhook request the provider would send, signed like a real one.
+type FakeGateway struct {
+	secret      []byte
+	checkoutURL string
+
+	mu      sync.Mutex
+	intents map[string]*domain.PaymentIntent
+	keys    map[string]string
+	refunds map[string]domain.PaymentRefund
+}
+
+func NewFakeGateway(webhookSecret string, checkoutURL string) *FakeGateway {
+	return &FakeGateway{
+		secret:      []byte(webhookSecret),
+		checkoutURL: strings.TrimSuffix(checkoutURL, "/"),
+		intents:     make(map[string]*domain.PaymentIntent),
+		keys:        make(map[string]string),
+		refunds:     make(map[string]domain.PaymentRefund),
+	}
+}
+
+func (g *FakeGateway) CreateIntent(ctx context.Context, payload domain.PaymentPayload,
+	idempotencyKey string) (domain.PaymentIntent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if intentID, ok := g.keys[idempotencyKey]; ok {
+		intent := g.intents[intentID]
+		if intent.UserID != payload.UserID || intent.Amount != payload.PaymentSum {
+			return domain.PaymentIntent{}, ports.ErrPaymentIdempotencyConflict
+		}
+		return *intent, nil
+	}
+
+	id, err := newID("pi")
+	if err != nil {
+		return domain.PaymentIntent{}, err
+	}
+
+	intent := &domain.PaymentIntent{
+		ID:          id,
+		UserID:      payload.UserID,
+		Amount:      payload.PaymentSum,
+		Status:      domain.PaymentIntentPending,
+		CheckoutURL: g.checkoutURL + "/" + id,
+	}
+	g.intents[id] = intent
+	g.keys[idempotencyKey] = id
+
+	return *intent, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, intentID string, amount int64,
+	idempotencyKey string) (domain.PaymentRefund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if refund, ok := g.refunds[idempotencyKey]; ok {
+		if refund.IntentID != intentID || refund.Amount != amount {
+			return domain.PaymentRefund{}, ports.ErrPaymentIdempotencyConflict
+		}
+		return refund, nil
+	}
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return domain.PaymentRefund{}, ports.ErrPaymentIntentNotFound
+	}
+
+	if intent.Status != domain.PaymentIntentSucceeded || amount <= 0 || amount > intent.Amount {
+		return domain.PaymentRefund{}, ports.ErrPaymentNotRefundable
+	}
+
+	id, err := newID("re")
+	if err != nil {
+		return domain.PaymentRefund{}, err
+	}
+
+	refund := domain.PaymentRefund{
+		ID:       id,
+		IntentID: intentID,
+		Amount:   amount,
+	}
+	intent.Status = domain.PaymentIntentRefunded
+	g.refunds[idempotencyKey] = refund
+
+	return refund, nil
+}
+
+func (g *FakeGateway) ParseWebhook(payload []byte, signature string) (domain.PaymentEvent, error) {
+	err := VerifySignature(g.secret, payload, signature, time.Now())
+	if err != nil {
+		return domain.PaymentEvent{}, err
+	}
+
+	var event webhookEvent
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		return domain.PaymentEvent{}, util.WrapError(ports.ErrPaymentInvalidSignature, err)
+	}
+
+	return domain.PaymentEvent{
+		ID:       event.ID,
+		Type:     domain.PaymentEventType(event.Type),
+		IntentID: event.IntentID,
+	}, nil
+}
+
+// Pay completes a pending intent as if the user paid on the checkout page.
+func (g *FakeGateway) Pay(intentID string) ([]byte, string, error) {
+	return g.settle(intentID, domain.PaymentIntentSucceeded, domain.PaymentEventSucceeded)
+}
+
+// Decline fails a pending intent as if the card was declined.
+func (g *FakeGateway) Decline(intentID string) ([]byte, string, error) {
+	return g.settle(intentID, domain.PaymentIntentFailed, domain.PaymentEventFailed)
+}
+
+func (g *FakeGateway) settle(intentID string, status domain.PaymentIntentStatus,
+	eventType domain.PaymentEventType) ([]byte, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return nil, "", ports.ErrPaymentIntentNotFound
+	}
+
+	if intent.Status != domain.PaymentIntentPending {
+		return nil, "", util.WrapError(ports.ErrInternalPaymentGateway, errIntentSettled)
+	}
+	intent.Status = status
+
+	id, err := newID("evt")
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload, err := json.Marshal(webhookEvent{
+		ID:       id,
+		Type:     string(eventType),
+		IntentID: intentID,
+	})
+	if err != nil {
+		return nil, "", util.WrapError(ports.ErrInternalPaymentGateway, err)
+	}
+
+	return payload, Sign(g.secret, payload, time.Now()), nil
+}
+
+func newID(prefix string) (string, error) {
+	buf := make([]byte, 12)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", util.WrapError(ports.ErrInternalPaymentGateway, err)
+	}
+
+	return prefix + "_" + hex.EncodeToString(buf), nil
+}
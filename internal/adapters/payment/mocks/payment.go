@@ -0,0 +1,113 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PaymentGateway is an autogenerated mock type for the IPaymentGateway type
+type PaymentGateway struct {
+	mock.Mock
+}
+
+// CreateIntent provides a mock function with given fields: ctx, payload, idempotencyKey
+func (_m *PaymentGateway) CreateIntent(ctx context.Context, payload domain.PaymentPayload, idempotencyKey string) (domain.PaymentIntent, error) {
+	ret := _m.Called(ctx, payload, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIntent")
+	}
+
+	var r0 domain.PaymentIntent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PaymentPayload, string) (domain.PaymentIntent, error)); ok {
+		return rf(ctx, payload, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PaymentPayload, string) domain.PaymentIntent); ok {
+		r0 = rf(ctx, payload, idempotencyKey)
+	} else {
+		r0 = ret.Get(0).(domain.PaymentIntent)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PaymentPayload, string) error); ok {
+		r1 = rf(ctx, payload, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseWebhook provides a mock function with given fields: payload, signature
+func (_m *PaymentGateway) ParseWebhook(payload []byte, signature string) (domain.PaymentEvent, error) {
+	ret := _m.Called(payload, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseWebhook")
+	}
+
+	var r0 domain.PaymentEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]byte, string) (domain.PaymentEvent, error)); ok {
+		return rf(payload, signature)
+	}
+	if rf, ok := ret.Get(0).(func([]byte, string) domain.PaymentEvent); ok {
+		r0 = rf(payload, signature)
+	} else {
+		r0 = ret.Get(0).(domain.PaymentEvent)
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte, string) error); ok {
+		r1 = rf(payload, signature)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refund provides a mock function with given fields: ctx, intentID, amount, idempotencyKey
+func (_m *PaymentGateway) Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) (domain.PaymentRefund, error) {
+	ret := _m.Called(ctx, intentID, amount, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 domain.PaymentRefund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) (domain.PaymentRefund, error)); ok {
+		return rf(ctx, intentID, amount, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) domain.PaymentRefund); ok {
+		r0 = rf(ctx, intentID, amount, idempotencyKey)
+	} else {
+		r0 = ret.Get(0).(domain.PaymentRefund)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string) error); ok {
+		r1 = rf(ctx, intentID, amount, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPaymentGateway creates a new instance of PaymentGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentGateway(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentGateway {
+	mock := &PaymentGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
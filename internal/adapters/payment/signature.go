@@ -0,0 +1,82 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+)
+
+// SignatureTolerance is how old a signed webhook may be. Older ones are
+// rejected even with a valid signature, so captured requests can't be
+// replayed later.
+const SignatureTolerance = 5 * time.Minute
+
+var errSignatureFormat = errors.New("malformed signature header")
+
+// Sign returns the signature header for a webhook body, formatted as
+// t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">.
+func Sign(secret []byte, payload []byte, timestamp time.Time) string {
+	unix := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", unix, hex.EncodeToString(computeMAC(secret, payload, unix)))
+}
+
+func VerifySignature(secret []byte, payload []byte, header string, now time.Time) error {
+	var unix int64
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return util.WrapError(ports.ErrPaymentInvalidSignature, errSignatureFormat)
+		}
+
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return util.WrapError(ports.ErrPaymentInvalidSignature, err)
+			}
+			unix = parsed
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return util.WrapError(ports.ErrPaymentInvalidSignature, err)
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	if unix == 0 || len(signatures) == 0 {
+		return util.WrapError(ports.ErrPaymentInvalidSignature, errSignatureFormat)
+	}
+
+	age := now.Sub(time.Unix(unix, 0))
+	if age > SignatureTolerance || age < -SignatureTolerance {
+		return ports.ErrPaymentInvalidSignature
+	}
+
+	// Several v1 values are allowed so that the secret can be rotated.
+	expected := computeMAC(secret, payload, unix)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+
+	return ports.ErrPaymentInvalidSignature
+}
+
+func computeMAC(secret []byte, payload []byte, unix int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(unix, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/payment"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+const webhookSecret = "webhook-secret"
+
+func TestSignature(t *testing.T) {
+	secret := []byte(webhookSecret)
+	payload := []byte(`{"id":"evt_1","type":"payment.succeeded","intent_id":"pi_1"}`)
+	now := time.Now()
+
+	t.Run("test valid signature", func(t *testing.T) {
+		header := payment.Sign(secret, payload, now)
+		require.NoError(t, payment.VerifySignature(secret, payload, header, now))
+	})
+
+	t.Run("test tampered payload", func(t *testing.T) {
+		header := payment.Sign(secret, payload, now)
+		tampered := []byte(`{"id":"evt_1","type":"payment.succeeded","intent_id":"pi_2"}`)
+		err := payment.VerifySignature(secret, tampered, header, now)
+		require.ErrorIs(t, err, ports.ErrPaymentInvalidSignature)
+	})
+
+	t.Run("test wrong secret", func(t *testing.T) {
+		header := payment.Sign([]byte("other-secret"), payload, now)
+		err := payment.VerifySignature(secret, payload, header, now)
+		require.ErrorIs(t, err, ports.ErrPaymentInvalidSignature)
+	})
+
+	t.Run("test replayed after tolerance", func(t *testing.T) {
+		signedAt := now.Add(-payment.SignatureTolerance - time.Minute)
+		header := payment.Sign(secret, payload, signedAt)
+		err := payment.VerifySignature(secret, payload, header, now)
+		require.ErrorIs(t, err, ports.ErrPaymentInvalidSignature)
+	})
+
+	t.Run("test one of rotated secrets", func(t *testing.T) {
+		old := payment.Sign([]byte("old-secret"), payload, now)
+		current := payment.Sign(secret, payload, now)
+		header := fmt.Sprintf("%s,%s", old, current[len(fmt.Sprintf("t=%d,", now.Unix())):])
+		require.NoError(t, payment.VerifySignature(secret, payload, header, now))
+	})
+
+	t.Run("test malformed header", func(t *testing.T) {
+		err := payment.VerifySignature(secret, payload, "garbage", now)
+		require.ErrorIs(t, err, ports.ErrPaymentInvalidSignature)
+	})
+}
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+	payload := domain.PaymentPayload{UserID: uuid.New(), PaymentSum: 29900}
+
+	t.Run("test create intent idempotent", func(t *testing.T) {
+		gateway := payment.NewFakeGateway(webhookSecret, "http://localhost/checkout")
+
+		first, err := gateway.CreateIntent(ctx, payload, "order-1")
+		require.NoError(t, err)
+		require.Equal(t, domain.PaymentIntentPending, first.Status)
+		require.Equal(t, "http://localhost/checkout/"+first.ID, first.CheckoutURL)
+
+		second, err := gateway.CreateIntent(ctx, payload, "order-1")
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+
+		other := domain.PaymentPayload{UserID: payload.UserID, PaymentSum: 1}
+		_, err = gateway.CreateIntent(ctx, other, "order-1")
+		require.ErrorIs(t, err, ports.ErrPaymentIdempotencyConflict)
+	})
+
+	t.Run("test pay sends signed webhook", func(t *testing.T) {
+		gateway := payment.NewFakeGateway(webhookSecret, "http://localhost/checkout")
+		intent, err := gateway.CreateIntent(ctx, payload, "order-2")
+		require.NoError(t, err)
+
+		body, signature, err := gateway.Pay(intent.ID)
+		require.NoError(t, err)
+
+		event, err := gateway.ParseWebhook(body, signature)
+		require.NoError(t, err)
+		require.Equal(t, domain.PaymentEventSucceeded, event.Type)
+		require.Equal(t, intent.ID, event.IntentID)
+
+		_, _, err = gateway.Pay(intent.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("test refund", func(t *testing.T) {
+		gateway := payment.NewFakeGateway(webhookSecret, "http://localhost/checkout")
+		intent, err := gateway.CreateIntent(ctx, payload, "order-3")
+		require.NoError(t, err)
+
+		_, err = gateway.Refund(ctx, intent.ID, intent.Amount, "refund-3")
+		require.ErrorIs(t, err, ports.ErrPaymentNotRefundable)
+
+		_, _, err = gateway.Pay(intent.ID)
+		require.NoError(t, err)
+
+		refund, err := gateway.Refund(ctx, intent.ID, intent.Amount, "refund-3")
+		require.NoError(t, err)
+		require.Equal(t, intent.Amount, refund.Amount)
+
+		again, err := gateway.Refund(ctx, intent.ID, intent.Amount, "refund-3")
+		require.NoError(t, err)
+		require.Equal(t, refund, again)
+	})
+
+	t.Run("test decline", func(t *testing.T) {
+		gateway := payment.NewFakeGateway(webhookSecret, "http://localhost/checkout")
+		intent, err := gateway.CreateIntent(ctx, payload, "order-4")
+		require.NoError(t, err)
+
+		body, signature, err := gateway.Decline(intent.ID)
+		require.NoError(t, err)
+
+		event, err := gateway.ParseWebhook(body, signature)
+		require.NoError(t, err)
+		require.Equal(t, domain.PaymentEventFailed, event.Type)
+	})
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RateLimitStorage is an autogenerated mock type for the IRateLimitStorage type
+type RateLimitStorage struct {
+	mock.Mock
+}
+
+// AddFailure provides a mock function with given fields: ctx, key, window
+func (_m *RateLimitStorage) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	ret := _m.Called(ctx, key, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddFailure")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int, error)); ok {
+		return rf(ctx, key, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int); ok {
+		r0 = rf(ctx, key, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Hit provides a mock function with given fields: ctx, key, now, window, limit
+func (_m *RateLimitStorage) Hit(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (bool, time.Time, error) {
+	ret := _m.Called(ctx, key, now, window, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Hit")
+	}
+
+	var r0 bool
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration, int) (bool, time.Time, error)); ok {
+		return rf(ctx, key, now, window, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration, int) bool); ok {
+		r0 = rf(ctx, key, now, window, limit)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration, int) time.Time); ok {
+		r1 = rf(ctx, key, now, window, limit)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, time.Time, time.Duration, int) error); ok {
+		r2 = rf(ctx, key, now, window, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Lock provides a mock function with given fields: ctx, key, duration
+func (_m *RateLimitStorage) Lock(ctx context.Context, key string, duration time.Duration) error {
+	ret := _m.Called(ctx, key, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, duration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockTTL provides a mock function with given fields: ctx, key
+func (_m *RateLimitStorage) LockTTL(ctx context.Context, key string) (time.Duration, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockTTL")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (time.Duration, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) time.Duration); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetFailures provides a mock function with given fields: ctx, key
+func (_m *RateLimitStorage) ResetFailures(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetFailures")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRateLimitStorage creates a new instance of RateLimitStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRateLimitStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RateLimitStorage {
+	mock := &RateLimitStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	windowKeyPrefix  = "ratelimit:"
+	failureKeyPrefix = "lockout:failures:"
+	lockKeyPrefix    = "lockout:lock:"
+)
+
+// hitScript keeps the hits of a window in a sorted set scored by their time
+// in milliseconds, so the check and the insert happen atomically.
+var hitScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, now}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2])}
+`)
+
+type RedisStorage struct {
+	redisClient *redis.Client
+}
+
+func NewRedisStorage(redisClient *redis.Client) *RedisStorage {
+	return &RedisStorage{redisClient: redisClient}
+}
+
+func (rs *RedisStorage) Hit(ctx context.Context, key string, now time.Time, window time.Duration,
+	limit int) (bool, time.Time, error) {
+	result, err := hitScript.Run(ctx, rs.redisClient, []string{windowKeyPrefix + key},
+		now.UnixMilli(), window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return false, time.Time{}, util.WrapError(ports.ErrInternalRateLimitStorage, err)
+	}
+
+	return result[0] == 1, time.UnixMilli(result[1]), nil
+}
+
+func (rs *RedisStorage) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	pipe := rs.redisClient.TxPipeline()
+	failures := pipe.Incr(ctx, failureKeyPrefix+key)
+	pipe.PExpire(ctx, failureKeyPrefix+key, window)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalRateLimitStorage, err)
+	}
+
+	return int(failures.Val()), nil
+}
+
+func (rs *RedisStorage) Lock(ctx context.Context, key string, duration time.Duration) error {
+	err := rs.redisClient.Set(ctx, lockKeyPrefix+key, 1, duration).Err()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalRateLimitStorage, err)
+	}
+
+	return nil
+}
+
+func (rs *RedisStorage) LockTTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := rs.redisClient.PTTL(ctx, lockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalRateLimitStorage, err)
+	}
+
+	// PTTL answers with a negative value for a missing key.
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (rs *RedisStorage) ResetFailures(ctx context.Context, key string) error {
+	err := rs.redisClient.Del(ctx, failureKeyPrefix+key, lockKeyPrefix+key).Err()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalRateLimitStorage, err)
+	}
+
+	return nil
+}
@@ -8,6 +8,10 @@ import (
 	domain "github.com/hanoys/sigma-music/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	null "github.com/guregu/null/v5"
+
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -44,6 +48,34 @@ func (_m *AlbumRepository) Create(ctx context.Context, album domain.Album, music
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *AlbumRepository) Delete(ctx context.Context, id uuid.UUID) (domain.Album, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 domain.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Album, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Album); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Album)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields: ctx
 func (_m *AlbumRepository) GetAll(ctx context.Context) ([]domain.Album, error) {
 	ret := _m.Called(ctx)
@@ -180,6 +212,100 @@ func (_m *AlbumRepository) Publish(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
+// PublishScheduled provides a mock function with given fields: ctx, now
+func (_m *AlbumRepository) PublishScheduled(ctx context.Context, now time.Time) ([]domain.Album, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishScheduled")
+	}
+
+	var r0 []domain.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Album, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Album); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Album)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Schedule provides a mock function with given fields: ctx, id, releaseAt
+func (_m *AlbumRepository) Schedule(ctx context.Context, id uuid.UUID, releaseAt null.Time) error {
+	ret := _m.Called(ctx, id, releaseAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Schedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, null.Time) error); ok {
+		r0 = rf(ctx, id, releaseAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unpublish provides a mock function with given fields: ctx, id, reason
+func (_m *AlbumRepository) Unpublish(ctx context.Context, id uuid.UUID, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unpublish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, album
+func (_m *AlbumRepository) Update(ctx context.Context, album domain.Album) (domain.Album, error) {
+	ret := _m.Called(ctx, album)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 domain.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Album) (domain.Album, error)); ok {
+		return rf(ctx, album)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Album) domain.Album); ok {
+		r0 = rf(ctx, album)
+	} else {
+		r0 = ret.Get(0).(domain.Album)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Album) error); ok {
+		r1 = rf(ctx, album)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAlbumRepository creates a new instance of AlbumRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAlbumRepository(t interface {
@@ -8,6 +8,10 @@ import (
 	domain "github.com/hanoys/sigma-music/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	null "github.com/guregu/null/v5"
+
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -44,34 +48,171 @@ func (_m *CommentRepository) Create(ctx context.Context, comment domain.Comment)
 	return r0, r1
 }
 
-// GetByTrackID provides a mock function with given fields: ctx, trackID
-func (_m *CommentRepository) GetByTrackID(ctx context.Context, trackID uuid.UUID) ([]domain.Comment, error) {
-	ret := _m.Called(ctx, trackID)
+// CreateReport provides a mock function with given fields: ctx, report
+func (_m *CommentRepository) CreateReport(ctx context.Context, report domain.CommentReport) (domain.CommentReport, error) {
+	ret := _m.Called(ctx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReport")
+	}
+
+	var r0 domain.CommentReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CommentReport) (domain.CommentReport, error)); ok {
+		return rf(ctx, report)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CommentReport) domain.CommentReport); ok {
+		r0 = rf(ctx, report)
+	} else {
+		r0 = ret.Get(0).(domain.CommentReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.CommentReport) error); ok {
+		r1 = rf(ctx, report)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, trackID
+func (_m *CommentRepository) Delete(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) (domain.Comment, error) {
+	ret := _m.Called(ctx, userID, trackID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (domain.Comment, error)); ok {
+		return rf(ctx, userID, trackID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) domain.Comment); ok {
+		r0 = rf(ctx, userID, trackID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, trackID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteByID provides a mock function with given fields: ctx, commentID
+func (_m *CommentRepository) DeleteByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	ret := _m.Called(ctx, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByID")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Comment, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Comment); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteVote provides a mock function with given fields: ctx, commentID, userID
+func (_m *CommentRepository) DeleteVote(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error {
+	ret := _m.Called(ctx, commentID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteVote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, commentID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: ctx, commentID
+func (_m *CommentRepository) GetByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	ret := _m.Called(ctx, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Comment, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Comment); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByTrackID provides a mock function with given fields: ctx, trackID, page
+func (_m *CommentRepository) GetByTrackID(ctx context.Context, trackID uuid.UUID, page domain.CommentPage) ([]domain.Comment, int, error) {
+	ret := _m.Called(ctx, trackID, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByTrackID")
 	}
 
 	var r0 []domain.Comment
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Comment, error)); ok {
-		return rf(ctx, trackID)
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.CommentPage) ([]domain.Comment, int, error)); ok {
+		return rf(ctx, trackID, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Comment); ok {
-		r0 = rf(ctx, trackID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.CommentPage) []domain.Comment); ok {
+		r0 = rf(ctx, trackID, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Comment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, trackID)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.CommentPage) int); ok {
+		r1 = rf(ctx, trackID, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, domain.CommentPage) error); ok {
+		r2 = rf(ctx, trackID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetByUserID provides a mock function with given fields: ctx, userID
@@ -104,6 +245,249 @@ func (_m *CommentRepository) GetByUserID(ctx context.Context, userID uuid.UUID)
 	return r0, r1
 }
 
+// GetModerationQueue provides a mock function with given fields: ctx, limit, offset
+func (_m *CommentRepository) GetModerationQueue(ctx context.Context, limit int, offset int) ([]domain.ReportedComment, int, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetModerationQueue")
+	}
+
+	var r0 []domain.ReportedComment
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.ReportedComment, int, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.ReportedComment); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ReportedComment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReplies provides a mock function with given fields: ctx, parentIDs
+func (_m *CommentRepository) GetReplies(ctx context.Context, parentIDs []uuid.UUID) ([]domain.Comment, error) {
+	ret := _m.Called(ctx, parentIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplies")
+	}
+
+	var r0 []domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) ([]domain.Comment, error)); ok {
+		return rf(ctx, parentIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) []domain.Comment); ok {
+		r0 = rf(ctx, parentIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, parentIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Hide provides a mock function with given fields: ctx, commentID, hiddenBy, hiddenAt
+func (_m *CommentRepository) Hide(ctx context.Context, commentID uuid.UUID, hiddenBy domain.CommentHider, hiddenAt time.Time) (domain.Comment, error) {
+	ret := _m.Called(ctx, commentID, hiddenBy, hiddenAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Hide")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.CommentHider, time.Time) (domain.Comment, error)); ok {
+		return rf(ctx, commentID, hiddenBy, hiddenAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.CommentHider, time.Time) domain.Comment); ok {
+		r0 = rf(ctx, commentID, hiddenBy, hiddenAt)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.CommentHider, time.Time) error); ok {
+		r1 = rf(ctx, commentID, hiddenBy, hiddenAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveReports provides a mock function with given fields: ctx, commentID, resolvedAt
+func (_m *CommentRepository) ResolveReports(ctx context.Context, commentID uuid.UUID, resolvedAt time.Time) error {
+	ret := _m.Called(ctx, commentID, resolvedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveReports")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, commentID, resolvedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, commentID
+func (_m *CommentRepository) Restore(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	ret := _m.Called(ctx, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Comment, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Comment); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPinned provides a mock function with given fields: ctx, commentID, pinnedAt
+func (_m *CommentRepository) SetPinned(ctx context.Context, commentID uuid.UUID, pinnedAt null.Time) (domain.Comment, error) {
+	ret := _m.Called(ctx, commentID, pinnedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPinned")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, null.Time) (domain.Comment, error)); ok {
+		return rf(ctx, commentID, pinnedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, null.Time) domain.Comment); ok {
+		r0 = rf(ctx, commentID, pinnedAt)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, null.Time) error); ok {
+		r1 = rf(ctx, commentID, pinnedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, comment
+func (_m *CommentRepository) Update(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+	ret := _m.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) (domain.Comment, error)); ok {
+		return rf(ctx, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) domain.Comment); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment) error); ok {
+		r1 = rf(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, comment
+func (_m *CommentRepository) Upsert(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+	ret := _m.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) (domain.Comment, error)); ok {
+		return rf(ctx, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) domain.Comment); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment) error); ok {
+		r1 = rf(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Vote provides a mock function with given fields: ctx, vote
+func (_m *CommentRepository) Vote(ctx context.Context, vote domain.CommentVote) error {
+	ret := _m.Called(ctx, vote)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Vote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CommentVote) error); ok {
+		r0 = rf(ctx, vote)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewCommentRepository creates a new instance of CommentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCommentRepository(t interface {
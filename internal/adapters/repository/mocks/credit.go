@@ -0,0 +1,229 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// CreditRepository is an autogenerated mock type for the ICreditRepository type
+type CreditRepository struct {
+	mock.Mock
+}
+
+// AcceptInvite provides a mock function with given fields: ctx, albumID, musicianID
+func (_m *CreditRepository) AcceptInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	ret := _m.Called(ctx, albumID, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptInvite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, albumID, musicianID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTrackCredit provides a mock function with given fields: ctx, trackID, musicianID, role
+func (_m *CreditRepository) AddTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error {
+	ret := _m.Called(ctx, trackID, musicianID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTrackCredit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, domain.CreditRole) error); ok {
+		r0 = rf(ctx, trackID, musicianID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeclineInvite provides a mock function with given fields: ctx, albumID, musicianID
+func (_m *CreditRepository) DeclineInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	ret := _m.Called(ctx, albumID, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeclineInvite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, albumID, musicianID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAlbumContributors provides a mock function with given fields: ctx, albumID
+func (_m *CreditRepository) GetAlbumContributors(ctx context.Context, albumID uuid.UUID) ([]domain.AlbumContributor, error) {
+	ret := _m.Called(ctx, albumID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbumContributors")
+	}
+
+	var r0 []domain.AlbumContributor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.AlbumContributor, error)); ok {
+		return rf(ctx, albumID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.AlbumContributor); ok {
+		r0 = rf(ctx, albumID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AlbumContributor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, albumID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInvites provides a mock function with given fields: ctx, musicianID
+func (_m *CreditRepository) GetInvites(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error) {
+	ret := _m.Called(ctx, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInvites")
+	}
+
+	var r0 []domain.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Album, error)); ok {
+		return rf(ctx, musicianID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Album); ok {
+		r0 = rf(ctx, musicianID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Album)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, musicianID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrackCredits provides a mock function with given fields: ctx, trackID
+func (_m *CreditRepository) GetTrackCredits(ctx context.Context, trackID uuid.UUID) ([]domain.TrackCredit, error) {
+	ret := _m.Called(ctx, trackID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrackCredits")
+	}
+
+	var r0 []domain.TrackCredit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.TrackCredit, error)); ok {
+		return rf(ctx, trackID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.TrackCredit); ok {
+		r0 = rf(ctx, trackID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TrackCredit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteToAlbum provides a mock function with given fields: ctx, albumID, musicianID
+func (_m *CreditRepository) InviteToAlbum(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	ret := _m.Called(ctx, albumID, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InviteToAlbum")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, albumID, musicianID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveContributor provides a mock function with given fields: ctx, albumID, musicianID
+func (_m *CreditRepository) RemoveContributor(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	ret := _m.Called(ctx, albumID, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveContributor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, albumID, musicianID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveTrackCredit provides a mock function with given fields: ctx, trackID, musicianID, role
+func (_m *CreditRepository) RemoveTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error {
+	ret := _m.Called(ctx, trackID, musicianID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTrackCredit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, domain.CreditRole) error); ok {
+		r0 = rf(ctx, trackID, musicianID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCreditRepository creates a new instance of CreditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCreditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CreditRepository {
+	mock := &CreditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,177 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// DataExportRepository is an autogenerated mock type for the IDataExportRepository type
+type DataExportRepository struct {
+	mock.Mock
+}
+
+// ClaimPending provides a mock function with given fields: ctx, now, staleBefore, limit
+func (_m *DataExportRepository) ClaimPending(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]domain.DataExport, error) {
+	ret := _m.Called(ctx, now, staleBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPending")
+	}
+
+	var r0 []domain.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]domain.DataExport, error)); ok {
+		return rf(ctx, now, staleBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []domain.DataExport); ok {
+		r0 = rf(ctx, now, staleBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, now, staleBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, export
+func (_m *DataExportRepository) Create(ctx context.Context, export domain.DataExport) (domain.DataExport, error) {
+	ret := _m.Called(ctx, export)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 domain.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DataExport) (domain.DataExport, error)); ok {
+		return rf(ctx, export)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DataExport) domain.DataExport); ok {
+		r0 = rf(ctx, export)
+	} else {
+		r0 = ret.Get(0).(domain.DataExport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DataExport) error); ok {
+		r1 = rf(ctx, export)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Finish provides a mock function with given fields: ctx, export
+func (_m *DataExportRepository) Finish(ctx context.Context, export domain.DataExport) (domain.DataExport, error) {
+	ret := _m.Called(ctx, export)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Finish")
+	}
+
+	var r0 domain.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DataExport) (domain.DataExport, error)); ok {
+		return rf(ctx, export)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DataExport) domain.DataExport); ok {
+		r0 = rf(ctx, export)
+	} else {
+		r0 = ret.Get(0).(domain.DataExport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DataExport) error); ok {
+		r1 = rf(ctx, export)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, exportID
+func (_m *DataExportRepository) GetByID(ctx context.Context, exportID uuid.UUID) (domain.DataExport, error) {
+	ret := _m.Called(ctx, exportID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 domain.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.DataExport, error)); ok {
+		return rf(ctx, exportID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.DataExport); ok {
+		r0 = rf(ctx, exportID)
+	} else {
+		r0 = ret.Get(0).(domain.DataExport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, exportID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataExportRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.DataExport, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 []domain.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.DataExport, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.DataExport); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataExportRepository creates a new instance of DataExportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataExportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataExportRepository {
+	mock := &DataExportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
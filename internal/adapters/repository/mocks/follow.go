@@ -0,0 +1,205 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// FollowRepository is an autogenerated mock type for the IFollowRepository type
+type FollowRepository struct {
+	mock.Mock
+}
+
+// AddToFeed provides a mock function with given fields: ctx, albumID, publishedAt
+func (_m *FollowRepository) AddToFeed(ctx context.Context, albumID uuid.UUID, publishedAt time.Time) error {
+	ret := _m.Called(ctx, albumID, publishedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToFeed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, albumID, publishedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountFollowers provides a mock function with given fields: ctx, musicianID
+func (_m *FollowRepository) CountFollowers(ctx context.Context, musicianID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFollowers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, musicianID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, musicianID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, musicianID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountFollowersByIDs provides a mock function with given fields: ctx, musicianIDs
+func (_m *FollowRepository) CountFollowersByIDs(ctx context.Context, musicianIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	ret := _m.Called(ctx, musicianIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFollowersByIDs")
+	}
+
+	var r0 map[uuid.UUID]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (map[uuid.UUID]int64, error)); ok {
+		return rf(ctx, musicianIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) map[uuid.UUID]int64); ok {
+		r0 = rf(ctx, musicianIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, musicianIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Follow provides a mock function with given fields: ctx, userID, musicianID
+func (_m *FollowRepository) Follow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Follow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, musicianID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFeed provides a mock function with given fields: ctx, userID
+func (_m *FollowRepository) GetFeed(ctx context.Context, userID uuid.UUID) ([]domain.FeedItem, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeed")
+	}
+
+	var r0 []domain.FeedItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.FeedItem, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.FeedItem); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.FeedItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowing provides a mock function with given fields: ctx, userID
+func (_m *FollowRepository) GetFollowing(ctx context.Context, userID uuid.UUID) ([]domain.Musician, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowing")
+	}
+
+	var r0 []domain.Musician
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Musician, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Musician); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Musician)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfollow provides a mock function with given fields: ctx, userID, musicianID
+func (_m *FollowRepository) Unfollow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unfollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, musicianID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFollowRepository creates a new instance of FollowRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFollowRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FollowRepository {
+	mock := &FollowRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
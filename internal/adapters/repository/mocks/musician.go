@@ -8,6 +8,8 @@ import (
 	domain "github.com/hanoys/sigma-music/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	null "github.com/guregu/null/v5"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -214,6 +216,120 @@ func (_m *MusicianRepository) GetByTrackID(ctx context.Context, trackID uuid.UUI
 	return r0, r1
 }
 
+// GetLinks provides a mock function with given fields: ctx, musicianID
+func (_m *MusicianRepository) GetLinks(ctx context.Context, musicianID uuid.UUID) ([]domain.MusicianLink, error) {
+	ret := _m.Called(ctx, musicianID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLinks")
+	}
+
+	var r0 []domain.MusicianLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.MusicianLink, error)); ok {
+		return rf(ctx, musicianID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.MusicianLink); ok {
+		r0 = rf(ctx, musicianID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MusicianLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, musicianID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVerifiedAt provides a mock function with given fields: ctx, musicianID, verifiedAt
+func (_m *MusicianRepository) SetVerifiedAt(ctx context.Context, musicianID uuid.UUID, verifiedAt null.Time) (domain.Musician, error) {
+	ret := _m.Called(ctx, musicianID, verifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetVerifiedAt")
+	}
+
+	var r0 domain.Musician
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, null.Time) (domain.Musician, error)); ok {
+		return rf(ctx, musicianID, verifiedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, null.Time) domain.Musician); ok {
+		r0 = rf(ctx, musicianID, verifiedAt)
+	} else {
+		r0 = ret.Get(0).(domain.Musician)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, null.Time) error); ok {
+		r1 = rf(ctx, musicianID, verifiedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, musician
+func (_m *MusicianRepository) Update(ctx context.Context, musician domain.Musician) (domain.Musician, error) {
+	ret := _m.Called(ctx, musician)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 domain.Musician
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Musician) (domain.Musician, error)); ok {
+		return rf(ctx, musician)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Musician) domain.Musician); ok {
+		r0 = rf(ctx, musician)
+	} else {
+		r0 = ret.Get(0).(domain.Musician)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Musician) error); ok {
+		r1 = rf(ctx, musician)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, musician, links
+func (_m *MusicianRepository) UpdateProfile(ctx context.Context, musician domain.Musician, links []domain.MusicianLink) (domain.Musician, error) {
+	ret := _m.Called(ctx, musician, links)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 domain.Musician
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Musician, []domain.MusicianLink) (domain.Musician, error)); ok {
+		return rf(ctx, musician, links)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Musician, []domain.MusicianLink) domain.Musician); ok {
+		r0 = rf(ctx, musician, links)
+	} else {
+		r0 = ret.Get(0).(domain.Musician)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Musician, []domain.MusicianLink) error); ok {
+		r1 = rf(ctx, musician, links)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMusicianRepository creates a new instance of MusicianRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMusicianRepository(t interface {
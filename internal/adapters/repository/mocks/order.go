@@ -0,0 +1,179 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// OrderRepository is an autogenerated mock type for the IOrderRepository type
+type OrderRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, order
+func (_m *OrderRepository) Create(ctx context.Context, order domain.Order) (domain.Order, error) {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Order) (domain.Order, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Order) domain.Order); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Order) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, orderID
+func (_m *OrderRepository) GetByID(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Order, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Order); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIdempotencyKey provides a mock function with given fields: ctx, userID, idempotencyKey
+func (_m *OrderRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, idempotencyKey string) (domain.Order, error) {
+	ret := _m.Called(ctx, userID, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIdempotencyKey")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (domain.Order, error)); ok {
+		return rf(ctx, userID, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) domain.Order); ok {
+		r0 = rf(ctx, userID, idempotencyKey)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIntentID provides a mock function with given fields: ctx, intentID
+func (_m *OrderRepository) GetByIntentID(ctx context.Context, intentID string) (domain.Order, error) {
+	ret := _m.Called(ctx, intentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIntentID")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Order, error)); ok {
+		return rf(ctx, intentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Order); ok {
+		r0 = rf(ctx, intentID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, intentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetIntent provides a mock function with given fields: ctx, orderID, intent
+func (_m *OrderRepository) SetIntent(ctx context.Context, orderID uuid.UUID, intent domain.PaymentIntent) error {
+	ret := _m.Called(ctx, orderID, intent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetIntent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PaymentIntent) error); ok {
+		r0 = rf(ctx, orderID, intent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, orderID, from, to
+func (_m *OrderRepository) UpdateStatus(ctx context.Context, orderID uuid.UUID, from domain.OrderStatus, to domain.OrderStatus) error {
+	ret := _m.Called(ctx, orderID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatus, domain.OrderStatus) error); ok {
+		r0 = rf(ctx, orderID, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOrderRepository creates a new instance of OrderRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderRepository {
+	mock := &OrderRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
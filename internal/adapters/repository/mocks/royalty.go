@@ -0,0 +1,167 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// RoyaltyRepository is an autogenerated mock type for the IRoyaltyRepository type
+type RoyaltyRepository struct {
+	mock.Mock
+}
+
+// CreatePeriod provides a mock function with given fields: ctx, period, entries
+func (_m *RoyaltyRepository) CreatePeriod(ctx context.Context, period domain.RoyaltyPeriod, entries []domain.RoyaltyEntry) error {
+	ret := _m.Called(ctx, period, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RoyaltyPeriod, []domain.RoyaltyEntry) error); ok {
+		r0 = rf(ctx, period, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLastPeriod provides a mock function with given fields: ctx
+func (_m *RoyaltyRepository) GetLastPeriod(ctx context.Context) (domain.RoyaltyPeriod, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastPeriod")
+	}
+
+	var r0 domain.RoyaltyPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (domain.RoyaltyPeriod, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) domain.RoyaltyPeriod); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(domain.RoyaltyPeriod)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevenue provides a mock function with given fields: ctx, start, end
+func (_m *RoyaltyRepository) GetRevenue(ctx context.Context, start time.Time, end time.Time) (int64, error) {
+	ret := _m.Called(ctx, start, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevenue")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (int64, error)); ok {
+		return rf(ctx, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) int64); ok {
+		r0 = rf(ctx, start, end)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatements provides a mock function with given fields: ctx, musicianID, from, to
+func (_m *RoyaltyRepository) GetStatements(ctx context.Context, musicianID uuid.UUID, from time.Time, to time.Time) ([]domain.RoyaltyStatement, error) {
+	ret := _m.Called(ctx, musicianID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatements")
+	}
+
+	var r0 []domain.RoyaltyStatement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) ([]domain.RoyaltyStatement, error)); ok {
+		return rf(ctx, musicianID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) []domain.RoyaltyStatement); ok {
+		r0 = rf(ctx, musicianID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.RoyaltyStatement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, musicianID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrackPlays provides a mock function with given fields: ctx, start, end, minSeconds
+func (_m *RoyaltyRepository) GetTrackPlays(ctx context.Context, start time.Time, end time.Time, minSeconds int) ([]domain.TrackPlays, error) {
+	ret := _m.Called(ctx, start, end, minSeconds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrackPlays")
+	}
+
+	var r0 []domain.TrackPlays
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]domain.TrackPlays, error)); ok {
+		return rf(ctx, start, end, minSeconds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []domain.TrackPlays); ok {
+		r0 = rf(ctx, start, end, minSeconds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TrackPlays)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, start, end, minSeconds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRoyaltyRepository creates a new instance of RoyaltyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoyaltyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoyaltyRepository {
+	mock := &RoyaltyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
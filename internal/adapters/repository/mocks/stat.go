@@ -16,17 +16,17 @@ type StatRepository struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: ctx, recordID, userID, trackID
-func (_m *StatRepository) Add(ctx context.Context, recordID uuid.UUID, userID uuid.UUID, trackID uuid.UUID) error {
-	ret := _m.Called(ctx, recordID, userID, trackID)
+// Add provides a mock function with given fields: ctx, recordID, userID, trackID, listenedSeconds
+func (_m *StatRepository) Add(ctx context.Context, recordID uuid.UUID, userID uuid.UUID, trackID uuid.UUID, listenedSeconds int) error {
+	ret := _m.Called(ctx, recordID, userID, trackID, listenedSeconds)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Add")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
-		r0 = rf(ctx, recordID, userID, trackID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, int) error); ok {
+		r0 = rf(ctx, recordID, userID, trackID, listenedSeconds)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -34,6 +34,36 @@ func (_m *StatRepository) Add(ctx context.Context, recordID uuid.UUID, userID uu
 	return r0
 }
 
+// GetHistory provides a mock function with given fields: ctx, userID
+func (_m *StatRepository) GetHistory(ctx context.Context, userID uuid.UUID) ([]domain.ListenRecord, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []domain.ListenRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.ListenRecord, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.ListenRecord); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ListenRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetListenedGenres provides a mock function with given fields: ctx, userID
 func (_m *StatRepository) GetListenedGenres(ctx context.Context, userID uuid.UUID) ([]domain.UserGenresStat, error) {
 	ret := _m.Called(ctx, userID)
@@ -90,9 +90,37 @@ func (_m *TrackRepository) Delete(ctx context.Context, trackID uuid.UUID) (domai
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *TrackRepository) GetAll(ctx context.Context) ([]domain.Track, error) {
-	ret := _m.Called(ctx)
+// DeleteFavorite provides a mock function with given fields: ctx, trackID, userID
+func (_m *TrackRepository) DeleteFavorite(ctx context.Context, trackID uuid.UUID, userID uuid.UUID) (domain.Track, error) {
+	ret := _m.Called(ctx, trackID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFavorite")
+	}
+
+	var r0 domain.Track
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (domain.Track, error)); ok {
+		return rf(ctx, trackID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) domain.Track); ok {
+		r0 = rf(ctx, trackID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Track)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: ctx, sort
+func (_m *TrackRepository) GetAll(ctx context.Context, sort domain.TrackSort) ([]domain.Track, error) {
+	ret := _m.Called(ctx, sort)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
@@ -100,19 +128,19 @@ func (_m *TrackRepository) GetAll(ctx context.Context) ([]domain.Track, error) {
 
 	var r0 []domain.Track
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Track, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TrackSort) ([]domain.Track, error)); ok {
+		return rf(ctx, sort)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.Track); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TrackSort) []domain.Track); ok {
+		r0 = rf(ctx, sort)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Track)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TrackSort) error); ok {
+		r1 = rf(ctx, sort)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,14 +148,44 @@ func (_m *TrackRepository) GetAll(ctx context.Context) ([]domain.Track, error) {
 	return r0, r1
 }
 
-// GetByAlbumID provides a mock function with given fields: ctx, albumID
-func (_m *TrackRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error) {
-	ret := _m.Called(ctx, albumID)
+// GetByAlbumID provides a mock function with given fields: ctx, albumID, sort
+func (_m *TrackRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	ret := _m.Called(ctx, albumID, sort)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByAlbumID")
 	}
 
+	var r0 []domain.Track
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) ([]domain.Track, error)); ok {
+		return rf(ctx, albumID, sort)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) []domain.Track); ok {
+		r0 = rf(ctx, albumID, sort)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Track)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.TrackSort) error); ok {
+		r1 = rf(ctx, albumID, sort)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByAlbumIDInternal provides a mock function with given fields: ctx, albumID
+func (_m *TrackRepository) GetByAlbumIDInternal(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error) {
+	ret := _m.Called(ctx, albumID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAlbumIDInternal")
+	}
+
 	var r0 []domain.Track
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Track, error)); ok {
@@ -178,9 +236,9 @@ func (_m *TrackRepository) GetByID(ctx context.Context, trackID uuid.UUID) (doma
 	return r0, r1
 }
 
-// GetByMusicianID provides a mock function with given fields: ctx, musicianID
-func (_m *TrackRepository) GetByMusicianID(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error) {
-	ret := _m.Called(ctx, musicianID)
+// GetByMusicianID provides a mock function with given fields: ctx, musicianID, sort
+func (_m *TrackRepository) GetByMusicianID(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	ret := _m.Called(ctx, musicianID, sort)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByMusicianID")
@@ -188,19 +246,19 @@ func (_m *TrackRepository) GetByMusicianID(ctx context.Context, musicianID uuid.
 
 	var r0 []domain.Track
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Track, error)); ok {
-		return rf(ctx, musicianID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) ([]domain.Track, error)); ok {
+		return rf(ctx, musicianID, sort)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Track); ok {
-		r0 = rf(ctx, musicianID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) []domain.Track); ok {
+		r0 = rf(ctx, musicianID, sort)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Track)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, musicianID)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.TrackSort) error); ok {
+		r1 = rf(ctx, musicianID, sort)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -208,9 +266,9 @@ func (_m *TrackRepository) GetByMusicianID(ctx context.Context, musicianID uuid.
 	return r0, r1
 }
 
-// GetOwn provides a mock function with given fields: ctx, musicianID
-func (_m *TrackRepository) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error) {
-	ret := _m.Called(ctx, musicianID)
+// GetOwn provides a mock function with given fields: ctx, musicianID, sort
+func (_m *TrackRepository) GetOwn(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	ret := _m.Called(ctx, musicianID, sort)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOwn")
@@ -218,19 +276,19 @@ func (_m *TrackRepository) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]
 
 	var r0 []domain.Track
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Track, error)); ok {
-		return rf(ctx, musicianID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) ([]domain.Track, error)); ok {
+		return rf(ctx, musicianID, sort)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Track); ok {
-		r0 = rf(ctx, musicianID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) []domain.Track); ok {
+		r0 = rf(ctx, musicianID, sort)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Track)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, musicianID)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.TrackSort) error); ok {
+		r1 = rf(ctx, musicianID, sort)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -238,9 +296,9 @@ func (_m *TrackRepository) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]
 	return r0, r1
 }
 
-// GetUserFavorites provides a mock function with given fields: ctx, userID
-func (_m *TrackRepository) GetUserFavorites(ctx context.Context, userID uuid.UUID) ([]domain.Track, error) {
-	ret := _m.Called(ctx, userID)
+// GetUserFavorites provides a mock function with given fields: ctx, userID, sort
+func (_m *TrackRepository) GetUserFavorites(ctx context.Context, userID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	ret := _m.Called(ctx, userID, sort)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserFavorites")
@@ -248,19 +306,65 @@ func (_m *TrackRepository) GetUserFavorites(ctx context.Context, userID uuid.UUI
 
 	var r0 []domain.Track
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Track, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) ([]domain.Track, error)); ok {
+		return rf(ctx, userID, sort)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Track); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.TrackSort) []domain.Track); ok {
+		r0 = rf(ctx, userID, sort)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Track)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.TrackSort) error); ok {
+		r1 = rf(ctx, userID, sort)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reorder provides a mock function with given fields: ctx, albumID, positions
+func (_m *TrackRepository) Reorder(ctx context.Context, albumID uuid.UUID, positions []domain.TrackPosition) error {
+	ret := _m.Called(ctx, albumID, positions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reorder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []domain.TrackPosition) error); ok {
+		r0 = rf(ctx, albumID, positions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, track
+func (_m *TrackRepository) Update(ctx context.Context, track domain.Track) (domain.Track, error) {
+	ret := _m.Called(ctx, track)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 domain.Track
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Track) (domain.Track, error)); ok {
+		return rf(ctx, track)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Track) domain.Track); ok {
+		r0 = rf(ctx, track)
+	} else {
+		r0 = ret.Get(0).(domain.Track)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Track) error); ok {
+		r1 = rf(ctx, track)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/hanoys/sigma-music/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// TwoFactorRepository is an autogenerated mock type for the ITwoFactorRepository type
+type TwoFactorRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, accountID
+func (_m *TwoFactorRepository) Delete(ctx context.Context, accountID uuid.UUID) error {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enable provides a mock function with given fields: ctx, accountID, step
+func (_m *TwoFactorRepository) Enable(ctx context.Context, accountID uuid.UUID, step int64) error {
+	ret := _m.Called(ctx, accountID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, accountID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, accountID
+func (_m *TwoFactorRepository) Get(ctx context.Context, accountID uuid.UUID) (domain.TwoFactor, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 domain.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.TwoFactor, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.TwoFactor); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactor)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, twoFactor, recoveryCodeHashes
+func (_m *TwoFactorRepository) Save(ctx context.Context, twoFactor domain.TwoFactor, recoveryCodeHashes []string) error {
+	ret := _m.Called(ctx, twoFactor, recoveryCodeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TwoFactor, []string) error); ok {
+		r0 = rf(ctx, twoFactor, recoveryCodeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, accountID, codeHash
+func (_m *TwoFactorRepository) UseRecoveryCode(ctx context.Context, accountID uuid.UUID, codeHash string) error {
+	ret := _m.Called(ctx, accountID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, accountID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseStep provides a mock function with given fields: ctx, accountID, step
+func (_m *TwoFactorRepository) UseStep(ctx context.Context, accountID uuid.UUID, step int64) error {
+	ret := _m.Called(ctx, accountID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, accountID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTwoFactorRepository creates a new instance of TwoFactorRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorRepository {
+	mock := &TwoFactorRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,6 +8,8 @@ import (
 	domain "github.com/hanoys/sigma-music/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,6 +18,34 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// CancelDeletion provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelDeletion")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, user
 func (_m *UserRepository) Create(ctx context.Context, user domain.User) (domain.User, error) {
 	ret := _m.Called(ctx, user)
@@ -44,6 +74,24 @@ func (_m *UserRepository) Create(ctx context.Context, user domain.User) (domain.
 	return r0, r1
 }
 
+// DeleteScheduled provides a mock function with given fields: ctx, userID, before
+func (_m *UserRepository) DeleteScheduled(ctx context.Context, userID uuid.UUID, before time.Time) error {
+	ret := _m.Called(ctx, userID, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteScheduled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, userID, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAll provides a mock function with given fields: ctx
 func (_m *UserRepository) GetAll(ctx context.Context) ([]domain.User, error) {
 	ret := _m.Called(ctx)
@@ -186,6 +234,148 @@ func (_m *UserRepository) GetByPhone(ctx context.Context, phone string) (domain.
 	return r0, r1
 }
 
+// GetScheduledBefore provides a mock function with given fields: ctx, before
+func (_m *UserRepository) GetScheduledBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduledBefore")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]uuid.UUID, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []uuid.UUID); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScheduleDeletion provides a mock function with given fields: ctx, userID, at
+func (_m *UserRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) (domain.User, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleDeletion")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (domain.User, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) domain.User); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetAvatar provides a mock function with given fields: ctx, userID, avatarURL
+func (_m *UserRepository) SetAvatar(ctx context.Context, userID uuid.UUID, avatarURL string) (domain.User, error) {
+	ret := _m.Called(ctx, userID, avatarURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAvatar")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (domain.User, error)); ok {
+		return rf(ctx, userID, avatarURL)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) domain.User); ok {
+		r0 = rf(ctx, userID, avatarURL)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, avatarURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRole provides a mock function with given fields: ctx, userID, role
+func (_m *UserRepository) SetRole(ctx context.Context, userID uuid.UUID, role int) (domain.User, error) {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRole")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) (domain.User, error)); ok {
+		return rf(ctx, userID, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) domain.User); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, userID, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, userID, changes
+func (_m *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, changes domain.UserProfileChanges) (domain.User, error) {
+	ret := _m.Called(ctx, userID, changes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.UserProfileChanges) (domain.User, error)); ok {
+		return rf(ctx, userID, changes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.UserProfileChanges) domain.User); ok {
+		r0 = rf(ctx, userID, changes)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.UserProfileChanges) error); ok {
+		r1 = rf(ctx, userID, changes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	accountSelectQuery = "SELECT a.*, v.account_id IS NOT NULL AS email_verified FROM accounts a " +
+		"LEFT JOIN account_email_verifications v ON v.account_id = a.id AND lower(v.email) = lower(a.email)"
+
+	AccountGetByIDQuery        = accountSelectQuery + " WHERE a.id = $1"
+	AccountGetByNameQuery      = accountSelectQuery + " WHERE a.name = $1"
+	AccountGetByEmailQuery     = accountSelectQuery + " WHERE lower(a.email) = lower($1)"
+	AccountUpdatePasswordQuery = "UPDATE accounts SET password = $2, salt = $3 WHERE id = $1"
+	AccountVerifyEmailQuery    = "INSERT INTO account_email_verifications (account_id, email) VALUES ($1, $2) " +
+		"ON CONFLICT (account_id) DO UPDATE SET email = EXCLUDED.email, verified_at = now()"
+)
+
+type PostgresAccountRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresAccountRepository(connection *sqlx.DB) *PostgresAccountRepository {
+	return &PostgresAccountRepository{connection: connection}
+}
+
+func (ar *PostgresAccountRepository) GetByID(ctx context.Context, accountID uuid.UUID) (domain.Account, error) {
+	var foundAccount entity2.PgAccountInfo
+	err := ar.connection.GetContext(ctx, &foundAccount, AccountGetByIDQuery, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Account{}, util.WrapError(ports.ErrAccountNotFound, err)
+		}
+		return domain.Account{}, util.WrapError(ports.ErrInternalAccountRepo, err)
+	}
+
+	return foundAccount.ToDomain(), nil
+}
+
+func (ar *PostgresAccountRepository) GetByName(ctx context.Context, name string) (domain.Account, error) {
+	var foundAccount entity2.PgAccountInfo
+	err := ar.connection.GetContext(ctx, &foundAccount, AccountGetByNameQuery, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Account{}, util.WrapError(ports.ErrAccountNotFound, err)
+		}
+		return domain.Account{}, util.WrapError(ports.ErrInternalAccountRepo, err)
+	}
+
+	return foundAccount.ToDomain(), nil
+}
+
+func (ar *PostgresAccountRepository) GetByEmail(ctx context.Context, email string) (domain.Account, error) {
+	var foundAccount entity2.PgAccountInfo
+	err := ar.connection.GetContext(ctx, &foundAccount, AccountGetByEmailQuery, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Account{}, util.WrapError(ports.ErrAccountNotFound, err)
+		}
+		return domain.Account{}, util.WrapError(ports.ErrInternalAccountRepo, err)
+	}
+
+	return foundAccount.ToDomain(), nil
+}
+
+func (ar *PostgresAccountRepository) UpdatePassword(ctx context.Context, accountID uuid.UUID, password domain.SaltedPassword) error {
+	result, err := ar.connection.ExecContext(ctx, AccountUpdatePasswordQuery, accountID, password.HashPassword, password.Salt)
+	if err != nil {
+		return util.WrapError(ports.ErrAccountUpdate, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalAccountRepo, err)
+	}
+
+	if affected == 0 {
+		return ports.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (ar *PostgresAccountRepository) MarkEmailVerified(ctx context.Context, accountID uuid.UUID, email string) error {
+	_, err := ar.connection.ExecContext(ctx, AccountVerifyEmailQuery, accountID, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == pgerrcode.ForeignKeyViolation {
+				return util.WrapError(ports.ErrAccountNotFound, err)
+			}
+		}
+		return util.WrapError(ports.ErrAccountUpdate, err)
+	}
+
+	return nil
+}
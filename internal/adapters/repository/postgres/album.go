@@ -18,14 +18,24 @@ import (
 )
 
 const (
-	AlbumGetAllQuery          = "SELECT * FROM albums WHERE published = TRUE"
-	AlbumGetByMusicianIDQuery = "SELECT a.id, a.name, a.description, a.published, a.release_date, a.image_url FROM album_musician JOIN public.albums a on a.id = album_musician.album_id WHERE musician_id = $1 AND published = TRUE"
-	AlbumGetOwnQuery          = "SELECT a.id, a.name, a.description, a.published, a.release_date, a.image_url FROM album_musician JOIN public.albums a on a.id = album_musician.album_id WHERE musician_id = $1"
-	AlbumGetByIDQuery         = "SELECT * FROM albums WHERE id = $1 AND published = TRUE"
-	AlbumGetByIDInternalQuery = "SELECT * FROM albums WHERE id = $1"
-	AlbumInsertQuery          = "INSERT INTO album_musician(musician_id, album_id) VALUES ($1, $2)"
+	AlbumGetAllQuery           = "SELECT a.*, " + ratingColumns + " FROM albums a LEFT JOIN album_ratings r ON r.album_id = a.id WHERE a.published = TRUE"
+	AlbumGetByMusicianIDQuery  = "SELECT a.id, a.name, a.description, a.published, a.release_date, a.image_url, a.scheduled_release, a.unpublish_reason, " + ratingColumns + " FROM album_musician JOIN public.albums a on a.id = album_musician.album_id LEFT JOIN album_ratings r ON r.album_id = a.id WHERE musician_id = $1 AND accepted = TRUE AND published = TRUE"
+	AlbumGetOwnQuery           = "SELECT a.id, a.name, a.description, a.published, a.release_date, a.image_url, a.scheduled_release, a.unpublish_reason, " + ratingColumns + " FROM album_musician JOIN public.albums a on a.id = album_musician.album_id LEFT JOIN album_ratings r ON r.album_id = a.id WHERE musician_id = $1 AND accepted = TRUE"
+	AlbumGetByIDQuery          = "SELECT a.*, " + ratingColumns + " FROM albums a LEFT JOIN album_ratings r ON r.album_id = a.id WHERE a.id = $1 AND a.published = TRUE"
+	AlbumGetByIDInternalQuery  = "SELECT * FROM albums WHERE id = $1"
+	AlbumInsertQuery           = "INSERT INTO album_musician(musician_id, album_id, is_owner, accepted) VALUES ($1, $2, TRUE, TRUE)"
+	AlbumDeleteQuery           = "DELETE FROM albums WHERE id = $1"
+	AlbumScheduleQuery         = "UPDATE albums SET scheduled_release = $2 WHERE id = $1 AND published = FALSE"
+	AlbumExistsQuery           = "SELECT EXISTS(SELECT 1 FROM albums WHERE id = $1)"
+	AlbumTryLockQuery          = "SELECT pg_try_advisory_xact_lock($1)"
+	AlbumPublishScheduledQuery = "UPDATE albums SET published = TRUE, release_date = scheduled_release, scheduled_release = NULL " +
+		"WHERE published = FALSE AND scheduled_release <= $1 RETURNING *"
 )
 
+// AlbumSchedulerLockID is the advisory lock key held while publishing scheduled
+// albums, so only one application instance releases them at a time.
+const AlbumSchedulerLockID int64 = 0x5349474d41
+
 type PostgresAlbumRepository struct {
 	connection *sqlx.DB
 }
@@ -89,7 +99,7 @@ func (ar *PostgresAlbumRepository) Update(ctx context.Context, album domain.Albu
 }
 
 func (ar *PostgresAlbumRepository) GetAll(ctx context.Context) ([]domain.Album, error) {
-	var albums []entity2.PgAlbum
+	var albums []entity2.PgRatedAlbum
 	err := ar.connection.SelectContext(ctx, &albums, AlbumGetAllQuery)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalAlbumRepo, err)
@@ -104,7 +114,7 @@ func (ar *PostgresAlbumRepository) GetAll(ctx context.Context) ([]domain.Album,
 }
 
 func (ar *PostgresAlbumRepository) GetByMusicianID(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error) {
-	var albums []entity2.PgAlbum
+	var albums []entity2.PgRatedAlbum
 	err := ar.connection.SelectContext(ctx, &albums, AlbumGetByMusicianIDQuery, musicianID)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalAlbumRepo, err)
@@ -119,7 +129,7 @@ func (ar *PostgresAlbumRepository) GetByMusicianID(ctx context.Context, musician
 }
 
 func (ar *PostgresAlbumRepository) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error) {
-	var albums []entity2.PgAlbum
+	var albums []entity2.PgRatedAlbum
 	err := ar.connection.SelectContext(ctx, &albums, AlbumGetOwnQuery, musicianID)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalAlbumRepo, err)
@@ -134,7 +144,7 @@ func (ar *PostgresAlbumRepository) GetOwn(ctx context.Context, musicianID uuid.U
 }
 
 func (ar *PostgresAlbumRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Album, error) {
-	var foundAlbum entity2.PgAlbum
+	var foundAlbum entity2.PgRatedAlbum
 	err := ar.connection.GetContext(ctx, &foundAlbum, AlbumGetByIDQuery, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -157,7 +167,11 @@ func (ar *PostgresAlbumRepository) Publish(ctx context.Context, id uuid.UUID) er
 	}
 
 	foundAlbum.Published = true
-	foundAlbum.ReleaseDate = null.TimeFrom(time.Now())
+	if !foundAlbum.ReleaseDate.Valid {
+		foundAlbum.ReleaseDate = null.TimeFrom(time.Now())
+	}
+	foundAlbum.ScheduledRelease = null.Time{}
+	foundAlbum.UnpublishReason = null.String{}
 	updateQuery := entity2.UpdateQueryString(foundAlbum, "albums")
 	_, err = ar.connection.NamedExecContext(ctx, updateQuery, foundAlbum)
 	if err != nil {
@@ -166,3 +180,110 @@ func (ar *PostgresAlbumRepository) Publish(ctx context.Context, id uuid.UUID) er
 
 	return nil
 }
+
+// Schedule sets the release time in the same statement that checks the album
+// is still unpublished, so it can't race with PublishScheduled.
+func (ar *PostgresAlbumRepository) Schedule(ctx context.Context, id uuid.UUID, releaseAt null.Time) error {
+	res, err := ar.connection.ExecContext(ctx, AlbumScheduleQuery, id, releaseAt)
+	if err != nil {
+		return util.WrapError(ports.ErrAlbumUpdate, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrAlbumUpdate, err)
+	}
+
+	if affected == 0 {
+		var exists bool
+		err = ar.connection.GetContext(ctx, &exists, AlbumExistsQuery, id)
+		if err != nil {
+			return util.WrapError(ports.ErrInternalAlbumRepo, err)
+		}
+		if !exists {
+			return ports.ErrAlbumIDNotFound
+		}
+		return ports.ErrAlbumPublished
+	}
+
+	return nil
+}
+
+func (ar *PostgresAlbumRepository) PublishScheduled(ctx context.Context, now time.Time) ([]domain.Album, error) {
+	tx, err := ar.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalAlbumRepo, err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	err = tx.GetContext(ctx, &locked, AlbumTryLockQuery, AlbumSchedulerLockID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalAlbumRepo, err)
+	}
+
+	if !locked {
+		return nil, nil
+	}
+
+	var albums []entity2.PgAlbum
+	err = tx.SelectContext(ctx, &albums, AlbumPublishScheduledQuery, now)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrAlbumPublish, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalAlbumRepo, err)
+	}
+
+	domainAlbums := make([]domain.Album, len(albums))
+	for i, album := range albums {
+		domainAlbums[i] = album.ToDomain()
+	}
+
+	return domainAlbums, nil
+}
+
+func (ar *PostgresAlbumRepository) Unpublish(ctx context.Context, id uuid.UUID, reason string) error {
+	var foundAlbum entity2.PgAlbum
+	err := ar.connection.GetContext(ctx, &foundAlbum, AlbumGetByIDInternalQuery, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return util.WrapError(ports.ErrAlbumIDNotFound, err)
+		}
+		return util.WrapError(ports.ErrInternalAlbumRepo, err)
+	}
+
+	if !foundAlbum.Published {
+		return ports.ErrAlbumNotPublished
+	}
+
+	foundAlbum.Published = false
+	foundAlbum.UnpublishReason = null.StringFrom(reason)
+	updateQuery := entity2.UpdateQueryString(foundAlbum, "albums")
+	_, err = ar.connection.NamedExecContext(ctx, updateQuery, foundAlbum)
+	if err != nil {
+		return util.WrapError(ports.ErrAlbumUpdate, err)
+	}
+
+	return nil
+}
+
+func (ar *PostgresAlbumRepository) Delete(ctx context.Context, id uuid.UUID) (domain.Album, error) {
+	var deletedAlbum entity2.PgAlbum
+	err := ar.connection.GetContext(ctx, &deletedAlbum, AlbumGetByIDInternalQuery, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Album{}, util.WrapError(ports.ErrAlbumIDNotFound, err)
+		}
+		return domain.Album{}, util.WrapError(ports.ErrInternalAlbumRepo, err)
+	}
+
+	_, err = ar.connection.ExecContext(ctx, AlbumDeleteQuery, id)
+	if err != nil {
+		return domain.Album{}, util.WrapError(ports.ErrAlbumDelete, err)
+	}
+
+	return deletedAlbum.ToDomain(), nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
@@ -17,12 +19,46 @@ import (
 
 const (
 	CommentGetByIDQuery             = "SELECT * FROM comments WHERE id = $1"
-	CommentGetByUserIDQuery         = "SELECT * FROM comments WHERE user_id = $1"
-	CommentGetByUserAndTrackIDQuery = "SELECT * FROM comments WHERE user_id = $1 and track_id = $2"
-	CommentGetByTrackIDQuery        = "SELECT * FROM comments WHERE track_id = $1"
-	DeleteComment                   = "DELETE FROM comments WHERE user_id = $1 and track_id = $2"
+	CommentGetByUserIDQuery         = "SELECT * FROM comments WHERE user_id = $1 ORDER BY created_at DESC, id"
+	CommentGetByUserAndTrackIDQuery = "SELECT * FROM comments WHERE user_id = $1 and track_id = $2 AND parent_id IS NULL"
+	CommentGetNewestByTrackIDQuery  = "SELECT * FROM comments WHERE track_id = $1 AND parent_id IS NULL AND hidden_at IS NULL " +
+		"ORDER BY pinned_at DESC NULLS LAST, created_at DESC, id LIMIT $2 OFFSET $3"
+	CommentGetTopRatedByTrackIDQuery = "SELECT * FROM comments WHERE track_id = $1 AND parent_id IS NULL AND hidden_at IS NULL " +
+		"ORDER BY pinned_at DESC NULLS LAST, stars DESC, created_at DESC, id LIMIT $2 OFFSET $3"
+	CommentGetHelpfulByTrackIDQuery = "SELECT * FROM comments WHERE track_id = $1 AND parent_id IS NULL AND hidden_at IS NULL " +
+		"ORDER BY pinned_at DESC NULLS LAST, helpful_votes - unhelpful_votes DESC, helpful_votes DESC, created_at DESC, id " +
+		"LIMIT $2 OFFSET $3"
+	CommentCountByTrackIDQuery = "SELECT count(*) FROM comments WHERE track_id = $1 AND parent_id IS NULL AND hidden_at IS NULL"
+	CommentGetRepliesQuery     = "SELECT * FROM comments WHERE parent_id = ANY($1) AND hidden_at IS NULL ORDER BY created_at, id"
+	CommentUpsertReviewQuery   = "INSERT INTO comments (id, user_id, track_id, stars, comment_text, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id, track_id) WHERE parent_id IS NULL " +
+		"DO UPDATE SET stars = EXCLUDED.stars, comment_text = EXCLUDED.comment_text, edited_at = EXCLUDED.created_at " +
+		"RETURNING *"
+	CommentUpdateQuery     = "UPDATE comments SET stars = $2, comment_text = $3, edited_at = $4 WHERE id = $1 RETURNING *"
+	CommentDeleteByIDQuery = "DELETE FROM comments WHERE id = $1 RETURNING *"
+	DeleteComment          = "DELETE FROM comments WHERE user_id = $1 and track_id = $2 AND parent_id IS NULL"
+	CommentHideQuery       = "UPDATE comments SET hidden_at = $2, hidden_by = $3 WHERE id = $1 RETURNING *"
+	CommentRestoreQuery    = "UPDATE comments SET hidden_at = NULL, hidden_by = NULL WHERE id = $1 RETURNING *"
+	CommentSetPinnedQuery  = "UPDATE comments SET pinned_at = $2 WHERE id = $1 RETURNING *"
+	CommentReportQuery     = "INSERT INTO comment_reports (id, comment_id, reporter_id, reason, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5) RETURNING *"
+	CommentResolveReportsQuery  = "UPDATE comment_reports SET resolved_at = $2 WHERE comment_id = $1 AND resolved_at IS NULL"
+	CommentCountReportedQuery   = "SELECT count(DISTINCT comment_id) FROM comment_reports WHERE resolved_at IS NULL"
+	CommentModerationQueueQuery = "SELECT c.*, count(*) AS reports, " +
+		"(array_agg(r.reason ORDER BY r.created_at DESC))[1] AS last_reason, min(r.created_at) AS first_reported_at " +
+		"FROM comment_reports r JOIN comments c ON c.id = r.comment_id WHERE r.resolved_at IS NULL " +
+		"GROUP BY c.id ORDER BY reports DESC, first_reported_at, c.id LIMIT $1 OFFSET $2"
+	CommentVoteQuery = "INSERT INTO comment_votes (comment_id, user_id, helpful, created_at) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (comment_id, user_id) DO UPDATE SET helpful = EXCLUDED.helpful, created_at = EXCLUDED.created_at"
+	CommentDeleteVoteQuery = "DELETE FROM comment_votes WHERE comment_id = $1 AND user_id = $2"
 )
 
+var commentPageQueries = map[domain.CommentSort]string{
+	domain.CommentSortNewest:  CommentGetNewestByTrackIDQuery,
+	domain.CommentSortStars:   CommentGetTopRatedByTrackIDQuery,
+	domain.CommentSortHelpful: CommentGetHelpfulByTrackIDQuery,
+}
+
 type PostgresCommentRepository struct {
 	connection *sqlx.DB
 }
@@ -54,6 +90,53 @@ func (cr *PostgresCommentRepository) Create(ctx context.Context, comment domain.
 	return createdTrack.ToDomain(), nil
 }
 
+func (cr *PostgresCommentRepository) Upsert(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+	pgComment := entity2.NewPgComment(comment)
+	var upserted entity2.PgComment
+	err := cr.connection.GetContext(ctx, &upserted, CommentUpsertReviewQuery,
+		pgComment.ID,
+		pgComment.UserID,
+		pgComment.TrackID,
+		pgComment.Stars,
+		pgComment.Text,
+		pgComment.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return domain.Comment{}, util.WrapError(ports.ErrTrackIDNotFound, err)
+		}
+		return domain.Comment{}, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	return upserted.ToDomain(), nil
+}
+
+func (cr *PostgresCommentRepository) Update(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+	pgComment := entity2.NewPgComment(comment)
+	return cr.get(ctx, CommentUpdateQuery, pgComment.ID, pgComment.Stars, pgComment.Text, pgComment.EditedAt)
+}
+
+func (cr *PostgresCommentRepository) GetByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	return cr.get(ctx, CommentGetByIDQuery, commentID)
+}
+
+func (cr *PostgresCommentRepository) DeleteByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	return cr.get(ctx, CommentDeleteByIDQuery, commentID)
+}
+
+func (cr *PostgresCommentRepository) get(ctx context.Context, query string, args ...interface{}) (domain.Comment, error) {
+	var comment entity2.PgComment
+	err := cr.connection.GetContext(ctx, &comment, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Comment{}, util.WrapError(ports.ErrCommentIDNotFound, err)
+		}
+		return domain.Comment{}, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	return comment.ToDomain(), nil
+}
+
 func (cr *PostgresCommentRepository) Delete(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) (domain.Comment, error) {
 	var deletedComment entity2.PgComment
 	err := cr.connection.GetContext(ctx, &deletedComment, CommentGetByUserAndTrackIDQuery, userID, trackID)
@@ -87,9 +170,36 @@ func (cr *PostgresCommentRepository) GetByUserID(ctx context.Context, userID uui
 	return domainComments, nil
 }
 
-func (cr *PostgresCommentRepository) GetByTrackID(ctx context.Context, trackID uuid.UUID) ([]domain.Comment, error) {
+func (cr *PostgresCommentRepository) GetByTrackID(ctx context.Context, trackID uuid.UUID,
+	page domain.CommentPage) ([]domain.Comment, int, error) {
+	query, ok := commentPageQueries[page.Sort]
+	if !ok {
+		query = CommentGetNewestByTrackIDQuery
+	}
+
+	var total int
+	err := cr.connection.GetContext(ctx, &total, CommentCountByTrackIDQuery, trackID)
+	if err != nil {
+		return nil, 0, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	var comments []entity2.PgComment
+	err = cr.connection.SelectContext(ctx, &comments, query, trackID, page.Limit, page.Offset)
+	if err != nil {
+		return nil, 0, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	domainComments := make([]domain.Comment, len(comments))
+	for i, comment := range comments {
+		domainComments[i] = comment.ToDomain()
+	}
+
+	return domainComments, total, nil
+}
+
+func (cr *PostgresCommentRepository) GetReplies(ctx context.Context, parentIDs []uuid.UUID) ([]domain.Comment, error) {
 	var comments []entity2.PgComment
-	err := cr.connection.SelectContext(ctx, &comments, CommentGetByTrackIDQuery, trackID)
+	err := cr.connection.SelectContext(ctx, &comments, CommentGetRepliesQuery, parentIDs)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalCommentRepo, err)
 	}
@@ -101,3 +211,107 @@ func (cr *PostgresCommentRepository) GetByTrackID(ctx context.Context, trackID u
 
 	return domainComments, nil
 }
+
+func (cr *PostgresCommentRepository) Hide(ctx context.Context, commentID uuid.UUID, hiddenBy domain.CommentHider,
+	hiddenAt time.Time) (domain.Comment, error) {
+	return cr.get(ctx, CommentHideQuery, commentID, hiddenAt, string(hiddenBy))
+}
+
+func (cr *PostgresCommentRepository) Restore(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	return cr.get(ctx, CommentRestoreQuery, commentID)
+}
+
+func (cr *PostgresCommentRepository) SetPinned(ctx context.Context, commentID uuid.UUID,
+	pinnedAt null.Time) (domain.Comment, error) {
+	return cr.get(ctx, CommentSetPinnedQuery, commentID, pinnedAt)
+}
+
+func (cr *PostgresCommentRepository) CreateReport(ctx context.Context,
+	report domain.CommentReport) (domain.CommentReport, error) {
+	pgReport := entity2.NewPgCommentReport(report)
+	var created entity2.PgCommentReport
+	err := cr.connection.GetContext(ctx, &created, CommentReportQuery,
+		pgReport.ID,
+		pgReport.CommentID,
+		pgReport.ReporterID,
+		pgReport.Reason,
+		pgReport.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return domain.CommentReport{}, util.WrapError(ports.ErrCommentReported, err)
+			case pgerrcode.ForeignKeyViolation:
+				return domain.CommentReport{}, util.WrapError(ports.ErrCommentIDNotFound, err)
+			}
+		}
+		return domain.CommentReport{}, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	return created.ToDomain(), nil
+}
+
+func (cr *PostgresCommentRepository) ResolveReports(ctx context.Context, commentID uuid.UUID,
+	resolvedAt time.Time) error {
+	_, err := cr.connection.ExecContext(ctx, CommentResolveReportsQuery, commentID, resolvedAt)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	return nil
+}
+
+func (cr *PostgresCommentRepository) GetModerationQueue(ctx context.Context, limit int,
+	offset int) ([]domain.ReportedComment, int, error) {
+	var total int
+	err := cr.connection.GetContext(ctx, &total, CommentCountReportedQuery)
+	if err != nil {
+		return nil, 0, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	var reported []entity2.PgReportedComment
+	err = cr.connection.SelectContext(ctx, &reported, CommentModerationQueueQuery, limit, offset)
+	if err != nil {
+		return nil, 0, util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	queue := make([]domain.ReportedComment, len(reported))
+	for i, comment := range reported {
+		queue[i] = comment.ToDomain()
+	}
+
+	return queue, total, nil
+}
+
+func (cr *PostgresCommentRepository) Vote(ctx context.Context, vote domain.CommentVote) error {
+	_, err := cr.connection.ExecContext(ctx, CommentVoteQuery, vote.CommentID, vote.UserID, vote.Helpful,
+		vote.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return util.WrapError(ports.ErrCommentIDNotFound, err)
+		}
+		return util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	return nil
+}
+
+func (cr *PostgresCommentRepository) DeleteVote(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error {
+	res, err := cr.connection.ExecContext(ctx, CommentDeleteVoteQuery, commentID, userID)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalCommentRepo, err)
+	}
+
+	if affected == 0 {
+		return ports.ErrCommentVoteNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	CreditInviteQuery            = "INSERT INTO album_musician(musician_id, album_id, is_owner, accepted) VALUES ($1, $2, FALSE, FALSE)"
+	CreditAcceptInviteQuery      = "UPDATE album_musician SET accepted = TRUE WHERE album_id = $1 AND musician_id = $2 AND accepted = FALSE"
+	CreditDeclineInviteQuery     = "DELETE FROM album_musician WHERE album_id = $1 AND musician_id = $2 AND accepted = FALSE"
+	CreditRemoveContributorQuery = "DELETE FROM album_musician WHERE album_id = $1 AND musician_id = $2 AND is_owner = FALSE"
+	CreditGetInvitesQuery        = "SELECT a.id, a.name, a.description, a.published, a.release_date, a.image_url, a.scheduled_release, a.unpublish_reason " +
+		"FROM album_musician am JOIN albums a ON a.id = am.album_id WHERE am.musician_id = $1 AND am.accepted = FALSE ORDER BY am.invited_at DESC"
+	CreditGetAlbumContributorsQuery = "SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url, m.verified_at, " +
+		"am.album_id, am.is_owner, am.accepted FROM album_musician am JOIN musicians m ON m.id = am.musician_id " +
+		"WHERE am.album_id = $1 ORDER BY am.is_owner DESC, am.invited_at"
+	CreditInsertTrackCreditQuery = "INSERT INTO track_credits(track_id, musician_id, role) VALUES ($1, $2, $3)"
+	CreditDeleteTrackCreditQuery = "DELETE FROM track_credits WHERE track_id = $1 AND musician_id = $2 AND role = $3"
+	// Accepted album contributors are credited as primary artists unless the
+	// track lists its primary artists explicitly.
+	CreditGetTrackCreditsQuery = "SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url, m.verified_at, " +
+		"tc.track_id, tc.role FROM track_credits tc JOIN musicians m ON m.id = tc.musician_id WHERE tc.track_id = $1 " +
+		"UNION ALL " +
+		"SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url, m.verified_at, " +
+		"t.id track_id, 'primary' AS role FROM tracks t JOIN album_musician am ON am.album_id = t.album_id " +
+		"JOIN musicians m ON m.id = am.musician_id WHERE t.id = $1 AND am.accepted = TRUE " +
+		"AND NOT EXISTS (SELECT 1 FROM track_credits WHERE track_id = $1 AND role = 'primary')"
+)
+
+type PostgresCreditRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresCreditRepository(connection *sqlx.DB) *PostgresCreditRepository {
+	return &PostgresCreditRepository{connection: connection}
+}
+
+func (cr *PostgresCreditRepository) InviteToAlbum(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	_, err := cr.connection.ExecContext(ctx, CreditInviteQuery, musicianID, albumID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return util.WrapError(ports.ErrAlreadyContributor, err)
+			case pgerrcode.ForeignKeyViolation:
+				return util.WrapError(ports.ErrMusicianIDNotFound, err)
+			}
+		}
+		return util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	return nil
+}
+
+func (cr *PostgresCreditRepository) AcceptInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	return cr.execAffecting(ctx, ports.ErrInviteNotFound, CreditAcceptInviteQuery, albumID, musicianID)
+}
+
+func (cr *PostgresCreditRepository) DeclineInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	return cr.execAffecting(ctx, ports.ErrInviteNotFound, CreditDeclineInviteQuery, albumID, musicianID)
+}
+
+func (cr *PostgresCreditRepository) RemoveContributor(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	return cr.execAffecting(ctx, ports.ErrContributorNotFound, CreditRemoveContributorQuery, albumID, musicianID)
+}
+
+func (cr *PostgresCreditRepository) GetInvites(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error) {
+	var albums []entity.PgAlbum
+	err := cr.connection.SelectContext(ctx, &albums, CreditGetInvitesQuery, musicianID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	domainAlbums := make([]domain.Album, len(albums))
+	for i, album := range albums {
+		domainAlbums[i] = album.ToDomain()
+	}
+
+	return domainAlbums, nil
+}
+
+func (cr *PostgresCreditRepository) GetAlbumContributors(ctx context.Context, albumID uuid.UUID) ([]domain.AlbumContributor, error) {
+	var contributors []entity.PgAlbumContributor
+	err := cr.connection.SelectContext(ctx, &contributors, CreditGetAlbumContributorsQuery, albumID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	domainContributors := make([]domain.AlbumContributor, len(contributors))
+	for i, contributor := range contributors {
+		domainContributors[i] = contributor.ToDomain()
+	}
+
+	return domainContributors, nil
+}
+
+func (cr *PostgresCreditRepository) AddTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error {
+	_, err := cr.connection.ExecContext(ctx, CreditInsertTrackCreditQuery, trackID, musicianID, string(role))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return util.WrapError(ports.ErrCreditDuplicate, err)
+			case pgerrcode.ForeignKeyViolation:
+				return util.WrapError(ports.ErrMusicianIDNotFound, err)
+			}
+		}
+		return util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	return nil
+}
+
+func (cr *PostgresCreditRepository) RemoveTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error {
+	return cr.execAffecting(ctx, ports.ErrCreditNotFound, CreditDeleteTrackCreditQuery, trackID, musicianID, string(role))
+}
+
+func (cr *PostgresCreditRepository) GetTrackCredits(ctx context.Context, trackID uuid.UUID) ([]domain.TrackCredit, error) {
+	var credits []entity.PgTrackCredit
+	err := cr.connection.SelectContext(ctx, &credits, CreditGetTrackCreditsQuery, trackID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	domainCredits := make([]domain.TrackCredit, len(credits))
+	for i, credit := range credits {
+		domainCredits[i] = credit.ToDomain()
+	}
+
+	return domainCredits, nil
+}
+
+func (cr *PostgresCreditRepository) execAffecting(ctx context.Context, notFound error, query string, args ...any) error {
+	res, err := cr.connection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalCreditRepo, err)
+	}
+
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
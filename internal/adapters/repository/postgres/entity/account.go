@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgAccount struct {
+	ID       uuid.UUID   `db:"id"`
+	Kind     string      `db:"kind"`
+	Name     string      `db:"name"`
+	Email    null.String `db:"email"`
+	Password string      `db:"password"`
+	Salt     string      `db:"salt"`
+}
+
+// PgAccountInfo is the account as it is read, with the verification status
+// joined in. Writes go through PgAccount, which has only the table columns.
+type PgAccountInfo struct {
+	PgAccount
+	EmailVerified bool `db:"email_verified"`
+}
+
+func (a *PgAccountInfo) ToDomain() domain.Account {
+	account := a.PgAccount.ToDomain()
+	account.EmailVerified = a.EmailVerified
+	return account
+}
+
+func (a *PgAccount) ToDomain() domain.Account {
+	return domain.Account{
+		ID:       a.ID,
+		Kind:     a.Kind,
+		Name:     a.Name,
+		Email:    a.Email.String,
+		Password: a.Password,
+		Salt:     a.Salt,
+	}
+}
+
+func NewPgAccount(account domain.Account) PgAccount {
+	return PgAccount{
+		ID:       account.ID,
+		Kind:     account.Kind,
+		Name:     account.Name,
+		Email:    null.NewString(account.Email, account.Email != ""),
+		Password: account.Password,
+		Salt:     account.Salt,
+	}
+}
+
+func NewPgUserAccount(user domain.User) PgAccount {
+	return NewPgAccount(domain.Account{
+		ID:       user.ID,
+		Kind:     domain.AccountKindUser,
+		Name:     user.Name,
+		Email:    user.Email,
+		Password: user.Password,
+		Salt:     user.Salt,
+	})
+}
+
+func NewPgMusicianAccount(musician domain.Musician) PgAccount {
+	return NewPgAccount(domain.Account{
+		ID:       musician.ID,
+		Kind:     domain.AccountKindMusician,
+		Name:     musician.Name,
+		Email:    musician.Email,
+		Password: musician.Password,
+		Salt:     musician.Salt,
+	})
+}
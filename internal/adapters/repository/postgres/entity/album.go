@@ -7,32 +7,38 @@ import (
 )
 
 type PgAlbum struct {
-	ID          uuid.UUID   `db:"id"`
-	Name        string      `db:"name"`
-	Description string      `db:"description"`
-	Published   bool        `db:"published"`
-	ReleaseDate null.Time   `db:"release_date"`
-	ImageURL    null.String `db:"image_url"`
+	ID               uuid.UUID   `db:"id"`
+	Name             string      `db:"name"`
+	Description      string      `db:"description"`
+	Published        bool        `db:"published"`
+	ReleaseDate      null.Time   `db:"release_date"`
+	ImageURL         null.String `db:"image_url"`
+	ScheduledRelease null.Time   `db:"scheduled_release"`
+	UnpublishReason  null.String `db:"unpublish_reason"`
 }
 
 func (a *PgAlbum) ToDomain() domain.Album {
 	return domain.Album{
-		ID:          a.ID,
-		Name:        a.Name,
-		Description: a.Description,
-		Published:   a.Published,
-		ReleaseDate: a.ReleaseDate,
-		ImageURL:    a.ImageURL,
+		ID:               a.ID,
+		Name:             a.Name,
+		Description:      a.Description,
+		Published:        a.Published,
+		ReleaseDate:      a.ReleaseDate,
+		ImageURL:         a.ImageURL,
+		ScheduledRelease: a.ScheduledRelease,
+		UnpublishReason:  a.UnpublishReason,
 	}
 }
 
 func NewPgAlbum(album domain.Album) PgAlbum {
 	return PgAlbum{
-		ID:          album.ID,
-		Name:        album.Name,
-		Description: album.Description,
-		Published:   album.Published,
-		ReleaseDate: album.ReleaseDate,
-		ImageURL:    album.ImageURL,
+		ID:               album.ID,
+		Name:             album.Name,
+		Description:      album.Description,
+		Published:        album.Published,
+		ReleaseDate:      album.ReleaseDate,
+		ImageURL:         album.ImageURL,
+		ScheduledRelease: album.ScheduledRelease,
+		UnpublishReason:  album.UnpublishReason,
 	}
 }
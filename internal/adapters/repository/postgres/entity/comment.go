@@ -1,34 +1,108 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
 type PgComment struct {
-	ID      uuid.UUID `db:"id"`
-	UserID  uuid.UUID `db:"user_id"`
-	TrackID uuid.UUID `db:"track_id"`
-	Stars   int       `db:"stars"`
-	Text    string    `db:"comment_text"`
+	ID             uuid.UUID     `db:"id"`
+	UserID         uuid.UUID     `db:"user_id"`
+	TrackID        uuid.UUID     `db:"track_id"`
+	ParentID       uuid.NullUUID `db:"parent_id"`
+	Stars          null.Int      `db:"stars"`
+	Text           string        `db:"comment_text"`
+	CreatedAt      time.Time     `db:"created_at"`
+	EditedAt       null.Time     `db:"edited_at"`
+	PinnedAt       null.Time     `db:"pinned_at"`
+	HiddenAt       null.Time     `db:"hidden_at"`
+	HiddenBy       null.String   `db:"hidden_by"`
+	HelpfulVotes   int           `db:"helpful_votes"`
+	UnhelpfulVotes int           `db:"unhelpful_votes"`
 }
 
 func (c *PgComment) ToDomain() domain.Comment {
 	return domain.Comment{
-		ID:      c.ID,
-		UserID:  c.UserID,
-		TrackID: c.TrackID,
-		Stars:   c.Stars,
-		Text:    c.Text,
+		ID:             c.ID,
+		UserID:         c.UserID,
+		TrackID:        c.TrackID,
+		ParentID:       c.ParentID.UUID,
+		Stars:          int(c.Stars.Int64),
+		Text:           c.Text,
+		CreatedAt:      c.CreatedAt,
+		EditedAt:       c.EditedAt,
+		PinnedAt:       c.PinnedAt,
+		HiddenAt:       c.HiddenAt,
+		HiddenBy:       domain.CommentHider(c.HiddenBy.ValueOrZero()),
+		HelpfulVotes:   c.HelpfulVotes,
+		UnhelpfulVotes: c.UnhelpfulVotes,
 	}
 }
 
 func NewPgComment(comment domain.Comment) PgComment {
 	return PgComment{
-		ID:      comment.ID,
-		UserID:  comment.UserID,
-		TrackID: comment.TrackID,
-		Stars:   comment.Stars,
-		Text:    comment.Text,
+		ID:             comment.ID,
+		UserID:         comment.UserID,
+		TrackID:        comment.TrackID,
+		ParentID:       uuid.NullUUID{UUID: comment.ParentID, Valid: comment.IsReply()},
+		Stars:          null.NewInt(int64(comment.Stars), !comment.IsReply()),
+		Text:           comment.Text,
+		CreatedAt:      comment.CreatedAt,
+		EditedAt:       comment.EditedAt,
+		PinnedAt:       comment.PinnedAt,
+		HiddenAt:       comment.HiddenAt,
+		HiddenBy:       null.NewString(string(comment.HiddenBy), comment.HiddenBy != ""),
+		HelpfulVotes:   comment.HelpfulVotes,
+		UnhelpfulVotes: comment.UnhelpfulVotes,
+	}
+}
+
+type PgCommentReport struct {
+	ID         uuid.UUID `db:"id"`
+	CommentID  uuid.UUID `db:"comment_id"`
+	ReporterID uuid.UUID `db:"reporter_id"`
+	Reason     string    `db:"reason"`
+	CreatedAt  time.Time `db:"created_at"`
+	ResolvedAt null.Time `db:"resolved_at"`
+}
+
+func (r *PgCommentReport) ToDomain() domain.CommentReport {
+	return domain.CommentReport{
+		ID:         r.ID,
+		CommentID:  r.CommentID,
+		ReporterID: r.ReporterID,
+		Reason:     r.Reason,
+		CreatedAt:  r.CreatedAt,
+		ResolvedAt: r.ResolvedAt,
+	}
+}
+
+func NewPgCommentReport(report domain.CommentReport) PgCommentReport {
+	return PgCommentReport{
+		ID:         report.ID,
+		CommentID:  report.CommentID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		CreatedAt:  report.CreatedAt,
+		ResolvedAt: report.ResolvedAt,
+	}
+}
+
+type PgReportedComment struct {
+	PgComment
+	Reports         int       `db:"reports"`
+	LastReason      string    `db:"last_reason"`
+	FirstReportedAt time.Time `db:"first_reported_at"`
+}
+
+func (r *PgReportedComment) ToDomain() domain.ReportedComment {
+	return domain.ReportedComment{
+		Comment:         r.PgComment.ToDomain(),
+		Reports:         r.Reports,
+		LastReason:      r.LastReason,
+		FirstReportedAt: r.FirstReportedAt,
 	}
 }
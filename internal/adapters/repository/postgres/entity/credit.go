@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgAlbumContributor struct {
+	PgMusician
+	AlbumID  uuid.UUID `db:"album_id"`
+	Owner    bool      `db:"is_owner"`
+	Accepted bool      `db:"accepted"`
+}
+
+func (c *PgAlbumContributor) ToDomain() domain.AlbumContributor {
+	return domain.AlbumContributor{
+		AlbumID:  c.AlbumID,
+		Musician: c.PgMusician.ToDomain(),
+		Owner:    c.Owner,
+		Accepted: c.Accepted,
+	}
+}
+
+type PgTrackCredit struct {
+	PgMusician
+	TrackID uuid.UUID `db:"track_id"`
+	Role    string    `db:"role"`
+}
+
+func (c *PgTrackCredit) ToDomain() domain.TrackCredit {
+	return domain.TrackCredit{
+		TrackID:  c.TrackID,
+		Musician: c.PgMusician.ToDomain(),
+		Role:     domain.CreditRole(c.Role),
+	}
+}
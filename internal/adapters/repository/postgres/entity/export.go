@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgDataExport struct {
+	ID          uuid.UUID `db:"id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Status      string    `db:"status"`
+	CreatedAt   time.Time `db:"created_at"`
+	StartedAt   null.Time `db:"started_at"`
+	CompletedAt null.Time `db:"completed_at"`
+	ExpiresAt   null.Time `db:"expires_at"`
+}
+
+func (e *PgDataExport) ToDomain() domain.DataExport {
+	return domain.DataExport{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		Status:      domain.DataExportStatus(e.Status),
+		CreatedAt:   e.CreatedAt,
+		StartedAt:   e.StartedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+func NewPgDataExport(export domain.DataExport) PgDataExport {
+	return PgDataExport{
+		ID:          export.ID,
+		UserID:      export.UserID,
+		Status:      string(export.Status),
+		CreatedAt:   export.CreatedAt,
+		StartedAt:   export.StartedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
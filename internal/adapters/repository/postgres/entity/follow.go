@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+// PgFollowerCount is a row of a follower count grouped by musician.
+type PgFollowerCount struct {
+	MusicianID uuid.UUID `db:"musician_id"`
+	Count      int64     `db:"count"`
+}
+
+type PgFeedItem struct {
+	UserID           uuid.UUID   `db:"user_id"`
+	MusicianID       uuid.UUID   `db:"musician_id"`
+	PublishedAt      time.Time   `db:"published_at"`
+	AlbumID          uuid.UUID   `db:"album_id"`
+	AlbumName        string      `db:"album_name"`
+	AlbumDescription string      `db:"album_description"`
+	AlbumPublished   bool        `db:"album_published"`
+	AlbumReleaseDate null.Time   `db:"album_release_date"`
+	AlbumImageURL    null.String `db:"album_image_url"`
+}
+
+func (f *PgFeedItem) ToDomain() domain.FeedItem {
+	return domain.FeedItem{
+		UserID:     f.UserID,
+		MusicianID: f.MusicianID,
+		Album: domain.Album{
+			ID:          f.AlbumID,
+			Name:        f.AlbumName,
+			Description: f.AlbumDescription,
+			Published:   f.AlbumPublished,
+			ReleaseDate: f.AlbumReleaseDate,
+			ImageURL:    f.AlbumImageURL,
+		},
+		PublishedAt: f.PublishedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgExternalIdentity struct {
+	Provider  string      `db:"provider"`
+	Subject   string      `db:"subject"`
+	AccountID uuid.UUID   `db:"account_id"`
+	Email     null.String `db:"email"`
+	CreatedAt time.Time   `db:"created_at"`
+}
+
+func (i *PgExternalIdentity) ToDomain() domain.ExternalIdentity {
+	return domain.ExternalIdentity{
+		Provider:  i.Provider,
+		Subject:   i.Subject,
+		AccountID: i.AccountID,
+		Email:     i.Email.String,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func NewPgExternalIdentity(identity domain.ExternalIdentity) PgExternalIdentity {
+	return PgExternalIdentity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		AccountID: identity.AccountID,
+		Email:     null.NewString(identity.Email, identity.Email != ""),
+		CreatedAt: identity.CreatedAt,
+	}
+}
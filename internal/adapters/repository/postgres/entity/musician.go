@@ -15,6 +15,7 @@ type PgMusician struct {
 	Country     string      `db:"country"`
 	Description string      `db:"description"`
 	ImageURL    null.String `db:"image_url"`
+	VerifiedAt  null.Time   `db:"verified_at"`
 }
 
 func (m *PgMusician) ToDomain() domain.Musician {
@@ -27,6 +28,7 @@ func (m *PgMusician) ToDomain() domain.Musician {
 		Country:     m.Country,
 		Description: m.Description,
 		ImageURL:    m.ImageURL,
+		VerifiedAt:  m.VerifiedAt,
 	}
 }
 
@@ -40,5 +42,47 @@ func NewPgMusician(musician domain.Musician) PgMusician {
 		Country:     musician.Country,
 		Description: musician.Description,
 		ImageURL:    musician.ImageURL,
+		VerifiedAt:  musician.VerifiedAt,
+	}
+}
+
+// PgMusicianProfile is the part of PgMusician stored in musician_profiles,
+// the rest lives in accounts.
+type PgMusicianProfile struct {
+	ID          uuid.UUID   `db:"id"`
+	Country     string      `db:"country"`
+	Description string      `db:"description"`
+	ImageURL    null.String `db:"image_url"`
+	VerifiedAt  null.Time   `db:"verified_at"`
+}
+
+func NewPgMusicianProfile(musician domain.Musician) PgMusicianProfile {
+	return PgMusicianProfile{
+		ID:          musician.ID,
+		Country:     musician.Country,
+		Description: musician.Description,
+		ImageURL:    musician.ImageURL,
+		VerifiedAt:  musician.VerifiedAt,
+	}
+}
+
+type PgMusicianLink struct {
+	MusicianID uuid.UUID `db:"musician_id"`
+	Kind       string    `db:"kind"`
+	URL        string    `db:"url"`
+}
+
+func (l *PgMusicianLink) ToDomain() domain.MusicianLink {
+	return domain.MusicianLink{
+		Kind: domain.MusicianLinkKind(l.Kind),
+		URL:  l.URL,
+	}
+}
+
+func NewPgMusicianLink(musicianID uuid.UUID, link domain.MusicianLink) PgMusicianLink {
+	return PgMusicianLink{
+		MusicianID: musicianID,
+		Kind:       string(link.Kind),
+		URL:        link.URL,
 	}
 }
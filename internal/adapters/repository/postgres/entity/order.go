@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgOrder struct {
+	ID             uuid.UUID   `db:"id"`
+	UserID         uuid.UUID   `db:"user_id"`
+	PlanID         uuid.UUID   `db:"plan_id"`
+	Amount         int64       `db:"amount"`
+	Status         string      `db:"status"`
+	IdempotencyKey string      `db:"idempotency_key"`
+	IntentID       null.String `db:"intent_id"`
+	CheckoutURL    null.String `db:"checkout_url"`
+	CreatedAt      time.Time   `db:"created_at"`
+	PaidAt         null.Time   `db:"paid_at"`
+}
+
+func (o *PgOrder) ToDomain() domain.Order {
+	return domain.Order{
+		ID:             o.ID,
+		UserID:         o.UserID,
+		PlanID:         o.PlanID,
+		Amount:         o.Amount,
+		Status:         domain.OrderStatus(o.Status),
+		IdempotencyKey: o.IdempotencyKey,
+		IntentID:       o.IntentID.String,
+		CheckoutURL:    o.CheckoutURL.String,
+		CreatedAt:      o.CreatedAt,
+		PaidAt:         o.PaidAt,
+	}
+}
+
+func NewPgOrder(order domain.Order) PgOrder {
+	return PgOrder{
+		ID:             order.ID,
+		UserID:         order.UserID,
+		PlanID:         order.PlanID,
+		Amount:         order.Amount,
+		Status:         string(order.Status),
+		IdempotencyKey: order.IdempotencyKey,
+		IntentID:       null.NewString(order.IntentID, order.IntentID != ""),
+		CheckoutURL:    null.NewString(order.CheckoutURL, order.CheckoutURL != ""),
+		CreatedAt:      order.CreatedAt,
+		PaidAt:         order.PaidAt,
+	}
+}
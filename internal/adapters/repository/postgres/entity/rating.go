@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+// PgRating holds the rating columns selected next to tracks and albums.
+type PgRating struct {
+	Count  int `db:"rating_count"`
+	Stars1 int `db:"rating_1"`
+	Stars2 int `db:"rating_2"`
+	Stars3 int `db:"rating_3"`
+	Stars4 int `db:"rating_4"`
+	Stars5 int `db:"rating_5"`
+}
+
+func (r *PgRating) ToDomain() domain.Rating {
+	return domain.Rating{
+		Count:     r.Count,
+		Histogram: [5]int{r.Stars1, r.Stars2, r.Stars3, r.Stars4, r.Stars5},
+	}
+}
+
+type PgRatedTrack struct {
+	PgTrack
+	PgRating
+}
+
+func (t *PgRatedTrack) ToDomain() domain.Track {
+	track := t.PgTrack.ToDomain()
+	track.Rating = t.PgRating.ToDomain()
+	return track
+}
+
+type PgRatedAlbum struct {
+	PgAlbum
+	PgRating
+}
+
+func (a *PgRatedAlbum) ToDomain() domain.Album {
+	album := a.PgAlbum.ToDomain()
+	album.Rating = a.PgRating.ToDomain()
+	return album
+}
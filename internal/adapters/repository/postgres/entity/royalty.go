@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgRoyaltyPeriod struct {
+	Start       time.Time `db:"period_start"`
+	End         time.Time `db:"period_end"`
+	Revenue     int64     `db:"revenue"`
+	Pool        int64     `db:"pool"`
+	Plays       int64     `db:"plays"`
+	AllocatedAt time.Time `db:"allocated_at"`
+}
+
+func (p *PgRoyaltyPeriod) ToDomain() domain.RoyaltyPeriod {
+	return domain.RoyaltyPeriod{
+		Start:       p.Start.UTC(),
+		End:         p.End.UTC(),
+		Revenue:     p.Revenue,
+		Pool:        p.Pool,
+		Plays:       p.Plays,
+		AllocatedAt: p.AllocatedAt,
+	}
+}
+
+type PgTrackMusicianPlays struct {
+	TrackID    uuid.UUID `db:"track_id"`
+	MusicianID uuid.UUID `db:"musician_id"`
+	Plays      int64     `db:"plays"`
+}
+
+type PgRoyaltyStatement struct {
+	MusicianID  uuid.UUID `db:"musician_id"`
+	Plays       int64     `db:"plays"`
+	Amount      int64     `db:"amount"`
+	PeriodStart time.Time `db:"period_start"`
+	PeriodEnd   time.Time `db:"period_end"`
+	Revenue     int64     `db:"revenue"`
+	Pool        int64     `db:"pool"`
+	PeriodPlays int64     `db:"period_plays"`
+	AllocatedAt time.Time `db:"allocated_at"`
+}
+
+func (s *PgRoyaltyStatement) ToDomain() domain.RoyaltyStatement {
+	return domain.RoyaltyStatement{
+		MusicianID: s.MusicianID,
+		Period: domain.RoyaltyPeriod{
+			Start:       s.PeriodStart.UTC(),
+			End:         s.PeriodEnd.UTC(),
+			Revenue:     s.Revenue,
+			Pool:        s.Pool,
+			Plays:       s.PeriodPlays,
+			AllocatedAt: s.AllocatedAt,
+		},
+		Plays:  s.Plays,
+		Amount: s.Amount,
+	}
+}
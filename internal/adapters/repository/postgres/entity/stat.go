@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
@@ -32,3 +34,17 @@ func (ugs *PgUserGenresStat) ToDomain() domain.UserGenresStat {
 		ListenCount: ugs.ListenCount,
 	}
 }
+
+type PgListenRecord struct {
+	TrackID         uuid.UUID `db:"track_id"`
+	ListenedAt      time.Time `db:"listened_at"`
+	ListenedSeconds int       `db:"listened_seconds"`
+}
+
+func (lr *PgListenRecord) ToDomain() domain.ListenRecord {
+	return domain.ListenRecord{
+		TrackID:         lr.TrackID,
+		ListenedAt:      lr.ListenedAt,
+		ListenedSeconds: lr.ListenedSeconds,
+	}
+}
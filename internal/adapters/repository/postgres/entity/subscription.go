@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgSubscriptionPlan struct {
+	ID           uuid.UUID `db:"id"`
+	Name         string    `db:"name"`
+	Price        int64     `db:"price"`
+	DurationDays int       `db:"duration_days"`
+}
+
+func (p *PgSubscriptionPlan) ToDomain() domain.SubscriptionPlan {
+	return domain.SubscriptionPlan{
+		ID:           p.ID,
+		Name:         p.Name,
+		Price:        p.Price,
+		DurationDays: p.DurationDays,
+	}
+}
+
+type PgSubscription struct {
+	ID             uuid.UUID     `db:"id"`
+	UserID         uuid.UUID     `db:"user_id"`
+	PlanID         uuid.NullUUID `db:"plan_id"`
+	StartDate      time.Time     `db:"start_date"`
+	ExpirationDate time.Time     `db:"expiration_date"`
+	Status         string        `db:"status"`
+}
+
+func (s *PgSubscription) ToDomain() domain.Subscription {
+	return domain.Subscription{
+		ID:             s.ID,
+		UserID:         s.UserID,
+		PlanID:         s.PlanID.UUID,
+		StartDate:      s.StartDate,
+		ExpirationDate: s.ExpirationDate,
+		Status:         domain.SubscriptionStatus(s.Status),
+	}
+}
+
+func NewPgSubscription(subscription domain.Subscription) PgSubscription {
+	return PgSubscription{
+		ID:             subscription.ID,
+		UserID:         subscription.UserID,
+		PlanID:         uuid.NullUUID{UUID: subscription.PlanID, Valid: subscription.PlanID != uuid.Nil},
+		StartDate:      subscription.StartDate,
+		ExpirationDate: subscription.ExpirationDate,
+		Status:         string(subscription.Status),
+	}
+}
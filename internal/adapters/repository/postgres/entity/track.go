@@ -6,26 +6,35 @@ import (
 )
 
 type PgTrack struct {
-	ID      uuid.UUID `db:"id"`
-	AlbumID uuid.UUID `db:"album_id"`
-	Name    string    `db:"name"`
-	URL     string    `db:"url"`
+	ID              uuid.UUID `db:"id"`
+	AlbumID         uuid.UUID `db:"album_id"`
+	Name            string    `db:"name"`
+	URL             string    `db:"url"`
+	DiscNumber      int       `db:"disc_number"`
+	TrackNumber     int       `db:"track_number"`
+	DurationSeconds int       `db:"duration_seconds"`
 }
 
 func (t *PgTrack) ToDomain() domain.Track {
 	return domain.Track{
-		ID:      t.ID,
-		AlbumID: t.AlbumID,
-		Name:    t.Name,
-		URL:     t.URL,
+		ID:              t.ID,
+		AlbumID:         t.AlbumID,
+		Name:            t.Name,
+		URL:             t.URL,
+		DiscNumber:      t.DiscNumber,
+		TrackNumber:     t.TrackNumber,
+		DurationSeconds: t.DurationSeconds,
 	}
 }
 
 func NewPgTrack(track domain.Track) PgTrack {
 	return PgTrack{
-		ID:      track.ID,
-		AlbumID: track.AlbumID,
-		Name:    track.Name,
-		URL:     track.URL,
+		ID:              track.ID,
+		AlbumID:         track.AlbumID,
+		Name:            track.Name,
+		URL:             track.URL,
+		DiscNumber:      track.DiscNumber,
+		TrackNumber:     track.TrackNumber,
+		DurationSeconds: track.DurationSeconds,
 	}
 }
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type PgTwoFactor struct {
+	AccountID    uuid.UUID `db:"account_id"`
+	Secret       string    `db:"secret"`
+	EnabledAt    null.Time `db:"enabled_at"`
+	LastUsedStep int64     `db:"last_used_step"`
+}
+
+func (t *PgTwoFactor) ToDomain() domain.TwoFactor {
+	return domain.TwoFactor{
+		AccountID:    t.AccountID,
+		Secret:       t.Secret,
+		Enabled:      t.EnabledAt.Valid,
+		LastUsedStep: t.LastUsedStep,
+	}
+}
@@ -2,39 +2,71 @@ package entity
 
 import (
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
 type PgUser struct {
-	ID       uuid.UUID `db:"id"`
-	Name     string    `db:"name"`
-	Email    string    `db:"email"`
-	Phone    string    `db:"phone"`
-	Password string    `db:"password"`
-	Salt     string    `db:"salt"`
-	Country  string    `db:"country"`
+	ID                  uuid.UUID   `db:"id"`
+	Name                string      `db:"name"`
+	Email               string      `db:"email"`
+	Phone               string      `db:"phone"`
+	Password            string      `db:"password"`
+	Salt                string      `db:"salt"`
+	Country             string      `db:"country"`
+	Role                int         `db:"role"`
+	AvatarURL           null.String `db:"avatar_url"`
+	DeletionScheduledAt null.Time   `db:"deletion_scheduled_at"`
 }
 
 func (u *PgUser) ToDomain() domain.User {
 	return domain.User{
-		ID:       u.ID,
-		Name:     u.Name,
-		Email:    u.Email,
-		Phone:    u.Phone,
-		Password: u.Password,
-		Salt:     u.Salt,
-		Country:  u.Country,
+		ID:                  u.ID,
+		Name:                u.Name,
+		Email:               u.Email,
+		Phone:               u.Phone,
+		Password:            u.Password,
+		Salt:                u.Salt,
+		Country:             u.Country,
+		Role:                u.Role,
+		AvatarURL:           u.AvatarURL,
+		DeletionScheduledAt: u.DeletionScheduledAt,
 	}
 }
 
 func NewPgUser(user domain.User) PgUser {
 	return PgUser{
-		ID:       user.ID,
-		Name:     user.Name,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		Password: user.Password,
-		Salt:     user.Salt,
-		Country:  user.Country,
+		ID:                  user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		Phone:               user.Phone,
+		Password:            user.Password,
+		Salt:                user.Salt,
+		Country:             user.Country,
+		Role:                user.Role,
+		AvatarURL:           user.AvatarURL,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}
+}
+
+// PgUserProfile is the part of PgUser stored in user_profiles, the rest lives
+// in accounts.
+type PgUserProfile struct {
+	ID                  uuid.UUID   `db:"id"`
+	Phone               string      `db:"phone"`
+	Country             string      `db:"country"`
+	Role                int         `db:"role"`
+	AvatarURL           null.String `db:"avatar_url"`
+	DeletionScheduledAt null.Time   `db:"deletion_scheduled_at"`
+}
+
+func NewPgUserProfile(user domain.User) PgUserProfile {
+	return PgUserProfile{
+		ID:                  user.ID,
+		Phone:               user.Phone,
+		Country:             user.Country,
+		Role:                user.Role,
+		AvatarURL:           user.AvatarURL,
+		DeletionScheduledAt: user.DeletionScheduledAt,
 	}
 }
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	dataExportColumns     = "id, user_id, status, created_at, started_at, completed_at, expires_at"
+	DataExportInsertQuery = "INSERT INTO data_exports (id, user_id, status, created_at) " +
+		"VALUES ($1, $2, $3, $4) RETURNING " + dataExportColumns
+	// A stale export is claimed again with a new start time, the run that
+	// started it before no longer owns it then.
+	DataExportFinishQuery = "UPDATE data_exports SET status = $2, completed_at = $3, expires_at = $4 " +
+		"WHERE id = $1 AND status = 'running' AND started_at = $5 RETURNING " + dataExportColumns
+	DataExportGetByIDQuery     = "SELECT " + dataExportColumns + " FROM data_exports WHERE id = $1"
+	DataExportGetByUserIDQuery = "SELECT " + dataExportColumns + " FROM data_exports WHERE user_id = $1"
+	// Several instances may run the export job, SKIP LOCKED hands each export
+	// to one of them.
+	DataExportClaimQuery = "UPDATE data_exports SET status = 'running', started_at = $1 WHERE id IN " +
+		"(SELECT id FROM data_exports WHERE status = 'pending' OR (status = 'running' AND started_at < $2) " +
+		"ORDER BY created_at LIMIT $3 FOR UPDATE SKIP LOCKED) RETURNING " + dataExportColumns
+)
+
+type PostgresDataExportRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresDataExportRepository(connection *sqlx.DB) *PostgresDataExportRepository {
+	return &PostgresDataExportRepository{connection: connection}
+}
+
+func (er *PostgresDataExportRepository) Create(ctx context.Context, export domain.DataExport) (domain.DataExport, error) {
+	pgExport := entity2.NewPgDataExport(export)
+	var created entity2.PgDataExport
+	err := er.connection.GetContext(ctx, &created, DataExportInsertQuery,
+		pgExport.ID,
+		pgExport.UserID,
+		pgExport.Status,
+		pgExport.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return domain.DataExport{}, util.WrapError(ports.ErrDataExportInProgress, err)
+			case pgerrcode.ForeignKeyViolation:
+				return domain.DataExport{}, util.WrapError(ports.ErrUserIDNotFound, err)
+			}
+		}
+		return domain.DataExport{}, util.WrapError(ports.ErrInternalDataExportRepo, err)
+	}
+
+	return created.ToDomain(), nil
+}
+
+func (er *PostgresDataExportRepository) Finish(ctx context.Context, export domain.DataExport) (domain.DataExport, error) {
+	pgExport := entity2.NewPgDataExport(export)
+	finished, err := er.get(ctx, DataExportFinishQuery,
+		pgExport.ID,
+		pgExport.Status,
+		pgExport.CompletedAt,
+		pgExport.ExpiresAt,
+		pgExport.StartedAt)
+	if errors.Is(err, ports.ErrDataExportIDNotFound) {
+		return domain.DataExport{}, util.WrapError(ports.ErrDataExportLost, err)
+	}
+
+	return finished, err
+}
+
+func (er *PostgresDataExportRepository) GetByID(ctx context.Context, exportID uuid.UUID) (domain.DataExport, error) {
+	return er.get(ctx, DataExportGetByIDQuery, exportID)
+}
+
+func (er *PostgresDataExportRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.DataExport, error) {
+	var exports []entity2.PgDataExport
+	err := er.connection.SelectContext(ctx, &exports, DataExportGetByUserIDQuery, userID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalDataExportRepo, err)
+	}
+
+	domainExports := make([]domain.DataExport, len(exports))
+	for i, export := range exports {
+		domainExports[i] = export.ToDomain()
+	}
+
+	return domainExports, nil
+}
+
+func (er *PostgresDataExportRepository) ClaimPending(ctx context.Context, now time.Time, staleBefore time.Time,
+	limit int) ([]domain.DataExport, error) {
+	var exports []entity2.PgDataExport
+	err := er.connection.SelectContext(ctx, &exports, DataExportClaimQuery, now, staleBefore, limit)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalDataExportRepo, err)
+	}
+
+	domainExports := make([]domain.DataExport, len(exports))
+	for i, export := range exports {
+		domainExports[i] = export.ToDomain()
+	}
+
+	return domainExports, nil
+}
+
+func (er *PostgresDataExportRepository) get(ctx context.Context, query string,
+	args ...interface{}) (domain.DataExport, error) {
+	var export entity2.PgDataExport
+	err := er.connection.GetContext(ctx, &export, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DataExport{}, util.WrapError(ports.ErrDataExportIDNotFound, err)
+		}
+		return domain.DataExport{}, util.WrapError(ports.ErrInternalDataExportRepo, err)
+	}
+
+	return export.ToDomain(), nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	FollowInsertQuery         = "INSERT INTO musician_followers(user_id, musician_id) VALUES ($1, $2)"
+	FollowDeleteQuery         = "DELETE FROM musician_followers WHERE user_id = $1 AND musician_id = $2"
+	FollowGetFollowingQuery   = "SELECT m.* FROM musicians m JOIN musician_followers mf ON m.id = mf.musician_id WHERE mf.user_id = $1 ORDER BY mf.created_at DESC"
+	FollowCountFollowersQuery = "SELECT count(*) FROM musician_followers WHERE musician_id = $1"
+	FollowCountBatchQuery     = "SELECT musician_id, count(*) FROM musician_followers WHERE musician_id = ANY($1) GROUP BY musician_id"
+	FeedInsertQuery           = "INSERT INTO feed(user_id, musician_id, album_id, published_at) " +
+		"SELECT mf.user_id, mf.musician_id, am.album_id, $2 FROM musician_followers mf " +
+		"JOIN album_musician am ON am.musician_id = mf.musician_id " +
+		"WHERE am.album_id = $1 AND am.accepted = TRUE ON CONFLICT DO NOTHING"
+	FeedGetByUserIDQuery = "SELECT f.user_id, f.musician_id, f.published_at, a.id album_id, a.name album_name, " +
+		"a.description album_description, a.published album_published, a.release_date album_release_date, " +
+		"a.image_url album_image_url FROM feed f JOIN albums a ON a.id = f.album_id " +
+		"WHERE f.user_id = $1 AND a.published = TRUE ORDER BY f.published_at DESC"
+)
+
+type PostgresFollowRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresFollowRepository(connection *sqlx.DB) *PostgresFollowRepository {
+	return &PostgresFollowRepository{connection: connection}
+}
+
+func (fr *PostgresFollowRepository) Follow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error {
+	_, err := fr.connection.ExecContext(ctx, FollowInsertQuery, userID, musicianID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return util.WrapError(ports.ErrAlreadyFollowing, err)
+			case pgerrcode.ForeignKeyViolation:
+				return util.WrapError(ports.ErrMusicianIDNotFound, err)
+			}
+		}
+		return util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	return nil
+}
+
+func (fr *PostgresFollowRepository) Unfollow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error {
+	res, err := fr.connection.ExecContext(ctx, FollowDeleteQuery, userID, musicianID)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	if affected == 0 {
+		return ports.ErrNotFollowing
+	}
+
+	return nil
+}
+
+func (fr *PostgresFollowRepository) GetFollowing(ctx context.Context, userID uuid.UUID) ([]domain.Musician, error) {
+	var musicians []entity.PgMusician
+	err := fr.connection.SelectContext(ctx, &musicians, FollowGetFollowingQuery, userID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	domainMusicians := make([]domain.Musician, len(musicians))
+	for i, musician := range musicians {
+		domainMusicians[i] = musician.ToDomain()
+	}
+
+	return domainMusicians, nil
+}
+
+func (fr *PostgresFollowRepository) CountFollowers(ctx context.Context, musicianID uuid.UUID) (int64, error) {
+	var count int64
+	err := fr.connection.GetContext(ctx, &count, FollowCountFollowersQuery, musicianID)
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	return count, nil
+}
+
+// CountFollowersByIDs counts followers of several musicians in one query.
+// Musicians without followers are missing from the result.
+func (fr *PostgresFollowRepository) CountFollowersByIDs(ctx context.Context,
+	musicianIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	var counts []entity.PgFollowerCount
+	err := fr.connection.SelectContext(ctx, &counts, FollowCountBatchQuery, musicianIDs)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	followers := make(map[uuid.UUID]int64, len(counts))
+	for _, count := range counts {
+		followers[count.MusicianID] = count.Count
+	}
+
+	return followers, nil
+}
+
+func (fr *PostgresFollowRepository) AddToFeed(ctx context.Context, albumID uuid.UUID, publishedAt time.Time) error {
+	_, err := fr.connection.ExecContext(ctx, FeedInsertQuery, albumID, publishedAt)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	return nil
+}
+
+func (fr *PostgresFollowRepository) GetFeed(ctx context.Context, userID uuid.UUID) ([]domain.FeedItem, error) {
+	var items []entity.PgFeedItem
+	err := fr.connection.SelectContext(ctx, &items, FeedGetByUserIDQuery, userID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalFollowRepo, err)
+	}
+
+	domainItems := make([]domain.FeedItem, len(items))
+	for i, item := range items {
+		domainItems[i] = item.ToDomain()
+	}
+
+	return domainItems, nil
+}
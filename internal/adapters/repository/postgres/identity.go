@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	IdentityGetQuery = "SELECT provider, subject, account_id, email, created_at FROM account_identities " +
+		"WHERE provider = $1 AND subject = $2"
+	IdentityGetByAccountIDQuery = "SELECT provider, subject, account_id, email, created_at FROM account_identities " +
+		"WHERE account_id = $1 ORDER BY provider"
+	IdentityInsertQuery = "INSERT INTO account_identities (provider, subject, account_id, email) VALUES ($1, $2, $3, $4)"
+	IdentityDeleteQuery = "DELETE FROM account_identities WHERE account_id = $1 AND provider = $2"
+)
+
+type PostgresExternalIdentityRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresExternalIdentityRepository(connection *sqlx.DB) *PostgresExternalIdentityRepository {
+	return &PostgresExternalIdentityRepository{connection: connection}
+}
+
+func (ir *PostgresExternalIdentityRepository) Get(ctx context.Context, provider string, subject string) (domain.ExternalIdentity, error) {
+	var identity entity2.PgExternalIdentity
+	err := ir.connection.GetContext(ctx, &identity, IdentityGetQuery, provider, subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ExternalIdentity{}, util.WrapError(ports.ErrOIDCIdentityNotFound, err)
+		}
+		return domain.ExternalIdentity{}, util.WrapError(ports.ErrInternalIdentityRepo, err)
+	}
+
+	return identity.ToDomain(), nil
+}
+
+func (ir *PostgresExternalIdentityRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]domain.ExternalIdentity, error) {
+	var identities []entity2.PgExternalIdentity
+	err := ir.connection.SelectContext(ctx, &identities, IdentityGetByAccountIDQuery, accountID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalIdentityRepo, err)
+	}
+
+	domainIdentities := make([]domain.ExternalIdentity, len(identities))
+	for i, identity := range identities {
+		domainIdentities[i] = identity.ToDomain()
+	}
+
+	return domainIdentities, nil
+}
+
+func (ir *PostgresExternalIdentityRepository) Create(ctx context.Context, identity domain.ExternalIdentity) error {
+	pgIdentity := entity2.NewPgExternalIdentity(identity)
+	_, err := ir.connection.ExecContext(ctx, IdentityInsertQuery,
+		pgIdentity.Provider, pgIdentity.Subject, pgIdentity.AccountID, pgIdentity.Email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return util.WrapError(ports.ErrOIDCIdentityAlreadyLinked, err)
+			case pgerrcode.ForeignKeyViolation:
+				return util.WrapError(ports.ErrAccountNotFound, err)
+			}
+		}
+		return util.WrapError(ports.ErrInternalIdentityRepo, err)
+	}
+
+	return nil
+}
+
+func (ir *PostgresExternalIdentityRepository) Delete(ctx context.Context, accountID uuid.UUID, provider string) error {
+	result, err := ir.connection.ExecContext(ctx, IdentityDeleteQuery, accountID, provider)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalIdentityRepo, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalIdentityRepo, err)
+	}
+
+	if affected == 0 {
+		return ports.ErrOIDCIdentityNotFound
+	}
+
+	return nil
+}
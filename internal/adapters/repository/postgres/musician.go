@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
@@ -20,10 +21,19 @@ const (
 	MusicianGetByIDQuery      = "SELECT * FROM musicians WHERE id = $1"
 	MusicianGetByNameQuery    = "SELECT * FROM musicians WHERE name = $1"
 	MusicianGetByEmailQuery   = "SELECT * FROM musicians WHERE email = $1"
-	MusicianGetByAlbumIDQuery = "SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url FROM musicians m JOIN public.album_musician am on m.id = am.musician_id WHERE album_id = $1"
-	MusicianGetByTrackIDQuery = "SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url FROM musicians m JOIN public.album_musician am on m.id = am.musician_id JOIN public.tracks t ON am.album_id = t.album_id WHERE t.id = $1"
+	MusicianGetByAlbumIDQuery = "SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url, m.verified_at FROM musicians m JOIN public.album_musician am on m.id = am.musician_id WHERE album_id = $1 AND am.is_owner = TRUE"
+	MusicianGetByTrackIDQuery = "SELECT m.id, m.name, m.email, m.salt, m.password, m.country, m.description, m.image_url, m.verified_at FROM musicians m JOIN public.album_musician am on m.id = am.musician_id JOIN public.tracks t ON am.album_id = t.album_id WHERE t.id = $1 AND am.is_owner = TRUE"
+	MusicianGetLinksQuery     = "SELECT musician_id, kind, url FROM musician_links WHERE musician_id = $1 ORDER BY kind"
+	MusicianDeleteLinksQuery  = "DELETE FROM musician_links WHERE musician_id = $1"
+	MusicianInsertLinkQuery   = "INSERT INTO musician_links (musician_id, kind, url) VALUES ($1, $2, $3)"
+	MusicianSetProfileQuery   = "UPDATE musician_profiles SET country = $2, description = $3 WHERE id = $1"
+	MusicianSetNameQuery      = "UPDATE accounts SET name = $2 WHERE id = $1 AND name <> $2"
+	MusicianUnverifyQuery     = "UPDATE musician_profiles SET verified_at = NULL WHERE id = $1"
+	MusicianSetVerifiedQuery  = "UPDATE musician_profiles SET verified_at = $2 WHERE id = $1"
 )
 
+// PostgresMusicianRepository reads musicians from the musicians view, which joins
+// musician_profiles with accounts, and writes both tables in one transaction.
 type PostgresMusicianRepository struct {
 	connection *sqlx.DB
 }
@@ -33,9 +43,18 @@ func NewPostgresMusicianRepository(connection *sqlx.DB) *PostgresMusicianReposit
 }
 
 func (mr *PostgresMusicianRepository) Create(ctx context.Context, musician domain.Musician) (domain.Musician, error) {
-	pgMusician := entity2.NewPgMusician(musician)
-	queryString := entity2.InsertQueryString(pgMusician, "musicians")
-	_, err := mr.connection.NamedExecContext(ctx, queryString, pgMusician)
+	tx, err := mr.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrInternalMusicianRepo, err)
+	}
+	defer tx.Rollback()
+
+	pgAccount := entity2.NewPgMusicianAccount(musician)
+	_, err = tx.NamedExecContext(ctx, entity2.InsertQueryString(pgAccount, "accounts"), pgAccount)
+	if err == nil {
+		pgProfile := entity2.NewPgMusicianProfile(musician)
+		_, err = tx.NamedExecContext(ctx, entity2.InsertQueryString(pgProfile, "musician_profiles"), pgProfile)
+	}
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -46,8 +65,13 @@ func (mr *PostgresMusicianRepository) Create(ctx context.Context, musician domai
 		return domain.Musician{}, util.WrapError(ports.ErrInternalMusicianRepo, err)
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrInternalMusicianRepo, err)
+	}
+
 	var createdMusician entity2.PgMusician
-	err = mr.connection.GetContext(ctx, &createdMusician, MusicianGetByIDQuery, pgMusician.ID)
+	err = mr.connection.GetContext(ctx, &createdMusician, MusicianGetByIDQuery, musician.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Musician{}, util.WrapError(ports.ErrMusicianIDNotFound, err)
@@ -59,15 +83,35 @@ func (mr *PostgresMusicianRepository) Create(ctx context.Context, musician domai
 }
 
 func (mr *PostgresMusicianRepository) Update(ctx context.Context, musician domain.Musician) (domain.Musician, error) {
-	pgMusician := entity2.NewPgMusician(musician)
-	queryString := entity2.UpdateQueryString(pgMusician, "musicians")
-	_, err := mr.connection.NamedExecContext(ctx, queryString, pgMusician)
+	tx, err := mr.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrInternalMusicianRepo, err)
+	}
+	defer tx.Rollback()
+
+	pgAccount := entity2.NewPgMusicianAccount(musician)
+	_, err = tx.NamedExecContext(ctx, entity2.UpdateQueryString(pgAccount, "accounts"), pgAccount)
+	if err == nil {
+		pgProfile := entity2.NewPgMusicianProfile(musician)
+		_, err = tx.NamedExecContext(ctx, entity2.UpdateQueryString(pgProfile, "musician_profiles"), pgProfile)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == pgerrcode.UniqueViolation {
+				return domain.Musician{}, util.WrapError(ports.ErrMusicianDuplicate, err)
+			}
+		}
+		return domain.Musician{}, util.WrapError(ports.ErrMusicianUpdate, err)
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return domain.Musician{}, util.WrapError(ports.ErrMusicianUpdate, err)
 	}
 
 	var updatedMusician entity2.PgMusician
-	err = mr.connection.GetContext(ctx, &updatedMusician, MusicianGetByIDQuery, pgMusician.ID)
+	err = mr.connection.GetContext(ctx, &updatedMusician, MusicianGetByIDQuery, musician.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Musician{}, util.WrapError(ports.ErrMusicianIDNotFound, err)
@@ -157,3 +201,118 @@ func (mr *PostgresMusicianRepository) GetByTrackID(ctx context.Context, trackID
 
 	return foundMusician.ToDomain(), nil
 }
+
+func (mr *PostgresMusicianRepository) GetLinks(ctx context.Context, musicianID uuid.UUID) ([]domain.MusicianLink, error) {
+	var links []entity2.PgMusicianLink
+	err := mr.connection.SelectContext(ctx, &links, MusicianGetLinksQuery, musicianID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalMusicianRepo, err)
+	}
+
+	domainLinks := make([]domain.MusicianLink, len(links))
+	for i, link := range links {
+		domainLinks[i] = link.ToDomain()
+	}
+
+	return domainLinks, nil
+}
+
+// UpdateProfile writes the editable profile fields and, unless links is nil,
+// replaces the musician links in one transaction. Verification vouches for
+// the artist behind the name, so an actual rename drops it. It never touches
+// the email, password or image, which have their own flows.
+func (mr *PostgresMusicianRepository) UpdateProfile(ctx context.Context, musician domain.Musician,
+	links []domain.MusicianLink) (domain.Musician, error) {
+	tx, err := mr.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrInternalMusicianRepo, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, MusicianSetProfileQuery, musician.ID, musician.Country, musician.Description)
+	if err == nil {
+		var rows int64
+		rows, err = res.RowsAffected()
+		if err == nil && rows == 0 {
+			return domain.Musician{}, ports.ErrMusicianIDNotFound
+		}
+	}
+	if err == nil {
+		res, err = tx.ExecContext(ctx, MusicianSetNameQuery, musician.ID, musician.Name)
+	}
+	if err == nil {
+		var renamed int64
+		renamed, err = res.RowsAffected()
+		if err == nil && renamed > 0 {
+			_, err = tx.ExecContext(ctx, MusicianUnverifyQuery, musician.ID)
+		}
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == pgerrcode.UniqueViolation {
+				return domain.Musician{}, util.WrapError(ports.ErrMusicianDuplicate, err)
+			}
+		}
+		return domain.Musician{}, util.WrapError(ports.ErrMusicianUpdate, err)
+	}
+
+	if links != nil {
+		err = replaceLinks(ctx, tx, musician.ID, links)
+		if err != nil {
+			return domain.Musician{}, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrMusicianUpdate, err)
+	}
+
+	return mr.GetByID(ctx, musician.ID)
+}
+
+// replaceLinks replaces all links of the musician.
+func replaceLinks(ctx context.Context, tx *sqlx.Tx, musicianID uuid.UUID, links []domain.MusicianLink) error {
+	_, err := tx.ExecContext(ctx, MusicianDeleteLinksQuery, musicianID)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalMusicianRepo, err)
+	}
+
+	for _, link := range links {
+		pgLink := entity2.NewPgMusicianLink(musicianID, link)
+		_, err = tx.ExecContext(ctx, MusicianInsertLinkQuery, pgLink.MusicianID, pgLink.Kind, pgLink.URL)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case pgerrcode.ForeignKeyViolation:
+					return util.WrapError(ports.ErrMusicianIDNotFound, err)
+				case pgerrcode.UniqueViolation, pgerrcode.CheckViolation:
+					return util.WrapError(ports.ErrMusicianInvalidLink, err)
+				}
+			}
+			return util.WrapError(ports.ErrInternalMusicianRepo, err)
+		}
+	}
+
+	return nil
+}
+
+func (mr *PostgresMusicianRepository) SetVerifiedAt(ctx context.Context, musicianID uuid.UUID,
+	verifiedAt null.Time) (domain.Musician, error) {
+	res, err := mr.connection.ExecContext(ctx, MusicianSetVerifiedQuery, musicianID, verifiedAt)
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrMusicianUpdate, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return domain.Musician{}, util.WrapError(ports.ErrMusicianUpdate, err)
+	}
+	if rows == 0 {
+		return domain.Musician{}, ports.ErrMusicianIDNotFound
+	}
+
+	return mr.GetByID(ctx, musicianID)
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	orderColumns     = "id, user_id, plan_id, amount, status, idempotency_key, intent_id, checkout_url, created_at, paid_at"
+	OrderInsertQuery = "INSERT INTO orders (id, user_id, plan_id, amount, status, idempotency_key) " +
+		"VALUES ($1, $2, $3, $4, $5, $6) RETURNING " + orderColumns
+	OrderGetByIDQuery             = "SELECT " + orderColumns + " FROM orders WHERE id = $1"
+	OrderGetByIdempotencyKeyQuery = "SELECT " + orderColumns + " FROM orders WHERE user_id = $1 AND idempotency_key = $2"
+	OrderGetByIntentIDQuery       = "SELECT " + orderColumns + " FROM orders WHERE intent_id = $1"
+	OrderSetIntentQuery           = "UPDATE orders SET intent_id = $2, checkout_url = $3 WHERE id = $1"
+	OrderUpdateStatusQuery        = "UPDATE orders SET status = $3, " +
+		"paid_at = CASE $3 WHEN 'paid' THEN now() WHEN 'pending' THEN NULL ELSE paid_at END " +
+		"WHERE id = $1 AND status = $2"
+)
+
+type PostgresOrderRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresOrderRepository(connection *sqlx.DB) *PostgresOrderRepository {
+	return &PostgresOrderRepository{connection: connection}
+}
+
+func (or *PostgresOrderRepository) Create(ctx context.Context, order domain.Order) (domain.Order, error) {
+	pgOrder := entity2.NewPgOrder(order)
+	var created entity2.PgOrder
+	err := or.connection.GetContext(ctx, &created, OrderInsertQuery,
+		pgOrder.ID,
+		pgOrder.UserID,
+		pgOrder.PlanID,
+		pgOrder.Amount,
+		pgOrder.Status,
+		pgOrder.IdempotencyKey)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return domain.Order{}, util.WrapError(ports.ErrOrderDuplicate, err)
+			case pgerrcode.ForeignKeyViolation:
+				return domain.Order{}, util.WrapError(ports.ErrSubscriptionPlanNotFound, err)
+			}
+		}
+		return domain.Order{}, util.WrapError(ports.ErrInternalOrderRepo, err)
+	}
+
+	return created.ToDomain(), nil
+}
+
+func (or *PostgresOrderRepository) GetByID(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	return or.get(ctx, OrderGetByIDQuery, orderID)
+}
+
+func (or *PostgresOrderRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID,
+	idempotencyKey string) (domain.Order, error) {
+	return or.get(ctx, OrderGetByIdempotencyKeyQuery, userID, idempotencyKey)
+}
+
+func (or *PostgresOrderRepository) GetByIntentID(ctx context.Context, intentID string) (domain.Order, error) {
+	return or.get(ctx, OrderGetByIntentIDQuery, intentID)
+}
+
+func (or *PostgresOrderRepository) get(ctx context.Context, query string, args ...interface{}) (domain.Order, error) {
+	var order entity2.PgOrder
+	err := or.connection.GetContext(ctx, &order, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Order{}, util.WrapError(ports.ErrOrderNotFound, err)
+		}
+		return domain.Order{}, util.WrapError(ports.ErrInternalOrderRepo, err)
+	}
+
+	return order.ToDomain(), nil
+}
+
+func (or *PostgresOrderRepository) SetIntent(ctx context.Context, orderID uuid.UUID, intent domain.PaymentIntent) error {
+	return or.execAffecting(ctx, ports.ErrOrderNotFound, OrderSetIntentQuery, orderID, intent.ID, intent.CheckoutURL)
+}
+
+func (or *PostgresOrderRepository) UpdateStatus(ctx context.Context, orderID uuid.UUID, from domain.OrderStatus,
+	to domain.OrderStatus) error {
+	return or.execAffecting(ctx, ports.ErrOrderStatusChanged, OrderUpdateStatusQuery, orderID, string(from), string(to))
+}
+
+func (or *PostgresOrderRepository) execAffecting(ctx context.Context, notAffected error, query string,
+	args ...interface{}) error {
+	result, err := or.connection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalOrderRepo, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalOrderRepo, err)
+	}
+
+	if affected == 0 {
+		return notAffected
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	royaltyPeriodColumns      = "period_start, period_end, revenue, pool, plays, allocated_at"
+	RoyaltyGetLastPeriodQuery = "SELECT " + royaltyPeriodColumns + " FROM royalty_periods ORDER BY period_start DESC LIMIT 1"
+	RoyaltyGetRevenueQuery    = "SELECT COALESCE(SUM(amount), 0) FROM orders WHERE status = 'paid' AND paid_at >= $1 AND paid_at < $2"
+	RoyaltyGetTrackPlaysQuery = "SELECT uh.track_id, am.musician_id, count(*) plays FROM users_history uh " +
+		"JOIN tracks t ON t.id = uh.track_id " +
+		"JOIN album_musician am ON am.album_id = t.album_id AND am.accepted = TRUE " +
+		"WHERE uh.listened_at >= $1 AND uh.listened_at < $2 AND uh.listened_seconds >= $3 " +
+		"AND EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = uh.user_id " +
+		"AND s.start_date <= uh.listened_at AND s.expiration_date > uh.listened_at) " +
+		"GROUP BY uh.track_id, am.musician_id ORDER BY uh.track_id, am.musician_id"
+	RoyaltyInsertPeriodQuery = "INSERT INTO royalty_periods (period_start, period_end, revenue, pool, plays) " +
+		"VALUES ($1, $2, $3, $4, $5)"
+	RoyaltyInsertEntryQuery = "INSERT INTO royalty_entries (id, musician_id, period_start, plays, amount) " +
+		"VALUES ($1, $2, $3, $4, $5)"
+	RoyaltyGetStatementsQuery = "SELECT e.musician_id, e.plays, e.amount, p.period_start, p.period_end, p.revenue, " +
+		"p.pool, p.plays period_plays, p.allocated_at FROM royalty_entries e " +
+		"JOIN royalty_periods p ON p.period_start = e.period_start " +
+		"WHERE e.musician_id = $1 AND e.period_start >= $2 AND e.period_start < $3 " +
+		"ORDER BY e.period_start DESC"
+)
+
+type PostgresRoyaltyRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresRoyaltyRepository(connection *sqlx.DB) *PostgresRoyaltyRepository {
+	return &PostgresRoyaltyRepository{connection: connection}
+}
+
+func (rr *PostgresRoyaltyRepository) GetLastPeriod(ctx context.Context) (domain.RoyaltyPeriod, error) {
+	var period entity2.PgRoyaltyPeriod
+	err := rr.connection.GetContext(ctx, &period, RoyaltyGetLastPeriodQuery)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.RoyaltyPeriod{}, util.WrapError(ports.ErrRoyaltyPeriodNotFound, err)
+		}
+		return domain.RoyaltyPeriod{}, util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+
+	return period.ToDomain(), nil
+}
+
+func (rr *PostgresRoyaltyRepository) GetRevenue(ctx context.Context, start time.Time, end time.Time) (int64, error) {
+	var revenue int64
+	err := rr.connection.GetContext(ctx, &revenue, RoyaltyGetRevenueQuery, start, end)
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+
+	return revenue, nil
+}
+
+func (rr *PostgresRoyaltyRepository) GetTrackPlays(ctx context.Context, start time.Time, end time.Time,
+	minSeconds int) ([]domain.TrackPlays, error) {
+	var rows []entity2.PgTrackMusicianPlays
+	err := rr.connection.SelectContext(ctx, &rows, RoyaltyGetTrackPlaysQuery, start, end, minSeconds)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+
+	// Rows come ordered by track, one per contributor with the same count.
+	var trackPlays []domain.TrackPlays
+	for _, row := range rows {
+		last := len(trackPlays) - 1
+		if last < 0 || trackPlays[last].TrackID != row.TrackID {
+			trackPlays = append(trackPlays, domain.TrackPlays{TrackID: row.TrackID, Plays: row.Plays})
+			last++
+		}
+		trackPlays[last].MusicianIDs = append(trackPlays[last].MusicianIDs, row.MusicianID)
+	}
+
+	return trackPlays, nil
+}
+
+func (rr *PostgresRoyaltyRepository) CreatePeriod(ctx context.Context, period domain.RoyaltyPeriod,
+	entries []domain.RoyaltyEntry) error {
+	tx, err := rr.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, RoyaltyInsertPeriodQuery,
+		period.Start,
+		period.End,
+		period.Revenue,
+		period.Pool,
+		period.Plays)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return util.WrapError(ports.ErrRoyaltyPeriodAllocated, err)
+		}
+		return util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+
+	for _, entry := range entries {
+		_, err = tx.ExecContext(ctx, RoyaltyInsertEntryQuery,
+			entry.ID,
+			entry.MusicianID,
+			entry.PeriodStart,
+			entry.Plays,
+			entry.Amount)
+		if err != nil {
+			return util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+
+	return nil
+}
+
+func (rr *PostgresRoyaltyRepository) GetStatements(ctx context.Context, musicianID uuid.UUID, from time.Time,
+	to time.Time) ([]domain.RoyaltyStatement, error) {
+	var statements []entity2.PgRoyaltyStatement
+	err := rr.connection.SelectContext(ctx, &statements, RoyaltyGetStatementsQuery, musicianID, from, to)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalRoyaltyRepo, err)
+	}
+
+	domainStatements := make([]domain.RoyaltyStatement, len(statements))
+	for i, statement := range statements {
+		domainStatements[i] = statement.ToDomain()
+	}
+
+	return domainStatements, nil
+}
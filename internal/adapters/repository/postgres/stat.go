@@ -2,22 +2,26 @@ package postgres
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
 const (
-	StatAddQuery             = "INSERT INTO users_history(id, user_id, track_id) VALUES ($1, $2, $3)"
+	StatAddQuery = "INSERT INTO users_history(id, user_id, track_id, listened_seconds) " +
+		"SELECT $1, $2, t.id, LEAST($4, t.duration_seconds) FROM tracks t WHERE t.id = $3"
 	statGetMostListenedQuery = "select musician_id, $1 user_id, cnt " +
 		"from (select a.id musician_id, count(*) cnt from (select m.id, uh.user_id from users_history uh " +
 		"join tracks t on uh.track_id = t.id " +
 		"join albums a on a.id = t.album_id " +
-		"join album_musician am on am.album_id = a.id " +
+		"join album_musician am on am.album_id = a.id and am.accepted = TRUE " +
 		"join musicians m on m.id = am.musician_id " +
 		"where uh.user_id=$1) as a " +
 		"group by a.id " +
@@ -29,6 +33,8 @@ const (
 		"where uh.user_id = $1 " +
 		"group by user_id, g.id " +
 		"order by cnt DESC"
+	StatGetHistoryQuery = "SELECT track_id, listened_at, listened_seconds FROM users_history " +
+		"WHERE user_id = $1 ORDER BY listened_at"
 )
 
 type PostgresStatRepository struct {
@@ -39,12 +45,28 @@ func NewPostgresStatRepository(connection *sqlx.DB) *PostgresStatRepository {
 	return &PostgresStatRepository{connection: connection}
 }
 
-func (sr *PostgresStatRepository) Add(ctx context.Context, recordID uuid.UUID, userID uuid.UUID, trackID uuid.UUID) error {
-	_, err := sr.connection.ExecContext(ctx, StatAddQuery, recordID, userID, trackID)
+// Add records a play. The listened seconds come from the client, so they are
+// cut down to the length of the track.
+func (sr *PostgresStatRepository) Add(ctx context.Context, recordID uuid.UUID, userID uuid.UUID, trackID uuid.UUID,
+	listenedSeconds int) error {
+	res, err := sr.connection.ExecContext(ctx, StatAddQuery, recordID, userID, trackID, listenedSeconds)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return util.WrapError(ports.ErrTrackIDNotFound, err)
+		}
 		return util.WrapError(ports.ErrInternalStatRepo, err)
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalStatRepo, err)
+	}
+
+	if affected == 0 {
+		return ports.ErrTrackIDNotFound
+	}
+
 	return nil
 }
 
@@ -77,3 +99,18 @@ func (sr *PostgresStatRepository) GetListenedGenres(ctx context.Context, userID
 
 	return domainGenresStat, nil
 }
+
+func (sr *PostgresStatRepository) GetHistory(ctx context.Context, userID uuid.UUID) ([]domain.ListenRecord, error) {
+	var records []entity.PgListenRecord
+	err := sr.connection.SelectContext(ctx, &records, StatGetHistoryQuery, userID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalStatRepo, err)
+	}
+
+	domainRecords := make([]domain.ListenRecord, len(records))
+	for i, record := range records {
+		domainRecords[i] = record.ToDomain()
+	}
+
+	return domainRecords, nil
+}
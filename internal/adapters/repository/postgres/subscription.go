@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	SubscriptionGetPlansQuery    = "SELECT id, name, price, duration_days FROM subscription_plans ORDER BY price"
+	SubscriptionGetPlanByIDQuery = "SELECT id, name, price, duration_days FROM subscription_plans WHERE id = $1"
+	SubscriptionGetCurrentQuery  = "SELECT id, user_id, plan_id, start_date, expiration_date, status " +
+		"FROM subscriptions WHERE user_id = $1 AND status <> 'expired'"
+	SubscriptionInsertQuery = "INSERT INTO subscriptions (id, user_id, plan_id, start_date, expiration_date, status) " +
+		"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, user_id, plan_id, start_date, expiration_date, status"
+	SubscriptionUpdateQuery = "UPDATE subscriptions SET expiration_date = $2, status = $3 WHERE id = $1 " +
+		"RETURNING id, user_id, plan_id, start_date, expiration_date, status"
+	SubscriptionExpireDueQuery = "UPDATE subscriptions SET status = 'expired' " +
+		"WHERE status <> 'expired' AND expiration_date <= $1"
+)
+
+type PostgresSubscriptionRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresSubscriptionRepository(connection *sqlx.DB) *PostgresSubscriptionRepository {
+	return &PostgresSubscriptionRepository{connection: connection}
+}
+
+func (sr *PostgresSubscriptionRepository) GetPlans(ctx context.Context) ([]domain.SubscriptionPlan, error) {
+	var plans []entity2.PgSubscriptionPlan
+	err := sr.connection.SelectContext(ctx, &plans, SubscriptionGetPlansQuery)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	domainPlans := make([]domain.SubscriptionPlan, len(plans))
+	for i, plan := range plans {
+		domainPlans[i] = plan.ToDomain()
+	}
+
+	return domainPlans, nil
+}
+
+func (sr *PostgresSubscriptionRepository) GetPlanByID(ctx context.Context, planID uuid.UUID) (domain.SubscriptionPlan, error) {
+	var plan entity2.PgSubscriptionPlan
+	err := sr.connection.GetContext(ctx, &plan, SubscriptionGetPlanByIDQuery, planID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.SubscriptionPlan{}, util.WrapError(ports.ErrSubscriptionPlanNotFound, err)
+		}
+		return domain.SubscriptionPlan{}, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	return plan.ToDomain(), nil
+}
+
+func (sr *PostgresSubscriptionRepository) GetCurrent(ctx context.Context, userID uuid.UUID) (domain.Subscription, error) {
+	var subscription entity2.PgSubscription
+	err := sr.connection.GetContext(ctx, &subscription, SubscriptionGetCurrentQuery, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Subscription{}, util.WrapError(ports.ErrSubscriptionNotFound, err)
+		}
+		return domain.Subscription{}, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	return subscription.ToDomain(), nil
+}
+
+func (sr *PostgresSubscriptionRepository) Create(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+	pgSubscription := entity2.NewPgSubscription(subscription)
+	var created entity2.PgSubscription
+	err := sr.connection.GetContext(ctx, &created, SubscriptionInsertQuery,
+		pgSubscription.ID,
+		pgSubscription.UserID,
+		pgSubscription.PlanID,
+		pgSubscription.StartDate,
+		pgSubscription.ExpirationDate,
+		pgSubscription.Status)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				return domain.Subscription{}, util.WrapError(ports.ErrSubscriptionAlreadyActive, err)
+			case pgerrcode.ForeignKeyViolation:
+				return domain.Subscription{}, util.WrapError(ports.ErrUserIDNotFound, err)
+			}
+		}
+		return domain.Subscription{}, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	return created.ToDomain(), nil
+}
+
+func (sr *PostgresSubscriptionRepository) Update(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+	var updated entity2.PgSubscription
+	err := sr.connection.GetContext(ctx, &updated, SubscriptionUpdateQuery,
+		subscription.ID, subscription.ExpirationDate, string(subscription.Status))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Subscription{}, util.WrapError(ports.ErrSubscriptionNotFound, err)
+		}
+		return domain.Subscription{}, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	return updated.ToDomain(), nil
+}
+
+func (sr *PostgresSubscriptionRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	result, err := sr.connection.ExecContext(ctx, SubscriptionExpireDueQuery, now)
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalSubscriptionRepo, err)
+	}
+
+	return expired, nil
+}
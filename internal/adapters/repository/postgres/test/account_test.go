@@ -0,0 +1,244 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type AccountSuite struct {
+	suite.Suite
+}
+
+func NewAccountRepository() (ports.IAccountRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresAccountRepository(conn)
+	return repo, mock
+}
+
+type AccountGetByIDSuite struct {
+	AccountSuite
+}
+
+func (s *AccountGetByIDSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, user domain.User) {
+	pgAccount := entity.NewPgUserAccount(user)
+	expectedRows := sqlmock.NewRows(append(EntityColumns(pgAccount), "email_verified")).
+		AddRow(append(EntityValues(pgAccount), true)...)
+	mock.ExpectQuery(postgres.AccountGetByIDQuery).
+		WithArgs(user.ID).
+		WillReturnRows(expectedRows)
+}
+
+func (s *AccountGetByIDSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account get by id test success")
+	repo, mock := NewAccountRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	s.SuccessRepositoryMock(mock, user)
+
+	account, err := repo.GetByID(context.Background(), user.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(user.Email, account.Email)
+	t.Assert().True(account.EmailVerified)
+}
+
+func (s *AccountGetByIDSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, id uuid.UUID) {
+	mock.ExpectQuery(postgres.AccountGetByIDQuery).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *AccountGetByIDSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account get by id test not found")
+	repo, mock := NewAccountRepository()
+	id := uuid.New()
+	s.NotFoundRepositoryMock(mock, id)
+
+	_, err := repo.GetByID(context.Background(), id)
+
+	t.Assert().ErrorIs(err, ports.ErrAccountNotFound)
+}
+
+func TestAccountGetByIDSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AccountGetByIDRepository", new(AccountGetByIDSuite))
+}
+
+type AccountGetByNameSuite struct {
+	AccountSuite
+}
+
+func (s *AccountGetByNameSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, user domain.User) {
+	pgAccount := entity.NewPgUserAccount(user)
+	expectedRows := sqlmock.NewRows(append(EntityColumns(pgAccount), "email_verified")).
+		AddRow(append(EntityValues(pgAccount), false)...)
+	mock.ExpectQuery(postgres.AccountGetByNameQuery).
+		WithArgs(user.Name).
+		WillReturnRows(expectedRows)
+}
+
+func (s *AccountGetByNameSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account get by name test success")
+	repo, mock := NewAccountRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	s.SuccessRepositoryMock(mock, user)
+
+	account, err := repo.GetByName(context.Background(), user.Name)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(user.ID, account.ID)
+	t.Assert().Equal(domain.AccountKindUser, account.Kind)
+}
+
+func (s *AccountGetByNameSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, name string) {
+	mock.ExpectQuery(postgres.AccountGetByNameQuery).
+		WithArgs(name).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *AccountGetByNameSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account get by name test not found")
+	repo, mock := NewAccountRepository()
+	s.NotFoundRepositoryMock(mock, "test")
+
+	_, err := repo.GetByName(context.Background(), "test")
+
+	t.Assert().ErrorIs(err, ports.ErrAccountNotFound)
+}
+
+func TestAccountGetByNameSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AccountGetByNameRepository", new(AccountGetByNameSuite))
+}
+
+type AccountGetByEmailSuite struct {
+	AccountSuite
+}
+
+func (s *AccountGetByEmailSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician) {
+	pgAccount := entity.NewPgMusicianAccount(musician)
+	expectedRows := sqlmock.NewRows(append(EntityColumns(pgAccount), "email_verified")).
+		AddRow(append(EntityValues(pgAccount), false)...)
+	mock.ExpectQuery(postgres.AccountGetByEmailQuery).
+		WithArgs(musician.Email).
+		WillReturnRows(expectedRows)
+}
+
+func (s *AccountGetByEmailSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account get by email test success")
+	repo, mock := NewAccountRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	s.SuccessRepositoryMock(mock, musician)
+
+	account, err := repo.GetByEmail(context.Background(), musician.Email)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(musician.ID, account.ID)
+	t.Assert().Equal(domain.AccountKindMusician, account.Kind)
+}
+
+func TestAccountGetByEmailSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AccountGetByEmailRepository", new(AccountGetByEmailSuite))
+}
+
+type AccountUpdatePasswordSuite struct {
+	AccountSuite
+}
+
+func (s *AccountUpdatePasswordSuite) RepositoryMock(mock sqlmock.Sqlmock, id uuid.UUID,
+	password domain.SaltedPassword, affected int64) {
+	mock.ExpectExec(postgres.AccountUpdatePasswordQuery).
+		WithArgs(id, password.HashPassword, password.Salt).
+		WillReturnResult(sqlmock.NewResult(0, affected))
+}
+
+func (s *AccountUpdatePasswordSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account update password test success")
+	repo, mock := NewAccountRepository()
+	id := uuid.New()
+	password := domain.SaltedPassword{HashPassword: "hash", Salt: "salt"}
+	s.RepositoryMock(mock, id, password, 1)
+
+	err := repo.UpdatePassword(context.Background(), id, password)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountUpdatePasswordSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account update password test not found")
+	repo, mock := NewAccountRepository()
+	id := uuid.New()
+	password := domain.SaltedPassword{HashPassword: "hash", Salt: "salt"}
+	s.RepositoryMock(mock, id, password, 0)
+
+	err := repo.UpdatePassword(context.Background(), id, password)
+
+	t.Assert().ErrorIs(err, ports.ErrAccountNotFound)
+}
+
+func TestAccountUpdatePasswordSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AccountUpdatePasswordRepository", new(AccountUpdatePasswordSuite))
+}
+
+type AccountMarkEmailVerifiedSuite struct {
+	AccountSuite
+}
+
+func (s *AccountMarkEmailVerifiedSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, id uuid.UUID, email string) {
+	mock.ExpectExec(postgres.AccountVerifyEmailQuery).
+		WithArgs(id, email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (s *AccountMarkEmailVerifiedSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account mark email verified test success")
+	repo, mock := NewAccountRepository()
+	id := uuid.New()
+	s.SuccessRepositoryMock(mock, id, "test@mail.com")
+
+	err := repo.MarkEmailVerified(context.Background(), id, "test@mail.com")
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountMarkEmailVerifiedSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, id uuid.UUID, email string) {
+	mock.ExpectExec(postgres.AccountVerifyEmailQuery).
+		WithArgs(id, email).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.ForeignKeyViolation})
+}
+
+func (s *AccountMarkEmailVerifiedSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Account mark email verified test not found")
+	repo, mock := NewAccountRepository()
+	id := uuid.New()
+	s.NotFoundRepositoryMock(mock, id, "test@mail.com")
+
+	err := repo.MarkEmailVerified(context.Background(), id, "test@mail.com")
+
+	t.Assert().ErrorIs(err, ports.ErrAccountNotFound)
+}
+
+func TestAccountMarkEmailVerifiedSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AccountMarkEmailVerifiedRepository", new(AccountMarkEmailVerifiedSuite))
+}
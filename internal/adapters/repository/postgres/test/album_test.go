@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
@@ -330,3 +332,222 @@ func (s *AlbumPublishSuite) TestNotFound(t provider.T) {
 func TestAlbumPublishSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "AlbumPublishRepository", new(AlbumPublishSuite))
 }
+
+type AlbumScheduleSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumScheduleSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, releaseAt null.Time) {
+	mock.ExpectExec(postgres.AlbumScheduleQuery).
+		WithArgs(albumID, releaseAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (s *AlbumScheduleSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album schedule test success")
+	repo, mock := NewAlbumRepository()
+	albumID := uuid.New()
+	releaseAt := null.TimeFrom(time.Now().Add(time.Hour))
+	s.SuccessRepositoryMock(mock, albumID, releaseAt)
+
+	err := repo.Schedule(context.Background(), albumID, releaseAt)
+
+	t.Assert().Nil(err)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *AlbumScheduleSuite) NotUpdatedRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, exists bool) {
+	mock.ExpectExec(postgres.AlbumScheduleQuery).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(postgres.AlbumExistsQuery).
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+func (s *AlbumScheduleSuite) TestPublished(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album schedule test already published")
+	repo, mock := NewAlbumRepository()
+	albumID := uuid.New()
+	s.NotUpdatedRepositoryMock(mock, albumID, true)
+
+	err := repo.Schedule(context.Background(), albumID, null.TimeFrom(time.Now()))
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumPublished)
+}
+
+func (s *AlbumScheduleSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album schedule test album not found")
+	repo, mock := NewAlbumRepository()
+	albumID := uuid.New()
+	s.NotUpdatedRepositoryMock(mock, albumID, false)
+
+	err := repo.Schedule(context.Background(), albumID, null.TimeFrom(time.Now()))
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumIDNotFound)
+}
+
+func TestAlbumScheduleSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AlbumScheduleRepository", new(AlbumScheduleSuite))
+}
+
+type AlbumPublishScheduledSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumPublishScheduledSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, album domain.Album, now time.Time) {
+	pgAlbum := entity.NewPgAlbum(album)
+	mock.ExpectBegin()
+	mock.ExpectQuery(postgres.AlbumTryLockQuery).
+		WithArgs(postgres.AlbumSchedulerLockID).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(true))
+	mock.ExpectQuery(postgres.AlbumPublishScheduledQuery).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgAlbum)).AddRow(EntityValues(pgAlbum)...))
+	mock.ExpectCommit()
+}
+
+func (s *AlbumPublishScheduledSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album publish scheduled test success")
+	repo, mock := NewAlbumRepository()
+	album := builder.NewAlbumBuilder().Default().Build()
+	now := time.Now()
+	s.SuccessRepositoryMock(mock, album, now)
+
+	albums, err := repo.PublishScheduled(context.Background(), now)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.Album{album}, albums)
+}
+
+func (s *AlbumPublishScheduledSuite) LockHeldRepositoryMock(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(postgres.AlbumTryLockQuery).
+		WithArgs(postgres.AlbumSchedulerLockID).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(false))
+	mock.ExpectRollback()
+}
+
+func (s *AlbumPublishScheduledSuite) TestLockHeld(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album publish scheduled test lock held")
+	repo, mock := NewAlbumRepository()
+	s.LockHeldRepositoryMock(mock)
+
+	albums, err := repo.PublishScheduled(context.Background(), time.Now())
+
+	t.Assert().Nil(err)
+	t.Assert().Nil(albums)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func TestAlbumPublishScheduledSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AlbumPublishScheduledRepository", new(AlbumPublishScheduledSuite))
+}
+
+type AlbumUnpublishSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumUnpublishSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, album domain.Album) {
+	pgAlbum := entity.NewPgAlbum(album)
+	expectedRows := sqlmock.NewRows(EntityColumns(pgAlbum)).
+		AddRow(EntityValues(pgAlbum)...)
+	mock.ExpectQuery(postgres.AlbumGetByIDInternalQuery).
+		WillReturnRows(expectedRows)
+
+	queryString := UpdateQueryString(pgAlbum, "albums")
+	mock.ExpectExec(queryString).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func (s *AlbumUnpublishSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album unpublish test success")
+	repo, mock := NewAlbumRepository()
+	album := builder.NewAlbumBuilder().Default().SetPublished(true).Build()
+	s.SuccessRepositoryMock(mock, album)
+
+	err := repo.Unpublish(context.Background(), album.ID, "reason")
+
+	t.Assert().Nil(err)
+}
+
+func (s *AlbumUnpublishSuite) NotPublishedRepositoryMock(mock sqlmock.Sqlmock, album domain.Album) {
+	pgAlbum := entity.NewPgAlbum(album)
+	expectedRows := sqlmock.NewRows(EntityColumns(pgAlbum)).
+		AddRow(EntityValues(pgAlbum)...)
+	mock.ExpectQuery(postgres.AlbumGetByIDInternalQuery).
+		WillReturnRows(expectedRows)
+}
+
+func (s *AlbumUnpublishSuite) TestNotPublished(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album unpublish test not published")
+	repo, mock := NewAlbumRepository()
+	album := builder.NewAlbumBuilder().Default().SetPublished(false).Build()
+	s.NotPublishedRepositoryMock(mock, album)
+
+	err := repo.Unpublish(context.Background(), album.ID, "reason")
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumNotPublished)
+}
+
+func TestAlbumUnpublishSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AlbumUnpublishRepository", new(AlbumUnpublishSuite))
+}
+
+type AlbumDeleteSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumDeleteSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, album domain.Album) {
+	pgAlbum := entity.NewPgAlbum(album)
+	expectedRows := sqlmock.NewRows(EntityColumns(pgAlbum)).
+		AddRow(EntityValues(pgAlbum)...)
+	mock.ExpectQuery(postgres.AlbumGetByIDInternalQuery).
+		WithArgs(album.ID).
+		WillReturnRows(expectedRows)
+
+	mock.ExpectExec(postgres.AlbumDeleteQuery).
+		WithArgs(album.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func (s *AlbumDeleteSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album delete test success")
+	repo, mock := NewAlbumRepository()
+	album := builder.NewAlbumBuilder().Default().Build()
+	s.SuccessRepositoryMock(mock, album)
+
+	deletedAlbum, err := repo.Delete(context.Background(), album.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(album, deletedAlbum)
+}
+
+func (s *AlbumDeleteSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, album domain.Album) {
+	mock.ExpectQuery(postgres.AlbumGetByIDInternalQuery).
+		WithArgs(album.ID).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *AlbumDeleteSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Album delete test not found")
+	repo, mock := NewAlbumRepository()
+	album := builder.NewAlbumBuilder().Default().Build()
+	s.NotFoundRepositoryMock(mock, album)
+
+	_, err := repo.Delete(context.Background(), album.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumIDNotFound)
+}
+
+func TestAlbumDeleteSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "AlbumDeleteRepository", new(AlbumDeleteSuite))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
@@ -138,12 +140,16 @@ type CommentGetByTrackIDSuite struct {
 	CommentSuite
 }
 
-func (s *CommentGetByTrackIDSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, comment domain.Comment) {
+func (s *CommentGetByTrackIDSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, query string, comment domain.Comment,
+	page domain.CommentPage) {
 	pgComment := entity.NewPgComment(comment)
+	mock.ExpectQuery(postgres.CommentCountByTrackIDQuery).
+		WithArgs(comment.TrackID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 	expectedRows := sqlmock.NewRows(EntityColumns(pgComment)).
 		AddRow(EntityValues(pgComment)...)
-	mock.ExpectQuery(postgres.CommentGetByTrackIDQuery).
-		WithArgs(comment.TrackID).
+	mock.ExpectQuery(query).
+		WithArgs(comment.TrackID, page.Limit, page.Offset).
 		WillReturnRows(expectedRows)
 }
 
@@ -152,18 +158,49 @@ func (s *CommentGetByTrackIDSuite) TestSuccess(t provider.T) {
 	t.Title("Repository Comment get by track id test success")
 	repo, mock := NewCommentRepository()
 	comment := builder.NewCommentBuilder().Default().Build()
-	s.SuccessRepositoryMock(mock, comment)
+	page := domain.CommentPage{Sort: domain.CommentSortNewest, Limit: 20}
+	s.SuccessRepositoryMock(mock, postgres.CommentGetNewestByTrackIDQuery, comment, page)
+
+	comments, total, err := repo.GetByTrackID(context.Background(), comment.TrackID, page)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(1, total)
+	t.Assert().Equal(comment, comments[0])
+}
+
+func (s *CommentGetByTrackIDSuite) TestSortByStars(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment get by track id test sorted by stars")
+	repo, mock := NewCommentRepository()
+	comment := builder.NewCommentBuilder().Default().SetStars(5).Build()
+	page := domain.CommentPage{Sort: domain.CommentSortStars, Limit: 10, Offset: 10}
+	s.SuccessRepositoryMock(mock, postgres.CommentGetTopRatedByTrackIDQuery, comment, page)
 
-	comments, err := repo.GetByTrackID(context.Background(), comment.TrackID)
+	comments, _, err := repo.GetByTrackID(context.Background(), comment.TrackID, page)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(comment, comments[0])
+}
+
+func (s *CommentGetByTrackIDSuite) TestSortByHelpful(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment get by track id test sorted by helpful votes")
+	repo, mock := NewCommentRepository()
+	comment := builder.NewCommentBuilder().Default().Build()
+	comment.HelpfulVotes = 3
+	page := domain.CommentPage{Sort: domain.CommentSortHelpful, Limit: 20}
+	s.SuccessRepositoryMock(mock, postgres.CommentGetHelpfulByTrackIDQuery, comment, page)
+
+	comments, _, err := repo.GetByTrackID(context.Background(), comment.TrackID, page)
 
 	t.Assert().Nil(err)
 	t.Assert().Equal(comment, comments[0])
 }
 
 func (s *CommentGetByTrackIDSuite) InternalErrorRepositoryMock(mock sqlmock.Sqlmock, comment domain.Comment) {
-	mock.ExpectQuery(postgres.CommentGetByTrackIDQuery).
+	mock.ExpectQuery(postgres.CommentCountByTrackIDQuery).
 		WithArgs(comment.TrackID).
-		WillReturnError(sql.ErrNoRows)
+		WillReturnError(sql.ErrConnDone)
 }
 
 func (s *CommentGetByTrackIDSuite) TestInternalError(t provider.T) {
@@ -173,7 +210,8 @@ func (s *CommentGetByTrackIDSuite) TestInternalError(t provider.T) {
 	comment := builder.NewCommentBuilder().Default().Build()
 	s.InternalErrorRepositoryMock(mock, comment)
 
-	comments, err := repo.GetByUserID(context.Background(), comment.TrackID)
+	comments, _, err := repo.GetByTrackID(context.Background(), comment.TrackID,
+		domain.CommentPage{Sort: domain.CommentSortNewest, Limit: 20})
 
 	t.Assert().Nil(comments)
 	t.Assert().ErrorIs(err, ports.ErrInternalCommentRepo)
@@ -182,3 +220,161 @@ func (s *CommentGetByTrackIDSuite) TestInternalError(t provider.T) {
 func TestCommentGetByTrackIDSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "CommentGetByTrackIDRepository", new(CommentGetByTrackIDSuite))
 }
+
+type CommentUpsertSuite struct {
+	CommentSuite
+}
+
+func (s *CommentUpsertSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, comment domain.Comment) {
+	pgComment := entity.NewPgComment(comment)
+	mock.ExpectQuery(postgres.CommentUpsertReviewQuery).
+		WithArgs(pgComment.ID, pgComment.UserID, pgComment.TrackID, pgComment.Stars, pgComment.Text,
+			pgComment.CreatedAt).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgComment)).
+			AddRow(EntityValues(pgComment)...))
+}
+
+func (s *CommentUpsertSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment upsert test success")
+	repo, mock := NewCommentRepository()
+	comment := builder.NewCommentBuilder().Default().SetStars(4).Build()
+	s.SuccessRepositoryMock(mock, comment)
+
+	result, err := repo.Upsert(context.Background(), comment)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(comment, result)
+}
+
+func (s *CommentUpsertSuite) UnknownTrackRepositoryMock(mock sqlmock.Sqlmock, comment domain.Comment) {
+	pgComment := entity.NewPgComment(comment)
+	mock.ExpectQuery(postgres.CommentUpsertReviewQuery).
+		WithArgs(pgComment.ID, pgComment.UserID, pgComment.TrackID, pgComment.Stars, pgComment.Text,
+			pgComment.CreatedAt).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.ForeignKeyViolation})
+}
+
+func (s *CommentUpsertSuite) TestUnknownTrack(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment upsert test unknown track")
+	repo, mock := NewCommentRepository()
+	comment := builder.NewCommentBuilder().Default().SetStars(4).Build()
+	s.UnknownTrackRepositoryMock(mock, comment)
+
+	_, err := repo.Upsert(context.Background(), comment)
+
+	t.Assert().ErrorIs(err, ports.ErrTrackIDNotFound)
+}
+
+func TestCommentUpsertSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "CommentUpsertRepository", new(CommentUpsertSuite))
+}
+
+type CommentReportSuite struct {
+	CommentSuite
+}
+
+func (s *CommentReportSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, report domain.CommentReport) {
+	pgReport := entity.NewPgCommentReport(report)
+	mock.ExpectQuery(postgres.CommentReportQuery).
+		WithArgs(pgReport.ID, pgReport.CommentID, pgReport.ReporterID, pgReport.Reason, pgReport.CreatedAt).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+}
+
+func (s *CommentReportSuite) TestDuplicate(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment report test reported twice")
+	repo, mock := NewCommentRepository()
+	report := domain.CommentReport{
+		ID:         uuid.New(),
+		CommentID:  uuid.New(),
+		ReporterID: uuid.New(),
+		Reason:     "spam",
+		CreatedAt:  time.Now(),
+	}
+	s.DuplicateRepositoryMock(mock, report)
+
+	_, err := repo.CreateReport(context.Background(), report)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentReported)
+}
+
+func (s *CommentReportSuite) QueueRepositoryMock(mock sqlmock.Sqlmock, reported entity.PgReportedComment) {
+	mock.ExpectQuery(postgres.CommentCountReportedQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	columns := append(EntityColumns(reported.PgComment), "reports", "last_reason", "first_reported_at")
+	values := append(EntityValues(reported.PgComment), reported.Reports, reported.LastReason, reported.FirstReportedAt)
+	mock.ExpectQuery(postgres.CommentModerationQueueQuery).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(values...))
+}
+
+func (s *CommentReportSuite) TestModerationQueue(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment moderation queue test success")
+	repo, mock := NewCommentRepository()
+	comment := builder.NewCommentBuilder().Default().Build()
+	reported := entity.PgReportedComment{
+		PgComment:       entity.NewPgComment(comment),
+		Reports:         2,
+		LastReason:      "spam",
+		FirstReportedAt: time.Now(),
+	}
+	s.QueueRepositoryMock(mock, reported)
+
+	queue, total, err := repo.GetModerationQueue(context.Background(), 20, 0)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(1, total)
+	t.Assert().Equal(reported.ToDomain(), queue[0])
+}
+
+func TestCommentReportSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "CommentReportRepository", new(CommentReportSuite))
+}
+
+type CommentVoteSuite struct {
+	CommentSuite
+}
+
+func (s *CommentVoteSuite) NotVotedRepositoryMock(mock sqlmock.Sqlmock, commentID uuid.UUID, userID uuid.UUID) {
+	mock.ExpectExec(postgres.CommentDeleteVoteQuery).
+		WithArgs(commentID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *CommentVoteSuite) TestDeleteNotVoted(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment delete vote test not voted")
+	repo, mock := NewCommentRepository()
+	commentID := uuid.New()
+	userID := uuid.New()
+	s.NotVotedRepositoryMock(mock, commentID, userID)
+
+	err := repo.DeleteVote(context.Background(), commentID, userID)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentVoteNotFound)
+}
+
+func (s *CommentVoteSuite) UnknownCommentRepositoryMock(mock sqlmock.Sqlmock, vote domain.CommentVote) {
+	mock.ExpectExec(postgres.CommentVoteQuery).
+		WithArgs(vote.CommentID, vote.UserID, vote.Helpful, vote.CreatedAt).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.ForeignKeyViolation})
+}
+
+func (s *CommentVoteSuite) TestUnknownComment(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Comment vote test unknown comment")
+	repo, mock := NewCommentRepository()
+	vote := domain.CommentVote{CommentID: uuid.New(), UserID: uuid.New(), Helpful: true, CreatedAt: time.Now()}
+	s.UnknownCommentRepositoryMock(mock, vote)
+
+	err := repo.Vote(context.Background(), vote)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentIDNotFound)
+}
+
+func TestCommentVoteSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "CommentVoteRepository", new(CommentVoteSuite))
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CreditSuite struct {
+	suite.Suite
+}
+
+func NewCreditRepository() (ports.ICreditRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresCreditRepository(conn)
+	return repo, mock
+}
+
+type CreditInviteSuite struct {
+	CreditSuite
+}
+
+func (s *CreditInviteSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.CreditInviteQuery).
+		WithArgs(musicianID, albumID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func (s *CreditInviteSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Credit invite test success")
+	repo, mock := NewCreditRepository()
+	albumID := uuid.New()
+	musicianID := uuid.New()
+	s.SuccessRepositoryMock(mock, albumID, musicianID)
+
+	err := repo.InviteToAlbum(context.Background(), albumID, musicianID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *CreditInviteSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.CreditInviteQuery).
+		WithArgs(musicianID, albumID).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+}
+
+func (s *CreditInviteSuite) TestDuplicate(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Credit invite test already contributor")
+	repo, mock := NewCreditRepository()
+	albumID := uuid.New()
+	musicianID := uuid.New()
+	s.DuplicateRepositoryMock(mock, albumID, musicianID)
+
+	err := repo.InviteToAlbum(context.Background(), albumID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrAlreadyContributor)
+}
+
+func TestCreditInviteSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "CreditInviteRepository", new(CreditInviteSuite))
+}
+
+type CreditAcceptInviteSuite struct {
+	CreditSuite
+}
+
+func (s *CreditAcceptInviteSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.CreditAcceptInviteQuery).
+		WithArgs(albumID, musicianID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *CreditAcceptInviteSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Credit accept invite test not found")
+	repo, mock := NewCreditRepository()
+	albumID := uuid.New()
+	musicianID := uuid.New()
+	s.NotFoundRepositoryMock(mock, albumID, musicianID)
+
+	err := repo.AcceptInvite(context.Background(), albumID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrInviteNotFound)
+}
+
+func TestCreditAcceptInviteSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "CreditAcceptInviteRepository", new(CreditAcceptInviteSuite))
+}
+
+type CreditGetTrackCreditsSuite struct {
+	CreditSuite
+}
+
+func (s *CreditGetTrackCreditsSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, trackID uuid.UUID, musician domain.Musician) {
+	pgMusician := entity.NewPgMusician(musician)
+	columns := append(EntityColumns(pgMusician), "track_id", "role")
+	values := append(EntityValues(pgMusician), trackID, "producer")
+	mock.ExpectQuery(postgres.CreditGetTrackCreditsQuery).
+		WithArgs(trackID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(values...))
+}
+
+func (s *CreditGetTrackCreditsSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Credit get track credits test success")
+	repo, mock := NewCreditRepository()
+	trackID := uuid.New()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	s.SuccessRepositoryMock(mock, trackID, musician)
+
+	credits, err := repo.GetTrackCredits(context.Background(), trackID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.TrackCredit{{
+		TrackID:  trackID,
+		Musician: musician,
+		Role:     domain.CreditRoleProducer,
+	}}, credits)
+}
+
+func TestCreditGetTrackCreditsSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "CreditGetTrackCreditsRepository", new(CreditGetTrackCreditsSuite))
+}
@@ -0,0 +1,230 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type DataExportSuite struct {
+	suite.Suite
+}
+
+func NewDataExportRepository() (ports.IDataExportRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresDataExportRepository(conn)
+	return repo, mock
+}
+
+func newDataExport() domain.DataExport {
+	return domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.DataExportPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+type DataExportCreateSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportCreateSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, export domain.DataExport) {
+	pgExport := entity.NewPgDataExport(export)
+	mock.ExpectQuery(postgres.DataExportInsertQuery).
+		WithArgs(pgExport.ID, pgExport.UserID, pgExport.Status, pgExport.CreatedAt).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgExport)).
+			AddRow(EntityValues(pgExport)...))
+}
+
+func (s *DataExportCreateSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport create test success")
+	repo, mock := NewDataExportRepository()
+	export := newDataExport()
+	s.SuccessRepositoryMock(mock, export)
+
+	result, err := repo.Create(context.Background(), export)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(export, result)
+}
+
+func (s *DataExportCreateSuite) InProgressRepositoryMock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(postgres.DataExportInsertQuery).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+}
+
+func (s *DataExportCreateSuite) TestInProgress(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport create test export already in progress")
+	repo, mock := NewDataExportRepository()
+	s.InProgressRepositoryMock(mock)
+
+	_, err := repo.Create(context.Background(), newDataExport())
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportInProgress)
+}
+
+func TestDataExportCreateSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "DataExportCreateRepository", new(DataExportCreateSuite))
+}
+
+type DataExportGetByIDSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportGetByIDSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, exportID uuid.UUID) {
+	mock.ExpectQuery(postgres.DataExportGetByIDQuery).
+		WithArgs(exportID).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *DataExportGetByIDSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport get by id test not found")
+	repo, mock := NewDataExportRepository()
+	exportID := uuid.New()
+	s.NotFoundRepositoryMock(mock, exportID)
+
+	_, err := repo.GetByID(context.Background(), exportID)
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportIDNotFound)
+}
+
+func TestDataExportGetByIDSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "DataExportGetByIDRepository", new(DataExportGetByIDSuite))
+}
+
+type DataExportGetByUserIDSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportGetByUserIDSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, export domain.DataExport) {
+	pgExport := entity.NewPgDataExport(export)
+	mock.ExpectQuery(postgres.DataExportGetByUserIDQuery).
+		WithArgs(export.UserID).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgExport)).
+			AddRow(EntityValues(pgExport)...))
+}
+
+func (s *DataExportGetByUserIDSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport get by user id test success")
+	repo, mock := NewDataExportRepository()
+	export := newDataExport()
+	s.SuccessRepositoryMock(mock, export)
+
+	exports, err := repo.GetByUserID(context.Background(), export.UserID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.DataExport{export}, exports)
+}
+
+func TestDataExportGetByUserIDSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "DataExportGetByUserIDRepository", new(DataExportGetByUserIDSuite))
+}
+
+type DataExportFinishSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportFinishSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, export domain.DataExport) {
+	pgExport := entity.NewPgDataExport(export)
+	mock.ExpectQuery(postgres.DataExportFinishQuery).
+		WithArgs(pgExport.ID, pgExport.Status, pgExport.CompletedAt, pgExport.ExpiresAt, pgExport.StartedAt).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgExport)).
+			AddRow(EntityValues(pgExport)...))
+}
+
+func newFinishedDataExport() domain.DataExport {
+	export := newDataExport()
+	export.Status = domain.DataExportReady
+	export.StartedAt = null.TimeFrom(export.CreatedAt)
+	export.CompletedAt = null.TimeFrom(export.CreatedAt.Add(time.Minute))
+	export.ExpiresAt = null.TimeFrom(export.CreatedAt.Add(time.Hour))
+	return export
+}
+
+func (s *DataExportFinishSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport finish test success")
+	repo, mock := NewDataExportRepository()
+	export := newFinishedDataExport()
+	s.SuccessRepositoryMock(mock, export)
+
+	result, err := repo.Finish(context.Background(), export)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(export, result)
+}
+
+func (s *DataExportFinishSuite) LostRepositoryMock(mock sqlmock.Sqlmock, export domain.DataExport) {
+	pgExport := entity.NewPgDataExport(export)
+	mock.ExpectQuery(postgres.DataExportFinishQuery).
+		WithArgs(pgExport.ID, pgExport.Status, pgExport.CompletedAt, pgExport.ExpiresAt, pgExport.StartedAt).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *DataExportFinishSuite) TestLost(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport finish test export claimed again")
+	repo, mock := NewDataExportRepository()
+	export := newFinishedDataExport()
+	s.LostRepositoryMock(mock, export)
+
+	_, err := repo.Finish(context.Background(), export)
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportLost)
+}
+
+func TestDataExportFinishSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "DataExportFinishRepository", new(DataExportFinishSuite))
+}
+
+type DataExportClaimPendingSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportClaimPendingSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, now time.Time,
+	staleBefore time.Time, export domain.DataExport) {
+	pgExport := entity.NewPgDataExport(export)
+	mock.ExpectQuery(postgres.DataExportClaimQuery).
+		WithArgs(now, staleBefore, 10).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgExport)).
+			AddRow(EntityValues(pgExport)...))
+}
+
+func (s *DataExportClaimPendingSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository DataExport claim pending test success")
+	repo, mock := NewDataExportRepository()
+	now := time.Now()
+	export := newDataExport()
+	export.Status = domain.DataExportRunning
+	s.SuccessRepositoryMock(mock, now, now.Add(-time.Hour), export)
+
+	exports, err := repo.ClaimPending(context.Background(), now, now.Add(-time.Hour), 10)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.DataExport{export}, exports)
+}
+
+func TestDataExportClaimPendingSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "DataExportClaimPendingRepository", new(DataExportClaimPendingSuite))
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type FollowSuite struct {
+	suite.Suite
+}
+
+// uuidSliceConverter passes uuid slices through like pgx does for ANY($1).
+type uuidSliceConverter struct{}
+
+func (uuidSliceConverter) ConvertValue(v any) (driver.Value, error) {
+	if ids, ok := v.([]uuid.UUID); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func NewFollowRepository() (ports.IFollowRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+		sqlmock.ValueConverterOption(uuidSliceConverter{}))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresFollowRepository(conn)
+	return repo, mock
+}
+
+type FollowFollowSuite struct {
+	FollowSuite
+}
+
+func (s *FollowFollowSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.FollowInsertQuery).
+		WithArgs(userID, musicianID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func (s *FollowFollowSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Follow follow test success")
+	repo, mock := NewFollowRepository()
+	userID := uuid.New()
+	musicianID := uuid.New()
+	s.SuccessRepositoryMock(mock, userID, musicianID)
+
+	err := repo.Follow(context.Background(), userID, musicianID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *FollowFollowSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.FollowInsertQuery).
+		WithArgs(userID, musicianID).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+}
+
+func (s *FollowFollowSuite) TestDuplicate(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Follow follow test duplicate")
+	repo, mock := NewFollowRepository()
+	userID := uuid.New()
+	musicianID := uuid.New()
+	s.DuplicateRepositoryMock(mock, userID, musicianID)
+
+	err := repo.Follow(context.Background(), userID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrAlreadyFollowing)
+}
+
+func TestFollowFollowSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "FollowFollowRepository", new(FollowFollowSuite))
+}
+
+type FollowUnfollowSuite struct {
+	FollowSuite
+}
+
+func (s *FollowUnfollowSuite) NotFollowingRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.FollowDeleteQuery).
+		WithArgs(userID, musicianID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *FollowUnfollowSuite) TestNotFollowing(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Follow unfollow test not following")
+	repo, mock := NewFollowRepository()
+	userID := uuid.New()
+	musicianID := uuid.New()
+	s.NotFollowingRepositoryMock(mock, userID, musicianID)
+
+	err := repo.Unfollow(context.Background(), userID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrNotFollowing)
+}
+
+func TestFollowUnfollowSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "FollowUnfollowRepository", new(FollowUnfollowSuite))
+}
+
+type FollowCountFollowersSuite struct {
+	FollowSuite
+}
+
+func (s *FollowCountFollowersSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, musicianID uuid.UUID) {
+	mock.ExpectQuery(postgres.FollowCountFollowersQuery).
+		WithArgs(musicianID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+}
+
+func (s *FollowCountFollowersSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Follow count followers test success")
+	repo, mock := NewFollowRepository()
+	musicianID := uuid.New()
+	s.SuccessRepositoryMock(mock, musicianID)
+
+	count, err := repo.CountFollowers(context.Background(), musicianID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(int64(3), count)
+}
+
+func (s *FollowCountFollowersSuite) BatchRepositoryMock(mock sqlmock.Sqlmock, musicianIDs []uuid.UUID) {
+	mock.ExpectQuery(postgres.FollowCountBatchQuery).
+		WithArgs(musicianIDs).
+		WillReturnRows(sqlmock.NewRows([]string{"musician_id", "count"}).AddRow(musicianIDs[0], 3))
+}
+
+func (s *FollowCountFollowersSuite) TestBatch(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Follow count followers test one query for many musicians")
+	repo, mock := NewFollowRepository()
+	musicianIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	s.BatchRepositoryMock(mock, musicianIDs)
+
+	counts, err := repo.CountFollowersByIDs(context.Background(), musicianIDs)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(map[uuid.UUID]int64{musicianIDs[0]: 3}, counts)
+	t.Assert().Zero(counts[musicianIDs[1]])
+}
+
+func TestFollowCountFollowersSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "FollowCountFollowersRepository", new(FollowCountFollowersSuite))
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ExternalIdentitySuite struct {
+	suite.Suite
+}
+
+func NewExternalIdentityRepository() (ports.IExternalIdentityRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresExternalIdentityRepository(conn)
+	return repo, mock
+}
+
+func newExternalIdentity() domain.ExternalIdentity {
+	return domain.ExternalIdentity{
+		Provider:  "corporate",
+		Subject:   "external-subject",
+		AccountID: uuid.New(),
+		Email:     "staff@label.com",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+type ExternalIdentityGetSuite struct {
+	ExternalIdentitySuite
+}
+
+func (s *ExternalIdentityGetSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, identity domain.ExternalIdentity) {
+	expectedRows := sqlmock.NewRows([]string{"provider", "subject", "account_id", "email", "created_at"}).
+		AddRow(identity.Provider, identity.Subject, identity.AccountID, identity.Email, identity.CreatedAt)
+	mock.ExpectQuery(postgres.IdentityGetQuery).
+		WithArgs(identity.Provider, identity.Subject).
+		WillReturnRows(expectedRows)
+}
+
+func (s *ExternalIdentityGetSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository ExternalIdentity get test success")
+	repo, mock := NewExternalIdentityRepository()
+	identity := newExternalIdentity()
+	s.SuccessRepositoryMock(mock, identity)
+
+	result, err := repo.Get(context.Background(), identity.Provider, identity.Subject)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(identity, result)
+}
+
+func (s *ExternalIdentityGetSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, identity domain.ExternalIdentity) {
+	mock.ExpectQuery(postgres.IdentityGetQuery).
+		WithArgs(identity.Provider, identity.Subject).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *ExternalIdentityGetSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository ExternalIdentity get test not found")
+	repo, mock := NewExternalIdentityRepository()
+	identity := newExternalIdentity()
+	s.NotFoundRepositoryMock(mock, identity)
+
+	_, err := repo.Get(context.Background(), identity.Provider, identity.Subject)
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCIdentityNotFound)
+}
+
+func TestExternalIdentityGetSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "ExternalIdentityGetRepository", new(ExternalIdentityGetSuite))
+}
+
+type ExternalIdentityCreateSuite struct {
+	ExternalIdentitySuite
+}
+
+func (s *ExternalIdentityCreateSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, identity domain.ExternalIdentity) {
+	mock.ExpectExec(postgres.IdentityInsertQuery).
+		WithArgs(identity.Provider, identity.Subject, identity.AccountID, identity.Email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (s *ExternalIdentityCreateSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository ExternalIdentity create test success")
+	repo, mock := NewExternalIdentityRepository()
+	identity := newExternalIdentity()
+	s.SuccessRepositoryMock(mock, identity)
+
+	err := repo.Create(context.Background(), identity)
+
+	t.Assert().Nil(err)
+}
+
+func (s *ExternalIdentityCreateSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, identity domain.ExternalIdentity) {
+	mock.ExpectExec(postgres.IdentityInsertQuery).
+		WithArgs(identity.Provider, identity.Subject, identity.AccountID, identity.Email).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+}
+
+func (s *ExternalIdentityCreateSuite) TestDuplicate(t provider.T) {
+	t.Parallel()
+	t.Title("Repository ExternalIdentity create test already linked")
+	repo, mock := NewExternalIdentityRepository()
+	identity := newExternalIdentity()
+	s.DuplicateRepositoryMock(mock, identity)
+
+	err := repo.Create(context.Background(), identity)
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCIdentityAlreadyLinked)
+}
+
+func TestExternalIdentityCreateSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "ExternalIdentityCreateRepository", new(ExternalIdentityCreateSuite))
+}
+
+type ExternalIdentityDeleteSuite struct {
+	ExternalIdentitySuite
+}
+
+func (s *ExternalIdentityDeleteSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, identity domain.ExternalIdentity) {
+	mock.ExpectExec(postgres.IdentityDeleteQuery).
+		WithArgs(identity.AccountID, identity.Provider).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *ExternalIdentityDeleteSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository ExternalIdentity delete test not found")
+	repo, mock := NewExternalIdentityRepository()
+	identity := newExternalIdentity()
+	s.NotFoundRepositoryMock(mock, identity)
+
+	err := repo.Delete(context.Background(), identity.AccountID, identity.Provider)
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCIdentityNotFound)
+}
+
+func TestExternalIdentityDeleteSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "ExternalIdentityDeleteRepository", new(ExternalIdentityDeleteSuite))
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
@@ -35,12 +37,18 @@ type MusicianCreateSuite struct {
 }
 
 func (s *MusicianCreateSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, user domain.Musician) {
-	pgMusician := entity.NewPgMusician(user)
-	queryString := InsertQueryString(pgMusician, "musicians")
-	mock.ExpectExec(queryString).
-		WithArgs(EntityValues(pgMusician)...).
+	pgAccount := entity.NewPgMusicianAccount(user)
+	pgProfile := entity.NewPgMusicianProfile(user)
+	mock.ExpectBegin()
+	mock.ExpectExec(InsertQueryString(pgAccount, "accounts")).
+		WithArgs(EntityValues(pgAccount)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(InsertQueryString(pgProfile, "musician_profiles")).
+		WithArgs(EntityValues(pgProfile)...).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
+	pgMusician := entity.NewPgMusician(user)
 	expectedRows := sqlmock.NewRows(EntityColumns(pgMusician)).
 		AddRow(EntityValues(pgMusician)...)
 	mock.ExpectQuery(postgres.MusicianGetByIDQuery).
@@ -62,11 +70,12 @@ func (s *MusicianCreateSuite) TestSuccess(t provider.T) {
 }
 
 func (s *MusicianCreateSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, user domain.Musician) {
-	pgMusician := entity.NewPgMusician(user)
-	queryString := InsertQueryString(pgMusician, "musicians")
-	mock.ExpectExec(queryString).
-		WithArgs(EntityValues(pgMusician)...).
+	pgAccount := entity.NewPgMusicianAccount(user)
+	mock.ExpectBegin()
+	mock.ExpectExec(InsertQueryString(pgAccount, "accounts")).
+		WithArgs(EntityValues(pgAccount)...).
 		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+	mock.ExpectRollback()
 }
 
 func (s *MusicianCreateSuite) TestDuplicate(t provider.T) {
@@ -363,3 +372,245 @@ func (s *MusicianGetByTrackIDSuite) TestNotFound(t provider.T) {
 func TestMusicianGetByTrackIDSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "MusicianGetByTrackIDRepository", new(MusicianGetByTrackIDSuite))
 }
+
+type MusicianUpdateProfileSuite struct {
+	MusicianSuite
+}
+
+func (s *MusicianUpdateProfileSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician,
+	links []domain.MusicianLink) {
+	pgMusician := entity.NewPgMusician(musician)
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.MusicianSetProfileQuery).
+		WithArgs(musician.ID, musician.Country, musician.Description).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.MusicianSetNameQuery).
+		WithArgs(musician.ID, musician.Name).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(postgres.MusicianDeleteLinksQuery).
+		WithArgs(musician.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, link := range links {
+		mock.ExpectExec(postgres.MusicianInsertLinkQuery).
+			WithArgs(musician.ID, string(link.Kind), link.URL).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+	mock.ExpectQuery(postgres.MusicianGetByIDQuery).
+		WithArgs(musician.ID).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgMusician)).
+			AddRow(EntityValues(pgMusician)...))
+}
+
+func (s *MusicianUpdateProfileSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician update profile test replaces links in the same transaction")
+	repo, mock := NewMusicianRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	links := []domain.MusicianLink{
+		{Kind: domain.MusicianLinkWebsite, URL: "https://musician.example.com"},
+		{Kind: domain.MusicianLinkTelegram, URL: "https://t.me/musician"},
+	}
+	s.SuccessRepositoryMock(mock, musician, links)
+
+	result, err := repo.UpdateProfile(context.Background(), musician, links)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(musician, result)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *MusicianUpdateProfileSuite) RenameRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician) {
+	renamed := musician
+	renamed.VerifiedAt = null.Time{}
+	pgMusician := entity.NewPgMusician(renamed)
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.MusicianSetProfileQuery).
+		WithArgs(musician.ID, musician.Country, musician.Description).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.MusicianSetNameQuery).
+		WithArgs(musician.ID, musician.Name).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.MusicianUnverifyQuery).
+		WithArgs(musician.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(postgres.MusicianGetByIDQuery).
+		WithArgs(musician.ID).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgMusician)).
+			AddRow(EntityValues(pgMusician)...))
+}
+
+func (s *MusicianUpdateProfileSuite) TestRenameDropsVerification(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician update profile test rename drops verified badge")
+	repo, mock := NewMusicianRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	musician.VerifiedAt = null.TimeFrom(time.Now())
+	s.RenameRepositoryMock(mock, musician)
+
+	result, err := repo.UpdateProfile(context.Background(), musician, nil)
+
+	t.Assert().Nil(err)
+	t.Assert().False(result.IsVerified())
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *MusicianUpdateProfileSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.MusicianSetProfileQuery).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.MusicianSetNameQuery).
+		WithArgs(musician.ID, musician.Name).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+	mock.ExpectRollback()
+}
+
+func (s *MusicianUpdateProfileSuite) TestDuplicate(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician update profile test name taken")
+	repo, mock := NewMusicianRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	s.DuplicateRepositoryMock(mock, musician)
+
+	_, err := repo.UpdateProfile(context.Background(), musician, nil)
+
+	t.Assert().ErrorIs(err, ports.ErrMusicianDuplicate)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *MusicianUpdateProfileSuite) InvalidLinkRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.MusicianSetProfileQuery).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.MusicianSetNameQuery).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(postgres.MusicianDeleteLinksQuery).
+		WithArgs(musician.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.MusicianInsertLinkQuery).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.CheckViolation})
+	mock.ExpectRollback()
+}
+
+func (s *MusicianUpdateProfileSuite) TestInvalidLinkRollsBack(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician update profile test invalid link rolls back the profile")
+	repo, mock := NewMusicianRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	s.InvalidLinkRepositoryMock(mock, musician)
+
+	_, err := repo.UpdateProfile(context.Background(), musician, []domain.MusicianLink{
+		{Kind: domain.MusicianLinkWebsite, URL: "https://musician.example.com"},
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrMusicianInvalidLink)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *MusicianUpdateProfileSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.MusicianSetProfileQuery).
+		WithArgs(musician.ID, musician.Country, musician.Description).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+}
+
+func (s *MusicianUpdateProfileSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician update profile test musician not found")
+	repo, mock := NewMusicianRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	s.NotFoundRepositoryMock(mock, musician)
+
+	_, err := repo.UpdateProfile(context.Background(), musician, nil)
+
+	t.Assert().ErrorIs(err, ports.ErrMusicianIDNotFound)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func TestMusicianUpdateProfileSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "MusicianUpdateProfileRepository", new(MusicianUpdateProfileSuite))
+}
+
+type MusicianSetVerifiedAtSuite struct {
+	MusicianSuite
+}
+
+func (s *MusicianSetVerifiedAtSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, musician domain.Musician) {
+	pgMusician := entity.NewPgMusician(musician)
+	mock.ExpectExec(postgres.MusicianSetVerifiedQuery).
+		WithArgs(musician.ID, musician.VerifiedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(postgres.MusicianGetByIDQuery).
+		WithArgs(musician.ID).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgMusician)).
+			AddRow(EntityValues(pgMusician)...))
+}
+
+func (s *MusicianSetVerifiedAtSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician set verified test writes only verified_at")
+	repo, mock := NewMusicianRepository()
+	musician := builder.NewMusicianBuilder().Default().Build()
+	musician.VerifiedAt = null.TimeFrom(time.Now())
+	s.SuccessRepositoryMock(mock, musician)
+
+	result, err := repo.SetVerifiedAt(context.Background(), musician.ID, musician.VerifiedAt)
+
+	t.Assert().Nil(err)
+	t.Assert().True(result.IsVerified())
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *MusicianSetVerifiedAtSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, musicianID uuid.UUID) {
+	mock.ExpectExec(postgres.MusicianSetVerifiedQuery).
+		WithArgs(musicianID, null.Time{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *MusicianSetVerifiedAtSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician set verified test musician not found")
+	repo, mock := NewMusicianRepository()
+	musicianID := uuid.New()
+	s.NotFoundRepositoryMock(mock, musicianID)
+
+	_, err := repo.SetVerifiedAt(context.Background(), musicianID, null.Time{})
+
+	t.Assert().ErrorIs(err, ports.ErrMusicianIDNotFound)
+}
+
+func TestMusicianSetVerifiedAtSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "MusicianSetVerifiedAtRepository", new(MusicianSetVerifiedAtSuite))
+}
+
+type MusicianLinksSuite struct {
+	MusicianSuite
+}
+
+func (s *MusicianLinksSuite) GetRepositoryMock(mock sqlmock.Sqlmock, musicianID uuid.UUID, link domain.MusicianLink) {
+	pgLink := entity.NewPgMusicianLink(musicianID, link)
+	mock.ExpectQuery(postgres.MusicianGetLinksQuery).
+		WithArgs(musicianID).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgLink)).
+			AddRow(EntityValues(pgLink)...))
+}
+
+func (s *MusicianLinksSuite) TestGet(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Musician get links test success")
+	repo, mock := NewMusicianRepository()
+	musicianID := uuid.New()
+	link := domain.MusicianLink{Kind: domain.MusicianLinkWebsite, URL: "https://musician.example.com"}
+	s.GetRepositoryMock(mock, musicianID, link)
+
+	links, err := repo.GetLinks(context.Background(), musicianID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.MusicianLink{link}, links)
+}
+
+func TestMusicianLinksSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "MusicianLinksRepository", new(MusicianLinksSuite))
+}
@@ -0,0 +1,199 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type RoyaltySuite struct {
+	suite.Suite
+}
+
+func NewRoyaltyRepository() (ports.IRoyaltyRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresRoyaltyRepository(conn)
+	return repo, mock
+}
+
+func newRoyaltyPeriod() domain.RoyaltyPeriod {
+	period := domain.RoyaltyPeriodOf(time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC))
+	period.Revenue = 1000
+	period.Pool = 700
+	period.Plays = 3
+	return period
+}
+
+type RoyaltyGetLastPeriodSuite struct {
+	RoyaltySuite
+}
+
+func (s *RoyaltyGetLastPeriodSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(postgres.RoyaltyGetLastPeriodQuery).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *RoyaltyGetLastPeriodSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Royalty get last period test not found")
+	repo, mock := NewRoyaltyRepository()
+	s.NotFoundRepositoryMock(mock)
+
+	_, err := repo.GetLastPeriod(context.Background())
+
+	t.Assert().ErrorIs(err, ports.ErrRoyaltyPeriodNotFound)
+}
+
+func TestRoyaltyGetLastPeriodSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "RoyaltyGetLastPeriodRepository", new(RoyaltyGetLastPeriodSuite))
+}
+
+type RoyaltyGetTrackPlaysSuite struct {
+	RoyaltySuite
+}
+
+func (s *RoyaltyGetTrackPlaysSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, period domain.RoyaltyPeriod,
+	trackID uuid.UUID, otherTrackID uuid.UUID, musicianID uuid.UUID, otherMusicianID uuid.UUID) {
+	mock.ExpectQuery(postgres.RoyaltyGetTrackPlaysQuery).
+		WithArgs(period.Start, period.End, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"track_id", "musician_id", "plays"}).
+			AddRow(trackID, musicianID, 2).
+			AddRow(trackID, otherMusicianID, 2).
+			AddRow(otherTrackID, musicianID, 1))
+}
+
+func (s *RoyaltyGetTrackPlaysSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Royalty get track plays test grouped by track")
+	repo, mock := NewRoyaltyRepository()
+	period := newRoyaltyPeriod()
+	trackID, otherTrackID := uuid.New(), uuid.New()
+	musicianID, otherMusicianID := uuid.New(), uuid.New()
+	s.SuccessRepositoryMock(mock, period, trackID, otherTrackID, musicianID, otherMusicianID)
+
+	plays, err := repo.GetTrackPlays(context.Background(), period.Start, period.End, 30)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.TrackPlays{
+		{TrackID: trackID, MusicianIDs: []uuid.UUID{musicianID, otherMusicianID}, Plays: 2},
+		{TrackID: otherTrackID, MusicianIDs: []uuid.UUID{musicianID}, Plays: 1},
+	}, plays)
+}
+
+func TestRoyaltyGetTrackPlaysSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "RoyaltyGetTrackPlaysRepository", new(RoyaltyGetTrackPlaysSuite))
+}
+
+type RoyaltyCreatePeriodSuite struct {
+	RoyaltySuite
+}
+
+func (s *RoyaltyCreatePeriodSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, period domain.RoyaltyPeriod,
+	entry domain.RoyaltyEntry) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.RoyaltyInsertPeriodQuery).
+		WithArgs(period.Start, period.End, period.Revenue, period.Pool, period.Plays).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(postgres.RoyaltyInsertEntryQuery).
+		WithArgs(entry.ID, entry.MusicianID, entry.PeriodStart, entry.Plays, entry.Amount).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+}
+
+func (s *RoyaltyCreatePeriodSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Royalty create period test success")
+	repo, mock := NewRoyaltyRepository()
+	period := newRoyaltyPeriod()
+	entry := domain.RoyaltyEntry{
+		ID:          uuid.New(),
+		MusicianID:  uuid.New(),
+		PeriodStart: period.Start,
+		Plays:       3,
+		Amount:      700,
+	}
+	s.SuccessRepositoryMock(mock, period, entry)
+
+	err := repo.CreatePeriod(context.Background(), period, []domain.RoyaltyEntry{entry})
+
+	t.Assert().Nil(err)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *RoyaltyCreatePeriodSuite) AllocatedRepositoryMock(mock sqlmock.Sqlmock, period domain.RoyaltyPeriod) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.RoyaltyInsertPeriodQuery).
+		WithArgs(period.Start, period.End, period.Revenue, period.Pool, period.Plays).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+	mock.ExpectRollback()
+}
+
+func (s *RoyaltyCreatePeriodSuite) TestAllocated(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Royalty create period test already allocated")
+	repo, mock := NewRoyaltyRepository()
+	period := newRoyaltyPeriod()
+	s.AllocatedRepositoryMock(mock, period)
+
+	err := repo.CreatePeriod(context.Background(), period, nil)
+
+	t.Assert().ErrorIs(err, ports.ErrRoyaltyPeriodAllocated)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func TestRoyaltyCreatePeriodSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "RoyaltyCreatePeriodRepository", new(RoyaltyCreatePeriodSuite))
+}
+
+type RoyaltyGetStatementsSuite struct {
+	RoyaltySuite
+}
+
+func (s *RoyaltyGetStatementsSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, statement domain.RoyaltyStatement,
+	from time.Time, to time.Time) {
+	period := statement.Period
+	mock.ExpectQuery(postgres.RoyaltyGetStatementsQuery).
+		WithArgs(statement.MusicianID, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"musician_id", "plays", "amount", "period_start", "period_end",
+			"revenue", "pool", "period_plays", "allocated_at"}).
+			AddRow(statement.MusicianID, statement.Plays, statement.Amount, period.Start, period.End,
+				period.Revenue, period.Pool, period.Plays, period.AllocatedAt))
+}
+
+func (s *RoyaltyGetStatementsSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Royalty get statements test success")
+	repo, mock := NewRoyaltyRepository()
+	period := newRoyaltyPeriod()
+	period.AllocatedAt = period.End.Add(time.Hour)
+	statement := domain.RoyaltyStatement{
+		MusicianID: uuid.New(),
+		Period:     period,
+		Plays:      2,
+		Amount:     467,
+	}
+	from, to := period.Start, period.End
+	s.SuccessRepositoryMock(mock, statement, from, to)
+
+	statements, err := repo.GetStatements(context.Background(), statement.MusicianID, from, to)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.RoyaltyStatement{statement}, statements)
+}
+
+func TestRoyaltyGetStatementsSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "RoyaltyGetStatementsRepository", new(RoyaltyGetStatementsSuite))
+}
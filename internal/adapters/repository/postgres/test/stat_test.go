@@ -3,10 +3,12 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"github.com/jmoiron/sqlx"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
@@ -38,11 +40,57 @@ func (s *StatAddSuite) TestSuccess(t provider.T) {
 	repo, mock := NewStatRepository()
 	s.SuccessRepositoryMock(mock)
 
-	err := repo.Add(context.Background(), uuid.New(), uuid.New(), uuid.New())
+	err := repo.Add(context.Background(), uuid.New(), uuid.New(), uuid.New(), 30)
 
 	t.Assert().Nil(err)
 }
 
+func (s *StatAddSuite) TrackNotFoundRepositoryMock(mock sqlmock.Sqlmock, trackID uuid.UUID) {
+	mock.ExpectExec(postgres.StatAddQuery).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), trackID, 30).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *StatAddSuite) TestTrackNotFound(t provider.T) {
+	t.Parallel()
+	repo, mock := NewStatRepository()
+	trackID := uuid.New()
+	s.TrackNotFoundRepositoryMock(mock, trackID)
+
+	err := repo.Add(context.Background(), uuid.New(), uuid.New(), trackID, 30)
+
+	t.Assert().ErrorIs(err, ports.ErrTrackIDNotFound)
+}
+
 func TestStatAddSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "StatAddRepository", new(StatAddSuite))
 }
+
+type StatGetHistorySuite struct {
+	StatSuite
+}
+
+func (s *StatGetHistorySuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID,
+	record domain.ListenRecord) {
+	mock.ExpectQuery(postgres.StatGetHistoryQuery).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"track_id", "listened_at", "listened_seconds"}).
+			AddRow(record.TrackID, record.ListenedAt, record.ListenedSeconds))
+}
+
+func (s *StatGetHistorySuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	repo, mock := NewStatRepository()
+	userID := uuid.New()
+	record := domain.ListenRecord{TrackID: uuid.New(), ListenedAt: time.Now(), ListenedSeconds: 42}
+	s.SuccessRepositoryMock(mock, userID, record)
+
+	history, err := repo.GetHistory(context.Background(), userID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.ListenRecord{record}, history)
+}
+
+func TestStatGetHistorySuite(t *testing.T) {
+	suite.RunNamedSuite(t, "StatGetHistoryRepository", new(StatGetHistorySuite))
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type SubscriptionSuite struct {
+	suite.Suite
+}
+
+func NewSubscriptionRepository() (ports.ISubscriptionRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresSubscriptionRepository(conn)
+	return repo, mock
+}
+
+func newSubscription() domain.Subscription {
+	start := time.Now().UTC().Truncate(time.Second)
+	return domain.Subscription{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		PlanID:         uuid.New(),
+		StartDate:      start,
+		ExpirationDate: start.AddDate(0, 0, 30),
+		Status:         domain.SubscriptionActive,
+	}
+}
+
+func subscriptionRows(subscriptions ...domain.Subscription) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "user_id", "plan_id", "start_date", "expiration_date", "status"})
+	for _, subscription := range subscriptions {
+		planID := interface{}(subscription.PlanID)
+		if subscription.PlanID == uuid.Nil {
+			planID = nil
+		}
+		rows.AddRow(subscription.ID, subscription.UserID, planID, subscription.StartDate,
+			subscription.ExpirationDate, string(subscription.Status))
+	}
+	return rows
+}
+
+type SubscriptionGetCurrentSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionGetCurrentSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, subscription domain.Subscription) {
+	mock.ExpectQuery(postgres.SubscriptionGetCurrentQuery).
+		WithArgs(subscription.UserID).
+		WillReturnRows(subscriptionRows(subscription))
+}
+
+func (s *SubscriptionGetCurrentSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Subscription get current test success")
+	repo, mock := NewSubscriptionRepository()
+	subscription := newSubscription()
+	s.SuccessRepositoryMock(mock, subscription)
+
+	result, err := repo.GetCurrent(context.Background(), subscription.UserID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(subscription, result)
+}
+
+func (s *SubscriptionGetCurrentSuite) TestWithoutPlan(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Subscription get current test subscription without plan")
+	repo, mock := NewSubscriptionRepository()
+	subscription := newSubscription()
+	subscription.PlanID = uuid.Nil
+	s.SuccessRepositoryMock(mock, subscription)
+
+	result, err := repo.GetCurrent(context.Background(), subscription.UserID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(uuid.Nil, result.PlanID)
+}
+
+func (s *SubscriptionGetCurrentSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID) {
+	mock.ExpectQuery(postgres.SubscriptionGetCurrentQuery).
+		WithArgs(userID).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *SubscriptionGetCurrentSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Subscription get current test not found")
+	repo, mock := NewSubscriptionRepository()
+	userID := uuid.New()
+	s.NotFoundRepositoryMock(mock, userID)
+
+	_, err := repo.GetCurrent(context.Background(), userID)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionNotFound)
+}
+
+func TestSubscriptionGetCurrentSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "SubscriptionGetCurrentRepository", new(SubscriptionGetCurrentSuite))
+}
+
+type SubscriptionCreateSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionCreateSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, subscription domain.Subscription) {
+	mock.ExpectQuery(postgres.SubscriptionInsertQuery).
+		WithArgs(subscription.ID, subscription.UserID, subscription.PlanID, subscription.StartDate,
+			subscription.ExpirationDate, string(subscription.Status)).
+		WillReturnRows(subscriptionRows(subscription))
+}
+
+func (s *SubscriptionCreateSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Subscription create test success")
+	repo, mock := NewSubscriptionRepository()
+	subscription := newSubscription()
+	s.SuccessRepositoryMock(mock, subscription)
+
+	result, err := repo.Create(context.Background(), subscription)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(subscription, result)
+}
+
+func (s *SubscriptionCreateSuite) AlreadyActiveRepositoryMock(mock sqlmock.Sqlmock, subscription domain.Subscription) {
+	mock.ExpectQuery(postgres.SubscriptionInsertQuery).
+		WithArgs(subscription.ID, subscription.UserID, subscription.PlanID, subscription.StartDate,
+			subscription.ExpirationDate, string(subscription.Status)).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+}
+
+func (s *SubscriptionCreateSuite) TestAlreadyActive(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Subscription create test already active")
+	repo, mock := NewSubscriptionRepository()
+	subscription := newSubscription()
+	s.AlreadyActiveRepositoryMock(mock, subscription)
+
+	_, err := repo.Create(context.Background(), subscription)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionAlreadyActive)
+}
+
+func TestSubscriptionCreateSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "SubscriptionCreateRepository", new(SubscriptionCreateSuite))
+}
+
+type SubscriptionExpireDueSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionExpireDueSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, now time.Time) {
+	mock.ExpectExec(postgres.SubscriptionExpireDueQuery).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+}
+
+func (s *SubscriptionExpireDueSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Subscription expire due test success")
+	repo, mock := NewSubscriptionRepository()
+	now := time.Now()
+	s.SuccessRepositoryMock(mock, now)
+
+	expired, err := repo.ExpireDue(context.Background(), now)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(int64(3), expired)
+}
+
+func TestSubscriptionExpireDueSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "SubscriptionExpireDueRepository", new(SubscriptionExpireDueSuite))
+}
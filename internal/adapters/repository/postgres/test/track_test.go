@@ -81,6 +81,62 @@ func TestTrackCreateSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "TrackCreateRepository", new(TrackCreateSuite))
 }
 
+type TrackUpdateSuite struct {
+	TrackSuite
+}
+
+func (s *TrackUpdateSuite) MovedRepositoryMock(mock sqlmock.Sqlmock, track domain.Track) {
+	mock.ExpectQuery(postgres.TrackNextNumberQuery).
+		WithArgs(track.AlbumID, track.DiscNumber).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(track.TrackNumber))
+
+	pgTrack := entity.NewPgTrack(track)
+	queryString := UpdateQueryString(pgTrack, "tracks")
+	mock.ExpectExec(queryString).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	expectedRows := sqlmock.NewRows(EntityColumns(pgTrack)).
+		AddRow(EntityValues(pgTrack)...)
+	mock.ExpectQuery(postgres.TrackGetByIDInternalQuery).
+		WillReturnRows(expectedRows)
+}
+
+func (s *TrackUpdateSuite) TestMoved(t provider.T) {
+	t.Parallel()
+	repo, mock := NewTrackRepository()
+	track := builder.NewTrackBuilder().Default().SetTrackNumber(1, 4).Build()
+	s.MovedRepositoryMock(mock, track)
+
+	movedTrack := track
+	movedTrack.TrackNumber = 0
+	trackResult, err := repo.Update(context.Background(), movedTrack)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(track, trackResult)
+}
+
+func (s *TrackUpdateSuite) FailRepositoryMock(mock sqlmock.Sqlmock, track domain.Track) {
+	pgTrack := entity.NewPgTrack(track)
+	queryString := UpdateQueryString(pgTrack, "tracks")
+	mock.ExpectExec(queryString).
+		WillReturnError(sql.ErrConnDone)
+}
+
+func (s *TrackUpdateSuite) TestFail(t provider.T) {
+	t.Parallel()
+	repo, mock := NewTrackRepository()
+	track := builder.NewTrackBuilder().Default().Build()
+	s.FailRepositoryMock(mock, track)
+
+	_, err := repo.Update(context.Background(), track)
+
+	t.Assert().ErrorIs(err, ports.ErrTrackUpdate)
+}
+
+func TestTrackUpdateSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "TrackUpdateRepository", new(TrackUpdateSuite))
+}
+
 type TrackGetAllSuite struct {
 	TrackSuite
 }
@@ -99,12 +155,39 @@ func (s *TrackGetAllSuite) TestSuccess(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.SuccessRepositoryMock(mock, track)
 
-	tracks, err := repo.GetAll(context.Background())
+	tracks, err := repo.GetAll(context.Background(), domain.TrackSortDefault)
 
 	t.Assert().Nil(err)
 	t.Assert().Equal(track, tracks[0])
 }
 
+func (s *TrackGetAllSuite) SortByRatingRepositoryMock(mock sqlmock.Sqlmock, rated domain.Track, unrated domain.Track) {
+	pgRated := entity.NewPgTrack(rated)
+	pgUnrated := entity.NewPgTrack(unrated)
+	pgRating := entity.PgRating{Count: 1, Stars5: 1}
+	expectedRows := sqlmock.NewRows(append(EntityColumns(pgRated), EntityColumns(pgRating)...)).
+		AddRow(append(EntityValues(pgRated), EntityValues(pgRating)...)...).
+		AddRow(append(EntityValues(pgUnrated), EntityValues(entity.PgRating{})...)...)
+	mock.ExpectQuery(postgres.TrackGetAllByRatingQuery).
+		WillReturnRows(expectedRows)
+}
+
+func (s *TrackGetAllSuite) TestSortByRating(t provider.T) {
+	t.Parallel()
+	repo, mock := NewTrackRepository()
+	rated := builder.NewTrackBuilder().Default().SetID(uuid.New()).Build()
+	unrated := builder.NewTrackBuilder().Default().SetID(uuid.New()).Build()
+	s.SortByRatingRepositoryMock(mock, rated, unrated)
+
+	tracks, err := repo.GetAll(context.Background(), domain.TrackSortRating)
+
+	t.Assert().Nil(err)
+	t.Assert().Len(tracks, 2)
+	t.Assert().Equal(rated.ID, tracks[0].ID)
+	t.Assert().Equal(5.0, tracks[0].Rating.Mean())
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
 func (s *TrackGetAllSuite) InternalErrorRepositoryMock(mock sqlmock.Sqlmock, album domain.Track) {
 	mock.ExpectQuery(postgres.TrackGetAllQuery).
 		WillReturnError(sql.ErrNoRows)
@@ -116,7 +199,7 @@ func (s *TrackGetAllSuite) TestInternalError(t provider.T) {
 	album := builder.NewTrackBuilder().Default().Build()
 	s.InternalErrorRepositoryMock(mock, album)
 
-	albums, err := repo.GetAll(context.Background())
+	albums, err := repo.GetAll(context.Background(), domain.TrackSortDefault)
 
 	t.Assert().Nil(albums)
 	t.Assert().ErrorIs(err, ports.ErrInternalTrackRepo)
@@ -151,6 +234,29 @@ func (s *TrackGetByIDSuite) TestSuccess(t provider.T) {
 	t.Assert().Equal(track, resultTrack)
 }
 
+func (s *TrackGetByIDSuite) RatedRepositoryMock(mock sqlmock.Sqlmock, track domain.Track) {
+	pgTrack := entity.NewPgTrack(track)
+	pgRating := entity.PgRating{Count: 3, Stars4: 1, Stars5: 2}
+	expectedRows := sqlmock.NewRows(append(EntityColumns(pgTrack), EntityColumns(pgRating)...)).
+		AddRow(append(EntityValues(pgTrack), EntityValues(pgRating)...)...)
+	mock.ExpectQuery(postgres.TrackGetByIDQuery).
+		WithArgs(track.ID).
+		WillReturnRows(expectedRows)
+}
+
+func (s *TrackGetByIDSuite) TestRated(t provider.T) {
+	t.Parallel()
+	repo, mock := NewTrackRepository()
+	track := builder.NewTrackBuilder().Default().Build()
+	s.RatedRepositoryMock(mock, track)
+
+	resultTrack, err := repo.GetByID(context.Background(), track.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.Rating{Count: 3, Histogram: [5]int{0, 0, 0, 1, 2}}, resultTrack.Rating)
+	t.Assert().InDelta(14.0/3, resultTrack.Rating.Mean(), 1e-9)
+}
+
 func (s *TrackGetByIDSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, track domain.Track) {
 	mock.ExpectQuery(postgres.TrackGetByIDQuery).
 		WillReturnError(sql.ErrNoRows)
@@ -190,12 +296,31 @@ func (s *TrackGetByAlbumIDSuite) TestSuccess(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.SuccessRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetByAlbumID(context.Background(), uuid.New())
+	resultTracks, err := repo.GetByAlbumID(context.Background(), uuid.New(), domain.TrackSortDefault)
 
 	t.Assert().Nil(err)
 	t.Assert().Equal(track, resultTracks[0])
 }
 
+func (s *TrackGetByAlbumIDSuite) SortByRatingRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID) {
+	mock.ExpectQuery(postgres.TrackGetByAlbumIDByRating).
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func (s *TrackGetByAlbumIDSuite) TestSortByRating(t provider.T) {
+	t.Parallel()
+	repo, mock := NewTrackRepository()
+	albumID := uuid.New()
+	s.SortByRatingRepositoryMock(mock, albumID)
+
+	resultTracks, err := repo.GetByAlbumID(context.Background(), albumID, domain.TrackSortRating)
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(resultTracks)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
 func (s *TrackGetByAlbumIDSuite) InternalErrorRepositoryMock(mock sqlmock.Sqlmock, track domain.Track) {
 	mock.ExpectQuery(postgres.TrackGetByAlbumID).
 		WillReturnError(sql.ErrNoRows)
@@ -207,7 +332,7 @@ func (s *TrackGetByAlbumIDSuite) TestNotFound(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.InternalErrorRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetByAlbumID(context.Background(), track.ID)
+	resultTracks, err := repo.GetByAlbumID(context.Background(), track.ID, domain.TrackSortDefault)
 
 	t.Assert().ErrorIs(err, ports.ErrInternalTrackRepo)
 	t.Assert().Nil(resultTracks)
@@ -235,7 +360,7 @@ func (s *TrackGetByMusicianIDSuite) TestSuccess(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.SuccessRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetByMusicianID(context.Background(), uuid.New())
+	resultTracks, err := repo.GetByMusicianID(context.Background(), uuid.New(), domain.TrackSortDefault)
 
 	t.Assert().Nil(err)
 	t.Assert().Equal(track, resultTracks[0])
@@ -252,7 +377,7 @@ func (s *TrackGetByMusicianIDSuite) TestNotFound(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.InternalErrorRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetByMusicianID(context.Background(), track.ID)
+	resultTracks, err := repo.GetByMusicianID(context.Background(), track.ID, domain.TrackSortDefault)
 
 	t.Assert().ErrorIs(err, ports.ErrInternalTrackRepo)
 	t.Assert().Nil(resultTracks)
@@ -280,7 +405,7 @@ func (s *TrackGetOwnSuite) TestSuccess(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.SuccessRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetOwn(context.Background(), uuid.New())
+	resultTracks, err := repo.GetOwn(context.Background(), uuid.New(), domain.TrackSortDefault)
 
 	t.Assert().Nil(err)
 	t.Assert().Equal(track, resultTracks[0])
@@ -297,7 +422,7 @@ func (s *TrackGetOwnSuite) TestNotFound(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.InternalErrorRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetOwn(context.Background(), track.ID)
+	resultTracks, err := repo.GetOwn(context.Background(), track.ID, domain.TrackSortDefault)
 
 	t.Assert().ErrorIs(err, ports.ErrInternalTrackRepo)
 	t.Assert().Nil(resultTracks)
@@ -325,7 +450,7 @@ func (s *TrackGetUserFavoritesSuite) TestSuccess(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.SuccessRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetUserFavorites(context.Background(), uuid.New())
+	resultTracks, err := repo.GetUserFavorites(context.Background(), uuid.New(), domain.TrackSortDefault)
 
 	t.Assert().Nil(err)
 	t.Assert().Equal(track, resultTracks[0])
@@ -342,7 +467,7 @@ func (s *TrackGetUserFavoritesSuite) TestNotFound(t provider.T) {
 	track := builder.NewTrackBuilder().Default().Build()
 	s.InternalErrorRepositoryMock(mock, track)
 
-	resultTracks, err := repo.GetUserFavorites(context.Background(), track.ID)
+	resultTracks, err := repo.GetUserFavorites(context.Background(), track.ID, domain.TrackSortDefault)
 
 	t.Assert().ErrorIs(err, ports.ErrInternalTrackRepo)
 	t.Assert().Nil(resultTracks)
@@ -391,3 +516,54 @@ func (s *TrackAddToUserFavoritesSuite) TestDuplicate(t provider.T) {
 func TestTrackAddToUserFavoritesSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "TrackGetAddToUserFavoritesRepository", new(TrackAddToUserFavoritesSuite))
 }
+
+type TrackReorderSuite struct {
+	TrackSuite
+}
+
+func (s *TrackReorderSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, position domain.TrackPosition) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.TrackSetPositionQuery).
+		WithArgs(position.TrackID, albumID, position.DiscNumber, position.TrackNumber).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func (s *TrackReorderSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Track reorder test success")
+	repo, mock := NewTrackRepository()
+	albumID := uuid.New()
+	position := domain.TrackPosition{TrackID: uuid.New(), DiscNumber: 1, TrackNumber: 3}
+	s.SuccessRepositoryMock(mock, albumID, position)
+
+	err := repo.Reorder(context.Background(), albumID, []domain.TrackPosition{position})
+
+	t.Assert().Nil(err)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *TrackReorderSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, albumID uuid.UUID, position domain.TrackPosition) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.TrackSetPositionQuery).
+		WithArgs(position.TrackID, albumID, position.DiscNumber, position.TrackNumber).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+}
+
+func (s *TrackReorderSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository Track reorder test track not in album")
+	repo, mock := NewTrackRepository()
+	albumID := uuid.New()
+	position := domain.TrackPosition{TrackID: uuid.New(), DiscNumber: 1, TrackNumber: 3}
+	s.NotFoundRepositoryMock(mock, albumID, position)
+
+	err := repo.Reorder(context.Background(), albumID, []domain.TrackPosition{position})
+
+	t.Assert().ErrorIs(err, ports.ErrTrackIDNotFound)
+}
+
+func TestTrackReorderSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "TrackReorderRepository", new(TrackReorderSuite))
+}
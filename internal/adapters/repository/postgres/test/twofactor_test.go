@@ -0,0 +1,189 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/jmoiron/sqlx"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type TwoFactorSuite struct {
+	suite.Suite
+}
+
+func NewTwoFactorRepository() (ports.ITwoFactorRepository, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	conn := sqlx.NewDb(db, "pgx")
+	repo := postgres.NewPostgresTwoFactorRepository(conn)
+	return repo, mock
+}
+
+type TwoFactorGetSuite struct {
+	TwoFactorSuite
+}
+
+func (s *TwoFactorGetSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, accountID uuid.UUID) {
+	expectedRows := sqlmock.NewRows([]string{"account_id", "secret", "enabled_at", "last_used_step"}).
+		AddRow(accountID, "JBSWY3DPEHPK3PXP", time.Now(), int64(42))
+	mock.ExpectQuery(postgres.TwoFactorGetQuery).
+		WithArgs(accountID).
+		WillReturnRows(expectedRows)
+}
+
+func (s *TwoFactorGetSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository TwoFactor get test success")
+	repo, mock := NewTwoFactorRepository()
+	accountID := uuid.New()
+	s.SuccessRepositoryMock(mock, accountID)
+
+	twoFactor, err := repo.Get(context.Background(), accountID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.TwoFactor{
+		AccountID:    accountID,
+		Secret:       "JBSWY3DPEHPK3PXP",
+		Enabled:      true,
+		LastUsedStep: 42,
+	}, twoFactor)
+}
+
+func (s *TwoFactorGetSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, accountID uuid.UUID) {
+	mock.ExpectQuery(postgres.TwoFactorGetQuery).
+		WithArgs(accountID).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *TwoFactorGetSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository TwoFactor get test not found")
+	repo, mock := NewTwoFactorRepository()
+	accountID := uuid.New()
+	s.NotFoundRepositoryMock(mock, accountID)
+
+	_, err := repo.Get(context.Background(), accountID)
+
+	t.Assert().ErrorIs(err, ports.ErrTwoFactorNotFound)
+}
+
+func TestTwoFactorGetSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "TwoFactorGetRepository", new(TwoFactorGetSuite))
+}
+
+type TwoFactorSaveSuite struct {
+	TwoFactorSuite
+}
+
+func (s *TwoFactorSaveSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, twoFactor domain.TwoFactor,
+	codeHashes []string) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.TwoFactorSaveQuery).
+		WithArgs(twoFactor.AccountID, twoFactor.Secret).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.TwoFactorDeleteRecoveryCodesQuery).
+		WithArgs(twoFactor.AccountID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, codeHash := range codeHashes {
+		mock.ExpectExec(postgres.TwoFactorInsertRecoveryCodeQuery).
+			WithArgs(twoFactor.AccountID, codeHash).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func (s *TwoFactorSaveSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository TwoFactor save test success")
+	repo, mock := NewTwoFactorRepository()
+	twoFactor := domain.TwoFactor{AccountID: uuid.New(), Secret: "JBSWY3DPEHPK3PXP"}
+	codeHashes := []string{"first", "second"}
+	s.SuccessRepositoryMock(mock, twoFactor, codeHashes)
+
+	err := repo.Save(context.Background(), twoFactor, codeHashes)
+
+	t.Assert().Nil(err)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func TestTwoFactorSaveSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "TwoFactorSaveRepository", new(TwoFactorSaveSuite))
+}
+
+type TwoFactorUseStepSuite struct {
+	TwoFactorSuite
+}
+
+func (s *TwoFactorUseStepSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, accountID uuid.UUID, step int64) {
+	mock.ExpectExec(postgres.TwoFactorUseStepQuery).
+		WithArgs(accountID, step).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (s *TwoFactorUseStepSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("Repository TwoFactor use step test success")
+	repo, mock := NewTwoFactorRepository()
+	accountID := uuid.New()
+	s.SuccessRepositoryMock(mock, accountID, 100)
+
+	err := repo.UseStep(context.Background(), accountID, 100)
+
+	t.Assert().Nil(err)
+}
+
+func (s *TwoFactorUseStepSuite) ReplayedRepositoryMock(mock sqlmock.Sqlmock, accountID uuid.UUID, step int64) {
+	mock.ExpectExec(postgres.TwoFactorUseStepQuery).
+		WithArgs(accountID, step).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *TwoFactorUseStepSuite) TestReplayed(t provider.T) {
+	t.Parallel()
+	t.Title("Repository TwoFactor use step test replayed code")
+	repo, mock := NewTwoFactorRepository()
+	accountID := uuid.New()
+	s.ReplayedRepositoryMock(mock, accountID, 100)
+
+	err := repo.UseStep(context.Background(), accountID, 100)
+
+	t.Assert().ErrorIs(err, ports.ErrTwoFactorInvalidCode)
+}
+
+func TestTwoFactorUseStepSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "TwoFactorUseStepRepository", new(TwoFactorUseStepSuite))
+}
+
+type TwoFactorEnableSuite struct {
+	TwoFactorSuite
+}
+
+func (s *TwoFactorEnableSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, accountID uuid.UUID, step int64) {
+	mock.ExpectExec(postgres.TwoFactorEnableQuery).
+		WithArgs(accountID, step).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *TwoFactorEnableSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Repository TwoFactor enable test not found")
+	repo, mock := NewTwoFactorRepository()
+	accountID := uuid.New()
+	s.NotFoundRepositoryMock(mock, accountID, 100)
+
+	err := repo.Enable(context.Background(), accountID, 100)
+
+	t.Assert().ErrorIs(err, ports.ErrTwoFactorNotFound)
+}
+
+func TestTwoFactorEnableSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "TwoFactorEnableRepository", new(TwoFactorEnableSuite))
+}
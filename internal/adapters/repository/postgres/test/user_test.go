@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
@@ -34,12 +37,18 @@ type UserCreateSuite struct {
 }
 
 func (s *UserCreateSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, user domain.User) {
-	pgUser := entity.NewPgUser(user)
-	queryString := InsertQueryString(pgUser, "users")
-	mock.ExpectExec(queryString).
-		WithArgs(EntityValues(pgUser)...).
+	pgAccount := entity.NewPgUserAccount(user)
+	pgProfile := entity.NewPgUserProfile(user)
+	mock.ExpectBegin()
+	mock.ExpectExec(InsertQueryString(pgAccount, "accounts")).
+		WithArgs(EntityValues(pgAccount)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(InsertQueryString(pgProfile, "user_profiles")).
+		WithArgs(EntityValues(pgProfile)...).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
+	pgUser := entity.NewPgUser(user)
 	expectedRows := sqlmock.NewRows(EntityColumns(pgUser)).
 		AddRow(EntityValues(pgUser)...)
 	mock.ExpectQuery(postgres.UserGetByIDQuery).
@@ -60,11 +69,12 @@ func (s *UserCreateSuite) TestSuccess(t provider.T) {
 }
 
 func (s *UserCreateSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, user domain.User) {
-	pgUser := entity.NewPgUser(user)
-	queryString := InsertQueryString(pgUser, "users")
-	mock.ExpectExec(queryString).
-		WithArgs(EntityValues(pgUser)...).
+	pgAccount := entity.NewPgUserAccount(user)
+	mock.ExpectBegin()
+	mock.ExpectExec(InsertQueryString(pgAccount, "accounts")).
+		WithArgs(EntityValues(pgAccount)...).
 		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+	mock.ExpectRollback()
 }
 
 func (s *UserCreateSuite) TestDuplicate(t provider.T) {
@@ -83,6 +93,143 @@ func TestUserCreateSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "UserCreateRepository", new(UserCreateSuite))
 }
 
+type UserSetRoleSuite struct {
+	UserSuite
+}
+
+func (s *UserSetRoleSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, user domain.User) {
+	mock.ExpectExec(postgres.UserSetRoleQuery).
+		WithArgs(user.ID, user.Role).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	pgUser := entity.NewPgUser(user)
+	expectedRows := sqlmock.NewRows(EntityColumns(pgUser)).
+		AddRow(EntityValues(pgUser)...)
+	mock.ExpectQuery(postgres.UserGetByIDQuery).
+		WithArgs(pgUser.ID).
+		WillReturnRows(expectedRows)
+}
+
+func (s *UserSetRoleSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	user.Role = domain.ModeratorRole
+	s.SuccessRepositoryMock(mock, user)
+
+	userResult, err := repo.SetRole(context.Background(), user.ID, domain.ModeratorRole)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(user, userResult)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *UserSetRoleSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID) {
+	mock.ExpectExec(postgres.UserSetRoleQuery).
+		WithArgs(userID, domain.AdminRole).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(postgres.UserGetByIDQuery).
+		WithArgs(userID).
+		WillReturnError(sql.ErrNoRows)
+}
+
+func (s *UserSetRoleSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	userID := uuid.New()
+	s.NotFoundRepositoryMock(mock, userID)
+
+	_, err := repo.SetRole(context.Background(), userID, domain.AdminRole)
+
+	t.Assert().ErrorIs(err, ports.ErrUserIDNotFound)
+}
+
+func TestUserSetRoleSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "UserSetRoleRepository", new(UserSetRoleSuite))
+}
+
+type UserScheduleDeletionSuite struct {
+	UserSuite
+}
+
+func (s *UserScheduleDeletionSuite) ScheduleRepositoryMock(mock sqlmock.Sqlmock, user domain.User, rows int64) {
+	mock.ExpectExec(postgres.UserScheduleQuery).
+		WithArgs(user.ID, user.DeletionScheduledAt.Time).
+		WillReturnResult(sqlmock.NewResult(0, rows))
+
+	pgUser := entity.NewPgUser(user)
+	expectedRows := sqlmock.NewRows(EntityColumns(pgUser)).
+		AddRow(EntityValues(pgUser)...)
+	mock.ExpectQuery(postgres.UserGetByIDQuery).
+		WithArgs(pgUser.ID).
+		WillReturnRows(expectedRows)
+}
+
+func (s *UserScheduleDeletionSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	user.DeletionScheduledAt = null.TimeFrom(time.Now().Add(time.Hour).UTC())
+	s.ScheduleRepositoryMock(mock, user, 1)
+
+	userResult, err := repo.ScheduleDeletion(context.Background(), user.ID, user.DeletionScheduledAt.Time)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(user, userResult)
+}
+
+func (s *UserScheduleDeletionSuite) TestAlreadyRequested(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	user.DeletionScheduledAt = null.TimeFrom(time.Now().Add(time.Hour).UTC())
+	s.ScheduleRepositoryMock(mock, user, 0)
+
+	_, err := repo.ScheduleDeletion(context.Background(), user.ID, user.DeletionScheduledAt.Time)
+
+	t.Assert().ErrorIs(err, ports.ErrUserDeletionRequested)
+}
+
+func (s *UserScheduleDeletionSuite) CancelRepositoryMock(mock sqlmock.Sqlmock, user domain.User, rows int64) {
+	mock.ExpectExec(postgres.UserUnscheduleQuery).
+		WithArgs(user.ID).
+		WillReturnResult(sqlmock.NewResult(0, rows))
+
+	pgUser := entity.NewPgUser(user)
+	expectedRows := sqlmock.NewRows(EntityColumns(pgUser)).
+		AddRow(EntityValues(pgUser)...)
+	mock.ExpectQuery(postgres.UserGetByIDQuery).
+		WithArgs(pgUser.ID).
+		WillReturnRows(expectedRows)
+}
+
+func (s *UserScheduleDeletionSuite) TestCancel(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	s.CancelRepositoryMock(mock, user, 1)
+
+	userResult, err := repo.CancelDeletion(context.Background(), user.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().False(userResult.DeletionScheduledAt.Valid)
+}
+
+func (s *UserScheduleDeletionSuite) TestCancelNotRequested(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	s.CancelRepositoryMock(mock, user, 0)
+
+	_, err := repo.CancelDeletion(context.Background(), user.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrUserDeletionNotRequested)
+}
+
+func TestUserScheduleDeletionSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "UserScheduleDeletionRepository", new(UserScheduleDeletionSuite))
+}
+
 type UserGetAllSuite struct {
 	UserSuite
 }
@@ -313,3 +460,179 @@ func (s *UserGetByPhoneSuite) TestNotFound(t provider.T) {
 func TestUserGetByPhoneSuite(t *testing.T) {
 	suite.RunNamedSuite(t, "UserGetByPhoneRepository", new(UserGetByPhoneSuite))
 }
+
+type UserUpdateProfileSuite struct {
+	UserSuite
+}
+
+func (s *UserUpdateProfileSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, user domain.User,
+	changes domain.UserProfileChanges) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.UserUpdateProfileQuery).
+		WithArgs(user.ID, changes.Phone, changes.Country).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.UserUpdateAccountQuery).
+		WithArgs(user.ID, changes.Name, changes.Email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	pgUser := entity.NewPgUser(user)
+	mock.ExpectQuery(postgres.UserGetByIDQuery).
+		WithArgs(user.ID).
+		WillReturnRows(sqlmock.NewRows(EntityColumns(pgUser)).
+			AddRow(EntityValues(pgUser)...))
+}
+
+func (s *UserUpdateProfileSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	user := builder.NewUserBuilder().Default().Build()
+	changes := domain.UserProfileChanges{Email: null.StringFrom(user.Email)}
+	s.SuccessRepositoryMock(mock, user, changes)
+
+	result, err := repo.UpdateProfile(context.Background(), user.ID, changes)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(user, result)
+	t.Assert().Nil(mock.ExpectationsWereMet())
+}
+
+func (s *UserUpdateProfileSuite) NotFoundRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.UserUpdateProfileQuery).
+		WithArgs(userID, null.String{}, null.String{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+}
+
+func (s *UserUpdateProfileSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	userID := uuid.New()
+	s.NotFoundRepositoryMock(mock, userID)
+
+	_, err := repo.UpdateProfile(context.Background(), userID, domain.UserProfileChanges{})
+
+	t.Assert().ErrorIs(err, ports.ErrUserIDNotFound)
+}
+
+func (s *UserUpdateProfileSuite) DuplicateRepositoryMock(mock sqlmock.Sqlmock, userID uuid.UUID,
+	changes domain.UserProfileChanges) {
+	mock.ExpectBegin()
+	mock.ExpectExec(postgres.UserUpdateProfileQuery).
+		WithArgs(userID, changes.Phone, changes.Country).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(postgres.UserUpdateAccountQuery).
+		WithArgs(userID, changes.Name, changes.Email).
+		WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+	mock.ExpectRollback()
+}
+
+func (s *UserUpdateProfileSuite) TestDuplicate(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	userID := uuid.New()
+	changes := domain.UserProfileChanges{Name: null.StringFrom("taken")}
+	s.DuplicateRepositoryMock(mock, userID, changes)
+
+	_, err := repo.UpdateProfile(context.Background(), userID, changes)
+
+	t.Assert().ErrorIs(err, ports.ErrUserDuplicate)
+}
+
+func TestUserUpdateProfileSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "UserUpdateProfileRepository", new(UserUpdateProfileSuite))
+}
+
+type UserGetScheduledBeforeSuite struct {
+	UserSuite
+}
+
+func (s *UserGetScheduledBeforeSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, before time.Time,
+	ids []uuid.UUID) {
+	expectedRows := sqlmock.NewRows([]string{"id"})
+	for _, id := range ids {
+		expectedRows.AddRow(id)
+	}
+	mock.ExpectQuery(postgres.UserGetScheduledQuery).
+		WithArgs(before).
+		WillReturnRows(expectedRows)
+}
+
+func (s *UserGetScheduledBeforeSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	before := time.Now()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	s.SuccessRepositoryMock(mock, before, ids)
+
+	scheduled, err := repo.GetScheduledBefore(context.Background(), before)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(ids, scheduled)
+}
+
+func (s *UserGetScheduledBeforeSuite) FailRepositoryMock(mock sqlmock.Sqlmock, before time.Time) {
+	mock.ExpectQuery(postgres.UserGetScheduledQuery).
+		WithArgs(before).
+		WillReturnError(sql.ErrConnDone)
+}
+
+func (s *UserGetScheduledBeforeSuite) TestFail(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	before := time.Now()
+	s.FailRepositoryMock(mock, before)
+
+	_, err := repo.GetScheduledBefore(context.Background(), before)
+
+	t.Assert().ErrorIs(err, ports.ErrInternalUserRepo)
+}
+
+func TestUserGetScheduledBeforeSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "UserGetScheduledBeforeRepository", new(UserGetScheduledBeforeSuite))
+}
+
+type UserDeleteScheduledSuite struct {
+	UserSuite
+}
+
+func (s *UserDeleteScheduledSuite) SuccessRepositoryMock(mock sqlmock.Sqlmock, id uuid.UUID, before time.Time) {
+	mock.ExpectExec(postgres.UserDeleteScheduledQuery).
+		WithArgs(id, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (s *UserDeleteScheduledSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	id := uuid.New()
+	before := time.Now()
+	s.SuccessRepositoryMock(mock, id, before)
+
+	err := repo.DeleteScheduled(context.Background(), id, before)
+
+	t.Assert().Nil(err)
+}
+
+func (s *UserDeleteScheduledSuite) CancelledRepositoryMock(mock sqlmock.Sqlmock, id uuid.UUID, before time.Time) {
+	mock.ExpectExec(postgres.UserDeleteScheduledQuery).
+		WithArgs(id, before).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *UserDeleteScheduledSuite) TestCancelled(t provider.T) {
+	t.Parallel()
+	repo, mock := NewUserRepository()
+	id := uuid.New()
+	before := time.Now()
+	s.CancelledRepositoryMock(mock, id, before)
+
+	err := repo.DeleteScheduled(context.Background(), id, before)
+
+	t.Assert().ErrorIs(err, ports.ErrUserDeletionNotRequested)
+}
+
+func TestUserDeleteScheduledSuite(t *testing.T) {
+	suite.RunNamedSuite(t, "UserDeleteScheduledRepository", new(UserDeleteScheduledSuite))
+}
@@ -15,19 +15,50 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// ratingColumns selects the rating of the row joined as r, which is missing
+// for tracks and albums without reviews.
+const ratingColumns = "COALESCE(r.reviews, 0) AS rating_count, COALESCE(r.stars_1, 0) AS rating_1, " +
+	"COALESCE(r.stars_2, 0) AS rating_2, COALESCE(r.stars_3, 0) AS rating_3, " +
+	"COALESCE(r.stars_4, 0) AS rating_4, COALESCE(r.stars_5, 0) AS rating_5"
+
+// trackRatingOrder orders the tracks by mean stars, then by the number of
+// reviews, so unrated tracks come last. Queries append their own tie-breaker.
+const trackRatingOrder = " ORDER BY COALESCE((r.stars_1 + 2 * r.stars_2 + 3 * r.stars_3 + 4 * r.stars_4 + 5 * r.stars_5)::float / NULLIF(r.reviews, 0), 0) DESC, " +
+	"COALESCE(r.reviews, 0) DESC"
+
+const trackGetByAlbumIDSelect = "SELECT t.id, t.album_id, t.name, t.url, t.disc_number, t.track_number, t.duration_seconds, " + ratingColumns + " FROM tracks t JOIN albums a ON t.album_id = a.id LEFT JOIN track_ratings r ON r.track_id = t.id WHERE a.published = TRUE AND a.id = $1"
+
 const (
-	TrackGetAllQuery          = "SELECT t.id, t.album_id, t.name, t.url FROM tracks t JOIN albums a ON t.album_id = a.id WHERE a.published = TRUE"
+	TrackGetAllQuery          = "SELECT t.id, t.album_id, t.name, t.url, t.disc_number, t.track_number, t.duration_seconds, " + ratingColumns + " FROM tracks t JOIN albums a ON t.album_id = a.id LEFT JOIN track_ratings r ON r.track_id = t.id WHERE a.published = TRUE"
 	TrackDeleteQuery          = "DELETE FROM tracks WHERE id = $1"
 	TrackDeleteFavoriteQuery  = "DELETE FROM favorite WHERE user_id = $1 and track_id = $2"
-	TrackGetByIDQuery         = "SELECT t.id, t.album_id, t.name, t.url FROM tracks t JOIN albums a ON t.album_id = a.id WHERE t.id = $1 AND a.published = TRUE"
-	TrackGetByIDInternalQuery = "SELECT id, album_id, name, url FROM tracks WHERE id = $1"
-	TrackGetUserFavorites     = "SELECT t.id, t.album_id, t.name, t.url FROM tracks t JOIN favorite f on t.id = f.track_id WHERE f.user_id = $1"
-	TrackGetByAlbumID         = "SELECT t.id, t.album_id, t.name, t.url FROM tracks t JOIN albums a ON t.album_id = a.id WHERE a.published = TRUE AND a.id = $1"
-	TrackGetByMusicianID      = "SELECT t.id, t.album_id, t.name, t.url FROM tracks t JOIN albums a ON t.album_id = a.id JOIN album_musician am on a.id = am.album_id JOIN musicians m on am.musician_id = m.id WHERE published = TRUE and m.id = $1"
-	TrackGetOwn               = "SELECT t.id, t.album_id, t.name, t.url FROM tracks t JOIN albums a ON t.album_id = a.id JOIN album_musician am on a.id = am.album_id JOIN musicians m on am.musician_id = m.id WHERE m.id = $1"
+	TrackGetByIDQuery         = "SELECT t.id, t.album_id, t.name, t.url, t.disc_number, t.track_number, t.duration_seconds, " + ratingColumns + " FROM tracks t JOIN albums a ON t.album_id = a.id LEFT JOIN track_ratings r ON r.track_id = t.id WHERE t.id = $1 AND a.published = TRUE"
+	TrackGetByIDInternalQuery = "SELECT id, album_id, name, url, disc_number, track_number, duration_seconds FROM tracks WHERE id = $1"
+	TrackGetUserFavorites     = "SELECT t.id, t.album_id, t.name, t.url, t.disc_number, t.track_number, t.duration_seconds, " + ratingColumns + " FROM tracks t JOIN favorite f on t.id = f.track_id LEFT JOIN track_ratings r ON r.track_id = t.id WHERE f.user_id = $1"
+	TrackGetByAlbumID         = trackGetByAlbumIDSelect + " ORDER BY t.disc_number, t.track_number"
+	TrackGetByMusicianID      = "SELECT t.id, t.album_id, t.name, t.url, t.disc_number, t.track_number, t.duration_seconds, " + ratingColumns + " FROM tracks t JOIN albums a ON t.album_id = a.id JOIN album_musician am on a.id = am.album_id JOIN musicians m on am.musician_id = m.id LEFT JOIN track_ratings r ON r.track_id = t.id WHERE published = TRUE and am.accepted = TRUE and m.id = $1"
+	TrackGetOwn               = "SELECT t.id, t.album_id, t.name, t.url, t.disc_number, t.track_number, t.duration_seconds, " + ratingColumns + " FROM tracks t JOIN albums a ON t.album_id = a.id JOIN album_musician am on a.id = am.album_id JOIN musicians m on am.musician_id = m.id LEFT JOIN track_ratings r ON r.track_id = t.id WHERE am.accepted = TRUE and m.id = $1"
 	TrackInsertFavorite       = "INSERT INTO favorite(user_id, track_id) VALUES ($1, $2)"
+	TrackGetByAlbumIDInternal = "SELECT id, album_id, name, url, disc_number, track_number, duration_seconds FROM tracks WHERE album_id = $1 ORDER BY disc_number, track_number"
+	TrackNextNumberQuery      = "SELECT COALESCE(MAX(track_number), 0) + 1 FROM tracks WHERE album_id = $1 AND disc_number = $2"
+	TrackSetPositionQuery     = "UPDATE tracks SET disc_number = $3, track_number = $4 WHERE id = $1 AND album_id = $2"
+
+	TrackGetAllByRatingQuery      = TrackGetAllQuery + trackRatingOrder + ", t.name, t.id"
+	TrackGetUserFavoritesByRating = TrackGetUserFavorites + trackRatingOrder + ", t.name, t.id"
+	TrackGetByAlbumIDByRating     = trackGetByAlbumIDSelect + trackRatingOrder + ", t.disc_number, t.track_number"
+	TrackGetByMusicianIDByRating  = TrackGetByMusicianID + trackRatingOrder + ", t.name, t.id"
+	TrackGetOwnByRating           = TrackGetOwn + trackRatingOrder + ", t.name, t.id"
 )
 
+// trackListQuery picks the query listing the tracks in the requested order.
+func trackListQuery(sort domain.TrackSort, byDefault string, byRating string) string {
+	if sort == domain.TrackSortRating {
+		return byRating
+	}
+
+	return byDefault
+}
+
 type PostgresTrackRepository struct {
 	connection *sqlx.DB
 }
@@ -37,6 +68,17 @@ func NewPostgresTrackRepository(connection *sqlx.DB) *PostgresTrackRepository {
 }
 
 func (tr *PostgresTrackRepository) Create(ctx context.Context, track domain.Track) (domain.Track, error) {
+	if track.DiscNumber == 0 {
+		track.DiscNumber = 1
+	}
+
+	if track.TrackNumber == 0 {
+		err := tr.connection.GetContext(ctx, &track.TrackNumber, TrackNextNumberQuery, track.AlbumID, track.DiscNumber)
+		if err != nil {
+			return domain.Track{}, util.WrapError(ports.ErrInternalTrackRepo, err)
+		}
+	}
+
 	pgTrack := entity2.NewPgTrack(track)
 	queryString := entity2.InsertQueryString(pgTrack, "tracks")
 	_, err := tr.connection.NamedExecContext(ctx, queryString, pgTrack)
@@ -63,6 +105,17 @@ func (tr *PostgresTrackRepository) Create(ctx context.Context, track domain.Trac
 }
 
 func (tr *PostgresTrackRepository) Update(ctx context.Context, track domain.Track) (domain.Track, error) {
+	if track.DiscNumber == 0 {
+		track.DiscNumber = 1
+	}
+
+	if track.TrackNumber == 0 {
+		err := tr.connection.GetContext(ctx, &track.TrackNumber, TrackNextNumberQuery, track.AlbumID, track.DiscNumber)
+		if err != nil {
+			return domain.Track{}, util.WrapError(ports.ErrInternalTrackRepo, err)
+		}
+	}
+
 	pgTrack := entity2.NewPgTrack(track)
 	queryString := entity2.UpdateQueryString(pgTrack, "tracks")
 	_, err := tr.connection.NamedExecContext(ctx, queryString, pgTrack)
@@ -82,9 +135,9 @@ func (tr *PostgresTrackRepository) Update(ctx context.Context, track domain.Trac
 	return updatedTrack.ToDomain(), nil
 }
 
-func (tr *PostgresTrackRepository) GetAll(ctx context.Context) ([]domain.Track, error) {
-	var tracks []entity2.PgTrack
-	err := tr.connection.SelectContext(ctx, &tracks, TrackGetAllQuery)
+func (tr *PostgresTrackRepository) GetAll(ctx context.Context, sort domain.TrackSort) ([]domain.Track, error) {
+	var tracks []entity2.PgRatedTrack
+	err := tr.connection.SelectContext(ctx, &tracks, trackListQuery(sort, TrackGetAllQuery, TrackGetAllByRatingQuery))
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalTrackRepo, err)
 	}
@@ -98,7 +151,7 @@ func (tr *PostgresTrackRepository) GetAll(ctx context.Context) ([]domain.Track,
 }
 
 func (tr *PostgresTrackRepository) GetByID(ctx context.Context, trackID uuid.UUID) (domain.Track, error) {
-	var foundTrack entity2.PgTrack
+	var foundTrack entity2.PgRatedTrack
 	err := tr.connection.GetContext(ctx, &foundTrack, TrackGetByIDQuery, trackID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -146,9 +199,10 @@ func (tr *PostgresTrackRepository) DeleteFavorite(ctx context.Context, trackID u
 	return deletedTrack.ToDomain(), nil
 }
 
-func (tr *PostgresTrackRepository) GetUserFavorites(ctx context.Context, userID uuid.UUID) ([]domain.Track, error) {
-	var tracks []entity2.PgTrack
-	err := tr.connection.SelectContext(ctx, &tracks, TrackGetUserFavorites, userID)
+func (tr *PostgresTrackRepository) GetUserFavorites(ctx context.Context, userID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	var tracks []entity2.PgRatedTrack
+	err := tr.connection.SelectContext(ctx, &tracks,
+		trackListQuery(sort, TrackGetUserFavorites, TrackGetUserFavoritesByRating), userID)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalTrackRepo, err)
 	}
@@ -179,9 +233,10 @@ func (tr *PostgresTrackRepository) AddToUserFavorites(ctx context.Context, track
 	return nil
 }
 
-func (tr *PostgresTrackRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error) {
-	var tracks []entity2.PgTrack
-	err := tr.connection.SelectContext(ctx, &tracks, TrackGetByAlbumID, albumID)
+func (tr *PostgresTrackRepository) GetByAlbumID(ctx context.Context, albumID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	var tracks []entity2.PgRatedTrack
+	err := tr.connection.SelectContext(ctx, &tracks,
+		trackListQuery(sort, TrackGetByAlbumID, TrackGetByAlbumIDByRating), albumID)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalTrackRepo, err)
 	}
@@ -194,9 +249,25 @@ func (tr *PostgresTrackRepository) GetByAlbumID(ctx context.Context, albumID uui
 	return domainTracks, nil
 }
 
-func (tr *PostgresTrackRepository) GetByMusicianID(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error) {
-	var tracks []entity2.PgTrack
-	err := tr.connection.SelectContext(ctx, &tracks, TrackGetByMusicianID, musicianID)
+func (tr *PostgresTrackRepository) GetByMusicianID(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	var tracks []entity2.PgRatedTrack
+	err := tr.connection.SelectContext(ctx, &tracks,
+		trackListQuery(sort, TrackGetByMusicianID, TrackGetByMusicianIDByRating), musicianID)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalTrackRepo, err)
+	}
+
+	domainTracks := make([]domain.Track, len(tracks))
+	for i, track := range tracks {
+		domainTracks[i] = track.ToDomain()
+	}
+
+	return domainTracks, nil
+}
+
+func (tr *PostgresTrackRepository) GetOwn(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	var tracks []entity2.PgRatedTrack
+	err := tr.connection.SelectContext(ctx, &tracks, trackListQuery(sort, TrackGetOwn, TrackGetOwnByRating), musicianID)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalTrackRepo, err)
 	}
@@ -209,9 +280,9 @@ func (tr *PostgresTrackRepository) GetByMusicianID(ctx context.Context, musician
 	return domainTracks, nil
 }
 
-func (tr *PostgresTrackRepository) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error) {
+func (tr *PostgresTrackRepository) GetByAlbumIDInternal(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error) {
 	var tracks []entity2.PgTrack
-	err := tr.connection.SelectContext(ctx, &tracks, TrackGetOwn, musicianID)
+	err := tr.connection.SelectContext(ctx, &tracks, TrackGetByAlbumIDInternal, albumID)
 	if err != nil {
 		return nil, util.WrapError(ports.ErrInternalTrackRepo, err)
 	}
@@ -223,3 +294,42 @@ func (tr *PostgresTrackRepository) GetOwn(ctx context.Context, musicianID uuid.U
 
 	return domainTracks, nil
 }
+
+func (tr *PostgresTrackRepository) Reorder(ctx context.Context, albumID uuid.UUID, positions []domain.TrackPosition) error {
+	tx, err := tr.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTrackRepo, err)
+	}
+	defer tx.Rollback()
+
+	for _, position := range positions {
+		res, err := tx.ExecContext(ctx, TrackSetPositionQuery, position.TrackID, albumID,
+			position.DiscNumber, position.TrackNumber)
+		if err != nil {
+			return util.WrapError(ports.ErrTrackUpdate, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return util.WrapError(ports.ErrInternalTrackRepo, err)
+		}
+
+		if affected == 0 {
+			return ports.ErrTrackIDNotFound
+		}
+	}
+
+	// The position constraint is deferred, so conflicts surface on commit.
+	err = tx.Commit()
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == pgerrcode.UniqueViolation {
+				return util.WrapError(ports.ErrTrackPosition, err)
+			}
+		}
+		return util.WrapError(ports.ErrInternalTrackRepo, err)
+	}
+
+	return nil
+}
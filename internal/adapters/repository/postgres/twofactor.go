@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	TwoFactorGetQuery  = "SELECT account_id, secret, enabled_at, last_used_step FROM account_two_factor WHERE account_id = $1"
+	TwoFactorSaveQuery = "INSERT INTO account_two_factor (account_id, secret) VALUES ($1, $2) " +
+		"ON CONFLICT (account_id) DO UPDATE SET secret = EXCLUDED.secret, enabled_at = NULL, " +
+		"last_used_step = 0, created_at = now()"
+	TwoFactorDeleteRecoveryCodesQuery = "DELETE FROM account_recovery_codes WHERE account_id = $1"
+	TwoFactorInsertRecoveryCodeQuery  = "INSERT INTO account_recovery_codes (account_id, code_hash) VALUES ($1, $2)"
+	TwoFactorEnableQuery              = "UPDATE account_two_factor SET enabled_at = now(), last_used_step = $2 " +
+		"WHERE account_id = $1 AND enabled_at IS NULL"
+	TwoFactorDeleteQuery  = "DELETE FROM account_two_factor WHERE account_id = $1"
+	TwoFactorUseStepQuery = "UPDATE account_two_factor SET last_used_step = $2 " +
+		"WHERE account_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2"
+	TwoFactorUseRecoveryCodeQuery = "UPDATE account_recovery_codes SET used_at = now() " +
+		"WHERE account_id = $1 AND code_hash = $2 AND used_at IS NULL"
+)
+
+type PostgresTwoFactorRepository struct {
+	connection *sqlx.DB
+}
+
+func NewPostgresTwoFactorRepository(connection *sqlx.DB) *PostgresTwoFactorRepository {
+	return &PostgresTwoFactorRepository{connection: connection}
+}
+
+func (tr *PostgresTwoFactorRepository) Get(ctx context.Context, accountID uuid.UUID) (domain.TwoFactor, error) {
+	var twoFactor entity2.PgTwoFactor
+	err := tr.connection.GetContext(ctx, &twoFactor, TwoFactorGetQuery, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.TwoFactor{}, util.WrapError(ports.ErrTwoFactorNotFound, err)
+		}
+		return domain.TwoFactor{}, util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+
+	return twoFactor.ToDomain(), nil
+}
+
+func (tr *PostgresTwoFactorRepository) Save(ctx context.Context, twoFactor domain.TwoFactor, recoveryCodeHashes []string) error {
+	tx, err := tr.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, TwoFactorSaveQuery, twoFactor.AccountID, twoFactor.Secret)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+
+	_, err = tx.ExecContext(ctx, TwoFactorDeleteRecoveryCodesQuery, twoFactor.AccountID)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		_, err = tx.ExecContext(ctx, TwoFactorInsertRecoveryCodeQuery, twoFactor.AccountID, codeHash)
+		if err != nil {
+			return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+
+	return nil
+}
+
+func (tr *PostgresTwoFactorRepository) Enable(ctx context.Context, accountID uuid.UUID, step int64) error {
+	return tr.execAffecting(ctx, ports.ErrTwoFactorNotFound, TwoFactorEnableQuery, accountID, step)
+}
+
+func (tr *PostgresTwoFactorRepository) Delete(ctx context.Context, accountID uuid.UUID) error {
+	return tr.execAffecting(ctx, ports.ErrTwoFactorNotFound, TwoFactorDeleteQuery, accountID)
+}
+
+func (tr *PostgresTwoFactorRepository) UseStep(ctx context.Context, accountID uuid.UUID, step int64) error {
+	return tr.execAffecting(ctx, ports.ErrTwoFactorInvalidCode, TwoFactorUseStepQuery, accountID, step)
+}
+
+func (tr *PostgresTwoFactorRepository) UseRecoveryCode(ctx context.Context, accountID uuid.UUID, codeHash string) error {
+	return tr.execAffecting(ctx, ports.ErrTwoFactorInvalidCode, TwoFactorUseRecoveryCodeQuery, accountID, codeHash)
+}
+
+// execAffecting runs a statement that has to change exactly one row and
+// returns notAffected when it changed none.
+func (tr *PostgresTwoFactorRepository) execAffecting(ctx context.Context, notAffected error, query string,
+	args ...interface{}) error {
+	result, err := tr.connection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalTwoFactorRepo, err)
+	}
+
+	if affected == 0 {
+		return notAffected
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	entity2 "github.com/hanoys/sigma-music/internal/adapters/repository/postgres/entity"
@@ -16,13 +17,29 @@ import (
 )
 
 const (
-	UserGetAllQuery     = "SELECT * FROM users"
-	UserGetByIDQuery    = "SELECT * FROM users WHERE id = $1"
-	UserGetByNameQuery  = "SELECT * FROM users WHERE name = $1"
-	UserGetByEmailQuery = "SELECT * FROM users WHERE email = $1"
-	UserGetByPhoneQuery = "SELECT * FROM users WHERE phone = $1"
+	UserGetAllQuery          = "SELECT * FROM users"
+	UserGetByIDQuery         = "SELECT * FROM users WHERE id = $1"
+	UserGetByNameQuery       = "SELECT * FROM users WHERE name = $1"
+	UserGetByEmailQuery      = "SELECT * FROM users WHERE email = $1"
+	UserGetByPhoneQuery      = "SELECT * FROM users WHERE phone = $1"
+	UserGetScheduledQuery    = "SELECT id FROM user_profiles WHERE deletion_scheduled_at <= $1"
+	UserDeleteScheduledQuery = "DELETE FROM accounts WHERE id = $1 AND id IN " +
+		"(SELECT id FROM user_profiles WHERE deletion_scheduled_at <= $2)"
+
+	// Unset fields are passed as NULL and keep the stored value.
+	UserUpdateProfileQuery = "UPDATE user_profiles SET phone = COALESCE($2, phone), " +
+		"country = COALESCE($3, country) WHERE id = $1"
+	UserUpdateAccountQuery = "UPDATE accounts SET name = COALESCE($2, name), email = COALESCE($3, email) WHERE id = $1"
+
+	UserSetRoleQuery    = "UPDATE user_profiles SET role = $2 WHERE id = $1"
+	UserSetAvatarQuery  = "UPDATE user_profiles SET avatar_url = $2 WHERE id = $1"
+	UserScheduleQuery   = "UPDATE user_profiles SET deletion_scheduled_at = $2 WHERE id = $1 AND deletion_scheduled_at IS NULL"
+	UserUnscheduleQuery = "UPDATE user_profiles SET deletion_scheduled_at = NULL " +
+		"WHERE id = $1 AND deletion_scheduled_at IS NOT NULL"
 )
 
+// PostgresUserRepository reads users from the users view, which joins
+// user_profiles with accounts, and writes both tables in one transaction.
 type PostgresUserRepository struct {
 	connection *sqlx.DB
 }
@@ -32,10 +49,18 @@ func NewPostgresUserRepository(connection *sqlx.DB) *PostgresUserRepository {
 }
 
 func (ur *PostgresUserRepository) Create(ctx context.Context, user domain.User) (domain.User, error) {
-	pgUser := entity2.NewPgUser(user)
-	queryString := entity2.InsertQueryString(pgUser, "users")
+	tx, err := ur.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.User{}, util.WrapError(ports.ErrInternalUserRepo, err)
+	}
+	defer tx.Rollback()
 
-	_, err := ur.connection.NamedExecContext(ctx, queryString, pgUser)
+	pgAccount := entity2.NewPgUserAccount(user)
+	_, err = tx.NamedExecContext(ctx, entity2.InsertQueryString(pgAccount, "accounts"), pgAccount)
+	if err == nil {
+		pgProfile := entity2.NewPgUserProfile(user)
+		_, err = tx.NamedExecContext(ctx, entity2.InsertQueryString(pgProfile, "user_profiles"), pgProfile)
+	}
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -46,8 +71,13 @@ func (ur *PostgresUserRepository) Create(ctx context.Context, user domain.User)
 		return domain.User{}, util.WrapError(ports.ErrInternalUserRepo, err)
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		return domain.User{}, util.WrapError(ports.ErrInternalUserRepo, err)
+	}
+
 	var createdUser entity2.PgUser
-	err = ur.connection.GetContext(ctx, &createdUser, UserGetByIDQuery, pgUser.ID)
+	err = ur.connection.GetContext(ctx, &createdUser, UserGetByIDQuery, user.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, util.WrapError(ports.ErrUserIDNotFound, err)
@@ -58,6 +88,94 @@ func (ur *PostgresUserRepository) Create(ctx context.Context, user domain.User)
 	return createdUser.ToDomain(), nil
 }
 
+// SetRole changes only the role column, leaving the account untouched.
+func (ur *PostgresUserRepository) SetRole(ctx context.Context, userID uuid.UUID, role int) (domain.User, error) {
+	return ur.setProfileColumn(ctx, ports.ErrUserIDNotFound, UserSetRoleQuery, userID, role)
+}
+
+// SetAvatar changes only the avatar_url column.
+func (ur *PostgresUserRepository) SetAvatar(ctx context.Context, userID uuid.UUID,
+	avatarURL string) (domain.User, error) {
+	return ur.setProfileColumn(ctx, ports.ErrUserIDNotFound, UserSetAvatarQuery, userID, avatarURL)
+}
+
+// ScheduleDeletion sets the deletion time unless one is already set.
+func (ur *PostgresUserRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID,
+	at time.Time) (domain.User, error) {
+	return ur.setProfileColumn(ctx, ports.ErrUserDeletionRequested, UserScheduleQuery, userID, at)
+}
+
+// CancelDeletion clears the deletion time if one is set.
+func (ur *PostgresUserRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	return ur.setProfileColumn(ctx, ports.ErrUserDeletionNotRequested, UserUnscheduleQuery, userID)
+}
+
+// setProfileColumn runs a single-column user_profiles update and returns the
+// fresh user. When no row changes it reports a missing user or, if the user
+// exists, the given conflict error.
+func (ur *PostgresUserRepository) setProfileColumn(ctx context.Context, conflict error, query string,
+	userID uuid.UUID, args ...interface{}) (domain.User, error) {
+	res, err := ur.connection.ExecContext(ctx, query, append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return domain.User{}, util.WrapError(ports.ErrUserUpdate, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return domain.User{}, util.WrapError(ports.ErrUserUpdate, err)
+	}
+
+	user, err := ur.GetByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if rows == 0 {
+		return domain.User{}, conflict
+	}
+
+	return user, nil
+}
+
+// UpdateProfile writes the changed profile fields in one transaction. It never
+// touches the password, which only the account repository changes.
+func (ur *PostgresUserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID,
+	changes domain.UserProfileChanges) (domain.User, error) {
+	tx, err := ur.connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.User{}, util.WrapError(ports.ErrInternalUserRepo, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, UserUpdateProfileQuery, userID, changes.Phone, changes.Country)
+	if err == nil {
+		var rows int64
+		rows, err = res.RowsAffected()
+		if err == nil && rows == 0 {
+			return domain.User{}, ports.ErrUserIDNotFound
+		}
+	}
+	if err == nil {
+		_, err = tx.ExecContext(ctx, UserUpdateAccountQuery, userID, changes.Name, changes.Email)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == pgerrcode.UniqueViolation {
+				return domain.User{}, util.WrapError(ports.ErrUserDuplicate, err)
+			}
+		}
+		return domain.User{}, util.WrapError(ports.ErrUserUpdate, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.User{}, util.WrapError(ports.ErrUserUpdate, err)
+	}
+
+	return ur.GetByID(ctx, userID)
+}
+
 func (ur *PostgresUserRepository) GetAll(ctx context.Context) ([]domain.User, error) {
 	var users []entity2.PgUser
 	err := ur.connection.SelectContext(ctx, &users, UserGetAllQuery)
@@ -124,3 +242,35 @@ func (ur *PostgresUserRepository) GetByPhone(ctx context.Context, phone string)
 
 	return foundUser.ToDomain(), nil
 }
+
+// GetScheduledBefore returns the IDs of users whose deletion is due by the
+// given time.
+func (ur *PostgresUserRepository) GetScheduledBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := ur.connection.SelectContext(ctx, &ids, UserGetScheduledQuery, before)
+	if err != nil {
+		return nil, util.WrapError(ports.ErrInternalUserRepo, err)
+	}
+
+	return ids, nil
+}
+
+// DeleteScheduled removes the account if its deletion is still due by the
+// given time, so a request cancelled in the meantime keeps the account.
+func (ur *PostgresUserRepository) DeleteScheduled(ctx context.Context, userID uuid.UUID, before time.Time) error {
+	res, err := ur.connection.ExecContext(ctx, UserDeleteScheduledQuery, userID, before)
+	if err != nil {
+		return util.WrapError(ports.ErrInternalUserRepo, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return util.WrapError(ports.ErrInternalUserRepo, err)
+	}
+
+	if rows == 0 {
+		return ports.ErrUserDeletionNotRequested
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/adapters/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors, base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPProvider(t *testing.T) {
+	provider := totp.NewProvider("Sigma Music")
+
+	t.Run("test rfc 6238 vectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+
+		for unix, expected := range vectors {
+			code, err := totp.Code(rfcSecret, time.Unix(unix, 0))
+			require.NoError(t, err)
+			require.Equal(t, expected, code, unix)
+		}
+	})
+
+	t.Run("test validate accepts adjacent steps", func(t *testing.T) {
+		secret, err := provider.GenerateSecret()
+		require.NoError(t, err)
+		now := time.Unix(1700000000, 0)
+
+		previous, err := totp.Code(secret, now.Add(-30*time.Second))
+		require.NoError(t, err)
+		step, ok := provider.Validate(secret, previous, now)
+		require.True(t, ok)
+		require.Equal(t, now.Unix()/30-1, step)
+
+		stale, err := totp.Code(secret, now.Add(-90*time.Second))
+		require.NoError(t, err)
+		_, ok = provider.Validate(secret, stale, now)
+		require.False(t, ok)
+	})
+
+	t.Run("test validate rejects malformed code", func(t *testing.T) {
+		_, ok := provider.Validate(rfcSecret, "12345", time.Now())
+		require.False(t, ok)
+
+		_, ok = provider.Validate("not base32!", "123456", time.Now())
+		require.False(t, ok)
+	})
+
+	t.Run("test uri", func(t *testing.T) {
+		uri := provider.URI(rfcSecret, "musician")
+		require.True(t, strings.HasPrefix(uri, "otpauth://totp/Sigma%20Music:musician?"))
+		require.Contains(t, uri, "secret="+rfcSecret)
+		require.Contains(t, uri, "issuer=Sigma+Music")
+	})
+
+	t.Run("test recovery codes", func(t *testing.T) {
+		codes, err := provider.GenerateRecoveryCodes()
+		require.NoError(t, err)
+		require.Len(t, codes, 10)
+
+		seen := make(map[string]bool)
+		for _, code := range codes {
+			require.Len(t, code, 11)
+			require.False(t, seen[code])
+			seen[code] = true
+		}
+
+		require.Equal(t, provider.HashRecoveryCode(codes[0]),
+			provider.HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+	})
+}
@@ -8,10 +8,16 @@ import (
 	"time"
 
 	"github.com/JeremyLoy/config"
+	"github.com/hanoys/sigma-music/internal/adapters/auth"
+	"github.com/hanoys/sigma-music/internal/adapters/hash"
+	"github.com/hanoys/sigma-music/internal/adapters/mail"
+	"github.com/hanoys/sigma-music/internal/adapters/payment"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"github.com/jmoiron/sqlx"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -37,6 +43,13 @@ type Config struct {
 		AccessTokenExpTime  int64  `yaml:"access_expiration_time"`
 		RefreshTokenExpTime int64  `yaml:"refresh_expiration_time"`
 		SecretKey           string `yaml:"secret"`
+		SigningKeyID        string `yaml:"signing_key_id"`
+		Keys                []struct {
+			ID         string `yaml:"id"`
+			Algorithm  string `yaml:"algorithm"`
+			PrivateKey string `yaml:"private_key"`
+			PublicKey  string `yaml:"public_key"`
+		} `yaml:"keys"`
 	} `yaml:"jwt"`
 
 	Redis struct {
@@ -49,6 +62,9 @@ type Config struct {
 		TrackBucketName         string `config:"TRACK_MINIO_BUCKET_NAME"`
 		AlbumImageBucketName    string `config:"ALBUM_IMAGE_MINIO_BUCKET_NAME"`
 		MusicianImageBucketName string `config:"MUSICIAN_IMAGE_MINIO_BUCKET_NAME"`
+		UserAvatarBucketName    string `config:"USER_AVATAR_MINIO_BUCKET_NAME"`
+		ExportBucketName        string `config:"EXPORT_MINIO_BUCKET_NAME"`
+		PublicEndpoint          string `config:"MINIO_PUBLIC_ENDPOINT"`
 		RootUser                string `config:"MINIO_ROOT_USER"`
 		RootPassword            string `config:"MINIO_ROOT_PASSWORD"`
 	}
@@ -56,6 +72,104 @@ type Config struct {
 	Logger struct {
 		LogLevel string `yaml:"level"`
 	} `yaml:"log"`
+
+	Scheduler struct {
+		ReleaseInterval int64 `yaml:"release_interval"`
+		ExpiryInterval  int64 `yaml:"subscription_expiry_interval"`
+		RoyaltyInterval int64 `yaml:"royalty_allocation_interval"`
+		PurgeInterval   int64 `yaml:"user_purge_interval"`
+		ExportInterval  int64 `yaml:"data_export_interval"`
+	} `yaml:"scheduler"`
+
+	Hash struct {
+		Algorithm string `yaml:"algorithm"`
+		Argon2id  struct {
+			Memory      uint32 `yaml:"memory"`
+			Iterations  uint32 `yaml:"iterations"`
+			Parallelism uint8  `yaml:"parallelism"`
+		} `yaml:"argon2id"`
+		BcryptCost int `yaml:"bcrypt_cost"`
+	} `yaml:"hash"`
+
+	Mail struct {
+		Driver string `yaml:"driver"`
+		From   string `yaml:"from"`
+		Dir    string `yaml:"dir"`
+		SMTP   struct {
+			Host     string `yaml:"host"`
+			Port     string `yaml:"port"`
+			Username string `yaml:"username"`
+			Password string `yaml:"password"`
+		} `yaml:"smtp"`
+	} `yaml:"mail"`
+
+	Account struct {
+		TokenSecret           string `yaml:"token_secret"`
+		VerifyEmailURL        string `yaml:"verify_email_url"`
+		ResetPasswordURL      string `yaml:"reset_password_url"`
+		VerifyEmailTokenTTL   int64  `yaml:"verify_email_expiration_time"`
+		ResetPasswordTokenTTL int64  `yaml:"reset_password_expiration_time"`
+		TOTPIssuer            string `yaml:"totp_issuer"`
+		DeletionGracePeriod   int64  `yaml:"deletion_grace_period"`
+	} `yaml:"account"`
+
+	RateLimit struct {
+		Auth    RateLimitConfig `yaml:"auth"`
+		Login   RateLimitConfig `yaml:"login"`
+		Upload  RateLimitConfig `yaml:"upload"`
+		Comment RateLimitConfig `yaml:"comment"`
+		Play    RateLimitConfig `yaml:"play"`
+		Lockout struct {
+			Threshold     int   `yaml:"threshold"`
+			BaseDuration  int64 `yaml:"base_duration"`
+			MaxDuration   int64 `yaml:"max_duration"`
+			FailureWindow int64 `yaml:"failure_window"`
+		} `yaml:"lockout"`
+	} `yaml:"rate_limit"`
+
+	Payment struct {
+		Driver        string `yaml:"driver"`
+		WebhookSecret string `yaml:"webhook_secret"`
+		CheckoutURL   string `yaml:"checkout_url"`
+	} `yaml:"payment"`
+
+	Comment struct {
+		BannedWords []string `yaml:"banned_words"`
+	} `yaml:"comment"`
+
+	Export struct {
+		RetentionDays int   `yaml:"retention_days"`
+		LinkTTL       int64 `yaml:"link_ttl"`
+	} `yaml:"export"`
+
+	Royalty struct {
+		MinPlaySeconds     int   `yaml:"min_play_seconds"`
+		ArtistSharePercent int64 `yaml:"artist_share_percent"`
+	} `yaml:"royalty"`
+
+	OIDC struct {
+		Providers []struct {
+			Name         string   `yaml:"name"`
+			Issuer       string   `yaml:"issuer"`
+			ClientID     string   `yaml:"client_id"`
+			ClientSecret string   `yaml:"client_secret"`
+			RedirectURL  string   `yaml:"redirect_url"`
+			Scopes       []string `yaml:"scopes"`
+		} `yaml:"providers"`
+	} `yaml:"oidc"`
+}
+
+// RateLimitConfig allows requests per window seconds.
+type RateLimitConfig struct {
+	Requests int   `yaml:"requests"`
+	Window   int64 `yaml:"window"`
+}
+
+func (c RateLimitConfig) ToDomain() domain.RateLimit {
+	return domain.RateLimit{
+		Requests: c.Requests,
+		Window:   time.Duration(c.Window) * time.Second,
+	}
 }
 
 func GetConfig(configPath string) (*Config, error) {
@@ -107,10 +221,43 @@ type MinioConfig struct {
 	TrackBucketName         string
 	AlbumImageBucketName    string
 	MusicianImageBucketName string
+	UserAvatarBucketName    string
+	ExportBucketName        string
+	ExportRetentionDays     int
+	PublicEndpoint          string
 	RootUser                string
 	RootPassword            string
 }
 
+type HashConfig struct {
+	Algorithm           string
+	Argon2idMemory      uint32
+	Argon2idIterations  uint32
+	Argon2idParallelism uint8
+	BcryptCost          int
+}
+
+type JWTKeysConfig struct {
+	SigningKeyID string
+	Keys         []auth.KeyConfig
+}
+
+type MailConfig struct {
+	Driver       string
+	From         string
+	Dir          string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+type PaymentConfig struct {
+	Driver        string
+	WebhookSecret string
+	CheckoutURL   string
+}
+
 type LoggerConfig struct {
 	LogLevel string
 }
@@ -207,9 +354,126 @@ func NewMinioClient(cfg *MinioConfig) (*minio.Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = minioCreateBucket(ctx, minioClient, cfg.UserAvatarBucketName)
+	if err != nil {
+		return nil, err
+	}
+	err = minioCreatePrivateBucket(ctx, minioClient, cfg.ExportBucketName, cfg.ExportRetentionDays)
+	if err != nil {
+		return nil, err
+	}
 	return minioClient, nil
 }
 
+// minioCreatePrivateBucket makes a bucket without the public read policy whose
+// objects are removed by minio after expirationDays.
+func minioCreatePrivateBucket(ctx context.Context, minioClient *minio.Client, bucketName string,
+	expirationDays int) error {
+	err := minioClient.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{})
+	if err != nil {
+		exists, errBucketExists := minioClient.BucketExists(ctx, bucketName)
+		if errBucketExists != nil || !exists {
+			return errors.Wrap(errBucketExists, "failed to make minio bucket")
+		}
+	}
+
+	if expirationDays <= 0 {
+		return nil
+	}
+
+	config := lifecycle.NewConfiguration()
+	config.Rules = []lifecycle.Rule{{
+		ID:         "expire",
+		Status:     "Enabled",
+		Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(expirationDays)},
+	}}
+	err = minioClient.SetBucketLifecycle(ctx, bucketName, config)
+	if err != nil {
+		return errors.Wrap(err, "failed to set bucket lifecycle")
+	}
+
+	return nil
+}
+
+// NewMinioSigner returns a client for signing download links. It never talks
+// to minio: the region is fixed, so presigning doesn't look it up.
+func NewMinioSigner(cfg *MinioConfig) (*minio.Client, error) {
+	endpoint := cfg.PublicEndpoint
+	if endpoint == "" {
+		endpoint = strings.Replace(cfg.Endpoint, "minio", "localhost", 1)
+	}
+
+	signer, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.RootUser, cfg.RootPassword, ""),
+		Secure: false,
+		Region: "us-east-1",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create minio signer")
+	}
+
+	return signer, nil
+}
+
+func NewHashPasswordProvider(cfg *HashConfig) (ports.IHashPasswordProvider, error) {
+	switch strings.ToLower(cfg.Algorithm) {
+	case "", "argon2id":
+		params := hash.DefaultArgon2idParams()
+		if cfg.Argon2idMemory > 0 {
+			params.Memory = cfg.Argon2idMemory
+		}
+		if cfg.Argon2idIterations > 0 {
+			params.Iterations = cfg.Argon2idIterations
+		}
+		if cfg.Argon2idParallelism > 0 {
+			params.Parallelism = cfg.Argon2idParallelism
+		}
+		return hash.NewArgon2idPasswordProvider(params), nil
+	case "bcrypt":
+		return hash.NewBcryptPasswordProvider(cfg.BcryptCost), nil
+	case "sha256":
+		return hash.NewHashPasswordProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown hash algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// NewJWTKeySet returns nil when no keys are configured, in which case the
+// token provider keeps signing with the shared secret.
+func NewJWTKeySet(cfg *JWTKeysConfig) (*auth.KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, nil
+	}
+
+	return auth.LoadKeySet(cfg.SigningKeyID, cfg.Keys)
+}
+
+func NewMailer(cfg *MailConfig) (ports.IMailer, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "file":
+		return mail.NewFileMailer(cfg.Dir, cfg.From), nil
+	case "smtp":
+		return mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", cfg.Driver)
+	}
+}
+
+func NewPaymentGateway(cfg *PaymentConfig) (ports.IPaymentGateway, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "fake":
+		return payment.NewFakeGateway(cfg.WebhookSecret, cfg.CheckoutURL), nil
+	default:
+		return nil, fmt.Errorf("unknown payment driver: %s", cfg.Driver)
+	}
+}
+
 func NewLogger(cfg *LoggerConfig) (*zap.Logger, error) {
 	var logLevel zap.AtomicLevel
 	if strings.ToLower(cfg.LogLevel) == "info" {
@@ -246,11 +510,20 @@ func NewLogger(cfg *LoggerConfig) (*zap.Logger, error) {
 }
 
 type Repositories struct {
-	User     ports.IUserRepository
-	Musician ports.IMusicianRepository
-	Album    ports.IAlbumRepository
-	Comment  ports.ICommentRepository
-	Genre    ports.IGenreRepository
-	Stat     ports.IStatRepository
-	Track    ports.ITrackRepository
+	Account          ports.IAccountRepository
+	TwoFactor        ports.ITwoFactorRepository
+	ExternalIdentity ports.IExternalIdentityRepository
+	User             ports.IUserRepository
+	Musician         ports.IMusicianRepository
+	Album            ports.IAlbumRepository
+	Comment          ports.ICommentRepository
+	Genre            ports.IGenreRepository
+	Stat             ports.IStatRepository
+	Track            ports.ITrackRepository
+	Follow           ports.IFollowRepository
+	Credit           ports.ICreditRepository
+	Subscription     ports.ISubscriptionRepository
+	Order            ports.IOrderRepository
+	Royalty          ports.IRoyaltyRepository
+	DataExport       ports.IDataExportRepository
 }
@@ -2,13 +2,14 @@ package console
 
 import (
 	"log"
+	"time"
 
 	"github.com/hanoys/sigma-music/internal/adapters/auth"
 	"github.com/hanoys/sigma-music/internal/adapters/auth/adapters"
 	consd "github.com/hanoys/sigma-music/internal/adapters/delivery/console"
-	"github.com/hanoys/sigma-music/internal/adapters/hash"
 	"github.com/hanoys/sigma-music/internal/adapters/miniostorage"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/adapters/totp"
 	"github.com/hanoys/sigma-music/internal/app/config"
 	"github.com/hanoys/sigma-music/internal/service"
 	"go.uber.org/zap"
@@ -42,6 +43,8 @@ func Run() {
 			return
 		}
 
+		repositories.Account = postgres.NewPostgresAccountRepository(dbConn)
+		repositories.TwoFactor = postgres.NewPostgresTwoFactorRepository(dbConn)
 		repositories.User = postgres.NewPostgresUserRepository(dbConn)
 		repositories.Musician = postgres.NewPostgresMusicianRepository(dbConn)
 		repositories.Album = postgres.NewPostgresAlbumRepository(dbConn)
@@ -49,6 +52,9 @@ func Run() {
 		repositories.Genre = postgres.NewPostgresGenreRepository(dbConn)
 		repositories.Stat = postgres.NewPostgresStatRepository(dbConn)
 		repositories.Track = postgres.NewPostgresTrackRepository(dbConn)
+		repositories.Follow = postgres.NewPostgresFollowRepository(dbConn)
+		repositories.Credit = postgres.NewPostgresCreditRepository(dbConn)
+		repositories.DataExport = postgres.NewPostgresDataExportRepository(dbConn)
 	default:
 		logger.Fatal("Error unknown database name", zap.Error(err),
 			zap.String("Database name", cfg.DB.Type))
@@ -65,16 +71,23 @@ func Run() {
 	}
 
 	minioClient, err := config.NewMinioClient(&config.MinioConfig{
-		Endpoint:        cfg.Minio.Endpoint,
-		TrackBucketName: cfg.Minio.TrackBucketName,
-		RootUser:        cfg.Minio.RootUser,
-		RootPassword:    cfg.Minio.RootPassword,
+		Endpoint:                cfg.Minio.Endpoint,
+		TrackBucketName:         cfg.Minio.TrackBucketName,
+		AlbumImageBucketName:    cfg.Minio.AlbumImageBucketName,
+		MusicianImageBucketName: cfg.Minio.MusicianImageBucketName,
+		UserAvatarBucketName:    cfg.Minio.UserAvatarBucketName,
+		ExportBucketName:        cfg.Minio.ExportBucketName,
+		ExportRetentionDays:     cfg.Export.RetentionDays,
+		RootUser:                cfg.Minio.RootUser,
+		RootPassword:            cfg.Minio.RootPassword,
 	})
 	if err != nil {
 		logger.Fatal("Error connecting minio", zap.Error(err))
 		return
 	}
 
+	accountRepo := repositories.Account
+	twoFactorRepo := repositories.TwoFactor
 	userRepo := repositories.User
 	musicianRepo := repositories.Musician
 	albumRepo := repositories.Album
@@ -82,25 +95,66 @@ func Run() {
 	genreRepo := repositories.Genre
 	statRepo := repositories.Stat
 	trackRepo := repositories.Track
+	followRepo := repositories.Follow
+	exportRepo := repositories.DataExport
+
+	jwtKeysConfig := config.JWTKeysConfig{SigningKeyID: cfg.JWT.SigningKeyID}
+	for _, key := range cfg.JWT.Keys {
+		jwtKeysConfig.Keys = append(jwtKeysConfig.Keys, auth.KeyConfig{
+			ID:             key.ID,
+			Algorithm:      key.Algorithm,
+			PrivateKeyPath: key.PrivateKey,
+			PublicKeyPath:  key.PublicKey,
+		})
+	}
+	jwtKeys, err := config.NewJWTKeySet(&jwtKeysConfig)
+	if err != nil {
+		logger.Fatal("Error loading jwt keys", zap.Error(err))
+		return
+	}
 
 	tokenStorage := adapters.NewTokenStorage(redisClient)
 	tokenProvider := auth.NewProvider(tokenStorage, &auth.ProviderConfig{
 		AccessTokenExpTime:  cfg.JWT.AccessTokenExpTime,
 		RefreshTokenExpTime: cfg.JWT.RefreshTokenExpTime,
 		SecretKey:           cfg.JWT.SecretKey,
+		Keys:                jwtKeys,
+	})
+	hashProvider, err := config.NewHashPasswordProvider(&config.HashConfig{
+		Algorithm:           cfg.Hash.Algorithm,
+		Argon2idMemory:      cfg.Hash.Argon2id.Memory,
+		Argon2idIterations:  cfg.Hash.Argon2id.Iterations,
+		Argon2idParallelism: cfg.Hash.Argon2id.Parallelism,
+		BcryptCost:          cfg.Hash.BcryptCost,
 	})
-	hashProvider := hash.NewHashPasswordProvider()
+	if err != nil {
+		logger.Fatal("Error creating hash provider", zap.Error(err))
+		return
+	}
+	oneTimeTokenProvider := auth.NewOneTimeTokenProvider(tokenStorage, cfg.Account.TokenSecret)
+	totpProvider := totp.NewProvider(cfg.Account.TOTPIssuer)
 	trackStorage := miniostorage.NewTrackStorage(minioClient, cfg.Minio.TrackBucketName)
 	albumStorage := miniostorage.NewAlbumImageStorage(minioClient, cfg.Minio.AlbumImageBucketName)
+	musicianImageStorage := miniostorage.NewMusicianImageStorage(minioClient, cfg.Minio.MusicianImageBucketName)
+	userAvatarStorage := miniostorage.NewUserAvatarStorage(minioClient, cfg.Minio.UserAvatarBucketName)
+	// The console never signs download links, the internal client will do.
+	exportStorage := miniostorage.NewDataExportStorage(minioClient, minioClient, cfg.Minio.ExportBucketName)
 
-	authService := service.NewAuthorizationService(userRepo, musicianRepo, tokenProvider, hashProvider, logger)
-	userService := service.NewUserService(userRepo, hashProvider, logger)
-	musicianService := service.NewMusicianService(musicianRepo, hashProvider, logger)
-	albumService := service.NewAlbumService(albumRepo, albumStorage, logger)
-	commentService := service.NewCommentService(commentRepo, logger)
+	authService := service.NewAuthorizationService(accountRepo, userRepo, twoFactorRepo, tokenProvider,
+		oneTimeTokenProvider, totpProvider, hashProvider, logger)
+	userService := service.NewUserService(userRepo, userAvatarStorage, exportRepo, exportStorage, tokenProvider,
+		hashProvider, service.UserServiceConfig{
+			DeletionGracePeriod: time.Duration(cfg.Account.DeletionGracePeriod) * 24 * time.Hour,
+		}, logger)
+	musicianService := service.NewMusicianService(musicianRepo, musicianImageStorage, hashProvider, logger)
+	followService := service.NewFollowService(followRepo, logger)
 	genreService := service.NewGenreService(genreRepo, logger)
 	statService := service.NewStatService(statRepo, genreService, musicianService, logger)
 	trackService := service.NewTrackService(trackRepo, trackStorage, genreService, logger)
+	commentService := service.NewCommentService(commentRepo, trackService, service.CommentServiceConfig{
+		BannedWords: cfg.Comment.BannedWords,
+	}, logger)
+	albumService := service.NewAlbumService(albumRepo, albumStorage, trackService, followService, logger)
 
 	cons := consd.NewConsole(consd.NewHandler(consd.HandlerParams{
 		AlbumService:    albumService,
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
@@ -8,10 +9,14 @@ import (
 	"github.com/hanoys/sigma-music/internal/adapters/auth"
 	"github.com/hanoys/sigma-music/internal/adapters/auth/adapters"
 	"github.com/hanoys/sigma-music/internal/adapters/delivery/api"
-	"github.com/hanoys/sigma-music/internal/adapters/hash"
 	"github.com/hanoys/sigma-music/internal/adapters/miniostorage"
+	"github.com/hanoys/sigma-music/internal/adapters/oidc"
+	"github.com/hanoys/sigma-music/internal/adapters/ratelimit"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/adapters/totp"
 	"github.com/hanoys/sigma-music/internal/app/config"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
 	"github.com/hanoys/sigma-music/internal/service"
 	"go.uber.org/zap"
 )
@@ -44,6 +49,9 @@ func Run() {
 			return
 		}
 
+		repositories.Account = postgres.NewPostgresAccountRepository(dbConn)
+		repositories.TwoFactor = postgres.NewPostgresTwoFactorRepository(dbConn)
+		repositories.ExternalIdentity = postgres.NewPostgresExternalIdentityRepository(dbConn)
 		repositories.User = postgres.NewPostgresUserRepository(dbConn)
 		repositories.Musician = postgres.NewPostgresMusicianRepository(dbConn)
 		repositories.Album = postgres.NewPostgresAlbumRepository(dbConn)
@@ -51,6 +59,12 @@ func Run() {
 		repositories.Genre = postgres.NewPostgresGenreRepository(dbConn)
 		repositories.Stat = postgres.NewPostgresStatRepository(dbConn)
 		repositories.Track = postgres.NewPostgresTrackRepository(dbConn)
+		repositories.Follow = postgres.NewPostgresFollowRepository(dbConn)
+		repositories.Credit = postgres.NewPostgresCreditRepository(dbConn)
+		repositories.Subscription = postgres.NewPostgresSubscriptionRepository(dbConn)
+		repositories.Order = postgres.NewPostgresOrderRepository(dbConn)
+		repositories.Royalty = postgres.NewPostgresRoyaltyRepository(dbConn)
+		repositories.DataExport = postgres.NewPostgresDataExportRepository(dbConn)
 	default:
 		logger.Fatal("Error unknown database name", zap.Error(err),
 			zap.String("Database name", cfg.DB.Type))
@@ -66,56 +80,209 @@ func Run() {
 		return
 	}
 
-	minioClient, err := config.NewMinioClient(&config.MinioConfig{
+	minioConfig := config.MinioConfig{
 		Endpoint:                cfg.Minio.Endpoint,
 		TrackBucketName:         cfg.Minio.TrackBucketName,
 		AlbumImageBucketName:    cfg.Minio.AlbumImageBucketName,
 		MusicianImageBucketName: cfg.Minio.MusicianImageBucketName,
+		UserAvatarBucketName:    cfg.Minio.UserAvatarBucketName,
+		ExportBucketName:        cfg.Minio.ExportBucketName,
+		ExportRetentionDays:     cfg.Export.RetentionDays,
+		PublicEndpoint:          cfg.Minio.PublicEndpoint,
 		RootUser:                cfg.Minio.RootUser,
 		RootPassword:            cfg.Minio.RootPassword,
-	})
+	}
+	minioClient, err := config.NewMinioClient(&minioConfig)
 	if err != nil {
 		logger.Fatal("Error connecting minio", zap.Error(err))
 		return
 	}
+	minioSigner, err := config.NewMinioSigner(&minioConfig)
+	if err != nil {
+		logger.Fatal("Error creating minio signer", zap.Error(err))
+		return
+	}
 
+	accountRepo := repositories.Account
+	twoFactorRepo := repositories.TwoFactor
+	identityRepo := repositories.ExternalIdentity
 	userRepo := repositories.User
 	musicianRepo := repositories.Musician
 	albumRepo := repositories.Album
 	commentRepo := repositories.Comment
 	genreRepo := repositories.Genre
 	trackRepo := repositories.Track
+	followRepo := repositories.Follow
+	creditRepo := repositories.Credit
+	subscriptionRepo := repositories.Subscription
+	orderRepo := repositories.Order
+	royaltyRepo := repositories.Royalty
+	statRepo := repositories.Stat
+	exportRepo := repositories.DataExport
+
+	jwtKeysConfig := config.JWTKeysConfig{SigningKeyID: cfg.JWT.SigningKeyID}
+	for _, key := range cfg.JWT.Keys {
+		jwtKeysConfig.Keys = append(jwtKeysConfig.Keys, auth.KeyConfig{
+			ID:             key.ID,
+			Algorithm:      key.Algorithm,
+			PrivateKeyPath: key.PrivateKey,
+			PublicKeyPath:  key.PublicKey,
+		})
+	}
+	jwtKeys, err := config.NewJWTKeySet(&jwtKeysConfig)
+	if err != nil {
+		logger.Fatal("Error loading jwt keys", zap.Error(err))
+		return
+	}
 
 	tokenStorage := adapters.NewTokenStorage(redisClient)
 	tokenProvider := auth.NewProvider(tokenStorage, &auth.ProviderConfig{
 		AccessTokenExpTime:  cfg.JWT.AccessTokenExpTime,
 		RefreshTokenExpTime: cfg.JWT.RefreshTokenExpTime,
 		SecretKey:           cfg.JWT.SecretKey,
+		Keys:                jwtKeys,
+	})
+	hashProvider, err := config.NewHashPasswordProvider(&config.HashConfig{
+		Algorithm:           cfg.Hash.Algorithm,
+		Argon2idMemory:      cfg.Hash.Argon2id.Memory,
+		Argon2idIterations:  cfg.Hash.Argon2id.Iterations,
+		Argon2idParallelism: cfg.Hash.Argon2id.Parallelism,
+		BcryptCost:          cfg.Hash.BcryptCost,
 	})
-	hashProvider := hash.NewHashPasswordProvider()
+	if err != nil {
+		logger.Fatal("Error creating hash provider", zap.Error(err))
+		return
+	}
+	mailer, err := config.NewMailer(&config.MailConfig{
+		Driver:       cfg.Mail.Driver,
+		From:         cfg.Mail.From,
+		Dir:          cfg.Mail.Dir,
+		SMTPHost:     cfg.Mail.SMTP.Host,
+		SMTPPort:     cfg.Mail.SMTP.Port,
+		SMTPUsername: cfg.Mail.SMTP.Username,
+		SMTPPassword: cfg.Mail.SMTP.Password,
+	})
+	if err != nil {
+		logger.Fatal("Error creating mailer", zap.Error(err))
+		return
+	}
+	paymentGateway, err := config.NewPaymentGateway(&config.PaymentConfig{
+		Driver:        cfg.Payment.Driver,
+		WebhookSecret: cfg.Payment.WebhookSecret,
+		CheckoutURL:   cfg.Payment.CheckoutURL,
+	})
+	if err != nil {
+		logger.Fatal("Error creating payment gateway", zap.Error(err))
+		return
+	}
+	oneTimeTokenProvider := auth.NewOneTimeTokenProvider(tokenStorage, cfg.Account.TokenSecret)
+	totpProvider := totp.NewProvider(cfg.Account.TOTPIssuer)
+	oidcProviders := make(map[string]ports.IOIDCProvider, len(cfg.OIDC.Providers))
+	for _, provider := range cfg.OIDC.Providers {
+		oidcProviders[provider.Name] = oidc.NewProvider(oidc.ProviderConfig{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		})
+	}
 	trackStorage := miniostorage.NewTrackStorage(minioClient, cfg.Minio.TrackBucketName)
 	albumImageStorage := miniostorage.NewAlbumImageStorage(minioClient, cfg.Minio.AlbumImageBucketName)
 	musicianImageStorage := miniostorage.NewMusicianImageStorage(minioClient, cfg.Minio.MusicianImageBucketName)
+	userAvatarStorage := miniostorage.NewUserAvatarStorage(minioClient, cfg.Minio.UserAvatarBucketName)
+	exportStorage := miniostorage.NewDataExportStorage(minioClient, minioSigner, cfg.Minio.ExportBucketName)
 
-	authService := service.NewAuthorizationService(userRepo, musicianRepo, tokenProvider, hashProvider, logger)
-	userService := service.NewUserService(userRepo, hashProvider, logger)
+	authService := service.NewAuthorizationService(accountRepo, userRepo, twoFactorRepo, tokenProvider,
+		oneTimeTokenProvider, totpProvider, hashProvider, logger)
+	accountService := service.NewAccountService(accountRepo, twoFactorRepo, oneTimeTokenProvider, tokenProvider,
+		mailer, hashProvider, totpProvider, service.AccountServiceConfig{
+			VerifyEmailURL:        cfg.Account.VerifyEmailURL,
+			ResetPasswordURL:      cfg.Account.ResetPasswordURL,
+			VerifyEmailTokenTTL:   time.Duration(cfg.Account.VerifyEmailTokenTTL) * time.Minute,
+			ResetPasswordTokenTTL: time.Duration(cfg.Account.ResetPasswordTokenTTL) * time.Minute,
+		}, logger)
+	oidcService := service.NewOIDCService(oidcProviders, oidc.NewRedisStateStorage(redisClient), identityRepo,
+		accountRepo, userRepo, tokenProvider, logger)
+	userService := service.NewUserService(userRepo, userAvatarStorage, exportRepo, exportStorage, tokenProvider,
+		hashProvider, service.UserServiceConfig{
+			DeletionGracePeriod: time.Duration(cfg.Account.DeletionGracePeriod) * 24 * time.Hour,
+		}, logger)
 	musicianService := service.NewMusicianService(musicianRepo, musicianImageStorage, hashProvider, logger)
-	albumService := service.NewAlbumService(albumRepo, albumImageStorage, logger)
-	commentService := service.NewCommentService(commentRepo, logger)
+	followService := service.NewFollowService(followRepo, logger)
+	creditService := service.NewCreditService(creditRepo, logger)
 	genreService := service.NewGenreService(genreRepo, logger)
 	trackService := service.NewTrackService(trackRepo, trackStorage, genreService, logger)
+	commentService := service.NewCommentService(commentRepo, trackService, service.CommentServiceConfig{
+		BannedWords: cfg.Comment.BannedWords,
+	}, logger)
+	albumService := service.NewAlbumService(albumRepo, albumImageStorage, trackService, followService, logger)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, logger)
+	paymentService := service.NewPaymentService(paymentGateway, orderRepo, subscriptionService, logger)
+	statService := service.NewStatService(statRepo, genreService, musicianService, logger)
+	royaltyService := service.NewRoyaltyService(royaltyRepo, service.RoyaltyServiceConfig{
+		MinPlaySeconds:     cfg.Royalty.MinPlaySeconds,
+		ArtistSharePercent: cfg.Royalty.ArtistSharePercent,
+	}, logger)
+	exportService := service.NewDataExportService(exportRepo, exportStorage, userService, trackService,
+		commentService, statService, followService, service.DataExportServiceConfig{
+			Retention: time.Duration(cfg.Export.RetentionDays) * 24 * time.Hour,
+			LinkTTL:   time.Duration(cfg.Export.LinkTTL) * time.Second,
+		}, logger)
+	rateLimitService := service.NewRateLimitService(ratelimit.NewRedisStorage(redisClient), logger)
+
+	releaseScheduler := service.NewReleaseScheduler(albumService,
+		time.Duration(cfg.Scheduler.ReleaseInterval)*time.Second, logger)
+	go releaseScheduler.Run(context.Background())
+	expiryScheduler := service.NewSubscriptionExpiryScheduler(subscriptionService,
+		time.Duration(cfg.Scheduler.ExpiryInterval)*time.Second, logger)
+	go expiryScheduler.Run(context.Background())
+	royaltyScheduler := service.NewRoyaltyScheduler(royaltyService,
+		time.Duration(cfg.Scheduler.RoyaltyInterval)*time.Second, logger)
+	go royaltyScheduler.Run(context.Background())
+	purgeScheduler := service.NewUserPurgeScheduler(userService,
+		time.Duration(cfg.Scheduler.PurgeInterval)*time.Second, logger)
+	go purgeScheduler.Run(context.Background())
+	exportScheduler := service.NewDataExportScheduler(exportService,
+		time.Duration(cfg.Scheduler.ExportInterval)*time.Second, logger)
+	go exportScheduler.Run(context.Background())
 
 	handler := api.NewHandler(logger)
 	services := api.Services{
 		AuthService:     authService,
+		AccountService:  accountService,
+		OIDCService:     oidcService,
 		AlbumService:    albumService,
 		MusicianService: musicianService,
 		UserService:     userService,
 		TrackService:    trackService,
 		CommentService:  commentService,
 		GenreService:    genreService,
+		FollowService:   followService,
+		CreditService:   creditService,
+		StatService:     statService,
+
+		SubscriptionService: subscriptionService,
+		PaymentService:      paymentService,
+		RoyaltyService:      royaltyService,
+		DataExportService:   exportService,
+
+		RateLimitService: rateLimitService,
 	}
 	handler.SetServices(&services)
+	handler.SetRateLimits(api.RateLimits{
+		Auth:    cfg.RateLimit.Auth.ToDomain(),
+		Login:   cfg.RateLimit.Login.ToDomain(),
+		Upload:  cfg.RateLimit.Upload.ToDomain(),
+		Comment: cfg.RateLimit.Comment.ToDomain(),
+		Play:    cfg.RateLimit.Play.ToDomain(),
+		Lockout: domain.LockoutPolicy{
+			Threshold:     cfg.RateLimit.Lockout.Threshold,
+			BaseDuration:  time.Duration(cfg.RateLimit.Lockout.BaseDuration) * time.Second,
+			MaxDuration:   time.Duration(cfg.RateLimit.Lockout.MaxDuration) * time.Second,
+			FailureWindow: time.Duration(cfg.RateLimit.Lockout.FailureWindow) * time.Second,
+		},
+	})
 	handler.ConfigureHandlers()
 
 	server := http.Server{
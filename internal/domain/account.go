@@ -0,0 +1,37 @@
+package domain
+
+import "github.com/google/uuid"
+
+const (
+	AccountKindUser     = "user"
+	AccountKindMusician = "musician"
+)
+
+// Account holds the login credentials shared by both kinds of profiles. The
+// account ID is also the ID of the user or musician profile attached to it.
+type Account struct {
+	ID       uuid.UUID
+	Kind     string
+	Name     string
+	Email    string
+	Password string
+	Salt     string
+	// EmailVerified is reset as soon as the email changes.
+	EmailVerified bool
+}
+
+type OneTimeTokenPurpose string
+
+const (
+	OneTimeTokenVerifyEmail   OneTimeTokenPurpose = "verify_email"
+	OneTimeTokenResetPassword OneTimeTokenPurpose = "reset_password"
+	OneTimeTokenTwoFactor     OneTimeTokenPurpose = "two_factor"
+)
+
+// OneTimeToken is bound to the email it was sent to, so it stops working once
+// the account email changes.
+type OneTimeToken struct {
+	Purpose   OneTimeTokenPurpose
+	AccountID uuid.UUID
+	Email     string
+}
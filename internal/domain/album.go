@@ -6,10 +6,13 @@ import (
 )
 
 type Album struct {
-	ID          uuid.UUID
-	Name        string
-	Description string
-	Published   bool
-	ReleaseDate null.Time
-	ImageURL    null.String
+	ID               uuid.UUID
+	Name             string
+	Description      string
+	Published        bool
+	ReleaseDate      null.Time
+	ImageURL         null.String
+	ScheduledRelease null.Time
+	UnpublishReason  null.String
+	Rating           Rating
 }
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type TokenPair struct {
 	AccessToken  string
@@ -8,6 +12,38 @@ type TokenPair struct {
 }
 
 type Payload struct {
-	UserID uuid.UUID
-	Role   int
+	UserID    uuid.UUID
+	Role      int
+	SessionID uuid.UUID
+}
+
+type SessionMetadata struct {
+	DeviceLabel string
+	UserAgent   string
+	IP          string
+}
+
+type Session struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Role       int
+	Metadata   SessionMetadata
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	// RefreshTokenID is the ID of the refresh token currently issued for the
+	// session, it's revoked together with the session.
+	RefreshTokenID string
+}
+
+// JSONWebKey is a public verification key in the RFC 7517 format. RSA keys
+// fill Modulus and Exponent, Ed25519 keys fill Curve and X.
+type JSONWebKey struct {
+	KeyType   string
+	KeyID     string
+	Use       string
+	Algorithm string
+	Modulus   string
+	Exponent  string
+	Curve     string
+	X         string
 }
@@ -1,11 +1,85 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+)
+
+// Comment is either a review, the one rated comment a user leaves on a track,
+// or a reply to a review. Replies have a ParentID and no Stars.
 type Comment struct {
-	ID      uuid.UUID
-	UserID  uuid.UUID
-	TrackID uuid.UUID
-	Stars   int
-	Text    string
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	TrackID        uuid.UUID
+	ParentID       uuid.UUID
+	Stars          int
+	Text           string
+	CreatedAt      time.Time
+	EditedAt       null.Time
+	PinnedAt       null.Time
+	HiddenAt       null.Time
+	HiddenBy       CommentHider
+	HelpfulVotes   int
+	UnhelpfulVotes int
+	Replies        []Comment
+}
+
+func (c Comment) IsReply() bool {
+	return c.ParentID != uuid.Nil
+}
+
+func (c Comment) IsHidden() bool {
+	return c.HiddenAt.Valid
+}
+
+// CommentHider tells who hid a comment. Musicians hide comments on their own
+// tracks, moderators anywhere, and only moderators restore what they hid.
+type CommentHider string
+
+const (
+	CommentHiddenByModerator CommentHider = "moderator"
+	CommentHiddenByMusician  CommentHider = "musician"
+)
+
+// CommentVote is whether a user found a comment helpful. A user has one vote
+// per comment.
+type CommentVote struct {
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+	Helpful   bool
+	CreatedAt time.Time
+}
+
+type CommentReport struct {
+	ID         uuid.UUID
+	CommentID  uuid.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	CreatedAt  time.Time
+	ResolvedAt null.Time
+}
+
+// ReportedComment is an entry of the moderation queue: a comment with the
+// reports nobody has acted on yet.
+type ReportedComment struct {
+	Comment         Comment
+	Reports         int
+	LastReason      string
+	FirstReportedAt time.Time
+}
+
+type CommentSort string
+
+const (
+	CommentSortNewest  CommentSort = "newest"
+	CommentSortStars   CommentSort = "stars"
+	CommentSortHelpful CommentSort = "helpful"
+)
+
+type CommentPage struct {
+	Sort   CommentSort
+	Limit  int
+	Offset int
 }
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+type AlbumContributor struct {
+	AlbumID  uuid.UUID
+	Musician Musician
+	Owner    bool
+	Accepted bool
+}
+
+type CreditRole string
+
+const (
+	CreditRolePrimary  CreditRole = "primary"
+	CreditRoleFeatured CreditRole = "featured"
+	CreditRoleProducer CreditRole = "producer"
+	CreditRoleComposer CreditRole = "composer"
+)
+
+func (r CreditRole) Valid() bool {
+	switch r {
+	case CreditRolePrimary, CreditRoleFeatured, CreditRoleProducer, CreditRoleComposer:
+		return true
+	}
+	return false
+}
+
+type TrackCredit struct {
+	TrackID  uuid.UUID
+	Musician Musician
+	Role     CreditRole
+}
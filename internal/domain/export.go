@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportRunning DataExportStatus = "running"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is a user's request for a copy of their personal data. The
+// archive is built in the background and can be downloaded until ExpiresAt.
+type DataExport struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Status      DataExportStatus
+	CreatedAt   time.Time
+	StartedAt   null.Time
+	CompletedAt null.Time
+	ExpiresAt   null.Time
+}
+
+func (e DataExport) IsExpired(now time.Time) bool {
+	return e.ExpiresAt.Valid && !now.Before(e.ExpiresAt.Time)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedItem struct {
+	UserID      uuid.UUID
+	MusicianID  uuid.UUID
+	Album       Album
+	PublishedAt time.Time
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
 )
@@ -14,4 +16,65 @@ type Musician struct {
 	Country     string
 	Description string
 	ImageURL    null.String
+	VerifiedAt  null.Time
+}
+
+func (m Musician) IsVerified() bool {
+	return m.VerifiedAt.Valid
+}
+
+type MusicianLinkKind string
+
+const (
+	MusicianLinkWebsite    MusicianLinkKind = "website"
+	MusicianLinkInstagram  MusicianLinkKind = "instagram"
+	MusicianLinkTwitter    MusicianLinkKind = "twitter"
+	MusicianLinkFacebook   MusicianLinkKind = "facebook"
+	MusicianLinkYouTube    MusicianLinkKind = "youtube"
+	MusicianLinkSoundCloud MusicianLinkKind = "soundcloud"
+	MusicianLinkBandcamp   MusicianLinkKind = "bandcamp"
+	MusicianLinkSpotify    MusicianLinkKind = "spotify"
+	MusicianLinkVK         MusicianLinkKind = "vk"
+	MusicianLinkTelegram   MusicianLinkKind = "telegram"
+)
+
+// musicianLinkHosts lists the domains a social link may point to. A website
+// may point anywhere, so it has no entry.
+var musicianLinkHosts = map[MusicianLinkKind][]string{
+	MusicianLinkInstagram:  {"instagram.com"},
+	MusicianLinkTwitter:    {"twitter.com", "x.com"},
+	MusicianLinkFacebook:   {"facebook.com"},
+	MusicianLinkYouTube:    {"youtube.com", "youtu.be"},
+	MusicianLinkSoundCloud: {"soundcloud.com"},
+	MusicianLinkBandcamp:   {"bandcamp.com"},
+	MusicianLinkSpotify:    {"spotify.com"},
+	MusicianLinkVK:         {"vk.com"},
+	MusicianLinkTelegram:   {"t.me", "telegram.me"},
+}
+
+func (k MusicianLinkKind) IsKnown() bool {
+	_, ok := musicianLinkHosts[k]
+	return ok || k == MusicianLinkWebsite
+}
+
+// AllowsHost reports whether a link of this kind may point to the host. The
+// host matches a listed domain or any of its subdomains.
+func (k MusicianLinkKind) AllowsHost(host string) bool {
+	if k == MusicianLinkWebsite {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	for _, allowed := range musicianLinkHosts[k] {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type MusicianLink struct {
+	Kind MusicianLinkKind
+	URL  string
 }
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExternalIdentity links the subject of an OpenID Connect provider to a local
+// account, so the same person always ends up in the same account.
+type ExternalIdentity struct {
+	Provider  string
+	Subject   string
+	AccountID uuid.UUID
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCClaims are the verified claims of an ID token.
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCLoginState is kept between the redirect to the provider and the
+// callback. A non-nil AccountID links the identity to that account instead
+// of logging in.
+type OIDCLoginState struct {
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	AccountID    uuid.UUID
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+)
+
+type OrderStatus string
+
+const (
+	OrderPending  OrderStatus = "pending"
+	OrderPaid     OrderStatus = "paid"
+	OrderFailed   OrderStatus = "failed"
+	OrderRefunded OrderStatus = "refunded"
+)
+
+// Order is a purchase of one period of a subscription plan. IdempotencyKey
+// comes from the client, so a retried checkout returns the same order.
+type Order struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	PlanID         uuid.UUID
+	Amount         int64
+	Status         OrderStatus
+	IdempotencyKey string
+	IntentID       string
+	CheckoutURL    string
+	CreatedAt      time.Time
+	// PaidAt is when the order last became paid, revenue is counted then.
+	PaidAt null.Time
+}
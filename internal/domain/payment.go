@@ -8,3 +8,42 @@ type PaymentPayload struct {
 	UserID     uuid.UUID
 	PaymentSum int64
 }
+
+type PaymentIntentStatus string
+
+const (
+	PaymentIntentPending   PaymentIntentStatus = "pending"
+	PaymentIntentSucceeded PaymentIntentStatus = "succeeded"
+	PaymentIntentFailed    PaymentIntentStatus = "failed"
+	PaymentIntentRefunded  PaymentIntentStatus = "refunded"
+)
+
+// PaymentIntent is a payment as the gateway sees it. The user pays on the
+// CheckoutURL page and the outcome arrives later as a PaymentEvent.
+type PaymentIntent struct {
+	ID          string
+	UserID      uuid.UUID
+	Amount      int64
+	Status      PaymentIntentStatus
+	CheckoutURL string
+}
+
+type PaymentEventType string
+
+const (
+	PaymentEventSucceeded PaymentEventType = "payment.succeeded"
+	PaymentEventFailed    PaymentEventType = "payment.failed"
+	PaymentEventRefunded  PaymentEventType = "payment.refunded"
+)
+
+type PaymentEvent struct {
+	ID       string
+	Type     PaymentEventType
+	IntentID string
+}
+
+type PaymentRefund struct {
+	ID       string
+	IntentID string
+	Amount   int64
+}
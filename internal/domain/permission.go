@@ -0,0 +1,39 @@
+package domain
+
+// Permission names follow the resource:action[:scope] pattern, where the any
+// scope allows the action on resources owned by other accounts.
+type Permission string
+
+const (
+	PermissionTrackDeleteAny   Permission = "track:delete:any"
+	PermissionCommentModerate  Permission = "comment:moderate"
+	PermissionUserRoleManage   Permission = "user:role:manage"
+	PermissionSessionRevokeAny Permission = "session:revoke:any"
+	PermissionPaymentRefund    Permission = "payment:refund"
+	PermissionMusicianVerify   Permission = "musician:verify"
+)
+
+var moderatorPermissions = []Permission{
+	PermissionTrackDeleteAny,
+	PermissionCommentModerate,
+}
+
+var rolePermissions = map[int][]Permission{
+	ModeratorRole: moderatorPermissions,
+	AdminRole: append([]Permission{
+		PermissionUserRoleManage,
+		PermissionSessionRevokeAny,
+		PermissionPaymentRefund,
+		PermissionMusicianVerify,
+	}, moderatorPermissions...),
+}
+
+func HasPermission(role int, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// RateLimit allows Requests hits within any Window long period. A zero
+// limit disables limiting.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// LockoutPolicy locks a key after Threshold failures in a row. Failures are
+// forgotten after FailureWindow without a new one.
+type LockoutPolicy struct {
+	Threshold     int
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+	FailureWindow time.Duration
+}
+
+func (p LockoutPolicy) Enabled() bool {
+	return p.Threshold > 0 && p.BaseDuration > 0
+}
+
+// Duration is the lockout after the given number of failures: none below the
+// threshold, then BaseDuration doubled with every further failure up to
+// MaxDuration.
+func (p LockoutPolicy) Duration(failures int) time.Duration {
+	if !p.Enabled() || failures < p.Threshold {
+		return 0
+	}
+
+	duration := p.BaseDuration
+	for i := p.Threshold; i < failures; i++ {
+		duration *= 2
+		if p.MaxDuration > 0 && duration >= p.MaxDuration {
+			return p.MaxDuration
+		}
+	}
+
+	if p.MaxDuration > 0 && duration > p.MaxDuration {
+		return p.MaxDuration
+	}
+
+	return duration
+}
@@ -0,0 +1,32 @@
+package domain
+
+// Rating summarizes the stars of the reviews on a track or album.
+// Histogram[i] is the number of reviews with i+1 stars.
+type Rating struct {
+	Count     int
+	Histogram [5]int
+}
+
+// Mean returns the average number of stars, zero when there are no reviews.
+func (r Rating) Mean() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+
+	total := 0
+	for i, count := range r.Histogram {
+		total += (i + 1) * count
+	}
+
+	return float64(total) / float64(r.Count)
+}
+
+// TrackSort is the order of a track list. TrackSortRating puts the tracks
+// with the highest mean stars first, then the ones with more reviews, so
+// unrated tracks come last.
+type TrackSort string
+
+const (
+	TrackSortDefault TrackSort = ""
+	TrackSortRating  TrackSort = "rating"
+)
@@ -3,4 +3,28 @@ package domain
 const (
 	UserRole = iota
 	MusicianRole
+	ModeratorRole
+	AdminRole
 )
+
+// IsUserAccountRole reports whether the role belongs to the users table.
+// Moderators and admins are listeners with extra permissions, so they keep
+// access to everything a plain user can do.
+func IsUserAccountRole(role int) bool {
+	return role == UserRole || role == ModeratorRole || role == AdminRole
+}
+
+func RoleName(role int) string {
+	switch role {
+	case UserRole:
+		return "User"
+	case MusicianRole:
+		return "Musician"
+	case ModeratorRole:
+		return "Moderator"
+	case AdminRole:
+		return "Admin"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoyaltyPeriod is a calendar month in UTC whose subscription revenue was
+// allocated to musicians. Pool is the musicians' share of Revenue and Plays
+// the number of qualifying plays it was split by.
+type RoyaltyPeriod struct {
+	Start       time.Time
+	End         time.Time
+	Revenue     int64
+	Pool        int64
+	Plays       int64
+	AllocatedAt time.Time
+}
+
+// RoyaltyPeriodOf returns the month containing t, without allocation totals.
+func RoyaltyPeriodOf(t time.Time) RoyaltyPeriod {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return RoyaltyPeriod{
+		Start: start,
+		End:   start.AddDate(0, 1, 0),
+	}
+}
+
+type RoyaltyEntry struct {
+	ID          uuid.UUID
+	MusicianID  uuid.UUID
+	PeriodStart time.Time
+	Plays       int64
+	Amount      int64
+	CreatedAt   time.Time
+}
+
+// TrackPlays counts qualifying plays of a track in a period. MusicianIDs are
+// the accepted contributors of its album, who share its royalties equally.
+type TrackPlays struct {
+	TrackID     uuid.UUID
+	MusicianIDs []uuid.UUID
+	Plays       int64
+}
+
+// RoyaltyStatement is the monthly statement of a musician.
+type RoyaltyStatement struct {
+	MusicianID uuid.UUID
+	Period     RoyaltyPeriod
+	Plays      int64
+	Amount     int64
+}
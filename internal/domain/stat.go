@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserMusiciansStat struct {
 	MusicianID  uuid.UUID
@@ -32,3 +36,9 @@ type ListenReport struct {
 	ListenedGenres        []GenreStat
 	ListenCount           int64
 }
+
+type ListenRecord struct {
+	TrackID         uuid.UUID
+	ListenedAt      time.Time
+	ListenedSeconds int
+}
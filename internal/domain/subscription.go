@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive SubscriptionStatus = "active"
+	// SubscriptionCanceled subscriptions keep their benefits until they
+	// expire.
+	SubscriptionCanceled SubscriptionStatus = "canceled"
+	SubscriptionExpired  SubscriptionStatus = "expired"
+)
+
+type SubscriptionPlan struct {
+	ID   uuid.UUID
+	Name string
+	// Price is in minor currency units, like PaymentPayload.PaymentSum.
+	Price        int64
+	DurationDays int
+}
+
+// Extend returns the end of a period of the plan that starts at from.
+func (p SubscriptionPlan) Extend(from time.Time) time.Time {
+	return from.AddDate(0, 0, p.DurationDays)
+}
+
+type Subscription struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// PlanID is uuid.Nil for subscriptions created before plans existed.
+	PlanID         uuid.UUID
+	StartDate      time.Time
+	ExpirationDate time.Time
+	Status         SubscriptionStatus
+}
+
+// IsActive does not rely on the status alone, since expired subscriptions
+// are only marked so periodically.
+func (s Subscription) IsActive(now time.Time) bool {
+	return s.Status != SubscriptionExpired && !now.Before(s.StartDate) && now.Before(s.ExpirationDate)
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingMean(t *testing.T) {
+	t.Run("test zero votes", func(t *testing.T) {
+		require.Equal(t, 0.0, domain.Rating{}.Mean())
+	})
+
+	t.Run("test weighted by stars", func(t *testing.T) {
+		rating := domain.Rating{Count: 4, Histogram: [5]int{1, 0, 0, 1, 2}}
+		require.InDelta(t, 15.0/4, rating.Mean(), 1e-9)
+	})
+
+	t.Run("test tie does not depend on count", func(t *testing.T) {
+		few := domain.Rating{Count: 2, Histogram: [5]int{0, 0, 1, 0, 1}}
+		many := domain.Rating{Count: 4, Histogram: [5]int{0, 0, 2, 0, 2}}
+		require.Equal(t, few.Mean(), many.Mean())
+	})
+}
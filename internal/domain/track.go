@@ -5,8 +5,20 @@ import (
 )
 
 type Track struct {
-	ID       uuid.UUID
-	AlbumID  uuid.UUID
-	Name     string
-	URL      string
+	ID          uuid.UUID
+	AlbumID     uuid.UUID
+	Name        string
+	URL         string
+	DiscNumber  int
+	TrackNumber int
+	// DurationSeconds is the length of the audio, plays are never counted
+	// longer than that. Zero for tracks uploaded before it was recorded.
+	DurationSeconds int
+	Rating          Rating
+}
+
+type TrackPosition struct {
+	TrackID     uuid.UUID
+	DiscNumber  int
+	TrackNumber int
 }
@@ -0,0 +1,32 @@
+package domain
+
+import "github.com/google/uuid"
+
+// TwoFactor is the TOTP setup of an account. It is stored on enrollment and
+// only takes effect once confirmed with a valid code. LastUsedStep keeps a
+// code from being accepted twice.
+type TwoFactor struct {
+	AccountID    uuid.UUID
+	Secret       string
+	Enabled      bool
+	LastUsedStep int64
+}
+
+// TwoFactorEnrollment is shown once: only hashes of the recovery codes are
+// stored.
+type TwoFactorEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+// LoginResult holds either the tokens or, when the account has two-factor
+// authentication enabled, the challenge token the login is completed with.
+type LoginResult struct {
+	Tokens         TokenPair
+	ChallengeToken string
+}
+
+func (r LoginResult) ChallengeRequired() bool {
+	return r.ChallengeToken != ""
+}
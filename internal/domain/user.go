@@ -1,13 +1,28 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+)
 
 type User struct {
-	ID       uuid.UUID
-	Name     string
-	Email    string
-	Phone    string
-	Password string
-	Salt     string
-	Country  string
+	ID                  uuid.UUID
+	Name                string
+	Email               string
+	Phone               string
+	Password            string
+	Salt                string
+	Country             string
+	Role                int
+	AvatarURL           null.String
+	DeletionScheduledAt null.Time
+}
+
+// UserProfileChanges lists the profile fields to change, the unset ones are
+// left as they are.
+type UserProfileChanges struct {
+	Name    null.String
+	Email   null.String
+	Phone   null.String
+	Country null.String
 }
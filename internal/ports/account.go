@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrAccountInvalidName   = errors.New("account name can't contain '@'")
+	ErrAccountUpdate        = errors.New("failed to update account")
+	ErrAccountNoEmail       = errors.New("account has no email")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrInternalAccountRepo  = errors.New("account repository internal error")
+)
+
+type IAccountRepository interface {
+	GetByID(ctx context.Context, accountID uuid.UUID) (domain.Account, error)
+	GetByName(ctx context.Context, name string) (domain.Account, error)
+	GetByEmail(ctx context.Context, email string) (domain.Account, error)
+	UpdatePassword(ctx context.Context, accountID uuid.UUID, password domain.SaltedPassword) error
+	MarkEmailVerified(ctx context.Context, accountID uuid.UUID, email string) error
+}
+
+type ChangePasswordRequest struct {
+	AccountID   uuid.UUID
+	SessionID   uuid.UUID
+	OldPassword string
+	NewPassword string
+}
+
+type IAccountService interface {
+	SendEmailVerification(ctx context.Context, accountID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, req ChangePasswordRequest) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	BeginTwoFactor(ctx context.Context, accountID uuid.UUID) (domain.TwoFactorEnrollment, error)
+	EnableTwoFactor(ctx context.Context, accountID uuid.UUID, code string) error
+	DisableTwoFactor(ctx context.Context, accountID uuid.UUID, password string) error
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"io"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
@@ -16,6 +18,10 @@ var (
 	ErrAlbumPublish      = errors.New("can't publish album with such id")
 	ErrInternalAlbumRepo = errors.New("album repository internal error")
 	ErrAlbumUpdate       = errors.New("failed to update album")
+	ErrAlbumPublished    = errors.New("album is already published")
+	ErrAlbumSchedule     = errors.New("album release must be scheduled in the future")
+	ErrAlbumNotPublished = errors.New("album is not published")
+	ErrAlbumDelete       = errors.New("can't delete album with such id")
 )
 
 type IAlbumRepository interface {
@@ -26,6 +32,10 @@ type IAlbumRepository interface {
 	GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Album, error)
 	Publish(ctx context.Context, id uuid.UUID) error
+	Schedule(ctx context.Context, id uuid.UUID, releaseAt null.Time) error
+	PublishScheduled(ctx context.Context, now time.Time) ([]domain.Album, error)
+	Unpublish(ctx context.Context, id uuid.UUID, reason string) error
+	Delete(ctx context.Context, id uuid.UUID) (domain.Album, error)
 }
 
 type CreateAlbumServiceReq struct {
@@ -34,8 +44,16 @@ type CreateAlbumServiceReq struct {
 	Description string
 }
 
+type UpdateAlbumServiceReq struct {
+	AlbumID     uuid.UUID
+	MusicianID  uuid.UUID
+	Name        null.String
+	Description null.String
+}
+
 type IAlbumImageStorage interface {
 	UploadImage(ctx context.Context, image io.Reader, id string) (url.URL, error)
+	DeleteImage(ctx context.Context, id string) error
 }
 
 type IAlbumService interface {
@@ -46,4 +64,10 @@ type IAlbumService interface {
 	GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Album, error)
 	Publish(ctx context.Context, albumID uuid.UUID) error
+	Schedule(ctx context.Context, albumID uuid.UUID, releaseAt time.Time) error
+	CancelSchedule(ctx context.Context, albumID uuid.UUID) error
+	PublishScheduled(ctx context.Context) ([]domain.Album, error)
+	Unpublish(ctx context.Context, albumID uuid.UUID, reason string) error
+	Update(ctx context.Context, albumInfo UpdateAlbumServiceReq) (domain.Album, error)
+	Delete(ctx context.Context, albumID uuid.UUID) (domain.Album, error)
 }
@@ -3,6 +3,9 @@ package ports
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
@@ -12,18 +15,45 @@ var (
 	ErrTokenProviderParsingToken = errors.New("can't parse token")
 	ErrTokenProviderSignToken    = errors.New("can't sign token")
 	ErrInternalTokenProvider     = errors.New("internal provider error ")
+	ErrTokenProviderRevokedToken = errors.New("token revoked")
+	ErrTokenProviderReusedToken  = errors.New("refresh token reused")
+	ErrSessionNotFound           = errors.New("session with such id not found")
 )
 
 type ITokenProvider interface {
-	NewSession(ctx context.Context, payload domain.Payload) (domain.TokenPair, error)
-	CloseSession(ctx context.Context, refreshTokenString string) error
+	NewSession(ctx context.Context, payload domain.Payload, metadata domain.SessionMetadata) (domain.TokenPair, error)
+	CloseSession(ctx context.Context, tokenString string) error
 	RefreshSession(ctx context.Context, refreshTokenString string) (domain.TokenPair, error)
 	VerifyToken(ctx context.Context, accessTokenString string) (domain.Payload, error)
+	GetSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	PublicKeys() []domain.JSONWebKey
+}
+
+var (
+	ErrOneTimeTokenInvalid          = errors.New("one-time token is invalid or already used")
+	ErrInternalOneTimeTokenProvider = errors.New("internal one-time token provider error")
+)
+
+// IOneTimeTokenProvider issues tokens for links sent by email. A token can be
+// consumed once and only for the purpose it was issued for.
+type IOneTimeTokenProvider interface {
+	Issue(ctx context.Context, token domain.OneTimeToken, ttl time.Duration) (string, error)
+	Consume(ctx context.Context, purpose domain.OneTimeTokenPurpose, tokenString string) (domain.OneTimeToken, error)
 }
 
 type LogInCredentials struct {
 	Name     string
 	Password string
+	Metadata domain.SessionMetadata
+}
+
+type TwoFactorCredentials struct {
+	ChallengeToken string
+	// Code is either a TOTP code or a recovery code.
+	Code     string
+	Metadata domain.SessionMetadata
 }
 
 var (
@@ -35,8 +65,13 @@ var (
 )
 
 type IAuthorizationService interface {
-	LogIn(ctx context.Context, cred LogInCredentials) (domain.TokenPair, error)
+	LogIn(ctx context.Context, cred LogInCredentials) (domain.LoginResult, error)
+	LogInTwoFactor(ctx context.Context, cred TwoFactorCredentials) (domain.TokenPair, error)
 	LogOut(ctx context.Context, accessTokenString string) error
 	RefreshToken(ctx context.Context, refreshTokenString string) (domain.TokenPair, error)
 	VerifyToken(ctx context.Context, accessTokenString string) (domain.Payload, error)
+	GetSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	LogOutEverywhere(ctx context.Context, userID uuid.UUID) error
+	GetPublicKeys(ctx context.Context) []domain.JSONWebKey
 }
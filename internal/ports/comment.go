@@ -3,8 +3,10 @@ package ports
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
@@ -14,14 +16,47 @@ var (
 	ErrDeleteComment            = errors.New("can not delete comment")
 	ErrCommentByTrackIDNotFound = errors.New("comment with such track id not found")
 	ErrCommentByUserIDNotFound  = errors.New("comment with such track id not found")
+	ErrCommentStars             = errors.New("review stars must be between 1 and 5, replies have none")
+	ErrCommentNotOwner          = errors.New("comment belongs to another user")
+	ErrCommentProfanity         = errors.New("comment contains banned words")
+	ErrCommentReportOwn         = errors.New("can not report own comment")
+	ErrCommentReported          = errors.New("comment already reported by the user")
+	ErrCommentNotOnOwnTrack     = errors.New("comment is not on a track of the musician")
+	ErrCommentHiddenByModerator = errors.New("comment was hidden by a moderator")
+	ErrCommentPinReply          = errors.New("only reviews can be pinned")
+	ErrCommentVoteOwn           = errors.New("can not vote for own comment")
+	ErrCommentVoteNotFound      = errors.New("comment vote not found")
 	ErrInternalCommentRepo      = errors.New("comment repository internal error")
 )
 
 type ICommentRepository interface {
 	Create(ctx context.Context, comment domain.Comment) (domain.Comment, error)
+	// Upsert creates the review of the user on the track or overwrites the
+	// existing one, marking it edited.
+	Upsert(ctx context.Context, comment domain.Comment) (domain.Comment, error)
+	Update(ctx context.Context, comment domain.Comment) (domain.Comment, error)
+	GetByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error)
 	Delete(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) (domain.Comment, error)
+	DeleteByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Comment, error)
-	GetByTrackID(ctx context.Context, trackID uuid.UUID) ([]domain.Comment, error)
+	// GetByTrackID returns a page of the reviews on the track and how many
+	// there are in total.
+	GetByTrackID(ctx context.Context, trackID uuid.UUID, page domain.CommentPage) ([]domain.Comment, int, error)
+	GetReplies(ctx context.Context, parentIDs []uuid.UUID) ([]domain.Comment, error)
+	Hide(ctx context.Context, commentID uuid.UUID, hiddenBy domain.CommentHider, hiddenAt time.Time) (domain.Comment, error)
+	Restore(ctx context.Context, commentID uuid.UUID) (domain.Comment, error)
+	// SetPinned pins the comment at pinnedAt, or unpins it when pinnedAt is null.
+	SetPinned(ctx context.Context, commentID uuid.UUID, pinnedAt null.Time) (domain.Comment, error)
+	CreateReport(ctx context.Context, report domain.CommentReport) (domain.CommentReport, error)
+	// ResolveReports closes the open reports of the comment, taking it off
+	// the moderation queue.
+	ResolveReports(ctx context.Context, commentID uuid.UUID, resolvedAt time.Time) error
+	// GetModerationQueue returns a page of the comments with open reports,
+	// most reported first, and how many there are in total.
+	GetModerationQueue(ctx context.Context, limit int, offset int) ([]domain.ReportedComment, int, error)
+	// Vote records the vote of the user, replacing the one they gave before.
+	Vote(ctx context.Context, vote domain.CommentVote) error
+	DeleteVote(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error
 }
 
 type PostCommentServiceReq struct {
@@ -31,9 +66,41 @@ type PostCommentServiceReq struct {
 	Text    string
 }
 
+type ReplyCommentServiceReq struct {
+	UserID   uuid.UUID
+	ParentID uuid.UUID
+	Text     string
+}
+
+type EditCommentServiceReq struct {
+	UserID    uuid.UUID
+	CommentID uuid.UUID
+	Stars     null.Int
+	Text      null.String
+}
+
+type ReportCommentServiceReq struct {
+	UserID    uuid.UUID
+	CommentID uuid.UUID
+	Reason    string
+}
+
 type ICommentService interface {
 	Post(ctx context.Context, comment PostCommentServiceReq) (domain.Comment, error)
+	Reply(ctx context.Context, reply ReplyCommentServiceReq) (domain.Comment, error)
+	Edit(ctx context.Context, edit EditCommentServiceReq) (domain.Comment, error)
 	Delete(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) (domain.Comment, error)
-	GetCommentsOnTrack(ctx context.Context, trackID uuid.UUID) ([]domain.Comment, error)
+	DeleteOwn(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (domain.Comment, error)
+	GetCommentsOnTrack(ctx context.Context, trackID uuid.UUID, page domain.CommentPage) ([]domain.Comment, int, error)
 	GetUserComments(ctx context.Context, userID uuid.UUID) ([]domain.Comment, error)
+	Report(ctx context.Context, report ReportCommentServiceReq) (domain.CommentReport, error)
+	GetModerationQueue(ctx context.Context, page domain.CommentPage) ([]domain.ReportedComment, int, error)
+	// Hide and Restore are moderator actions, both resolve the open reports.
+	Hide(ctx context.Context, commentID uuid.UUID) (domain.Comment, error)
+	Restore(ctx context.Context, commentID uuid.UUID) (domain.Comment, error)
+	HideOnOwnTrack(ctx context.Context, musicianID uuid.UUID, commentID uuid.UUID) (domain.Comment, error)
+	RestoreOnOwnTrack(ctx context.Context, musicianID uuid.UUID, commentID uuid.UUID) (domain.Comment, error)
+	Pin(ctx context.Context, musicianID uuid.UUID, commentID uuid.UUID, pinned bool) (domain.Comment, error)
+	Vote(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, helpful bool) (domain.Comment, error)
+	DeleteVote(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (domain.Comment, error)
 }
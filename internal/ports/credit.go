@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrAlreadyContributor  = errors.New("musician is already an album contributor")
+	ErrInviteNotFound      = errors.New("album invite not found")
+	ErrContributorNotFound = errors.New("album contributor not found")
+	ErrUnknownCreditRole   = errors.New("unknown track credit role")
+	ErrCreditDuplicate     = errors.New("track credit duplicate error")
+	ErrCreditNotFound      = errors.New("track credit not found")
+	ErrInternalCreditRepo  = errors.New("credit repository internal error")
+	ErrSelfInvite          = errors.New("musician can't invite themselves")
+)
+
+type ICreditRepository interface {
+	InviteToAlbum(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	AcceptInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	DeclineInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	RemoveContributor(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	GetInvites(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error)
+	GetAlbumContributors(ctx context.Context, albumID uuid.UUID) ([]domain.AlbumContributor, error)
+	AddTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error
+	RemoveTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error
+	GetTrackCredits(ctx context.Context, trackID uuid.UUID) ([]domain.TrackCredit, error)
+}
+
+type ICreditService interface {
+	InviteToAlbum(ctx context.Context, albumID uuid.UUID, ownerID uuid.UUID, musicianID uuid.UUID) error
+	AcceptInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	DeclineInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	RemoveContributor(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error
+	GetInvites(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error)
+	GetAlbumContributors(ctx context.Context, albumID uuid.UUID) ([]domain.AlbumContributor, error)
+	AddTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error
+	RemoveTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error
+	GetTrackCredits(ctx context.Context, trackID uuid.UUID) ([]domain.TrackCredit, error)
+}
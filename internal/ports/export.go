@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrDataExportIDNotFound   = errors.New("data export with such id not found")
+	ErrDataExportInProgress   = errors.New("data export is already in progress")
+	ErrDataExportNotReady     = errors.New("data export isn't ready yet")
+	ErrDataExportExpired      = errors.New("data export has expired")
+	ErrDataExportLost         = errors.New("data export was claimed by another run")
+	ErrInternalDataExportRepo = errors.New("data export repository internal error")
+)
+
+type IDataExportRepository interface {
+	Create(ctx context.Context, export domain.DataExport) (domain.DataExport, error)
+	// Finish stores the outcome of a running export if the run that claimed it
+	// at export.StartedAt still owns it, ErrDataExportLost is returned otherwise.
+	Finish(ctx context.Context, export domain.DataExport) (domain.DataExport, error)
+	GetByID(ctx context.Context, exportID uuid.UUID) (domain.DataExport, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.DataExport, error)
+	ClaimPending(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]domain.DataExport, error)
+}
+
+type IDataExportStorage interface {
+	PutArchive(ctx context.Context, archive io.Reader, size int64, id string) error
+	SignedURL(ctx context.Context, id string, ttl time.Duration) (url.URL, error)
+	DeleteArchive(ctx context.Context, id string) error
+}
+
+type IDataExportService interface {
+	Request(ctx context.Context, userID uuid.UUID) (domain.DataExport, error)
+	Get(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (domain.DataExport, error)
+	DownloadURL(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (url.URL, error)
+	ProcessPending(ctx context.Context) ([]domain.DataExport, error)
+}
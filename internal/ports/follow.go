@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrAlreadyFollowing   = errors.New("musician is already followed")
+	ErrNotFollowing       = errors.New("musician is not followed")
+	ErrInternalFollowRepo = errors.New("follow repository internal error")
+)
+
+type IFollowRepository interface {
+	Follow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error
+	Unfollow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error
+	GetFollowing(ctx context.Context, userID uuid.UUID) ([]domain.Musician, error)
+	CountFollowers(ctx context.Context, musicianID uuid.UUID) (int64, error)
+	CountFollowersByIDs(ctx context.Context, musicianIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	AddToFeed(ctx context.Context, albumID uuid.UUID, publishedAt time.Time) error
+	GetFeed(ctx context.Context, userID uuid.UUID) ([]domain.FeedItem, error)
+}
+
+type IFollowService interface {
+	Follow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error
+	Unfollow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error
+	GetFollowing(ctx context.Context, userID uuid.UUID) ([]domain.Musician, error)
+	CountFollowers(ctx context.Context, musicianID uuid.UUID) (int64, error)
+	CountFollowersByIDs(ctx context.Context, musicianIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	PublishToFeed(ctx context.Context, album domain.Album) error
+	GetFeed(ctx context.Context, userID uuid.UUID) ([]domain.FeedItem, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrInternalMailer = errors.New("internal mailer error")
+)
+
+type IMailer interface {
+	Send(ctx context.Context, mail domain.Mail) error
+}
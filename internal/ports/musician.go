@@ -7,6 +7,7 @@ import (
 	"net/url"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
@@ -18,6 +19,7 @@ var (
 	ErrMusicianUnknownCountry = errors.New("such country doesn't exists")
 	ErrInternalMusicianRepo   = errors.New("musician repository internal error")
 	ErrMusicianUpdate         = errors.New("failed to update musician")
+	ErrMusicianInvalidLink    = errors.New("invalid musician link")
 )
 
 type IMusicianRepository interface {
@@ -29,6 +31,11 @@ type IMusicianRepository interface {
 	GetByEmail(ctx context.Context, email string) (domain.Musician, error)
 	GetByAlbumID(ctx context.Context, albumID uuid.UUID) (domain.Musician, error)
 	GetByTrackID(ctx context.Context, trackID uuid.UUID) (domain.Musician, error)
+	GetLinks(ctx context.Context, musicianID uuid.UUID) ([]domain.MusicianLink, error)
+	// UpdateProfile writes the name, country, description and verification of
+	// the musician and, unless links is nil, replaces its links atomically.
+	UpdateProfile(ctx context.Context, musician domain.Musician, links []domain.MusicianLink) (domain.Musician, error)
+	SetVerifiedAt(ctx context.Context, musicianID uuid.UUID, verifiedAt null.Time) (domain.Musician, error)
 }
 
 type IMusicianImageStorage interface {
@@ -48,6 +55,15 @@ type MusicianServiceCreateRequest struct {
 	Description string
 }
 
+// MusicianServiceUpdateRequest changes only the fields that are set. Links
+// replace all links of the musician unless nil, an empty slice removes them.
+type MusicianServiceUpdateRequest struct {
+	Name        null.String
+	Country     null.String
+	Description null.String
+	Links       []domain.MusicianLink
+}
+
 type IMusicianService interface {
 	Register(ctx context.Context, musician MusicianServiceCreateRequest) (domain.Musician, error)
 	UploadImage(ctx context.Context, image io.Reader, id uuid.UUID) (domain.Musician, error)
@@ -57,4 +73,7 @@ type IMusicianService interface {
 	GetByEmail(ctx context.Context, email string) (domain.Musician, error)
 	GetByAlbumID(ctx context.Context, albumID uuid.UUID) (domain.Musician, error)
 	GetByTrackID(ctx context.Context, trackID uuid.UUID) (domain.Musician, error)
+	UpdateProfile(ctx context.Context, musicianID uuid.UUID, req MusicianServiceUpdateRequest) (domain.Musician, error)
+	GetLinks(ctx context.Context, musicianID uuid.UUID) ([]domain.MusicianLink, error)
+	SetVerified(ctx context.Context, musicianID uuid.UUID, verified bool) (domain.Musician, error)
 }
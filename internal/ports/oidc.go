@@ -0,0 +1,66 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrOIDCProviderNotFound      = errors.New("unknown identity provider")
+	ErrOIDCInvalidState          = errors.New("invalid or expired login state")
+	ErrOIDCExchange              = errors.New("failed to exchange authorization code")
+	ErrOIDCInvalidIDToken        = errors.New("invalid id token")
+	ErrOIDCIdentityNotLinked     = errors.New("external identity is not linked to an account")
+	ErrOIDCIdentityAlreadyLinked = errors.New("external identity is already linked to an account")
+	ErrOIDCIdentityNotFound      = errors.New("external identity not found")
+	ErrInternalOIDCProvider      = errors.New("internal identity provider error")
+	ErrInternalOIDCStateStorage  = errors.New("internal oidc state storage error")
+	ErrInternalIdentityRepo      = errors.New("internal external identity repository error")
+)
+
+// IOIDCProvider runs the authorization code flow with PKCE against one
+// OpenID Connect provider.
+type IOIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	// Exchange redeems the code and returns the claims of the verified ID
+	// token, whose nonce has to match.
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (domain.OIDCClaims, error)
+}
+
+type IOIDCStateStorage interface {
+	Save(ctx context.Context, state string, loginState domain.OIDCLoginState, ttl time.Duration) error
+	// Take returns ErrOIDCInvalidState for an unknown state, a state can be
+	// taken once.
+	Take(ctx context.Context, state string) (domain.OIDCLoginState, error)
+}
+
+type IExternalIdentityRepository interface {
+	Get(ctx context.Context, provider string, subject string) (domain.ExternalIdentity, error)
+	GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]domain.ExternalIdentity, error)
+	Create(ctx context.Context, identity domain.ExternalIdentity) error
+	Delete(ctx context.Context, accountID uuid.UUID, provider string) error
+}
+
+type BeginOIDCRequest struct {
+	Provider string
+	// LinkAccountID is set when a logged in account links a new identity.
+	LinkAccountID uuid.UUID
+}
+
+type CompleteOIDCRequest struct {
+	Provider string
+	State    string
+	Code     string
+	Metadata domain.SessionMetadata
+}
+
+type IOIDCService interface {
+	Begin(ctx context.Context, req BeginOIDCRequest) (string, error)
+	Complete(ctx context.Context, req CompleteOIDCRequest) (domain.TokenPair, error)
+	GetIdentities(ctx context.Context, accountID uuid.UUID) ([]domain.ExternalIdentity, error)
+	Unlink(ctx context.Context, accountID uuid.UUID, provider string) error
+}
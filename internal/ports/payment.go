@@ -0,0 +1,61 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrPaymentInvalidSignature    = errors.New("invalid payment webhook signature")
+	ErrPaymentIntentNotFound      = errors.New("payment intent not found")
+	ErrPaymentIdempotencyConflict = errors.New("idempotency key was used for another request")
+	ErrPaymentNotRefundable       = errors.New("payment can't be refunded")
+	ErrInternalPaymentGateway     = errors.New("internal payment gateway error")
+)
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrOrderDuplicate      = errors.New("order with such idempotency key already exists")
+	ErrOrderStatusChanged  = errors.New("order status was changed concurrently")
+	ErrOrderNotRefundable  = errors.New("only paid orders can be refunded")
+	ErrIdempotencyKeyEmpty = errors.New("idempotency key is required")
+	ErrInternalOrderRepo   = errors.New("internal order repository error")
+)
+
+// IPaymentGateway is implemented once per payment provider. Requests with an
+// idempotency key that was already used return the original result instead
+// of charging or refunding twice.
+type IPaymentGateway interface {
+	CreateIntent(ctx context.Context, payload domain.PaymentPayload, idempotencyKey string) (domain.PaymentIntent, error)
+	Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) (domain.PaymentRefund, error)
+	// ParseWebhook verifies the signature of a webhook request body before
+	// decoding the event from it.
+	ParseWebhook(payload []byte, signature string) (domain.PaymentEvent, error)
+}
+
+type IOrderRepository interface {
+	Create(ctx context.Context, order domain.Order) (domain.Order, error)
+	GetByID(ctx context.Context, orderID uuid.UUID) (domain.Order, error)
+	GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, idempotencyKey string) (domain.Order, error)
+	GetByIntentID(ctx context.Context, intentID string) (domain.Order, error)
+	SetIntent(ctx context.Context, orderID uuid.UUID, intent domain.PaymentIntent) error
+	// UpdateStatus changes the status only if it still is from, so every
+	// transition happens once however often a webhook is delivered.
+	UpdateStatus(ctx context.Context, orderID uuid.UUID, from domain.OrderStatus, to domain.OrderStatus) error
+}
+
+type CheckoutRequest struct {
+	UserID         uuid.UUID
+	PlanID         uuid.UUID
+	IdempotencyKey string
+}
+
+type IPaymentService interface {
+	Checkout(ctx context.Context, req CheckoutRequest) (domain.Order, error)
+	GetOrder(ctx context.Context, userID uuid.UUID, orderID uuid.UUID) (domain.Order, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	Refund(ctx context.Context, orderID uuid.UUID) (domain.Order, error)
+}
@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrRateLimited              = errors.New("too many requests")
+	ErrInternalRateLimitStorage = errors.New("internal rate limit storage error")
+)
+
+// RateLimitError is ErrRateLimited together with the time after which the
+// request may be repeated.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryAfterSeconds rounds up, so a client that waits that long is let in.
+func (e *RateLimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+type IRateLimitStorage interface {
+	// Hit records a hit at now in the sliding window of key unless limit hits
+	// are already there. A rejected hit is not recorded and oldest is the time
+	// of the earliest hit still in the window.
+	Hit(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (allowed bool, oldest time.Time, err error)
+	// AddFailure counts a failure and returns the failures in a row.
+	AddFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	// LockTTL returns zero when key is not locked.
+	LockTTL(ctx context.Context, key string) (time.Duration, error)
+	ResetFailures(ctx context.Context, key string) error
+}
+
+type IRateLimitService interface {
+	Allow(ctx context.Context, key string, limit domain.RateLimit) error
+	CheckLockout(ctx context.Context, key string) error
+	RegisterFailure(ctx context.Context, key string, policy domain.LockoutPolicy) error
+	Reset(ctx context.Context, key string) error
+}
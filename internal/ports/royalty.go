@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrRoyaltyPeriodNotFound  = errors.New("royalty period not found")
+	ErrRoyaltyPeriodAllocated = errors.New("royalty period is already allocated")
+	ErrRoyaltyStatementRange  = errors.New("royalty statement range is invalid")
+	ErrInternalRoyaltyRepo    = errors.New("internal royalty repository error")
+)
+
+type IRoyaltyRepository interface {
+	GetLastPeriod(ctx context.Context) (domain.RoyaltyPeriod, error)
+	GetRevenue(ctx context.Context, start time.Time, end time.Time) (int64, error)
+	GetTrackPlays(ctx context.Context, start time.Time, end time.Time, minSeconds int) ([]domain.TrackPlays, error)
+	// CreatePeriod stores the period together with its entries atomically.
+	CreatePeriod(ctx context.Context, period domain.RoyaltyPeriod, entries []domain.RoyaltyEntry) error
+	GetStatements(ctx context.Context, musicianID uuid.UUID, from time.Time, to time.Time) ([]domain.RoyaltyStatement, error)
+}
+
+type IRoyaltyService interface {
+	AllocateDue(ctx context.Context) ([]domain.RoyaltyPeriod, error)
+	GetStatements(ctx context.Context, musicianID uuid.UUID, from time.Time, to time.Time) ([]domain.RoyaltyStatement, error)
+}
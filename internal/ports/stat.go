@@ -12,12 +12,14 @@ var (
 )
 
 type IStatRepository interface {
-	Add(ctx context.Context, recordID uuid.UUID, userID uuid.UUID, trackID uuid.UUID) error
+	Add(ctx context.Context, recordID uuid.UUID, userID uuid.UUID, trackID uuid.UUID, listenedSeconds int) error
 	GetMostListenedMusicians(ctx context.Context, userID uuid.UUID, maxCnt int) ([]domain.UserMusiciansStat, error)
 	GetListenedGenres(ctx context.Context, userID uuid.UUID) ([]domain.UserGenresStat, error)
+	GetHistory(ctx context.Context, userID uuid.UUID) ([]domain.ListenRecord, error)
 }
 
 type IStatService interface {
-	Add(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error
+	Add(ctx context.Context, userID uuid.UUID, trackID uuid.UUID, listenedSeconds int) error
 	FormReport(ctx context.Context, userID uuid.UUID) (domain.ListenReport, error)
+	GetHistory(ctx context.Context, userID uuid.UUID) ([]domain.ListenRecord, error)
 }
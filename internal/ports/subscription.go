@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrSubscriptionPlanNotFound  = errors.New("subscription plan not found")
+	ErrSubscriptionNotFound      = errors.New("subscription not found")
+	ErrSubscriptionAlreadyActive = errors.New("subscription is already active")
+	ErrSubscriptionNotRenewable  = errors.New("subscription has no plan to renew")
+	ErrSubscriptionRequired      = errors.New("active subscription required")
+	ErrInternalSubscriptionRepo  = errors.New("internal subscription repository error")
+)
+
+type ISubscriptionRepository interface {
+	GetPlans(ctx context.Context) ([]domain.SubscriptionPlan, error)
+	GetPlanByID(ctx context.Context, planID uuid.UUID) (domain.SubscriptionPlan, error)
+	// GetCurrent returns the subscription of the user that is not marked
+	// expired yet, there is at most one.
+	GetCurrent(ctx context.Context, userID uuid.UUID) (domain.Subscription, error)
+	Create(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error)
+	Update(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error)
+	ExpireDue(ctx context.Context, now time.Time) (int64, error)
+}
+
+type ISubscriptionService interface {
+	GetPlans(ctx context.Context) ([]domain.SubscriptionPlan, error)
+	GetPlan(ctx context.Context, planID uuid.UUID) (domain.SubscriptionPlan, error)
+	GetCurrent(ctx context.Context, userID uuid.UUID) (domain.Subscription, error)
+	Start(ctx context.Context, userID uuid.UUID, planID uuid.UUID) (domain.Subscription, error)
+	Renew(ctx context.Context, userID uuid.UUID) (domain.Subscription, error)
+	Cancel(ctx context.Context, userID uuid.UUID) (domain.Subscription, error)
+	// Activate applies a paid period of the plan: the current subscription
+	// is extended by it, or a new one is started.
+	Activate(ctx context.Context, userID uuid.UUID, planID uuid.UUID) (domain.Subscription, error)
+	// Revoke takes a refunded period of the plan back.
+	Revoke(ctx context.Context, userID uuid.UUID, planID uuid.UUID) error
+	CheckActive(ctx context.Context, userID uuid.UUID) error
+	ExpireDue(ctx context.Context) (int64, error)
+}
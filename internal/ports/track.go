@@ -7,6 +7,7 @@ import (
 	"net/url"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
@@ -17,20 +18,23 @@ var (
 	ErrTrackDeleteFavorite = errors.New("can't delete favorite track with such id")
 	ErrInternalTrackRepo   = errors.New("internal track repository error")
 	ErrTrackUpdate         = errors.New("failed to update track")
+	ErrTrackPosition       = errors.New("invalid track position")
 )
 
 type ITrackRepository interface {
 	Create(ctx context.Context, track domain.Track) (domain.Track, error)
 	Update(ctx context.Context, track domain.Track) (domain.Track, error)
-	GetAll(ctx context.Context) ([]domain.Track, error)
+	GetAll(ctx context.Context, sort domain.TrackSort) ([]domain.Track, error)
 	GetByID(ctx context.Context, trackID uuid.UUID) (domain.Track, error)
 	Delete(ctx context.Context, trackID uuid.UUID) (domain.Track, error)
 	DeleteFavorite(ctx context.Context, trackID uuid.UUID, userID uuid.UUID) (domain.Track, error)
-	GetUserFavorites(ctx context.Context, userID uuid.UUID) ([]domain.Track, error)
+	GetUserFavorites(ctx context.Context, userID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
 	AddToUserFavorites(ctx context.Context, trackID uuid.UUID, userID uuid.UUID) error
-	GetByAlbumID(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error)
-	GetByMusicianID(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error)
-	GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error)
+	GetByAlbumID(ctx context.Context, albumID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
+	GetByMusicianID(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
+	GetOwn(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
+	GetByAlbumIDInternal(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error)
+	Reorder(ctx context.Context, albumID uuid.UUID, positions []domain.TrackPosition) error
 }
 
 type PutTrackReq struct {
@@ -44,21 +48,41 @@ type ITrackObjectStorage interface {
 }
 
 type CreateTrackReq struct {
-	AlbumID   uuid.UUID
-	Name      string
-	TrackBLOB io.Reader
-	GenresID  []uuid.UUID
+	AlbumID         uuid.UUID
+	Name            string
+	DurationSeconds int
+	TrackBLOB       io.Reader
+	GenresID        []uuid.UUID
+}
+
+type UpdateTrackReq struct {
+	TrackID         uuid.UUID
+	MusicianID      uuid.UUID
+	Name            null.String
+	AlbumID         uuid.NullUUID
+	DurationSeconds null.Int
+	GenresID        []uuid.UUID // nil keeps the current genres
+}
+
+type ReplaceTrackAudioReq struct {
+	TrackID    uuid.UUID
+	MusicianID uuid.UUID
+	TrackBLOB  io.Reader
 }
 
 type ITrackService interface {
 	Create(ctx context.Context, trackInfo CreateTrackReq) (domain.Track, error)
-	GetAll(ctx context.Context) ([]domain.Track, error)
+	GetAll(ctx context.Context, sort domain.TrackSort) ([]domain.Track, error)
 	GetByID(ctx context.Context, trackID uuid.UUID) (domain.Track, error)
 	Delete(ctx context.Context, trackID uuid.UUID) (domain.Track, error)
 	DeleteFavorite(ctx context.Context, trackID uuid.UUID, userID uuid.UUID) (domain.Track, error)
-	GetUserFavorites(ctx context.Context, userID uuid.UUID) ([]domain.Track, error)
+	GetUserFavorites(ctx context.Context, userID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
 	AddToUserFavorites(ctx context.Context, trackID uuid.UUID, userID uuid.UUID) error
-	GetByAlbumID(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error)
-	GetByMusicianID(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error)
-	GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error)
+	GetByAlbumID(ctx context.Context, albumID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
+	GetByMusicianID(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
+	GetOwn(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error)
+	DeleteByAlbumID(ctx context.Context, albumID uuid.UUID) error
+	Reorder(ctx context.Context, albumID uuid.UUID, positions []domain.TrackPosition) error
+	Update(ctx context.Context, trackInfo UpdateTrackReq) (domain.Track, error)
+	ReplaceAudio(ctx context.Context, trackInfo ReplaceTrackAudioReq) (domain.Track, error)
 }
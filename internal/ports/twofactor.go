@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+var (
+	ErrTwoFactorNotFound       = errors.New("two-factor authentication is not set up")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorInvalidCode    = errors.New("invalid two-factor code")
+	ErrInternalTwoFactorRepo   = errors.New("two-factor repository internal error")
+)
+
+type ITwoFactorRepository interface {
+	Get(ctx context.Context, accountID uuid.UUID) (domain.TwoFactor, error)
+	// Save replaces the setup of the account with a disabled one and its
+	// recovery codes.
+	Save(ctx context.Context, twoFactor domain.TwoFactor, recoveryCodeHashes []string) error
+	Enable(ctx context.Context, accountID uuid.UUID, step int64) error
+	Delete(ctx context.Context, accountID uuid.UUID) error
+	// UseStep fails with ErrTwoFactorInvalidCode unless the step is newer than
+	// the last accepted one.
+	UseStep(ctx context.Context, accountID uuid.UUID, step int64) error
+	// UseRecoveryCode fails with ErrTwoFactorInvalidCode if the code is
+	// unknown or already used.
+	UseRecoveryCode(ctx context.Context, accountID uuid.UUID, codeHash string) error
+}
+
+type ITOTPProvider interface {
+	GenerateSecret() (string, error)
+	// URI is the otpauth:// link authenticator apps read from a QR code.
+	URI(secret string, accountName string) string
+	// Validate returns the time step the code belongs to.
+	Validate(secret string, code string, at time.Time) (int64, bool)
+	GenerateRecoveryCodes() ([]string, error)
+	HashRecoveryCode(code string) string
+}
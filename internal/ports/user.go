@@ -3,7 +3,12 @@ package ports
 import (
 	"context"
 	"errors"
+	"io"
+	"net/url"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 )
 
@@ -15,21 +20,37 @@ var (
 	ErrUserPhoneNotFound  = errors.New("user with such email doesn't exists")
 	ErrUserUnknownCountry = errors.New("such country doesn't exists")
 	ErrInternalUserRepo   = errors.New("user repository internal error")
+	ErrUserUpdate         = errors.New("failed to update user")
+	ErrUserRole           = errors.New("role can't be assigned to a user")
 )
 
 type IUserRepository interface {
 	Create(ctx context.Context, user domain.User) (domain.User, error)
+	SetRole(ctx context.Context, userID uuid.UUID, role int) (domain.User, error)
+	SetAvatar(ctx context.Context, userID uuid.UUID, avatarURL string) (domain.User, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) (domain.User, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, changes domain.UserProfileChanges) (domain.User, error)
 	GetAll(ctx context.Context) ([]domain.User, error)
 	GetByID(ctx context.Context, userID uuid.UUID) (domain.User, error)
 	GetByName(ctx context.Context, name string) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	GetByPhone(ctx context.Context, phone string) (domain.User, error)
+	GetScheduledBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	DeleteScheduled(ctx context.Context, userID uuid.UUID, before time.Time) error
+}
+
+type IUserAvatarStorage interface {
+	UploadImage(ctx context.Context, image io.Reader, id string) (url.URL, error)
+	DeleteImage(ctx context.Context, id string) error
 }
 
 var (
 	ErrUserWithSuchNameAlreadyExists  = errors.New("user with such name already exists")
 	ErrUserWithSuchEmailAlreadyExists = errors.New("user with such email already exists")
 	ErrUserWithSuchPhoneAlreadyExists = errors.New("user with such phone already exists")
+	ErrUserDeletionRequested          = errors.New("user account deletion is already requested")
+	ErrUserDeletionNotRequested       = errors.New("user account deletion isn't requested")
 )
 
 type UserServiceCreateRequest struct {
@@ -40,6 +61,13 @@ type UserServiceCreateRequest struct {
 	Country  string
 }
 
+type UserServiceUpdateRequest struct {
+	Name    null.String
+	Email   null.String
+	Phone   null.String
+	Country null.String
+}
+
 type IUserService interface {
 	Register(ctx context.Context, user UserServiceCreateRequest) (domain.User, error)
 	GetAll(ctx context.Context) ([]domain.User, error)
@@ -47,4 +75,10 @@ type IUserService interface {
 	GetByName(ctx context.Context, name string) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	GetByPhone(ctx context.Context, phone string) (domain.User, error)
+	SetRole(ctx context.Context, userID uuid.UUID, role int) (domain.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req UserServiceUpdateRequest) (domain.User, error)
+	UploadAvatar(ctx context.Context, image io.Reader, userID uuid.UUID) (domain.User, error)
+	RequestDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error)
+	PurgeDeleted(ctx context.Context) ([]uuid.UUID, error)
 }
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultVerifyEmailTokenTTL   = 24 * time.Hour
+	defaultResetPasswordTokenTTL = 30 * time.Minute
+)
+
+// AccountServiceConfig holds the frontend pages the mailed links point to.
+// The token is appended to them as the token query parameter.
+type AccountServiceConfig struct {
+	VerifyEmailURL        string
+	ResetPasswordURL      string
+	VerifyEmailTokenTTL   time.Duration
+	ResetPasswordTokenTTL time.Duration
+}
+
+type AccountService struct {
+	repository          ports.IAccountRepository
+	twoFactorRepository ports.ITwoFactorRepository
+	oneTimeTokens       ports.IOneTimeTokenProvider
+	tokenProvider       ports.ITokenProvider
+	mailer              ports.IMailer
+	hash                ports.IHashPasswordProvider
+	totp                ports.ITOTPProvider
+	cfg                 AccountServiceConfig
+	logger              *zap.Logger
+}
+
+func NewAccountService(repo ports.IAccountRepository, twoFactorRepo ports.ITwoFactorRepository,
+	oneTimeTokens ports.IOneTimeTokenProvider, tokenProvider ports.ITokenProvider, mailer ports.IMailer,
+	hash ports.IHashPasswordProvider, totp ports.ITOTPProvider, cfg AccountServiceConfig,
+	logger *zap.Logger) *AccountService {
+	if cfg.VerifyEmailTokenTTL <= 0 {
+		cfg.VerifyEmailTokenTTL = defaultVerifyEmailTokenTTL
+	}
+
+	if cfg.ResetPasswordTokenTTL <= 0 {
+		cfg.ResetPasswordTokenTTL = defaultResetPasswordTokenTTL
+	}
+
+	return &AccountService{
+		repository:          repo,
+		twoFactorRepository: twoFactorRepo,
+		oneTimeTokens:       oneTimeTokens,
+		tokenProvider:       tokenProvider,
+		mailer:              mailer,
+		hash:                hash,
+		totp:                totp,
+		cfg:                 cfg,
+		logger:              logger,
+	}
+}
+
+func tokenLink(page string, token string) string {
+	separator := "?"
+	if strings.Contains(page, "?") {
+		separator = "&"
+	}
+
+	return page + separator + "token=" + url.QueryEscape(token)
+}
+
+func (as *AccountService) SendEmailVerification(ctx context.Context, accountID uuid.UUID) error {
+	account, err := as.repository.GetByID(ctx, accountID)
+	if err != nil {
+		as.logger.Error("Failed to send email verification", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	if account.Email == "" {
+		as.logger.Error("Failed to send email verification", zap.Error(ports.ErrAccountNoEmail),
+			zap.String("Account ID", accountID.String()))
+		return ports.ErrAccountNoEmail
+	}
+
+	if account.EmailVerified {
+		return ports.ErrEmailAlreadyVerified
+	}
+
+	token, err := as.oneTimeTokens.Issue(ctx, domain.OneTimeToken{
+		Purpose:   domain.OneTimeTokenVerifyEmail,
+		AccountID: account.ID,
+		Email:     account.Email,
+	}, as.cfg.VerifyEmailTokenTTL)
+	if err != nil {
+		as.logger.Error("Failed to issue email verification token", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	err = as.mailer.Send(ctx, domain.Mail{
+		To:      account.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf("Hi %s,\n\nconfirm your email by opening the link below:\n\n%s\n\n"+
+			"The link is valid for %s. If you didn't create an account, ignore this mail.\n",
+			account.Name, tokenLink(as.cfg.VerifyEmailURL, token), as.cfg.VerifyEmailTokenTTL),
+	})
+	if err != nil {
+		as.logger.Error("Failed to send email verification", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	as.logger.Info("Email verification successfully sent", zap.String("Account ID", accountID.String()))
+
+	return nil
+}
+
+func (as *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	oneTimeToken, err := as.oneTimeTokens.Consume(ctx, domain.OneTimeTokenVerifyEmail, token)
+	if err != nil {
+		as.logger.Error("Failed to verify email", zap.Error(err))
+		return err
+	}
+
+	account, err := as.repository.GetByID(ctx, oneTimeToken.AccountID)
+	if errors.Is(err, ports.ErrAccountNotFound) {
+		return ports.ErrOneTimeTokenInvalid
+	} else if err != nil {
+		as.logger.Error("Failed to verify email", zap.Error(err),
+			zap.String("Account ID", oneTimeToken.AccountID.String()))
+		return err
+	}
+
+	if !strings.EqualFold(account.Email, oneTimeToken.Email) {
+		as.logger.Error("Failed to verify email", zap.Error(ports.ErrOneTimeTokenInvalid),
+			zap.String("Account ID", account.ID.String()))
+		return ports.ErrOneTimeTokenInvalid
+	}
+
+	err = as.repository.MarkEmailVerified(ctx, account.ID, oneTimeToken.Email)
+	if err != nil {
+		as.logger.Error("Failed to verify email", zap.Error(err), zap.String("Account ID", account.ID.String()))
+		return err
+	}
+
+	as.logger.Info("Email successfully verified", zap.String("Account ID", account.ID.String()))
+
+	return nil
+}
+
+// ChangePassword closes every other session of the account, the session the
+// password was changed from stays logged in.
+func (as *AccountService) ChangePassword(ctx context.Context, req ports.ChangePasswordRequest) error {
+	account, err := as.repository.GetByID(ctx, req.AccountID)
+	if err != nil {
+		as.logger.Error("Failed to change password", zap.Error(err),
+			zap.String("Account ID", req.AccountID.String()))
+		return err
+	}
+
+	if !as.hash.ComparePasswordWithHash(req.OldPassword, domain.SaltedPassword{
+		HashPassword: account.Password,
+		Salt:         account.Salt,
+	}) {
+		as.logger.Error("Failed to change password", zap.Error(ports.ErrIncorrectPassword),
+			zap.String("Account ID", req.AccountID.String()))
+		return ports.ErrIncorrectPassword
+	}
+
+	err = as.repository.UpdatePassword(ctx, account.ID, as.hash.EncodePassword(req.NewPassword))
+	if err != nil {
+		as.logger.Error("Failed to change password", zap.Error(err),
+			zap.String("Account ID", req.AccountID.String()))
+		return err
+	}
+
+	sessions, err := as.tokenProvider.GetSessions(ctx, account.ID)
+	if err != nil {
+		as.logger.Error("Failed to close sessions after password change", zap.Error(err),
+			zap.String("Account ID", req.AccountID.String()))
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == req.SessionID {
+			continue
+		}
+
+		err = as.tokenProvider.RevokeSession(ctx, account.ID, session.ID)
+		if err != nil && !errors.Is(err, ports.ErrSessionNotFound) {
+			as.logger.Error("Failed to close sessions after password change", zap.Error(err),
+				zap.String("Account ID", req.AccountID.String()))
+			return err
+		}
+	}
+
+	as.logger.Info("Password successfully changed", zap.String("Account ID", req.AccountID.String()))
+
+	return nil
+}
+
+// ForgotPassword never tells whether the email belongs to an account. Reset
+// links are only sent to verified addresses, an unverified one may have been
+// typed by somebody else.
+func (as *AccountService) ForgotPassword(ctx context.Context, email string) error {
+	account, err := as.repository.GetByEmail(ctx, email)
+	if errors.Is(err, ports.ErrAccountNotFound) {
+		as.logger.Info("Password reset requested for unknown email")
+		return nil
+	} else if err != nil {
+		as.logger.Error("Failed to request password reset", zap.Error(err))
+		return err
+	}
+
+	if !account.EmailVerified {
+		as.logger.Info("Password reset requested for unverified email",
+			zap.String("Account ID", account.ID.String()))
+		return nil
+	}
+
+	token, err := as.oneTimeTokens.Issue(ctx, domain.OneTimeToken{
+		Purpose:   domain.OneTimeTokenResetPassword,
+		AccountID: account.ID,
+		Email:     account.Email,
+	}, as.cfg.ResetPasswordTokenTTL)
+	if err != nil {
+		as.logger.Error("Failed to issue password reset token", zap.Error(err),
+			zap.String("Account ID", account.ID.String()))
+		return err
+	}
+
+	err = as.mailer.Send(ctx, domain.Mail{
+		To:      account.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nset a new password by opening the link below:\n\n%s\n\n"+
+			"The link is valid for %s. If you didn't ask for it, ignore this mail.\n",
+			account.Name, tokenLink(as.cfg.ResetPasswordURL, token), as.cfg.ResetPasswordTokenTTL),
+	})
+	if err != nil {
+		as.logger.Error("Failed to send password reset", zap.Error(err),
+			zap.String("Account ID", account.ID.String()))
+		return err
+	}
+
+	as.logger.Info("Password reset successfully sent", zap.String("Account ID", account.ID.String()))
+
+	return nil
+}
+
+// ResetPassword closes every session of the account.
+func (as *AccountService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	oneTimeToken, err := as.oneTimeTokens.Consume(ctx, domain.OneTimeTokenResetPassword, token)
+	if err != nil {
+		as.logger.Error("Failed to reset password", zap.Error(err))
+		return err
+	}
+
+	account, err := as.repository.GetByID(ctx, oneTimeToken.AccountID)
+	if errors.Is(err, ports.ErrAccountNotFound) {
+		return ports.ErrOneTimeTokenInvalid
+	} else if err != nil {
+		as.logger.Error("Failed to reset password", zap.Error(err),
+			zap.String("Account ID", oneTimeToken.AccountID.String()))
+		return err
+	}
+
+	if !strings.EqualFold(account.Email, oneTimeToken.Email) {
+		as.logger.Error("Failed to reset password", zap.Error(ports.ErrOneTimeTokenInvalid),
+			zap.String("Account ID", account.ID.String()))
+		return ports.ErrOneTimeTokenInvalid
+	}
+
+	err = as.repository.UpdatePassword(ctx, account.ID, as.hash.EncodePassword(newPassword))
+	if err != nil {
+		as.logger.Error("Failed to reset password", zap.Error(err), zap.String("Account ID", account.ID.String()))
+		return err
+	}
+
+	err = as.tokenProvider.RevokeAllSessions(ctx, account.ID)
+	if err != nil {
+		as.logger.Error("Failed to close sessions after password reset", zap.Error(err),
+			zap.String("Account ID", account.ID.String()))
+		return err
+	}
+
+	as.logger.Info("Password successfully reset", zap.String("Account ID", account.ID.String()))
+
+	return nil
+}
+
+// BeginTwoFactor stores a new secret with fresh recovery codes. It replaces an
+// enrollment that was never confirmed, an enabled setup has to be disabled
+// first.
+func (as *AccountService) BeginTwoFactor(ctx context.Context, accountID uuid.UUID) (domain.TwoFactorEnrollment, error) {
+	account, err := as.repository.GetByID(ctx, accountID)
+	if err != nil {
+		as.logger.Error("Failed to begin two-factor enrollment", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	twoFactor, err := as.twoFactorRepository.Get(ctx, accountID)
+	if err == nil && twoFactor.Enabled {
+		return domain.TwoFactorEnrollment{}, ports.ErrTwoFactorAlreadyEnabled
+	} else if err != nil && !errors.Is(err, ports.ErrTwoFactorNotFound) {
+		as.logger.Error("Failed to begin two-factor enrollment", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	secret, err := as.totp.GenerateSecret()
+	if err != nil {
+		as.logger.Error("Failed to generate two-factor secret", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return domain.TwoFactorEnrollment{}, ports.ErrInternalTwoFactorRepo
+	}
+
+	recoveryCodes, err := as.totp.GenerateRecoveryCodes()
+	if err != nil {
+		as.logger.Error("Failed to generate recovery codes", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return domain.TwoFactorEnrollment{}, ports.ErrInternalTwoFactorRepo
+	}
+
+	codeHashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		codeHashes[i] = as.totp.HashRecoveryCode(code)
+	}
+
+	err = as.twoFactorRepository.Save(ctx, domain.TwoFactor{
+		AccountID: accountID,
+		Secret:    secret,
+	}, codeHashes)
+	if err != nil {
+		as.logger.Error("Failed to save two-factor enrollment", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	as.logger.Info("Two-factor enrollment started", zap.String("Account ID", accountID.String()))
+
+	return domain.TwoFactorEnrollment{
+		Secret:        secret,
+		URI:           as.totp.URI(secret, account.Name),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (as *AccountService) EnableTwoFactor(ctx context.Context, accountID uuid.UUID, code string) error {
+	twoFactor, err := as.twoFactorRepository.Get(ctx, accountID)
+	if err != nil {
+		as.logger.Error("Failed to enable two-factor authentication", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	if twoFactor.Enabled {
+		return ports.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := as.totp.Validate(twoFactor.Secret, code, time.Now())
+	if !ok {
+		as.logger.Error("Failed to enable two-factor authentication", zap.Error(ports.ErrTwoFactorInvalidCode),
+			zap.String("Account ID", accountID.String()))
+		return ports.ErrTwoFactorInvalidCode
+	}
+
+	err = as.twoFactorRepository.Enable(ctx, accountID, step)
+	if err != nil {
+		as.logger.Error("Failed to enable two-factor authentication", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	as.logger.Info("Two-factor authentication enabled", zap.String("Account ID", accountID.String()))
+
+	return nil
+}
+
+func (as *AccountService) DisableTwoFactor(ctx context.Context, accountID uuid.UUID, password string) error {
+	account, err := as.repository.GetByID(ctx, accountID)
+	if err != nil {
+		as.logger.Error("Failed to disable two-factor authentication", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	if !as.hash.ComparePasswordWithHash(password, domain.SaltedPassword{
+		HashPassword: account.Password,
+		Salt:         account.Salt,
+	}) {
+		as.logger.Error("Failed to disable two-factor authentication", zap.Error(ports.ErrIncorrectPassword),
+			zap.String("Account ID", accountID.String()))
+		return ports.ErrIncorrectPassword
+	}
+
+	err = as.twoFactorRepository.Delete(ctx, accountID)
+	if err != nil {
+		as.logger.Error("Failed to disable two-factor authentication", zap.Error(err),
+			zap.String("Account ID", accountID.String()))
+		return err
+	}
+
+	as.logger.Info("Two-factor authentication disabled", zap.String("Account ID", accountID.String()))
+
+	return nil
+}
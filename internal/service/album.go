@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
@@ -12,16 +13,22 @@ import (
 )
 
 type AlbumService struct {
-	repository   ports.IAlbumRepository
-	imageStorage ports.IAlbumImageStorage
-	logger       *zap.Logger
+	repository    ports.IAlbumRepository
+	imageStorage  ports.IAlbumImageStorage
+	trackService  ports.ITrackService
+	followService ports.IFollowService
+	logger        *zap.Logger
 }
 
-func NewAlbumService(repo ports.IAlbumRepository, imageStorage ports.IAlbumImageStorage, logger *zap.Logger) *AlbumService {
+func NewAlbumService(repo ports.IAlbumRepository, imageStorage ports.IAlbumImageStorage,
+	trackService ports.ITrackService, followService ports.IFollowService, logger *zap.Logger,
+) *AlbumService {
 	return &AlbumService{
-		repository:   repo,
-		imageStorage: imageStorage,
-		logger:       logger,
+		repository:    repo,
+		imageStorage:  imageStorage,
+		trackService:  trackService,
+		followService: followService,
+		logger:        logger,
 	}
 }
 
@@ -128,5 +135,135 @@ func (as *AlbumService) Publish(ctx context.Context, albumID uuid.UUID) error {
 
 	as.logger.Info("Successfully published album", zap.String("Album ID", albumID.String()))
 
+	album, err := as.repository.GetByID(ctx, albumID)
+	if err != nil {
+		as.logger.Error("Failed to get published album for feed", zap.Error(err),
+			zap.String("Album ID", albumID.String()))
+		return nil
+	}
+
+	// The album is already public at this point, so a feed failure is logged
+	// by the follow service and must not be reported as a failed publish.
+	_ = as.followService.PublishToFeed(ctx, album)
+
+	return nil
+}
+
+func (as *AlbumService) Schedule(ctx context.Context, albumID uuid.UUID, releaseAt time.Time) error {
+	if !releaseAt.After(time.Now()) {
+		as.logger.Error("Failed to schedule album release in the past", zap.String("Album ID", albumID.String()),
+			zap.Time("Release time", releaseAt))
+		return ports.ErrAlbumSchedule
+	}
+
+	err := as.repository.Schedule(ctx, albumID, null.TimeFrom(releaseAt))
+	if err != nil {
+		as.logger.Error("Failed to schedule album release", zap.Error(err), zap.String("Album ID", albumID.String()))
+		return err
+	}
+
+	as.logger.Info("Successfully scheduled album release", zap.String("Album ID", albumID.String()),
+		zap.Time("Release time", releaseAt))
+
+	return nil
+}
+
+func (as *AlbumService) CancelSchedule(ctx context.Context, albumID uuid.UUID) error {
+	err := as.repository.Schedule(ctx, albumID, null.Time{})
+	if err != nil {
+		as.logger.Error("Failed to cancel album release schedule", zap.Error(err),
+			zap.String("Album ID", albumID.String()))
+		return err
+	}
+
+	as.logger.Info("Successfully canceled album release schedule", zap.String("Album ID", albumID.String()))
+
+	return nil
+}
+
+func (as *AlbumService) PublishScheduled(ctx context.Context) ([]domain.Album, error) {
+	albums, err := as.repository.PublishScheduled(ctx, time.Now())
+	if err != nil {
+		as.logger.Error("Failed to publish scheduled albums", zap.Error(err))
+		return nil, err
+	}
+
+	for _, album := range albums {
+		as.logger.Info("Successfully published scheduled album", zap.String("Album ID", album.ID.String()))
+		_ = as.followService.PublishToFeed(ctx, album)
+	}
+
+	return albums, nil
+}
+
+func (as *AlbumService) Unpublish(ctx context.Context, albumID uuid.UUID, reason string) error {
+	err := as.repository.Unpublish(ctx, albumID, reason)
+	if err != nil {
+		as.logger.Error("Failed to unpublish album", zap.Error(err), zap.String("Album ID", albumID.String()))
+		return err
+	}
+
+	as.logger.Info("Successfully unpublished album", zap.String("Album ID", albumID.String()),
+		zap.String("Reason", reason))
+
 	return nil
 }
+
+func (as *AlbumService) Update(ctx context.Context, albumInfo ports.UpdateAlbumServiceReq) (domain.Album, error) {
+	albums, err := as.repository.GetOwn(ctx, albumInfo.MusicianID)
+	if err != nil {
+		as.logger.Error("Failed to update album", zap.Error(err), zap.String("Album ID", albumInfo.AlbumID.String()))
+		return domain.Album{}, err
+	}
+
+	for _, album := range albums {
+		if album.ID != albumInfo.AlbumID {
+			continue
+		}
+
+		if albumInfo.Name.Valid {
+			album.Name = albumInfo.Name.String
+		}
+
+		if albumInfo.Description.Valid {
+			album.Description = albumInfo.Description.String
+		}
+
+		album, err = as.repository.Update(ctx, album)
+		if err != nil {
+			as.logger.Error("Failed to update album", zap.Error(err), zap.String("Album ID", album.ID.String()))
+			return domain.Album{}, err
+		}
+
+		as.logger.Info("Successfully updated album", zap.String("Album ID", album.ID.String()))
+
+		return album, nil
+	}
+
+	return domain.Album{}, ports.ErrAlbumIDNotFound
+}
+
+func (as *AlbumService) Delete(ctx context.Context, albumID uuid.UUID) (domain.Album, error) {
+	err := as.trackService.DeleteByAlbumID(ctx, albumID)
+	if err != nil {
+		as.logger.Error("Failed to delete album tracks", zap.Error(err), zap.String("Album ID", albumID.String()))
+		return domain.Album{}, err
+	}
+
+	album, err := as.repository.Delete(ctx, albumID)
+	if err != nil {
+		as.logger.Error("Failed to delete album", zap.Error(err), zap.String("Album ID", albumID.String()))
+		return domain.Album{}, err
+	}
+
+	if album.ImageURL.Valid {
+		err = as.imageStorage.DeleteImage(ctx, albumID.String())
+		if err != nil {
+			as.logger.Error("Failed to delete album image", zap.Error(err), zap.String("Album ID", albumID.String()))
+		}
+	}
+
+	as.logger.Info("Successfully deleted album", zap.String("Album ID", albumID.String()))
+
+	return album, nil
+}
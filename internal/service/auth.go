@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
@@ -11,109 +13,156 @@ import (
 	"go.uber.org/zap"
 )
 
+// twoFactorChallengeTTL is how long the second login step may take.
+const twoFactorChallengeTTL = 5 * time.Minute
+
 type AuthorizationService struct {
-	userRepository     ports.IUserRepository
-	musicianRepository ports.IMusicianRepository
-	tokenProvider      ports.ITokenProvider
-	hash               ports.IHashPasswordProvider
-	logger             *zap.Logger
+	accountRepository   ports.IAccountRepository
+	userRepository      ports.IUserRepository
+	twoFactorRepository ports.ITwoFactorRepository
+	tokenProvider       ports.ITokenProvider
+	oneTimeTokens       ports.IOneTimeTokenProvider
+	totp                ports.ITOTPProvider
+	hash                ports.IHashPasswordProvider
+	logger              *zap.Logger
 }
 
-func NewAuthorizationService(userRepo ports.IUserRepository, musicianRepo ports.IMusicianRepository,
-	tokenProvider ports.ITokenProvider, hash ports.IHashPasswordProvider, logger *zap.Logger) *AuthorizationService {
+func NewAuthorizationService(accountRepo ports.IAccountRepository, userRepo ports.IUserRepository,
+	twoFactorRepo ports.ITwoFactorRepository, tokenProvider ports.ITokenProvider,
+	oneTimeTokens ports.IOneTimeTokenProvider, totp ports.ITOTPProvider, hash ports.IHashPasswordProvider,
+	logger *zap.Logger) *AuthorizationService {
 	return &AuthorizationService{
-		userRepository:     userRepo,
-		musicianRepository: musicianRepo,
-		tokenProvider:      tokenProvider,
-		hash:               hash,
-		logger:             logger,
+		accountRepository:   accountRepo,
+		userRepository:      userRepo,
+		twoFactorRepository: twoFactorRepo,
+		tokenProvider:       tokenProvider,
+		oneTimeTokens:       oneTimeTokens,
+		totp:                totp,
+		hash:                hash,
+		logger:              logger,
 	}
 }
 
-func (a *AuthorizationService) authUser(ctx context.Context, name string, password string) (domain.User, error) {
-	user, err := a.userRepository.GetByName(ctx, name)
+// findAccount accepts either an email or an account name. Names may contain
+// no '@', so a login that looks like an email is looked up by email first.
+func (a *AuthorizationService) findAccount(ctx context.Context, login string) (domain.Account, error) {
+	if strings.Contains(login, "@") {
+		account, err := a.accountRepository.GetByEmail(ctx, login)
+		if !errors.Is(err, ports.ErrAccountNotFound) {
+			return account, err
+		}
+	}
+
+	return a.accountRepository.GetByName(ctx, login)
+}
 
-	if errors.Is(err, ports.ErrUserNameNotFound) {
-		return domain.User{}, ports.ErrIncorrectName
+func (a *AuthorizationService) authAccount(ctx context.Context, login string, password string) (domain.Account, error) {
+	account, err := a.findAccount(ctx, login)
+	if errors.Is(err, ports.ErrAccountNotFound) {
+		return domain.Account{}, ports.ErrIncorrectName
 	} else if err != nil {
-		return domain.User{}, util.WrapError(ports.ErrInternalAuthRepo, err)
+		return domain.Account{}, util.WrapError(ports.ErrInternalAuthRepo, err)
 	}
 
 	saltedPassword := domain.SaltedPassword{
-		HashPassword: user.Password,
-		Salt:         user.Salt,
+		HashPassword: account.Password,
+		Salt:         account.Salt,
 	}
 
 	if !a.hash.ComparePasswordWithHash(password, saltedPassword) {
-		return domain.User{}, ports.ErrIncorrectPassword
+		return domain.Account{}, ports.ErrIncorrectPassword
+	}
+
+	if a.hash.NeedsRehash(saltedPassword) {
+		// The password is already verified, so a failed upgrade must not
+		// block the login; the hash is upgraded on one of the next logins.
+		err = a.accountRepository.UpdatePassword(ctx, account.ID, a.hash.EncodePassword(password))
+		if err != nil {
+			a.logger.Error("Failed to upgrade account password hash", zap.Error(err),
+				zap.String("Account ID", account.ID.String()))
+		}
 	}
 
-	return user, nil
+	return account, nil
 }
 
-func (a *AuthorizationService) authMusician(ctx context.Context, name string, password string) (domain.Musician, error) {
-	musician, err := a.musicianRepository.GetByName(ctx, name)
+// LogIn returns the tokens right away unless the account has two-factor
+// authentication enabled. Then only a challenge token is returned, which
+// LogInTwoFactor exchanges for the tokens together with a valid code. The
+// challenge can be used once, so every code guess needs the password again.
+func (a *AuthorizationService) LogIn(ctx context.Context, cred ports.LogInCredentials) (domain.LoginResult, error) {
+	account, err := a.authAccount(ctx, cred.Name, cred.Password)
+	if err != nil {
+		a.logger.Error("Failed to authorize user", zap.Error(err), zap.String("User Name", cred.Name))
+		return domain.LoginResult{}, err
+	}
 
-	if errors.Is(err, ports.ErrMusicianNameNotFound) {
-		return domain.Musician{}, ports.ErrIncorrectName
-	} else if err != nil {
-		return domain.Musician{}, util.WrapError(ports.ErrInternalAuthRepo, err)
+	twoFactor, err := a.twoFactorRepository.Get(ctx, account.ID)
+	if err != nil && !errors.Is(err, ports.ErrTwoFactorNotFound) {
+		a.logger.Error("Failed to get two-factor setup", zap.Error(err), zap.String("Account ID", account.ID.String()))
+		return domain.LoginResult{}, util.WrapError(ports.ErrInternalAuthRepo, err)
 	}
 
-	saltedPassword := domain.SaltedPassword{
-		HashPassword: musician.Password,
-		Salt:         musician.Salt,
+	if err == nil && twoFactor.Enabled {
+		challengeToken, err := a.oneTimeTokens.Issue(ctx, domain.OneTimeToken{
+			Purpose:   domain.OneTimeTokenTwoFactor,
+			AccountID: account.ID,
+		}, twoFactorChallengeTTL)
+		if err != nil {
+			a.logger.Error("Failed to issue two-factor challenge", zap.Error(err),
+				zap.String("Account ID", account.ID.String()))
+			return domain.LoginResult{}, err
+		}
+
+		a.logger.Info("Two-factor challenge issued", zap.String("Account ID", account.ID.String()))
+
+		return domain.LoginResult{ChallengeToken: challengeToken}, nil
 	}
 
-	if !a.hash.ComparePasswordWithHash(password, saltedPassword) {
-		return domain.Musician{}, ports.ErrIncorrectPassword
+	tokens, err := a.newSession(ctx, account, cred.Metadata)
+	if err != nil {
+		return domain.LoginResult{}, err
 	}
 
-	return musician, nil
+	return domain.LoginResult{Tokens: tokens}, nil
 }
 
-func (a *AuthorizationService) LogIn(ctx context.Context, cred ports.LogInCredentials) (domain.TokenPair, error) {
-	var id uuid.UUID
-	var role int
-
-	user, err := a.authUser(ctx, cred.Name, cred.Password)
-	if err == nil {
-		id = user.ID
-		role = domain.UserRole
-	} else {
-		musician, err := a.authMusician(ctx, cred.Name, cred.Password)
-		if err == nil {
-			id = musician.ID
-			role = domain.MusicianRole
-		} else {
-			a.logger.Error("Failed to authorize user", zap.Error(err), zap.String("User Name", cred.Name))
-			return domain.TokenPair{}, err
+func (a *AuthorizationService) LogInTwoFactor(ctx context.Context, cred ports.TwoFactorCredentials) (domain.TokenPair, error) {
+	challenge, err := a.oneTimeTokens.Consume(ctx, domain.OneTimeTokenTwoFactor, cred.ChallengeToken)
+	if err != nil {
+		a.logger.Error("Failed to verify two-factor challenge", zap.Error(err))
+		if errors.Is(err, ports.ErrOneTimeTokenInvalid) {
+			return domain.TokenPair{}, ports.ErrInvalidToken
 		}
+		return domain.TokenPair{}, err
 	}
 
-	payload := domain.Payload{
-		UserID: id,
-		Role:   role,
+	twoFactor, err := a.twoFactorRepository.Get(ctx, challenge.AccountID)
+	if err != nil || !twoFactor.Enabled {
+		a.logger.Error("Failed to get two-factor setup", zap.Error(err),
+			zap.String("Account ID", challenge.AccountID.String()))
+		return domain.TokenPair{}, ports.ErrInvalidToken
 	}
 
-	var stringRole string
-	if payload.Role == domain.UserRole {
-		stringRole = "User"
-	} else {
-		stringRole = "Musician"
+	err = verifySecondFactor(ctx, a.twoFactorRepository, a.totp, twoFactor, cred.Code)
+	if err != nil {
+		a.logger.Error("Failed to verify two-factor code", zap.Error(err),
+			zap.String("Account ID", challenge.AccountID.String()))
+		return domain.TokenPair{}, err
 	}
 
-	tokens, err := a.tokenProvider.NewSession(ctx, payload)
+	account, err := a.accountRepository.GetByID(ctx, challenge.AccountID)
 	if err != nil {
-		a.logger.Error("Failed to create new session for user", zap.Error(err),
-			zap.String("User ID", payload.UserID.String()), zap.String("User Role", stringRole))
-		return domain.TokenPair{}, err
+		a.logger.Error("Failed to get account", zap.Error(err), zap.String("Account ID", challenge.AccountID.String()))
+		return domain.TokenPair{}, util.WrapError(ports.ErrInternalAuthRepo, err)
 	}
 
-	a.logger.Info("User successfully authorized",
-		zap.String("User ID", payload.UserID.String()), zap.String("User Role", stringRole))
+	return a.newSession(ctx, account, cred.Metadata)
+}
 
-	return tokens, nil
+func (a *AuthorizationService) newSession(ctx context.Context, account domain.Account,
+	metadata domain.SessionMetadata) (domain.TokenPair, error) {
+	return issueSession(ctx, a.tokenProvider, a.userRepository, account, metadata, a.logger)
 }
 
 func (a *AuthorizationService) LogOut(ctx context.Context, tokenString string) error {
@@ -123,12 +172,7 @@ func (a *AuthorizationService) LogOut(ctx context.Context, tokenString string) e
 		if errVerify != nil {
 			a.logger.Error("Failed to close session for user", zap.Error(errVerify))
 		} else {
-			var stringRole string
-			if payload.Role == domain.UserRole {
-				stringRole = "User"
-			} else {
-				stringRole = "Musician"
-			}
+			stringRole := domain.RoleName(payload.Role)
 
 			a.logger.Error("Failed to close user session", zap.Error(err),
 				zap.String("User ID", payload.UserID.String()), zap.String("User Role", stringRole))
@@ -143,7 +187,15 @@ func (a *AuthorizationService) LogOut(ctx context.Context, tokenString string) e
 func (a *AuthorizationService) RefreshToken(ctx context.Context, refreshTokenString string) (domain.TokenPair, error) {
 	tokenPair, err := a.tokenProvider.RefreshSession(ctx, refreshTokenString)
 	if err != nil {
-		a.logger.Error("Failed to refresh token for user", zap.Error(err))
+		if errors.Is(err, ports.ErrTokenProviderReusedToken) {
+			a.logger.Warn("Refresh token reuse detected, session revoked", zap.Error(err))
+		} else {
+			a.logger.Error("Failed to refresh token for user", zap.Error(err))
+		}
+
+		if isTokenRejected(err) {
+			return domain.TokenPair{}, ports.ErrInvalidToken
+		}
 		return domain.TokenPair{}, err
 	}
 
@@ -152,19 +204,12 @@ func (a *AuthorizationService) RefreshToken(ctx context.Context, refreshTokenStr
 
 func (a *AuthorizationService) VerifyToken(ctx context.Context, tokenString string) (domain.Payload, error) {
 	payload, err := a.tokenProvider.VerifyToken(ctx, tokenString)
-	var stringRole string
-	if payload.Role == domain.UserRole {
-		stringRole = "User"
-	} else {
-		stringRole = "Musician"
-	}
+	stringRole := domain.RoleName(payload.Role)
 
 	if err != nil {
 		a.logger.Error("Failed to verify token for user", zap.Error(err),
 			zap.String("User ID", payload.UserID.String()), zap.String("User Role", stringRole))
-		if errors.Is(err, ports.ErrTokenProviderInvalidToken) ||
-			errors.Is(err, ports.ErrTokenProviderExpiredToken) ||
-			errors.Is(err, ports.ErrTokenProviderParsingToken) {
+		if isTokenRejected(err) {
 			return domain.Payload{}, ports.ErrInvalidToken
 		}
 		return domain.Payload{}, ports.ErrInternalAuthRepo
@@ -175,3 +220,53 @@ func (a *AuthorizationService) VerifyToken(ctx context.Context, tokenString stri
 
 	return payload, err
 }
+
+func (a *AuthorizationService) GetSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	sessions, err := a.tokenProvider.GetSessions(ctx, userID)
+	if err != nil {
+		a.logger.Error("Failed to get user sessions", zap.Error(err), zap.String("User ID", userID.String()))
+		return nil, err
+	}
+
+	a.logger.Info("User sessions successfully received", zap.String("User ID", userID.String()))
+
+	return sessions, nil
+}
+
+func (a *AuthorizationService) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	err := a.tokenProvider.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		a.logger.Error("Failed to revoke user session", zap.Error(err),
+			zap.String("User ID", userID.String()), zap.String("Session ID", sessionID.String()))
+		return err
+	}
+
+	a.logger.Info("User session successfully revoked",
+		zap.String("User ID", userID.String()), zap.String("Session ID", sessionID.String()))
+
+	return nil
+}
+
+func (a *AuthorizationService) LogOutEverywhere(ctx context.Context, userID uuid.UUID) error {
+	err := a.tokenProvider.RevokeAllSessions(ctx, userID)
+	if err != nil {
+		a.logger.Error("Failed to revoke all user sessions", zap.Error(err), zap.String("User ID", userID.String()))
+		return err
+	}
+
+	a.logger.Info("All user sessions successfully revoked", zap.String("User ID", userID.String()))
+
+	return nil
+}
+
+func (a *AuthorizationService) GetPublicKeys(ctx context.Context) []domain.JSONWebKey {
+	return a.tokenProvider.PublicKeys()
+}
+
+func isTokenRejected(err error) bool {
+	return errors.Is(err, ports.ErrTokenProviderInvalidToken) ||
+		errors.Is(err, ports.ErrTokenProviderExpiredToken) ||
+		errors.Is(err, ports.ErrTokenProviderParsingToken) ||
+		errors.Is(err, ports.ErrTokenProviderRevokedToken) ||
+		errors.Is(err, ports.ErrTokenProviderReusedToken)
+}
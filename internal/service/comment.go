@@ -2,49 +2,92 @@ package service
 
 import (
 	"context"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"go.uber.org/zap"
 )
 
+const (
+	defaultCommentPageLimit = 20
+	maxCommentPageLimit     = 100
+)
+
+// CommentServiceConfig lists the words comments may not contain. Words are
+// matched whole and case-insensitively.
+type CommentServiceConfig struct {
+	BannedWords []string
+}
+
 type CommentService struct {
-	repository ports.ICommentRepository
-	logger     *zap.Logger
+	repository   ports.ICommentRepository
+	trackService ports.ITrackService
+	bannedWords  map[string]struct{}
+	logger       *zap.Logger
 }
 
-func NewCommentService(repo ports.ICommentRepository, logger *zap.Logger) *CommentService {
+func NewCommentService(repo ports.ICommentRepository, trackService ports.ITrackService, cfg CommentServiceConfig,
+	logger *zap.Logger) *CommentService {
+	bannedWords := make(map[string]struct{}, len(cfg.BannedWords))
+	for _, word := range cfg.BannedWords {
+		bannedWords[strings.ToLower(word)] = struct{}{}
+	}
+
 	return &CommentService{
-		repository: repo,
-		logger:     logger,
+		repository:   repo,
+		trackService: trackService,
+		bannedWords:  bannedWords,
+		logger:       logger,
+	}
+}
+
+func (cs *CommentService) hasBannedWords(text string) bool {
+	if len(cs.bannedWords) == 0 {
+		return false
 	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		if _, banned := cs.bannedWords[word]; banned {
+			return true
+		}
+	}
+
+	return false
 }
 
+func validStars(stars int) bool {
+	return stars >= 1 && stars <= 5
+}
+
+// Post creates the review of the user on the track, or replaces the text and
+// stars of the one they already left.
 func (cs *CommentService) Post(ctx context.Context, comment ports.PostCommentServiceReq) (domain.Comment, error) {
+	if !validStars(comment.Stars) {
+		return domain.Comment{}, ports.ErrCommentStars
+	}
+
+	if cs.hasBannedWords(comment.Text) {
+		return domain.Comment{}, ports.ErrCommentProfanity
+	}
+
 	postComment := domain.Comment{
-		ID:      uuid.New(),
-		UserID:  comment.UserID,
-		TrackID: comment.TrackID,
-		Stars:   comment.Stars,
-		Text:    comment.Text,
-	}
-
-	comments, err := cs.GetUserComments(ctx, comment.UserID)
-    if err != nil {
-        return domain.Comment{}, err
-    }
-
-	for _, postedComment := range comments {
-		if postedComment.UserID == comment.UserID && postedComment.TrackID == comment.TrackID {
-			_, err = cs.repository.Delete(ctx, comment.UserID, postedComment.TrackID)
-            if err != nil {
-                return domain.Comment{}, err
-            }
-		}
+		ID:        uuid.New(),
+		UserID:    comment.UserID,
+		TrackID:   comment.TrackID,
+		Stars:     comment.Stars,
+		Text:      comment.Text,
+		CreatedAt: time.Now(),
 	}
 
-	comm, err := cs.repository.Create(ctx, postComment)
+	comm, err := cs.repository.Upsert(ctx, postComment)
 	if err != nil {
 		cs.logger.Error("Failed to post comment", zap.Error(err), zap.String("Comment ID", postComment.ID.String()),
 			zap.String("User ID", postComment.UserID.String()), zap.String("Track ID", postComment.TrackID.String()))
@@ -52,27 +95,182 @@ func (cs *CommentService) Post(ctx context.Context, comment ports.PostCommentSer
 		return domain.Comment{}, err
 	}
 
-	cs.logger.Info("Comment successfully posted", zap.String("Comment ID", postComment.ID.String()),
-		zap.String("User ID", postComment.UserID.String()), zap.String("Track ID", postComment.TrackID.String()))
+	cs.logger.Info("Comment successfully posted", zap.String("Comment ID", comm.ID.String()),
+		zap.String("User ID", comm.UserID.String()), zap.String("Track ID", comm.TrackID.String()))
 
 	return comm, nil
 }
 
+// Reply adds a reply to the thread of the comment. Threads are one level
+// deep, so replying to a reply adds to the thread of its review.
+func (cs *CommentService) Reply(ctx context.Context, reply ports.ReplyCommentServiceReq) (domain.Comment, error) {
+	if cs.hasBannedWords(reply.Text) {
+		return domain.Comment{}, ports.ErrCommentProfanity
+	}
+
+	parent, err := cs.repository.GetByID(ctx, reply.ParentID)
+	if err != nil {
+		cs.logger.Error("Failed to get replied comment", zap.Error(err),
+			zap.String("Comment ID", reply.ParentID.String()))
+		return domain.Comment{}, err
+	}
+
+	threadID := parent.ID
+	if parent.IsReply() {
+		threadID = parent.ParentID
+	}
+
+	replyComment := domain.Comment{
+		ID:        uuid.New(),
+		UserID:    reply.UserID,
+		TrackID:   parent.TrackID,
+		ParentID:  threadID,
+		Text:      reply.Text,
+		CreatedAt: time.Now(),
+	}
+
+	comm, err := cs.repository.Create(ctx, replyComment)
+	if err != nil {
+		cs.logger.Error("Failed to post reply", zap.Error(err), zap.String("Comment ID", threadID.String()),
+			zap.String("User ID", reply.UserID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Reply successfully posted", zap.String("Comment ID", comm.ID.String()),
+		zap.String("Parent ID", threadID.String()), zap.String("User ID", reply.UserID.String()))
+
+	return comm, nil
+}
+
+func (cs *CommentService) own(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (domain.Comment, error) {
+	comment, err := cs.repository.GetByID(ctx, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	if comment.UserID != userID {
+		return domain.Comment{}, ports.ErrCommentNotOwner
+	}
+
+	return comment, nil
+}
+
+func (cs *CommentService) Edit(ctx context.Context, edit ports.EditCommentServiceReq) (domain.Comment, error) {
+	comment, err := cs.own(ctx, edit.UserID, edit.CommentID)
+	if err != nil {
+		cs.logger.Error("Failed to get edited comment", zap.Error(err),
+			zap.String("Comment ID", edit.CommentID.String()), zap.String("User ID", edit.UserID.String()))
+		return domain.Comment{}, err
+	}
+
+	if edit.Stars.Valid {
+		if comment.IsReply() || !validStars(int(edit.Stars.Int64)) {
+			return domain.Comment{}, ports.ErrCommentStars
+		}
+		comment.Stars = int(edit.Stars.Int64)
+	}
+
+	if edit.Text.Valid {
+		if cs.hasBannedWords(edit.Text.String) {
+			return domain.Comment{}, ports.ErrCommentProfanity
+		}
+		comment.Text = edit.Text.String
+	}
+
+	comment.EditedAt = null.TimeFrom(time.Now())
+	edited, err := cs.repository.Update(ctx, comment)
+	if err != nil {
+		cs.logger.Error("Failed to edit comment", zap.Error(err), zap.String("Comment ID", comment.ID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment successfully edited", zap.String("Comment ID", comment.ID.String()),
+		zap.String("User ID", edit.UserID.String()))
+
+	return edited, nil
+}
+
 func (cs *CommentService) Delete(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) (domain.Comment, error) {
 	comment, err := cs.repository.Delete(ctx, userID, trackID)
 	return comment, err
 }
 
-func (cs *CommentService) GetCommentsOnTrack(ctx context.Context, trackID uuid.UUID) ([]domain.Comment, error) {
-	comments, err := cs.repository.GetByTrackID(ctx, trackID)
+// DeleteOwn deletes a review or reply of the user by its ID. Deleting a
+// review deletes its replies too.
+func (cs *CommentService) DeleteOwn(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (domain.Comment, error) {
+	_, err := cs.own(ctx, userID, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to get deleted comment", zap.Error(err),
+			zap.String("Comment ID", commentID.String()), zap.String("User ID", userID.String()))
+		return domain.Comment{}, err
+	}
+
+	comment, err := cs.repository.DeleteByID(ctx, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to delete comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment successfully deleted", zap.String("Comment ID", commentID.String()),
+		zap.String("User ID", userID.String()))
+
+	return comment, nil
+}
+
+func normalizeCommentPage(page domain.CommentPage) domain.CommentPage {
+	if page.Limit <= 0 {
+		page.Limit = defaultCommentPageLimit
+	} else if page.Limit > maxCommentPageLimit {
+		page.Limit = maxCommentPageLimit
+	}
+
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	if page.Sort == "" {
+		page.Sort = domain.CommentSortNewest
+	}
+
+	return page
+}
+
+// GetCommentsOnTrack returns a page of the reviews on the track with their
+// replies, oldest reply first, and the total number of reviews.
+func (cs *CommentService) GetCommentsOnTrack(ctx context.Context, trackID uuid.UUID,
+	page domain.CommentPage) ([]domain.Comment, int, error) {
+	page = normalizeCommentPage(page)
+	comments, total, err := cs.repository.GetByTrackID(ctx, trackID, page)
 	if err != nil {
 		cs.logger.Error("Failed to get comments on track", zap.Error(err))
-		return nil, err
+		return nil, 0, err
+	}
+
+	if len(comments) > 0 {
+		parentIDs := make([]uuid.UUID, len(comments))
+		for i, comment := range comments {
+			parentIDs[i] = comment.ID
+		}
+
+		replies, err := cs.repository.GetReplies(ctx, parentIDs)
+		if err != nil {
+			cs.logger.Error("Failed to get comment replies", zap.Error(err), zap.String("Track ID", trackID.String()))
+			return nil, 0, err
+		}
+
+		threads := make(map[uuid.UUID]int, len(comments))
+		for i, comment := range comments {
+			threads[comment.ID] = i
+		}
+		for _, reply := range replies {
+			i := threads[reply.ParentID]
+			comments[i].Replies = append(comments[i].Replies, reply)
+		}
 	}
 
 	cs.logger.Info("Comments successfully received by track ID", zap.String("Track ID", trackID.String()))
 
-	return comments, nil
+	return comments, total, nil
 }
 
 func (cs *CommentService) GetUserComments(ctx context.Context, userID uuid.UUID) ([]domain.Comment, error) {
@@ -86,3 +284,242 @@ func (cs *CommentService) GetUserComments(ctx context.Context, userID uuid.UUID)
 
 	return comments, nil
 }
+
+func (cs *CommentService) Report(ctx context.Context, report ports.ReportCommentServiceReq) (domain.CommentReport, error) {
+	comment, err := cs.repository.GetByID(ctx, report.CommentID)
+	if err != nil {
+		cs.logger.Error("Failed to get reported comment", zap.Error(err),
+			zap.String("Comment ID", report.CommentID.String()))
+		return domain.CommentReport{}, err
+	}
+
+	if comment.UserID == report.UserID {
+		return domain.CommentReport{}, ports.ErrCommentReportOwn
+	}
+
+	created, err := cs.repository.CreateReport(ctx, domain.CommentReport{
+		ID:         uuid.New(),
+		CommentID:  report.CommentID,
+		ReporterID: report.UserID,
+		Reason:     report.Reason,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		cs.logger.Error("Failed to report comment", zap.Error(err),
+			zap.String("Comment ID", report.CommentID.String()), zap.String("User ID", report.UserID.String()))
+		return domain.CommentReport{}, err
+	}
+
+	cs.logger.Info("Comment successfully reported", zap.String("Comment ID", report.CommentID.String()),
+		zap.String("User ID", report.UserID.String()))
+
+	return created, nil
+}
+
+func (cs *CommentService) GetModerationQueue(ctx context.Context,
+	page domain.CommentPage) ([]domain.ReportedComment, int, error) {
+	page = normalizeCommentPage(page)
+	queue, total, err := cs.repository.GetModerationQueue(ctx, page.Limit, page.Offset)
+	if err != nil {
+		cs.logger.Error("Failed to get moderation queue", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return queue, total, nil
+}
+
+func (cs *CommentService) Hide(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	comment, err := cs.repository.Hide(ctx, commentID, domain.CommentHiddenByModerator, time.Now())
+	if err != nil {
+		cs.logger.Error("Failed to hide comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	err = cs.resolveReports(ctx, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment hidden by moderator", zap.String("Comment ID", commentID.String()))
+
+	return comment, nil
+}
+
+func (cs *CommentService) Restore(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
+	comment, err := cs.repository.Restore(ctx, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to restore comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	// Restoring a comment that was never hidden dismisses its reports.
+	err = cs.resolveReports(ctx, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment restored by moderator", zap.String("Comment ID", commentID.String()))
+
+	return comment, nil
+}
+
+func (cs *CommentService) resolveReports(ctx context.Context, commentID uuid.UUID) error {
+	err := cs.repository.ResolveReports(ctx, commentID, time.Now())
+	if err != nil {
+		cs.logger.Error("Failed to resolve comment reports", zap.Error(err),
+			zap.String("Comment ID", commentID.String()))
+		return err
+	}
+
+	return nil
+}
+
+// onOwnTrack returns the comment if it was left on a track of the musician.
+func (cs *CommentService) onOwnTrack(ctx context.Context, musicianID uuid.UUID,
+	commentID uuid.UUID) (domain.Comment, error) {
+	comment, err := cs.repository.GetByID(ctx, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	tracks, err := cs.trackService.GetOwn(ctx, musicianID, domain.TrackSortDefault)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	for _, track := range tracks {
+		if track.ID == comment.TrackID {
+			return comment, nil
+		}
+	}
+
+	return domain.Comment{}, ports.ErrCommentNotOnOwnTrack
+}
+
+func (cs *CommentService) HideOnOwnTrack(ctx context.Context, musicianID uuid.UUID,
+	commentID uuid.UUID) (domain.Comment, error) {
+	comment, err := cs.onOwnTrack(ctx, musicianID, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to get comment to hide", zap.Error(err),
+			zap.String("Comment ID", commentID.String()), zap.String("Musician ID", musicianID.String()))
+		return domain.Comment{}, err
+	}
+
+	if comment.HiddenBy == domain.CommentHiddenByModerator {
+		return domain.Comment{}, ports.ErrCommentHiddenByModerator
+	}
+
+	comment, err = cs.repository.Hide(ctx, commentID, domain.CommentHiddenByMusician, time.Now())
+	if err != nil {
+		cs.logger.Error("Failed to hide comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment hidden by musician", zap.String("Comment ID", commentID.String()),
+		zap.String("Musician ID", musicianID.String()))
+
+	return comment, nil
+}
+
+func (cs *CommentService) RestoreOnOwnTrack(ctx context.Context, musicianID uuid.UUID,
+	commentID uuid.UUID) (domain.Comment, error) {
+	comment, err := cs.onOwnTrack(ctx, musicianID, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to get comment to restore", zap.Error(err),
+			zap.String("Comment ID", commentID.String()), zap.String("Musician ID", musicianID.String()))
+		return domain.Comment{}, err
+	}
+
+	if comment.HiddenBy == domain.CommentHiddenByModerator {
+		return domain.Comment{}, ports.ErrCommentHiddenByModerator
+	}
+
+	comment, err = cs.repository.Restore(ctx, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to restore comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment restored by musician", zap.String("Comment ID", commentID.String()),
+		zap.String("Musician ID", musicianID.String()))
+
+	return comment, nil
+}
+
+// Pin pins a review on a track of the musician to the top of the track
+// comments, or unpins it.
+func (cs *CommentService) Pin(ctx context.Context, musicianID uuid.UUID, commentID uuid.UUID,
+	pinned bool) (domain.Comment, error) {
+	comment, err := cs.onOwnTrack(ctx, musicianID, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to get comment to pin", zap.Error(err),
+			zap.String("Comment ID", commentID.String()), zap.String("Musician ID", musicianID.String()))
+		return domain.Comment{}, err
+	}
+
+	if comment.IsReply() {
+		return domain.Comment{}, ports.ErrCommentPinReply
+	}
+
+	var pinnedAt null.Time
+	if pinned {
+		pinnedAt = null.TimeFrom(time.Now())
+	}
+
+	comment, err = cs.repository.SetPinned(ctx, commentID, pinnedAt)
+	if err != nil {
+		cs.logger.Error("Failed to pin comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment pin changed", zap.String("Comment ID", commentID.String()),
+		zap.Bool("Pinned", pinned), zap.String("Musician ID", musicianID.String()))
+
+	return comment, nil
+}
+
+// Vote records whether the user found the comment helpful, replacing their
+// previous vote, and returns the comment with the new counts.
+func (cs *CommentService) Vote(ctx context.Context, userID uuid.UUID, commentID uuid.UUID,
+	helpful bool) (domain.Comment, error) {
+	comment, err := cs.repository.GetByID(ctx, commentID)
+	if err != nil {
+		cs.logger.Error("Failed to get voted comment", zap.Error(err), zap.String("Comment ID", commentID.String()))
+		return domain.Comment{}, err
+	}
+
+	if comment.UserID == userID {
+		return domain.Comment{}, ports.ErrCommentVoteOwn
+	}
+
+	err = cs.repository.Vote(ctx, domain.CommentVote{
+		CommentID: commentID,
+		UserID:    userID,
+		Helpful:   helpful,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		cs.logger.Error("Failed to vote for comment", zap.Error(err),
+			zap.String("Comment ID", commentID.String()), zap.String("User ID", userID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment vote successfully recorded", zap.String("Comment ID", commentID.String()),
+		zap.String("User ID", userID.String()), zap.Bool("Helpful", helpful))
+
+	return cs.repository.GetByID(ctx, commentID)
+}
+
+func (cs *CommentService) DeleteVote(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (domain.Comment, error) {
+	err := cs.repository.DeleteVote(ctx, commentID, userID)
+	if err != nil {
+		cs.logger.Error("Failed to delete comment vote", zap.Error(err),
+			zap.String("Comment ID", commentID.String()), zap.String("User ID", userID.String()))
+		return domain.Comment{}, err
+	}
+
+	cs.logger.Info("Comment vote successfully deleted", zap.String("Comment ID", commentID.String()),
+		zap.String("User ID", userID.String()))
+
+	return cs.repository.GetByID(ctx, commentID)
+}
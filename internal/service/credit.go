@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+type CreditService struct {
+	repository ports.ICreditRepository
+	logger     *zap.Logger
+}
+
+func NewCreditService(repo ports.ICreditRepository, logger *zap.Logger) *CreditService {
+	return &CreditService{
+		repository: repo,
+		logger:     logger,
+	}
+}
+
+func (cs *CreditService) InviteToAlbum(ctx context.Context, albumID uuid.UUID, ownerID uuid.UUID, musicianID uuid.UUID) error {
+	if ownerID == musicianID {
+		return ports.ErrSelfInvite
+	}
+
+	err := cs.repository.InviteToAlbum(ctx, albumID, musicianID)
+	if err != nil {
+		cs.logger.Error("Failed to invite musician to album", zap.Error(err),
+			zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+		return err
+	}
+
+	cs.logger.Info("Musician successfully invited to album",
+		zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+	return nil
+}
+
+func (cs *CreditService) AcceptInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	err := cs.repository.AcceptInvite(ctx, albumID, musicianID)
+	if err != nil {
+		cs.logger.Error("Failed to accept album invite", zap.Error(err),
+			zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+		return err
+	}
+
+	cs.logger.Info("Album invite successfully accepted",
+		zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+	return nil
+}
+
+func (cs *CreditService) DeclineInvite(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	err := cs.repository.DeclineInvite(ctx, albumID, musicianID)
+	if err != nil {
+		cs.logger.Error("Failed to decline album invite", zap.Error(err),
+			zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+		return err
+	}
+
+	cs.logger.Info("Album invite successfully declined",
+		zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+	return nil
+}
+
+func (cs *CreditService) RemoveContributor(ctx context.Context, albumID uuid.UUID, musicianID uuid.UUID) error {
+	err := cs.repository.RemoveContributor(ctx, albumID, musicianID)
+	if err != nil {
+		cs.logger.Error("Failed to remove album contributor", zap.Error(err),
+			zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+		return err
+	}
+
+	cs.logger.Info("Album contributor successfully removed",
+		zap.String("Album ID", albumID.String()), zap.String("Musician ID", musicianID.String()))
+
+	return nil
+}
+
+func (cs *CreditService) GetInvites(ctx context.Context, musicianID uuid.UUID) ([]domain.Album, error) {
+	albums, err := cs.repository.GetInvites(ctx, musicianID)
+	if err != nil {
+		cs.logger.Error("Failed to get album invites", zap.Error(err),
+			zap.String("Musician ID", musicianID.String()))
+
+		return nil, err
+	}
+
+	return albums, nil
+}
+
+func (cs *CreditService) GetAlbumContributors(ctx context.Context, albumID uuid.UUID) ([]domain.AlbumContributor, error) {
+	contributors, err := cs.repository.GetAlbumContributors(ctx, albumID)
+	if err != nil {
+		cs.logger.Error("Failed to get album contributors", zap.Error(err),
+			zap.String("Album ID", albumID.String()))
+
+		return nil, err
+	}
+
+	accepted := make([]domain.AlbumContributor, 0, len(contributors))
+	for _, contributor := range contributors {
+		if contributor.Accepted {
+			accepted = append(accepted, contributor)
+		}
+	}
+
+	return accepted, nil
+}
+
+func (cs *CreditService) AddTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error {
+	if !role.Valid() {
+		return ports.ErrUnknownCreditRole
+	}
+
+	err := cs.repository.AddTrackCredit(ctx, trackID, musicianID, role)
+	if err != nil {
+		cs.logger.Error("Failed to add track credit", zap.Error(err),
+			zap.String("Track ID", trackID.String()), zap.String("Musician ID", musicianID.String()),
+			zap.String("Role", string(role)))
+
+		return err
+	}
+
+	cs.logger.Info("Track credit successfully added",
+		zap.String("Track ID", trackID.String()), zap.String("Musician ID", musicianID.String()),
+		zap.String("Role", string(role)))
+
+	return nil
+}
+
+func (cs *CreditService) RemoveTrackCredit(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID, role domain.CreditRole) error {
+	if !role.Valid() {
+		return ports.ErrUnknownCreditRole
+	}
+
+	err := cs.repository.RemoveTrackCredit(ctx, trackID, musicianID, role)
+	if err != nil {
+		cs.logger.Error("Failed to remove track credit", zap.Error(err),
+			zap.String("Track ID", trackID.String()), zap.String("Musician ID", musicianID.String()),
+			zap.String("Role", string(role)))
+
+		return err
+	}
+
+	cs.logger.Info("Track credit successfully removed",
+		zap.String("Track ID", trackID.String()), zap.String("Musician ID", musicianID.String()),
+		zap.String("Role", string(role)))
+
+	return nil
+}
+
+func (cs *CreditService) GetTrackCredits(ctx context.Context, trackID uuid.UUID) ([]domain.TrackCredit, error) {
+	credits, err := cs.repository.GetTrackCredits(ctx, trackID)
+	if err != nil {
+		cs.logger.Error("Failed to get track credits", zap.Error(err),
+			zap.String("Track ID", trackID.String()))
+
+		return nil, err
+	}
+
+	return credits, nil
+}
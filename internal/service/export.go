@@ -0,0 +1,335 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultExportRetention = 7 * 24 * time.Hour
+	defaultExportLinkTTL   = 15 * time.Minute
+	exportBatchSize        = 10
+	// A running export older than this is assumed to be left by a worker that
+	// died and is claimed again.
+	exportStaleAfter = 30 * time.Minute
+)
+
+// DataExportServiceConfig sets how long archives are kept and how long a
+// download link stays valid. The storage has to drop archives after
+// Retention too.
+type DataExportServiceConfig struct {
+	Retention time.Duration
+	LinkTTL   time.Duration
+}
+
+type DataExportService struct {
+	repository     ports.IDataExportRepository
+	storage        ports.IDataExportStorage
+	userService    ports.IUserService
+	trackService   ports.ITrackService
+	commentService ports.ICommentService
+	statService    ports.IStatService
+	followService  ports.IFollowService
+	retention      time.Duration
+	linkTTL        time.Duration
+	logger         *zap.Logger
+}
+
+func NewDataExportService(repo ports.IDataExportRepository, storage ports.IDataExportStorage,
+	userService ports.IUserService, trackService ports.ITrackService, commentService ports.ICommentService,
+	statService ports.IStatService, followService ports.IFollowService, cfg DataExportServiceConfig,
+	logger *zap.Logger) *DataExportService {
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultExportRetention
+	}
+
+	if cfg.LinkTTL <= 0 {
+		cfg.LinkTTL = defaultExportLinkTTL
+	}
+
+	return &DataExportService{
+		repository:     repo,
+		storage:        storage,
+		userService:    userService,
+		trackService:   trackService,
+		commentService: commentService,
+		statService:    statService,
+		followService:  followService,
+		retention:      cfg.Retention,
+		linkTTL:        cfg.LinkTTL,
+		logger:         logger,
+	}
+}
+
+func (es *DataExportService) Request(ctx context.Context, userID uuid.UUID) (domain.DataExport, error) {
+	export, err := es.repository.Create(ctx, domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    domain.DataExportPending,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		es.logger.Error("Failed to request data export", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.DataExport{}, err
+	}
+
+	es.logger.Info("Data export successfully requested", zap.String("User ID", userID.String()),
+		zap.String("Export ID", export.ID.String()))
+
+	return export, nil
+}
+
+// Get returns the user's own export. Exports of other users are reported as
+// not found.
+func (es *DataExportService) Get(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (domain.DataExport, error) {
+	export, err := es.repository.GetByID(ctx, exportID)
+	if err != nil {
+		es.logger.Error("Failed to get data export", zap.Error(err), zap.String("Export ID", exportID.String()))
+		return domain.DataExport{}, err
+	}
+
+	if export.UserID != userID {
+		es.logger.Error("Failed to get data export", zap.Error(ports.ErrDataExportIDNotFound),
+			zap.String("Export ID", exportID.String()), zap.String("User ID", userID.String()))
+		return domain.DataExport{}, ports.ErrDataExportIDNotFound
+	}
+
+	return export, nil
+}
+
+func (es *DataExportService) DownloadURL(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (url.URL, error) {
+	export, err := es.Get(ctx, userID, exportID)
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	if export.Status != domain.DataExportReady {
+		return url.URL{}, ports.ErrDataExportNotReady
+	}
+
+	if export.IsExpired(time.Now()) {
+		return url.URL{}, ports.ErrDataExportExpired
+	}
+
+	link, err := es.storage.SignedURL(ctx, exportID.String(), es.linkTTL)
+	if err != nil {
+		es.logger.Error("Failed to sign data export link", zap.Error(err), zap.String("Export ID", exportID.String()))
+		return url.URL{}, err
+	}
+
+	return link, nil
+}
+
+// ProcessPending builds the archives of a batch of queued exports and returns
+// the exports it finished, successfully or not.
+func (es *DataExportService) ProcessPending(ctx context.Context) ([]domain.DataExport, error) {
+	now := time.Now()
+	exports, err := es.repository.ClaimPending(ctx, now, now.Add(-exportStaleAfter), exportBatchSize)
+	if err != nil {
+		es.logger.Error("Failed to claim data exports", zap.Error(err))
+		return nil, err
+	}
+
+	processed := make([]domain.DataExport, 0, len(exports))
+	for _, export := range exports {
+		err = es.build(ctx, export)
+		if err != nil {
+			es.logger.Error("Failed to build data export", zap.Error(err),
+				zap.String("Export ID", export.ID.String()), zap.String("User ID", export.UserID.String()))
+			export.Status = domain.DataExportFailed
+		} else {
+			export.Status = domain.DataExportReady
+			export.ExpiresAt = null.TimeFrom(time.Now().Add(es.retention))
+		}
+		export.CompletedAt = null.TimeFrom(time.Now())
+
+		finished, err := es.repository.Finish(ctx, export)
+		if errors.Is(err, ports.ErrDataExportLost) {
+			es.logger.Warn("Data export was taken over by another run", zap.String("Export ID", export.ID.String()))
+			continue
+		} else if err != nil {
+			es.logger.Error("Failed to finish data export", zap.Error(err), zap.String("Export ID", export.ID.String()))
+			continue
+		}
+		export = finished
+
+		es.logger.Info("Data export finished", zap.String("Export ID", export.ID.String()),
+			zap.String("Status", string(export.Status)))
+		processed = append(processed, export)
+	}
+
+	return processed, nil
+}
+
+func (es *DataExportService) build(ctx context.Context, export domain.DataExport) error {
+	archive, err := es.archive(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	return es.storage.PutArchive(ctx, bytes.NewReader(archive), int64(len(archive)), export.ID.String())
+}
+
+type exportProfile struct {
+	ID                  uuid.UUID  `json:"id"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email"`
+	Phone               string     `json:"phone"`
+	Country             string     `json:"country"`
+	Role                string     `json:"role"`
+	AvatarURL           string     `json:"avatar_url,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type exportComment struct {
+	ID             uuid.UUID  `json:"id"`
+	TrackID        uuid.UUID  `json:"track_id"`
+	ParentID       *uuid.UUID `json:"parent_id,omitempty"`
+	Stars          int        `json:"stars,omitempty"`
+	Text           string     `json:"text"`
+	CreatedAt      time.Time  `json:"created_at"`
+	EditedAt       *time.Time `json:"edited_at,omitempty"`
+	Hidden         bool       `json:"hidden"`
+	HelpfulVotes   int        `json:"helpful_votes"`
+	UnhelpfulVotes int        `json:"unhelpful_votes"`
+}
+
+// archive gathers everything stored about the user into a ZIP: the profile and
+// comments as JSON, favorites, listening history and followed musicians as
+// CSV. Passwords and salts are left out. There are no playlists in the
+// service, so there's no file for them.
+func (es *DataExportService) archive(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	user, err := es.userService.GetById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites, err := es.trackService.GetUserFavorites(ctx, userID, domain.TrackSortDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := es.commentService.GetUserComments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := es.statService.GetHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := es.followService.GetFollowing(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	err = writeJSON(zw, "profile.json", exportProfile{
+		ID:                  user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		Phone:               user.Phone,
+		Country:             user.Country,
+		Role:                strings.ToLower(domain.RoleName(user.Role)),
+		AvatarURL:           user.AvatarURL.ValueOrZero(),
+		DeletionScheduledAt: user.DeletionScheduledAt.Ptr(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exportComments := make([]exportComment, len(comments))
+	for i, comment := range comments {
+		exportComments[i] = exportComment{
+			ID:             comment.ID,
+			TrackID:        comment.TrackID,
+			Stars:          comment.Stars,
+			Text:           comment.Text,
+			CreatedAt:      comment.CreatedAt,
+			EditedAt:       comment.EditedAt.Ptr(),
+			Hidden:         comment.IsHidden(),
+			HelpfulVotes:   comment.HelpfulVotes,
+			UnhelpfulVotes: comment.UnhelpfulVotes,
+		}
+		if comment.IsReply() {
+			parentID := comment.ParentID
+			exportComments[i].ParentID = &parentID
+		}
+	}
+	err = writeJSON(zw, "comments.json", exportComments)
+	if err != nil {
+		return nil, err
+	}
+
+	favoriteRows := [][]string{{"track_id", "album_id", "name"}}
+	for _, track := range favorites {
+		favoriteRows = append(favoriteRows, []string{track.ID.String(), track.AlbumID.String(), track.Name})
+	}
+	err = writeCSV(zw, "favorites.csv", favoriteRows)
+	if err != nil {
+		return nil, err
+	}
+
+	historyRows := [][]string{{"track_id", "listened_at", "listened_seconds"}}
+	for _, record := range history {
+		historyRows = append(historyRows, []string{record.TrackID.String(),
+			record.ListenedAt.UTC().Format(time.RFC3339), strconv.Itoa(record.ListenedSeconds)})
+	}
+	err = writeCSV(zw, "history.csv", historyRows)
+	if err != nil {
+		return nil, err
+	}
+
+	followingRows := [][]string{{"musician_id", "name"}}
+	for _, musician := range following {
+		followingRows = append(followingRows, []string{musician.ID.String(), musician.Name})
+	}
+	err = writeCSV(zw, "following.csv", followingRows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func writeCSV(zw *zip.Writer, name string, rows [][]string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	return csv.NewWriter(w).WriteAll(rows)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+type FollowService struct {
+	repository ports.IFollowRepository
+	logger     *zap.Logger
+}
+
+func NewFollowService(repo ports.IFollowRepository, logger *zap.Logger) *FollowService {
+	return &FollowService{
+		repository: repo,
+		logger:     logger,
+	}
+}
+
+func (fs *FollowService) Follow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error {
+	err := fs.repository.Follow(ctx, userID, musicianID)
+	if err != nil {
+		fs.logger.Error("Failed to follow musician", zap.Error(err),
+			zap.String("User ID", userID.String()), zap.String("Musician ID", musicianID.String()))
+
+		return err
+	}
+
+	fs.logger.Info("Musician successfully followed",
+		zap.String("User ID", userID.String()), zap.String("Musician ID", musicianID.String()))
+
+	return nil
+}
+
+func (fs *FollowService) Unfollow(ctx context.Context, userID uuid.UUID, musicianID uuid.UUID) error {
+	err := fs.repository.Unfollow(ctx, userID, musicianID)
+	if err != nil {
+		fs.logger.Error("Failed to unfollow musician", zap.Error(err),
+			zap.String("User ID", userID.String()), zap.String("Musician ID", musicianID.String()))
+
+		return err
+	}
+
+	fs.logger.Info("Musician successfully unfollowed",
+		zap.String("User ID", userID.String()), zap.String("Musician ID", musicianID.String()))
+
+	return nil
+}
+
+func (fs *FollowService) GetFollowing(ctx context.Context, userID uuid.UUID) ([]domain.Musician, error) {
+	musicians, err := fs.repository.GetFollowing(ctx, userID)
+	if err != nil {
+		fs.logger.Error("Failed to get followed musicians", zap.Error(err), zap.String("User ID", userID.String()))
+		return nil, err
+	}
+
+	fs.logger.Info("Followed musicians successfully received", zap.String("User ID", userID.String()))
+
+	return musicians, nil
+}
+
+func (fs *FollowService) CountFollowers(ctx context.Context, musicianID uuid.UUID) (int64, error) {
+	count, err := fs.repository.CountFollowers(ctx, musicianID)
+	if err != nil {
+		fs.logger.Error("Failed to count musician followers", zap.Error(err),
+			zap.String("Musician ID", musicianID.String()))
+
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (fs *FollowService) CountFollowersByIDs(ctx context.Context,
+	musicianIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts, err := fs.repository.CountFollowersByIDs(ctx, musicianIDs)
+	if err != nil {
+		fs.logger.Error("Failed to count musicians followers", zap.Error(err),
+			zap.Int("Musicians", len(musicianIDs)))
+
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (fs *FollowService) PublishToFeed(ctx context.Context, album domain.Album) error {
+	publishedAt := time.Now()
+	if album.ReleaseDate.Valid {
+		publishedAt = album.ReleaseDate.Time
+	}
+
+	err := fs.repository.AddToFeed(ctx, album.ID, publishedAt)
+	if err != nil {
+		fs.logger.Error("Failed to add album to followers feed", zap.Error(err),
+			zap.String("Album ID", album.ID.String()))
+
+		return err
+	}
+
+	fs.logger.Info("Album successfully added to followers feed", zap.String("Album ID", album.ID.String()))
+
+	return nil
+}
+
+func (fs *FollowService) GetFeed(ctx context.Context, userID uuid.UUID) ([]domain.FeedItem, error) {
+	feed, err := fs.repository.GetFeed(ctx, userID)
+	if err != nil {
+		fs.logger.Error("Failed to get user feed", zap.Error(err), zap.String("User ID", userID.String()))
+		return nil, err
+	}
+
+	fs.logger.Info("User feed successfully received", zap.String("User ID", userID.String()))
+
+	return feed, nil
+}
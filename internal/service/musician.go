@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"io"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
@@ -11,6 +14,8 @@ import (
 	"go.uber.org/zap"
 )
 
+const maxMusicianLinkLength = 1024
+
 type MusicianService struct {
 	repository   ports.IMusicianRepository
 	imageStorage ports.IMusicianImageStorage
@@ -30,6 +35,12 @@ func NewMusicianService(repo ports.IMusicianRepository, imageStorage ports.IMusi
 }
 
 func (ms *MusicianService) Register(ctx context.Context, musician ports.MusicianServiceCreateRequest) (domain.Musician, error) {
+	if strings.Contains(musician.Name, "@") {
+		ms.logger.Error("Failed to register musician", zap.Error(ports.ErrAccountInvalidName),
+			zap.String("Musician Name", musician.Name))
+		return domain.Musician{}, ports.ErrAccountInvalidName
+	}
+
 	_, err := ms.repository.GetByName(ctx, musician.Name)
 	if err == nil {
 		ms.logger.Error("Failed to register musician", zap.Error(err), zap.String("Musician Name", musician.Name))
@@ -159,3 +170,128 @@ func (ms *MusicianService) GetByTrackID(ctx context.Context, trackID uuid.UUID)
 
 	return mus, nil
 }
+
+func (ms *MusicianService) checkUniqueName(ctx context.Context, musician domain.Musician, name null.String) error {
+	if !name.Valid || name.String == musician.Name {
+		return nil
+	}
+
+	if strings.Contains(name.String, "@") {
+		return ports.ErrAccountInvalidName
+	}
+
+	found, err := ms.repository.GetByName(ctx, name.String)
+	if err == nil && found.ID != musician.ID {
+		return ports.ErrMusicianWithSuchNameAlreadyExists
+	}
+
+	return nil
+}
+
+// validateLinks accepts absolute http(s) links, one per kind, and social
+// links only on the platform's own domain.
+func validateLinks(links []domain.MusicianLink) error {
+	seen := make(map[domain.MusicianLinkKind]bool, len(links))
+	for _, link := range links {
+		if !link.Kind.IsKnown() || seen[link.Kind] || len(link.URL) > maxMusicianLinkLength {
+			return ports.ErrMusicianInvalidLink
+		}
+		seen[link.Kind] = true
+
+		u, err := url.Parse(link.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+			return ports.ErrMusicianInvalidLink
+		}
+
+		if !link.Kind.AllowsHost(u.Hostname()) {
+			return ports.ErrMusicianInvalidLink
+		}
+	}
+
+	return nil
+}
+
+func (ms *MusicianService) UpdateProfile(ctx context.Context, musicianID uuid.UUID,
+	req ports.MusicianServiceUpdateRequest) (domain.Musician, error) {
+	err := validateLinks(req.Links)
+	if err != nil {
+		ms.logger.Error("Failed to update musician", zap.Error(err), zap.String("Musician ID", musicianID.String()))
+		return domain.Musician{}, err
+	}
+
+	musician, err := ms.repository.GetByID(ctx, musicianID)
+	if err != nil {
+		ms.logger.Error("Failed to update musician", zap.Error(err), zap.String("Musician ID", musicianID.String()))
+		return domain.Musician{}, err
+	}
+
+	err = ms.checkUniqueName(ctx, musician, req.Name)
+	if err != nil {
+		ms.logger.Error("Failed to update musician", zap.Error(err), zap.String("Musician ID", musicianID.String()),
+			zap.String("Musician Name", req.Name.String))
+		return domain.Musician{}, err
+	}
+
+	if req.Name.Valid {
+		musician.Name = req.Name.String
+	}
+
+	if req.Country.Valid {
+		musician.Country = req.Country.String
+	}
+
+	if req.Description.Valid {
+		musician.Description = req.Description.String
+	}
+
+	musician, err = ms.repository.UpdateProfile(ctx, musician, req.Links)
+	if err != nil {
+		ms.logger.Error("Failed to update musician", zap.Error(err), zap.String("Musician ID", musicianID.String()))
+		return domain.Musician{}, err
+	}
+
+	ms.logger.Info("Musician profile successfully updated", zap.String("Musician ID", musicianID.String()))
+
+	return musician, nil
+}
+
+func (ms *MusicianService) GetLinks(ctx context.Context, musicianID uuid.UUID) ([]domain.MusicianLink, error) {
+	links, err := ms.repository.GetLinks(ctx, musicianID)
+	if err != nil {
+		ms.logger.Error("Failed to get musician links", zap.Error(err), zap.String("Musician ID", musicianID.String()))
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (ms *MusicianService) SetVerified(ctx context.Context, musicianID uuid.UUID,
+	verified bool) (domain.Musician, error) {
+	musician, err := ms.repository.GetByID(ctx, musicianID)
+	if err != nil {
+		ms.logger.Error("Failed to set musician verification", zap.Error(err),
+			zap.String("Musician ID", musicianID.String()))
+		return domain.Musician{}, err
+	}
+
+	if verified == musician.IsVerified() {
+		return musician, nil
+	}
+
+	verifiedAt := null.Time{}
+	if verified {
+		verifiedAt = null.TimeFrom(time.Now())
+	}
+
+	musician, err = ms.repository.SetVerifiedAt(ctx, musicianID, verifiedAt)
+	if err != nil {
+		ms.logger.Error("Failed to set musician verification", zap.Error(err),
+			zap.String("Musician ID", musicianID.String()))
+		return domain.Musician{}, err
+	}
+
+	ms.logger.Info("Musician verification successfully changed", zap.String("Musician ID", musicianID.String()),
+		zap.Bool("Verified", verified))
+
+	return musician, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+// oidcStateTTL is how long the user may stay at the identity provider.
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCService logs accounts in through external OpenID Connect providers.
+// The provider authenticates the person, so local two-factor authentication
+// is not asked for again.
+type OIDCService struct {
+	providers          map[string]ports.IOIDCProvider
+	stateStorage       ports.IOIDCStateStorage
+	identityRepository ports.IExternalIdentityRepository
+	accountRepository  ports.IAccountRepository
+	userRepository     ports.IUserRepository
+	tokenProvider      ports.ITokenProvider
+	logger             *zap.Logger
+}
+
+func NewOIDCService(providers map[string]ports.IOIDCProvider, stateStorage ports.IOIDCStateStorage,
+	identityRepo ports.IExternalIdentityRepository, accountRepo ports.IAccountRepository,
+	userRepo ports.IUserRepository, tokenProvider ports.ITokenProvider, logger *zap.Logger) *OIDCService {
+	return &OIDCService{
+		providers:          providers,
+		stateStorage:       stateStorage,
+		identityRepository: identityRepo,
+		accountRepository:  accountRepo,
+		userRepository:     userRepo,
+		tokenProvider:      tokenProvider,
+		logger:             logger,
+	}
+}
+
+func randomURLToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Begin returns the authorization URL the user has to be sent to.
+func (o *OIDCService) Begin(ctx context.Context, req ports.BeginOIDCRequest) (string, error) {
+	provider, ok := o.providers[req.Provider]
+	if !ok {
+		return "", ports.ErrOIDCProviderNotFound
+	}
+
+	var tokens [3]string
+	for i := range tokens {
+		token, err := randomURLToken()
+		if err != nil {
+			o.logger.Error("Failed to generate oidc state", zap.Error(err))
+			return "", ports.ErrInternalOIDCProvider
+		}
+		tokens[i] = token
+	}
+	state, nonce, codeVerifier := tokens[0], tokens[1], tokens[2]
+
+	err := o.stateStorage.Save(ctx, state, domain.OIDCLoginState{
+		Provider:     req.Provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		AccountID:    req.LinkAccountID,
+	}, oidcStateTTL)
+	if err != nil {
+		o.logger.Error("Failed to save oidc state", zap.Error(err), zap.String("Provider", req.Provider))
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		o.logger.Error("Failed to build authorization url", zap.Error(err), zap.String("Provider", req.Provider))
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// Complete finishes the flow started by Begin. A known identity logs into its
+// account. An unknown one is linked to the account that started a link flow,
+// or to the account whose verified email the provider also reports as
+// verified; otherwise it is rejected, accounts are never created here.
+func (o *OIDCService) Complete(ctx context.Context, req ports.CompleteOIDCRequest) (domain.TokenPair, error) {
+	provider, ok := o.providers[req.Provider]
+	if !ok {
+		return domain.TokenPair{}, ports.ErrOIDCProviderNotFound
+	}
+
+	loginState, err := o.stateStorage.Take(ctx, req.State)
+	if err != nil {
+		o.logger.Error("Failed to take oidc state", zap.Error(err), zap.String("Provider", req.Provider))
+		return domain.TokenPair{}, err
+	}
+
+	if loginState.Provider != req.Provider {
+		return domain.TokenPair{}, ports.ErrOIDCInvalidState
+	}
+
+	claims, err := provider.Exchange(ctx, req.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		o.logger.Error("Failed to exchange authorization code", zap.Error(err), zap.String("Provider", req.Provider))
+		return domain.TokenPair{}, err
+	}
+
+	account, err := o.resolveAccount(ctx, req.Provider, claims, loginState.AccountID)
+	if err != nil {
+		o.logger.Error("Failed to resolve external identity", zap.Error(err),
+			zap.String("Provider", req.Provider), zap.String("Subject", claims.Subject))
+		return domain.TokenPair{}, err
+	}
+
+	return issueSession(ctx, o.tokenProvider, o.userRepository, account, req.Metadata, o.logger)
+}
+
+func (o *OIDCService) resolveAccount(ctx context.Context, provider string, claims domain.OIDCClaims,
+	linkAccountID uuid.UUID) (domain.Account, error) {
+	identity, err := o.identityRepository.Get(ctx, provider, claims.Subject)
+	if err == nil {
+		if linkAccountID != uuid.Nil && identity.AccountID != linkAccountID {
+			return domain.Account{}, ports.ErrOIDCIdentityAlreadyLinked
+		}
+
+		return o.accountRepository.GetByID(ctx, identity.AccountID)
+	} else if !errors.Is(err, ports.ErrOIDCIdentityNotFound) {
+		return domain.Account{}, err
+	}
+
+	var account domain.Account
+	switch {
+	case linkAccountID != uuid.Nil:
+		account, err = o.accountRepository.GetByID(ctx, linkAccountID)
+		if err != nil {
+			return domain.Account{}, err
+		}
+	case claims.EmailVerified && claims.Email != "":
+		account, err = o.accountRepository.GetByEmail(ctx, claims.Email)
+		if errors.Is(err, ports.ErrAccountNotFound) || (err == nil && !account.EmailVerified) {
+			return domain.Account{}, ports.ErrOIDCIdentityNotLinked
+		} else if err != nil {
+			return domain.Account{}, err
+		}
+	default:
+		return domain.Account{}, ports.ErrOIDCIdentityNotLinked
+	}
+
+	err = o.identityRepository.Create(ctx, domain.ExternalIdentity{
+		Provider:  provider,
+		Subject:   claims.Subject,
+		AccountID: account.ID,
+		Email:     claims.Email,
+	})
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	o.logger.Info("External identity linked", zap.String("Provider", provider),
+		zap.String("Subject", claims.Subject), zap.String("Account ID", account.ID.String()))
+
+	return account, nil
+}
+
+func (o *OIDCService) GetIdentities(ctx context.Context, accountID uuid.UUID) ([]domain.ExternalIdentity, error) {
+	identities, err := o.identityRepository.GetByAccountID(ctx, accountID)
+	if err != nil {
+		o.logger.Error("Failed to get external identities", zap.Error(err), zap.String("Account ID", accountID.String()))
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (o *OIDCService) Unlink(ctx context.Context, accountID uuid.UUID, provider string) error {
+	err := o.identityRepository.Delete(ctx, accountID, provider)
+	if err != nil {
+		o.logger.Error("Failed to unlink external identity", zap.Error(err),
+			zap.String("Account ID", accountID.String()), zap.String("Provider", provider))
+		return err
+	}
+
+	o.logger.Info("External identity unlinked", zap.String("Account ID", accountID.String()),
+		zap.String("Provider", provider))
+
+	return nil
+}
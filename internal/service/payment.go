@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+type PaymentService struct {
+	gateway             ports.IPaymentGateway
+	orderRepository     ports.IOrderRepository
+	subscriptionService ports.ISubscriptionService
+	logger              *zap.Logger
+}
+
+func NewPaymentService(gateway ports.IPaymentGateway, orderRepo ports.IOrderRepository,
+	subscriptionService ports.ISubscriptionService, logger *zap.Logger) *PaymentService {
+	return &PaymentService{
+		gateway:             gateway,
+		orderRepository:     orderRepo,
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// Checkout creates an order for one period of the plan and a payment intent
+// for it. A repeated request with the same idempotency key returns the order
+// of the first one; an intent that failed to be created then is retried.
+func (ps *PaymentService) Checkout(ctx context.Context, req ports.CheckoutRequest) (domain.Order, error) {
+	if req.IdempotencyKey == "" {
+		return domain.Order{}, ports.ErrIdempotencyKeyEmpty
+	}
+
+	plan, err := ps.subscriptionService.GetPlan(ctx, req.PlanID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	order, err := ps.findOrCreateOrder(ctx, req, plan)
+	if err != nil {
+		ps.logger.Error("Failed to create order", zap.Error(err), zap.String("User ID", req.UserID.String()))
+		return domain.Order{}, err
+	}
+
+	if order.IntentID != "" {
+		return order, nil
+	}
+
+	intent, err := ps.gateway.CreateIntent(ctx, domain.PaymentPayload{
+		UserID:     order.UserID,
+		PaymentSum: order.Amount,
+	}, "order-"+order.ID.String())
+	if err != nil {
+		ps.logger.Error("Failed to create payment intent", zap.Error(err), zap.String("Order ID", order.ID.String()))
+		return domain.Order{}, err
+	}
+
+	err = ps.orderRepository.SetIntent(ctx, order.ID, intent)
+	if err != nil {
+		ps.logger.Error("Failed to save payment intent", zap.Error(err), zap.String("Order ID", order.ID.String()))
+		return domain.Order{}, err
+	}
+
+	order.IntentID = intent.ID
+	order.CheckoutURL = intent.CheckoutURL
+
+	ps.logger.Info("Order successfully created", zap.String("Order ID", order.ID.String()),
+		zap.String("User ID", order.UserID.String()), zap.String("Plan ID", order.PlanID.String()))
+
+	return order, nil
+}
+
+func (ps *PaymentService) findOrCreateOrder(ctx context.Context, req ports.CheckoutRequest,
+	plan domain.SubscriptionPlan) (domain.Order, error) {
+	order, err := ps.orderRepository.GetByIdempotencyKey(ctx, req.UserID, req.IdempotencyKey)
+	if errors.Is(err, ports.ErrOrderNotFound) {
+		order, err = ps.orderRepository.Create(ctx, domain.Order{
+			ID:             uuid.New(),
+			UserID:         req.UserID,
+			PlanID:         plan.ID,
+			Amount:         plan.Price,
+			Status:         domain.OrderPending,
+			IdempotencyKey: req.IdempotencyKey,
+		})
+		// A concurrent request with the same key won the race.
+		if errors.Is(err, ports.ErrOrderDuplicate) {
+			order, err = ps.orderRepository.GetByIdempotencyKey(ctx, req.UserID, req.IdempotencyKey)
+		}
+	}
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	if order.PlanID != plan.ID {
+		return domain.Order{}, ports.ErrPaymentIdempotencyConflict
+	}
+
+	return order, nil
+}
+
+func (ps *PaymentService) GetOrder(ctx context.Context, userID uuid.UUID, orderID uuid.UUID) (domain.Order, error) {
+	order, err := ps.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		ps.logger.Error("Failed to get order", zap.Error(err), zap.String("Order ID", orderID.String()))
+		return domain.Order{}, err
+	}
+
+	if order.UserID != userID {
+		return domain.Order{}, ports.ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+// HandleWebhook applies a payment event to its order. Gateways deliver events
+// at least once, so every event only takes effect with the order status
+// transition it belongs to.
+func (ps *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := ps.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		ps.logger.Error("Failed to verify payment webhook", zap.Error(err))
+		return err
+	}
+
+	order, err := ps.orderRepository.GetByIntentID(ctx, event.IntentID)
+	if errors.Is(err, ports.ErrOrderNotFound) {
+		// Not ours, e.g. created on the gateway dashboard. Acknowledged so
+		// that the gateway stops retrying.
+		ps.logger.Warn("Payment event for unknown intent", zap.String("Event ID", event.ID),
+			zap.String("Intent ID", event.IntentID))
+		return nil
+	} else if err != nil {
+		ps.logger.Error("Failed to get order", zap.Error(err), zap.String("Intent ID", event.IntentID))
+		return err
+	}
+
+	switch event.Type {
+	case domain.PaymentEventSucceeded:
+		err = ps.fulfill(ctx, order)
+	case domain.PaymentEventFailed:
+		err = ps.orderRepository.UpdateStatus(ctx, order.ID, domain.OrderPending, domain.OrderFailed)
+	case domain.PaymentEventRefunded:
+		err = ps.refunded(ctx, order)
+	default:
+		ps.logger.Warn("Unknown payment event", zap.String("Event ID", event.ID),
+			zap.String("Event type", string(event.Type)))
+		return nil
+	}
+
+	if errors.Is(err, ports.ErrOrderStatusChanged) {
+		ps.logger.Info("Payment event already applied", zap.String("Event ID", event.ID),
+			zap.String("Order ID", order.ID.String()))
+		return nil
+	} else if err != nil {
+		ps.logger.Error("Failed to apply payment event", zap.Error(err), zap.String("Event ID", event.ID),
+			zap.String("Order ID", order.ID.String()))
+		return err
+	}
+
+	ps.logger.Info("Payment event applied", zap.String("Event ID", event.ID),
+		zap.String("Event type", string(event.Type)), zap.String("Order ID", order.ID.String()))
+
+	return nil
+}
+
+// fulfill claims the order by marking it paid before activating the
+// subscription, and gives the claim back if the activation fails so that the
+// redelivered event can try again.
+func (ps *PaymentService) fulfill(ctx context.Context, order domain.Order) error {
+	err := ps.orderRepository.UpdateStatus(ctx, order.ID, domain.OrderPending, domain.OrderPaid)
+	if err != nil {
+		return err
+	}
+
+	_, err = ps.subscriptionService.Activate(ctx, order.UserID, order.PlanID)
+	if err != nil {
+		errRelease := ps.orderRepository.UpdateStatus(ctx, order.ID, domain.OrderPaid, domain.OrderPending)
+		if errRelease != nil {
+			ps.logger.Error("Failed to release order after failed activation", zap.Error(errRelease),
+				zap.String("Order ID", order.ID.String()))
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (ps *PaymentService) refunded(ctx context.Context, order domain.Order) error {
+	err := ps.orderRepository.UpdateStatus(ctx, order.ID, domain.OrderPaid, domain.OrderRefunded)
+	if err != nil {
+		return err
+	}
+
+	return ps.subscriptionService.Revoke(ctx, order.UserID, order.PlanID)
+}
+
+func (ps *PaymentService) Refund(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	order, err := ps.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		ps.logger.Error("Failed to get order", zap.Error(err), zap.String("Order ID", orderID.String()))
+		return domain.Order{}, err
+	}
+
+	if order.Status != domain.OrderPaid {
+		return domain.Order{}, ports.ErrOrderNotRefundable
+	}
+
+	_, err = ps.gateway.Refund(ctx, order.IntentID, order.Amount, "refund-"+order.ID.String())
+	if err != nil {
+		ps.logger.Error("Failed to refund payment", zap.Error(err), zap.String("Order ID", order.ID.String()))
+		return domain.Order{}, err
+	}
+
+	// The gateway reports the refund with a webhook too, whichever comes
+	// first changes the order.
+	err = ps.refunded(ctx, order)
+	if err != nil && !errors.Is(err, ports.ErrOrderStatusChanged) {
+		ps.logger.Error("Failed to apply refund", zap.Error(err), zap.String("Order ID", order.ID.String()))
+		return domain.Order{}, err
+	}
+
+	ps.logger.Info("Order successfully refunded", zap.String("Order ID", order.ID.String()))
+
+	order.Status = domain.OrderRefunded
+	return order, nil
+}
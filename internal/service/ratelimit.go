@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+// RateLimitService fails open: when the storage is unavailable the request is
+// let through, so an outage of the limiter does not take the API down.
+type RateLimitService struct {
+	storage ports.IRateLimitStorage
+	logger  *zap.Logger
+}
+
+func NewRateLimitService(storage ports.IRateLimitStorage, logger *zap.Logger) *RateLimitService {
+	return &RateLimitService{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (rs *RateLimitService) Allow(ctx context.Context, key string, limit domain.RateLimit) error {
+	if !limit.Enabled() {
+		return nil
+	}
+
+	now := time.Now()
+	allowed, oldest, err := rs.storage.Hit(ctx, key, now, limit.Window, limit.Requests)
+	if err != nil {
+		rs.logger.Error("Failed to check rate limit", zap.Error(err), zap.String("Key", key))
+		return nil
+	}
+
+	if allowed {
+		return nil
+	}
+
+	retryAfter := oldest.Add(limit.Window).Sub(now)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+
+	rs.logger.Warn("Rate limit exceeded", zap.String("Key", key), zap.Duration("Retry after", retryAfter))
+
+	return &ports.RateLimitError{RetryAfter: retryAfter}
+}
+
+func (rs *RateLimitService) CheckLockout(ctx context.Context, key string) error {
+	ttl, err := rs.storage.LockTTL(ctx, key)
+	if err != nil {
+		rs.logger.Error("Failed to check lockout", zap.Error(err), zap.String("Key", key))
+		return nil
+	}
+
+	if ttl > 0 {
+		return &ports.RateLimitError{RetryAfter: ttl}
+	}
+
+	return nil
+}
+
+func (rs *RateLimitService) RegisterFailure(ctx context.Context, key string, policy domain.LockoutPolicy) error {
+	if !policy.Enabled() {
+		return nil
+	}
+
+	failures, err := rs.storage.AddFailure(ctx, key, policy.FailureWindow)
+	if err != nil {
+		rs.logger.Error("Failed to register failure", zap.Error(err), zap.String("Key", key))
+		return err
+	}
+
+	lockout := policy.Duration(failures)
+	if lockout == 0 {
+		return nil
+	}
+
+	err = rs.storage.Lock(ctx, key, lockout)
+	if err != nil {
+		rs.logger.Error("Failed to lock out", zap.Error(err), zap.String("Key", key))
+		return err
+	}
+
+	rs.logger.Warn("Locked out after repeated failures", zap.String("Key", key),
+		zap.Int("Failures", failures), zap.Duration("Lockout", lockout))
+
+	return nil
+}
+
+func (rs *RateLimitService) Reset(ctx context.Context, key string) error {
+	err := rs.storage.ResetFailures(ctx, key)
+	if err != nil {
+		rs.logger.Error("Failed to reset failures", zap.Error(err), zap.String("Key", key))
+		return err
+	}
+
+	return nil
+}
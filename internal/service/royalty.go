@@ -0,0 +1,216 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMinPlaySeconds     = 30
+	defaultArtistSharePercent = 70
+)
+
+// RoyaltyServiceConfig sets which plays count and how much of the
+// subscription revenue goes to musicians.
+type RoyaltyServiceConfig struct {
+	MinPlaySeconds     int
+	ArtistSharePercent int64
+}
+
+type RoyaltyService struct {
+	repository ports.IRoyaltyRepository
+	cfg        RoyaltyServiceConfig
+	logger     *zap.Logger
+}
+
+func NewRoyaltyService(repo ports.IRoyaltyRepository, cfg RoyaltyServiceConfig, logger *zap.Logger) *RoyaltyService {
+	if cfg.MinPlaySeconds <= 0 {
+		cfg.MinPlaySeconds = defaultMinPlaySeconds
+	}
+
+	if cfg.ArtistSharePercent <= 0 || cfg.ArtistSharePercent > 100 {
+		cfg.ArtistSharePercent = defaultArtistSharePercent
+	}
+
+	return &RoyaltyService{
+		repository: repo,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// AllocateDue allocates every finished month after the last allocated one.
+// The first run only allocates the previous month. A month allocated
+// concurrently by another instance is skipped.
+func (rs *RoyaltyService) AllocateDue(ctx context.Context) ([]domain.RoyaltyPeriod, error) {
+	current := domain.RoyaltyPeriodOf(time.Now())
+
+	next := domain.RoyaltyPeriodOf(current.Start.AddDate(0, -1, 0))
+	last, err := rs.repository.GetLastPeriod(ctx)
+	if err == nil {
+		next = domain.RoyaltyPeriodOf(last.End)
+	} else if !errors.Is(err, ports.ErrRoyaltyPeriodNotFound) {
+		rs.logger.Error("Failed to get last royalty period", zap.Error(err))
+		return nil, err
+	}
+
+	var allocated []domain.RoyaltyPeriod
+	for next.Start.Before(current.Start) {
+		period, err := rs.allocate(ctx, next)
+		if errors.Is(err, ports.ErrRoyaltyPeriodAllocated) {
+			rs.logger.Info("Royalty period already allocated", zap.Time("Period", next.Start))
+		} else if err != nil {
+			return allocated, err
+		} else {
+			allocated = append(allocated, period)
+		}
+
+		next = domain.RoyaltyPeriodOf(next.End)
+	}
+
+	return allocated, nil
+}
+
+func (rs *RoyaltyService) allocate(ctx context.Context, period domain.RoyaltyPeriod) (domain.RoyaltyPeriod, error) {
+	revenue, err := rs.repository.GetRevenue(ctx, period.Start, period.End)
+	if err != nil {
+		rs.logger.Error("Failed to get royalty period revenue", zap.Error(err), zap.Time("Period", period.Start))
+		return domain.RoyaltyPeriod{}, err
+	}
+
+	trackPlays, err := rs.repository.GetTrackPlays(ctx, period.Start, period.End, rs.cfg.MinPlaySeconds)
+	if err != nil {
+		rs.logger.Error("Failed to get royalty period plays", zap.Error(err), zap.Time("Period", period.Start))
+		return domain.RoyaltyPeriod{}, err
+	}
+
+	period.Revenue = revenue
+	period.Pool = revenue * rs.cfg.ArtistSharePercent / 100
+	for _, track := range trackPlays {
+		period.Plays += track.Plays
+	}
+
+	entries := splitRoyalties(period, trackPlays)
+	if period.Plays == 0 && period.Pool > 0 {
+		rs.logger.Warn("Royalty period has no qualifying plays, pool is not allocated",
+			zap.Time("Period", period.Start), zap.Int64("Pool", period.Pool))
+	}
+
+	err = rs.repository.CreatePeriod(ctx, period, entries)
+	if err != nil {
+		if !errors.Is(err, ports.ErrRoyaltyPeriodAllocated) {
+			rs.logger.Error("Failed to store royalty period", zap.Error(err), zap.Time("Period", period.Start))
+		}
+		return domain.RoyaltyPeriod{}, err
+	}
+
+	rs.logger.Info("Royalty period successfully allocated", zap.Time("Period", period.Start),
+		zap.Int64("Pool", period.Pool), zap.Int64("Plays", period.Plays), zap.Int("Musicians", len(entries)))
+
+	return period, nil
+}
+
+// splitRoyalties divides the pool between tracks by their plays and the
+// share of each track equally between its musicians. Amounts are whole
+// minor units; what rounding down leaves over goes to the largest remainders
+// first, so the entries always add up to the pool exactly.
+func splitRoyalties(period domain.RoyaltyPeriod, trackPlays []domain.TrackPlays) []domain.RoyaltyEntry {
+	if period.Plays == 0 {
+		return nil
+	}
+
+	tracks := make([]domain.TrackPlays, len(trackPlays))
+	copy(tracks, trackPlays)
+	amounts := make([]int64, len(tracks))
+	remainders := make([]int64, len(tracks))
+	var distributed int64
+	for i, track := range tracks {
+		amounts[i] = period.Pool * track.Plays / period.Plays
+		remainders[i] = period.Pool * track.Plays % period.Plays
+		distributed += amounts[i]
+	}
+
+	order := make([]int, len(tracks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if remainders[order[a]] != remainders[order[b]] {
+			return remainders[order[a]] > remainders[order[b]]
+		}
+		return bytes.Compare(tracks[order[a]].TrackID[:], tracks[order[b]].TrackID[:]) < 0
+	})
+	for i := 0; distributed < period.Pool; i++ {
+		amounts[order[i]]++
+		distributed++
+	}
+
+	byMusician := make(map[uuid.UUID]*domain.RoyaltyEntry)
+	for i, track := range tracks {
+		musicians := make([]uuid.UUID, len(track.MusicianIDs))
+		copy(musicians, track.MusicianIDs)
+		sort.Slice(musicians, func(a, b int) bool {
+			return bytes.Compare(musicians[a][:], musicians[b][:]) < 0
+		})
+
+		count := int64(len(musicians))
+		for j, musicianID := range musicians {
+			share := amounts[i] / count
+			if int64(j) < amounts[i]%count {
+				share++
+			}
+
+			entry, ok := byMusician[musicianID]
+			if !ok {
+				entry = &domain.RoyaltyEntry{
+					ID:          uuid.New(),
+					MusicianID:  musicianID,
+					PeriodStart: period.Start,
+				}
+				byMusician[musicianID] = entry
+			}
+			entry.Plays += track.Plays
+			entry.Amount += share
+		}
+	}
+
+	entries := make([]domain.RoyaltyEntry, 0, len(byMusician))
+	for _, entry := range byMusician {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return bytes.Compare(entries[a].MusicianID[:], entries[b].MusicianID[:]) < 0
+	})
+
+	return entries
+}
+
+// GetStatements returns the monthly statements of the musician for periods
+// starting in [from, to), newest first. A zero to means up to now.
+func (rs *RoyaltyService) GetStatements(ctx context.Context, musicianID uuid.UUID, from time.Time,
+	to time.Time) ([]domain.RoyaltyStatement, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	if !from.Before(to) {
+		return nil, ports.ErrRoyaltyStatementRange
+	}
+
+	statements, err := rs.repository.GetStatements(ctx, musicianID, from, to)
+	if err != nil {
+		rs.logger.Error("Failed to get royalty statements", zap.Error(err),
+			zap.String("Musician ID", musicianID.String()))
+		return nil, err
+	}
+
+	return statements, nil
+}
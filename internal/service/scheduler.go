@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+const defaultReleaseInterval = time.Minute
+
+type ReleaseScheduler struct {
+	albumService ports.IAlbumService
+	interval     time.Duration
+	logger       *zap.Logger
+}
+
+func NewReleaseScheduler(albumService ports.IAlbumService, interval time.Duration, logger *zap.Logger) *ReleaseScheduler {
+	if interval <= 0 {
+		interval = defaultReleaseInterval
+	}
+
+	return &ReleaseScheduler{
+		albumService: albumService,
+		interval:     interval,
+		logger:       logger,
+	}
+}
+
+// Run publishes due albums every interval until ctx is done. Running it on
+// several instances is safe: the repository holds an advisory lock per pass.
+func (rs *ReleaseScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	rs.logger.Info("Release scheduler started", zap.Duration("Interval", rs.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			rs.logger.Info("Release scheduler stopped")
+			return
+		case <-ticker.C:
+			_, _ = rs.albumService.PublishScheduled(ctx)
+		}
+	}
+}
+
+const defaultExpiryInterval = time.Hour
+
+type SubscriptionExpiryScheduler struct {
+	subscriptionService ports.ISubscriptionService
+	interval            time.Duration
+	logger              *zap.Logger
+}
+
+func NewSubscriptionExpiryScheduler(subscriptionService ports.ISubscriptionService, interval time.Duration,
+	logger *zap.Logger) *SubscriptionExpiryScheduler {
+	if interval <= 0 {
+		interval = defaultExpiryInterval
+	}
+
+	return &SubscriptionExpiryScheduler{
+		subscriptionService: subscriptionService,
+		interval:            interval,
+		logger:              logger,
+	}
+}
+
+// Run marks subscriptions past their expiration date every interval until ctx
+// is done. Access checks don't wait for it, they compare the dates themselves.
+func (es *SubscriptionExpiryScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(es.interval)
+	defer ticker.Stop()
+
+	es.logger.Info("Subscription expiry scheduler started", zap.Duration("Interval", es.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			es.logger.Info("Subscription expiry scheduler stopped")
+			return
+		case <-ticker.C:
+			_, _ = es.subscriptionService.ExpireDue(ctx)
+		}
+	}
+}
+
+const defaultRoyaltyInterval = time.Hour
+
+type RoyaltyScheduler struct {
+	royaltyService ports.IRoyaltyService
+	interval       time.Duration
+	logger         *zap.Logger
+}
+
+func NewRoyaltyScheduler(royaltyService ports.IRoyaltyService, interval time.Duration,
+	logger *zap.Logger) *RoyaltyScheduler {
+	if interval <= 0 {
+		interval = defaultRoyaltyInterval
+	}
+
+	return &RoyaltyScheduler{
+		royaltyService: royaltyService,
+		interval:       interval,
+		logger:         logger,
+	}
+}
+
+// Run allocates the royalties of finished months every interval until ctx is
+// done. A month is allocated once, so most passes find nothing to do.
+func (rs *RoyaltyScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	rs.logger.Info("Royalty scheduler started", zap.Duration("Interval", rs.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			rs.logger.Info("Royalty scheduler stopped")
+			return
+		case <-ticker.C:
+			_, _ = rs.royaltyService.AllocateDue(ctx)
+		}
+	}
+}
+
+const defaultPurgeInterval = time.Hour
+
+type UserPurgeScheduler struct {
+	userService ports.IUserService
+	interval    time.Duration
+	logger      *zap.Logger
+}
+
+func NewUserPurgeScheduler(userService ports.IUserService, interval time.Duration,
+	logger *zap.Logger) *UserPurgeScheduler {
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	return &UserPurgeScheduler{
+		userService: userService,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Run removes the accounts whose deletion grace period is over every interval
+// until ctx is done.
+func (ps *UserPurgeScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(ps.interval)
+	defer ticker.Stop()
+
+	ps.logger.Info("User purge scheduler started", zap.Duration("Interval", ps.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			ps.logger.Info("User purge scheduler stopped")
+			return
+		case <-ticker.C:
+			_, _ = ps.userService.PurgeDeleted(ctx)
+		}
+	}
+}
+
+const defaultExportInterval = 30 * time.Second
+
+type DataExportScheduler struct {
+	exportService ports.IDataExportService
+	interval      time.Duration
+	logger        *zap.Logger
+}
+
+func NewDataExportScheduler(exportService ports.IDataExportService, interval time.Duration,
+	logger *zap.Logger) *DataExportScheduler {
+	if interval <= 0 {
+		interval = defaultExportInterval
+	}
+
+	return &DataExportScheduler{
+		exportService: exportService,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Run builds the requested data exports every interval until ctx is done.
+// Each instance claims its own exports, so several can run side by side.
+func (es *DataExportScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(es.interval)
+	defer ticker.Stop()
+
+	es.logger.Info("Data export scheduler started", zap.Duration("Interval", es.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			es.logger.Info("Data export scheduler stopped")
+			return
+		case <-ticker.C:
+			_, _ = es.exportService.ProcessPending(ctx)
+		}
+	}
+}
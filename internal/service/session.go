@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/util"
+	"go.uber.org/zap"
+)
+
+func accountRole(ctx context.Context, userRepository ports.IUserRepository, account domain.Account) (int, error) {
+	if account.Kind == domain.AccountKindMusician {
+		return domain.MusicianRole, nil
+	}
+
+	user, err := userRepository.GetByID(ctx, account.ID)
+	if err != nil {
+		return 0, util.WrapError(ports.ErrInternalAuthRepo, err)
+	}
+
+	return user.Role, nil
+}
+
+// issueSession opens a session for an account that has already been
+// authenticated, whichever way that happened.
+func issueSession(ctx context.Context, tokenProvider ports.ITokenProvider, userRepository ports.IUserRepository,
+	account domain.Account, metadata domain.SessionMetadata, logger *zap.Logger) (domain.TokenPair, error) {
+	role, err := accountRole(ctx, userRepository, account)
+	if err != nil {
+		logger.Error("Failed to get account role", zap.Error(err), zap.String("Account ID", account.ID.String()))
+		return domain.TokenPair{}, err
+	}
+
+	payload := domain.Payload{
+		UserID: account.ID,
+		Role:   role,
+	}
+
+	stringRole := domain.RoleName(payload.Role)
+
+	tokens, err := tokenProvider.NewSession(ctx, payload, metadata)
+	if err != nil {
+		logger.Error("Failed to create new session for user", zap.Error(err),
+			zap.String("User ID", payload.UserID.String()), zap.String("User Role", stringRole))
+		return domain.TokenPair{}, err
+	}
+
+	logger.Info("User successfully authorized",
+		zap.String("User ID", payload.UserID.String()), zap.String("User Role", stringRole))
+
+	return tokens, nil
+}
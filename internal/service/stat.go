@@ -27,8 +27,8 @@ func NewStatService(repo ports.IStatRepository, genreService ports.IGenreService
 	}
 }
 
-func (ss *StatService) Add(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error {
-	err := ss.repository.Add(ctx, uuid.New(), userID, trackID)
+func (ss *StatService) Add(ctx context.Context, userID uuid.UUID, trackID uuid.UUID, listenedSeconds int) error {
+	err := ss.repository.Add(ctx, uuid.New(), userID, trackID, listenedSeconds)
 	if err != nil {
 		ss.logger.Error("Failed to add track to statistics", zap.Error(err),
 			zap.String("User ID", userID.String()), zap.String("Track ID", trackID.String()))
@@ -127,3 +127,13 @@ func (ss *StatService) FormReport(ctx context.Context, userID uuid.UUID) (domain
 
 	return listenReport, nil
 }
+
+func (ss *StatService) GetHistory(ctx context.Context, userID uuid.UUID) ([]domain.ListenRecord, error) {
+	records, err := ss.repository.GetHistory(ctx, userID)
+	if err != nil {
+		ss.logger.Error("Failed to get listening history", zap.Error(err), zap.String("User ID", userID.String()))
+		return nil, err
+	}
+
+	return records, nil
+}
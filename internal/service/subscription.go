@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"go.uber.org/zap"
+)
+
+type SubscriptionService struct {
+	repository ports.ISubscriptionRepository
+	logger     *zap.Logger
+}
+
+func NewSubscriptionService(repo ports.ISubscriptionRepository, logger *zap.Logger) *SubscriptionService {
+	return &SubscriptionService{
+		repository: repo,
+		logger:     logger,
+	}
+}
+
+func (ss *SubscriptionService) GetPlans(ctx context.Context) ([]domain.SubscriptionPlan, error) {
+	plans, err := ss.repository.GetPlans(ctx)
+	if err != nil {
+		ss.logger.Error("Failed to get subscription plans", zap.Error(err))
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func (ss *SubscriptionService) GetPlan(ctx context.Context, planID uuid.UUID) (domain.SubscriptionPlan, error) {
+	plan, err := ss.repository.GetPlanByID(ctx, planID)
+	if err != nil {
+		ss.logger.Error("Failed to get subscription plan", zap.Error(err), zap.String("Plan ID", planID.String()))
+		return domain.SubscriptionPlan{}, err
+	}
+
+	return plan, nil
+}
+
+// current returns the subscription that is in effect at now. One that is past
+// its expiration date but not yet marked expired counts as not found.
+func (ss *SubscriptionService) current(ctx context.Context, userID uuid.UUID, now time.Time) (domain.Subscription, error) {
+	subscription, err := ss.repository.GetCurrent(ctx, userID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	if !subscription.IsActive(now) {
+		return subscription, ports.ErrSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
+
+func (ss *SubscriptionService) GetCurrent(ctx context.Context, userID uuid.UUID) (domain.Subscription, error) {
+	subscription, err := ss.current(ctx, userID, time.Now())
+	if err != nil {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	return subscription, nil
+}
+
+func (ss *SubscriptionService) Start(ctx context.Context, userID uuid.UUID, planID uuid.UUID) (domain.Subscription, error) {
+	plan, err := ss.GetPlan(ctx, planID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	now := time.Now()
+	previous, err := ss.current(ctx, userID, now)
+	if err == nil {
+		ss.logger.Error("Failed to start subscription", zap.Error(ports.ErrSubscriptionAlreadyActive),
+			zap.String("User ID", userID.String()))
+		return domain.Subscription{}, ports.ErrSubscriptionAlreadyActive
+	} else if !errors.Is(err, ports.ErrSubscriptionNotFound) {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	return ss.replace(ctx, userID, previous, plan, now)
+}
+
+// replace starts a new subscription of the plan. previous is what current
+// returned: a subscription that ran out but wasn't marked expired yet has to
+// be marked now, since only one unexpired subscription per user is allowed.
+func (ss *SubscriptionService) replace(ctx context.Context, userID uuid.UUID, previous domain.Subscription,
+	plan domain.SubscriptionPlan, now time.Time) (domain.Subscription, error) {
+	if previous.ID != uuid.Nil {
+		previous.Status = domain.SubscriptionExpired
+		_, err := ss.repository.Update(ctx, previous)
+		if err != nil {
+			ss.logger.Error("Failed to expire previous subscription", zap.Error(err),
+				zap.String("Subscription ID", previous.ID.String()))
+			return domain.Subscription{}, err
+		}
+	}
+
+	subscription, err := ss.repository.Create(ctx, domain.Subscription{
+		ID:             uuid.New(),
+		UserID:         userID,
+		PlanID:         plan.ID,
+		StartDate:      now,
+		ExpirationDate: plan.Extend(now),
+		Status:         domain.SubscriptionActive,
+	})
+	if err != nil {
+		ss.logger.Error("Failed to create subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	ss.logger.Info("Subscription successfully started",
+		zap.String("User ID", userID.String()), zap.String("Plan ID", plan.ID.String()))
+
+	return subscription, nil
+}
+
+// Renew adds a period of the same plan to the end of the current one, so an
+// early renewal loses nothing. A canceled subscription becomes active again.
+func (ss *SubscriptionService) Renew(ctx context.Context, userID uuid.UUID) (domain.Subscription, error) {
+	subscription, err := ss.current(ctx, userID, time.Now())
+	if err != nil {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	if subscription.PlanID == uuid.Nil {
+		return domain.Subscription{}, ports.ErrSubscriptionNotRenewable
+	}
+
+	plan, err := ss.GetPlan(ctx, subscription.PlanID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	return ss.extend(ctx, subscription, plan)
+}
+
+func (ss *SubscriptionService) extend(ctx context.Context, subscription domain.Subscription,
+	plan domain.SubscriptionPlan) (domain.Subscription, error) {
+	subscription.ExpirationDate = plan.Extend(subscription.ExpirationDate)
+	subscription.Status = domain.SubscriptionActive
+	subscription, err := ss.repository.Update(ctx, subscription)
+	if err != nil {
+		ss.logger.Error("Failed to renew subscription", zap.Error(err),
+			zap.String("User ID", subscription.UserID.String()))
+		return domain.Subscription{}, err
+	}
+
+	ss.logger.Info("Subscription successfully renewed", zap.String("User ID", subscription.UserID.String()),
+		zap.Time("Expiration date", subscription.ExpirationDate))
+
+	return subscription, nil
+}
+
+func (ss *SubscriptionService) Activate(ctx context.Context, userID uuid.UUID, planID uuid.UUID) (domain.Subscription, error) {
+	plan, err := ss.GetPlan(ctx, planID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	now := time.Now()
+	subscription, err := ss.current(ctx, userID, now)
+	if errors.Is(err, ports.ErrSubscriptionNotFound) {
+		return ss.replace(ctx, userID, subscription, plan, now)
+	} else if err != nil {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	return ss.extend(ctx, subscription, plan)
+}
+
+// Revoke ends the subscription right away when the refunded period was all
+// that was left of it.
+func (ss *SubscriptionService) Revoke(ctx context.Context, userID uuid.UUID, planID uuid.UUID) error {
+	plan, err := ss.GetPlan(ctx, planID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	subscription, err := ss.current(ctx, userID, now)
+	if errors.Is(err, ports.ErrSubscriptionNotFound) {
+		return nil
+	} else if err != nil {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return err
+	}
+
+	subscription.ExpirationDate = subscription.ExpirationDate.AddDate(0, 0, -plan.DurationDays)
+	if !subscription.ExpirationDate.After(now) {
+		subscription.ExpirationDate = now
+		subscription.Status = domain.SubscriptionExpired
+	}
+
+	_, err = ss.repository.Update(ctx, subscription)
+	if err != nil {
+		ss.logger.Error("Failed to revoke subscription period", zap.Error(err), zap.String("User ID", userID.String()))
+		return err
+	}
+
+	ss.logger.Info("Subscription period successfully revoked", zap.String("User ID", userID.String()),
+		zap.String("Plan ID", planID.String()))
+
+	return nil
+}
+
+// Cancel keeps the subscription in effect until its expiration date.
+func (ss *SubscriptionService) Cancel(ctx context.Context, userID uuid.UUID) (domain.Subscription, error) {
+	subscription, err := ss.current(ctx, userID, time.Now())
+	if err != nil {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	if subscription.Status == domain.SubscriptionCanceled {
+		return subscription, nil
+	}
+
+	subscription.Status = domain.SubscriptionCanceled
+	subscription, err = ss.repository.Update(ctx, subscription)
+	if err != nil {
+		ss.logger.Error("Failed to cancel subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.Subscription{}, err
+	}
+
+	ss.logger.Info("Subscription successfully canceled", zap.String("User ID", userID.String()))
+
+	return subscription, nil
+}
+
+func (ss *SubscriptionService) CheckActive(ctx context.Context, userID uuid.UUID) error {
+	_, err := ss.current(ctx, userID, time.Now())
+	if errors.Is(err, ports.ErrSubscriptionNotFound) {
+		return ports.ErrSubscriptionRequired
+	} else if err != nil {
+		ss.logger.Error("Failed to get user subscription", zap.Error(err), zap.String("User ID", userID.String()))
+		return err
+	}
+
+	return nil
+}
+
+func (ss *SubscriptionService) ExpireDue(ctx context.Context) (int64, error) {
+	expired, err := ss.repository.ExpireDue(ctx, time.Now())
+	if err != nil {
+		ss.logger.Error("Failed to expire subscriptions", zap.Error(err))
+		return 0, err
+	}
+
+	if expired > 0 {
+		ss.logger.Info("Subscriptions expired", zap.Int64("Count", expired))
+	}
+
+	return expired, nil
+}
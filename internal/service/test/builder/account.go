@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/domain"
+)
+
+type AccountBuilder struct {
+	obj domain.Account
+}
+
+func NewAccountBuilder() *AccountBuilder {
+	return new(AccountBuilder)
+}
+
+func (b *AccountBuilder) Build() domain.Account {
+	return b.obj
+}
+
+func (b *AccountBuilder) Default() *AccountBuilder {
+	b.obj = domain.Account{
+		ID:       uuid.New(),
+		Kind:     domain.AccountKindUser,
+		Name:     "test",
+		Email:    "test@mail.com",
+		Password: "test",
+		Salt:     "test",
+	}
+	return b
+}
+
+func (b *AccountBuilder) SetID(id uuid.UUID) *AccountBuilder {
+	b.obj.ID = id
+	return b
+}
+
+func (b *AccountBuilder) SetKind(kind string) *AccountBuilder {
+	b.obj.Kind = kind
+	return b
+}
+
+func (b *AccountBuilder) SetName(name string) *AccountBuilder {
+	b.obj.Name = name
+	return b
+}
+
+func (b *AccountBuilder) SetEmail(email string) *AccountBuilder {
+	b.obj.Email = email
+	return b
+}
+
+func (b *AccountBuilder) SetPassword(password domain.SaltedPassword) *AccountBuilder {
+	b.obj.Password = password.HashPassword
+	b.obj.Salt = password.Salt
+	return b
+}
+
+func (b *AccountBuilder) SetEmailVerified(verified bool) *AccountBuilder {
+	b.obj.EmailVerified = verified
+	return b
+}
@@ -2,6 +2,8 @@ package builder
 
 import (
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 )
 
@@ -41,3 +43,46 @@ func (b *CreateAlbumServiceRequestBuilder) SetDescription(description string) *C
 	b.obj.Description = description
 	return b
 }
+
+type AlbumBuilder struct {
+	obj domain.Album
+}
+
+func NewAlbumBuilder() *AlbumBuilder {
+	return new(AlbumBuilder)
+}
+
+func (b *AlbumBuilder) Build() domain.Album {
+	return b.obj
+}
+
+func (b *AlbumBuilder) Default() *AlbumBuilder {
+	b.obj = domain.Album{
+		ID:          uuid.New(),
+		Name:        "name",
+		Description: "description",
+		Published:   false,
+		ReleaseDate: null.Time{},
+	}
+	return b
+}
+
+func (b *AlbumBuilder) SetID(id uuid.UUID) *AlbumBuilder {
+	b.obj.ID = id
+	return b
+}
+
+func (b *AlbumBuilder) SetPublished(published bool) *AlbumBuilder {
+	b.obj.Published = published
+	return b
+}
+
+func (b *AlbumBuilder) SetReleaseDate(releaseDate null.Time) *AlbumBuilder {
+	b.obj.ReleaseDate = releaseDate
+	return b
+}
+
+func (b *AlbumBuilder) SetImageURL(imageURL null.String) *AlbumBuilder {
+	b.obj.ImageURL = imageURL
+	return b
+}
@@ -94,10 +94,12 @@ func (b *TrackBuilder) Build() domain.Track {
 
 func (b *TrackBuilder) Default() *TrackBuilder {
 	b.obj = domain.Track{
-		ID:      uuid.New(),
-		AlbumID: uuid.New(),
-		Name:    "trackname",
-		URL:     "url",
+		ID:          uuid.New(),
+		AlbumID:     uuid.New(),
+		Name:        "trackname",
+		URL:         "url",
+		DiscNumber:  1,
+		TrackNumber: 1,
 	}
 	return b
 }
@@ -121,3 +123,9 @@ func (b *TrackBuilder) SetURL(url string) *TrackBuilder {
 	b.obj.URL = url
 	return b
 }
+
+func (b *TrackBuilder) SetTrackNumber(disc int, number int) *TrackBuilder {
+	b.obj.DiscNumber = disc
+	b.obj.TrackNumber = number
+	return b
+}
@@ -113,3 +113,8 @@ func (b *UserBuilder) SetCountry(country string) *UserBuilder {
 	b.obj.Country = country
 	return b
 }
+
+func (b *UserBuilder) SetRole(role int) *UserBuilder {
+	b.obj.Role = role
+	return b
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hanoys/sigma-music/internal/adapters/hash"
 	"github.com/hanoys/sigma-music/internal/adapters/miniostorage"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/service"
 	"github.com/hanoys/sigma-music/internal/service/test/builder"
 	"github.com/jmoiron/sqlx"
@@ -87,7 +88,7 @@ func (s *E2ESuite) TestE2E(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresUserRepository(s.db)
-	userService := service.NewUserService(repo, s.hash, s.logger)
+	userService := service.NewUserService(repo, nil, nil, nil, nil, s.hash, service.UserServiceConfig{}, s.logger)
 	createUserReq := builder.NewUserServiceCreateRequestBuilder().
 		Default().
 		SetName("Test").
@@ -98,7 +99,7 @@ func (s *E2ESuite) TestE2E(t provider.T) {
 	trackRepo := postgres.NewPostgresTrackRepository(s.db)
 	trackService := service.NewTrackService(trackRepo, storage, genreRepo, s.logger)
 	commentRepo := postgres.NewPostgresCommentRepository(s.db)
-	commentService := service.NewCommentService(commentRepo, s.logger)
+	commentService := service.NewCommentService(commentRepo, trackService, service.CommentServiceConfig{}, s.logger)
 
 	albumID, _ := uuid.Parse("b24fa8eb-9df6-406c-9b45-763d7b5a5078")
 
@@ -107,7 +108,7 @@ func (s *E2ESuite) TestE2E(t provider.T) {
 	t.Assert().Nil(err)
 	t.Assert().Equal(user.Name, createUserReq.Name)
 
-	tracks, err := trackService.GetByAlbumID(context.Background(), albumID)
+	tracks, err := trackService.GetByAlbumID(context.Background(), albumID, domain.TrackSortDefault)
 
 	t.Assert().Nil(err)
 	t.Assert().NotNil(tracks)
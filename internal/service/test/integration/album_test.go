@@ -17,7 +17,8 @@ func (s *AllSuite) TestAlbumCreate(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresAlbumRepository(s.db)
-	albumService := service.NewAlbumService(repo, s.logger)
+	followService := service.NewFollowService(postgres.NewPostgresFollowRepository(s.db), s.logger)
+	albumService := service.NewAlbumService(repo, nil, nil, followService, s.logger)
 	musicianID, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fa")
 	req := builder.NewCreateAlbumServiceRequestBuilder().
 		Default().
@@ -37,7 +38,8 @@ func (s *AllSuite) TestAlbumGetAll(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresAlbumRepository(s.db)
-	albumService := service.NewAlbumService(repo, s.logger)
+	followService := service.NewFollowService(postgres.NewPostgresFollowRepository(s.db), s.logger)
+	albumService := service.NewAlbumService(repo, nil, nil, followService, s.logger)
 
 	albums, err := albumService.GetAll(context.Background())
 
@@ -52,7 +54,8 @@ func (s *AllSuite) TestAlbumGetByID(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresAlbumRepository(s.db)
-	albumService := service.NewAlbumService(repo, s.logger)
+	followService := service.NewFollowService(postgres.NewPostgresFollowRepository(s.db), s.logger)
+	albumService := service.NewAlbumService(repo, nil, nil, followService, s.logger)
 	id, _ := uuid.Parse("b24fa8eb-9df6-406c-9b45-763d7b5a5078")
 
 	_, err := albumService.GetByID(context.Background(), id)
@@ -67,7 +70,8 @@ func (s *AllSuite) TestAlbumGetOwn(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresAlbumRepository(s.db)
-	albumService := service.NewAlbumService(repo, s.logger)
+	followService := service.NewFollowService(postgres.NewPostgresFollowRepository(s.db), s.logger)
+	albumService := service.NewAlbumService(repo, nil, nil, followService, s.logger)
 	id, _ := uuid.Parse("b24fa8eb-9df6-406c-9b45-763d7b5a5078")
 
 	_, err := albumService.GetOwn(context.Background(), id)
@@ -82,7 +86,8 @@ func (s *AllSuite) TestAlbumGetByMusicianID(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresAlbumRepository(s.db)
-	albumService := service.NewAlbumService(repo, s.logger)
+	followService := service.NewFollowService(postgres.NewPostgresFollowRepository(s.db), s.logger)
+	albumService := service.NewAlbumService(repo, nil, nil, followService, s.logger)
 	id, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fa")
 
 	albums, err := albumService.GetByMusicianID(context.Background(), id)
@@ -98,10 +103,17 @@ func (s *AllSuite) TestAlbumPublish(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresAlbumRepository(s.db)
-	albumService := service.NewAlbumService(repo, s.logger)
+	followService := service.NewFollowService(postgres.NewPostgresFollowRepository(s.db), s.logger)
+	albumService := service.NewAlbumService(repo, nil, nil, followService, s.logger)
 	id, _ := uuid.Parse("b24fa8eb-9df6-406c-9b45-763d7b5a5078")
 
+	userID, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fc")
+
 	err := albumService.Publish(context.Background(), id)
 
 	t.Assert().Nil(err)
+	feed, err := followService.GetFeed(context.Background(), userID)
+	t.Assert().Nil(err)
+	t.Require().Len(feed, 1)
+	t.Assert().Equal(id, feed[0].Album.ID)
 }
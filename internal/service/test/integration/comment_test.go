@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/postgres/test/builder"
+	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/service"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 )
@@ -17,7 +18,7 @@ func (s *AllSuite) TestPost(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresCommentRepository(s.db)
-	commentService := service.NewCommentService(repo, s.logger)
+	commentService := service.NewCommentService(repo, nil, service.CommentServiceConfig{}, s.logger)
 	userID, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fc")
 	trackID, _ := uuid.Parse("41623ac1-b98d-4478-a10f-870a80c697b6")
 	req := builder.NewPostCommentServiceRequestBuilder().
@@ -39,7 +40,7 @@ func (s *AllSuite) TestGetUserComments(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresCommentRepository(s.db)
-	commentService := service.NewCommentService(repo, s.logger)
+	commentService := service.NewCommentService(repo, nil, service.CommentServiceConfig{}, s.logger)
 	userID, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fc")
 
 	comments, err := commentService.GetUserComments(context.Background(), userID)
@@ -55,10 +56,10 @@ func (s *AllSuite) TestGetCommentsOnTrack(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresCommentRepository(s.db)
-	commentService := service.NewCommentService(repo, s.logger)
+	commentService := service.NewCommentService(repo, nil, service.CommentServiceConfig{}, s.logger)
 	trackID, _ := uuid.Parse("41623ac1-b98d-4478-a10f-870a80c697b6")
 
-	comments, err := commentService.GetCommentsOnTrack(context.Background(), trackID)
+	comments, _, err := commentService.GetCommentsOnTrack(context.Background(), trackID, domain.CommentPage{})
 
 	t.Assert().Nil(err)
 	t.Assert().NotNil(comments)
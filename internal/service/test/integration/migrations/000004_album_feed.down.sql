@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS feed CASCADE;
+DROP TABLE IF EXISTS musician_followers CASCADE;
+DROP VIEW IF EXISTS album_ratings;
+DROP TABLE IF EXISTS track_ratings CASCADE;
+
+ALTER TABLE album_musician
+    DROP COLUMN IF EXISTS is_owner,
+    DROP COLUMN IF EXISTS accepted,
+    DROP COLUMN IF EXISTS invited_at;
+
+ALTER TABLE albums
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS scheduled_release,
+    DROP COLUMN IF EXISTS unpublish_reason;
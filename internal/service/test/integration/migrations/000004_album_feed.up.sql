@@ -0,0 +1,52 @@
+-- Brings the album publish path up to the application schema: the columns the
+-- album repository writes, the rating aggregates it reads and the followers
+-- feed that a published album is pushed to.
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS image_url VARCHAR(1024);
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS scheduled_release TIMESTAMP;
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS unpublish_reason VARCHAR(1024);
+
+ALTER TABLE album_musician ADD COLUMN IF NOT EXISTS is_owner BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE album_musician ADD COLUMN IF NOT EXISTS accepted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE album_musician ADD COLUMN IF NOT EXISTS invited_at TIMESTAMP NOT NULL DEFAULT now();
+
+UPDATE album_musician SET is_owner = TRUE, accepted = TRUE;
+
+CREATE TABLE IF NOT EXISTS track_ratings (
+    track_id UUID PRIMARY KEY REFERENCES tracks ON DELETE CASCADE,
+    reviews  INT NOT NULL DEFAULT 0,
+    stars_1  INT NOT NULL DEFAULT 0,
+    stars_2  INT NOT NULL DEFAULT 0,
+    stars_3  INT NOT NULL DEFAULT 0,
+    stars_4  INT NOT NULL DEFAULT 0,
+    stars_5  INT NOT NULL DEFAULT 0
+);
+
+CREATE OR REPLACE VIEW album_ratings AS
+SELECT t.album_id,
+       SUM(r.reviews)::INT AS reviews,
+       SUM(r.stars_1)::INT AS stars_1,
+       SUM(r.stars_2)::INT AS stars_2,
+       SUM(r.stars_3)::INT AS stars_3,
+       SUM(r.stars_4)::INT AS stars_4,
+       SUM(r.stars_5)::INT AS stars_5
+FROM track_ratings r
+         JOIN tracks t ON t.id = r.track_id
+GROUP BY t.album_id;
+
+CREATE TABLE IF NOT EXISTS musician_followers (
+    user_id UUID REFERENCES users ON DELETE CASCADE,
+    musician_id UUID REFERENCES musicians ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, musician_id)
+);
+
+CREATE TABLE IF NOT EXISTS feed (
+    user_id UUID REFERENCES users ON DELETE CASCADE,
+    musician_id UUID REFERENCES musicians ON DELETE CASCADE,
+    album_id UUID REFERENCES albums ON DELETE CASCADE,
+    published_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, album_id)
+);
+
+INSERT INTO musician_followers(user_id, musician_id)
+VALUES ('1add32df-d439-4fd1-9d4c-bef946b4a1fc', '1add32df-d439-4fd1-9d4c-bef946b4a1fa');
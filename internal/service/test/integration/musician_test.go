@@ -17,7 +17,7 @@ func (s *AllSuite) TestRegister(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresMusicianRepository(s.db)
-	musicianService := service.NewMusicianService(repo, s.hash, s.logger)
+	musicianService := service.NewMusicianService(repo, nil, s.hash, s.logger)
 
 	req := builder.NewMusicianServiceCreateRequestBuilder().
 		Default().
@@ -37,7 +37,7 @@ func (s *AllSuite) TestGenreGetByIDSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresMusicianRepository(s.db)
-	musicianService := service.NewMusicianService(repo, s.hash, s.logger)
+	musicianService := service.NewMusicianService(repo, nil, s.hash, s.logger)
 
 	id, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fa")
 	foundMusician, err := musicianService.GetByID(context.Background(), id)
@@ -53,7 +53,7 @@ func (s *AllSuite) TestGenreGetByNameSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresMusicianRepository(s.db)
-	musicianService := service.NewMusicianService(repo, s.hash, s.logger)
+	musicianService := service.NewMusicianService(repo, nil, s.hash, s.logger)
 
 	name := "Timur"
 	foundMusician, err := musicianService.GetByName(context.Background(), name)
@@ -69,7 +69,7 @@ func (s *AllSuite) TestGenreGetByEmailSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresMusicianRepository(s.db)
-	musicianService := service.NewMusicianService(repo, s.hash, s.logger)
+	musicianService := service.NewMusicianService(repo, nil, s.hash, s.logger)
 
 	email := "timur@mail.ru"
 	foundMusician, err := musicianService.GetByEmail(context.Background(), email)
@@ -17,14 +17,14 @@ func (s *AllSuite) TestAdd(t provider.T) {
 	}
 	repo := postgres.NewPostgresStatRepository(s.db)
 	musrepo := postgres.NewPostgresMusicianRepository(s.db)
-	musicianService := service.NewMusicianService(musrepo, s.hash, s.logger)
+	musicianService := service.NewMusicianService(musrepo, nil, s.hash, s.logger)
 	genrerepo := postgres.NewPostgresGenreRepository(s.db)
 	genreService := service.NewGenreService(genrerepo, s.logger)
 	statService := service.NewStatService(repo, genreService, musicianService, s.logger)
 	userID, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fc")
 	trackID, _ := uuid.Parse("41623ac1-b98d-4478-a10f-870a80c697b6")
 
-	err := statService.Add(context.Background(), userID, trackID)
+	err := statService.Add(context.Background(), userID, trackID, 30)
 
 	t.Assert().Nil(err)
 }
@@ -37,7 +37,7 @@ func (s *AllSuite) TestFormReport(t provider.T) {
 	}
 	repo := postgres.NewPostgresStatRepository(s.db)
 	musrepo := postgres.NewPostgresMusicianRepository(s.db)
-	musicianService := service.NewMusicianService(musrepo, s.hash, s.logger)
+	musicianService := service.NewMusicianService(musrepo, nil, s.hash, s.logger)
 	genrerepo := postgres.NewPostgresGenreRepository(s.db)
 	genreService := service.NewGenreService(genrerepo, s.logger)
 	statService := service.NewStatService(repo, genreService, musicianService, s.logger)
@@ -17,7 +17,7 @@ func (s *AllSuite) TestCreateSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresUserRepository(s.db)
-	userService := service.NewUserService(repo, s.hash, s.logger)
+	userService := service.NewUserService(repo, nil, nil, nil, nil, s.hash, service.UserServiceConfig{}, s.logger)
 	req := builder.NewUserServiceCreateRequestBuilder().
 		Default().
 		SetName("Test").
@@ -39,7 +39,7 @@ func (s *AllSuite) TestUserGetByIDSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresUserRepository(s.db)
-	userService := service.NewUserService(repo, s.hash, s.logger)
+	userService := service.NewUserService(repo, nil, nil, nil, nil, s.hash, service.UserServiceConfig{}, s.logger)
 
 	id, _ := uuid.Parse("1add32df-d439-4fd1-9d4c-bef946b4a1fc")
 	foundUser, err := userService.GetById(context.Background(), id)
@@ -55,7 +55,7 @@ func (s *AllSuite) TestUserGetByNameSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresUserRepository(s.db)
-	userService := service.NewUserService(repo, s.hash, s.logger)
+	userService := service.NewUserService(repo, nil, nil, nil, nil, s.hash, service.UserServiceConfig{}, s.logger)
 
 	name := "Timur"
 	foundUser, err := userService.GetByName(context.Background(), name)
@@ -71,7 +71,7 @@ func (s *AllSuite) TestUserGetByEmailSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresUserRepository(s.db)
-	userService := service.NewUserService(repo, s.hash, s.logger)
+	userService := service.NewUserService(repo, nil, nil, nil, nil, s.hash, service.UserServiceConfig{}, s.logger)
 
 	email := "timur@mail.ru"
 	foundUser, err := userService.GetByEmail(context.Background(), email)
@@ -87,7 +87,7 @@ func (s *AllSuite) TestGetByPhoneSuccess(t provider.T) {
 		t.Skip()
 	}
 	repo := postgres.NewPostgresUserRepository(s.db)
-	userService := service.NewUserService(repo, s.hash, s.logger)
+	userService := service.NewUserService(repo, nil, nil, nil, nil, s.hash, service.UserServiceConfig{}, s.logger)
 
 	phone := "+79999999999"
 	foundUser, err := userService.GetByPhone(context.Background(), phone)
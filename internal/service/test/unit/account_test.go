@@ -0,0 +1,531 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/auth/mocks"
+	"github.com/hanoys/sigma-music/internal/adapters/hash"
+	mocks4 "github.com/hanoys/sigma-music/internal/adapters/mail/mocks"
+	mocks3 "github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/adapters/totp"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/hanoys/sigma-music/internal/service/test/builder"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type AccountSuite struct {
+	suite.Suite
+	logger       *zap.Logger
+	hashProvider *hash.HashPasswordProvider
+	totpProvider *totp.Provider
+}
+
+func (s *AccountSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+	s.hashProvider = hash.NewHashPasswordProvider()
+	s.totpProvider = totp.NewProvider("Sigma Music")
+}
+
+type accountServiceMocks struct {
+	repository          *mocks3.AccountRepository
+	twoFactorRepository *mocks3.TwoFactorRepository
+	oneTimeTokens       *mocks.OneTimeTokenProvider
+	tokenProvider       *mocks.TokenProvider
+	mailer              *mocks4.Mailer
+}
+
+func (s *AccountSuite) NewService(t provider.T) (*service.AccountService, accountServiceMocks) {
+	m := accountServiceMocks{
+		repository:          mocks3.NewAccountRepository(t),
+		twoFactorRepository: mocks3.NewTwoFactorRepository(t),
+		oneTimeTokens:       mocks.NewOneTimeTokenProvider(t),
+		tokenProvider:       mocks.NewTokenProvider(t),
+		mailer:              mocks4.NewMailer(t),
+	}
+
+	accountService := service.NewAccountService(m.repository, m.twoFactorRepository, m.oneTimeTokens,
+		m.tokenProvider, m.mailer, s.hashProvider, s.totpProvider, service.AccountServiceConfig{
+			VerifyEmailURL:   "http://localhost/verify-email",
+			ResetPasswordURL: "http://localhost/reset-password",
+		}, s.logger)
+
+	return accountService, m
+}
+
+type AccountSendEmailVerificationSuite struct {
+	AccountSuite
+}
+
+func (s *AccountSendEmailVerificationSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+	m.oneTimeTokens.
+		On("Issue", context.Background(), domain.OneTimeToken{
+			Purpose:   domain.OneTimeTokenVerifyEmail,
+			AccountID: account.ID,
+			Email:     account.Email,
+		}, mock.AnythingOfType("time.Duration")).
+		Return("id.signature", nil)
+	m.mailer.
+		On("Send", context.Background(), mock.MatchedBy(func(mail domain.Mail) bool {
+			return mail.To == account.Email
+		})).
+		Return(nil)
+}
+
+func (s *AccountSendEmailVerificationSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account send email verification test correct")
+	account := builder.NewAccountBuilder().Default().Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account)
+
+	err := accountService.SendEmailVerification(context.Background(), account.ID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountSendEmailVerificationSuite) AccountRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+}
+
+func (s *AccountSendEmailVerificationSuite) TestAlreadyVerified(t provider.T) {
+	t.Parallel()
+	t.Title("Account send email verification test already verified")
+	account := builder.NewAccountBuilder().Default().SetEmailVerified(true).Build()
+	accountService, m := s.NewService(t)
+	s.AccountRepositoryMock(m, account)
+
+	err := accountService.SendEmailVerification(context.Background(), account.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrEmailAlreadyVerified)
+}
+
+func (s *AccountSendEmailVerificationSuite) TestNoEmail(t provider.T) {
+	t.Parallel()
+	t.Title("Account send email verification test no email")
+	account := builder.NewAccountBuilder().Default().SetEmail("").Build()
+	accountService, m := s.NewService(t)
+	s.AccountRepositoryMock(m, account)
+
+	err := accountService.SendEmailVerification(context.Background(), account.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrAccountNoEmail)
+}
+
+func TestAccountSendEmailVerificationSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountSendEmailVerificationSuite))
+}
+
+type AccountVerifyEmailSuite struct {
+	AccountSuite
+}
+
+func (s *AccountVerifyEmailSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account, email string) {
+	m.oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenVerifyEmail, "token").
+		Return(domain.OneTimeToken{
+			Purpose:   domain.OneTimeTokenVerifyEmail,
+			AccountID: account.ID,
+			Email:     email,
+		}, nil)
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+}
+
+func (s *AccountVerifyEmailSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account verify email test correct")
+	account := builder.NewAccountBuilder().Default().Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account, account.Email)
+	m.repository.
+		On("MarkEmailVerified", context.Background(), account.ID, account.Email).
+		Return(nil)
+
+	err := accountService.VerifyEmail(context.Background(), "token")
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountVerifyEmailSuite) TestEmailChanged(t provider.T) {
+	t.Parallel()
+	t.Title("Account verify email test email changed after the token was sent")
+	account := builder.NewAccountBuilder().Default().Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account, "old@mail.com")
+
+	err := accountService.VerifyEmail(context.Background(), "token")
+
+	t.Assert().ErrorIs(err, ports.ErrOneTimeTokenInvalid)
+}
+
+func (s *AccountVerifyEmailSuite) InvalidTokenRepositoryMock(m accountServiceMocks) {
+	m.oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenVerifyEmail, "token").
+		Return(domain.OneTimeToken{}, ports.ErrOneTimeTokenInvalid)
+}
+
+func (s *AccountVerifyEmailSuite) TestInvalidToken(t provider.T) {
+	t.Parallel()
+	t.Title("Account verify email test invalid token")
+	accountService, m := s.NewService(t)
+	s.InvalidTokenRepositoryMock(m)
+
+	err := accountService.VerifyEmail(context.Background(), "token")
+
+	t.Assert().ErrorIs(err, ports.ErrOneTimeTokenInvalid)
+}
+
+func TestAccountVerifyEmailSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountVerifyEmailSuite))
+}
+
+type AccountChangePasswordSuite struct {
+	AccountSuite
+}
+
+func (s *AccountChangePasswordSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account,
+	currentSessionID uuid.UUID, otherSessionID uuid.UUID) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil).
+		On("UpdatePassword", context.Background(), account.ID, mock.AnythingOfType("domain.SaltedPassword")).
+		Return(nil)
+	m.tokenProvider.
+		On("GetSessions", context.Background(), account.ID).
+		Return([]domain.Session{{ID: currentSessionID}, {ID: otherSessionID}}, nil).
+		On("RevokeSession", context.Background(), account.ID, otherSessionID).
+		Return(nil)
+}
+
+func (s *AccountChangePasswordSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account change password test correct, current session kept")
+	account := builder.NewAccountBuilder().Default().
+		SetPassword(s.hashProvider.EncodePassword("old")).Build()
+	currentSessionID := uuid.New()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account, currentSessionID, uuid.New())
+
+	err := accountService.ChangePassword(context.Background(), ports.ChangePasswordRequest{
+		AccountID:   account.ID,
+		SessionID:   currentSessionID,
+		OldPassword: "old",
+		NewPassword: "new",
+	})
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountChangePasswordSuite) IncorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+}
+
+func (s *AccountChangePasswordSuite) TestIncorrectPassword(t provider.T) {
+	t.Parallel()
+	t.Title("Account change password test incorrect old password")
+	account := builder.NewAccountBuilder().Default().
+		SetPassword(s.hashProvider.EncodePassword("old")).Build()
+	accountService, m := s.NewService(t)
+	s.IncorrectRepositoryMock(m, account)
+
+	err := accountService.ChangePassword(context.Background(), ports.ChangePasswordRequest{
+		AccountID:   account.ID,
+		OldPassword: "wrong",
+		NewPassword: "new",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrIncorrectPassword)
+}
+
+func TestAccountChangePasswordSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountChangePasswordSuite))
+}
+
+type AccountForgotPasswordSuite struct {
+	AccountSuite
+}
+
+func (s *AccountForgotPasswordSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByEmail", context.Background(), account.Email).
+		Return(account, nil)
+	m.oneTimeTokens.
+		On("Issue", context.Background(), domain.OneTimeToken{
+			Purpose:   domain.OneTimeTokenResetPassword,
+			AccountID: account.ID,
+			Email:     account.Email,
+		}, mock.AnythingOfType("time.Duration")).
+		Return("id.signature", nil)
+	m.mailer.
+		On("Send", context.Background(), mock.MatchedBy(func(mail domain.Mail) bool {
+			return mail.To == account.Email
+		})).
+		Return(nil)
+}
+
+func (s *AccountForgotPasswordSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account forgot password test correct")
+	account := builder.NewAccountBuilder().Default().SetEmailVerified(true).Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account)
+
+	err := accountService.ForgotPassword(context.Background(), account.Email)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountForgotPasswordSuite) TestUnverifiedEmail(t provider.T) {
+	t.Parallel()
+	t.Title("Account forgot password test unverified email gets no mail")
+	account := builder.NewAccountBuilder().Default().Build()
+	accountService, m := s.NewService(t)
+	m.repository.
+		On("GetByEmail", context.Background(), account.Email).
+		Return(account, nil)
+
+	err := accountService.ForgotPassword(context.Background(), account.Email)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountForgotPasswordSuite) TestUnknownEmail(t provider.T) {
+	t.Parallel()
+	t.Title("Account forgot password test unknown email is not revealed")
+	accountService, m := s.NewService(t)
+	m.repository.
+		On("GetByEmail", context.Background(), "nobody@mail.com").
+		Return(domain.Account{}, ports.ErrAccountNotFound)
+
+	err := accountService.ForgotPassword(context.Background(), "nobody@mail.com")
+
+	t.Assert().Nil(err)
+}
+
+func TestAccountForgotPasswordSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountForgotPasswordSuite))
+}
+
+type AccountResetPasswordSuite struct {
+	AccountSuite
+}
+
+func (s *AccountResetPasswordSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenResetPassword, "token").
+		Return(domain.OneTimeToken{
+			Purpose:   domain.OneTimeTokenResetPassword,
+			AccountID: account.ID,
+			Email:     account.Email,
+		}, nil)
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil).
+		On("UpdatePassword", context.Background(), account.ID, mock.AnythingOfType("domain.SaltedPassword")).
+		Return(nil)
+	m.tokenProvider.
+		On("RevokeAllSessions", context.Background(), account.ID).
+		Return(nil)
+}
+
+func (s *AccountResetPasswordSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account reset password test correct")
+	account := builder.NewAccountBuilder().Default().SetEmailVerified(true).Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account)
+
+	err := accountService.ResetPassword(context.Background(), "token", "new")
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountResetPasswordSuite) TestInvalidToken(t provider.T) {
+	t.Parallel()
+	t.Title("Account reset password test invalid token")
+	accountService, m := s.NewService(t)
+	m.oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenResetPassword, "token").
+		Return(domain.OneTimeToken{}, ports.ErrOneTimeTokenInvalid)
+
+	err := accountService.ResetPassword(context.Background(), "token", "new")
+
+	t.Assert().ErrorIs(err, ports.ErrOneTimeTokenInvalid)
+}
+
+func TestAccountResetPasswordSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountResetPasswordSuite))
+}
+
+type AccountBeginTwoFactorSuite struct {
+	AccountSuite
+}
+
+func (s *AccountBeginTwoFactorSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+	m.twoFactorRepository.
+		On("Get", context.Background(), account.ID).
+		Return(domain.TwoFactor{}, ports.ErrTwoFactorNotFound).
+		On("Save", context.Background(), mock.MatchedBy(func(tf domain.TwoFactor) bool {
+			return tf.AccountID == account.ID && tf.Secret != "" && !tf.Enabled
+		}), mock.MatchedBy(func(hashes []string) bool {
+			return len(hashes) == 10
+		})).
+		Return(nil)
+}
+
+func (s *AccountBeginTwoFactorSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account begin two-factor test correct")
+	account := builder.NewAccountBuilder().Default().Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account)
+
+	enrollment, err := accountService.BeginTwoFactor(context.Background(), account.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().NotEmpty(enrollment.Secret)
+	t.Assert().Contains(enrollment.URI, "otpauth://totp/")
+	t.Assert().Len(enrollment.RecoveryCodes, 10)
+}
+
+func (s *AccountBeginTwoFactorSuite) AlreadyEnabledRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+	m.twoFactorRepository.
+		On("Get", context.Background(), account.ID).
+		Return(domain.TwoFactor{AccountID: account.ID, Enabled: true}, nil)
+}
+
+func (s *AccountBeginTwoFactorSuite) TestAlreadyEnabled(t provider.T) {
+	t.Parallel()
+	t.Title("Account begin two-factor test already enabled")
+	account := builder.NewAccountBuilder().Default().Build()
+	accountService, m := s.NewService(t)
+	s.AlreadyEnabledRepositoryMock(m, account)
+
+	_, err := accountService.BeginTwoFactor(context.Background(), account.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrTwoFactorAlreadyEnabled)
+}
+
+func TestAccountBeginTwoFactorSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountBeginTwoFactorSuite))
+}
+
+type AccountEnableTwoFactorSuite struct {
+	AccountSuite
+}
+
+func (s *AccountEnableTwoFactorSuite) CorrectRepositoryMock(m accountServiceMocks, twoFactor domain.TwoFactor) {
+	m.twoFactorRepository.
+		On("Get", context.Background(), twoFactor.AccountID).
+		Return(twoFactor, nil).
+		On("Enable", context.Background(), twoFactor.AccountID, mock.AnythingOfType("int64")).
+		Return(nil)
+}
+
+func (s *AccountEnableTwoFactorSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account enable two-factor test correct")
+	twoFactor := domain.TwoFactor{AccountID: uuid.New(), Secret: "JBSWY3DPEHPK3PXP"}
+	code, _ := totp.Code(twoFactor.Secret, time.Now())
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, twoFactor)
+
+	err := accountService.EnableTwoFactor(context.Background(), twoFactor.AccountID, code)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountEnableTwoFactorSuite) InvalidCodeRepositoryMock(m accountServiceMocks, twoFactor domain.TwoFactor) {
+	m.twoFactorRepository.
+		On("Get", context.Background(), twoFactor.AccountID).
+		Return(twoFactor, nil)
+}
+
+func (s *AccountEnableTwoFactorSuite) TestInvalidCode(t provider.T) {
+	t.Parallel()
+	t.Title("Account enable two-factor test invalid code")
+	twoFactor := domain.TwoFactor{AccountID: uuid.New(), Secret: "JBSWY3DPEHPK3PXP"}
+	accountService, m := s.NewService(t)
+	s.InvalidCodeRepositoryMock(m, twoFactor)
+
+	err := accountService.EnableTwoFactor(context.Background(), twoFactor.AccountID, "abcdef")
+
+	t.Assert().ErrorIs(err, ports.ErrTwoFactorInvalidCode)
+}
+
+func TestAccountEnableTwoFactorSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountEnableTwoFactorSuite))
+}
+
+type AccountDisableTwoFactorSuite struct {
+	AccountSuite
+}
+
+func (s *AccountDisableTwoFactorSuite) CorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+	m.twoFactorRepository.
+		On("Delete", context.Background(), account.ID).
+		Return(nil)
+}
+
+func (s *AccountDisableTwoFactorSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Account disable two-factor test correct")
+	account := builder.NewAccountBuilder().Default().
+		SetPassword(s.hashProvider.EncodePassword("password")).Build()
+	accountService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, account)
+
+	err := accountService.DisableTwoFactor(context.Background(), account.ID, "password")
+
+	t.Assert().Nil(err)
+}
+
+func (s *AccountDisableTwoFactorSuite) IncorrectRepositoryMock(m accountServiceMocks, account domain.Account) {
+	m.repository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+}
+
+func (s *AccountDisableTwoFactorSuite) TestIncorrectPassword(t provider.T) {
+	t.Parallel()
+	t.Title("Account disable two-factor test incorrect password")
+	account := builder.NewAccountBuilder().Default().
+		SetPassword(s.hashProvider.EncodePassword("password")).Build()
+	accountService, m := s.NewService(t)
+	s.IncorrectRepositoryMock(m, account)
+
+	err := accountService.DisableTwoFactor(context.Background(), account.ID, "wrong")
+
+	t.Assert().ErrorIs(err, ports.ErrIncorrectPassword)
+}
+
+func TestAccountDisableTwoFactorSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountDisableTwoFactorSuite))
+}
@@ -3,8 +3,11 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	mocks2 "github.com/hanoys/sigma-music/internal/adapters/miniostorage/mocks"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
@@ -42,7 +45,7 @@ func (s *AlbumCreateSuite) TestCorrect(t provider.T) {
 	t.Title("Album create test correct")
 	req := builder.NewCreateAlbumServiceRequestBuilder().Default().Build()
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	s.CorrectRepositoryMock(repository, req.MusicianID)
 
 	_, err := albumService.Create(context.Background(), req)
@@ -61,7 +64,7 @@ func (s *AlbumCreateSuite) TestDuplicate(t provider.T) {
 	t.Title("Album create test duplicate")
 	req := builder.NewCreateAlbumServiceRequestBuilder().Default().Build()
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	s.DuplicateRepositoryMock(repository, req.MusicianID)
 
 	_, err := albumService.Create(context.Background(), req)
@@ -77,21 +80,68 @@ type AlbumPublishSuite struct {
 	AlbumSuite
 }
 
-func (s *AlbumPublishSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, albumID uuid.UUID) {
+func (s *AlbumPublishSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, followRepository *mocks.FollowRepository, album domain.Album) {
 	repository.
-		On("Publish", context.Background(), albumID).
+		On("Publish", context.Background(), album.ID).
+		Return(nil)
+
+	repository.
+		On("GetByID", context.Background(), album.ID).
+		Return(album, nil)
+
+	followRepository.
+		On("AddToFeed", context.Background(), album.ID, album.ReleaseDate.Time).
 		Return(nil)
 }
 
 func (s *AlbumPublishSuite) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Album publish test correct")
-	albumID := uuid.New()
+	album := builder.NewAlbumBuilder().
+		Default().
+		SetPublished(true).
+		SetReleaseDate(null.TimeFrom(time.Now())).
+		Build()
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
-	s.CorrectRepositoryMock(repository, albumID)
+	followRepository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(followRepository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, followService, s.logger)
+	s.CorrectRepositoryMock(repository, followRepository, album)
 
-	err := albumService.Publish(context.Background(), albumID)
+	err := albumService.Publish(context.Background(), album.ID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AlbumPublishSuite) FeedErrorRepositoryMock(repository *mocks.AlbumRepository, followRepository *mocks.FollowRepository, album domain.Album) {
+	repository.
+		On("Publish", context.Background(), album.ID).
+		Return(nil)
+
+	repository.
+		On("GetByID", context.Background(), album.ID).
+		Return(album, nil)
+
+	followRepository.
+		On("AddToFeed", context.Background(), album.ID, album.ReleaseDate.Time).
+		Return(ports.ErrInternalFollowRepo)
+}
+
+func (s *AlbumPublishSuite) TestFeedError(t provider.T) {
+	t.Parallel()
+	t.Title("Album publish test feed error does not fail publish")
+	album := builder.NewAlbumBuilder().
+		Default().
+		SetPublished(true).
+		SetReleaseDate(null.TimeFrom(time.Now())).
+		Build()
+	repository := mocks.NewAlbumRepository(t)
+	followRepository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(followRepository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, followService, s.logger)
+	s.FeedErrorRepositoryMock(repository, followRepository, album)
+
+	err := albumService.Publish(context.Background(), album.ID)
 
 	t.Assert().Nil(err)
 }
@@ -107,7 +157,7 @@ func (s *AlbumPublishSuite) TestErrorPublish(t provider.T) {
 	t.Title("Album publish test error")
 	albumID := uuid.New()
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	s.ErrorPublishRepositoryMock(repository, albumID)
 
 	err := albumService.Publish(context.Background(), albumID)
@@ -133,7 +183,7 @@ func (s *AlbumGetAllSuite) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Album get all test correct")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	s.CorrectRepositoryMock(repository)
 
 	_, err := albumService.GetAll(context.Background())
@@ -151,7 +201,7 @@ func (s *AlbumGetAllSuite) TestInternalError(t provider.T) {
 	t.Parallel()
 	t.Title("Album get all test internal error")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	s.InternalErrorRepositoryMock(repository)
 
 	_, err := albumService.GetAll(context.Background())
@@ -177,7 +227,7 @@ func (s *AlbumGetByMusicianIDSuite) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Album get by musician id test correct")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	musicianID := uuid.New()
 	s.CorrectRepositoryMock(repository, musicianID)
 
@@ -196,7 +246,7 @@ func (s *AlbumGetByMusicianIDSuite) TestInternalError(t provider.T) {
 	t.Parallel()
 	t.Title("Album get by musician id test internal error")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	musicianID := uuid.New()
 	s.InternalErrorRepositoryMock(repository, musicianID)
 
@@ -223,7 +273,7 @@ func (s *AlbumGetOwnSuite) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Album get own id test correct")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	musicianID := uuid.New()
 	s.CorrectRepositoryMock(repository, musicianID)
 
@@ -242,7 +292,7 @@ func (s *AlbumGetOwnSuite) TestInternalError(t provider.T) {
 	t.Parallel()
 	t.Title("Album get own id test internal error")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	musicianID := uuid.New()
 	s.InternalErrorRepositoryMock(repository, musicianID)
 
@@ -269,7 +319,7 @@ func (s *AlbumGetByIDSuite) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Album get by id test correct")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	albumID := uuid.New()
 	s.CorrectRepositoryMock(repository, albumID)
 
@@ -288,7 +338,7 @@ func (s *AlbumGetByIDSuite) TestNotFound(t provider.T) {
 	t.Parallel()
 	t.Title("Album get by id test not found")
 	repository := mocks.NewAlbumRepository(t)
-	albumService := service.NewAlbumService(repository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
 	albumID := uuid.New()
 	s.NotFoundRepositoryMock(repository, albumID)
 
@@ -300,3 +350,309 @@ func (s *AlbumGetByIDSuite) TestNotFound(t provider.T) {
 func TestAlbumGetByIDSuite(t *testing.T) {
 	suite.RunSuite(t, new(AlbumGetByIDSuite))
 }
+
+type AlbumScheduleSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumScheduleSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, albumID uuid.UUID, releaseAt time.Time) {
+	repository.
+		On("Schedule", context.Background(), albumID, null.TimeFrom(releaseAt)).
+		Return(nil)
+}
+
+func (s *AlbumScheduleSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Album schedule test correct")
+	albumID := uuid.New()
+	releaseAt := time.Now().Add(time.Hour)
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.CorrectRepositoryMock(repository, albumID, releaseAt)
+
+	err := albumService.Schedule(context.Background(), albumID, releaseAt)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AlbumScheduleSuite) TestPastReleaseTime(t provider.T) {
+	t.Parallel()
+	t.Title("Album schedule test release time in the past")
+	albumID := uuid.New()
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+
+	err := albumService.Schedule(context.Background(), albumID, time.Now().Add(-time.Hour))
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumSchedule)
+}
+
+func (s *AlbumScheduleSuite) PublishedRepositoryMock(repository *mocks.AlbumRepository, albumID uuid.UUID, releaseAt time.Time) {
+	repository.
+		On("Schedule", context.Background(), albumID, null.TimeFrom(releaseAt)).
+		Return(ports.ErrAlbumPublished)
+}
+
+func (s *AlbumScheduleSuite) TestPublished(t provider.T) {
+	t.Parallel()
+	t.Title("Album schedule test album already published")
+	albumID := uuid.New()
+	releaseAt := time.Now().Add(time.Hour)
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.PublishedRepositoryMock(repository, albumID, releaseAt)
+
+	err := albumService.Schedule(context.Background(), albumID, releaseAt)
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumPublished)
+}
+
+func TestAlbumScheduleSuite(t *testing.T) {
+	suite.RunSuite(t, new(AlbumScheduleSuite))
+}
+
+type AlbumPublishScheduledSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumPublishScheduledSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, followRepository *mocks.FollowRepository, album domain.Album) {
+	repository.
+		On("PublishScheduled", context.Background(), mock.AnythingOfType("time.Time")).
+		Return([]domain.Album{album}, nil)
+
+	followRepository.
+		On("AddToFeed", context.Background(), album.ID, album.ReleaseDate.Time).
+		Return(nil)
+}
+
+func (s *AlbumPublishScheduledSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Album publish scheduled test correct")
+	album := builder.NewAlbumBuilder().
+		Default().
+		SetPublished(true).
+		SetReleaseDate(null.TimeFrom(time.Now())).
+		Build()
+	repository := mocks.NewAlbumRepository(t)
+	followRepository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(followRepository, s.logger)
+	albumService := service.NewAlbumService(repository, nil, nil, followService, s.logger)
+	s.CorrectRepositoryMock(repository, followRepository, album)
+
+	albums, err := albumService.PublishScheduled(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.Album{album}, albums)
+}
+
+func (s *AlbumPublishScheduledSuite) LockHeldRepositoryMock(repository *mocks.AlbumRepository) {
+	repository.
+		On("PublishScheduled", context.Background(), mock.AnythingOfType("time.Time")).
+		Return(nil, nil)
+}
+
+func (s *AlbumPublishScheduledSuite) TestLockHeld(t provider.T) {
+	t.Parallel()
+	t.Title("Album publish scheduled test lock held by another instance")
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.LockHeldRepositoryMock(repository)
+
+	albums, err := albumService.PublishScheduled(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(albums)
+}
+
+func TestAlbumPublishScheduledSuite(t *testing.T) {
+	suite.RunSuite(t, new(AlbumPublishScheduledSuite))
+}
+
+type AlbumUnpublishSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumUnpublishSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, albumID uuid.UUID, reason string) {
+	repository.
+		On("Unpublish", context.Background(), albumID, reason).
+		Return(nil)
+}
+
+func (s *AlbumUnpublishSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Album unpublish test correct")
+	albumID := uuid.New()
+	reason := "wrong artwork"
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.CorrectRepositoryMock(repository, albumID, reason)
+
+	err := albumService.Unpublish(context.Background(), albumID, reason)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AlbumUnpublishSuite) NotPublishedRepositoryMock(repository *mocks.AlbumRepository, albumID uuid.UUID, reason string) {
+	repository.
+		On("Unpublish", context.Background(), albumID, reason).
+		Return(ports.ErrAlbumNotPublished)
+}
+
+func (s *AlbumUnpublishSuite) TestNotPublished(t provider.T) {
+	t.Parallel()
+	t.Title("Album unpublish test album not published")
+	albumID := uuid.New()
+	reason := "wrong artwork"
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.NotPublishedRepositoryMock(repository, albumID, reason)
+
+	err := albumService.Unpublish(context.Background(), albumID, reason)
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumNotPublished)
+}
+
+func TestAlbumUnpublishSuite(t *testing.T) {
+	suite.RunSuite(t, new(AlbumUnpublishSuite))
+}
+
+type AlbumUpdateSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumUpdateSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, musicianID uuid.UUID, album domain.Album, updated domain.Album) {
+	repository.
+		On("GetOwn", context.Background(), musicianID).
+		Return([]domain.Album{album}, nil)
+
+	repository.
+		On("Update", context.Background(), updated).
+		Return(updated, nil)
+}
+
+func (s *AlbumUpdateSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Album update test correct")
+	musicianID := uuid.New()
+	album := builder.NewAlbumBuilder().Default().Build()
+	updated := album
+	updated.Name = "new name"
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.CorrectRepositoryMock(repository, musicianID, album, updated)
+
+	result, err := albumService.Update(context.Background(), ports.UpdateAlbumServiceReq{
+		AlbumID:    album.ID,
+		MusicianID: musicianID,
+		Name:       null.StringFrom(updated.Name),
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(updated, result)
+}
+
+func (s *AlbumUpdateSuite) NotFoundRepositoryMock(repository *mocks.AlbumRepository, musicianID uuid.UUID) {
+	repository.
+		On("GetOwn", context.Background(), musicianID).
+		Return([]domain.Album{}, nil)
+}
+
+func (s *AlbumUpdateSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Album update test not found")
+	musicianID := uuid.New()
+	repository := mocks.NewAlbumRepository(t)
+	albumService := service.NewAlbumService(repository, nil, nil, nil, s.logger)
+	s.NotFoundRepositoryMock(repository, musicianID)
+
+	_, err := albumService.Update(context.Background(), ports.UpdateAlbumServiceReq{
+		AlbumID:    uuid.New(),
+		MusicianID: musicianID,
+		Name:       null.StringFrom("new name"),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumIDNotFound)
+}
+
+func TestAlbumUpdateSuite(t *testing.T) {
+	suite.RunSuite(t, new(AlbumUpdateSuite))
+}
+
+type AlbumDeleteSuite struct {
+	AlbumSuite
+}
+
+func (s *AlbumDeleteSuite) CorrectRepositoryMock(repository *mocks.AlbumRepository, imageStorage *mocks2.AlbumImageStorage,
+	trackRepository *mocks.TrackRepository, trackStorage *mocks2.TrackObjectStorage, album domain.Album, track domain.Track,
+) {
+	trackRepository.
+		On("GetByAlbumIDInternal", context.Background(), album.ID).
+		Return([]domain.Track{track}, nil)
+
+	trackRepository.
+		On("Delete", context.Background(), track.ID).
+		Return(track, nil)
+
+	trackStorage.
+		On("DeleteTrack", context.Background(), track.ID).
+		Return(nil)
+
+	repository.
+		On("Delete", context.Background(), album.ID).
+		Return(album, nil)
+
+	imageStorage.
+		On("DeleteImage", context.Background(), album.ID.String()).
+		Return(nil)
+}
+
+func (s *AlbumDeleteSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Album delete test correct")
+	album := builder.NewAlbumBuilder().
+		Default().
+		SetImageURL(null.StringFrom("url")).
+		Build()
+	track := builder.NewTrackBuilder().Default().SetAlbumID(album.ID).Build()
+	repository := mocks.NewAlbumRepository(t)
+	imageStorage := mocks2.NewAlbumImageStorage(t)
+	trackRepository := mocks.NewTrackRepository(t)
+	trackStorage := mocks2.NewTrackObjectStorage(t)
+	trackService := service.NewTrackService(trackRepository, trackStorage, nil, s.logger)
+	albumService := service.NewAlbumService(repository, imageStorage, trackService, nil, s.logger)
+	s.CorrectRepositoryMock(repository, imageStorage, trackRepository, trackStorage, album, track)
+
+	result, err := albumService.Delete(context.Background(), album.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(album, result)
+}
+
+func (s *AlbumDeleteSuite) NotFoundRepositoryMock(repository *mocks.AlbumRepository, trackRepository *mocks.TrackRepository, albumID uuid.UUID) {
+	trackRepository.
+		On("GetByAlbumIDInternal", context.Background(), albumID).
+		Return([]domain.Track{}, nil)
+
+	repository.
+		On("Delete", context.Background(), albumID).
+		Return(domain.Album{}, ports.ErrAlbumIDNotFound)
+}
+
+func (s *AlbumDeleteSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Album delete test not found")
+	albumID := uuid.New()
+	repository := mocks.NewAlbumRepository(t)
+	trackRepository := mocks.NewTrackRepository(t)
+	trackService := service.NewTrackService(trackRepository, nil, nil, s.logger)
+	albumService := service.NewAlbumService(repository, nil, trackService, nil, s.logger)
+	s.NotFoundRepositoryMock(repository, trackRepository, albumID)
+
+	_, err := albumService.Delete(context.Background(), albumID)
+
+	t.Assert().ErrorIs(err, ports.ErrAlbumIDNotFound)
+}
+
+func TestAlbumDeleteSuite(t *testing.T) {
+	suite.RunSuite(t, new(AlbumDeleteSuite))
+}
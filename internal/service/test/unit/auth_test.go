@@ -3,16 +3,20 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/adapters/auth/mocks"
 	mocks2 "github.com/hanoys/sigma-music/internal/adapters/hash/mocks"
 	mocks3 "github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/adapters/totp"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
 	"github.com/hanoys/sigma-music/internal/service"
 	"github.com/hanoys/sigma-music/internal/service/test/builder"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 )
 
@@ -20,6 +24,7 @@ type AuthSuite struct {
 	suite.Suite
 	logger       *zap.Logger
 	hashProvider *mocks2.HashPasswordProvider
+	totpProvider *totp.Provider
 }
 
 func (s *AuthSuite) BeforeEach(t provider.T) {
@@ -27,89 +32,449 @@ func (s *AuthSuite) BeforeEach(t provider.T) {
 	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
 	s.logger, _ = loggerBuilder.Build()
 	s.hashProvider = mocks2.NewHashPasswordProvider(t)
+	s.totpProvider = totp.NewProvider("Sigma Music")
 }
 
 type AuthLogInSuite struct {
 	AuthSuite
 }
 
-func (s *AuthLogInSuite) CorrectRepositoryMock(userRepository *mocks3.UserRepository,
-	musicianRepository *mocks3.MusicianRepository, tokenProvider *mocks.TokenProvider,
-	user domain.User) {
+func (s *AuthLogInSuite) NoTwoFactorRepositoryMock(twoFactorRepository *mocks3.TwoFactorRepository,
+	account domain.Account) {
+	twoFactorRepository.
+		On("Get", context.Background(), account.ID).
+		Return(domain.TwoFactor{}, ports.ErrTwoFactorNotFound)
+}
+
+func (s *AuthLogInSuite) CorrectRepositoryMock(accountRepository *mocks3.AccountRepository,
+	userRepository *mocks3.UserRepository, tokenProvider *mocks.TokenProvider,
+	account domain.Account) {
+	accountRepository.
+		On("GetByName", context.Background(), account.Name).
+		Return(account, nil)
+
 	userRepository.
-		On("GetByName", context.Background(), user.Name).
-		Return(user, nil)
+		On("GetByID", context.Background(), account.ID).
+		Return(builder.NewUserBuilder().Default().SetID(account.ID).Build(), nil)
 
 	tokenProvider.
 		On("NewSession", context.Background(), domain.Payload{
-			UserID: user.ID,
+			UserID: account.ID,
 			Role:   domain.UserRole,
-		}).Return(domain.TokenPair{}, nil)
+		}, domain.SessionMetadata{}).Return(domain.TokenPair{}, nil)
 
 	s.hashProvider.
-		On("ComparePasswordWithHash", user.Password, domain.SaltedPassword{
-			HashPassword: user.Password,
-			Salt:         user.Salt,
+		On("ComparePasswordWithHash", account.Password, domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
 		}).Return(true)
+
+	s.hashProvider.
+		On("NeedsRehash", domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(false)
 }
 
 func (s *AuthLogInSuite) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Auth login test correct")
-	user := builder.NewUserBuilder().Default().Build()
-	loginCred := builder.NewLoginCredentialsMother(user.Name, user.Password).Create()
+	account := builder.NewAccountBuilder().Default().Build()
+	loginCred := builder.NewLoginCredentialsMother(account.Name, account.Password).Create()
+	tokenProvider := mocks.NewTokenProvider(t)
+	userRepository := mocks3.NewUserRepository(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.CorrectRepositoryMock(accountRepository, userRepository, tokenProvider, account)
+	s.NoTwoFactorRepositoryMock(twoFactorRepository, account)
+
+	_, err := authService.LogIn(context.Background(), loginCred)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AuthLogInSuite) EmailRepositoryMock(accountRepository *mocks3.AccountRepository,
+	tokenProvider *mocks.TokenProvider, account domain.Account) {
+	accountRepository.
+		On("GetByEmail", context.Background(), account.Email).
+		Return(account, nil)
+
+	tokenProvider.
+		On("NewSession", context.Background(), domain.Payload{
+			UserID: account.ID,
+			Role:   domain.MusicianRole,
+		}, domain.SessionMetadata{}).Return(domain.TokenPair{}, nil)
+
+	s.hashProvider.
+		On("ComparePasswordWithHash", account.Password, domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(true)
+
+	s.hashProvider.
+		On("NeedsRehash", domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(false)
+}
+
+func (s *AuthLogInSuite) TestMusicianByEmail(t provider.T) {
+	t.Parallel()
+	t.Title("Auth login test musician by email")
+	account := builder.NewAccountBuilder().Default().SetKind(domain.AccountKindMusician).Build()
+	loginCred := builder.NewLoginCredentialsMother(account.Email, account.Password).Create()
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
-	s.CorrectRepositoryMock(userRepository, musicianRepository, tokenProvider, user)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.EmailRepositoryMock(accountRepository, tokenProvider, account)
+	s.NoTwoFactorRepositoryMock(twoFactorRepository, account)
 
 	_, err := authService.LogIn(context.Background(), loginCred)
 
 	t.Assert().Nil(err)
 }
 
-func (s *AuthLogInSuite) ErrorRepositoryMock(userRepository *mocks3.UserRepository,
-	musicianRepository *mocks3.MusicianRepository, tokenProvider *mocks.TokenProvider,
-	user domain.User) {
+func (s *AuthLogInSuite) StaffRepositoryMock(accountRepository *mocks3.AccountRepository,
+	userRepository *mocks3.UserRepository, tokenProvider *mocks.TokenProvider,
+	account domain.Account) {
+	accountRepository.
+		On("GetByName", context.Background(), account.Name).
+		Return(account, nil)
+
 	userRepository.
-		On("GetByName", context.Background(), user.Name).
-		Return(domain.User{}, ports.ErrUserNameNotFound)
+		On("GetByID", context.Background(), account.ID).
+		Return(builder.NewUserBuilder().Default().SetID(account.ID).SetRole(domain.AdminRole).Build(), nil)
 
-	musicianRepository.
-		On("GetByName", context.Background(), user.Name).
-		Return(domain.Musician{}, ports.ErrMusicianNameNotFound)
+	tokenProvider.
+		On("NewSession", context.Background(), domain.Payload{
+			UserID: account.ID,
+			Role:   domain.AdminRole,
+		}, domain.SessionMetadata{}).Return(domain.TokenPair{}, nil)
 
+	s.hashProvider.
+		On("ComparePasswordWithHash", account.Password, domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(true)
+
+	s.hashProvider.
+		On("NeedsRehash", domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(false)
+}
+
+func (s *AuthLogInSuite) TestStaffRole(t provider.T) {
+	t.Parallel()
+	t.Title("Auth login test staff role from user profile")
+	account := builder.NewAccountBuilder().Default().Build()
+	loginCred := builder.NewLoginCredentialsMother(account.Name, account.Password).Create()
+	tokenProvider := mocks.NewTokenProvider(t)
+	userRepository := mocks3.NewUserRepository(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.StaffRepositoryMock(accountRepository, userRepository, tokenProvider, account)
+	s.NoTwoFactorRepositoryMock(twoFactorRepository, account)
+
+	_, err := authService.LogIn(context.Background(), loginCred)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AuthLogInSuite) LegacyHashRepositoryMock(accountRepository *mocks3.AccountRepository,
+	userRepository *mocks3.UserRepository, tokenProvider *mocks.TokenProvider,
+	hashProvider *mocks2.HashPasswordProvider, account domain.Account, rehashed domain.SaltedPassword) {
+	accountRepository.
+		On("GetByName", context.Background(), account.Name).
+		Return(account, nil)
+
+	legacy := domain.SaltedPassword{
+		HashPassword: account.Password,
+		Salt:         account.Salt,
+	}
+	hashProvider.
+		On("ComparePasswordWithHash", account.Password, legacy).
+		Return(true)
+	hashProvider.
+		On("NeedsRehash", legacy).
+		Return(true)
+	hashProvider.
+		On("EncodePassword", account.Password).
+		Return(rehashed)
+
+	accountRepository.
+		On("UpdatePassword", context.Background(), account.ID, rehashed).
+		Return(nil)
+
+	userRepository.
+		On("GetByID", context.Background(), account.ID).
+		Return(builder.NewUserBuilder().Default().SetID(account.ID).Build(), nil)
+
+	tokenProvider.
+		On("NewSession", context.Background(), domain.Payload{
+			UserID: account.ID,
+			Role:   domain.UserRole,
+		}, domain.SessionMetadata{}).Return(domain.TokenPair{}, nil)
+}
+
+func (s *AuthLogInSuite) TestLegacyHashUpgraded(t provider.T) {
+	t.Parallel()
+	t.Title("Auth login test legacy hash upgraded")
+	account := builder.NewAccountBuilder().Default().Build()
+	loginCred := builder.NewLoginCredentialsMother(account.Name, account.Password).Create()
+	rehashed := domain.SaltedPassword{HashPassword: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5"}
+	tokenProvider := mocks.NewTokenProvider(t)
+	userRepository := mocks3.NewUserRepository(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	hashProvider := mocks2.NewHashPasswordProvider(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, hashProvider, s.logger)
+	s.LegacyHashRepositoryMock(accountRepository, userRepository, tokenProvider, hashProvider, account, rehashed)
+	s.NoTwoFactorRepositoryMock(twoFactorRepository, account)
+
+	_, err := authService.LogIn(context.Background(), loginCred)
+
+	t.Assert().Nil(err)
+}
+
+func (s *AuthLogInSuite) ErrorRepositoryMock(accountRepository *mocks3.AccountRepository, account domain.Account) {
+	accountRepository.
+		On("GetByName", context.Background(), account.Name).
+		Return(domain.Account{}, ports.ErrAccountNotFound)
 }
 
 func (s *AuthLogInSuite) TestError(t provider.T) {
 	t.Parallel()
-	t.Title("Auth login test correct")
-	user := builder.NewUserBuilder().Default().Build()
-	loginCred := builder.NewLoginCredentialsMother(user.Name, user.Password).Create()
+	t.Title("Auth login test account not found")
+	account := builder.NewAccountBuilder().Default().Build()
+	loginCred := builder.NewLoginCredentialsMother(account.Name, account.Password).Create()
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
-	s.ErrorRepositoryMock(userRepository, musicianRepository, tokenProvider, user)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.ErrorRepositoryMock(accountRepository, account)
 
 	_, err := authService.LogIn(context.Background(), loginCred)
 
-	t.Assert().NotNil(err)
+	t.Assert().ErrorIs(err, ports.ErrIncorrectName)
+}
+
+func (s *AuthLogInSuite) EmailFallbackRepositoryMock(accountRepository *mocks3.AccountRepository, login string) {
+	accountRepository.
+		On("GetByEmail", context.Background(), login).
+		Return(domain.Account{}, ports.ErrAccountNotFound).
+		On("GetByName", context.Background(), login).
+		Return(domain.Account{}, ports.ErrAccountNotFound)
+}
+
+func (s *AuthLogInSuite) TestEmailNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Auth login test unknown email")
+	loginCred := builder.NewLoginCredentialsMother("nobody@mail.com", "password").Create()
+	tokenProvider := mocks.NewTokenProvider(t)
+	userRepository := mocks3.NewUserRepository(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.EmailFallbackRepositoryMock(accountRepository, loginCred.Name)
+
+	_, err := authService.LogIn(context.Background(), loginCred)
+
+	t.Assert().ErrorIs(err, ports.ErrIncorrectName)
+}
+
+func (s *AuthLogInSuite) TwoFactorRepositoryMock(accountRepository *mocks3.AccountRepository,
+	twoFactorRepository *mocks3.TwoFactorRepository, oneTimeTokens *mocks.OneTimeTokenProvider,
+	account domain.Account, challengeToken string) {
+	accountRepository.
+		On("GetByName", context.Background(), account.Name).
+		Return(account, nil)
+
+	s.hashProvider.
+		On("ComparePasswordWithHash", account.Password, domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(true)
+
+	s.hashProvider.
+		On("NeedsRehash", domain.SaltedPassword{
+			HashPassword: account.Password,
+			Salt:         account.Salt,
+		}).Return(false)
+
+	twoFactorRepository.
+		On("Get", context.Background(), account.ID).
+		Return(domain.TwoFactor{AccountID: account.ID, Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+
+	oneTimeTokens.
+		On("Issue", context.Background(), domain.OneTimeToken{
+			Purpose:   domain.OneTimeTokenTwoFactor,
+			AccountID: account.ID,
+		}, mock.Anything).Return(challengeToken, nil)
+}
+
+func (s *AuthLogInSuite) TestTwoFactorChallenge(t provider.T) {
+	t.Parallel()
+	t.Title("Auth login test two-factor challenge issued")
+	account := builder.NewAccountBuilder().Default().Build()
+	loginCred := builder.NewLoginCredentialsMother(account.Name, account.Password).Create()
+	challengeToken := "challenge"
+	oneTimeTokens := mocks.NewOneTimeTokenProvider(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, mocks3.NewUserRepository(t), twoFactorRepository,
+		mocks.NewTokenProvider(t), oneTimeTokens, s.totpProvider, s.hashProvider, s.logger)
+	s.TwoFactorRepositoryMock(accountRepository, twoFactorRepository, oneTimeTokens, account, challengeToken)
+
+	result, err := authService.LogIn(context.Background(), loginCred)
+
+	t.Assert().Nil(err)
+	t.Assert().True(result.ChallengeRequired())
+	t.Assert().Equal(challengeToken, result.ChallengeToken)
+	t.Assert().Equal(domain.TokenPair{}, result.Tokens)
 }
 
 func TestAuthLogInSuite(t *testing.T) {
 	suite.RunSuite(t, new(AuthLogInSuite))
 }
 
+type AuthLogInTwoFactorSuite struct {
+	AuthSuite
+}
+
+func (s *AuthLogInTwoFactorSuite) CorrectRepositoryMock(accountRepository *mocks3.AccountRepository,
+	userRepository *mocks3.UserRepository, twoFactorRepository *mocks3.TwoFactorRepository,
+	tokenProvider *mocks.TokenProvider, oneTimeTokens *mocks.OneTimeTokenProvider,
+	account domain.Account, twoFactor domain.TwoFactor, challengeToken string) {
+	oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenTwoFactor, challengeToken).
+		Return(domain.OneTimeToken{Purpose: domain.OneTimeTokenTwoFactor, AccountID: account.ID}, nil)
+
+	twoFactorRepository.
+		On("Get", context.Background(), account.ID).
+		Return(twoFactor, nil).
+		On("UseStep", context.Background(), account.ID, mock.AnythingOfType("int64")).
+		Return(nil)
+
+	accountRepository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+
+	userRepository.
+		On("GetByID", context.Background(), account.ID).
+		Return(builder.NewUserBuilder().Default().SetID(account.ID).Build(), nil)
+
+	tokenProvider.
+		On("NewSession", context.Background(), domain.Payload{
+			UserID: account.ID,
+			Role:   domain.UserRole,
+		}, domain.SessionMetadata{}).Return(domain.TokenPair{}, nil)
+}
+
+func (s *AuthLogInTwoFactorSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Auth two-factor login test correct")
+	account := builder.NewAccountBuilder().Default().Build()
+	twoFactor := domain.TwoFactor{AccountID: account.ID, Secret: "JBSWY3DPEHPK3PXP", Enabled: true}
+	challengeToken := "challenge"
+	code, _ := totp.Code(twoFactor.Secret, time.Now())
+	tokenProvider := mocks.NewTokenProvider(t)
+	oneTimeTokens := mocks.NewOneTimeTokenProvider(t)
+	userRepository := mocks3.NewUserRepository(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, twoFactorRepository,
+		tokenProvider, oneTimeTokens, s.totpProvider, s.hashProvider, s.logger)
+	s.CorrectRepositoryMock(accountRepository, userRepository, twoFactorRepository, tokenProvider, oneTimeTokens,
+		account, twoFactor, challengeToken)
+
+	_, err := authService.LogInTwoFactor(context.Background(), ports.TwoFactorCredentials{
+		ChallengeToken: challengeToken,
+		Code:           code,
+	})
+
+	t.Assert().Nil(err)
+}
+
+func (s *AuthLogInTwoFactorSuite) InvalidChallengeRepositoryMock(oneTimeTokens *mocks.OneTimeTokenProvider,
+	challengeToken string) {
+	oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenTwoFactor, challengeToken).
+		Return(domain.OneTimeToken{}, ports.ErrOneTimeTokenInvalid)
+}
+
+func (s *AuthLogInTwoFactorSuite) TestInvalidChallenge(t provider.T) {
+	t.Parallel()
+	t.Title("Auth two-factor login test invalid challenge")
+	challengeToken := "challenge"
+	oneTimeTokens := mocks.NewOneTimeTokenProvider(t)
+	authService := service.NewAuthorizationService(mocks3.NewAccountRepository(t), mocks3.NewUserRepository(t),
+		mocks3.NewTwoFactorRepository(t), mocks.NewTokenProvider(t), oneTimeTokens, s.totpProvider,
+		s.hashProvider, s.logger)
+	s.InvalidChallengeRepositoryMock(oneTimeTokens, challengeToken)
+
+	_, err := authService.LogInTwoFactor(context.Background(), ports.TwoFactorCredentials{
+		ChallengeToken: challengeToken,
+		Code:           "123456",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrInvalidToken)
+}
+
+func (s *AuthLogInTwoFactorSuite) InvalidCodeRepositoryMock(twoFactorRepository *mocks3.TwoFactorRepository,
+	oneTimeTokens *mocks.OneTimeTokenProvider, twoFactor domain.TwoFactor, challengeToken string, code string) {
+	oneTimeTokens.
+		On("Consume", context.Background(), domain.OneTimeTokenTwoFactor, challengeToken).
+		Return(domain.OneTimeToken{Purpose: domain.OneTimeTokenTwoFactor, AccountID: twoFactor.AccountID}, nil)
+
+	twoFactorRepository.
+		On("Get", context.Background(), twoFactor.AccountID).
+		Return(twoFactor, nil).
+		On("UseRecoveryCode", context.Background(), twoFactor.AccountID, s.totpProvider.HashRecoveryCode(code)).
+		Return(ports.ErrTwoFactorInvalidCode)
+}
+
+func (s *AuthLogInTwoFactorSuite) TestInvalidCode(t provider.T) {
+	t.Parallel()
+	t.Title("Auth two-factor login test invalid code")
+	twoFactor := domain.TwoFactor{AccountID: uuid.New(), Secret: "JBSWY3DPEHPK3PXP", Enabled: true}
+	challengeToken := "challenge"
+	code := "wrong-code"
+	oneTimeTokens := mocks.NewOneTimeTokenProvider(t)
+	twoFactorRepository := mocks3.NewTwoFactorRepository(t)
+	authService := service.NewAuthorizationService(mocks3.NewAccountRepository(t), mocks3.NewUserRepository(t),
+		twoFactorRepository, mocks.NewTokenProvider(t), oneTimeTokens, s.totpProvider, s.hashProvider, s.logger)
+	s.InvalidCodeRepositoryMock(twoFactorRepository, oneTimeTokens, twoFactor, challengeToken, code)
+
+	_, err := authService.LogInTwoFactor(context.Background(), ports.TwoFactorCredentials{
+		ChallengeToken: challengeToken,
+		Code:           code,
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrTwoFactorInvalidCode)
+}
+
+func TestAuthLogInTwoFactorSuite(t *testing.T) {
+	suite.RunSuite(t, new(AuthLogInTwoFactorSuite))
+}
+
 type AuthLogOutSuite struct {
 	AuthSuite
 }
 
 func (s *AuthLogOutSuite) CorrectRepositoryMock(userRepository *mocks3.UserRepository,
-	musicianRepository *mocks3.MusicianRepository, tokenProvider *mocks.TokenProvider,
+	accountRepository *mocks3.AccountRepository, tokenProvider *mocks.TokenProvider,
 	tokenString string) {
 	tokenProvider.
 		On("CloseSession", context.Background(), tokenString).
@@ -122,10 +487,10 @@ func (s *AuthLogOutSuite) TestCorrect(t provider.T) {
 	tokenString := "tokenstring"
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
-	s.CorrectRepositoryMock(userRepository, musicianRepository, tokenProvider, tokenString)
+	accountRepository := mocks3.NewAccountRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, mocks3.NewTwoFactorRepository(t),
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.CorrectRepositoryMock(userRepository, accountRepository, tokenProvider, tokenString)
 
 	err := authService.LogOut(context.Background(), tokenString)
 
@@ -133,7 +498,7 @@ func (s *AuthLogOutSuite) TestCorrect(t provider.T) {
 }
 
 func (s *AuthLogOutSuite) TokenExpiredRepositoryMock(userRepository *mocks3.UserRepository,
-	musicianRepository *mocks3.MusicianRepository, tokenProvider *mocks.TokenProvider,
+	accountRepository *mocks3.AccountRepository, tokenProvider *mocks.TokenProvider,
 	tokenString string, payload domain.Payload) {
 	tokenProvider.
 		On("CloseSession", context.Background(), tokenString).
@@ -151,10 +516,10 @@ func (s *AuthLogOutSuite) TestTokenExpired(t provider.T) {
 	payload := builder.NewPayloadBuilder().Default().Build()
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
-	s.TokenExpiredRepositoryMock(userRepository, musicianRepository, tokenProvider, tokenString, payload)
+	accountRepository := mocks3.NewAccountRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, mocks3.NewTwoFactorRepository(t),
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.TokenExpiredRepositoryMock(userRepository, accountRepository, tokenProvider, tokenString, payload)
 
 	err := authService.LogOut(context.Background(), tokenString)
 
@@ -183,9 +548,9 @@ func (s *AuthVerifyTokenSuite) TestCorrect(t provider.T) {
 	payload := builder.NewPayloadBuilder().Default().Build()
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
+	accountRepository := mocks3.NewAccountRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, mocks3.NewTwoFactorRepository(t),
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(tokenProvider, tokenString, payload)
 
 	servicePayload, err := authService.VerifyToken(context.Background(), tokenString)
@@ -209,9 +574,9 @@ func (s *AuthVerifyTokenSuite) TestInvalidToken(t provider.T) {
 	payload := builder.NewPayloadBuilder().Default().Build()
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
+	accountRepository := mocks3.NewAccountRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, mocks3.NewTwoFactorRepository(t),
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
 	s.InvalidTokenRepositoryMock(tokenProvider, tokenString, payload)
 
 	servicePayload, err := authService.VerifyToken(context.Background(), tokenString)
@@ -242,9 +607,9 @@ func (s *AuthRefreshTokenSuite) TestCorrect(t provider.T) {
 	tokenPair := builder.NewTokenPairBuilder().Default().Build()
 	tokenProvider := mocks.NewTokenProvider(t)
 	userRepository := mocks3.NewUserRepository(t)
-	musicianRepository := mocks3.NewMusicianRepository(t)
-	authService := service.NewAuthorizationService(userRepository, musicianRepository,
-		tokenProvider, s.hashProvider, s.logger)
+	accountRepository := mocks3.NewAccountRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, mocks3.NewTwoFactorRepository(t),
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(tokenProvider, tokenString, tokenPair)
 
 	serviceTokenPair, err := authService.RefreshToken(context.Background(), tokenString)
@@ -253,6 +618,102 @@ func (s *AuthRefreshTokenSuite) TestCorrect(t provider.T) {
 	t.Assert().Nil(err)
 }
 
+func (s *AuthRefreshTokenSuite) ReusedRepositoryMock(tokenProvider *mocks.TokenProvider,
+	refreshTokenString string) {
+	tokenProvider.
+		On("RefreshSession", context.Background(), refreshTokenString).
+		Return(domain.TokenPair{}, ports.ErrTokenProviderReusedToken)
+}
+
+func (s *AuthRefreshTokenSuite) TestReused(t provider.T) {
+	t.Parallel()
+	t.Title("Auth refresh token test reused token")
+	tokenString := "tokenstring"
+	tokenProvider := mocks.NewTokenProvider(t)
+	userRepository := mocks3.NewUserRepository(t)
+	accountRepository := mocks3.NewAccountRepository(t)
+	authService := service.NewAuthorizationService(accountRepository, userRepository, mocks3.NewTwoFactorRepository(t),
+		tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.ReusedRepositoryMock(tokenProvider, tokenString)
+
+	_, err := authService.RefreshToken(context.Background(), tokenString)
+
+	t.Assert().ErrorIs(err, ports.ErrInvalidToken)
+}
+
 func TestAuthRefreshTokenSuite(t *testing.T) {
 	suite.RunSuite(t, new(AuthRefreshTokenSuite))
 }
+
+type AuthSessionsSuite struct {
+	AuthSuite
+}
+
+func (s *AuthSessionsSuite) GetSessionsRepositoryMock(tokenProvider *mocks.TokenProvider,
+	userID uuid.UUID, sessions []domain.Session) {
+	tokenProvider.
+		On("GetSessions", context.Background(), userID).
+		Return(sessions, nil)
+}
+
+func (s *AuthSessionsSuite) TestGetSessions(t provider.T) {
+	t.Parallel()
+	t.Title("Auth get sessions test correct")
+	userID := uuid.New()
+	sessions := []domain.Session{{ID: uuid.New(), UserID: userID}}
+	tokenProvider := mocks.NewTokenProvider(t)
+	authService := service.NewAuthorizationService(mocks3.NewAccountRepository(t), mocks3.NewUserRepository(t),
+		mocks3.NewTwoFactorRepository(t), tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.GetSessionsRepositoryMock(tokenProvider, userID, sessions)
+
+	serviceSessions, err := authService.GetSessions(context.Background(), userID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(sessions, serviceSessions)
+}
+
+func (s *AuthSessionsSuite) RevokeNotFoundRepositoryMock(tokenProvider *mocks.TokenProvider,
+	userID uuid.UUID, sessionID uuid.UUID) {
+	tokenProvider.
+		On("RevokeSession", context.Background(), userID, sessionID).
+		Return(ports.ErrSessionNotFound)
+}
+
+func (s *AuthSessionsSuite) TestRevokeNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Auth revoke session test not found")
+	userID := uuid.New()
+	sessionID := uuid.New()
+	tokenProvider := mocks.NewTokenProvider(t)
+	authService := service.NewAuthorizationService(mocks3.NewAccountRepository(t), mocks3.NewUserRepository(t),
+		mocks3.NewTwoFactorRepository(t), tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.RevokeNotFoundRepositoryMock(tokenProvider, userID, sessionID)
+
+	err := authService.RevokeSession(context.Background(), userID, sessionID)
+
+	t.Assert().ErrorIs(err, ports.ErrSessionNotFound)
+}
+
+func (s *AuthSessionsSuite) LogOutEverywhereRepositoryMock(tokenProvider *mocks.TokenProvider, userID uuid.UUID) {
+	tokenProvider.
+		On("RevokeAllSessions", context.Background(), userID).
+		Return(nil)
+}
+
+func (s *AuthSessionsSuite) TestLogOutEverywhere(t provider.T) {
+	t.Parallel()
+	t.Title("Auth log out everywhere test correct")
+	userID := uuid.New()
+	tokenProvider := mocks.NewTokenProvider(t)
+	authService := service.NewAuthorizationService(mocks3.NewAccountRepository(t), mocks3.NewUserRepository(t),
+		mocks3.NewTwoFactorRepository(t), tokenProvider, mocks.NewOneTimeTokenProvider(t), s.totpProvider, s.hashProvider, s.logger)
+	s.LogOutEverywhereRepositoryMock(tokenProvider, userID)
+
+	err := authService.LogOutEverywhere(context.Background(), userID)
+
+	t.Assert().Nil(err)
+}
+
+func TestAuthSessionsSuite(t *testing.T) {
+	suite.RunSuite(t, new(AuthSessionsSuite))
+}
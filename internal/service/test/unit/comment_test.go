@@ -3,8 +3,10 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
@@ -33,8 +35,10 @@ type CommentPostSuite struct {
 
 func (s *CommentPostSuite) CorrectRepositoryMock(repository *mocks.CommentRepository) {
 	repository.
-		On("Create", context.Background(), mock.AnythingOfType("domain.Comment")).
-		Return(domain.Comment{}, nil)
+		On("Upsert", context.Background(), mock.AnythingOfType("domain.Comment")).
+		Return(func(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+			return comment, nil
+		})
 }
 
 func (s *CommentPostSuite) TestCorrect(t provider.T) {
@@ -42,64 +46,276 @@ func (s *CommentPostSuite) TestCorrect(t provider.T) {
 	t.Title("Comment post test correct")
 	req := builder.NewPostCommentServiceRequestBuilder().Default().Build()
 	repository := mocks.NewCommentRepository(t)
-	commentService := service.NewCommentService(repository, s.logger)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
 	s.CorrectRepositoryMock(repository)
 
-	_, err := commentService.Post(context.Background(), req)
+	comment, err := commentService.Post(context.Background(), req)
 
 	t.Assert().Nil(err)
+	t.Assert().Equal(req.Stars, comment.Stars)
+	t.Assert().False(comment.IsReply())
 }
 
-func (s *CommentPostSuite) DuplicateRepositoryMock(repository *mocks.CommentRepository) {
+func (s *CommentPostSuite) TestInvalidStars(t provider.T) {
+	t.Parallel()
+	t.Title("Comment post test invalid stars")
+	req := builder.NewPostCommentServiceRequestBuilder().Default().SetStars(6).Build()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+
+	_, err := commentService.Post(context.Background(), req)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentStars)
+}
+
+func (s *CommentPostSuite) UnknownTrackRepositoryMock(repository *mocks.CommentRepository) {
 	repository.
-		On("Create", context.Background(), mock.AnythingOfType("domain.Comment")).
-		Return(domain.Comment{}, ports.ErrCommentDuplicate)
+		On("Upsert", context.Background(), mock.AnythingOfType("domain.Comment")).
+		Return(domain.Comment{}, ports.ErrTrackIDNotFound)
 }
 
-func (s *CommentPostSuite) TestDuplicate(t provider.T) {
+func (s *CommentPostSuite) TestUnknownTrack(t provider.T) {
 	t.Parallel()
-	t.Title("Comment post test duplicate")
+	t.Title("Comment post test unknown track")
 	req := builder.NewPostCommentServiceRequestBuilder().Default().Build()
 	repository := mocks.NewCommentRepository(t)
-	commentService := service.NewCommentService(repository, s.logger)
-	s.DuplicateRepositoryMock(repository)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.UnknownTrackRepositoryMock(repository)
+
+	_, err := commentService.Post(context.Background(), req)
+
+	t.Assert().ErrorIs(err, ports.ErrTrackIDNotFound)
+}
+
+func (s *CommentPostSuite) TestBannedWord(t provider.T) {
+	t.Parallel()
+	t.Title("Comment post test banned word")
+	req := builder.NewPostCommentServiceRequestBuilder().Default().SetText("What a DARN good song").Build()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{
+		BannedWords: []string{"darn"},
+	}, s.logger)
 
 	_, err := commentService.Post(context.Background(), req)
 
-	t.Assert().ErrorIs(err, ports.ErrCommentDuplicate)
+	t.Assert().ErrorIs(err, ports.ErrCommentProfanity)
+}
+
+func (s *CommentPostSuite) TestBannedWordInsideOtherWord(t provider.T) {
+	t.Parallel()
+	t.Title("Comment post test banned word only matched whole")
+	req := builder.NewPostCommentServiceRequestBuilder().Default().SetText("Darnell sings well").Build()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{
+		BannedWords: []string{"darn"},
+	}, s.logger)
+	s.CorrectRepositoryMock(repository)
+
+	_, err := commentService.Post(context.Background(), req)
+
+	t.Assert().Nil(err)
 }
 
 func TestCommentPostSuite(t *testing.T) {
 	suite.RunSuite(t, new(CommentPostSuite))
 }
 
+type CommentReplySuite struct {
+	CommentSuite
+}
+
+func (s *CommentReplySuite) CorrectRepositoryMock(repository *mocks.CommentRepository, parent domain.Comment) {
+	repository.
+		On("GetByID", context.Background(), parent.ID).
+		Return(parent, nil)
+
+	repository.
+		On("Create", context.Background(), mock.AnythingOfType("domain.Comment")).
+		Return(func(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+			return comment, nil
+		})
+}
+
+func (s *CommentReplySuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Comment reply test correct")
+	review := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 4}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, review)
+
+	reply, err := commentService.Reply(context.Background(), ports.ReplyCommentServiceReq{
+		UserID:   uuid.New(),
+		ParentID: review.ID,
+		Text:     "Agreed",
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(review.ID, reply.ParentID)
+	t.Assert().Equal(review.TrackID, reply.TrackID)
+	t.Assert().Zero(reply.Stars)
+}
+
+func (s *CommentReplySuite) TestReplyToReply(t provider.T) {
+	t.Parallel()
+	t.Title("Comment reply test reply to reply joins the review thread")
+	reviewID := uuid.New()
+	parent := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), ParentID: reviewID}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, parent)
+
+	reply, err := commentService.Reply(context.Background(), ports.ReplyCommentServiceReq{
+		UserID:   uuid.New(),
+		ParentID: parent.ID,
+		Text:     "Me too",
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(reviewID, reply.ParentID)
+}
+
+func (s *CommentReplySuite) NotFoundRepositoryMock(repository *mocks.CommentRepository, parentID uuid.UUID) {
+	repository.
+		On("GetByID", context.Background(), parentID).
+		Return(domain.Comment{}, ports.ErrCommentIDNotFound)
+}
+
+func (s *CommentReplySuite) TestParentNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Comment reply test parent not found")
+	parentID := uuid.New()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.NotFoundRepositoryMock(repository, parentID)
+
+	_, err := commentService.Reply(context.Background(), ports.ReplyCommentServiceReq{
+		UserID:   uuid.New(),
+		ParentID: parentID,
+		Text:     "Hello",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrCommentIDNotFound)
+}
+
+func TestCommentReplySuite(t *testing.T) {
+	suite.RunSuite(t, new(CommentReplySuite))
+}
+
+type CommentEditSuite struct {
+	CommentSuite
+}
+
+func (s *CommentEditSuite) CorrectRepositoryMock(repository *mocks.CommentRepository, comment domain.Comment) {
+	repository.
+		On("GetByID", context.Background(), comment.ID).
+		Return(comment, nil)
+
+	repository.
+		On("Update", context.Background(), mock.AnythingOfType("domain.Comment")).
+		Return(func(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+			return comment, nil
+		}).
+		Maybe()
+}
+
+func (s *CommentEditSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Comment edit test correct")
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 2, Text: "Meh"}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, comment)
+
+	edited, err := commentService.Edit(context.Background(), ports.EditCommentServiceReq{
+		UserID:    comment.UserID,
+		CommentID: comment.ID,
+		Stars:     null.IntFrom(4),
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(4, edited.Stars)
+	t.Assert().Equal(comment.Text, edited.Text)
+	t.Assert().True(edited.EditedAt.Valid)
+}
+
+func (s *CommentEditSuite) TestNotOwner(t provider.T) {
+	t.Parallel()
+	t.Title("Comment edit test not owner")
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 2}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, comment)
+
+	_, err := commentService.Edit(context.Background(), ports.EditCommentServiceReq{
+		UserID:    uuid.New(),
+		CommentID: comment.ID,
+		Text:      null.StringFrom("Mine now"),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrCommentNotOwner)
+}
+
+func (s *CommentEditSuite) TestStarsOnReply(t provider.T) {
+	t.Parallel()
+	t.Title("Comment edit test stars on reply")
+	reply := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), ParentID: uuid.New()}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, reply)
+
+	_, err := commentService.Edit(context.Background(), ports.EditCommentServiceReq{
+		UserID:    reply.UserID,
+		CommentID: reply.ID,
+		Stars:     null.IntFrom(5),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrCommentStars)
+}
+
+func TestCommentEditSuite(t *testing.T) {
+	suite.RunSuite(t, new(CommentEditSuite))
+}
+
 type CommentGetCommentsOnTrack struct {
 	CommentSuite
 }
 
-func (s *CommentGetCommentsOnTrack) CorrectRepositoryMock(repository *mocks.CommentRepository, trackID uuid.UUID) {
+func (s *CommentGetCommentsOnTrack) CorrectRepositoryMock(repository *mocks.CommentRepository, trackID uuid.UUID,
+	review domain.Comment, reply domain.Comment) {
 	repository.
-		On("GetByTrackID", context.Background(), trackID).
-		Return([]domain.Comment{}, nil)
+		On("GetByTrackID", context.Background(), trackID, domain.CommentPage{
+			Sort:  domain.CommentSortNewest,
+			Limit: 20,
+		}).
+		Return([]domain.Comment{review}, 1, nil)
+
+	repository.
+		On("GetReplies", context.Background(), []uuid.UUID{review.ID}).
+		Return([]domain.Comment{reply}, nil)
 }
 
 func (s *CommentGetCommentsOnTrack) TestCorrect(t provider.T) {
 	t.Parallel()
 	t.Title("Comment get comments on track test correct")
 	trackID := uuid.New()
+	review := domain.Comment{ID: uuid.New(), TrackID: trackID, Stars: 5}
+	reply := domain.Comment{ID: uuid.New(), TrackID: trackID, ParentID: review.ID}
 	repository := mocks.NewCommentRepository(t)
-	commentService := service.NewCommentService(repository, s.logger)
-	s.CorrectRepositoryMock(repository, trackID)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, trackID, review, reply)
 
-	_, err := commentService.GetCommentsOnTrack(context.Background(), trackID)
+	comments, total, err := commentService.GetCommentsOnTrack(context.Background(), trackID, domain.CommentPage{})
 
 	t.Assert().Nil(err)
+	t.Assert().Equal(1, total)
+	t.Assert().Equal([]domain.Comment{reply}, comments[0].Replies)
 }
 
 func (s *CommentGetCommentsOnTrack) NotFoundRepositoryMock(repository *mocks.CommentRepository, trackID uuid.UUID) {
 	repository.
-		On("GetByTrackID", context.Background(), trackID).
-		Return([]domain.Comment{}, ports.ErrCommentByTrackIDNotFound)
+		On("GetByTrackID", context.Background(), trackID, mock.AnythingOfType("domain.CommentPage")).
+		Return(nil, 0, ports.ErrCommentByTrackIDNotFound)
 }
 
 func (s *CommentGetCommentsOnTrack) TestIDNotFound(t provider.T) {
@@ -107,10 +323,10 @@ func (s *CommentGetCommentsOnTrack) TestIDNotFound(t provider.T) {
 	t.Title("Comment get comments on track test not found")
 	trackID := uuid.New()
 	repository := mocks.NewCommentRepository(t)
-	commentService := service.NewCommentService(repository, s.logger)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
 	s.NotFoundRepositoryMock(repository, trackID)
 
-	_, err := commentService.GetCommentsOnTrack(context.Background(), trackID)
+	_, _, err := commentService.GetCommentsOnTrack(context.Background(), trackID, domain.CommentPage{})
 
 	t.Assert().ErrorIs(err, ports.ErrCommentByTrackIDNotFound)
 }
@@ -134,7 +350,7 @@ func (s *CommentGetUserComments) TestCorrect(t provider.T) {
 	t.Title("Comment get user comments test correct")
 	userID := uuid.New()
 	repository := mocks.NewCommentRepository(t)
-	commentService := service.NewCommentService(repository, s.logger)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
 	s.CorrectRepositoryMock(repository, userID)
 
 	_, err := commentService.GetUserComments(context.Background(), userID)
@@ -153,7 +369,7 @@ func (s *CommentGetUserComments) TestIDNotFound(t provider.T) {
 	t.Title("Comment get user comments test not found")
 	userID := uuid.New()
 	repository := mocks.NewCommentRepository(t)
-	commentService := service.NewCommentService(repository, s.logger)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
 	s.NotFoundRepositoryMock(repository, userID)
 
 	_, err := commentService.GetUserComments(context.Background(), userID)
@@ -164,3 +380,279 @@ func (s *CommentGetUserComments) TestIDNotFound(t provider.T) {
 func TestCommentGetUserCommentsSuite(t *testing.T) {
 	suite.RunSuite(t, new(CommentGetUserComments))
 }
+
+type CommentModerationSuite struct {
+	CommentSuite
+}
+
+func (s *CommentModerationSuite) ReportRepositoryMock(repository *mocks.CommentRepository, comment domain.Comment) {
+	repository.
+		On("GetByID", context.Background(), comment.ID).
+		Return(comment, nil)
+
+	repository.
+		On("CreateReport", context.Background(), mock.AnythingOfType("domain.CommentReport")).
+		Return(func(ctx context.Context, report domain.CommentReport) (domain.CommentReport, error) {
+			return report, nil
+		}).
+		Maybe()
+}
+
+func (s *CommentModerationSuite) TestReport(t provider.T) {
+	t.Parallel()
+	t.Title("Comment report test correct")
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 1}
+	reporterID := uuid.New()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.ReportRepositoryMock(repository, comment)
+
+	report, err := commentService.Report(context.Background(), ports.ReportCommentServiceReq{
+		UserID:    reporterID,
+		CommentID: comment.ID,
+		Reason:    "insults",
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(comment.ID, report.CommentID)
+	t.Assert().Equal(reporterID, report.ReporterID)
+}
+
+func (s *CommentModerationSuite) TestReportOwn(t provider.T) {
+	t.Parallel()
+	t.Title("Comment report test own comment")
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 1}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.ReportRepositoryMock(repository, comment)
+
+	_, err := commentService.Report(context.Background(), ports.ReportCommentServiceReq{
+		UserID:    comment.UserID,
+		CommentID: comment.ID,
+		Reason:    "mistake",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrCommentReportOwn)
+}
+
+func (s *CommentModerationSuite) HideRepositoryMock(repository *mocks.CommentRepository, commentID uuid.UUID) {
+	repository.
+		On("Hide", context.Background(), commentID, domain.CommentHiddenByModerator, mock.AnythingOfType("time.Time")).
+		Return(domain.Comment{ID: commentID, HiddenBy: domain.CommentHiddenByModerator}, nil)
+
+	repository.
+		On("ResolveReports", context.Background(), commentID, mock.AnythingOfType("time.Time")).
+		Return(nil)
+}
+
+func (s *CommentModerationSuite) TestHide(t provider.T) {
+	t.Parallel()
+	t.Title("Comment hide test resolves reports")
+	commentID := uuid.New()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.HideRepositoryMock(repository, commentID)
+
+	comment, err := commentService.Hide(context.Background(), commentID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.CommentHiddenByModerator, comment.HiddenBy)
+}
+
+func TestCommentModerationSuite(t *testing.T) {
+	suite.RunSuite(t, new(CommentModerationSuite))
+}
+
+type CommentOwnTrackSuite struct {
+	CommentSuite
+}
+
+func (s *CommentOwnTrackSuite) newCommentService(t provider.T, comment domain.Comment,
+	musicianID uuid.UUID, ownTracks []domain.Track) (*service.CommentService, *mocks.CommentRepository) {
+	repository := mocks.NewCommentRepository(t)
+	trackRepository := mocks.NewTrackRepository(t)
+	trackService := service.NewTrackService(trackRepository, nil, nil, s.logger)
+
+	repository.
+		On("GetByID", context.Background(), comment.ID).
+		Return(comment, nil)
+
+	trackRepository.
+		On("GetOwn", context.Background(), musicianID, domain.TrackSortDefault).
+		Return(ownTracks, nil)
+
+	return service.NewCommentService(repository, trackService, service.CommentServiceConfig{}, s.logger), repository
+}
+
+func (s *CommentOwnTrackSuite) TestHide(t provider.T) {
+	t.Parallel()
+	t.Title("Comment hide on own track test correct")
+	musicianID := uuid.New()
+	track := domain.Track{ID: uuid.New()}
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: track.ID, Stars: 1}
+	commentService, repository := s.newCommentService(t, comment, musicianID, []domain.Track{track})
+	repository.
+		On("Hide", context.Background(), comment.ID, domain.CommentHiddenByMusician, mock.AnythingOfType("time.Time")).
+		Return(domain.Comment{ID: comment.ID, HiddenBy: domain.CommentHiddenByMusician}, nil)
+
+	hidden, err := commentService.HideOnOwnTrack(context.Background(), musicianID, comment.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.CommentHiddenByMusician, hidden.HiddenBy)
+}
+
+func (s *CommentOwnTrackSuite) TestHideOtherTrack(t provider.T) {
+	t.Parallel()
+	t.Title("Comment hide on own track test track of another musician")
+	musicianID := uuid.New()
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 1}
+	commentService, _ := s.newCommentService(t, comment, musicianID, []domain.Track{{ID: uuid.New()}})
+
+	_, err := commentService.HideOnOwnTrack(context.Background(), musicianID, comment.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentNotOnOwnTrack)
+}
+
+func (s *CommentOwnTrackSuite) TestRestoreHiddenByModerator(t provider.T) {
+	t.Parallel()
+	t.Title("Comment restore on own track test hidden by moderator")
+	musicianID := uuid.New()
+	track := domain.Track{ID: uuid.New()}
+	comment := domain.Comment{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		TrackID:  track.ID,
+		Stars:    1,
+		HiddenAt: null.TimeFrom(time.Now()),
+		HiddenBy: domain.CommentHiddenByModerator,
+	}
+	commentService, _ := s.newCommentService(t, comment, musicianID, []domain.Track{track})
+
+	_, err := commentService.RestoreOnOwnTrack(context.Background(), musicianID, comment.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentHiddenByModerator)
+}
+
+func (s *CommentOwnTrackSuite) TestPin(t provider.T) {
+	t.Parallel()
+	t.Title("Comment pin test correct")
+	musicianID := uuid.New()
+	track := domain.Track{ID: uuid.New()}
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: track.ID, Stars: 5}
+	commentService, repository := s.newCommentService(t, comment, musicianID, []domain.Track{track})
+	repository.
+		On("SetPinned", context.Background(), comment.ID, mock.MatchedBy(func(pinnedAt null.Time) bool {
+			return pinnedAt.Valid
+		})).
+		Return(func(ctx context.Context, commentID uuid.UUID, pinnedAt null.Time) (domain.Comment, error) {
+			pinned := comment
+			pinned.PinnedAt = pinnedAt
+			return pinned, nil
+		})
+
+	pinned, err := commentService.Pin(context.Background(), musicianID, comment.ID, true)
+
+	t.Assert().Nil(err)
+	t.Assert().True(pinned.PinnedAt.Valid)
+}
+
+func (s *CommentOwnTrackSuite) TestPinReply(t provider.T) {
+	t.Parallel()
+	t.Title("Comment pin test reply")
+	musicianID := uuid.New()
+	track := domain.Track{ID: uuid.New()}
+	reply := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: track.ID, ParentID: uuid.New()}
+	commentService, _ := s.newCommentService(t, reply, musicianID, []domain.Track{track})
+
+	_, err := commentService.Pin(context.Background(), musicianID, reply.ID, true)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentPinReply)
+}
+
+func TestCommentOwnTrackSuite(t *testing.T) {
+	suite.RunSuite(t, new(CommentOwnTrackSuite))
+}
+
+type CommentVoteSuite struct {
+	CommentSuite
+}
+
+func (s *CommentVoteSuite) CorrectRepositoryMock(repository *mocks.CommentRepository, comment domain.Comment,
+	voted domain.Comment, userID uuid.UUID) {
+	repository.
+		On("GetByID", context.Background(), comment.ID).
+		Return(comment, nil).
+		Once()
+
+	repository.
+		On("Vote", context.Background(), mock.MatchedBy(func(vote domain.CommentVote) bool {
+			return vote.CommentID == comment.ID && vote.UserID == userID && vote.Helpful
+		})).
+		Return(nil)
+
+	repository.
+		On("GetByID", context.Background(), comment.ID).
+		Return(voted, nil).
+		Once()
+}
+
+func (s *CommentVoteSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Comment vote test correct")
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 4}
+	voted := comment
+	voted.HelpfulVotes = 1
+	userID := uuid.New()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, comment, voted, userID)
+
+	result, err := commentService.Vote(context.Background(), userID, comment.ID, true)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(1, result.HelpfulVotes)
+}
+
+func (s *CommentVoteSuite) OwnRepositoryMock(repository *mocks.CommentRepository, comment domain.Comment) {
+	repository.
+		On("GetByID", context.Background(), comment.ID).
+		Return(comment, nil)
+}
+
+func (s *CommentVoteSuite) TestOwn(t provider.T) {
+	t.Parallel()
+	t.Title("Comment vote test own comment")
+	comment := domain.Comment{ID: uuid.New(), UserID: uuid.New(), TrackID: uuid.New(), Stars: 4}
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.OwnRepositoryMock(repository, comment)
+
+	_, err := commentService.Vote(context.Background(), comment.UserID, comment.ID, true)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentVoteOwn)
+}
+
+func (s *CommentVoteSuite) NotVotedRepositoryMock(repository *mocks.CommentRepository, commentID uuid.UUID,
+	userID uuid.UUID) {
+	repository.
+		On("DeleteVote", context.Background(), commentID, userID).
+		Return(ports.ErrCommentVoteNotFound)
+}
+
+func (s *CommentVoteSuite) TestDeleteNotVoted(t provider.T) {
+	t.Parallel()
+	t.Title("Comment delete vote test not voted")
+	commentID := uuid.New()
+	userID := uuid.New()
+	repository := mocks.NewCommentRepository(t)
+	commentService := service.NewCommentService(repository, nil, service.CommentServiceConfig{}, s.logger)
+	s.NotVotedRepositoryMock(repository, commentID, userID)
+
+	_, err := commentService.DeleteVote(context.Background(), userID, commentID)
+
+	t.Assert().ErrorIs(err, ports.ErrCommentVoteNotFound)
+}
+
+func TestCommentVoteSuite(t *testing.T) {
+	suite.RunSuite(t, new(CommentVoteSuite))
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/hanoys/sigma-music/internal/service/test/builder"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/zap"
+)
+
+type CreditSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *CreditSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+type CreditInviteSuite struct {
+	CreditSuite
+}
+
+func (s *CreditInviteSuite) CorrectRepositoryMock(repository *mocks.CreditRepository, albumID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("InviteToAlbum", context.Background(), albumID, musicianID).
+		Return(nil)
+}
+
+func (s *CreditInviteSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Credit invite to album test correct")
+	albumID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewCreditRepository(t)
+	creditService := service.NewCreditService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, albumID, musicianID)
+
+	err := creditService.InviteToAlbum(context.Background(), albumID, uuid.New(), musicianID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *CreditInviteSuite) TestSelfInvite(t provider.T) {
+	t.Parallel()
+	t.Title("Credit invite to album test self invite")
+	musicianID := uuid.New()
+	repository := mocks.NewCreditRepository(t)
+	creditService := service.NewCreditService(repository, s.logger)
+
+	err := creditService.InviteToAlbum(context.Background(), uuid.New(), musicianID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrSelfInvite)
+}
+
+func (s *CreditInviteSuite) AlreadyContributorRepositoryMock(repository *mocks.CreditRepository, albumID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("InviteToAlbum", context.Background(), albumID, musicianID).
+		Return(ports.ErrAlreadyContributor)
+}
+
+func (s *CreditInviteSuite) TestAlreadyContributor(t provider.T) {
+	t.Parallel()
+	t.Title("Credit invite to album test already contributor")
+	albumID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewCreditRepository(t)
+	creditService := service.NewCreditService(repository, s.logger)
+	s.AlreadyContributorRepositoryMock(repository, albumID, musicianID)
+
+	err := creditService.InviteToAlbum(context.Background(), albumID, uuid.New(), musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrAlreadyContributor)
+}
+
+func TestCreditInviteSuite(t *testing.T) {
+	suite.RunSuite(t, new(CreditInviteSuite))
+}
+
+type CreditGetAlbumContributorsSuite struct {
+	CreditSuite
+}
+
+func (s *CreditGetAlbumContributorsSuite) CorrectRepositoryMock(repository *mocks.CreditRepository, albumID uuid.UUID, contributors []domain.AlbumContributor) {
+	repository.
+		On("GetAlbumContributors", context.Background(), albumID).
+		Return(contributors, nil)
+}
+
+func (s *CreditGetAlbumContributorsSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Credit get album contributors test skips pending invites")
+	albumID := uuid.New()
+	owner := domain.AlbumContributor{
+		AlbumID:  albumID,
+		Musician: builder.NewMusicianBuilder().Default().Build(),
+		Owner:    true,
+		Accepted: true,
+	}
+	pending := domain.AlbumContributor{
+		AlbumID:  albumID,
+		Musician: builder.NewMusicianBuilder().Default().SetID(uuid.New()).Build(),
+	}
+	repository := mocks.NewCreditRepository(t)
+	creditService := service.NewCreditService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, albumID, []domain.AlbumContributor{owner, pending})
+
+	contributors, err := creditService.GetAlbumContributors(context.Background(), albumID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.AlbumContributor{owner}, contributors)
+}
+
+func TestCreditGetAlbumContributorsSuite(t *testing.T) {
+	suite.RunSuite(t, new(CreditGetAlbumContributorsSuite))
+}
+
+type CreditAddTrackCreditSuite struct {
+	CreditSuite
+}
+
+func (s *CreditAddTrackCreditSuite) CorrectRepositoryMock(repository *mocks.CreditRepository, trackID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("AddTrackCredit", context.Background(), trackID, musicianID, domain.CreditRoleFeatured).
+		Return(nil)
+}
+
+func (s *CreditAddTrackCreditSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Credit add track credit test correct")
+	trackID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewCreditRepository(t)
+	creditService := service.NewCreditService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, trackID, musicianID)
+
+	err := creditService.AddTrackCredit(context.Background(), trackID, musicianID, domain.CreditRoleFeatured)
+
+	t.Assert().Nil(err)
+}
+
+func (s *CreditAddTrackCreditSuite) TestUnknownRole(t provider.T) {
+	t.Parallel()
+	t.Title("Credit add track credit test unknown role")
+	repository := mocks.NewCreditRepository(t)
+	creditService := service.NewCreditService(repository, s.logger)
+
+	err := creditService.AddTrackCredit(context.Background(), uuid.New(), uuid.New(), domain.CreditRole("drummer"))
+
+	t.Assert().ErrorIs(err, ports.ErrUnknownCreditRole)
+}
+
+func TestCreditAddTrackCreditSuite(t *testing.T) {
+	suite.RunSuite(t, new(CreditAddTrackCreditSuite))
+}
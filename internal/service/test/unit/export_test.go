@@ -0,0 +1,315 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	mocks2 "github.com/hanoys/sigma-music/internal/adapters/miniostorage/mocks"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/hanoys/sigma-music/internal/service/test/builder"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type DataExportSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *DataExportSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+type DataExportMocks struct {
+	repository *mocks.DataExportRepository
+	storage    *mocks2.DataExportStorage
+	users      *mocks.UserRepository
+	tracks     *mocks.TrackRepository
+	comments   *mocks.CommentRepository
+	stats      *mocks.StatRepository
+	follows    *mocks.FollowRepository
+}
+
+func (s *DataExportSuite) NewService(t provider.T) (*service.DataExportService, DataExportMocks) {
+	m := DataExportMocks{
+		repository: mocks.NewDataExportRepository(t),
+		storage:    mocks2.NewDataExportStorage(t),
+		users:      mocks.NewUserRepository(t),
+		tracks:     mocks.NewTrackRepository(t),
+		comments:   mocks.NewCommentRepository(t),
+		stats:      mocks.NewStatRepository(t),
+		follows:    mocks.NewFollowRepository(t),
+	}
+
+	exportService := service.NewDataExportService(m.repository, m.storage,
+		service.NewUserService(m.users, nil, nil, nil, nil, nil, service.UserServiceConfig{}, s.logger),
+		service.NewTrackService(m.tracks, nil, nil, s.logger),
+		service.NewCommentService(m.comments, nil, service.CommentServiceConfig{}, s.logger),
+		service.NewStatService(m.stats, nil, nil, s.logger),
+		service.NewFollowService(m.follows, s.logger),
+		service.DataExportServiceConfig{}, s.logger)
+
+	return exportService, m
+}
+
+type DataExportRequestSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportRequestSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Data export request test correct")
+	exportService, m := s.NewService(t)
+	userID := uuid.New()
+	m.repository.
+		On("Create", context.Background(), mock.AnythingOfType("domain.DataExport")).
+		Return(func(_ context.Context, export domain.DataExport) (domain.DataExport, error) {
+			return export, nil
+		})
+
+	export, err := exportService.Request(context.Background(), userID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(userID, export.UserID)
+	t.Assert().Equal(domain.DataExportPending, export.Status)
+}
+
+func (s *DataExportRequestSuite) TestInProgress(t provider.T) {
+	t.Parallel()
+	t.Title("Data export request test export already in progress")
+	exportService, m := s.NewService(t)
+	m.repository.
+		On("Create", context.Background(), mock.AnythingOfType("domain.DataExport")).
+		Return(domain.DataExport{}, ports.ErrDataExportInProgress)
+
+	_, err := exportService.Request(context.Background(), uuid.New())
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportInProgress)
+}
+
+func TestDataExportRequestSuite(t *testing.T) {
+	suite.RunSuite(t, new(DataExportRequestSuite))
+}
+
+type DataExportDownloadSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportDownloadSuite) ExportRepositoryMock(repository *mocks.DataExportRepository,
+	export domain.DataExport) {
+	repository.
+		On("GetByID", context.Background(), export.ID).
+		Return(export, nil)
+}
+
+func (s *DataExportDownloadSuite) TestReady(t provider.T) {
+	t.Parallel()
+	t.Title("Data export download test ready export gets a signed link")
+	exportService, m := s.NewService(t)
+	export := domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.DataExportReady,
+		ExpiresAt: null.TimeFrom(time.Now().Add(time.Hour)),
+	}
+	link := url.URL{Scheme: "http", Host: "localhost:9000", Path: "exports/" + export.ID.String() + ".zip"}
+	s.ExportRepositoryMock(m.repository, export)
+	m.storage.
+		On("SignedURL", context.Background(), export.ID.String(), mock.AnythingOfType("time.Duration")).
+		Return(link, nil)
+
+	result, err := exportService.DownloadURL(context.Background(), export.UserID, export.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(link, result)
+}
+
+func (s *DataExportDownloadSuite) TestNotReady(t provider.T) {
+	t.Parallel()
+	t.Title("Data export download test pending export")
+	exportService, m := s.NewService(t)
+	export := domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.DataExportPending}
+	s.ExportRepositoryMock(m.repository, export)
+
+	_, err := exportService.DownloadURL(context.Background(), export.UserID, export.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportNotReady)
+}
+
+func (s *DataExportDownloadSuite) TestExpired(t provider.T) {
+	t.Parallel()
+	t.Title("Data export download test expired export")
+	exportService, m := s.NewService(t)
+	export := domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.DataExportReady,
+		ExpiresAt: null.TimeFrom(time.Now().Add(-time.Hour)),
+	}
+	s.ExportRepositoryMock(m.repository, export)
+
+	_, err := exportService.DownloadURL(context.Background(), export.UserID, export.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportExpired)
+}
+
+func (s *DataExportDownloadSuite) TestOtherUser(t provider.T) {
+	t.Parallel()
+	t.Title("Data export download test export of another user")
+	exportService, m := s.NewService(t)
+	export := domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.DataExportReady}
+	s.ExportRepositoryMock(m.repository, export)
+
+	_, err := exportService.DownloadURL(context.Background(), uuid.New(), export.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrDataExportIDNotFound)
+}
+
+func TestDataExportDownloadSuite(t *testing.T) {
+	suite.RunSuite(t, new(DataExportDownloadSuite))
+}
+
+type DataExportProcessSuite struct {
+	DataExportSuite
+}
+
+func (s *DataExportProcessSuite) UserDataRepositoryMock(m DataExportMocks, user domain.User) {
+	m.users.
+		On("GetByID", context.Background(), user.ID).
+		Return(user, nil)
+	m.tracks.
+		On("GetUserFavorites", context.Background(), user.ID, domain.TrackSortDefault).
+		Return([]domain.Track{builder.NewTrackBuilder().Default().Build()}, nil)
+	m.comments.
+		On("GetByUserID", context.Background(), user.ID).
+		Return([]domain.Comment{{ID: uuid.New(), UserID: user.ID, TrackID: uuid.New(), Stars: 5, Text: "great",
+			CreatedAt: time.Now()}}, nil)
+	m.stats.
+		On("GetHistory", context.Background(), user.ID).
+		Return([]domain.ListenRecord{{TrackID: uuid.New(), ListenedAt: time.Now(), ListenedSeconds: 42}}, nil)
+	m.follows.
+		On("GetFollowing", context.Background(), user.ID).
+		Return([]domain.Musician{builder.NewMusicianBuilder().Default().Build()}, nil)
+}
+
+func (s *DataExportProcessSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Data export process test archive is stored and export is ready")
+	exportService, m := s.NewService(t)
+	user := builder.NewUserBuilder().Default().Build()
+	export := domain.DataExport{ID: uuid.New(), UserID: user.ID, Status: domain.DataExportRunning}
+	var archive []byte
+	m.repository.
+		On("ClaimPending", context.Background(), mock.AnythingOfType("time.Time"),
+			mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).
+		Return([]domain.DataExport{export}, nil).
+		On("Finish", context.Background(), mock.AnythingOfType("domain.DataExport")).
+		Return(func(_ context.Context, export domain.DataExport) (domain.DataExport, error) {
+			return export, nil
+		})
+	m.storage.
+		On("PutArchive", context.Background(), mock.Anything, mock.AnythingOfType("int64"), export.ID.String()).
+		Run(func(args mock.Arguments) {
+			archive, _ = io.ReadAll(args.Get(1).(io.Reader))
+		}).
+		Return(nil)
+	s.UserDataRepositoryMock(m, user)
+
+	processed, err := exportService.ProcessPending(context.Background())
+
+	t.Assert().Nil(err)
+	t.Require().Len(processed, 1)
+	t.Assert().Equal(domain.DataExportReady, processed[0].Status)
+	t.Assert().True(processed[0].ExpiresAt.Valid)
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	t.Require().Nil(err)
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, _ := file.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(content)
+	}
+	t.Assert().ElementsMatch([]string{"profile.json", "comments.json", "favorites.csv", "history.csv",
+		"following.csv"}, fileNames(files))
+	t.Assert().Contains(files["profile.json"], user.Email)
+	t.Assert().NotContains(files["profile.json"], "password")
+	t.Assert().Contains(files["history.csv"], ",42")
+}
+
+func (s *DataExportProcessSuite) TestFailed(t provider.T) {
+	t.Parallel()
+	t.Title("Data export process test storage failure marks export failed")
+	exportService, m := s.NewService(t)
+	user := builder.NewUserBuilder().Default().Build()
+	export := domain.DataExport{ID: uuid.New(), UserID: user.ID, Status: domain.DataExportRunning}
+	m.repository.
+		On("ClaimPending", context.Background(), mock.AnythingOfType("time.Time"),
+			mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).
+		Return([]domain.DataExport{export}, nil).
+		On("Finish", context.Background(), mock.AnythingOfType("domain.DataExport")).
+		Return(func(_ context.Context, export domain.DataExport) (domain.DataExport, error) {
+			return export, nil
+		})
+	m.storage.
+		On("PutArchive", context.Background(), mock.Anything, mock.AnythingOfType("int64"), export.ID.String()).
+		Return(io.ErrUnexpectedEOF)
+	s.UserDataRepositoryMock(m, user)
+
+	processed, err := exportService.ProcessPending(context.Background())
+
+	t.Assert().Nil(err)
+	t.Require().Len(processed, 1)
+	t.Assert().Equal(domain.DataExportFailed, processed[0].Status)
+	t.Assert().False(processed[0].ExpiresAt.Valid)
+}
+
+func (s *DataExportProcessSuite) TestLost(t provider.T) {
+	t.Parallel()
+	t.Title("Data export process test export taken over by another run is skipped")
+	exportService, m := s.NewService(t)
+	user := builder.NewUserBuilder().Default().Build()
+	export := domain.DataExport{ID: uuid.New(), UserID: user.ID, Status: domain.DataExportRunning}
+	m.repository.
+		On("ClaimPending", context.Background(), mock.AnythingOfType("time.Time"),
+			mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).
+		Return([]domain.DataExport{export}, nil).
+		On("Finish", context.Background(), mock.AnythingOfType("domain.DataExport")).
+		Return(domain.DataExport{}, ports.ErrDataExportLost)
+	m.storage.
+		On("PutArchive", context.Background(), mock.Anything, mock.AnythingOfType("int64"), export.ID.String()).
+		Return(nil)
+	s.UserDataRepositoryMock(m, user)
+
+	processed, err := exportService.ProcessPending(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(processed)
+}
+
+func TestDataExportProcessSuite(t *testing.T) {
+	suite.RunSuite(t, new(DataExportProcessSuite))
+}
+
+func fileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,209 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/hanoys/sigma-music/internal/service/test/builder"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/zap"
+)
+
+type FollowSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *FollowSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+type FollowFollowSuite struct {
+	FollowSuite
+}
+
+func (s *FollowFollowSuite) CorrectRepositoryMock(repository *mocks.FollowRepository, userID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("Follow", context.Background(), userID, musicianID).
+		Return(nil)
+}
+
+func (s *FollowFollowSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Follow musician test correct")
+	userID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, userID, musicianID)
+
+	err := followService.Follow(context.Background(), userID, musicianID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *FollowFollowSuite) AlreadyFollowingRepositoryMock(repository *mocks.FollowRepository, userID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("Follow", context.Background(), userID, musicianID).
+		Return(ports.ErrAlreadyFollowing)
+}
+
+func (s *FollowFollowSuite) TestAlreadyFollowing(t provider.T) {
+	t.Parallel()
+	t.Title("Follow musician test already following")
+	userID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.AlreadyFollowingRepositoryMock(repository, userID, musicianID)
+
+	err := followService.Follow(context.Background(), userID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrAlreadyFollowing)
+}
+
+func TestFollowFollowSuite(t *testing.T) {
+	suite.RunSuite(t, new(FollowFollowSuite))
+}
+
+type FollowUnfollowSuite struct {
+	FollowSuite
+}
+
+func (s *FollowUnfollowSuite) CorrectRepositoryMock(repository *mocks.FollowRepository, userID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("Unfollow", context.Background(), userID, musicianID).
+		Return(nil)
+}
+
+func (s *FollowUnfollowSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Unfollow musician test correct")
+	userID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, userID, musicianID)
+
+	err := followService.Unfollow(context.Background(), userID, musicianID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *FollowUnfollowSuite) NotFollowingRepositoryMock(repository *mocks.FollowRepository, userID uuid.UUID, musicianID uuid.UUID) {
+	repository.
+		On("Unfollow", context.Background(), userID, musicianID).
+		Return(ports.ErrNotFollowing)
+}
+
+func (s *FollowUnfollowSuite) TestNotFollowing(t provider.T) {
+	t.Parallel()
+	t.Title("Unfollow musician test not following")
+	userID := uuid.New()
+	musicianID := uuid.New()
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.NotFollowingRepositoryMock(repository, userID, musicianID)
+
+	err := followService.Unfollow(context.Background(), userID, musicianID)
+
+	t.Assert().ErrorIs(err, ports.ErrNotFollowing)
+}
+
+func TestFollowUnfollowSuite(t *testing.T) {
+	suite.RunSuite(t, new(FollowUnfollowSuite))
+}
+
+type FollowPublishToFeedSuite struct {
+	FollowSuite
+}
+
+func (s *FollowPublishToFeedSuite) CorrectRepositoryMock(repository *mocks.FollowRepository, album domain.Album) {
+	repository.
+		On("AddToFeed", context.Background(), album.ID, album.ReleaseDate.Time).
+		Return(nil)
+}
+
+func (s *FollowPublishToFeedSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Publish album to feed test correct")
+	album := builder.NewAlbumBuilder().
+		Default().
+		SetPublished(true).
+		SetReleaseDate(null.TimeFrom(time.Now())).
+		Build()
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, album)
+
+	err := followService.PublishToFeed(context.Background(), album)
+
+	t.Assert().Nil(err)
+}
+
+func TestFollowPublishToFeedSuite(t *testing.T) {
+	suite.RunSuite(t, new(FollowPublishToFeedSuite))
+}
+
+type FollowGetFeedSuite struct {
+	FollowSuite
+}
+
+func (s *FollowGetFeedSuite) CorrectRepositoryMock(repository *mocks.FollowRepository, userID uuid.UUID, feed []domain.FeedItem) {
+	repository.
+		On("GetFeed", context.Background(), userID).
+		Return(feed, nil)
+}
+
+func (s *FollowGetFeedSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Get feed test correct")
+	userID := uuid.New()
+	feed := []domain.FeedItem{{
+		UserID:      userID,
+		MusicianID:  uuid.New(),
+		Album:       builder.NewAlbumBuilder().Default().SetPublished(true).Build(),
+		PublishedAt: time.Now(),
+	}}
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, userID, feed)
+
+	result, err := followService.GetFeed(context.Background(), userID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(feed, result)
+}
+
+func (s *FollowGetFeedSuite) InternalErrorRepositoryMock(repository *mocks.FollowRepository, userID uuid.UUID) {
+	repository.
+		On("GetFeed", context.Background(), userID).
+		Return(nil, ports.ErrInternalFollowRepo)
+}
+
+func (s *FollowGetFeedSuite) TestInternalError(t provider.T) {
+	t.Parallel()
+	t.Title("Get feed test internal error")
+	userID := uuid.New()
+	repository := mocks.NewFollowRepository(t)
+	followService := service.NewFollowService(repository, s.logger)
+	s.InternalErrorRepositoryMock(repository, userID)
+
+	_, err := followService.GetFeed(context.Background(), userID)
+
+	t.Assert().ErrorIs(err, ports.ErrInternalFollowRepo)
+}
+
+func TestFollowGetFeedSuite(t *testing.T) {
+	suite.RunSuite(t, new(FollowGetFeedSuite))
+}
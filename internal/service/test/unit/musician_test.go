@@ -3,8 +3,10 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	"github.com/hanoys/sigma-music/internal/adapters/hash"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
 	"github.com/hanoys/sigma-music/internal/domain"
@@ -49,7 +51,7 @@ func (s *MusicianRegisterSuite) TestCorrect(t provider.T) {
 	t.Title("Musician register test correct")
 	req := builder.NewMusicianServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository, req)
 
 	_, err := musicianService.Register(context.Background(), req)
@@ -67,7 +69,7 @@ func (s *MusicianRegisterSuite) TestNameExists(t provider.T) {
 	t.Title("Musician register test name exists")
 	req := builder.NewMusicianServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.NameExistsRepositoryMock(repository, req)
 
 	_, err := musicianService.Register(context.Background(), req)
@@ -87,7 +89,7 @@ func (s *MusicianRegisterSuite) TestEmailExists(t provider.T) {
 	t.Title("Musician register test email exists")
 	req := builder.NewMusicianServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.EmailExistsRepositoryMock(repository, req)
 
 	_, err := musicianService.Register(context.Background(), req)
@@ -115,7 +117,7 @@ func (s *MusicianGetAllSuite) CorrectRepositoryMock(repository *mocks.MusicianRe
 func (s *MusicianGetAllSuite) TestCorrect(t provider.T) {
 	t.Title("Musician get all test correct")
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository)
 
 	musicians, err := musicianService.GetAll(context.Background())
@@ -133,7 +135,7 @@ func (s *MusicianGetAllSuite) RepositoryErrorRepositoryMock(repository *mocks.Mu
 func (s *MusicianGetAllSuite) TestRepositoryError(t provider.T) {
 	t.Title("Musician get all test error")
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.RepositoryErrorRepositoryMock(repository)
 
 	_, err := musicianService.GetAll(context.Background())
@@ -161,7 +163,7 @@ func (s *MusicianGetByIDSuite) TestCorrect(t provider.T) {
 	t.Title("Musician get by id test correct")
 	musicianID := uuid.New()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository, musicianID)
 
 	result, err := musicianService.GetByID(context.Background(), musicianID)
@@ -180,7 +182,7 @@ func (s *MusicianGetByIDSuite) TestIDNotFound(t provider.T) {
 	t.Title("Musician get by id test not found")
 	musicianID := uuid.New()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.NotFoundRepositoryMock(repository, musicianID)
 
 	_, err := musicianService.GetByID(context.Background(), musicianID)
@@ -208,7 +210,7 @@ func (s *MusicianGetByNameSuite) TestCorrect(t provider.T) {
 	t.Title("Musician get by name test correct")
 	name := "Test Musician"
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository, name)
 
 	result, err := musicianService.GetByName(context.Background(), name)
@@ -227,7 +229,7 @@ func (s *MusicianGetByNameSuite) TestNameNotFound(t provider.T) {
 	t.Title("Musician get by name test not found")
 	name := "Test Musician"
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.NotFoundRepositoryMock(repository, name)
 
 	_, err := musicianService.GetByName(context.Background(), name)
@@ -255,7 +257,7 @@ func (s *MusicianGetByEmailSuite) TestCorrect(t provider.T) {
 	t.Title("Musician get by email test correct")
 	email := "test.musician@mail.com"
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository, email)
 
 	result, err := musicianService.GetByEmail(context.Background(), email)
@@ -274,7 +276,7 @@ func (s *MusicianGetByEmailSuite) TestNotFound(t provider.T) {
 	t.Title("Musician get by email test not found")
 	email := "test.musician@mail.com"
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.NotFoundRepositoryMock(repository, email)
 
 	_, err := musicianService.GetByEmail(context.Background(), email)
@@ -302,7 +304,7 @@ func (s *MusicianGetByAlbumIDSuite) TestCorrect(t provider.T) {
 	t.Title("Musician get by album id test correct")
 	albumID := uuid.New()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository, albumID)
 
 	_, err := musicianService.GetByAlbumID(context.Background(), albumID)
@@ -322,7 +324,7 @@ func (s *MusicianGetByAlbumIDSuite) TestNotFound(t provider.T) {
 	t.Title("Musician get by album id test not found")
 	albumID := uuid.New()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.NotFoundRepositoryMock(repository, albumID)
 
 	_, err := musicianService.GetByAlbumID(context.Background(), albumID)
@@ -350,7 +352,7 @@ func (s *MusicianGetByTrackIDSuite) TestCorrect(t provider.T) {
 	t.Title("Musician get by track id test correct")
 	trackID := uuid.New()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.CorrectRepositoryMock(repository, trackID)
 
 	_, err := musicianService.GetByTrackID(context.Background(), trackID)
@@ -368,7 +370,7 @@ func (s *MusicianGetByTrackIDSuite) TestNotFound(t provider.T) {
 	t.Title("Musician get by track id test not found")
 	trackID := uuid.New()
 	repository := mocks.NewMusicianRepository(t)
-	musicianService := service.NewMusicianService(repository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
 	s.NotFoundRepositoryMock(repository, trackID)
 
 	_, err := musicianService.GetByTrackID(context.Background(), trackID)
@@ -379,3 +381,169 @@ func (s *MusicianGetByTrackIDSuite) TestNotFound(t provider.T) {
 func TestMusicianGetByTrackIDSuite(t *testing.T) {
 	suite.RunSuite(t, new(MusicianGetByTrackIDSuite))
 }
+
+type MusicianUpdateProfileSuite struct {
+	MusicianSuite
+}
+
+func (s *MusicianUpdateProfileSuite) CorrectRepositoryMock(repository *mocks.MusicianRepository,
+	musician domain.Musician, updated domain.Musician, links []domain.MusicianLink) {
+	repository.
+		On("GetByID", context.Background(), musician.ID).
+		Return(musician, nil).
+		On("UpdateProfile", context.Background(), updated, links).
+		Return(updated, nil)
+}
+
+func (s *MusicianUpdateProfileSuite) TestCorrect(t provider.T) {
+	t.Title("Musician update profile test correct")
+	musician := builder.NewMusicianBuilder().Default().Build()
+	updated := musician
+	updated.Country = "Germany"
+	updated.Description = "new description"
+	links := []domain.MusicianLink{
+		{Kind: domain.MusicianLinkWebsite, URL: "https://musician.example.com"},
+		{Kind: domain.MusicianLinkInstagram, URL: "https://www.instagram.com/musician"},
+	}
+	repository := mocks.NewMusicianRepository(t)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
+	s.CorrectRepositoryMock(repository, musician, updated, links)
+
+	result, err := musicianService.UpdateProfile(context.Background(), musician.ID,
+		ports.MusicianServiceUpdateRequest{
+			Country:     null.StringFrom(updated.Country),
+			Description: null.StringFrom(updated.Description),
+			Links:       links,
+		})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(updated, result)
+}
+
+func (s *MusicianUpdateProfileSuite) RenameRepositoryMock(repository *mocks.MusicianRepository,
+	musician domain.Musician, renamed domain.Musician) {
+	unverified := renamed
+	unverified.VerifiedAt = null.Time{}
+	repository.
+		On("GetByID", context.Background(), musician.ID).
+		Return(musician, nil).
+		On("GetByName", context.Background(), renamed.Name).
+		Return(domain.Musician{}, ports.ErrMusicianNameNotFound).
+		On("UpdateProfile", context.Background(), renamed, []domain.MusicianLink(nil)).
+		Return(unverified, nil)
+}
+
+func (s *MusicianUpdateProfileSuite) TestRename(t provider.T) {
+	t.Title("Musician update profile test rename is left to the repository to unverify")
+	musician := builder.NewMusicianBuilder().Default().Build()
+	musician.VerifiedAt = null.TimeFrom(time.Now())
+	renamed := musician
+	renamed.Name = "new name"
+	repository := mocks.NewMusicianRepository(t)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
+	s.RenameRepositoryMock(repository, musician, renamed)
+
+	result, err := musicianService.UpdateProfile(context.Background(), musician.ID,
+		ports.MusicianServiceUpdateRequest{Name: null.StringFrom(renamed.Name)})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(renamed.Name, result.Name)
+	t.Assert().False(result.IsVerified())
+}
+
+func (s *MusicianUpdateProfileSuite) NameTakenRepositoryMock(repository *mocks.MusicianRepository,
+	musician domain.Musician, name string) {
+	repository.
+		On("GetByID", context.Background(), musician.ID).
+		Return(musician, nil).
+		On("GetByName", context.Background(), name).
+		Return(domain.Musician{ID: uuid.New(), Name: name}, nil)
+}
+
+func (s *MusicianUpdateProfileSuite) TestNameTaken(t provider.T) {
+	t.Title("Musician update profile test name taken by another musician")
+	musician := builder.NewMusicianBuilder().Default().Build()
+	repository := mocks.NewMusicianRepository(t)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
+	s.NameTakenRepositoryMock(repository, musician, "taken")
+
+	_, err := musicianService.UpdateProfile(context.Background(), musician.ID,
+		ports.MusicianServiceUpdateRequest{Name: null.StringFrom("taken")})
+
+	t.Assert().ErrorIs(err, ports.ErrMusicianWithSuchNameAlreadyExists)
+}
+
+func (s *MusicianUpdateProfileSuite) TestInvalidLink(t provider.T) {
+	t.Title("Musician update profile test invalid links")
+	repository := mocks.NewMusicianRepository(t)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
+	invalid := [][]domain.MusicianLink{
+		{{Kind: domain.MusicianLinkWebsite, URL: "musician.example.com"}},
+		{{Kind: domain.MusicianLinkWebsite, URL: "javascript:alert(1)"}},
+		{{Kind: domain.MusicianLinkWebsite, URL: "ftp://musician.example.com"}},
+		{{Kind: domain.MusicianLinkInstagram, URL: "https://instagram.com.example.com/musician"}},
+		{{Kind: "myspace", URL: "https://myspace.com/musician"}},
+		{
+			{Kind: domain.MusicianLinkTwitter, URL: "https://x.com/musician"},
+			{Kind: domain.MusicianLinkTwitter, URL: "https://twitter.com/musician"},
+		},
+	}
+
+	for _, links := range invalid {
+		_, err := musicianService.UpdateProfile(context.Background(), uuid.New(),
+			ports.MusicianServiceUpdateRequest{Links: links})
+
+		t.Assert().ErrorIs(err, ports.ErrMusicianInvalidLink, links)
+	}
+}
+
+func TestMusicianUpdateProfileSuite(t *testing.T) {
+	suite.RunSuite(t, new(MusicianUpdateProfileSuite))
+}
+
+type MusicianSetVerifiedSuite struct {
+	MusicianSuite
+}
+
+func (s *MusicianSetVerifiedSuite) CorrectRepositoryMock(repository *mocks.MusicianRepository,
+	musician domain.Musician) {
+	repository.
+		On("GetByID", context.Background(), musician.ID).
+		Return(musician, nil).
+		On("SetVerifiedAt", context.Background(), musician.ID, mock.AnythingOfType("null.Time")).
+		Return(func(_ context.Context, _ uuid.UUID, verifiedAt null.Time) (domain.Musician, error) {
+			musician.VerifiedAt = verifiedAt
+			return musician, nil
+		})
+}
+
+func (s *MusicianSetVerifiedSuite) TestVerify(t provider.T) {
+	t.Title("Musician set verified test grant badge")
+	musician := builder.NewMusicianBuilder().Default().Build()
+	repository := mocks.NewMusicianRepository(t)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
+	s.CorrectRepositoryMock(repository, musician)
+
+	result, err := musicianService.SetVerified(context.Background(), musician.ID, true)
+
+	t.Assert().Nil(err)
+	t.Assert().True(result.IsVerified())
+}
+
+func (s *MusicianSetVerifiedSuite) TestUnverify(t provider.T) {
+	t.Title("Musician set verified test revoke badge")
+	musician := builder.NewMusicianBuilder().Default().Build()
+	musician.VerifiedAt = null.TimeFrom(time.Now())
+	repository := mocks.NewMusicianRepository(t)
+	musicianService := service.NewMusicianService(repository, nil, s.hashProvider, s.logger)
+	s.CorrectRepositoryMock(repository, musician)
+
+	result, err := musicianService.SetVerified(context.Background(), musician.ID, false)
+
+	t.Assert().Nil(err)
+	t.Assert().False(result.IsVerified())
+}
+
+func TestMusicianSetVerifiedSuite(t *testing.T) {
+	suite.RunSuite(t, new(MusicianSetVerifiedSuite))
+}
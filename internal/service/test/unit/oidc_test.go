@@ -0,0 +1,276 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/auth/mocks"
+	mocks5 "github.com/hanoys/sigma-music/internal/adapters/oidc/mocks"
+	mocks3 "github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/hanoys/sigma-music/internal/service/test/builder"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+const testOIDCProvider = "corporate"
+
+type OIDCSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *OIDCSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+type oidcServiceMocks struct {
+	provider           *mocks5.OIDCProvider
+	stateStorage       *mocks5.OIDCStateStorage
+	identityRepository *mocks3.ExternalIdentityRepository
+	accountRepository  *mocks3.AccountRepository
+	tokenProvider      *mocks.TokenProvider
+}
+
+func (s *OIDCSuite) NewService(t provider.T) (*service.OIDCService, oidcServiceMocks) {
+	m := oidcServiceMocks{
+		provider:           mocks5.NewOIDCProvider(t),
+		stateStorage:       mocks5.NewOIDCStateStorage(t),
+		identityRepository: mocks3.NewExternalIdentityRepository(t),
+		accountRepository:  mocks3.NewAccountRepository(t),
+		tokenProvider:      mocks.NewTokenProvider(t),
+	}
+
+	oidcService := service.NewOIDCService(map[string]ports.IOIDCProvider{testOIDCProvider: m.provider},
+		m.stateStorage, m.identityRepository, m.accountRepository, mocks3.NewUserRepository(t),
+		m.tokenProvider, s.logger)
+
+	return oidcService, m
+}
+
+type OIDCBeginSuite struct {
+	OIDCSuite
+}
+
+func (s *OIDCBeginSuite) CorrectRepositoryMock(m oidcServiceMocks, linkAccountID uuid.UUID) {
+	m.stateStorage.
+		On("Save", context.Background(), mock.AnythingOfType("string"), mock.MatchedBy(func(state domain.OIDCLoginState) bool {
+			return state.Provider == testOIDCProvider && state.AccountID == linkAccountID &&
+				state.Nonce != "" && len(state.CodeVerifier) >= 43
+		}), mock.AnythingOfType("time.Duration")).
+		Return(nil)
+	m.provider.
+		On("AuthCodeURL", context.Background(), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string")).
+		Return("https://idp.example.com/authorize?state=state", nil)
+}
+
+func (s *OIDCBeginSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC begin test correct")
+	linkAccountID := uuid.New()
+	oidcService, m := s.NewService(t)
+	s.CorrectRepositoryMock(m, linkAccountID)
+
+	authURL, err := oidcService.Begin(context.Background(), ports.BeginOIDCRequest{
+		Provider:      testOIDCProvider,
+		LinkAccountID: linkAccountID,
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal("https://idp.example.com/authorize?state=state", authURL)
+}
+
+func (s *OIDCBeginSuite) TestUnknownProvider(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC begin test unknown provider")
+	oidcService, _ := s.NewService(t)
+
+	_, err := oidcService.Begin(context.Background(), ports.BeginOIDCRequest{Provider: "unknown"})
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCProviderNotFound)
+}
+
+func TestOIDCBeginSuite(t *testing.T) {
+	suite.RunSuite(t, new(OIDCBeginSuite))
+}
+
+type OIDCCompleteSuite struct {
+	OIDCSuite
+}
+
+func (s *OIDCCompleteSuite) ExchangeRepositoryMock(m oidcServiceMocks, state string, loginState domain.OIDCLoginState,
+	code string, claims domain.OIDCClaims) {
+	m.stateStorage.
+		On("Take", context.Background(), state).
+		Return(loginState, nil)
+	m.provider.
+		On("Exchange", context.Background(), code, loginState.CodeVerifier, loginState.Nonce).
+		Return(claims, nil)
+}
+
+func (s *OIDCCompleteSuite) SessionRepositoryMock(m oidcServiceMocks, account domain.Account) {
+	m.tokenProvider.
+		On("NewSession", context.Background(), domain.Payload{
+			UserID: account.ID,
+			Role:   domain.MusicianRole,
+		}, domain.SessionMetadata{}).
+		Return(domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil)
+}
+
+func newOIDCLoginState(accountID uuid.UUID) domain.OIDCLoginState {
+	return domain.OIDCLoginState{
+		Provider:     testOIDCProvider,
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		AccountID:    accountID,
+	}
+}
+
+func (s *OIDCCompleteSuite) KnownIdentityRepositoryMock(m oidcServiceMocks, account domain.Account, claims domain.OIDCClaims) {
+	s.ExchangeRepositoryMock(m, "state", newOIDCLoginState(uuid.Nil), "code", claims)
+	m.identityRepository.
+		On("Get", context.Background(), testOIDCProvider, claims.Subject).
+		Return(domain.ExternalIdentity{Provider: testOIDCProvider, Subject: claims.Subject, AccountID: account.ID}, nil)
+	m.accountRepository.
+		On("GetByID", context.Background(), account.ID).
+		Return(account, nil)
+	s.SessionRepositoryMock(m, account)
+}
+
+func (s *OIDCCompleteSuite) TestKnownIdentity(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC complete test known identity logs in")
+	account := builder.NewAccountBuilder().Default().SetKind(domain.AccountKindMusician).Build()
+	claims := domain.OIDCClaims{Subject: "subject"}
+	oidcService, m := s.NewService(t)
+	s.KnownIdentityRepositoryMock(m, account, claims)
+
+	tokens, err := oidcService.Complete(context.Background(), ports.CompleteOIDCRequest{
+		Provider: testOIDCProvider,
+		State:    "state",
+		Code:     "code",
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal("access", tokens.AccessToken)
+}
+
+func (s *OIDCCompleteSuite) LinkByEmailRepositoryMock(m oidcServiceMocks, account domain.Account, claims domain.OIDCClaims) {
+	s.ExchangeRepositoryMock(m, "state", newOIDCLoginState(uuid.Nil), "code", claims)
+	m.identityRepository.
+		On("Get", context.Background(), testOIDCProvider, claims.Subject).
+		Return(domain.ExternalIdentity{}, ports.ErrOIDCIdentityNotFound).
+		On("Create", context.Background(), domain.ExternalIdentity{
+			Provider:  testOIDCProvider,
+			Subject:   claims.Subject,
+			AccountID: account.ID,
+			Email:     claims.Email,
+		}).
+		Return(nil)
+	m.accountRepository.
+		On("GetByEmail", context.Background(), claims.Email).
+		Return(account, nil)
+	s.SessionRepositoryMock(m, account)
+}
+
+func (s *OIDCCompleteSuite) TestLinkByVerifiedEmail(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC complete test unknown identity linked by verified email")
+	account := builder.NewAccountBuilder().Default().SetKind(domain.AccountKindMusician).
+		SetEmailVerified(true).Build()
+	claims := domain.OIDCClaims{Subject: "subject", Email: account.Email, EmailVerified: true}
+	oidcService, m := s.NewService(t)
+	s.LinkByEmailRepositoryMock(m, account, claims)
+
+	_, err := oidcService.Complete(context.Background(), ports.CompleteOIDCRequest{
+		Provider: testOIDCProvider,
+		State:    "state",
+		Code:     "code",
+	})
+
+	t.Assert().Nil(err)
+}
+
+func (s *OIDCCompleteSuite) UnverifiedEmailRepositoryMock(m oidcServiceMocks, account domain.Account, claims domain.OIDCClaims) {
+	s.ExchangeRepositoryMock(m, "state", newOIDCLoginState(uuid.Nil), "code", claims)
+	m.identityRepository.
+		On("Get", context.Background(), testOIDCProvider, claims.Subject).
+		Return(domain.ExternalIdentity{}, ports.ErrOIDCIdentityNotFound)
+	m.accountRepository.
+		On("GetByEmail", context.Background(), claims.Email).
+		Return(account, nil)
+}
+
+func (s *OIDCCompleteSuite) TestUnverifiedEmailNotLinked(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC complete test unverified local email is not linked")
+	account := builder.NewAccountBuilder().Default().SetKind(domain.AccountKindMusician).
+		SetEmailVerified(false).Build()
+	claims := domain.OIDCClaims{Subject: "subject", Email: account.Email, EmailVerified: true}
+	oidcService, m := s.NewService(t)
+	s.UnverifiedEmailRepositoryMock(m, account, claims)
+
+	_, err := oidcService.Complete(context.Background(), ports.CompleteOIDCRequest{
+		Provider: testOIDCProvider,
+		State:    "state",
+		Code:     "code",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCIdentityNotLinked)
+}
+
+func (s *OIDCCompleteSuite) AlreadyLinkedRepositoryMock(m oidcServiceMocks, claims domain.OIDCClaims) {
+	s.ExchangeRepositoryMock(m, "state", newOIDCLoginState(uuid.New()), "code", claims)
+	m.identityRepository.
+		On("Get", context.Background(), testOIDCProvider, claims.Subject).
+		Return(domain.ExternalIdentity{Provider: testOIDCProvider, Subject: claims.Subject, AccountID: uuid.New()}, nil)
+}
+
+func (s *OIDCCompleteSuite) TestLinkAlreadyLinked(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC complete test identity linked to another account")
+	claims := domain.OIDCClaims{Subject: "subject"}
+	oidcService, m := s.NewService(t)
+	s.AlreadyLinkedRepositoryMock(m, claims)
+
+	_, err := oidcService.Complete(context.Background(), ports.CompleteOIDCRequest{
+		Provider: testOIDCProvider,
+		State:    "state",
+		Code:     "code",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCIdentityAlreadyLinked)
+}
+
+func (s *OIDCCompleteSuite) InvalidStateRepositoryMock(m oidcServiceMocks) {
+	m.stateStorage.
+		On("Take", context.Background(), "state").
+		Return(domain.OIDCLoginState{}, ports.ErrOIDCInvalidState)
+}
+
+func (s *OIDCCompleteSuite) TestInvalidState(t provider.T) {
+	t.Parallel()
+	t.Title("OIDC complete test invalid state")
+	oidcService, m := s.NewService(t)
+	s.InvalidStateRepositoryMock(m)
+
+	_, err := oidcService.Complete(context.Background(), ports.CompleteOIDCRequest{
+		Provider: testOIDCProvider,
+		State:    "state",
+		Code:     "code",
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrOIDCInvalidState)
+}
+
+func TestOIDCCompleteSuite(t *testing.T) {
+	suite.RunSuite(t, new(OIDCCompleteSuite))
+}
@@ -0,0 +1,400 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/payment"
+	mocks2 "github.com/hanoys/sigma-music/internal/adapters/payment/mocks"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+const paymentWebhookSecret = "payment-webhook-secret"
+
+type PaymentSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *PaymentSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+func (s *PaymentSuite) newPaymentService(gateway ports.IPaymentGateway, orderRepo *mocks.OrderRepository,
+	subscriptionRepo *mocks.SubscriptionRepository) *service.PaymentService {
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, s.logger)
+	return service.NewPaymentService(gateway, orderRepo, subscriptionService, s.logger)
+}
+
+func pendingOrder(userID uuid.UUID, key string) domain.Order {
+	return domain.Order{
+		ID:             uuid.New(),
+		UserID:         userID,
+		PlanID:         monthlyPlan.ID,
+		Amount:         monthlyPlan.Price,
+		Status:         domain.OrderPending,
+		IdempotencyKey: key,
+		IntentID:       "pi_existing",
+		CheckoutURL:    "http://localhost/checkout/pi_existing",
+		CreatedAt:      time.Now(),
+	}
+}
+
+type PaymentCheckoutSuite struct {
+	PaymentSuite
+}
+
+func (s *PaymentCheckoutSuite) CorrectRepositoryMock(orderRepo *mocks.OrderRepository,
+	subscriptionRepo *mocks.SubscriptionRepository, userID uuid.UUID, key string) {
+	subscriptionRepo.
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(monthlyPlan, nil)
+	orderRepo.
+		On("GetByIdempotencyKey", context.Background(), userID, key).
+		Return(domain.Order{}, ports.ErrOrderNotFound).
+		On("Create", context.Background(), mock.AnythingOfType("domain.Order")).
+		Return(func(ctx context.Context, order domain.Order) (domain.Order, error) {
+			return order, nil
+		}).
+		On("SetIntent", context.Background(), mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("domain.PaymentIntent")).
+		Return(nil)
+}
+
+func (s *PaymentCheckoutSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Checkout test correct")
+	userID := uuid.New()
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := payment.NewFakeGateway(paymentWebhookSecret, "http://localhost/checkout")
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+	s.CorrectRepositoryMock(orderRepo, subscriptionRepo, userID, "key-1")
+
+	order, err := paymentService.Checkout(context.Background(), ports.CheckoutRequest{
+		UserID:         userID,
+		PlanID:         monthlyPlan.ID,
+		IdempotencyKey: "key-1",
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.OrderPending, order.Status)
+	t.Assert().Equal(monthlyPlan.Price, order.Amount)
+	t.Assert().NotEmpty(order.IntentID)
+	t.Assert().Equal("http://localhost/checkout/"+order.IntentID, order.CheckoutURL)
+}
+
+func (s *PaymentCheckoutSuite) RetryRepositoryMock(orderRepo *mocks.OrderRepository,
+	subscriptionRepo *mocks.SubscriptionRepository, existing domain.Order) {
+	subscriptionRepo.
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(monthlyPlan, nil)
+	orderRepo.
+		On("GetByIdempotencyKey", context.Background(), existing.UserID, existing.IdempotencyKey).
+		Return(existing, nil)
+}
+
+func (s *PaymentCheckoutSuite) TestRetry(t provider.T) {
+	t.Parallel()
+	t.Title("Checkout test retry with the same idempotency key")
+	existing := pendingOrder(uuid.New(), "key-1")
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := mocks2.NewPaymentGateway(t)
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+	s.RetryRepositoryMock(orderRepo, subscriptionRepo, existing)
+
+	order, err := paymentService.Checkout(context.Background(), ports.CheckoutRequest{
+		UserID:         existing.UserID,
+		PlanID:         monthlyPlan.ID,
+		IdempotencyKey: existing.IdempotencyKey,
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(existing, order)
+}
+
+func (s *PaymentCheckoutSuite) OtherPlanRepositoryMock(orderRepo *mocks.OrderRepository,
+	subscriptionRepo *mocks.SubscriptionRepository, existing domain.Order, plan domain.SubscriptionPlan) {
+	subscriptionRepo.
+		On("GetPlanByID", context.Background(), plan.ID).
+		Return(plan, nil)
+	orderRepo.
+		On("GetByIdempotencyKey", context.Background(), existing.UserID, existing.IdempotencyKey).
+		Return(existing, nil)
+}
+
+func (s *PaymentCheckoutSuite) TestKeyReusedForOtherPlan(t provider.T) {
+	t.Parallel()
+	t.Title("Checkout test idempotency key reused for another plan")
+	existing := pendingOrder(uuid.New(), "key-1")
+	yearlyPlan := domain.SubscriptionPlan{ID: uuid.New(), Name: "yearly", Price: 299000, DurationDays: 365}
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := mocks2.NewPaymentGateway(t)
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+	s.OtherPlanRepositoryMock(orderRepo, subscriptionRepo, existing, yearlyPlan)
+
+	_, err := paymentService.Checkout(context.Background(), ports.CheckoutRequest{
+		UserID:         existing.UserID,
+		PlanID:         yearlyPlan.ID,
+		IdempotencyKey: existing.IdempotencyKey,
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrPaymentIdempotencyConflict)
+}
+
+func (s *PaymentCheckoutSuite) TestNoIdempotencyKey(t provider.T) {
+	t.Parallel()
+	t.Title("Checkout test without idempotency key")
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := mocks2.NewPaymentGateway(t)
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+
+	_, err := paymentService.Checkout(context.Background(), ports.CheckoutRequest{
+		UserID: uuid.New(),
+		PlanID: monthlyPlan.ID,
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrIdempotencyKeyEmpty)
+}
+
+func TestPaymentCheckoutSuite(t *testing.T) {
+	suite.RunSuite(t, new(PaymentCheckoutSuite))
+}
+
+type PaymentWebhookSuite struct {
+	PaymentSuite
+}
+
+func (s *PaymentWebhookSuite) TestInvalidSignature(t provider.T) {
+	t.Parallel()
+	t.Title("Payment webhook test invalid signature")
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := payment.NewFakeGateway(paymentWebhookSecret, "http://localhost/checkout")
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","intent_id":"pi_1"}`)
+
+	err := paymentService.HandleWebhook(context.Background(), body,
+		payment.Sign([]byte("forged-secret"), body, time.Now()))
+
+	t.Assert().ErrorIs(err, ports.ErrPaymentInvalidSignature)
+}
+
+func (s *PaymentWebhookSuite) AlreadyAppliedRepositoryMock(orderRepo *mocks.OrderRepository, order domain.Order) {
+	orderRepo.
+		On("GetByIntentID", context.Background(), order.IntentID).
+		Return(order, nil).
+		On("UpdateStatus", context.Background(), order.ID, domain.OrderPending, domain.OrderPaid).
+		Return(ports.ErrOrderStatusChanged)
+}
+
+func (s *PaymentWebhookSuite) TestAlreadyApplied(t provider.T) {
+	t.Parallel()
+	t.Title("Payment webhook test redelivered event")
+	order := pendingOrder(uuid.New(), "key-1")
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := mocks2.NewPaymentGateway(t)
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+	gateway.
+		On("ParseWebhook", []byte("body"), "signature").
+		Return(domain.PaymentEvent{ID: "evt_1", Type: domain.PaymentEventSucceeded, IntentID: order.IntentID}, nil)
+	s.AlreadyAppliedRepositoryMock(orderRepo, order)
+
+	err := paymentService.HandleWebhook(context.Background(), []byte("body"), "signature")
+
+	t.Assert().Nil(err)
+}
+
+func (s *PaymentWebhookSuite) ActivationFailedRepositoryMock(orderRepo *mocks.OrderRepository,
+	subscriptionRepo *mocks.SubscriptionRepository, order domain.Order) {
+	orderRepo.
+		On("GetByIntentID", context.Background(), order.IntentID).
+		Return(order, nil).
+		On("UpdateStatus", context.Background(), order.ID, domain.OrderPending, domain.OrderPaid).
+		Return(nil).
+		On("UpdateStatus", context.Background(), order.ID, domain.OrderPaid, domain.OrderPending).
+		Return(nil)
+	subscriptionRepo.
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(domain.SubscriptionPlan{}, ports.ErrInternalSubscriptionRepo)
+}
+
+func (s *PaymentWebhookSuite) TestActivationFailed(t provider.T) {
+	t.Parallel()
+	t.Title("Payment webhook test activation failed, order released for redelivery")
+	order := pendingOrder(uuid.New(), "key-1")
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := mocks2.NewPaymentGateway(t)
+	paymentService := s.newPaymentService(gateway, orderRepo, subscriptionRepo)
+	gateway.
+		On("ParseWebhook", []byte("body"), "signature").
+		Return(domain.PaymentEvent{ID: "evt_1", Type: domain.PaymentEventSucceeded, IntentID: order.IntentID}, nil)
+	s.ActivationFailedRepositoryMock(orderRepo, subscriptionRepo, order)
+
+	err := paymentService.HandleWebhook(context.Background(), []byte("body"), "signature")
+
+	t.Assert().ErrorIs(err, ports.ErrInternalSubscriptionRepo)
+}
+
+func TestPaymentWebhookSuite(t *testing.T) {
+	suite.RunSuite(t, new(PaymentWebhookSuite))
+}
+
+// PaymentPurchaseFlowSuite runs a purchase from checkout to the activated
+// subscription and back through a refund, with the fake gateway settling the
+// payment and in-memory repositories.
+type PaymentPurchaseFlowSuite struct {
+	PaymentSuite
+}
+
+func (s *PaymentPurchaseFlowSuite) InMemoryRepositoryMock(orderRepo *mocks.OrderRepository,
+	subscriptionRepo *mocks.SubscriptionRepository) {
+	orders := make(map[uuid.UUID]domain.Order)
+	var subscriptions []domain.Subscription
+
+	findOrder := func(match func(domain.Order) bool) (domain.Order, error) {
+		for _, order := range orders {
+			if match(order) {
+				return order, nil
+			}
+		}
+		return domain.Order{}, ports.ErrOrderNotFound
+	}
+
+	orderRepo.
+		On("GetByIdempotencyKey", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, userID uuid.UUID, key string) (domain.Order, error) {
+			return findOrder(func(order domain.Order) bool {
+				return order.UserID == userID && order.IdempotencyKey == key
+			})
+		}).
+		On("GetByIntentID", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, intentID string) (domain.Order, error) {
+			return findOrder(func(order domain.Order) bool { return order.IntentID == intentID })
+		}).
+		On("GetByID", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+			return findOrder(func(order domain.Order) bool { return order.ID == orderID })
+		}).
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, order domain.Order) (domain.Order, error) {
+			orders[order.ID] = order
+			return order, nil
+		}).
+		On("SetIntent", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, orderID uuid.UUID, intent domain.PaymentIntent) error {
+			order := orders[orderID]
+			order.IntentID = intent.ID
+			order.CheckoutURL = intent.CheckoutURL
+			orders[orderID] = order
+			return nil
+		}).
+		On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, orderID uuid.UUID, from domain.OrderStatus, to domain.OrderStatus) error {
+			order := orders[orderID]
+			if order.Status != from {
+				return ports.ErrOrderStatusChanged
+			}
+			order.Status = to
+			orders[orderID] = order
+			return nil
+		})
+
+	subscriptionRepo.
+		On("GetPlanByID", mock.Anything, monthlyPlan.ID).
+		Return(monthlyPlan, nil).
+		On("GetCurrent", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, userID uuid.UUID) (domain.Subscription, error) {
+			for _, subscription := range subscriptions {
+				if subscription.UserID == userID && subscription.Status != domain.SubscriptionExpired {
+					return subscription, nil
+				}
+			}
+			return domain.Subscription{}, ports.ErrSubscriptionNotFound
+		}).
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+			subscriptions = append(subscriptions, subscription)
+			return subscription, nil
+		}).
+		On("Update", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+			for i := range subscriptions {
+				if subscriptions[i].ID == subscription.ID {
+					subscriptions[i] = subscription
+					return subscription, nil
+				}
+			}
+			return domain.Subscription{}, ports.ErrSubscriptionNotFound
+		})
+}
+
+func (s *PaymentPurchaseFlowSuite) TestPurchaseActivatesSubscription(t provider.T) {
+	t.Title("Purchase flow test from checkout to activated subscription and refund")
+	ctx := context.Background()
+	userID := uuid.New()
+	orderRepo := mocks.NewOrderRepository(t)
+	subscriptionRepo := mocks.NewSubscriptionRepository(t)
+	gateway := payment.NewFakeGateway(paymentWebhookSecret, "http://localhost/checkout")
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, s.logger)
+	paymentService := service.NewPaymentService(gateway, orderRepo, subscriptionService, s.logger)
+	s.InMemoryRepositoryMock(orderRepo, subscriptionRepo)
+	checkout := ports.CheckoutRequest{UserID: userID, PlanID: monthlyPlan.ID, IdempotencyKey: "purchase-1"}
+
+	order, err := paymentService.Checkout(ctx, checkout)
+	t.Require().Nil(err)
+	retried, err := paymentService.Checkout(ctx, checkout)
+	t.Require().Nil(err)
+	t.Assert().Equal(order.ID, retried.ID)
+	t.Assert().Equal(order.IntentID, retried.IntentID)
+
+	err = subscriptionService.CheckActive(ctx, userID)
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionRequired)
+
+	body, signature, err := gateway.Pay(order.IntentID)
+	t.Require().Nil(err)
+	err = paymentService.HandleWebhook(ctx, body, signature)
+	t.Require().Nil(err)
+
+	err = subscriptionService.CheckActive(ctx, userID)
+	t.Assert().Nil(err)
+	paid, err := paymentService.GetOrder(ctx, userID, order.ID)
+	t.Require().Nil(err)
+	t.Assert().Equal(domain.OrderPaid, paid.Status)
+	subscription, err := subscriptionService.GetCurrent(ctx, userID)
+	t.Require().Nil(err)
+
+	// A redelivered webhook must not add another period.
+	err = paymentService.HandleWebhook(ctx, body, signature)
+	t.Require().Nil(err)
+	redelivered, err := subscriptionService.GetCurrent(ctx, userID)
+	t.Require().Nil(err)
+	t.Assert().Equal(subscription.ExpirationDate, redelivered.ExpirationDate)
+
+	refunded, err := paymentService.Refund(ctx, order.ID)
+	t.Require().Nil(err)
+	t.Assert().Equal(domain.OrderRefunded, refunded.Status)
+
+	err = subscriptionService.CheckActive(ctx, userID)
+	t.Assert().True(errors.Is(err, ports.ErrSubscriptionRequired))
+}
+
+func TestPaymentPurchaseFlowSuite(t *testing.T) {
+	suite.RunSuite(t, new(PaymentPurchaseFlowSuite))
+}
@@ -0,0 +1,228 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/adapters/ratelimit/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+var testLockoutPolicy = domain.LockoutPolicy{
+	Threshold:     5,
+	BaseDuration:  30 * time.Second,
+	MaxDuration:   time.Hour,
+	FailureWindow: time.Hour,
+}
+
+type RateLimitSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *RateLimitSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+type RateLimitAllowSuite struct {
+	RateLimitSuite
+}
+
+func (s *RateLimitAllowSuite) CorrectRepositoryMock(storage *mocks.RateLimitStorage, key string, limit domain.RateLimit) {
+	storage.
+		On("Hit", context.Background(), key, mock.AnythingOfType("time.Time"), limit.Window, limit.Requests).
+		Return(true, time.Now(), nil)
+}
+
+func (s *RateLimitAllowSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit allow test correct")
+	key := "auth:ip:127.0.0.1"
+	limit := domain.RateLimit{Requests: 10, Window: time.Minute}
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.CorrectRepositoryMock(storage, key, limit)
+
+	err := rateLimitService.Allow(context.Background(), key, limit)
+
+	t.Assert().Nil(err)
+}
+
+func (s *RateLimitAllowSuite) LimitedRepositoryMock(storage *mocks.RateLimitStorage, key string,
+	limit domain.RateLimit, oldest time.Time) {
+	storage.
+		On("Hit", context.Background(), key, mock.AnythingOfType("time.Time"), limit.Window, limit.Requests).
+		Return(false, oldest, nil)
+}
+
+func (s *RateLimitAllowSuite) TestLimited(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit allow test limit exceeded")
+	key := "auth:ip:127.0.0.1"
+	limit := domain.RateLimit{Requests: 10, Window: time.Minute}
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.LimitedRepositoryMock(storage, key, limit, time.Now().Add(-20*time.Second))
+
+	err := rateLimitService.Allow(context.Background(), key, limit)
+
+	var rateLimitErr *ports.RateLimitError
+	t.Assert().ErrorIs(err, ports.ErrRateLimited)
+	t.Assert().True(errors.As(err, &rateLimitErr))
+	t.Assert().InDelta(40, rateLimitErr.RetryAfter.Seconds(), 1)
+}
+
+func (s *RateLimitAllowSuite) StorageErrorRepositoryMock(storage *mocks.RateLimitStorage, key string,
+	limit domain.RateLimit) {
+	storage.
+		On("Hit", context.Background(), key, mock.AnythingOfType("time.Time"), limit.Window, limit.Requests).
+		Return(false, time.Time{}, ports.ErrInternalRateLimitStorage)
+}
+
+func (s *RateLimitAllowSuite) TestStorageError(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit allow test storage error lets the request through")
+	key := "auth:ip:127.0.0.1"
+	limit := domain.RateLimit{Requests: 10, Window: time.Minute}
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.StorageErrorRepositoryMock(storage, key, limit)
+
+	err := rateLimitService.Allow(context.Background(), key, limit)
+
+	t.Assert().Nil(err)
+}
+
+func (s *RateLimitAllowSuite) TestDisabled(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit allow test disabled limit")
+	rateLimitService := service.NewRateLimitService(mocks.NewRateLimitStorage(t), s.logger)
+
+	err := rateLimitService.Allow(context.Background(), "auth:ip:127.0.0.1", domain.RateLimit{})
+
+	t.Assert().Nil(err)
+}
+
+func TestRateLimitAllowSuite(t *testing.T) {
+	suite.RunSuite(t, new(RateLimitAllowSuite))
+}
+
+type RateLimitLockoutSuite struct {
+	RateLimitSuite
+}
+
+func (s *RateLimitLockoutSuite) BelowThresholdRepositoryMock(storage *mocks.RateLimitStorage, key string) {
+	storage.
+		On("AddFailure", context.Background(), key, testLockoutPolicy.FailureWindow).
+		Return(testLockoutPolicy.Threshold-1, nil)
+}
+
+func (s *RateLimitLockoutSuite) TestBelowThreshold(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit register failure test below threshold")
+	key := "login:name"
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.BelowThresholdRepositoryMock(storage, key)
+
+	err := rateLimitService.RegisterFailure(context.Background(), key, testLockoutPolicy)
+
+	t.Assert().Nil(err)
+}
+
+func (s *RateLimitLockoutSuite) ProgressiveRepositoryMock(storage *mocks.RateLimitStorage, key string) {
+	storage.
+		On("AddFailure", context.Background(), key, testLockoutPolicy.FailureWindow).
+		Return(testLockoutPolicy.Threshold+2, nil).
+		On("Lock", context.Background(), key, 2*time.Minute).
+		Return(nil)
+}
+
+func (s *RateLimitLockoutSuite) TestProgressive(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit register failure test lockout doubles with every failure")
+	key := "login:name"
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.ProgressiveRepositoryMock(storage, key)
+
+	err := rateLimitService.RegisterFailure(context.Background(), key, testLockoutPolicy)
+
+	t.Assert().Nil(err)
+}
+
+func (s *RateLimitLockoutSuite) CappedRepositoryMock(storage *mocks.RateLimitStorage, key string) {
+	storage.
+		On("AddFailure", context.Background(), key, testLockoutPolicy.FailureWindow).
+		Return(testLockoutPolicy.Threshold+100, nil).
+		On("Lock", context.Background(), key, testLockoutPolicy.MaxDuration).
+		Return(nil)
+}
+
+func (s *RateLimitLockoutSuite) TestCapped(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit register failure test lockout capped")
+	key := "login:name"
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.CappedRepositoryMock(storage, key)
+
+	err := rateLimitService.RegisterFailure(context.Background(), key, testLockoutPolicy)
+
+	t.Assert().Nil(err)
+}
+
+func (s *RateLimitLockoutSuite) LockedRepositoryMock(storage *mocks.RateLimitStorage, key string) {
+	storage.
+		On("LockTTL", context.Background(), key).
+		Return(1500*time.Millisecond, nil)
+}
+
+func (s *RateLimitLockoutSuite) TestLocked(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit check lockout test locked")
+	key := "login:name"
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.LockedRepositoryMock(storage, key)
+
+	err := rateLimitService.CheckLockout(context.Background(), key)
+
+	var rateLimitErr *ports.RateLimitError
+	t.Assert().ErrorIs(err, ports.ErrRateLimited)
+	t.Assert().True(errors.As(err, &rateLimitErr))
+	t.Assert().Equal(2, rateLimitErr.RetryAfterSeconds())
+}
+
+func (s *RateLimitLockoutSuite) NotLockedRepositoryMock(storage *mocks.RateLimitStorage, key string) {
+	storage.
+		On("LockTTL", context.Background(), key).
+		Return(time.Duration(0), nil)
+}
+
+func (s *RateLimitLockoutSuite) TestNotLocked(t provider.T) {
+	t.Parallel()
+	t.Title("Rate limit check lockout test not locked")
+	key := "login:name"
+	storage := mocks.NewRateLimitStorage(t)
+	rateLimitService := service.NewRateLimitService(storage, s.logger)
+	s.NotLockedRepositoryMock(storage, key)
+
+	err := rateLimitService.CheckLockout(context.Background(), key)
+
+	t.Assert().Nil(err)
+}
+
+func TestRateLimitLockoutSuite(t *testing.T) {
+	suite.RunSuite(t, new(RateLimitLockoutSuite))
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+var royaltyConfig = service.RoyaltyServiceConfig{
+	MinPlaySeconds:     30,
+	ArtistSharePercent: 70,
+}
+
+type RoyaltySuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *RoyaltySuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+// previousRoyaltyPeriod is the last finished month, the one due for allocation.
+func previousRoyaltyPeriod() domain.RoyaltyPeriod {
+	current := domain.RoyaltyPeriodOf(time.Now())
+	return domain.RoyaltyPeriodOf(current.Start.AddDate(0, -1, 0))
+}
+
+type RoyaltyAllocateDueSuite struct {
+	RoyaltySuite
+}
+
+func (s *RoyaltyAllocateDueSuite) CorrectRepositoryMock(repository *mocks.RoyaltyRepository,
+	period domain.RoyaltyPeriod, plays []domain.TrackPlays, created *[]domain.RoyaltyEntry) {
+	last := domain.RoyaltyPeriodOf(period.Start.AddDate(0, -1, 0))
+	repository.
+		On("GetLastPeriod", context.Background()).
+		Return(last, nil).
+		On("GetRevenue", context.Background(), period.Start, period.End).
+		Return(int64(1000), nil).
+		On("GetTrackPlays", context.Background(), period.Start, period.End, 30).
+		Return(plays, nil).
+		On("CreatePeriod", context.Background(), mock.AnythingOfType("domain.RoyaltyPeriod"), mock.Anything).
+		Return(func(ctx context.Context, period domain.RoyaltyPeriod, entries []domain.RoyaltyEntry) error {
+			*created = entries
+			return nil
+		})
+}
+
+func (s *RoyaltyAllocateDueSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Royalty allocate test correct")
+	period := previousRoyaltyPeriod()
+	musicianID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	otherMusicianID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	plays := []domain.TrackPlays{
+		{TrackID: uuid.New(), MusicianIDs: []uuid.UUID{otherMusicianID, musicianID}, Plays: 2},
+		{TrackID: uuid.New(), MusicianIDs: []uuid.UUID{musicianID}, Plays: 1},
+	}
+	var entries []domain.RoyaltyEntry
+	repository := mocks.NewRoyaltyRepository(t)
+	royaltyService := service.NewRoyaltyService(repository, royaltyConfig, s.logger)
+	s.CorrectRepositoryMock(repository, period, plays, &entries)
+
+	allocated, err := royaltyService.AllocateDue(context.Background())
+
+	t.Assert().Nil(err)
+	t.Require().Len(allocated, 1)
+	t.Assert().Equal(period.Start, allocated[0].Start)
+	t.Assert().Equal(int64(1000), allocated[0].Revenue)
+	t.Assert().Equal(int64(700), allocated[0].Pool)
+	t.Assert().Equal(int64(3), allocated[0].Plays)
+	// 700 split 2:1 is 466.67 and 233.33, the leftover unit goes to the first
+	// track, whose 467 is then split 234 and 233 between its musicians.
+	t.Require().Len(entries, 2)
+	t.Assert().Equal(musicianID, entries[0].MusicianID)
+	t.Assert().Equal(int64(234+233), entries[0].Amount)
+	t.Assert().Equal(int64(3), entries[0].Plays)
+	t.Assert().Equal(otherMusicianID, entries[1].MusicianID)
+	t.Assert().Equal(int64(233), entries[1].Amount)
+	t.Assert().Equal(int64(2), entries[1].Plays)
+	t.Assert().Equal(period.Start, entries[0].PeriodStart)
+}
+
+func (s *RoyaltyAllocateDueSuite) FirstRunRepositoryMock(repository *mocks.RoyaltyRepository,
+	period domain.RoyaltyPeriod) {
+	repository.
+		On("GetLastPeriod", context.Background()).
+		Return(domain.RoyaltyPeriod{}, ports.ErrRoyaltyPeriodNotFound).
+		On("GetRevenue", context.Background(), period.Start, period.End).
+		Return(int64(1000), nil).
+		On("GetTrackPlays", context.Background(), period.Start, period.End, 30).
+		Return(nil, nil).
+		On("CreatePeriod", context.Background(), mock.AnythingOfType("domain.RoyaltyPeriod"),
+			[]domain.RoyaltyEntry(nil)).
+		Return(nil).
+		Once()
+}
+
+func (s *RoyaltyAllocateDueSuite) TestFirstRunWithoutPlays(t provider.T) {
+	t.Parallel()
+	t.Title("Royalty allocate test first run allocates previous month without plays")
+	period := previousRoyaltyPeriod()
+	repository := mocks.NewRoyaltyRepository(t)
+	royaltyService := service.NewRoyaltyService(repository, royaltyConfig, s.logger)
+	s.FirstRunRepositoryMock(repository, period)
+
+	allocated, err := royaltyService.AllocateDue(context.Background())
+
+	t.Assert().Nil(err)
+	t.Require().Len(allocated, 1)
+	t.Assert().Equal(period.Start, allocated[0].Start)
+	t.Assert().Equal(int64(0), allocated[0].Plays)
+}
+
+func (s *RoyaltyAllocateDueSuite) AllocatedRepositoryMock(repository *mocks.RoyaltyRepository,
+	period domain.RoyaltyPeriod) {
+	last := domain.RoyaltyPeriodOf(period.Start.AddDate(0, -1, 0))
+	repository.
+		On("GetLastPeriod", context.Background()).
+		Return(last, nil).
+		On("GetRevenue", context.Background(), period.Start, period.End).
+		Return(int64(0), nil).
+		On("GetTrackPlays", context.Background(), period.Start, period.End, 30).
+		Return(nil, nil).
+		On("CreatePeriod", context.Background(), mock.Anything, mock.Anything).
+		Return(ports.ErrRoyaltyPeriodAllocated)
+}
+
+func (s *RoyaltyAllocateDueSuite) TestAllocatedConcurrently(t provider.T) {
+	t.Parallel()
+	t.Title("Royalty allocate test period allocated by another instance")
+	repository := mocks.NewRoyaltyRepository(t)
+	royaltyService := service.NewRoyaltyService(repository, royaltyConfig, s.logger)
+	s.AllocatedRepositoryMock(repository, previousRoyaltyPeriod())
+
+	allocated, err := royaltyService.AllocateDue(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(allocated)
+}
+
+func (s *RoyaltyAllocateDueSuite) TestUpToDate(t provider.T) {
+	t.Parallel()
+	t.Title("Royalty allocate test nothing due")
+	repository := mocks.NewRoyaltyRepository(t)
+	royaltyService := service.NewRoyaltyService(repository, royaltyConfig, s.logger)
+	repository.
+		On("GetLastPeriod", context.Background()).
+		Return(previousRoyaltyPeriod(), nil)
+
+	allocated, err := royaltyService.AllocateDue(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(allocated)
+}
+
+func TestRoyaltyAllocateDueSuite(t *testing.T) {
+	suite.RunSuite(t, new(RoyaltyAllocateDueSuite))
+}
+
+type RoyaltyGetStatementsSuite struct {
+	RoyaltySuite
+}
+
+func (s *RoyaltyGetStatementsSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Royalty get statements test correct")
+	musicianID := uuid.New()
+	period := previousRoyaltyPeriod()
+	statement := domain.RoyaltyStatement{MusicianID: musicianID, Period: period, Plays: 3, Amount: 467}
+	repository := mocks.NewRoyaltyRepository(t)
+	royaltyService := service.NewRoyaltyService(repository, royaltyConfig, s.logger)
+	repository.
+		On("GetStatements", context.Background(), musicianID, period.Start, period.End).
+		Return([]domain.RoyaltyStatement{statement}, nil)
+
+	statements, err := royaltyService.GetStatements(context.Background(), musicianID, period.Start, period.End)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal([]domain.RoyaltyStatement{statement}, statements)
+}
+
+func (s *RoyaltyGetStatementsSuite) TestInvalidRange(t provider.T) {
+	t.Parallel()
+	t.Title("Royalty get statements test range ends before it starts")
+	period := previousRoyaltyPeriod()
+	repository := mocks.NewRoyaltyRepository(t)
+	royaltyService := service.NewRoyaltyService(repository, royaltyConfig, s.logger)
+
+	_, err := royaltyService.GetStatements(context.Background(), uuid.New(), period.End, period.Start)
+
+	t.Assert().ErrorIs(err, ports.ErrRoyaltyStatementRange)
+}
+
+func TestRoyaltyGetStatementsSuite(t *testing.T) {
+	suite.RunSuite(t, new(RoyaltyGetStatementsSuite))
+}
@@ -37,7 +37,7 @@ type StatAddSuite struct {
 func (s *StatAddSuite) CorrectRepositoryMock(statRepository *mocks.StatRepository,
 	musicianRepository *mocks.MusicianRepository, genreRepository *mocks.GenreRepository, userID uuid.UUID, trackID uuid.UUID) {
 	statRepository.
-		On("Add", context.Background(), mock.Anything, userID, trackID).
+		On("Add", context.Background(), mock.Anything, userID, trackID, 30).
 		Return(nil)
 }
 
@@ -50,11 +50,11 @@ func (s *StatAddSuite) TestCorrect(t provider.T) {
 	musicianRepository := mocks.NewMusicianRepository(t)
 	genreRepository := mocks.NewGenreRepository(t)
 	genreService := service.NewGenreService(genreRepository, s.logger)
-	musicianService := service.NewMusicianService(musicianRepository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(musicianRepository, nil, s.hashProvider, s.logger)
 	statService := service.NewStatService(statRepository, genreService, musicianService, s.logger)
 	s.CorrectRepositoryMock(statRepository, musicianRepository, genreRepository, userID, trackID)
 
-	err := statService.Add(context.Background(), userID, trackID)
+	err := statService.Add(context.Background(), userID, trackID, 30)
 
 	t.Assert().Nil(err)
 }
@@ -62,7 +62,7 @@ func (s *StatAddSuite) TestCorrect(t provider.T) {
 func (s *StatAddSuite) InternalErrorRepositoryMock(statRepository *mocks.StatRepository,
 	musicianRepository *mocks.MusicianRepository, genreRepository *mocks.GenreRepository, userID uuid.UUID, trackID uuid.UUID) {
 	statRepository.
-		On("Add", context.Background(), mock.Anything, userID, trackID).
+		On("Add", context.Background(), mock.Anything, userID, trackID, 30).
 		Return(ports.ErrInternalStatRepo)
 }
 
@@ -75,11 +75,11 @@ func (s *StatAddSuite) TestInternalError(t provider.T) {
 	musicianRepository := mocks.NewMusicianRepository(t)
 	genreRepository := mocks.NewGenreRepository(t)
 	genreService := service.NewGenreService(genreRepository, s.logger)
-	musicianService := service.NewMusicianService(musicianRepository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(musicianRepository, nil, s.hashProvider, s.logger)
 	statService := service.NewStatService(statRepository, genreService, musicianService, s.logger)
 	s.InternalErrorRepositoryMock(statRepository, musicianRepository, genreRepository, userID, trackID)
 
-	err := statService.Add(context.Background(), userID, trackID)
+	err := statService.Add(context.Background(), userID, trackID, 30)
 
 	t.Assert().ErrorIs(err, ports.ErrInternalStatRepo)
 }
@@ -146,7 +146,7 @@ func (s *StatFormReportSuite) TestCorrect(t provider.T) {
 	musicianRepository := mocks.NewMusicianRepository(t)
 	genreRepository := mocks.NewGenreRepository(t)
 	genreService := service.NewGenreService(genreRepository, s.logger)
-	musicianService := service.NewMusicianService(musicianRepository, s.hashProvider, s.logger)
+	musicianService := service.NewMusicianService(musicianRepository, nil, s.hashProvider, s.logger)
 	statService := service.NewStatService(statRepository, genreService, musicianService, s.logger)
 	s.CorrectRepositoryMock(statRepository, musicianRepository, genreRepository, userID, musiciansStat, genresStat, musicians, genres)
 
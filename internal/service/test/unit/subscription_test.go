@@ -0,0 +1,298 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+	"github.com/hanoys/sigma-music/internal/service"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+var monthlyPlan = domain.SubscriptionPlan{
+	ID:           uuid.New(),
+	Name:         "monthly",
+	Price:        29900,
+	DurationDays: 30,
+}
+
+func runningSubscription(userID uuid.UUID, expiresIn time.Duration) domain.Subscription {
+	now := time.Now()
+	return domain.Subscription{
+		ID:             uuid.New(),
+		UserID:         userID,
+		PlanID:         monthlyPlan.ID,
+		StartDate:      now.Add(-time.Hour),
+		ExpirationDate: now.Add(expiresIn),
+		Status:         domain.SubscriptionActive,
+	}
+}
+
+type SubscriptionSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func (s *SubscriptionSuite) BeforeEach(t provider.T) {
+	loggerBuilder := zap.NewDevelopmentConfig()
+	loggerBuilder.Level = zap.NewAtomicLevelAt(zap.FatalLevel)
+	s.logger, _ = loggerBuilder.Build()
+}
+
+type SubscriptionStartSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionStartSuite) CorrectRepositoryMock(repository *mocks.SubscriptionRepository, userID uuid.UUID) {
+	repository.
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(monthlyPlan, nil).
+		On("GetCurrent", context.Background(), userID).
+		Return(domain.Subscription{}, ports.ErrSubscriptionNotFound).
+		On("Create", context.Background(), mock.AnythingOfType("domain.Subscription")).
+		Return(func(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+			return subscription, nil
+		})
+}
+
+func (s *SubscriptionStartSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Start subscription test correct")
+	userID := uuid.New()
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, userID)
+
+	subscription, err := subscriptionService.Start(context.Background(), userID, monthlyPlan.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(userID, subscription.UserID)
+	t.Assert().Equal(domain.SubscriptionActive, subscription.Status)
+	t.Assert().Equal(subscription.StartDate.AddDate(0, 0, 30), subscription.ExpirationDate)
+}
+
+func (s *SubscriptionStartSuite) AlreadyActiveRepositoryMock(repository *mocks.SubscriptionRepository, userID uuid.UUID) {
+	repository.
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(monthlyPlan, nil).
+		On("GetCurrent", context.Background(), userID).
+		Return(runningSubscription(userID, time.Hour), nil)
+}
+
+func (s *SubscriptionStartSuite) TestAlreadyActive(t provider.T) {
+	t.Parallel()
+	t.Title("Start subscription test already active")
+	userID := uuid.New()
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.AlreadyActiveRepositoryMock(repository, userID)
+
+	_, err := subscriptionService.Start(context.Background(), userID, monthlyPlan.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionAlreadyActive)
+}
+
+func (s *SubscriptionStartSuite) RunOutRepositoryMock(repository *mocks.SubscriptionRepository, previous domain.Subscription) {
+	repository.
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(monthlyPlan, nil).
+		On("GetCurrent", context.Background(), previous.UserID).
+		Return(previous, nil).
+		On("Update", context.Background(), mock.MatchedBy(func(subscription domain.Subscription) bool {
+			return subscription.ID == previous.ID && subscription.Status == domain.SubscriptionExpired
+		})).
+		Return(previous, nil).
+		On("Create", context.Background(), mock.AnythingOfType("domain.Subscription")).
+		Return(func(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+			return subscription, nil
+		})
+}
+
+func (s *SubscriptionStartSuite) TestPreviousRunOut(t provider.T) {
+	t.Parallel()
+	t.Title("Start subscription test previous one ran out but is not marked expired")
+	userID := uuid.New()
+	previous := runningSubscription(userID, -time.Minute)
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.RunOutRepositoryMock(repository, previous)
+
+	subscription, err := subscriptionService.Start(context.Background(), userID, monthlyPlan.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().NotEqual(previous.ID, subscription.ID)
+}
+
+func (s *SubscriptionStartSuite) PlanNotFoundRepositoryMock(repository *mocks.SubscriptionRepository, planID uuid.UUID) {
+	repository.
+		On("GetPlanByID", context.Background(), planID).
+		Return(domain.SubscriptionPlan{}, ports.ErrSubscriptionPlanNotFound)
+}
+
+func (s *SubscriptionStartSuite) TestPlanNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Start subscription test plan not found")
+	planID := uuid.New()
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.PlanNotFoundRepositoryMock(repository, planID)
+
+	_, err := subscriptionService.Start(context.Background(), uuid.New(), planID)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionPlanNotFound)
+}
+
+func TestSubscriptionStartSuite(t *testing.T) {
+	suite.RunSuite(t, new(SubscriptionStartSuite))
+}
+
+type SubscriptionRenewSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionRenewSuite) CorrectRepositoryMock(repository *mocks.SubscriptionRepository, current domain.Subscription) {
+	repository.
+		On("GetCurrent", context.Background(), current.UserID).
+		Return(current, nil).
+		On("GetPlanByID", context.Background(), monthlyPlan.ID).
+		Return(monthlyPlan, nil).
+		On("Update", context.Background(), mock.AnythingOfType("domain.Subscription")).
+		Return(func(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+			return subscription, nil
+		})
+}
+
+func (s *SubscriptionRenewSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Renew subscription test correct")
+	current := runningSubscription(uuid.New(), 24*time.Hour)
+	current.Status = domain.SubscriptionCanceled
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, current)
+
+	subscription, err := subscriptionService.Renew(context.Background(), current.UserID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(current.ExpirationDate.AddDate(0, 0, 30), subscription.ExpirationDate)
+	t.Assert().Equal(domain.SubscriptionActive, subscription.Status)
+}
+
+func (s *SubscriptionRenewSuite) NoPlanRepositoryMock(repository *mocks.SubscriptionRepository, current domain.Subscription) {
+	repository.
+		On("GetCurrent", context.Background(), current.UserID).
+		Return(current, nil)
+}
+
+func (s *SubscriptionRenewSuite) TestNotRenewable(t provider.T) {
+	t.Parallel()
+	t.Title("Renew subscription test subscription without plan")
+	current := runningSubscription(uuid.New(), 24*time.Hour)
+	current.PlanID = uuid.Nil
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.NoPlanRepositoryMock(repository, current)
+
+	_, err := subscriptionService.Renew(context.Background(), current.UserID)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionNotRenewable)
+}
+
+func TestSubscriptionRenewSuite(t *testing.T) {
+	suite.RunSuite(t, new(SubscriptionRenewSuite))
+}
+
+type SubscriptionCancelSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionCancelSuite) CorrectRepositoryMock(repository *mocks.SubscriptionRepository, current domain.Subscription) {
+	repository.
+		On("GetCurrent", context.Background(), current.UserID).
+		Return(current, nil).
+		On("Update", context.Background(), mock.AnythingOfType("domain.Subscription")).
+		Return(func(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+			return subscription, nil
+		})
+}
+
+func (s *SubscriptionCancelSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Cancel subscription test correct")
+	current := runningSubscription(uuid.New(), 24*time.Hour)
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.CorrectRepositoryMock(repository, current)
+
+	subscription, err := subscriptionService.Cancel(context.Background(), current.UserID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.SubscriptionCanceled, subscription.Status)
+	t.Assert().Equal(current.ExpirationDate, subscription.ExpirationDate)
+	t.Assert().True(subscription.IsActive(time.Now()))
+}
+
+func TestSubscriptionCancelSuite(t *testing.T) {
+	suite.RunSuite(t, new(SubscriptionCancelSuite))
+}
+
+type SubscriptionCheckActiveSuite struct {
+	SubscriptionSuite
+}
+
+func (s *SubscriptionCheckActiveSuite) CurrentRepositoryMock(repository *mocks.SubscriptionRepository,
+	userID uuid.UUID, current domain.Subscription, err error) {
+	repository.
+		On("GetCurrent", context.Background(), userID).
+		Return(current, err)
+}
+
+func (s *SubscriptionCheckActiveSuite) TestActive(t provider.T) {
+	t.Parallel()
+	t.Title("Check active subscription test active")
+	current := runningSubscription(uuid.New(), time.Hour)
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.CurrentRepositoryMock(repository, current.UserID, current, nil)
+
+	err := subscriptionService.CheckActive(context.Background(), current.UserID)
+
+	t.Assert().Nil(err)
+}
+
+func (s *SubscriptionCheckActiveSuite) TestRunOut(t provider.T) {
+	t.Parallel()
+	t.Title("Check active subscription test ran out before being marked expired")
+	current := runningSubscription(uuid.New(), -time.Minute)
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.CurrentRepositoryMock(repository, current.UserID, current, nil)
+
+	err := subscriptionService.CheckActive(context.Background(), current.UserID)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionRequired)
+}
+
+func (s *SubscriptionCheckActiveSuite) TestNone(t provider.T) {
+	t.Parallel()
+	t.Title("Check active subscription test no subscription")
+	userID := uuid.New()
+	repository := mocks.NewSubscriptionRepository(t)
+	subscriptionService := service.NewSubscriptionService(repository, s.logger)
+	s.CurrentRepositoryMock(repository, userID, domain.Subscription{}, ports.ErrSubscriptionNotFound)
+
+	err := subscriptionService.CheckActive(context.Background(), userID)
+
+	t.Assert().ErrorIs(err, ports.ErrSubscriptionRequired)
+}
+
+func TestSubscriptionCheckActiveSuite(t *testing.T) {
+	suite.RunSuite(t, new(SubscriptionCheckActiveSuite))
+}
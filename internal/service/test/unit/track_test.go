@@ -3,9 +3,11 @@ package test
 import (
 	"context"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
 	mocks2 "github.com/hanoys/sigma-music/internal/adapters/miniostorage/mocks"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
 	"github.com/hanoys/sigma-music/internal/domain"
@@ -105,7 +107,7 @@ type TrackGetAllSuite struct {
 
 func (s *TrackGetAllSuite) CorrectRepositoryMock(repository *mocks.TrackRepository) {
 	repository.
-		On("GetAll", context.Background()).
+		On("GetAll", context.Background(), domain.TrackSortDefault).
 		Return(make([]domain.Track, 0), nil)
 }
 
@@ -119,15 +121,41 @@ func (s *TrackGetAllSuite) TestCorrect(t provider.T) {
 	genreService := service.NewGenreService(genreRepository, s.logger)
 	trackService := service.NewTrackService(trackRepository, trackStorage, genreService, s.logger)
 
-	tracks, err := trackService.GetAll(context.Background())
+	tracks, err := trackService.GetAll(context.Background(), domain.TrackSortDefault)
 
 	t.Assert().NotNil(tracks)
 	t.Assert().Nil(err)
 }
 
+func (s *TrackGetAllSuite) SortByRatingRepositoryMock(repository *mocks.TrackRepository, tracks []domain.Track) {
+	repository.
+		On("GetAll", context.Background(), domain.TrackSortRating).
+		Return(tracks, nil)
+}
+
+func (s *TrackGetAllSuite) TestSortByRating(t provider.T) {
+	t.Parallel()
+	t.Title("Track get all test sort by rating")
+	genreRepository := mocks.NewGenreRepository(t)
+	trackRepository := mocks.NewTrackRepository(t)
+	trackStorage := mocks2.NewTrackObjectStorage(t)
+	sorted := []domain.Track{
+		builder.NewTrackBuilder().Default().SetID(uuid.New()).Build(),
+		builder.NewTrackBuilder().Default().SetID(uuid.New()).Build(),
+	}
+	s.SortByRatingRepositoryMock(trackRepository, sorted)
+	genreService := service.NewGenreService(genreRepository, s.logger)
+	trackService := service.NewTrackService(trackRepository, trackStorage, genreService, s.logger)
+
+	tracks, err := trackService.GetAll(context.Background(), domain.TrackSortRating)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(sorted, tracks)
+}
+
 func (s *TrackGetAllSuite) InternalErrorRepositoryMock(repository *mocks.TrackRepository) {
 	repository.
-		On("GetAll", context.Background()).
+		On("GetAll", context.Background(), domain.TrackSortDefault).
 		Return(nil, ports.ErrInternalTrackRepo)
 }
 
@@ -141,7 +169,7 @@ func (s *TrackGetAllSuite) TestInternalError(t provider.T) {
 	genreService := service.NewGenreService(genreRepository, s.logger)
 	trackService := service.NewTrackService(trackRepository, trackStorage, genreService, s.logger)
 
-	tracks, err := trackService.GetAll(context.Background())
+	tracks, err := trackService.GetAll(context.Background(), domain.TrackSortDefault)
 
 	t.Assert().Nil(tracks)
 	t.Assert().ErrorIs(err, ports.ErrInternalTrackRepo)
@@ -260,3 +288,185 @@ func (s *TrackDeleteSuite) TestNotFound(t provider.T) {
 func TestTrackDeleteSuite(t *testing.T) {
 	suite.RunSuite(t, new(TrackDeleteSuite))
 }
+
+type TrackReorderSuite struct {
+	TrackSuite
+}
+
+func (s *TrackReorderSuite) CorrectRepositoryMock(repository *mocks.TrackRepository, albumID uuid.UUID, positions []domain.TrackPosition) {
+	repository.
+		On("Reorder", context.Background(), albumID, positions).
+		Return(nil)
+}
+
+func (s *TrackReorderSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Track reorder test correct")
+	albumID := uuid.New()
+	positions := []domain.TrackPosition{
+		{TrackID: uuid.New(), DiscNumber: 1, TrackNumber: 2},
+		{TrackID: uuid.New(), DiscNumber: 1, TrackNumber: 1},
+	}
+	trackRepository := mocks.NewTrackRepository(t)
+	trackService := service.NewTrackService(trackRepository, nil, nil, s.logger)
+	s.CorrectRepositoryMock(trackRepository, albumID, positions)
+
+	err := trackService.Reorder(context.Background(), albumID, positions)
+
+	t.Assert().Nil(err)
+}
+
+func (s *TrackReorderSuite) TestDuplicatePosition(t provider.T) {
+	t.Parallel()
+	t.Title("Track reorder test duplicate position")
+	positions := []domain.TrackPosition{
+		{TrackID: uuid.New(), DiscNumber: 1, TrackNumber: 1},
+		{TrackID: uuid.New(), DiscNumber: 1, TrackNumber: 1},
+	}
+	trackRepository := mocks.NewTrackRepository(t)
+	trackService := service.NewTrackService(trackRepository, nil, nil, s.logger)
+
+	err := trackService.Reorder(context.Background(), uuid.New(), positions)
+
+	t.Assert().ErrorIs(err, ports.ErrTrackPosition)
+}
+
+func TestTrackReorderSuite(t *testing.T) {
+	suite.RunSuite(t, new(TrackReorderSuite))
+}
+
+type TrackUpdateSuite struct {
+	TrackSuite
+}
+
+func (s *TrackUpdateSuite) CorrectRepositoryMock(trackRepository *mocks.TrackRepository, genreRepository *mocks.GenreRepository, musicianID uuid.UUID, track domain.Track, albumID uuid.UUID, genreID []uuid.UUID) {
+	trackRepository.
+		On("GetOwn", context.Background(), musicianID, domain.TrackSortDefault).
+		Return([]domain.Track{track}, nil)
+
+	trackRepository.
+		On("Update", context.Background(), mock.MatchedBy(func(t domain.Track) bool {
+			return t.ID == track.ID && t.AlbumID == albumID && t.Name == "Renamed" && t.TrackNumber == 0
+		})).
+		Return(track, nil)
+
+	genreRepository.
+		On("AddForTrack", context.Background(), track.ID, genreID).
+		Return(nil)
+}
+
+func (s *TrackUpdateSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Track update test correct")
+	musicianID := uuid.New()
+	albumID := uuid.New()
+	genreID := []uuid.UUID{uuid.New()}
+	track := builder.NewTrackBuilder().Default().Build()
+	genreRepository := mocks.NewGenreRepository(t)
+	trackRepository := mocks.NewTrackRepository(t)
+	s.CorrectRepositoryMock(trackRepository, genreRepository, musicianID, track, albumID, genreID)
+	genreService := service.NewGenreService(genreRepository, s.logger)
+	trackService := service.NewTrackService(trackRepository, nil, genreService, s.logger)
+
+	_, err := trackService.Update(context.Background(), ports.UpdateTrackReq{
+		TrackID:    track.ID,
+		MusicianID: musicianID,
+		Name:       null.StringFrom("Renamed"),
+		AlbumID:    uuid.NullUUID{UUID: albumID, Valid: true},
+		GenresID:   genreID,
+	})
+
+	t.Assert().Nil(err)
+}
+
+func (s *TrackUpdateSuite) NotOwnRepositoryMock(trackRepository *mocks.TrackRepository, musicianID uuid.UUID) {
+	trackRepository.
+		On("GetOwn", context.Background(), musicianID, domain.TrackSortDefault).
+		Return([]domain.Track{builder.NewTrackBuilder().Default().Build()}, nil)
+}
+
+func (s *TrackUpdateSuite) TestNotOwn(t provider.T) {
+	t.Parallel()
+	t.Title("Track update test not own track")
+	musicianID := uuid.New()
+	trackRepository := mocks.NewTrackRepository(t)
+	s.NotOwnRepositoryMock(trackRepository, musicianID)
+	trackService := service.NewTrackService(trackRepository, nil, nil, s.logger)
+
+	_, err := trackService.Update(context.Background(), ports.UpdateTrackReq{
+		TrackID:    uuid.New(),
+		MusicianID: musicianID,
+		Name:       null.StringFrom("Renamed"),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrTrackIDNotFound)
+}
+
+func TestTrackUpdateSuite(t *testing.T) {
+	suite.RunSuite(t, new(TrackUpdateSuite))
+}
+
+type TrackReplaceAudioSuite struct {
+	TrackSuite
+}
+
+func (s *TrackReplaceAudioSuite) CorrectRepositoryMock(trackRepository *mocks.TrackRepository, trackStorage *mocks2.TrackObjectStorage, musicianID uuid.UUID, track domain.Track) {
+	trackRepository.
+		On("GetOwn", context.Background(), musicianID, domain.TrackSortDefault).
+		Return([]domain.Track{track}, nil)
+
+	trackURL, _ := url.Parse(track.URL)
+	trackStorage.
+		On("PutTrack", context.Background(), mock.MatchedBy(func(req ports.PutTrackReq) bool {
+			return req.TrackID == track.ID.String()
+		})).
+		Return(*trackURL, nil)
+}
+
+func (s *TrackReplaceAudioSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("Track replace audio test correct")
+	musicianID := uuid.New()
+	track := builder.NewTrackBuilder().Default().Build()
+	trackRepository := mocks.NewTrackRepository(t)
+	trackStorage := mocks2.NewTrackObjectStorage(t)
+	s.CorrectRepositoryMock(trackRepository, trackStorage, musicianID, track)
+	trackService := service.NewTrackService(trackRepository, trackStorage, nil, s.logger)
+
+	serviceTrack, err := trackService.ReplaceAudio(context.Background(), ports.ReplaceTrackAudioReq{
+		TrackID:    track.ID,
+		MusicianID: musicianID,
+		TrackBLOB:  strings.NewReader("audio"),
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(track, serviceTrack)
+}
+
+func (s *TrackReplaceAudioSuite) NotFoundRepositoryMock(trackRepository *mocks.TrackRepository, musicianID uuid.UUID) {
+	trackRepository.
+		On("GetOwn", context.Background(), musicianID, domain.TrackSortDefault).
+		Return([]domain.Track{}, nil)
+}
+
+func (s *TrackReplaceAudioSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("Track replace audio test not found")
+	musicianID := uuid.New()
+	trackRepository := mocks.NewTrackRepository(t)
+	trackStorage := mocks2.NewTrackObjectStorage(t)
+	s.NotFoundRepositoryMock(trackRepository, musicianID)
+	trackService := service.NewTrackService(trackRepository, trackStorage, nil, s.logger)
+
+	_, err := trackService.ReplaceAudio(context.Background(), ports.ReplaceTrackAudioReq{
+		TrackID:    uuid.New(),
+		MusicianID: musicianID,
+		TrackBLOB:  strings.NewReader("audio"),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrTrackIDNotFound)
+}
+
+func TestTrackReplaceAudioSuite(t *testing.T) {
+	suite.RunSuite(t, new(TrackReplaceAudioSuite))
+}
@@ -2,10 +2,17 @@ package test
 
 import (
 	"context"
+	"errors"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	mocks3 "github.com/hanoys/sigma-music/internal/adapters/auth/mocks"
 	"github.com/hanoys/sigma-music/internal/adapters/hash"
+	mocks2 "github.com/hanoys/sigma-music/internal/adapters/miniostorage/mocks"
 	"github.com/hanoys/sigma-music/internal/adapters/repository/mocks"
 	"github.com/hanoys/sigma-music/internal/domain"
 	"github.com/hanoys/sigma-music/internal/ports"
@@ -53,7 +60,7 @@ func (s *UserRegisterSuite) TestCorrect(t provider.T) {
 	t.Title("User register test correct")
 	req := builder.NewUserServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.CorrectRepositoryMock(repository, req)
 
 	_, err := userService.Register(context.Background(), req)
@@ -72,7 +79,7 @@ func (s *UserRegisterSuite) TestNameExists(t provider.T) {
 	t.Title("User register test name exists")
 	req := builder.NewUserServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.NameExistsRepositoryMock(repository, req)
 
 	_, err := userService.Register(context.Background(), req)
@@ -93,7 +100,7 @@ func (s *UserRegisterSuite) TestEmailExists(t provider.T) {
 	t.Title("User register test email exists")
 	req := builder.NewUserServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.EmailExistsRepositoryMock(repository, req)
 
 	_, err := userService.Register(context.Background(), req)
@@ -116,7 +123,7 @@ func (s *UserRegisterSuite) TestPhoneExists(t provider.T) {
 	t.Title("User register test phone exists")
 	req := builder.NewUserServiceCreateRequestBuilder().Default().Build()
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.PhoneExistsRepositoryMock(repository, req)
 
 	_, err := userService.Register(context.Background(), req)
@@ -142,7 +149,7 @@ func (s *UserGetAllSuite) TestRepositoryError(t provider.T) {
 	t.Parallel()
 	t.Title("User get all test error")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.RepositoryErrorRepositoryMock(repository)
 
 	_, err := userService.GetAll(context.Background())
@@ -160,7 +167,7 @@ func (s *UserGetAllSuite) TestSuccess(t provider.T) {
 	t.Parallel()
 	t.Title("User get all test success")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.SuccessRepositoryMock(repository)
 
 	_, err := userService.GetAll(context.Background())
@@ -186,7 +193,7 @@ func (s *UserGetByIdSuite) TestIDNotFound(t provider.T) {
 	t.Parallel()
 	t.Title("User get by id test id not found")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.IdNotFoundRepositoryMock(repository)
 
 	_, err := userService.GetById(context.Background(), uuid.New())
@@ -204,7 +211,7 @@ func (s *UserGetByIdSuite) TestSuccess(t provider.T) {
 	t.Parallel()
 	t.Title("User get by id test success")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.SuccessRepositoryMock(repository)
 
 	_, err := userService.GetById(context.Background(), uuid.New())
@@ -231,7 +238,7 @@ func (s *UserGetByNameSuite) TestNameNotFound(t provider.T) {
 	t.Parallel()
 	t.Title("User get by name test not found")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.NameNotFoundRepositoryMock(repository)
 
 	_, err := userService.GetByName(context.Background(), "")
@@ -249,7 +256,7 @@ func (s *UserGetByNameSuite) TestSuccess(t provider.T) {
 	t.Parallel()
 	t.Title("User get by name test success")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.SuccessRepositoryMock(repository)
 
 	_, err := userService.GetByName(context.Background(), "")
@@ -276,7 +283,7 @@ func (s *UserGetByEmailSuite) TestEmailNotFoundError(t provider.T) {
 	t.Parallel()
 	t.Title("User get by email test not found")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.EmailNotFoundRepositoryMock(repository)
 
 	_, err := userService.GetByEmail(context.Background(), "")
@@ -294,7 +301,7 @@ func (s *UserGetByEmailSuite) TestSuccess(t provider.T) {
 	t.Parallel()
 	t.Title("User get by email test success")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.SuccessRepositoryMock(repository)
 
 	_, err := userService.GetByEmail(context.Background(), "")
@@ -320,7 +327,7 @@ func (s *UserGetByPhoneSuite) TestRepositoryError(t provider.T) {
 	t.Parallel()
 	t.Title("User get by phone test error")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.PhoneNotFoundRepositoryMock(repository)
 
 	_, err := userService.GetByPhone(context.Background(), "")
@@ -338,7 +345,7 @@ func (s *UserGetByPhoneSuite) TestSuccess(t provider.T) {
 	t.Parallel()
 	t.Title("User get by phone test success")
 	repository := mocks.NewUserRepository(t)
-	userService := service.NewUserService(repository, s.hashProvider, s.logger)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
 	s.SuccessRepositoryMock(repository)
 
 	_, err := userService.GetByPhone(context.Background(), "")
@@ -349,3 +356,381 @@ func (s *UserGetByPhoneSuite) TestSuccess(t provider.T) {
 func TestUserGetByPhoneSuite(t *testing.T) {
 	suite.RunSuite(t, new(UserGetByPhoneSuite))
 }
+
+type UserSetRoleSuite struct {
+	UserSuite
+}
+
+func (s *UserSetRoleSuite) SuccessRepositoryMock(repository *mocks.UserRepository, user domain.User) {
+	promoted := user
+	promoted.Role = domain.ModeratorRole
+	repository.
+		On("SetRole", context.Background(), user.ID, domain.ModeratorRole).
+		Return(promoted, nil)
+}
+
+func (s *UserSetRoleSuite) TestSuccess(t provider.T) {
+	t.Parallel()
+	t.Title("User set role test success")
+	user := builder.NewUserBuilder().Default().Build()
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.SuccessRepositoryMock(repository, user)
+
+	updated, err := userService.SetRole(context.Background(), user.ID, domain.ModeratorRole)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(domain.ModeratorRole, updated.Role)
+}
+
+func (s *UserSetRoleSuite) TestMusicianRole(t provider.T) {
+	t.Parallel()
+	t.Title("User set role test musician role rejected")
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+
+	_, err := userService.SetRole(context.Background(), uuid.New(), domain.MusicianRole)
+
+	t.Assert().ErrorIs(err, ports.ErrUserRole)
+}
+
+func (s *UserSetRoleSuite) NotFoundRepositoryMock(repository *mocks.UserRepository) {
+	repository.
+		On("SetRole", context.Background(), mock.AnythingOfType("uuid.UUID"), domain.AdminRole).
+		Return(domain.User{}, ports.ErrUserIDNotFound)
+}
+
+func (s *UserSetRoleSuite) TestNotFound(t provider.T) {
+	t.Parallel()
+	t.Title("User set role test user not found")
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.NotFoundRepositoryMock(repository)
+
+	_, err := userService.SetRole(context.Background(), uuid.New(), domain.AdminRole)
+
+	t.Assert().ErrorIs(err, ports.ErrUserIDNotFound)
+}
+
+func TestUserSetRoleSuite(t *testing.T) {
+	suite.RunSuite(t, new(UserSetRoleSuite))
+}
+
+type UserUpdateProfileSuite struct {
+	UserSuite
+}
+
+func (s *UserUpdateProfileSuite) CorrectRepositoryMock(repository *mocks.UserRepository, userID uuid.UUID,
+	changes domain.UserProfileChanges, updated domain.User) {
+	repository.
+		On("UpdateProfile", context.Background(), userID, changes).
+		Return(updated, nil)
+}
+
+func (s *UserUpdateProfileSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("User update profile test correct")
+	user := builder.NewUserBuilder().Default().Build()
+	updated := user
+	updated.Email = "new@mail.com"
+	updated.Country = "Germany"
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.CorrectRepositoryMock(repository, user.ID, domain.UserProfileChanges{
+		Email:   null.StringFrom(updated.Email),
+		Country: null.StringFrom(updated.Country),
+	}, updated)
+
+	result, err := userService.UpdateProfile(context.Background(), user.ID, ports.UserServiceUpdateRequest{
+		Email:   null.StringFrom(updated.Email),
+		Country: null.StringFrom(updated.Country),
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(updated, result)
+}
+
+func (s *UserUpdateProfileSuite) PhoneTakenRepositoryMock(repository *mocks.UserRepository, userID uuid.UUID,
+	phone string) {
+	repository.
+		On("UpdateProfile", context.Background(), userID, domain.UserProfileChanges{Phone: null.StringFrom(phone)}).
+		Return(domain.User{}, ports.ErrUserDuplicate)
+}
+
+func (s *UserUpdateProfileSuite) TestPhoneTaken(t provider.T) {
+	t.Parallel()
+	t.Title("User update profile test phone taken by another user")
+	userID := uuid.New()
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.PhoneTakenRepositoryMock(repository, userID, "+71111111111")
+
+	_, err := userService.UpdateProfile(context.Background(), userID, ports.UserServiceUpdateRequest{
+		Phone: null.StringFrom("+71111111111"),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrUserDuplicate)
+}
+
+func (s *UserUpdateProfileSuite) TestInvalidName(t provider.T) {
+	t.Parallel()
+	t.Title("User update profile test name with @")
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+
+	_, err := userService.UpdateProfile(context.Background(), uuid.New(), ports.UserServiceUpdateRequest{
+		Name: null.StringFrom("user@mail.com"),
+	})
+
+	t.Assert().ErrorIs(err, ports.ErrAccountInvalidName)
+}
+
+func TestUserUpdateProfileSuite(t *testing.T) {
+	suite.RunSuite(t, new(UserUpdateProfileSuite))
+}
+
+type UserUploadAvatarSuite struct {
+	UserSuite
+}
+
+func (s *UserUploadAvatarSuite) CorrectRepositoryMock(repository *mocks.UserRepository,
+	avatarStorage *mocks2.UserAvatarStorage, user domain.User, avatarURL url.URL) {
+	updated := user
+	updated.AvatarURL = null.StringFrom(avatarURL.String())
+	repository.
+		On("GetByID", context.Background(), user.ID).
+		Return(user, nil).
+		On("SetAvatar", context.Background(), user.ID, avatarURL.String()).
+		Return(updated, nil)
+	avatarStorage.
+		On("UploadImage", context.Background(), mock.Anything, user.ID.String()).
+		Return(avatarURL, nil)
+}
+
+func (s *UserUploadAvatarSuite) TestCorrect(t provider.T) {
+	t.Parallel()
+	t.Title("User upload avatar test correct")
+	user := builder.NewUserBuilder().Default().Build()
+	avatarURL := url.URL{Scheme: "http", Host: "localhost:9000", Path: "avatars/" + user.ID.String() + "_avatar.jpg"}
+	repository := mocks.NewUserRepository(t)
+	avatarStorage := mocks2.NewUserAvatarStorage(t)
+	userService := service.NewUserService(repository, avatarStorage, nil, nil, nil, s.hashProvider, service.UserServiceConfig{},
+		s.logger)
+	s.CorrectRepositoryMock(repository, avatarStorage, user, avatarURL)
+
+	result, err := userService.UploadAvatar(context.Background(), strings.NewReader("image"), user.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(avatarURL.String(), result.AvatarURL.String)
+}
+
+func TestUserUploadAvatarSuite(t *testing.T) {
+	suite.RunSuite(t, new(UserUploadAvatarSuite))
+}
+
+type UserDeletionSuite struct {
+	UserSuite
+}
+
+func (s *UserDeletionSuite) RequestRepositoryMock(repository *mocks.UserRepository, user domain.User) {
+	repository.
+		On("ScheduleDeletion", context.Background(), user.ID, mock.AnythingOfType("time.Time")).
+		Return(func(_ context.Context, _ uuid.UUID, at time.Time) (domain.User, error) {
+			scheduled := user
+			scheduled.DeletionScheduledAt = null.TimeFrom(at)
+			return scheduled, nil
+		})
+}
+
+func (s *UserDeletionSuite) TestRequest(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test request schedules after grace period and closes sessions")
+	user := builder.NewUserBuilder().Default().Build()
+	repository := mocks.NewUserRepository(t)
+	tokenProvider := mocks3.NewTokenProvider(t)
+	userService := service.NewUserService(repository, nil, nil, nil, tokenProvider, s.hashProvider,
+		service.UserServiceConfig{DeletionGracePeriod: 7 * 24 * time.Hour}, s.logger)
+	s.RequestRepositoryMock(repository, user)
+	tokenProvider.
+		On("RevokeAllSessions", context.Background(), user.ID).
+		Return(nil)
+
+	result, err := userService.RequestDeletion(context.Background(), user.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().True(result.DeletionScheduledAt.Valid)
+	t.Assert().WithinDuration(time.Now().Add(7*24*time.Hour), result.DeletionScheduledAt.Time, time.Minute)
+}
+
+func (s *UserDeletionSuite) ScheduledRepositoryMock(repository *mocks.UserRepository, user domain.User) {
+	repository.
+		On("ScheduleDeletion", context.Background(), user.ID, mock.AnythingOfType("time.Time")).
+		Return(domain.User{}, ports.ErrUserDeletionRequested)
+}
+
+func (s *UserDeletionSuite) TestAlreadyRequested(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test already requested")
+	user := builder.NewUserBuilder().Default().Build()
+	user.DeletionScheduledAt = null.TimeFrom(time.Now().Add(time.Hour))
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.ScheduledRepositoryMock(repository, user)
+
+	_, err := userService.RequestDeletion(context.Background(), user.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrUserDeletionRequested)
+}
+
+func (s *UserDeletionSuite) CancelRepositoryMock(repository *mocks.UserRepository, user domain.User) {
+	cancelled := user
+	cancelled.DeletionScheduledAt = null.Time{}
+	repository.
+		On("CancelDeletion", context.Background(), user.ID).
+		Return(cancelled, nil)
+}
+
+func (s *UserDeletionSuite) NotScheduledRepositoryMock(repository *mocks.UserRepository, user domain.User) {
+	repository.
+		On("CancelDeletion", context.Background(), user.ID).
+		Return(domain.User{}, ports.ErrUserDeletionNotRequested)
+}
+
+func (s *UserDeletionSuite) TestCancel(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test cancel")
+	user := builder.NewUserBuilder().Default().Build()
+	user.DeletionScheduledAt = null.TimeFrom(time.Now().Add(time.Hour))
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.CancelRepositoryMock(repository, user)
+
+	result, err := userService.CancelDeletion(context.Background(), user.ID)
+
+	t.Assert().Nil(err)
+	t.Assert().False(result.DeletionScheduledAt.Valid)
+}
+
+func (s *UserDeletionSuite) TestCancelNotRequested(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test cancel without request")
+	user := builder.NewUserBuilder().Default().Build()
+	repository := mocks.NewUserRepository(t)
+	userService := service.NewUserService(repository, nil, nil, nil, nil, s.hashProvider, service.UserServiceConfig{}, s.logger)
+	s.NotScheduledRepositoryMock(repository, user)
+
+	_, err := userService.CancelDeletion(context.Background(), user.ID)
+
+	t.Assert().ErrorIs(err, ports.ErrUserDeletionNotRequested)
+}
+
+type UserPurgeMocks struct {
+	repository    *mocks.UserRepository
+	avatarStorage *mocks2.UserAvatarStorage
+	exportRepo    *mocks.DataExportRepository
+	exportStorage *mocks2.DataExportStorage
+	tokenProvider *mocks3.TokenProvider
+}
+
+func (s *UserDeletionSuite) newPurgeService(t provider.T) (*service.UserService, UserPurgeMocks) {
+	m := UserPurgeMocks{
+		repository:    mocks.NewUserRepository(t),
+		avatarStorage: mocks2.NewUserAvatarStorage(t),
+		exportRepo:    mocks.NewDataExportRepository(t),
+		exportStorage: mocks2.NewDataExportStorage(t),
+		tokenProvider: mocks3.NewTokenProvider(t),
+	}
+
+	return service.NewUserService(m.repository, m.avatarStorage, m.exportRepo, m.exportStorage, m.tokenProvider,
+		s.hashProvider, service.UserServiceConfig{}, s.logger), m
+}
+
+func (s *UserDeletionSuite) PurgeRepositoryMock(m UserPurgeMocks, id uuid.UUID, exports []domain.DataExport) {
+	m.tokenProvider.
+		On("RevokeAllSessions", context.Background(), id).
+		Return(nil)
+	m.exportRepo.
+		On("GetByUserID", context.Background(), id).
+		Return(exports, nil)
+	for _, export := range exports {
+		m.exportStorage.
+			On("DeleteArchive", context.Background(), export.ID.String()).
+			Return(nil)
+	}
+	m.repository.
+		On("DeleteScheduled", context.Background(), id, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	m.avatarStorage.
+		On("DeleteImage", context.Background(), id.String()).
+		Return(nil)
+}
+
+func (s *UserDeletionSuite) TestPurge(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test purge closes sessions and removes archives and avatars")
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	userService, m := s.newPurgeService(t)
+	m.repository.
+		On("GetScheduledBefore", context.Background(), mock.AnythingOfType("time.Time")).
+		Return(ids, nil)
+	s.PurgeRepositoryMock(m, ids[0], []domain.DataExport{{ID: uuid.New()}, {ID: uuid.New()}})
+	s.PurgeRepositoryMock(m, ids[1], nil)
+
+	purged, err := userService.PurgeDeleted(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(ids, purged)
+}
+
+func (s *UserDeletionSuite) TestPurgeKeepsUserOnCleanupFailure(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test purge keeps the account when an archive can't be removed")
+	id := uuid.New()
+	export := domain.DataExport{ID: uuid.New()}
+	userService, m := s.newPurgeService(t)
+	m.repository.
+		On("GetScheduledBefore", context.Background(), mock.AnythingOfType("time.Time")).
+		Return([]uuid.UUID{id}, nil)
+	m.tokenProvider.
+		On("RevokeAllSessions", context.Background(), id).
+		Return(nil)
+	m.exportRepo.
+		On("GetByUserID", context.Background(), id).
+		Return([]domain.DataExport{export}, nil)
+	m.exportStorage.
+		On("DeleteArchive", context.Background(), export.ID.String()).
+		Return(errors.New("storage is unavailable"))
+
+	purged, err := userService.PurgeDeleted(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(purged)
+	m.repository.AssertNotCalled(t, "DeleteScheduled", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UserDeletionSuite) TestPurgeSkipsCancelled(t provider.T) {
+	t.Parallel()
+	t.Title("User deletion test purge skips a request cancelled meanwhile")
+	id := uuid.New()
+	userService, m := s.newPurgeService(t)
+	m.repository.
+		On("GetScheduledBefore", context.Background(), mock.AnythingOfType("time.Time")).
+		Return([]uuid.UUID{id}, nil).
+		On("DeleteScheduled", context.Background(), id, mock.AnythingOfType("time.Time")).
+		Return(ports.ErrUserDeletionNotRequested)
+	m.tokenProvider.
+		On("RevokeAllSessions", context.Background(), id).
+		Return(nil)
+	m.exportRepo.
+		On("GetByUserID", context.Background(), id).
+		Return(nil, nil)
+
+	purged, err := userService.PurgeDeleted(context.Background())
+
+	t.Assert().Nil(err)
+	t.Assert().Empty(purged)
+}
+
+func TestUserDeletionSuite(t *testing.T) {
+	suite.RunSuite(t, new(UserDeletionSuite))
+}
@@ -43,10 +43,11 @@ func (ts *TrackService) Create(ctx context.Context, trackInfo ports.CreateTrackR
 	}
 
 	track, err := ts.repository.Create(ctx, domain.Track{
-		ID:      trackID,
-		AlbumID: trackInfo.AlbumID,
-		Name:    trackInfo.Name,
-		URL:     url.String(),
+		ID:              trackID,
+		AlbumID:         trackInfo.AlbumID,
+		Name:            trackInfo.Name,
+		URL:             url.String(),
+		DurationSeconds: trackInfo.DurationSeconds,
 	})
 	if err != nil {
 		ts.logger.Error("Failed to create track", zap.Error(err),
@@ -74,8 +75,8 @@ func (ts *TrackService) Create(ctx context.Context, trackInfo ports.CreateTrackR
 	return track, nil
 }
 
-func (ts *TrackService) GetAll(ctx context.Context) ([]domain.Track, error) {
-	tracks, err := ts.repository.GetAll(ctx)
+func (ts *TrackService) GetAll(ctx context.Context, sort domain.TrackSort) ([]domain.Track, error) {
+	tracks, err := ts.repository.GetAll(ctx, sort)
 	if err != nil {
 		ts.logger.Error("Failed to get all tracks", zap.Error(err))
 		return nil, err
@@ -125,8 +126,8 @@ func (ts *TrackService) DeleteFavorite(ctx context.Context, trackID uuid.UUID, u
 	return track, nil
 }
 
-func (ts *TrackService) GetUserFavorites(ctx context.Context, userID uuid.UUID) ([]domain.Track, error) {
-	tracks, err := ts.repository.GetUserFavorites(ctx, userID)
+func (ts *TrackService) GetUserFavorites(ctx context.Context, userID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	tracks, err := ts.repository.GetUserFavorites(ctx, userID, sort)
 	if err != nil {
 		ts.logger.Error("Failed to get user favorites tracks", zap.Error(err),
 			zap.String("User ID", userID.String()))
@@ -154,8 +155,8 @@ func (ts *TrackService) AddToUserFavorites(ctx context.Context, trackID uuid.UUI
 	return nil
 }
 
-func (ts *TrackService) GetByAlbumID(ctx context.Context, albumID uuid.UUID) ([]domain.Track, error) {
-	tracks, err := ts.repository.GetByAlbumID(ctx, albumID)
+func (ts *TrackService) GetByAlbumID(ctx context.Context, albumID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	tracks, err := ts.repository.GetByAlbumID(ctx, albumID, sort)
 	if err != nil {
 		ts.logger.Error("Failed to get tracks by album ID", zap.Error(err),
 			zap.String("Album ID", albumID.String()))
@@ -168,8 +169,8 @@ func (ts *TrackService) GetByAlbumID(ctx context.Context, albumID uuid.UUID) ([]
 	return tracks, nil
 }
 
-func (ts *TrackService) GetByMusicianID(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error) {
-	tracks, err := ts.repository.GetByMusicianID(ctx, musicianID)
+func (ts *TrackService) GetByMusicianID(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	tracks, err := ts.repository.GetByMusicianID(ctx, musicianID, sort)
 	if err != nil {
 		ts.logger.Error("Failed to get tracks by musician ID", zap.Error(err),
 			zap.String("Musician ID", musicianID.String()))
@@ -182,8 +183,8 @@ func (ts *TrackService) GetByMusicianID(ctx context.Context, musicianID uuid.UUI
 	return tracks, nil
 }
 
-func (ts *TrackService) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]domain.Track, error) {
-	tracks, err := ts.repository.GetOwn(ctx, musicianID)
+func (ts *TrackService) GetOwn(ctx context.Context, musicianID uuid.UUID, sort domain.TrackSort) ([]domain.Track, error) {
+	tracks, err := ts.repository.GetOwn(ctx, musicianID, sort)
 	if err != nil {
 		ts.logger.Error("Failed to get own musician tracks", zap.Error(err),
 			zap.String("Musician ID", musicianID.String()))
@@ -195,3 +196,160 @@ func (ts *TrackService) GetOwn(ctx context.Context, musicianID uuid.UUID) ([]dom
 
 	return tracks, nil
 }
+
+func (ts *TrackService) DeleteByAlbumID(ctx context.Context, albumID uuid.UUID) error {
+	tracks, err := ts.repository.GetByAlbumIDInternal(ctx, albumID)
+	if err != nil {
+		ts.logger.Error("Failed to get album tracks for deletion", zap.Error(err),
+			zap.String("Album ID", albumID.String()))
+
+		return err
+	}
+
+	for _, track := range tracks {
+		_, err = ts.Delete(ctx, track.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	ts.logger.Info("Album tracks successfully deleted", zap.String("Album ID", albumID.String()))
+
+	return nil
+}
+
+func (ts *TrackService) Reorder(ctx context.Context, albumID uuid.UUID, positions []domain.TrackPosition) error {
+	if len(positions) == 0 {
+		return ports.ErrTrackPosition
+	}
+
+	type slot struct{ disc, number int }
+	seenTracks := make(map[uuid.UUID]struct{}, len(positions))
+	seenSlots := make(map[slot]struct{}, len(positions))
+	for _, position := range positions {
+		if position.DiscNumber <= 0 || position.TrackNumber <= 0 {
+			return ports.ErrTrackPosition
+		}
+
+		if _, ok := seenTracks[position.TrackID]; ok {
+			return ports.ErrTrackPosition
+		}
+		seenTracks[position.TrackID] = struct{}{}
+
+		key := slot{position.DiscNumber, position.TrackNumber}
+		if _, ok := seenSlots[key]; ok {
+			return ports.ErrTrackPosition
+		}
+		seenSlots[key] = struct{}{}
+	}
+
+	err := ts.repository.Reorder(ctx, albumID, positions)
+	if err != nil {
+		ts.logger.Error("Failed to reorder album tracks", zap.Error(err),
+			zap.String("Album ID", albumID.String()))
+
+		return err
+	}
+
+	ts.logger.Info("Album tracks successfully reordered", zap.String("Album ID", albumID.String()))
+
+	return nil
+}
+
+func (ts *TrackService) getOwnTrack(ctx context.Context, trackID uuid.UUID, musicianID uuid.UUID) (domain.Track, error) {
+	tracks, err := ts.repository.GetOwn(ctx, musicianID, domain.TrackSortDefault)
+	if err != nil {
+		return domain.Track{}, err
+	}
+
+	for _, track := range tracks {
+		if track.ID == trackID {
+			return track, nil
+		}
+	}
+
+	return domain.Track{}, ports.ErrTrackIDNotFound
+}
+
+func (ts *TrackService) Update(ctx context.Context, trackInfo ports.UpdateTrackReq) (domain.Track, error) {
+	track, err := ts.getOwnTrack(ctx, trackInfo.TrackID, trackInfo.MusicianID)
+	if err != nil {
+		ts.logger.Error("Failed to update track", zap.Error(err), zap.String("Track ID", trackInfo.TrackID.String()))
+		return domain.Track{}, err
+	}
+
+	if trackInfo.Name.Valid {
+		track.Name = trackInfo.Name.String
+	}
+
+	if trackInfo.DurationSeconds.Valid {
+		track.DurationSeconds = int(trackInfo.DurationSeconds.Int64)
+	}
+
+	// A moved track is appended to the end of the first disc of the new album.
+	if trackInfo.AlbumID.Valid && trackInfo.AlbumID.UUID != track.AlbumID {
+		track.AlbumID = trackInfo.AlbumID.UUID
+		track.DiscNumber = 1
+		track.TrackNumber = 0
+	}
+
+	track, err = ts.repository.Update(ctx, track)
+	if err != nil {
+		ts.logger.Error("Failed to update track", zap.Error(err), zap.String("Track ID", trackInfo.TrackID.String()))
+		return domain.Track{}, err
+	}
+
+	if trackInfo.GenresID != nil {
+		err = ts.genreService.AddForTrack(ctx, track.ID, trackInfo.GenresID)
+		if err != nil {
+			ts.logger.Error("Failed to update track genres", zap.Error(err),
+				zap.String("Track ID", trackInfo.TrackID.String()))
+
+			return domain.Track{}, err
+		}
+	}
+
+	ts.logger.Info("Track successfully updated", zap.String("Track ID", track.ID.String()),
+		zap.String("Album ID", track.AlbumID.String()))
+
+	return track, nil
+}
+
+func (ts *TrackService) ReplaceAudio(ctx context.Context, trackInfo ports.ReplaceTrackAudioReq) (domain.Track, error) {
+	track, err := ts.getOwnTrack(ctx, trackInfo.TrackID, trackInfo.MusicianID)
+	if err != nil {
+		ts.logger.Error("Failed to replace track audio", zap.Error(err),
+			zap.String("Track ID", trackInfo.TrackID.String()))
+
+		return domain.Track{}, err
+	}
+
+	// The object is stored under the track ID, so putting it again overwrites
+	// the audio while favorites, comments and history keep pointing at the track.
+	url, err := ts.trackStorage.PutTrack(ctx, ports.PutTrackReq{
+		TrackID:   track.ID.String(),
+		TrackBLOB: trackInfo.TrackBLOB,
+	})
+	if err != nil {
+		ts.logger.Error("Failed to replace track audio", zap.Error(err),
+			zap.String("Track ID", trackInfo.TrackID.String()))
+
+		return domain.Track{}, err
+	}
+
+	if url.String() != track.URL {
+		track.URL = url.String()
+		track, err = ts.repository.Update(ctx, track)
+		if err != nil {
+			ts.logger.Error("Failed to update track URL", zap.Error(err),
+				zap.String("Track ID", trackInfo.TrackID.String()), zap.String("Track URL", url.String()))
+
+			return domain.Track{}, err
+		}
+	}
+
+	ts.logger.Info("Track audio successfully replaced", zap.String("Track ID", track.ID.String()),
+		zap.String("Track URL", track.URL))
+
+	return track, nil
+}
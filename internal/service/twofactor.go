@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hanoys/sigma-music/internal/domain"
+	"github.com/hanoys/sigma-music/internal/ports"
+)
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code.
+// Both are burnt by the repository, so neither can be replayed.
+func verifySecondFactor(ctx context.Context, repo ports.ITwoFactorRepository, totp ports.ITOTPProvider,
+	twoFactor domain.TwoFactor, code string) error {
+	if step, ok := totp.Validate(twoFactor.Secret, code, time.Now()); ok {
+		return repo.UseStep(ctx, twoFactor.AccountID, step)
+	}
+
+	return repo.UseRecoveryCode(ctx, twoFactor.AccountID, totp.HashRecoveryCode(code))
+}
@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hanoys/sigma-music/internal/domain"
@@ -9,22 +13,52 @@ import (
 	"go.uber.org/zap"
 )
 
+const defaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+// UserServiceConfig sets how long a deletion request can be cancelled before
+// the account is removed for good.
+type UserServiceConfig struct {
+	DeletionGracePeriod time.Duration
+}
+
 type UserService struct {
-	repository ports.IUserRepository
-	hash       ports.IHashPasswordProvider
-	logger     *zap.Logger
+	repository    ports.IUserRepository
+	avatarStorage ports.IUserAvatarStorage
+	exportRepo    ports.IDataExportRepository
+	exportStorage ports.IDataExportStorage
+	tokenProvider ports.ITokenProvider
+	hash          ports.IHashPasswordProvider
+	gracePeriod   time.Duration
+	logger        *zap.Logger
 }
 
-func NewUserService(repo ports.IUserRepository, hash ports.IHashPasswordProvider,
+func NewUserService(repo ports.IUserRepository, avatarStorage ports.IUserAvatarStorage,
+	exportRepo ports.IDataExportRepository, exportStorage ports.IDataExportStorage,
+	tokenProvider ports.ITokenProvider, hash ports.IHashPasswordProvider, cfg UserServiceConfig,
 	logger *zap.Logger) *UserService {
+	if cfg.DeletionGracePeriod <= 0 {
+		cfg.DeletionGracePeriod = defaultDeletionGracePeriod
+	}
+
 	return &UserService{
-		repository: repo,
-		hash:       hash,
-		logger:     logger,
+		repository:    repo,
+		avatarStorage: avatarStorage,
+		exportRepo:    exportRepo,
+		exportStorage: exportStorage,
+		tokenProvider: tokenProvider,
+		hash:          hash,
+		gracePeriod:   cfg.DeletionGracePeriod,
+		logger:        logger,
 	}
 }
 
 func (us *UserService) Register(ctx context.Context, user ports.UserServiceCreateRequest) (domain.User, error) {
+	if strings.Contains(user.Name, "@") {
+		us.logger.Error("Failed to register user", zap.Error(ports.ErrAccountInvalidName),
+			zap.String("User Name", user.Name))
+		return domain.User{}, ports.ErrAccountInvalidName
+	}
+
 	_, err := us.repository.GetByName(ctx, user.Name)
 	if err == nil {
 		us.logger.Error("Failed to register user", zap.Error(err), zap.String("User Name", user.Name))
@@ -53,6 +87,7 @@ func (us *UserService) Register(ctx context.Context, user ports.UserServiceCreat
 		Password: saltedPassword.HashPassword,
 		Salt:     saltedPassword.Salt,
 		Country:  user.Country,
+		Role:     domain.UserRole,
 	}
 
 	u, err := us.repository.Create(ctx, createUser)
@@ -131,3 +166,167 @@ func (us *UserService) GetByPhone(ctx context.Context, phone string) (domain.Use
 
 	return u, nil
 }
+
+func (us *UserService) SetRole(ctx context.Context, userID uuid.UUID, role int) (domain.User, error) {
+	if !domain.IsUserAccountRole(role) {
+		us.logger.Error("Failed to set user role", zap.Error(ports.ErrUserRole),
+			zap.String("User ID", userID.String()), zap.Int("Role", role))
+		return domain.User{}, ports.ErrUserRole
+	}
+
+	u, err := us.repository.SetRole(ctx, userID, role)
+	if err != nil {
+		us.logger.Error("Failed to set user role", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	us.logger.Info("User role successfully changed", zap.String("User ID", userID.String()),
+		zap.String("Role", domain.RoleName(role)))
+
+	return u, nil
+}
+
+// UpdateProfile changes only the given fields. Names, emails and phones taken
+// by another account are rejected by the unique constraints.
+func (us *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID,
+	req ports.UserServiceUpdateRequest) (domain.User, error) {
+	if req.Name.Valid && strings.Contains(req.Name.String, "@") {
+		us.logger.Error("Failed to update user", zap.Error(ports.ErrAccountInvalidName),
+			zap.String("User ID", userID.String()), zap.String("User Name", req.Name.String))
+		return domain.User{}, ports.ErrAccountInvalidName
+	}
+
+	u, err := us.repository.UpdateProfile(ctx, userID, domain.UserProfileChanges{
+		Name:    req.Name,
+		Email:   req.Email,
+		Phone:   req.Phone,
+		Country: req.Country,
+	})
+	if err != nil {
+		us.logger.Error("Failed to update user", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	us.logger.Info("User profile successfully updated", zap.String("User ID", userID.String()))
+
+	return u, nil
+}
+
+func (us *UserService) UploadAvatar(ctx context.Context, image io.Reader, userID uuid.UUID) (domain.User, error) {
+	_, err := us.repository.GetByID(ctx, userID)
+	if err != nil {
+		us.logger.Error("Failed to upload user avatar", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	url, err := us.avatarStorage.UploadImage(ctx, image, userID.String())
+	if err != nil {
+		us.logger.Error("Failed to upload user avatar", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	u, err := us.repository.SetAvatar(ctx, userID, url.String())
+	if err != nil {
+		us.logger.Error("Failed to upload user avatar", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	us.logger.Info("User avatar successfully uploaded", zap.String("User ID", userID.String()))
+
+	return u, nil
+}
+
+// RequestDeletion schedules the account for removal after the grace period
+// and closes all of its sessions. Until then the user can log in again and
+// cancel the request.
+func (us *UserService) RequestDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	u, err := us.repository.ScheduleDeletion(ctx, userID, time.Now().Add(us.gracePeriod))
+	if err != nil {
+		us.logger.Error("Failed to request user deletion", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	err = us.tokenProvider.RevokeAllSessions(ctx, userID)
+	if err != nil {
+		us.logger.Error("Failed to close sessions after deletion request", zap.Error(err),
+			zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	us.logger.Info("User deletion successfully requested", zap.String("User ID", userID.String()),
+		zap.Time("Deletion At", u.DeletionScheduledAt.Time))
+
+	return u, nil
+}
+
+func (us *UserService) CancelDeletion(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	u, err := us.repository.CancelDeletion(ctx, userID)
+	if err != nil {
+		us.logger.Error("Failed to cancel user deletion", zap.Error(err), zap.String("User ID", userID.String()))
+		return domain.User{}, err
+	}
+
+	us.logger.Info("User deletion successfully cancelled", zap.String("User ID", userID.String()))
+
+	return u, nil
+}
+
+// PurgeDeleted removes the accounts whose grace period is over. Sessions and
+// export archives go first, a user whose cleanup fails is kept for the next
+// run. The database cascades the removal to the user's history, comments,
+// favorites, subscriptions and exports; avatars are removed afterwards.
+func (us *UserService) PurgeDeleted(ctx context.Context) ([]uuid.UUID, error) {
+	now := time.Now()
+	ids, err := us.repository.GetScheduledBefore(ctx, now)
+	if err != nil {
+		us.logger.Error("Failed to purge deleted users", zap.Error(err))
+		return nil, err
+	}
+
+	purged := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		err = us.purge(ctx, id, now)
+		if errors.Is(err, ports.ErrUserDeletionNotRequested) {
+			continue
+		} else if err != nil {
+			us.logger.Error("Failed to purge deleted user", zap.Error(err), zap.String("User ID", id.String()))
+			continue
+		}
+
+		err = us.avatarStorage.DeleteImage(ctx, id.String())
+		if err != nil {
+			us.logger.Error("Failed to delete user avatar", zap.Error(err), zap.String("User ID", id.String()))
+		}
+
+		purged = append(purged, id)
+	}
+
+	if len(purged) > 0 {
+		us.logger.Info("Deleted users successfully purged", zap.Int("Count", len(purged)))
+	}
+
+	return purged, nil
+}
+
+// purge closes the user's sessions and deletes the export archives before the
+// account, since the data_exports rows naming the archives go with it.
+func (us *UserService) purge(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	err := us.tokenProvider.RevokeAllSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	exports, err := us.exportRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		err = us.exportStorage.DeleteArchive(ctx, export.ID.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return us.repository.DeleteScheduled(ctx, userID, now)
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS feed CASCADE;
+DROP TABLE IF EXISTS musician_followers CASCADE;
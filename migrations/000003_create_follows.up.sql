@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS musician_followers (
+    user_id UUID REFERENCES users ON DELETE CASCADE,
+    musician_id UUID REFERENCES musicians ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, musician_id)
+);
+
+CREATE TABLE IF NOT EXISTS feed (
+    user_id UUID REFERENCES users ON DELETE CASCADE,
+    musician_id UUID REFERENCES musicians ON DELETE CASCADE,
+    album_id UUID REFERENCES albums ON DELETE CASCADE,
+    published_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, album_id)
+);
+
+CREATE INDEX IF NOT EXISTS feed_user_published_idx ON feed (user_id, published_at DESC);
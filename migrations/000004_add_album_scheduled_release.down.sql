@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS albums_scheduled_release_idx;
+
+ALTER TABLE albums DROP COLUMN IF EXISTS scheduled_release;
@@ -0,0 +1,4 @@
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS scheduled_release TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS albums_scheduled_release_idx ON albums(scheduled_release)
+    WHERE published = FALSE AND scheduled_release IS NOT NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE tracks DROP CONSTRAINT IF EXISTS tracks_album_position_key;
+
+ALTER TABLE tracks DROP COLUMN IF EXISTS track_number;
+ALTER TABLE tracks DROP COLUMN IF EXISTS disc_number;
+
+ALTER TABLE albums DROP COLUMN IF EXISTS unpublish_reason;
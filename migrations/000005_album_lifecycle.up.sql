@@ -0,0 +1,12 @@
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS unpublish_reason VARCHAR(1024);
+
+ALTER TABLE tracks ADD COLUMN IF NOT EXISTS disc_number INTEGER NOT NULL DEFAULT 1 CHECK (disc_number > 0);
+ALTER TABLE tracks ADD COLUMN IF NOT EXISTS track_number INTEGER NOT NULL DEFAULT 0;
+
+UPDATE tracks t
+SET track_number = numbered.num
+FROM (SELECT id, row_number() OVER (PARTITION BY album_id ORDER BY name, id) AS num FROM tracks) numbered
+WHERE t.id = numbered.id;
+
+ALTER TABLE tracks ADD CONSTRAINT tracks_album_position_key
+    UNIQUE (album_id, disc_number, track_number) DEFERRABLE INITIALLY DEFERRED;
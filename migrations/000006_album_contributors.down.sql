@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS track_credits;
+
+DROP INDEX IF EXISTS album_musician_owner_idx;
+
+ALTER TABLE album_musician DROP COLUMN IF EXISTS invited_at;
+ALTER TABLE album_musician DROP COLUMN IF EXISTS accepted;
+ALTER TABLE album_musician DROP COLUMN IF EXISTS is_owner;
@@ -0,0 +1,14 @@
+ALTER TABLE album_musician ADD COLUMN IF NOT EXISTS is_owner BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE album_musician ADD COLUMN IF NOT EXISTS accepted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE album_musician ADD COLUMN IF NOT EXISTS invited_at TIMESTAMP NOT NULL DEFAULT now();
+
+UPDATE album_musician SET is_owner = TRUE, accepted = TRUE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS album_musician_owner_idx ON album_musician(album_id) WHERE is_owner;
+
+CREATE TABLE IF NOT EXISTS track_credits (
+    track_id UUID REFERENCES tracks ON DELETE CASCADE,
+    musician_id UUID REFERENCES musicians ON DELETE CASCADE,
+    role VARCHAR(32) NOT NULL CHECK (role IN ('primary', 'featured', 'producer', 'composer')),
+    PRIMARY KEY (track_id, musician_id, role)
+);
@@ -0,0 +1,3 @@
+-- Staff accounts are regular users with an elevated role. The first admin is
+-- promoted by hand: UPDATE users SET role = 3 WHERE name = '...';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role INTEGER NOT NULL DEFAULT 0 CHECK (role IN (0, 2, 3));
@@ -0,0 +1,57 @@
+DROP VIEW IF EXISTS musicians;
+DROP VIEW IF EXISTS users;
+
+ALTER TABLE user_profiles
+    DROP CONSTRAINT IF EXISTS user_profiles_account_fk,
+    ADD COLUMN name VARCHAR(255),
+    ADD COLUMN email VARCHAR(255),
+    ADD COLUMN password VARCHAR(255),
+    ADD COLUMN salt VARCHAR(255);
+
+UPDATE user_profiles p
+SET name = COALESCE(n.original_value, a.name),
+    email = COALESCE(e.original_value, a.email, ''),
+    password = a.password,
+    salt = a.salt
+FROM accounts a
+LEFT JOIN account_merge_conflicts n ON n.account_id = a.id AND n.field = 'name'
+LEFT JOIN account_merge_conflicts e ON e.account_id = a.id AND e.field = 'email'
+WHERE a.id = p.id;
+
+ALTER TABLE user_profiles
+    ALTER COLUMN name SET NOT NULL,
+    ALTER COLUMN email SET NOT NULL,
+    ALTER COLUMN password SET NOT NULL,
+    ALTER COLUMN salt SET NOT NULL,
+    ADD CONSTRAINT users_name_key UNIQUE (name),
+    ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE user_profiles RENAME TO users;
+
+ALTER TABLE musician_profiles
+    DROP CONSTRAINT IF EXISTS musician_profiles_account_fk,
+    ADD COLUMN name VARCHAR(255),
+    ADD COLUMN email VARCHAR(255),
+    ADD COLUMN password VARCHAR(255),
+    ADD COLUMN salt VARCHAR(255);
+
+UPDATE musician_profiles p
+SET name = COALESCE(n.original_value, a.name),
+    email = COALESCE(e.original_value, a.email, ''),
+    password = a.password,
+    salt = a.salt
+FROM accounts a
+LEFT JOIN account_merge_conflicts n ON n.account_id = a.id AND n.field = 'name'
+LEFT JOIN account_merge_conflicts e ON e.account_id = a.id AND e.field = 'email'
+WHERE a.id = p.id;
+
+ALTER TABLE musician_profiles
+    ALTER COLUMN name SET NOT NULL,
+    ALTER COLUMN email SET NOT NULL,
+    ALTER COLUMN password SET NOT NULL,
+    ALTER COLUMN salt SET NOT NULL,
+    ADD CONSTRAINT musicians_name_key UNIQUE (name),
+    ADD CONSTRAINT musicians_email_key UNIQUE (email);
+ALTER TABLE musician_profiles RENAME TO musicians;
+
+DROP TABLE IF EXISTS account_merge_conflicts;
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,72 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('user', 'musician')),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255),
+    password VARCHAR(255) NOT NULL,
+    salt VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_email_idx ON accounts (lower(email));
+
+-- Users and musicians used to be separate namespaces, so the same name or
+-- email may belong to both. Rows are never merged into one account, since
+-- nothing proves they belong to the same person: the user keeps the value
+-- (login used to try users first), the musician gets a suffixed name or no
+-- login email, and the original value is kept here for support.
+CREATE TABLE IF NOT EXISTS account_merge_conflicts (
+    account_id UUID REFERENCES accounts ON DELETE CASCADE,
+    field VARCHAR(16) NOT NULL CHECK (field IN ('name', 'email')),
+    original_value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (account_id, field)
+);
+
+CREATE TEMPORARY TABLE account_merge AS
+SELECT id, kind, name, email, password, salt,
+       row_number() OVER (PARTITION BY name ORDER BY priority, id) AS name_rank,
+       row_number() OVER (PARTITION BY lower(email) ORDER BY priority, id) AS email_rank
+FROM (SELECT id, 'user' AS kind, name, email, password, salt, 0 AS priority FROM users
+      UNION ALL
+      SELECT id, 'musician' AS kind, name, email, password, salt, 1 AS priority FROM musicians) AS merged;
+
+INSERT INTO accounts (id, kind, name, email, password, salt)
+SELECT id,
+       kind,
+       CASE WHEN name_rank = 1 THEN name ELSE left(name, 246) || '-' || left(id::text, 8) END,
+       CASE WHEN email_rank = 1 THEN email END,
+       password,
+       salt
+FROM account_merge;
+
+INSERT INTO account_merge_conflicts (account_id, field, original_value)
+SELECT id, 'name', name FROM account_merge WHERE name_rank > 1
+UNION ALL
+SELECT id, 'email', email FROM account_merge WHERE email_rank > 1;
+
+DROP TABLE account_merge;
+
+ALTER TABLE users RENAME TO user_profiles;
+ALTER TABLE user_profiles
+    DROP COLUMN name,
+    DROP COLUMN email,
+    DROP COLUMN password,
+    DROP COLUMN salt,
+    ADD CONSTRAINT user_profiles_account_fk FOREIGN KEY (id) REFERENCES accounts ON DELETE CASCADE;
+
+ALTER TABLE musicians RENAME TO musician_profiles;
+ALTER TABLE musician_profiles
+    DROP COLUMN name,
+    DROP COLUMN email,
+    DROP COLUMN password,
+    DROP COLUMN salt,
+    ADD CONSTRAINT musician_profiles_account_fk FOREIGN KEY (id) REFERENCES accounts ON DELETE CASCADE;
+
+-- Read-only views with the old table shape, used by every query that reads
+-- profiles. Writes go to accounts and the profile tables directly.
+CREATE VIEW users AS
+SELECT p.id, a.name, COALESCE(a.email, '') AS email, p.phone, a.password, a.salt, p.country, p.role
+FROM user_profiles p JOIN accounts a ON a.id = p.id;
+
+CREATE VIEW musicians AS
+SELECT p.id, a.name, COALESCE(a.email, '') AS email, a.password, a.salt, p.country, p.description, p.image_url
+FROM musician_profiles p JOIN accounts a ON a.id = p.id;
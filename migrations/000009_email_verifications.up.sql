@@ -0,0 +1,8 @@
+-- An email counts as verified only while it matches the verified address, so
+-- changing the account email drops the verification without extra writes.
+CREATE TABLE IF NOT EXISTS account_email_verifications
+(
+    account_id  UUID PRIMARY KEY REFERENCES accounts (id) ON DELETE CASCADE,
+    email       TEXT        NOT NULL,
+    verified_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
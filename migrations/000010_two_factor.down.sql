@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS account_recovery_codes;
+DROP TABLE IF EXISTS account_two_factor;
@@ -0,0 +1,17 @@
+-- A row without enabled_at is an enrollment that was never confirmed.
+CREATE TABLE IF NOT EXISTS account_two_factor
+(
+    account_id     UUID PRIMARY KEY REFERENCES accounts (id) ON DELETE CASCADE,
+    secret         TEXT        NOT NULL,
+    enabled_at     TIMESTAMPTZ,
+    last_used_step BIGINT      NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS account_recovery_codes
+(
+    account_id UUID REFERENCES account_two_factor (account_id) ON DELETE CASCADE,
+    code_hash  TEXT NOT NULL,
+    used_at    TIMESTAMPTZ,
+    PRIMARY KEY (account_id, code_hash)
+);
@@ -0,0 +1,12 @@
+-- An account has at most one identity per provider, an identity belongs to
+-- exactly one account.
+CREATE TABLE IF NOT EXISTS account_identities
+(
+    provider   TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    account_id UUID        NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    email      TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, subject),
+    UNIQUE (account_id, provider)
+);
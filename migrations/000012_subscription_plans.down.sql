@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS subscriptions_current_idx;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS plan_id;
+
+DROP TABLE IF EXISTS subscription_plans;
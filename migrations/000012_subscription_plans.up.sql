@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS subscription_plans
+(
+    id            UUID PRIMARY KEY,
+    name          VARCHAR(64) NOT NULL UNIQUE,
+    price         BIGINT      NOT NULL CHECK (price >= 0),
+    duration_days INT         NOT NULL CHECK (duration_days > 0)
+);
+
+INSERT INTO subscription_plans (id, name, price, duration_days)
+VALUES ('6f1c2f0e-8d0a-4a53-9a4b-0c6d8f2d7e11', 'monthly', 29900, 30);
+INSERT INTO subscription_plans (id, name, price, duration_days)
+VALUES ('b4a7e2d9-3c5f-4e81-a6d2-5f9e0b1c3a72', 'yearly', 299000, 365);
+
+-- Subscriptions created before plans existed keep a NULL plan and can't be
+-- renewed, only replaced by a new one once they expire.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS plan_id UUID REFERENCES subscription_plans,
+    ADD COLUMN IF NOT EXISTS status  VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'canceled', 'expired'));
+
+UPDATE subscriptions
+SET status = 'expired'
+WHERE expiration_date <= now();
+
+-- Older data may hold overlapping subscriptions, keep the newest one of
+-- each user and expire the rest so the index below can be built.
+UPDATE subscriptions s
+SET status = 'expired'
+WHERE s.status <> 'expired'
+  AND EXISTS (SELECT 1
+              FROM subscriptions newer
+              WHERE newer.user_id = s.user_id
+                AND newer.status <> 'expired'
+                AND (newer.start_date, newer.id) > (s.start_date, s.id));
+
+-- A user has at most one subscription that is not expired.
+CREATE UNIQUE INDEX IF NOT EXISTS subscriptions_current_idx ON subscriptions (user_id) WHERE status <> 'expired';
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS orders
+(
+    id              UUID PRIMARY KEY,
+    user_id         UUID         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    plan_id         UUID         NOT NULL REFERENCES subscription_plans,
+    amount          BIGINT       NOT NULL CHECK (amount >= 0),
+    status          VARCHAR(16)  NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'paid', 'failed', 'refunded')),
+    idempotency_key VARCHAR(255) NOT NULL,
+    -- Set once the payment gateway created the intent for the order.
+    intent_id       VARCHAR(255) UNIQUE,
+    checkout_url    TEXT,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    UNIQUE (user_id, idempotency_key)
+);
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS royalty_entries;
+DROP TABLE IF EXISTS royalty_periods;
+DROP FUNCTION IF EXISTS royalty_ledger_immutable();
+
+DROP INDEX IF EXISTS orders_paid_at_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS paid_at;
+
+ALTER TABLE tracks
+    DROP COLUMN IF EXISTS duration_seconds;
+
+DROP INDEX IF EXISTS users_history_listened_at_idx;
+
+ALTER TABLE users_history
+    DROP COLUMN IF EXISTS listened_seconds,
+    DROP COLUMN IF EXISTS listened_at;
@@ -0,0 +1,65 @@
+-- Plays recorded before durations were tracked never qualify for royalties.
+ALTER TABLE users_history
+    ADD COLUMN IF NOT EXISTS listened_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS listened_seconds INT         NOT NULL DEFAULT 0 CHECK (listened_seconds >= 0);
+
+CREATE INDEX IF NOT EXISTS users_history_listened_at_idx ON users_history (listened_at);
+
+-- Reported plays are cut down to the track length. Tracks uploaded before it
+-- was recorded have none, so their plays don't qualify until it is set.
+ALTER TABLE tracks
+    ADD COLUMN IF NOT EXISTS duration_seconds INT NOT NULL DEFAULT 0 CHECK (duration_seconds >= 0);
+
+-- Revenue belongs to the period the order was paid in, not created in.
+-- Older paid orders have no payment time, their creation is the best guess.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS paid_at TIMESTAMPTZ;
+
+UPDATE orders
+SET paid_at = created_at
+WHERE status IN ('paid', 'refunded');
+
+CREATE INDEX IF NOT EXISTS orders_paid_at_idx ON orders (paid_at) WHERE status = 'paid';
+
+-- One row per allocated calendar month (UTC). revenue is what paid orders
+-- brought in, pool the musicians' share of it.
+CREATE TABLE IF NOT EXISTS royalty_periods
+(
+    period_start TIMESTAMPTZ PRIMARY KEY,
+    period_end   TIMESTAMPTZ NOT NULL,
+    revenue      BIGINT      NOT NULL CHECK (revenue >= 0),
+    pool         BIGINT      NOT NULL CHECK (pool >= 0),
+    plays        BIGINT      NOT NULL CHECK (plays >= 0),
+    allocated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS royalty_entries
+(
+    id           UUID PRIMARY KEY,
+    musician_id  UUID        NOT NULL REFERENCES musician_profiles,
+    period_start TIMESTAMPTZ NOT NULL REFERENCES royalty_periods,
+    plays        BIGINT      NOT NULL CHECK (plays >= 0),
+    amount       BIGINT      NOT NULL CHECK (amount >= 0),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (musician_id, period_start)
+);
+
+-- The ledger is append-only.
+CREATE OR REPLACE FUNCTION royalty_ledger_immutable() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'royalty ledger rows are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER royalty_periods_immutable
+    BEFORE UPDATE OR DELETE
+    ON royalty_periods
+    FOR EACH ROW
+EXECUTE FUNCTION royalty_ledger_immutable();
+
+CREATE TRIGGER royalty_entries_immutable
+    BEFORE UPDATE OR DELETE
+    ON royalty_entries
+    FOR EACH ROW
+EXECUTE FUNCTION royalty_ledger_immutable();
@@ -0,0 +1,13 @@
+DELETE
+FROM comments
+WHERE parent_id IS NOT NULL;
+
+DROP INDEX IF EXISTS comments_track_created_idx;
+DROP INDEX IF EXISTS comments_parent_idx;
+DROP INDEX IF EXISTS comments_review_idx;
+
+ALTER TABLE comments
+    DROP CONSTRAINT IF EXISTS comments_reply_stars,
+    DROP COLUMN IF EXISTS edited_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,22 @@
+-- Posting used to delete and re-insert the review, so duplicates are rare;
+-- keep one review per user and track before making that a constraint.
+DELETE
+FROM comments c
+    USING comments d
+WHERE c.user_id = d.user_id
+  AND c.track_id = d.track_id
+  AND c.id < d.id;
+
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS parent_id  UUID REFERENCES comments ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS edited_at  TIMESTAMPTZ;
+
+-- Replies are not rated.
+ALTER TABLE comments
+    ADD CONSTRAINT comments_reply_stars CHECK (parent_id IS NULL OR stars IS NULL);
+
+-- A user has one review per track, replies are not limited.
+CREATE UNIQUE INDEX IF NOT EXISTS comments_review_idx ON comments (user_id, track_id) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS comments_parent_idx ON comments (parent_id);
+CREATE INDEX IF NOT EXISTS comments_track_created_idx ON comments (track_id, created_at);
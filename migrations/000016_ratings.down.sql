@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS album_ratings;
+DROP TRIGGER IF EXISTS comments_track_ratings ON comments;
+DROP FUNCTION IF EXISTS track_ratings_refresh();
+DROP FUNCTION IF EXISTS track_rating_add(UUID, INT, INT);
+DROP TABLE IF EXISTS track_ratings;
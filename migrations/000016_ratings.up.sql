@@ -0,0 +1,79 @@
+-- Star counts of the reviews on each track, kept up to date by the trigger
+-- on comments. Replies have no stars and are not counted.
+CREATE TABLE IF NOT EXISTS track_ratings
+(
+    track_id UUID PRIMARY KEY REFERENCES tracks ON DELETE CASCADE,
+    reviews  INT NOT NULL DEFAULT 0 CHECK (reviews >= 0),
+    stars_1  INT NOT NULL DEFAULT 0 CHECK (stars_1 >= 0),
+    stars_2  INT NOT NULL DEFAULT 0 CHECK (stars_2 >= 0),
+    stars_3  INT NOT NULL DEFAULT 0 CHECK (stars_3 >= 0),
+    stars_4  INT NOT NULL DEFAULT 0 CHECK (stars_4 >= 0),
+    stars_5  INT NOT NULL DEFAULT 0 CHECK (stars_5 >= 0)
+);
+
+INSERT INTO track_ratings (track_id, reviews, stars_1, stars_2, stars_3, stars_4, stars_5)
+SELECT track_id,
+       COUNT(*),
+       COUNT(*) FILTER (WHERE stars = 1),
+       COUNT(*) FILTER (WHERE stars = 2),
+       COUNT(*) FILTER (WHERE stars = 3),
+       COUNT(*) FILTER (WHERE stars = 4),
+       COUNT(*) FILTER (WHERE stars = 5)
+FROM comments
+WHERE stars IS NOT NULL
+  AND track_id IS NOT NULL
+GROUP BY track_id
+ON CONFLICT (track_id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION track_rating_add(p_track_id UUID, p_stars INT, p_delta INT) RETURNS void AS
+$$
+BEGIN
+    -- Only new reviews create the row: a review removed because its track is
+    -- being deleted must not recreate the row of that track.
+    IF p_delta > 0 THEN
+        INSERT INTO track_ratings (track_id) VALUES (p_track_id) ON CONFLICT (track_id) DO NOTHING;
+    END IF;
+
+    UPDATE track_ratings
+    SET reviews = reviews + p_delta,
+        stars_1 = stars_1 + CASE WHEN p_stars = 1 THEN p_delta ELSE 0 END,
+        stars_2 = stars_2 + CASE WHEN p_stars = 2 THEN p_delta ELSE 0 END,
+        stars_3 = stars_3 + CASE WHEN p_stars = 3 THEN p_delta ELSE 0 END,
+        stars_4 = stars_4 + CASE WHEN p_stars = 4 THEN p_delta ELSE 0 END,
+        stars_5 = stars_5 + CASE WHEN p_stars = 5 THEN p_delta ELSE 0 END
+    WHERE track_id = p_track_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION track_ratings_refresh() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.stars IS NOT NULL THEN
+        PERFORM track_rating_add(OLD.track_id, OLD.stars, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.stars IS NOT NULL THEN
+        PERFORM track_rating_add(NEW.track_id, NEW.stars, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comments_track_ratings
+    AFTER INSERT OR DELETE OR UPDATE OF stars, track_id
+    ON comments
+    FOR EACH ROW
+EXECUTE FUNCTION track_ratings_refresh();
+
+CREATE OR REPLACE VIEW album_ratings AS
+SELECT t.album_id,
+       SUM(r.reviews)::INT AS reviews,
+       SUM(r.stars_1)::INT AS stars_1,
+       SUM(r.stars_2)::INT AS stars_2,
+       SUM(r.stars_3)::INT AS stars_3,
+       SUM(r.stars_4)::INT AS stars_4,
+       SUM(r.stars_5)::INT AS stars_5
+FROM track_ratings r
+         JOIN tracks t ON t.id = r.track_id
+GROUP BY t.album_id;
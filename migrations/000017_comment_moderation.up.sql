@@ -0,0 +1,23 @@
+-- Hidden comments stay in place for their author but are left out of the
+-- track listing. hidden_by tells whether a moderator or the musician of the
+-- track hid it: musicians can not restore what a moderator hid.
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS hidden_by VARCHAR(16) CHECK (hidden_by IN ('moderator', 'musician'));
+
+ALTER TABLE comments
+    ADD CONSTRAINT comments_hidden_by CHECK ((hidden_at IS NULL) = (hidden_by IS NULL));
+
+CREATE TABLE IF NOT EXISTS comment_reports
+(
+    id          UUID PRIMARY KEY,
+    comment_id  UUID         NOT NULL REFERENCES comments ON DELETE CASCADE,
+    reporter_id UUID         NOT NULL REFERENCES user_profiles ON DELETE CASCADE,
+    reason      VARCHAR(512) NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE (comment_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS comment_reports_open_idx ON comment_reports (comment_id) WHERE resolved_at IS NULL;
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS comment_votes_counts ON comment_votes;
+DROP FUNCTION IF EXISTS comment_votes_refresh();
+DROP TABLE IF EXISTS comment_votes;
+
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS unhelpful_votes,
+    DROP COLUMN IF EXISTS helpful_votes;
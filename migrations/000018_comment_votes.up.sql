@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS comment_votes
+(
+    comment_id UUID        NOT NULL REFERENCES comments ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES user_profiles ON DELETE CASCADE,
+    helpful    BOOLEAN     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- Vote counts live on the comment so the track listing can sort by them.
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS helpful_votes   INT NOT NULL DEFAULT 0 CHECK (helpful_votes >= 0),
+    ADD COLUMN IF NOT EXISTS unhelpful_votes INT NOT NULL DEFAULT 0 CHECK (unhelpful_votes >= 0);
+
+CREATE OR REPLACE FUNCTION comment_votes_refresh() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE comments
+        SET helpful_votes   = helpful_votes - CASE WHEN OLD.helpful THEN 1 ELSE 0 END,
+            unhelpful_votes = unhelpful_votes - CASE WHEN OLD.helpful THEN 0 ELSE 1 END
+        WHERE id = OLD.comment_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE comments
+        SET helpful_votes   = helpful_votes + CASE WHEN NEW.helpful THEN 1 ELSE 0 END,
+            unhelpful_votes = unhelpful_votes + CASE WHEN NEW.helpful THEN 0 ELSE 1 END
+        WHERE id = NEW.comment_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comment_votes_counts
+    AFTER INSERT OR DELETE OR UPDATE OF helpful
+    ON comment_votes
+    FOR EACH ROW
+EXECUTE FUNCTION comment_votes_refresh();
@@ -0,0 +1,11 @@
+DROP VIEW IF EXISTS users;
+
+CREATE VIEW users AS
+SELECT p.id, a.name, COALESCE(a.email, '') AS email, p.phone, a.password, a.salt, p.country, p.role
+FROM user_profiles p JOIN accounts a ON a.id = p.id;
+
+DROP INDEX IF EXISTS user_profiles_deletion_idx;
+
+ALTER TABLE user_profiles
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS deletion_scheduled_at;
@@ -0,0 +1,14 @@
+-- A deletion request only schedules the removal after a grace period. Removing
+-- the account then cascades to history, comments, favorites and
+-- everything else that references the profile.
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS avatar_url            VARCHAR(1024),
+    ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS user_profiles_deletion_idx ON user_profiles (deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
+
+CREATE OR REPLACE VIEW users AS
+SELECT p.id, a.name, COALESCE(a.email, '') AS email, p.phone, a.password, a.salt, p.country, p.role,
+       p.avatar_url, p.deletion_scheduled_at
+FROM user_profiles p JOIN accounts a ON a.id = p.id;
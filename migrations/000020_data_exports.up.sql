@@ -0,0 +1,18 @@
+-- Exports are built by a background job. A running export whose worker died
+-- is picked up again once it's stale, and a user has at most one unfinished
+-- export at a time.
+CREATE TABLE IF NOT EXISTS data_exports
+(
+    id           UUID PRIMARY KEY,
+    user_id      UUID        NOT NULL REFERENCES user_profiles ON DELETE CASCADE,
+    status       VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'ready', 'failed')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at   TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS data_exports_active_idx ON data_exports (user_id)
+    WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS data_exports_queue_idx ON data_exports (created_at)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,10 @@
+DROP VIEW IF EXISTS musicians;
+
+CREATE VIEW musicians AS
+SELECT p.id, a.name, COALESCE(a.email, '') AS email, a.password, a.salt, p.country, p.description, p.image_url
+FROM musician_profiles p JOIN accounts a ON a.id = p.id;
+
+DROP TABLE IF EXISTS musician_links;
+
+ALTER TABLE musician_profiles
+    DROP COLUMN IF EXISTS verified_at;
@@ -0,0 +1,19 @@
+-- Verification is granted by an admin and vouches that the profile belongs to
+-- the artist it's named after.
+ALTER TABLE musician_profiles
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+
+-- A musician has at most one link of each kind.
+CREATE TABLE IF NOT EXISTS musician_links
+(
+    musician_id UUID          NOT NULL REFERENCES musician_profiles ON DELETE CASCADE,
+    kind        VARCHAR(16)   NOT NULL CHECK (kind IN ('website', 'instagram', 'twitter', 'facebook', 'youtube',
+                                                       'soundcloud', 'bandcamp', 'spotify', 'vk', 'telegram')),
+    url         VARCHAR(1024) NOT NULL,
+    PRIMARY KEY (musician_id, kind)
+);
+
+CREATE OR REPLACE VIEW musicians AS
+SELECT p.id, a.name, COALESCE(a.email, '') AS email, a.password, a.salt, p.country, p.description, p.image_url,
+       p.verified_at
+FROM musician_profiles p JOIN accounts a ON a.id = p.id;